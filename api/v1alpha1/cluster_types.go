@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+// ColumnTags is the table column that shows the tags of the clusters
+const ColumnTags = "tags"
+
+// DiscoveredCluster represents a cluster found by a discovery provider. Automation can
+// depend on these fields, they will not be removed or renamed in this version.
+type DiscoveredCluster struct {
+	// ID is the unique identifier for the cluster with the provider, e.g. the ARN of an EKS cluster
+	ID string `json:"id"`
+	// Name is the name of the cluster
+	Name string `json:"name"`
+	// Endpoint is the URL of the API server of the cluster
+	Endpoint string `json:"endpoint,omitempty"`
+	// CertificateAuthorityData is the base64 encoded certificate authority of the API server
+	CertificateAuthorityData string `json:"certificateAuthorityData,omitempty"`
+	// Account is the AWS account ID or Azure subscription ID the cluster belongs to
+	Account string `json:"account,omitempty"`
+	// Region is the region or location of the cluster
+	Region string `json:"region,omitempty"`
+	// Tags are the tags of the cluster in the provider
+	Tags map[string]string `json:"tags,omitempty"`
+	// Annotations are the additional details about the cluster added by the provider and enrichers
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+// +kubebuilder:object:root=true
+// +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
+
+// DiscoveredClusters represents the clusters found by a discovery provider
+type DiscoveredClusters struct {
+	metav1.TypeMeta `json:",inline"`
+
+	// Provider is the name of the discovery provider
+	Provider string `json:"provider"`
+	// Clusters is the discovered clusters sorted by name
+	Clusters []DiscoveredCluster `json:"clusters"`
+}
+
+// NewDiscoveredClusters will create a new empty list of clusters discovered by the provider
+func NewDiscoveredClusters(provider string) *DiscoveredClusters {
+	return &DiscoveredClusters{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: SchemeGroupVersion.String(),
+			Kind:       "DiscoveredClusters",
+		},
+		Provider: provider,
+		Clusters: []DiscoveredCluster{},
+	}
+}
+
+// ToTable returns the clusters as a table. The columns are extra columns to add to the
+// table, each is the key of an annotation or ColumnTags.
+func (d *DiscoveredClusters) ToTable(columns ...string) *metav1.Table {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: metav1.SchemeGroupVersion.String(),
+			Kind:       "Table",
+		},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{
+				Name: "Name",
+				Type: "string",
+			},
+			{
+				Name: "Account",
+				Type: "string",
+			},
+			{
+				Name: "Region",
+				Type: "string",
+			},
+			{
+				Name: "Id",
+				Type: "string",
+			},
+		},
+	}
+
+	for _, column := range columns {
+		table.ColumnDefinitions = append(table.ColumnDefinitions, metav1.TableColumnDefinition{
+			Name:        columnName(column),
+			Type:        "string",
+			Description: column,
+		})
+	}
+
+	for _, cluster := range d.Clusters {
+		cells := []interface{}{cluster.Name, cluster.Account, cluster.Region, cluster.ID}
+		for _, column := range columns {
+			cells = append(cells, cluster.columnValue(column))
+		}
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: cells,
+		})
+	}
+
+	return table
+}
+
+func (c *DiscoveredCluster) columnValue(column string) string {
+	if column != ColumnTags {
+		return c.Annotations[column]
+	}
+
+	tags := make([]string, 0, len(c.Tags))
+	for key, value := range c.Tags {
+		tags = append(tags, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(tags)
+
+	return strings.Join(tags, ",")
+}
+
+// columnName returns the name of the column for an annotation, which is the annotation
+// key without its prefix, e.g. vpc-id for eks.amazonaws.com/vpc-id
+func columnName(column string) string {
+	name := column[strings.LastIndex(column, "/")+1:]
+
+	return strings.Title(strings.ReplaceAll(name, "-", " ")) //nolint: staticcheck
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1_test
+
+import (
+	"bytes"
+	"encoding/json"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/printer"
+)
+
+func TestDiscoveredClustersJSON(t *testing.T) {
+	g := NewWithT(t)
+
+	discovered := v1alpha1.NewDiscoveredClusters("eks")
+	discovered.Clusters = append(discovered.Clusters, v1alpha1.DiscoveredCluster{
+		ID:                       "arn:aws:eks:eu-west-2:123456789012:cluster/dev",
+		Name:                     "dev",
+		Endpoint:                 "https://dev.eks.amazonaws.com",
+		CertificateAuthorityData: "Y2E=",
+		Account:                  "123456789012",
+		Region:                   "eu-west-2",
+		Tags:                     map[string]string{"team": "platform"},
+	})
+
+	jsonPrinter, err := printer.New(printer.OutputPrinterJSON)
+	g.Expect(err).NotTo(HaveOccurred())
+	buf := &bytes.Buffer{}
+	g.Expect(jsonPrinter.Print(discovered, buf)).To(Succeed())
+
+	// The field names are part of the documented schema
+	output := map[string]interface{}{}
+	g.Expect(json.Unmarshal(buf.Bytes(), &output)).To(Succeed())
+	g.Expect(output).To(HaveKeyWithValue("apiVersion", "kconnect.fidelity.github.com/v1alpha1"))
+	g.Expect(output).To(HaveKeyWithValue("kind", "DiscoveredClusters"))
+	g.Expect(output).To(HaveKeyWithValue("provider", "eks"))
+	g.Expect(output["clusters"]).To(ConsistOf(map[string]interface{}{
+		"id":                       "arn:aws:eks:eu-west-2:123456789012:cluster/dev",
+		"name":                     "dev",
+		"endpoint":                 "https://dev.eks.amazonaws.com",
+		"certificateAuthorityData": "Y2E=",
+		"account":                  "123456789012",
+		"region":                   "eu-west-2",
+		"tags":                     map[string]interface{}{"team": "platform"},
+	}))
+
+	table := discovered.ToTable()
+	g.Expect(table.Rows).To(HaveLen(1))
+	g.Expect(table.Rows[0].Cells).To(Equal([]interface{}{"dev", "123456789012", "eu-west-2", "arn:aws:eks:eu-west-2:123456789012:cluster/dev"}))
+}
+
+func TestDiscoveredClustersTableColumns(t *testing.T) {
+	g := NewWithT(t)
+
+	discovered := v1alpha1.NewDiscoveredClusters("eks")
+	discovered.Clusters = append(discovered.Clusters, v1alpha1.DiscoveredCluster{
+		ID:          "arn:aws:eks:eu-west-2:123456789012:cluster/dev",
+		Name:        "dev",
+		Account:     "123456789012",
+		Region:      "eu-west-2",
+		Tags:        map[string]string{"team": "platform", "env": "dev"},
+		Annotations: map[string]string{"eks.amazonaws.com/vpc-id": "vpc-0123"},
+	})
+
+	table := discovered.ToTable("eks.amazonaws.com/vpc-id", v1alpha1.ColumnTags)
+	g.Expect(table.ColumnDefinitions).To(HaveLen(6))
+	g.Expect(table.ColumnDefinitions[4].Name).To(Equal("Vpc Id"))
+	g.Expect(table.ColumnDefinitions[5].Name).To(Equal("Tags"))
+	g.Expect(table.Rows[0].Cells).To(Equal([]interface{}{"dev", "123456789012", "eu-west-2", "arn:aws:eks:eu-west-2:123456789012:cluster/dev", "vpc-0123", "env=dev,team=platform"}))
+}
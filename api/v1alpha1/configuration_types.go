@@ -34,6 +34,21 @@ type ConfigurationSpec struct {
 	// Lists contains predefined name lists of name/value pairs that can be
 	// used to offer a selection to a user for a configuration item
 	Lists map[string][]ListItem `json:"lists,omitempty"`
+	// Variables holds user defined variables that can be referenced in
+	// configuration values using ${name}
+	Variables map[string]string `json:"variables,omitempty"`
+	// Plugins holds the policy for which plugins can be used
+	Plugins *PluginPolicy `json:"plugins,omitempty"`
+	// UsageMetrics holds where anonymized usage metrics are reported
+	UsageMetrics *UsageMetrics `json:"usageMetrics,omitempty"`
+	// SecretStore holds where sensitive values are persisted
+	SecretStore *SecretStore `json:"secretStore,omitempty"`
+	// ClusterPolicies holds the clusters that can be connected to per discovery
+	// provider name
+	ClusterPolicies map[string]ClusterPolicy `json:"clusterPolicies,omitempty"`
+	// Notices holds messages from the platform team that are shown once when
+	// a command starts
+	Notices []Notice `json:"notices,omitempty"`
 	// ImportedFrom holds where this configuration was originally imported from
 	ImportedFrom *string `json:"importedFrom,omitempty"`
 	// VersionCheck holds details of the last version cehck
@@ -65,6 +80,94 @@ type ListItem struct {
 	Value string `json:"value"`
 }
 
+// PluginPolicy controls which of the discovery and identity plugins can be used
+type PluginPolicy struct {
+	// Disabled is the names of the discovery or identity plugins that can't
+	// be used. Disabled plugins are hidden from the help.
+	Disabled []string `json:"disabled,omitempty"`
+}
+
+// UsageMetrics configures the reporting of anonymized usage metrics. No
+// metrics are reported unless an endpoint is set.
+type UsageMetrics struct {
+	// Endpoint is the URL the usage metrics are posted to
+	Endpoint string `json:"endpoint,omitempty"`
+}
+
+// ClusterPolicy restricts the discovered clusters that can be connected to. If there
+// are allow patterns a cluster must match one of them and a cluster that matches any
+// of the deny patterns is denied. Denied clusters aren't shown unless the policy is
+// overridden, which is recorded in the audit log.
+type ClusterPolicy struct {
+	// Allow holds the patterns of the clusters that are allowed
+	Allow []ClusterPattern `json:"allow,omitempty"`
+	// Deny holds the patterns of the clusters that are denied
+	Deny []ClusterPattern `json:"deny,omitempty"`
+}
+
+// ClusterPattern matches clusters by name and tags. The name and the tag values support
+// wildcards (*) and a cluster must match all of the fields that are set.
+type ClusterPattern struct {
+	// Name is the pattern for the cluster name
+	Name string `json:"name,omitempty"`
+	// Tags are the patterns for the values of the cluster tags
+	Tags map[string]string `json:"tags,omitempty"`
+}
+
+// Notice is a message, such as an upcoming change or a deprecation, that is shown
+// once to each user
+type Notice struct {
+	// ID identifies the notice, a notice is only shown once for each ID
+	ID string `json:"id"`
+	// Message is the text of the notice
+	Message string `json:"message"`
+	// Level is info (the default) or warning
+	Level string `json:"level,omitempty"`
+	// Expires is when the notice stops being shown
+	Expires *metav1.Time `json:"expires,omitempty"`
+}
+
+// SecretStore configures where kconnect persists sensitive values, such as the tokens
+// it creates and the identities saved to resume a use command. Configuration values
+// can also reference secrets in the store using ${secret:name}.
+type SecretStore struct {
+	// Type is the backend for the secrets: file (the default), keychain, vault or
+	// aws-secrets-manager
+	Type string `json:"type,omitempty"`
+	// Vault configures the HashiCorp Vault backend
+	Vault *VaultSecretStore `json:"vault,omitempty"`
+	// AWSSecretsManager configures the AWS Secrets Manager backend
+	AWSSecretsManager *AWSSecretsManagerSecretStore `json:"awsSecretsManager,omitempty"`
+}
+
+// VaultSecretStore configures the HashiCorp Vault secret store. The secrets are kept
+// in a KV version 2 secrets engine and the token is read from VAULT_TOKEN or the
+// token helper file (~/.vault-token).
+type VaultSecretStore struct {
+	// Address is the URL of the Vault server, it defaults to VAULT_ADDR
+	Address string `json:"address,omitempty"`
+	// Namespace is the Vault Enterprise namespace, it defaults to VAULT_NAMESPACE
+	Namespace string `json:"namespace,omitempty"`
+	// Mount is the path the KV secrets engine is mounted at, it defaults to secret
+	Mount string `json:"mount,omitempty"`
+	// Path is the path in the secrets engine the secrets are kept under, it defaults
+	// to kconnect
+	Path string `json:"path,omitempty"`
+}
+
+// AWSSecretsManagerSecretStore configures the AWS Secrets Manager secret store
+type AWSSecretsManagerSecretStore struct {
+	// Region is the AWS region of the secrets, it defaults to the region of the profile
+	Region string `json:"region,omitempty"`
+	// Profile is the AWS profile used to access the secrets
+	Profile string `json:"profile,omitempty"`
+	// Prefix is prefixed to the names of the secrets, it defaults to kconnect/
+	Prefix string `json:"prefix,omitempty"`
+	// KMSKeyID is the KMS key used to encrypt new secrets, the account's default key
+	// for Secrets Manager is used if it isn't set
+	KMSKeyID string `json:"kmsKeyID,omitempty"`
+}
+
 // +kubebuilder:object:root=true
 // +k8s:deepcopy-gen:interfaces=k8s.io/apimachinery/pkg/runtime.Object
 
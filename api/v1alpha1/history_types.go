@@ -17,6 +17,8 @@ limitations under the License.
 package v1alpha1
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
 	"errors"
 	"fmt"
@@ -45,6 +47,17 @@ type HistoryEntrySpec struct {
 	ConfigFile string `json:"configFile"`
 	// Alias is the given alternative user friendly name for the connection
 	Alias *string `json:"alias,omitempty"`
+	// Annotations are the additional details about the cluster added by enrichers
+	Annotations map[string]string `json:"annotations,omitempty"`
+	// Kubeconfig is the path of the kubeconfig that reconnecting to the entry always
+	// writes to, instead of the kubeconfig that was used when it was created
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// ContextName is the template for the name of the context when reconnecting to
+	// the entry, e.g. {{.Alias}}
+	ContextName string `json:"contextName,omitempty"`
+	// Group is the name of the alias group the entry belongs to, e.g. to export the
+	// kubeconfig files of all the entries in the group
+	Group string `json:"group,omitempty"`
 }
 
 type HistoryEntryStatus struct {
@@ -52,6 +65,14 @@ type HistoryEntryStatus struct {
 	LastModified metav1.Time `json:"lastModified"`
 	// LastUsed is the date/time that the entry was last updated
 	LastUsed metav1.Time `json:"lastUsed"`
+	// CredentialsExpiry is the date/time that the credentials from the last time the entry
+	// was used expire, if the identity provider knows it
+	CredentialsExpiry *metav1.Time `json:"credentialsExpiry,omitempty"`
+	// ClusterEndpoint is the API server endpoint of the cluster the last time the entry was used
+	ClusterEndpoint string `json:"clusterEndpoint,omitempty"`
+	// ClusterCAHash is the SHA256 hash of the certificate authority data of the cluster the
+	// last time the entry was used
+	ClusterCAHash string `json:"clusterCAHash,omitempty"`
 }
 
 // +kubebuilder:object:root=true
@@ -87,6 +108,10 @@ type HistoryReference struct {
 
 var ErrNoHistoryExtension = errors.New("no kconnext history extension found")
 
+// EnvironmentAnnotations are the annotations of an entry that can hold the environment
+// of the cluster, in order of precedence
+var EnvironmentAnnotations = []string{"environment", "env"}
+
 var ignoreFlags = map[string]struct{}{
 	"profile": {},
 }
@@ -155,6 +180,74 @@ func GetHistoryReferenceFromContext(context *api.Context) (*HistoryReference, er
 	return &kconnectExtensionObj, nil
 }
 
+// CredentialsExpired returns true if the credentials of the entry are known to have expired
+func (h *HistoryEntry) CredentialsExpired() bool {
+	return h.Status.CredentialsExpiry != nil && time.Now().After(h.Status.CredentialsExpiry.Time)
+}
+
+// CredentialsExpiryDescription describes when the credentials of the entry expire, e.g.
+// "expired 2h ago". It's empty if the expiry isn't known.
+func (h *HistoryEntry) CredentialsExpiryDescription() string {
+	if h.Status.CredentialsExpiry == nil {
+		return ""
+	}
+
+	return htime.DescribeExpiry(h.Status.CredentialsExpiry.Time, time.Now())
+}
+
+// CredentialsTimeLeft describes how long is left until the credentials of the entry
+// expire, e.g. "45m" or "expired". It's empty if the expiry isn't known.
+func (h *HistoryEntry) CredentialsTimeLeft() string {
+	if h.Status.CredentialsExpiry == nil {
+		return ""
+	}
+
+	return htime.DescribeRemaining(h.Status.CredentialsExpiry.Time, time.Now())
+}
+
+// EnvironmentLabel returns the environment of the cluster of the entry, e.g. prod. It's
+// the first of the EnvironmentAnnotations the entry has, e.g. added by an enricher, or
+// else the alias group of the entry.
+func (h *HistoryEntry) EnvironmentLabel() string {
+	for _, key := range EnvironmentAnnotations {
+		if value := h.Spec.Annotations[key]; value != "" {
+			return value
+		}
+	}
+
+	return h.Spec.Group
+}
+
+// SetClusterDetails records the endpoint and certificate authority data of the cluster
+func (h *HistoryEntry) SetClusterDetails(endpoint, caData string) {
+	h.Status.ClusterEndpoint = endpoint
+	h.Status.ClusterCAHash = hashCA(caData)
+}
+
+// ClusterDetailsChanged returns the details of the cluster that are different to the
+// ones recorded the last time the entry was used, e.g. because the cluster was rebuilt.
+// Details that weren't recorded aren't compared.
+func (h *HistoryEntry) ClusterDetailsChanged(endpoint, caData string) []string {
+	changed := []string{}
+	if h.Status.ClusterEndpoint != "" && h.Status.ClusterEndpoint != endpoint {
+		changed = append(changed, "endpoint")
+	}
+	if h.Status.ClusterCAHash != "" && h.Status.ClusterCAHash != hashCA(caData) {
+		changed = append(changed, "certificate authority")
+	}
+
+	return changed
+}
+
+func hashCA(caData string) string {
+	if caData == "" {
+		return ""
+	}
+	sum := sha256.Sum256([]byte(caData))
+
+	return hex.EncodeToString(sum[:])
+}
+
 func (h *HistoryEntry) Equals(other *HistoryEntry) bool {
 
 	if h == nil || other == nil {
@@ -254,6 +347,12 @@ func (l *HistoryEntryList) ToTable(currentContextID string) *metav1.Table {
 }
 
 func getTimeLeft(entry *HistoryEntry) string {
+	if entry.Status.CredentialsExpiry != nil {
+		if entry.CredentialsExpired() {
+			return entry.CredentialsExpiryDescription()
+		}
+		return htime.GetRemainingTime(entry.Status.CredentialsExpiry.Time)
+	}
 
 	var expiresTime time.Time
 	var err error
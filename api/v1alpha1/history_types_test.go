@@ -0,0 +1,99 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package v1alpha1_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fidelity/kconnect/api/v1alpha1"
+)
+
+func TestCredentialsExpiry(t *testing.T) {
+	g := NewWithT(t)
+
+	alias := ""
+	entry := v1alpha1.NewHistoryEntry()
+	entry.Spec.Alias = &alias
+	g.Expect(entry.CredentialsExpired()).To(BeFalse())
+	g.Expect(entry.CredentialsExpiryDescription()).To(BeEmpty())
+
+	expiry := metav1.NewTime(time.Now().Add(-2*time.Hour - time.Minute))
+	entry.Status.CredentialsExpiry = &expiry
+	g.Expect(entry.CredentialsExpired()).To(BeTrue())
+	g.Expect(entry.CredentialsExpiryDescription()).To(Equal("expired 2h ago"))
+
+	list := v1alpha1.NewHistoryEntryList()
+	list.Items = append(list.Items, *entry)
+	table := list.ToTable("")
+	g.Expect(table.Rows[0].Cells[7]).To(Equal("expired 2h ago"))
+
+	copied := entry.DeepCopy()
+	copied.Status.CredentialsExpiry.Time = time.Now().Add(45*time.Minute + 30*time.Second)
+	g.Expect(entry.CredentialsExpired()).To(BeTrue())
+	g.Expect(copied.CredentialsExpired()).To(BeFalse())
+	g.Expect(copied.CredentialsExpiryDescription()).To(Equal("expires in 45m"))
+}
+
+func TestCredentialsTimeLeft(t *testing.T) {
+	g := NewWithT(t)
+
+	entry := v1alpha1.NewHistoryEntry()
+	g.Expect(entry.CredentialsTimeLeft()).To(BeEmpty())
+
+	expiry := metav1.NewTime(time.Now().Add(45*time.Minute + 30*time.Second))
+	entry.Status.CredentialsExpiry = &expiry
+	g.Expect(entry.CredentialsTimeLeft()).To(Equal("45m"))
+
+	entry.Status.CredentialsExpiry.Time = time.Now().Add(-time.Minute)
+	g.Expect(entry.CredentialsTimeLeft()).To(Equal("expired"))
+}
+
+func TestEnvironmentLabel(t *testing.T) {
+	g := NewWithT(t)
+
+	entry := v1alpha1.NewHistoryEntry()
+	g.Expect(entry.EnvironmentLabel()).To(BeEmpty())
+
+	entry.Spec.Group = "payments-uat"
+	g.Expect(entry.EnvironmentLabel()).To(Equal("payments-uat"))
+
+	entry.Spec.Annotations = map[string]string{"env": "uat"}
+	g.Expect(entry.EnvironmentLabel()).To(Equal("uat"))
+
+	entry.Spec.Annotations["environment"] = "production"
+	g.Expect(entry.EnvironmentLabel()).To(Equal("production"))
+}
+
+func TestClusterDetailsChanged(t *testing.T) {
+	g := NewWithT(t)
+
+	entry := v1alpha1.NewHistoryEntry()
+	g.Expect(entry.ClusterDetailsChanged("https://api.example.com", "Y2E=")).To(BeEmpty())
+
+	entry.SetClusterDetails("https://api.example.com", "Y2E=")
+	g.Expect(entry.Status.ClusterCAHash).NotTo(Equal("Y2E="))
+	g.Expect(entry.ClusterDetailsChanged("https://api.example.com", "Y2E=")).To(BeEmpty())
+	g.Expect(entry.ClusterDetailsChanged("https://api2.example.com", "Y2E=")).To(Equal([]string{"endpoint"}))
+	g.Expect(entry.ClusterDetailsChanged("https://api2.example.com", "bmV3")).To(Equal([]string{"endpoint", "certificate authority"}))
+
+	entry.SetClusterDetails("https://api.example.com", "")
+	g.Expect(entry.ClusterDetailsChanged("https://api.example.com", "bmV3")).To(BeEmpty())
+}
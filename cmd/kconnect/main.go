@@ -22,20 +22,30 @@ import (
 	"log"
 	"os"
 	"strconv"
+	"time"
 
+	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
 	"github.com/fidelity/kconnect/internal/commands"
 	intver "github.com/fidelity/kconnect/internal/version"
+	"github.com/fidelity/kconnect/pkg/ci"
+	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/flags"
 	"github.com/fidelity/kconnect/pkg/logging"
 	_ "github.com/fidelity/kconnect/pkg/plugins" // Import all the plugins
+	"github.com/fidelity/kconnect/pkg/plugins/external"
+	"github.com/fidelity/kconnect/pkg/prompt"
+	"github.com/fidelity/kconnect/pkg/utils"
 )
 
 func main() {
 	if err := setupLogging(); err != nil {
 		log.Fatalf("failed to configure logging %v", err)
 	}
+	if err := setupPrompt(); err != nil {
+		zap.S().Fatalw("failed to configure prompts", "error", err.Error())
+	}
 
 	v := intver.Get()
 	zap.S().Infow("kconnect - the Kubernetes Connection Manager CLI", "version", v.Version)
@@ -46,15 +56,34 @@ func main() {
 	if err != nil {
 		zap.S().Fatalw("failed getting root command", "error", err.Error())
 	}
-	if err := rootCmd.ExecuteContext(ctx); err != nil {
-		zap.S().Fatalw("failed executing root command", "error", err.Error())
+	start := time.Now()
+	err = rootCmd.ExecuteContext(ctx)
+	cmd := executedCommand(rootCmd)
+	// Stop any long-running plugins before exiting
+	external.Cleanup()
+	commands.StopProfile(ctx, time.Since(start))
+	commands.ReportUsage(ctx, cmd, time.Since(start), err)
+	if err != nil {
+		zap.S().Errorw("failed executing root command", "error", err.Error())
+		os.Exit(commands.HandleError(cmd, err))
 	}
 }
 
+// executedCommand finds the command that was run from the arguments, in the same
+// way as the root command does when it's executed
+func executedCommand(rootCmd *cobra.Command) *cobra.Command {
+	cmd, _, err := rootCmd.Find(os.Args[1:])
+	if err != nil || cmd == nil {
+		return rootCmd
+	}
+
+	return cmd
+}
+
 func setupLogging() error {
-	verbosity, err := flags.GetFlagValueDirect(os.Args, "verbosity", "v")
+	verbosity, err := getFlagValue("verbosity", "v")
 	if err != nil {
-		return fmt.Errorf("getting verbosity flag: %w", err)
+		return err
 	}
 
 	logVerbosity := 0
@@ -65,9 +94,56 @@ func setupLogging() error {
 		}
 	}
 
-	if err := logging.Configure(logVerbosity); err != nil {
+	opts := &logging.Options{Verbosity: logVerbosity}
+	if ci.Enabled(os.Args) {
+		opts.NoColor = true
+		utils.DisableColors()
+	}
+	if opts.Format, err = getFlagValue("log-format", ""); err != nil {
+		return err
+	}
+	if opts.File, err = getFlagValue("log-file", ""); err != nil {
+		return err
+	}
+	levels, err := getFlagValue("log-level", "")
+	if err != nil {
+		return err
+	}
+	if opts.ComponentLevels, err = logging.ParseComponentLevels(levels); err != nil {
+		return fmt.Errorf("parsing log levels: %w", err)
+	}
+
+	if err := logging.Configure(opts); err != nil {
 		log.Fatalf("failed to configure logging %v", err)
 	}
 
 	return nil
 }
+
+// getFlagValue gets the value of a logging flag before the commands are created
+// so that everything is logged with the configured logger
+func getFlagValue(longName, shortName string) (string, error) {
+	value, err := flags.GetFlagValueDirect(os.Args, longName, shortName)
+	if err != nil {
+		// An invalid app config is reported by the command thats run, this
+		// allows the config commands to be used to fix it
+		if !config.IsValidationFailed(err) {
+			return "", fmt.Errorf("getting %s flag: %w", longName, err)
+		}
+		return "", nil
+	}
+
+	return value, nil
+}
+
+// setupPrompt will set the backend used to prompt for values. The KCONNECT_PROMPT
+// environment variable can be set to json so that kconnect can be driven by a GUI.
+func setupPrompt() error {
+	backend, err := prompt.NewBackend(os.Getenv("KCONNECT_PROMPT"), os.Stdin, os.Stdout)
+	if err != nil {
+		return err
+	}
+	prompt.SetBackend(backend)
+
+	return nil
+}
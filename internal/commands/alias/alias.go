@@ -47,6 +47,9 @@ connection history entries.
 
   # Remove an alias from a connection history entry
   {{.CommandPath}} alias remove --alias appdev
+
+  # Pin a kubeconfig file to a connection history entry
+  {{.CommandPath}} alias pin --alias appdev --kubeconfig ~/.kube/appdev.config
 `
 )
 
@@ -96,6 +99,12 @@ func Command() (*cobra.Command, error) {
 	}
 	aliasCmd.AddCommand(removeCmd)
 
+	pinCmd, err := pinCommand()
+	if err != nil {
+		return nil, fmt.Errorf("creating alias pin command: %w", err)
+	}
+	aliasCmd.AddCommand(pinCmd)
+
 	return aliasCmd, nil
 
 }
@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package alias
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/internal/helpers"
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	shortDescPin = "Pin a kubeconfig and context name to a connection history entry"
+	longDescPin  = `
+Pins the kubeconfig file to write to, and the name of the context, to a
+connection history entry.
+
+When reconnecting to the entry the pinned kubeconfig and context name are used
+instead of the ones from the original connection. This lets each alias write to
+its own kubeconfig file, e.g. to keep production clusters separate.
+
+The context name is a template, the fields available are .Alias, .ID, .Context
+(the context name from the discovery provider), .ClusterName and .Provider.
+
+The entry can also be added to an alias group. The kubeconfig files of all the
+entries in a group can be exported with "kubeconfig path @group".
+`
+	examplesPin = `
+  # Write the prod-eu alias to its own kubeconfig file
+  {{.CommandPath}} alias pin --alias prod-eu --kubeconfig ~/.kube/prod.config
+
+  # Name the context of the prod-eu alias after the alias
+  {{.CommandPath}} alias pin --alias prod-eu --context-name "{{.Alias}}"
+
+  # Add the prod-eu alias to the payments group with its own kubeconfig file
+  {{.CommandPath}} alias pin --alias prod-eu --group payments --kubeconfig ~/.kube/prod-eu.config
+
+  # Remove the pinned kubeconfig and context name
+  {{.CommandPath}} alias pin --alias prod-eu --clear
+`
+)
+
+func pinCommand() (*cobra.Command, error) { //nolint: dupl
+	cfg := config.NewConfigurationSet()
+
+	pinCmd := &cobra.Command{
+		Use:     "pin",
+		Short:   shortDescPin,
+		Long:    longDescPin,
+		Example: examplesPin,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			commonCfg, err := helpers.GetCommonConfig(cmd, cfg)
+			if err != nil {
+				return fmt.Errorf("gettng common config: %w", err)
+			}
+			if err := config.ApplyToConfigSet(commonCfg.ConfigFile, cfg); err != nil {
+				return fmt.Errorf("applying app config: %w", err)
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `alias pin` command")
+			params := &app.AliasPinInput{}
+
+			if err := config.Unmarshall(cfg, params); err != nil {
+				return fmt.Errorf("unmarshalling config into to params: %w", err)
+			}
+
+			historyLoader, err := loader.NewFileLoader(params.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", params.Location, err)
+			}
+			store, err := history.NewStore(maxHistoryEntries, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+
+			a := app.New(app.WithHistoryStore(store))
+
+			return a.AliasPin(cmd.Context(), params)
+		},
+	}
+	utils.FormatCommand(pinCmd)
+
+	if err := addConfigPin(cfg); err != nil {
+		return nil, fmt.Errorf("adding pin command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(pinCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return pinCmd, nil
+}
+
+func addConfigPin(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location config: %w", err)
+	}
+	if err := app.AddHistoryIdentifierConfig(cs); err != nil {
+		return fmt.Errorf("adding history identifier config: %w", err)
+	}
+
+	if _, err := cs.String("kubeconfig", "", "The kubeconfig file to write to when reconnecting to the history entry"); err != nil {
+		return fmt.Errorf("adding kubeconfig config item: %w", err)
+	}
+	if _, err := cs.String("context-name", "", "A template for the name of the context when reconnecting to the history entry, e.g. {{.Alias}}"); err != nil {
+		return fmt.Errorf("adding context-name config item: %w", err)
+	}
+	if _, err := cs.String("group", "", "The alias group to add the history entry to"); err != nil {
+		return fmt.Errorf("adding group config item: %w", err)
+	}
+	if _, err := cs.Bool("clear", false, "Remove the pinned kubeconfig, context name and group from the history entry"); err != nil {
+		return fmt.Errorf("adding clear config item: %w", err)
+	}
+
+	return nil
+}
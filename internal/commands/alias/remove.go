@@ -35,7 +35,7 @@ var (
 	shortDescRemove = "Remove connection history entry aliases."
 	longDescRemove  = `
 Remove an alias from a single connection history entry by the entry ID or the
-alias.
+alias. The alias can also be given as an argument.
 
 Set the --all flag on this command to remove all connection history aliases from
 the user's connection history.
@@ -44,6 +44,9 @@ the user's connection history.
   # Remove an alias using the alias name
   {{.CommandPath}} alias remove --alias dev-bu-1
 
+  # Remove an alias given as an argument
+  {{.CommandPath}} alias remove dev-bu-1
+
   # Remove an alias using a histiry entry id
   {{.CommandPath}} alias remove --id 01EMEM5DB60TMX7D8SS2JCX3MT
 
@@ -62,10 +65,12 @@ func removeCommand() (*cobra.Command, error) { //nolint: dupl
 	cfg := config.NewConfigurationSet()
 
 	rmCmd := &cobra.Command{
-		Use:     "remove",
-		Short:   shortDescRemove,
-		Long:    longDescRemove,
-		Example: examplesRemove,
+		Use:               "remove [alias]",
+		Short:             shortDescRemove,
+		Long:              longDescRemove,
+		Example:           examplesRemove,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: completeAliasArg,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			flags.BindFlags(cmd)
 			flags.PopulateConfigFromCommand(cmd, cfg)
@@ -86,6 +91,9 @@ func removeCommand() (*cobra.Command, error) { //nolint: dupl
 			if err := config.Unmarshall(cfg, params); err != nil {
 				return fmt.Errorf("unmarshalling config into to params: %w", err)
 			}
+			if len(args) == 1 && params.Alias == "" {
+				params.Alias = args[0]
+			}
 
 			historyLoader, err := loader.NewFileLoader(params.Location)
 			if err != nil {
@@ -110,6 +118,12 @@ func removeCommand() (*cobra.Command, error) { //nolint: dupl
 	if err := flags.CreateCommandFlags(rmCmd, cfg); err != nil {
 		return nil, err
 	}
+	if err := rmCmd.RegisterFlagCompletionFunc("alias", helpers.CompleteAliases); err != nil {
+		return nil, fmt.Errorf("registering alias completion: %w", err)
+	}
+	if err := rmCmd.RegisterFlagCompletionFunc("id", helpers.CompleteHistoryIDs); err != nil {
+		return nil, fmt.Errorf("registering id completion: %w", err)
+	}
 
 	return rmCmd, nil
 
@@ -132,3 +146,12 @@ func addConfigRemove(cs config.ConfigurationSet) error {
 
 	return nil
 }
+
+// completeAliasArg completes the alias argument, only one alias can be removed at a time
+func completeAliasArg(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+
+	return helpers.CompleteAliases(cmd, args, toComplete)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	ErrUnsupportedShell = errors.New("unsupported shell")
+
+	shortDesc = "Generate the shell completion script."
+	longDesc  = `
+Generate the script that completes kconnect commands, flags and values in the
+supplied shell.
+
+As well as the commands and flags, the aliases and ids in the connection history
+and the identity providers supported by each discovery provider are completed,
+for example with 'kconnect to <TAB>' or 'kconnect use eks --idp-protocol <TAB>'.
+`
+	examples = `
+  # Load the completions in the current bash session
+  source <({{.CommandPath}} bash)
+
+  # Load the completions for every zsh session
+  {{.CommandPath}} zsh > "${fpath[1]}/_kconnect"
+
+  # Load the completions for every fish session
+  {{.CommandPath}} fish > ~/.config/fish/completions/kconnect.fish
+
+  # Load the completions in the current PowerShell session
+  {{.CommandPath}} powershell | Out-String | Invoke-Expression
+`
+)
+
+// Command creates the completion cobra command
+func Command() *cobra.Command {
+	completionCmd := &cobra.Command{
+		Use:       "completion [bash|zsh|fish|powershell]",
+		Short:     shortDesc,
+		Long:      longDesc,
+		Example:   examples,
+		Args:      cobra.ExactValidArgs(1),
+		ValidArgs: []string{"bash", "zsh", "fish", "powershell"},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			root := cmd.Root()
+
+			var err error
+			switch args[0] {
+			case "bash":
+				err = root.GenBashCompletion(os.Stdout)
+			case "zsh":
+				err = root.GenZshCompletion(os.Stdout)
+			case "fish":
+				err = root.GenFishCompletion(os.Stdout, true)
+			case "powershell":
+				err = genPowerShellCompletion(os.Stdout, root)
+			default:
+				return fmt.Errorf("generating completion for %s: %w", args[0], ErrUnsupportedShell)
+			}
+			if err != nil {
+				return fmt.Errorf("generating %s completion: %w", args[0], err)
+			}
+
+			return nil
+		},
+	}
+	utils.FormatCommand(completionCmd)
+
+	return completionCmd
+}
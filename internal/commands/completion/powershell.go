@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package completion
+
+import (
+	"fmt"
+	"io"
+
+	"github.com/spf13/cobra"
+)
+
+// powerShellScript registers a completer that asks kconnect for the completions, in the
+// same way as the bash, zsh and fish scripts, so that the aliases, history ids and
+// identity providers are completed as well as the commands and flags. The completion
+// script of cobra for PowerShell only completes the commands and flags.
+const powerShellScript = `# powershell completion for %[1]s
+
+Register-ArgumentCompleter -Native -CommandName '%[1]s' -ScriptBlock {
+    param($WordToComplete, $CommandAst, $CursorPosition)
+
+    # The command line up to the cursor
+    $Command = "$($CommandAst.CommandElements)"
+    $Command = $Command.Substring(0, [Math]::Min($Command.Length, $CursorPosition - $CommandAst.Extent.StartOffset))
+    $Program, $Arguments = $Command.Split(" ", 2)
+
+    $RequestComp = "$Program %[2]s $Arguments"
+    if ($WordToComplete -eq "") {
+        # Complete the next argument rather than the last one
+        $RequestComp = "$RequestComp ''"
+    }
+    $Out = @(Invoke-Expression -ErrorAction SilentlyContinue "& $RequestComp" 2>$null)
+
+    # The last line is the directive of the completions, e.g. :4
+    $Directive = 0
+    if ($Out.Count -gt 0 -and $Out[-1] -match '^:(\d+)$') {
+        $Directive = [int]$Matches[1]
+        $Out = @($Out | Select-Object -SkipLast 1)
+    }
+    if (($Directive -band %[3]d) -ne 0) {
+        return
+    }
+
+    $Out | Where-Object { $_ -like "$WordToComplete*" } | ForEach-Object {
+        $Name, $Description = $_.Split("` + "`" + `t", 2)
+        if (-not $Description) {
+            $Description = $Name
+        }
+        [System.Management.Automation.CompletionResult]::new($Name, $Name, 'ParameterValue', $Description)
+    }
+}
+`
+
+// genPowerShellCompletion writes the PowerShell completion script for the root command
+func genPowerShellCompletion(w io.Writer, root *cobra.Command) error {
+	_, err := fmt.Fprintf(w, powerShellScript, root.Name(), cobra.ShellCompRequestCmd, cobra.ShellCompDirectiveError)
+
+	return err
+}
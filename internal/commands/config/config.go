@@ -20,6 +20,7 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
 
 	"github.com/fidelity/kconnect/internal/helpers"
 	"github.com/fidelity/kconnect/pkg/app"
@@ -38,7 +39,10 @@ If run with no flags, the command displays the configurations stored in the
 current user's $HOME/.kconnect/config.yaml file.
 
 The configure command can create a set of default configurations for a new
-system or a new user via the -f flag and a local filename or remote URL.
+system or a new user via the -f flag and a local filename or remote URL. The
+configuration can also be pulled from an OCI artifact in a container registry
+using an oci:// reference, the registry credentials are taken from the docker
+config and credential helpers unless a username and password are supplied.
 
 The user typically only needs to use this command the first time they use
 kconnect.
@@ -56,8 +60,17 @@ kconnect.
   # Set the user's configurations from a remote location via HTTP
   {{.CommandPath}} config -f https://mycompany.com/config.yaml
 
+  # Set the user's configurations from an OCI artifact in a container registry
+  {{.CommandPath}} config -f oci://ghcr.io/mycompany/kconnect-config:v1
+
   # Set the user's configurations from stdin
   cat ./config.yaml | {{.CommandPath}} config -f -
+
+  # Validate the user's configurations
+  {{.CommandPath}} config validate
+
+  # Print the JSON Schema for the configuration file
+  {{.CommandPath}} config schema
 `
 )
 
@@ -75,7 +88,12 @@ func Command() (*cobra.Command, error) {
 			flags.PopulateConfigFromCommand(cmd, cfg)
 			commonCfg, err := helpers.GetCommonConfig(cmd, cfg)
 			if err != nil {
-				return fmt.Errorf("gettng common config: %w", err)
+				if !config.IsValidationFailed(err) {
+					return fmt.Errorf("gettng common config: %w", err)
+				}
+				// Don't fail here as this command is used to fix an invalid configuration
+				zap.S().Warnf("the current configuration is invalid, use `%s config validate` for details", utils.CommandName())
+				return nil
 			}
 			if err := config.ApplyToConfigSet(commonCfg.ConfigFile, cfg); err != nil {
 				return fmt.Errorf("applying app config: %w", err)
@@ -104,6 +122,18 @@ func Command() (*cobra.Command, error) {
 		return nil, err
 	}
 
+	validateCmd, err := validateCommand()
+	if err != nil {
+		return nil, fmt.Errorf("creating config validate command: %w", err)
+	}
+	cfgCmd.AddCommand(validateCmd)
+
+	schemaCmd, err := schemaCommand()
+	if err != nil {
+		return nil, fmt.Errorf("creating config schema command: %w", err)
+	}
+	cfgCmd.AddCommand(schemaCmd)
+
 	return cfgCmd, nil
 
 }
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	shortDescSchema = "Print the JSON Schema for the kconnect configuration."
+	longDescSchema  = `
+Print a JSON Schema that describes the kconnect configuration file.
+
+The schema includes every configuration item of the registered providers with
+its type, default value and description. The type of each item and whether its
+required or sensitive are included as x-kconnect-type, x-kconnect-required and
+x-kconnect-sensitive as all the values are strings in the configuration file.
+
+If a provider name is supplied then only the schema for the configuration items
+of that provider is printed.
+`
+	examplesSchema = `
+  # Print the schema for the configuration file
+  {{.CommandPath}} config schema > kconnect-schema.json
+
+  # Print the schema for the eks provider configuration items
+  {{.CommandPath}} config schema eks
+`
+)
+
+func schemaCommand() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	schemaCmd := &cobra.Command{
+		Use:     "schema [provider]",
+		Short:   shortDescSchema,
+		Long:    longDescSchema,
+		Example: examplesSchema,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `config schema` command")
+			input := &app.ConfigSchemaInput{}
+
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into to params: %w", err)
+			}
+			if len(args) > 0 {
+				input.Provider = args[0]
+			}
+
+			a := app.New()
+			return a.ConfigurationSchema(cmd.Context(), input)
+		},
+	}
+	utils.FormatCommand(schemaCmd)
+
+	if err := app.AddCommonConfigItems(cfg); err != nil {
+		return nil, fmt.Errorf("add schema command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(schemaCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return schemaCmd, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	shortDescValidate = "Validate a kconnect configuration file."
+	longDescValidate  = `
+Validate a kconnect configuration file and report any problems found.
+
+The validation checks for unknown keys, values of the wrong type and values
+that are not allowed (e.g. an unknown idp-protocol). Each problem is reported
+with the file and line where it was found and a suggested fix where possible.
+
+Other commands only warn about unknown keys and providers when they load the
+configuration, as they may be for a plugin that isn't installed or from a newer
+version of kconnect.
+
+If no file is specified the current user's configuration is validated.
+`
+	examplesValidate = `
+  # Validate the user's current configuration
+  {{.CommandPath}} config validate
+
+  # Validate a configuration file before importing it
+  {{.CommandPath}} config validate -f ./defaults.yaml
+`
+)
+
+func validateCommand() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	validateCmd := &cobra.Command{
+		Use:     "validate",
+		Short:   shortDescValidate,
+		Long:    longDescValidate,
+		Example: examplesValidate,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `config validate` command")
+			input := &app.ConfigValidateInput{}
+
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into to params: %w", err)
+			}
+
+			a := app.New()
+			return a.ConfigurationValidate(cmd.Context(), input)
+		},
+	}
+	utils.FormatCommand(validateCmd)
+
+	if err := addConfigValidate(cfg); err != nil {
+		return nil, fmt.Errorf("add validate command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(validateCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return validateCmd, nil
+}
+
+func addConfigValidate(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if _, err := cs.String("file", "", "Configuration file to validate"); err != nil {
+		return fmt.Errorf("adding file config item: %w", err)
+	}
+	if err := cs.SetShort("file", "f"); err != nil {
+		return fmt.Errorf("setting shorthand for file config item: %w", err)
+	}
+
+	cs.SetHistoryIgnore("file") //nolint
+
+	return nil
+}
@@ -0,0 +1,147 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package credentialprocess
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/internal/helpers"
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/ci"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDesc = "Output the AWS credentials of a connection history entry for credential_process"
+	longDesc  = `
+Output the AWS credentials of a connection history entry in the credential_process
+format, so that the aws cli and the AWS SDKs can use the same session as kconnect
+outside of Kubernetes, e.g. for the credentials from the saml identity provider.
+
+The history entry must be for an EKS cluster. For the saml identity provider the
+credentials are read from the AWS profile that kconnect saved them to. If they
+have expired, or expire in the next 5 minutes, the cluster is reconnected to in
+the same way as the to command to refresh them. For other identity providers,
+e.g. aws-iam with an SSO profile, the credentials of the entry's AWS profile are
+resolved in the same way as the aws cli.
+
+The aws cli doesn't show prompts from a credential_process, so kconnect never
+prompts. If the identity provider needs a value that isn't saved, e.g. your
+password, run the to command to authenticate again.
+
+The credential-process command accepts the same history entry references as the
+to command, the entry can also be given with --alias.
+`
+	examples = `
+  # Use the credentials of an alias with the aws cli, in ~/.aws/config
+  [profile uat-bu1]
+  credential_process = {{.CommandPath}} credential-process --alias uat-bu1
+
+  # Output the credentials of the last cluster connected to
+  {{.CommandPath}} credential-process -
+`
+)
+
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	credentialProcessCmd := &cobra.Command{
+		Use:               "credential-process [historyid/alias/-/LAST/LAST~N]",
+		Short:             shortDesc,
+		Long:              longDesc,
+		Example:           examples,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: helpers.CompleteAliasesAndIDs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `credential-process` command")
+
+			input := &app.CredentialProcessInput{}
+			if len(args) > 0 {
+				input.AliasOrIDORPosition = args[0]
+			}
+
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into credential-process params: %w", err)
+			}
+
+			historyLoader, err := loader.NewFileLoader(input.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", input.Location, err)
+			}
+			// refreshing credentials should never increase number of history items, so set to arbitrary large number
+			input.MaxItems = 10000
+			store, err := history.NewStore(input.MaxItems, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+
+			opts := []app.Option{app.WithHistoryStore(store), app.WithInteractive(false)}
+			if input.CI {
+				opts = append(opts, app.WithCIEnvironment(ci.Detect()))
+			}
+			a := app.New(opts...)
+
+			return a.CredentialProcess(cmd.Context(), input)
+		},
+	}
+	utils.FormatCommand(credentialProcessCmd)
+
+	if err := addConfig(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(credentialProcessCmd, cfg); err != nil {
+		return nil, err
+	}
+	if err := credentialProcessCmd.RegisterFlagCompletionFunc("alias", helpers.CompleteAliases); err != nil {
+		return nil, fmt.Errorf("registering alias completion: %w", err)
+	}
+
+	return credentialProcessCmd, nil
+}
+
+func addConfig(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if err := app.AddEntryAliasConfigItems(cs); err != nil {
+		return fmt.Errorf("adding alias config: %w", err)
+	}
+	if _, err := cs.String("password", "", "Password to use"); err != nil {
+		return fmt.Errorf("adding password config: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location items: %w", err)
+	}
+
+	cs.SetHistoryIgnore("password") //nolint
+	cs.SetSensitive("password")     //nolint
+
+	return nil
+}
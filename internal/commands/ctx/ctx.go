@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ctx
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDesc = "List and switch between the kconnect contexts"
+	longDesc  = `
+List and switch between the contexts in the kubeconfig that were created by kconnect.
+
+The contexts are ordered by when their connection history entry was last used. A
+context can be chosen by its name, its alias or a fuzzy search of them. When the
+search matches more than 1 context you will be asked to choose one.
+
+The previous context is saved in the same file as kubectx (~/.kube/kubectx), so
+"ctx -" switches back to a context set by kubectx and "kubectx -" switches back
+to a context set by kconnect. This also applies when a context is set by the
+use and to commands.
+`
+	examples = `
+  # Choose a kconnect context interactively
+  {{.CommandPath}} ctx
+
+  # List the kconnect contexts
+  {{.CommandPath}} ctx --list
+
+  # Switch to a context by its alias
+  {{.CommandPath}} ctx mydev
+
+  # Switch to the context that fuzzy matches "prd"
+  {{.CommandPath}} ctx prd
+
+  # Switch back to the previous context
+  {{.CommandPath}} ctx -
+`
+)
+
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	ctxCmd := &cobra.Command{
+		Use:     "ctx [name/alias/-]",
+		Short:   shortDesc,
+		Long:    longDesc,
+		Example: examples,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `ctx` command")
+
+			input := &app.SwitchContextInput{}
+			if len(args) > 0 {
+				input.Context = args[0]
+			}
+
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into ctx params: %w", err)
+			}
+
+			historyLoader, err := loader.NewFileLoader(input.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", input.Location, err)
+			}
+			// switching context should never increase number of history items, so set to arbitrary large number
+			store, err := history.NewStore(10000, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+
+			a := app.New(app.WithHistoryStore(store), app.WithInteractive(!input.NoInput))
+
+			return a.SwitchContext(cmd.Context(), input)
+		},
+	}
+	utils.FormatCommand(ctxCmd)
+
+	if err := addConfig(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(ctxCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return ctxCmd, nil
+}
+
+func addConfig(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if _, err := cs.Bool("list", false, "List the kconnect contexts instead of switching"); err != nil {
+		return fmt.Errorf("adding list config: %w", err)
+	}
+	if err := cs.SetShort("list", "l"); err != nil {
+		return fmt.Errorf("setting list shorthand: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location items: %w", err)
+	}
+	if err := app.AddKubeconfigConfigItems(cs); err != nil {
+		return fmt.Errorf("adding kubeconfig config items: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,89 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net"
+	"os"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/printer"
+)
+
+// ErrorOutput is printed when a command run with --output json fails
+type ErrorOutput struct {
+	Error ErrorDetails `json:"error"`
+}
+
+// ErrorDetails are the details of the error of a failed command
+type ErrorDetails struct {
+	Code     kerrors.Code `json:"code"`
+	ExitCode int          `json:"exitCode"`
+	Message  string       `json:"message"`
+}
+
+// ErrorCode returns the code for the error. Cancellation and timeouts are reported
+// as such even if the step that failed added a code.
+func ErrorCode(err error) kerrors.Code {
+	var netErr net.Error
+
+	switch {
+	case errors.Is(err, context.Canceled):
+		return kerrors.CodeCanceled
+	case errors.Is(err, context.DeadlineExceeded):
+		return kerrors.CodeTimeout
+	case kerrors.CodeOf(err) != kerrors.CodeUnknown:
+		return kerrors.CodeOf(err)
+	case errors.As(err, &netErr):
+		if netErr.Timeout() {
+			return kerrors.CodeTimeout
+		}
+		return kerrors.CodeNetworkError
+	default:
+		return kerrors.CodeUnknown
+	}
+}
+
+// HandleError returns the exit code for the error of the command. If the command
+// was run with --output json the error is also printed as a JSON object.
+func HandleError(cmd *cobra.Command, err error) int {
+	code := ErrorCode(err)
+	exitCode := kerrors.ExitCode(code)
+
+	if cmd != nil {
+		if outputFlag := cmd.Flags().Lookup("output"); outputFlag != nil && outputFlag.Value.String() == string(printer.OutputPrinterJSON) {
+			output := &ErrorOutput{
+				Error: ErrorDetails{
+					Code:     code,
+					ExitCode: exitCode,
+					Message:  err.Error(),
+				},
+			}
+			if err := json.NewEncoder(os.Stdout).Encode(output); err != nil {
+				zap.S().Debugw("failed printing error", "error", err.Error())
+			}
+		}
+	}
+
+	return exitCode
+}
@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package gettoken
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/internal/helpers"
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/ci"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDesc = "Get a fresh bearer token for a cluster in the connection history"
+	longDesc  = `
+Get a fresh bearer token for a cluster in the connection history, for scripts
+that call the Kubernetes api server directly, e.g. with curl or a client library.
+
+The get-token command first reconnects to the cluster, in the same way as the to
+command, so the identity provider and settings recorded in the history entry are
+used whatever the discovery provider is. It doesn't change the current context of
+your kubeconfig.
+
+Only the token is written to stdout, or with --output exec-credential the
+ExecCredential JSON that a kubectl credential plugin returns, which includes when
+the token expires.
+
+When get-token is the credential plugin of kubectl, --notify-expiry shows a desktop
+notification when the credentials the token is refreshed with, e.g. the AWS session,
+expire within the given time. The notification is shown once for each expiry, so
+you can run the to command to sign in again before kubectl starts failing.
+
+The get-token command accepts the same history entry references as the to
+command, the entry can also be given with --alias.
+`
+	examples = `
+  # Call the api server with curl using a token for an alias
+  curl -H "Authorization: Bearer $({{.CommandPath}} get-token --alias uat-bu1)" https://api.uat-bu1.example.com/version
+
+  # Get the token for the last cluster connected to
+  {{.CommandPath}} get-token -
+
+  # Get the ExecCredential JSON, including the expiry of the token
+  {{.CommandPath}} get-token uat-bu1 --output exec-credential
+
+  # Get the ExecCredential JSON and be notified 15 minutes before the credentials expire
+  {{.CommandPath}} get-token uat-bu1 --output exec-credential --notify-expiry 15m
+`
+)
+
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	getTokenCmd := &cobra.Command{
+		Use:               "get-token [historyid/alias/-/LAST/LAST~N]",
+		Short:             shortDesc,
+		Long:              longDesc,
+		Example:           examples,
+		Args:              cobra.MaximumNArgs(1),
+		ValidArgsFunction: helpers.CompleteAliasesAndIDs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `get-token` command")
+
+			input := &app.GetTokenInput{}
+			if len(args) > 0 {
+				input.AliasOrIDORPosition = args[0]
+			}
+
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into get-token params: %w", err)
+			}
+
+			historyLoader, err := loader.NewFileLoader(input.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", input.Location, err)
+			}
+			// getting a token should never increase number of history items, so set to arbitrary large number
+			input.MaxItems = 10000
+			store, err := history.NewStore(input.MaxItems, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+
+			opts := []app.Option{app.WithHistoryStore(store)}
+			if input.CI {
+				opts = append(opts, app.WithCIEnvironment(ci.Detect()))
+			}
+			a := app.New(opts...)
+
+			return a.GetToken(cmd.Context(), input)
+		},
+	}
+	utils.FormatCommand(getTokenCmd)
+
+	if err := addConfig(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(getTokenCmd, cfg); err != nil {
+		return nil, err
+	}
+	if err := getTokenCmd.RegisterFlagCompletionFunc("alias", helpers.CompleteAliases); err != nil {
+		return nil, fmt.Errorf("registering alias completion: %w", err)
+	}
+
+	return getTokenCmd, nil
+}
+
+func addConfig(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if err := app.AddEntryAliasConfigItems(cs); err != nil {
+		return fmt.Errorf("adding alias config: %w", err)
+	}
+	if err := app.AddGetTokenConfigItems(cs); err != nil {
+		return fmt.Errorf("adding get-token config: %w", err)
+	}
+	if _, err := cs.String("password", "", "Password to use"); err != nil {
+		return fmt.Errorf("adding password config: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location items: %w", err)
+	}
+
+	cs.SetHistoryIgnore("password") //nolint
+	cs.SetSensitive("password")     //nolint
+
+	return nil
+}
@@ -0,0 +1,135 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handoff
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDesc = "Hand off the credentials for a context to a devcontainer, WSL distro or remote host"
+	longDesc  = `
+Hand off the credentials for a context to a devcontainer, WSL distro or remote
+host, so that the credentials established on your machine can be used there
+without running the identity provider flow again.
+
+The minimum kubeconfig for the context is copied to the target. The exec
+credential plugins, e.g. aws-iam-authenticator or kubelogin, are run locally and
+replaced with the short-lived credentials they return. The kubeconfig is only
+readable by the user and is removed from the target when it expires, which is
+after --expiry or when the credentials expire if that is sooner.
+
+The target is in the format kind:[user@]name:
+  * devcontainer:[user@]container - copied with docker exec
+  * wsl:[user@]distro - copied with wsl.exe
+  * ssh:[user@]host - copied with ssh
+
+The context can be chosen by its name or alias, otherwise the current context
+is handed off.
+`
+	examples = `
+  # Hand off the current context to a devcontainer
+  {{.CommandPath}} handoff --to devcontainer:vscode@festive_hopper
+
+  # Hand off a context by its alias to a remote host for 15 minutes
+  {{.CommandPath}} handoff uat-bu1 --to ssh:dev@buildbox --expiry 15m
+
+  # Hand off the current context to a WSL distro as the default kubeconfig
+  {{.CommandPath}} handoff --to wsl:Ubuntu --remote-kubeconfig .kube/config
+`
+)
+
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	handoffCmd := &cobra.Command{
+		Use:     "handoff [name/alias]",
+		Short:   shortDesc,
+		Long:    longDesc,
+		Example: examples,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `handoff` command")
+
+			input := &app.HandoffInput{}
+			if len(args) > 0 {
+				input.Context = args[0]
+			}
+
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into handoff params: %w", err)
+			}
+
+			historyLoader, err := loader.NewFileLoader(input.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", input.Location, err)
+			}
+			// handing off credentials should never increase number of history items, so set to arbitrary large number
+			store, err := history.NewStore(10000, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+
+			a := app.New(app.WithHistoryStore(store), app.WithInteractive(!input.NoInput))
+
+			return a.Handoff(cmd.Context(), input)
+		},
+	}
+	utils.FormatCommand(handoffCmd)
+
+	if err := addConfig(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(handoffCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return handoffCmd, nil
+}
+
+func addConfig(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if err := app.AddHandoffConfigItems(cs); err != nil {
+		return fmt.Errorf("adding handoff config: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location items: %w", err)
+	}
+	if err := app.AddKubeconfigConfigItems(cs); err != nil {
+		return fmt.Errorf("adding kubeconfig config items: %w", err)
+	}
+
+	return nil
+}
@@ -94,6 +94,12 @@ func Command() (*cobra.Command, error) {
 	}
 	historyCmd.AddCommand(rmCmd)
 
+	purgeCmd, err := purgeCommand()
+	if err != nil {
+		return nil, fmt.Errorf("creating history purge command: %w", err)
+	}
+	historyCmd.AddCommand(purgeCmd)
+
 	return historyCmd, nil
 
 }
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/internal/helpers"
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	shortDescPurge = "Purge history entries and scrub recorded usernames"
+	longDescPurge  = `
+Purge the history entries for clusters that match a pattern, or that were last
+used before a date, for example to follow a policy about keeping access metadata
+on shared machines. When both --cluster and --before are set an entry has to
+match both to be purged.
+
+The usernames recorded in the flags of the entries that are kept, such as
+--username and --proxy-username, can be removed with --scrub-usernames. The
+username has to be entered again when reconnecting to these entries.
+`
+	examplesPurge = `
+  # Purge the entries for the production clusters
+  {{.CommandPath}} history purge --cluster *prod*
+
+  # Purge the entries that haven't been used since the start of the year
+  {{.CommandPath}} history purge --before 2021-01-01
+
+  # Remove the usernames from all the history entries
+  {{.CommandPath}} history purge --scrub-usernames
+
+  # Purge the entries not used in the last year and scrub the usernames of the others
+  {{.CommandPath}} history purge --before 2020-06-01 --scrub-usernames
+`
+)
+
+func purgeCommand() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	purgeCmd := &cobra.Command{
+		Use:     "purge",
+		Short:   shortDescPurge,
+		Long:    longDescPurge,
+		Example: examplesPurge,
+		Args:    cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			commonCfg, err := helpers.GetCommonConfig(cmd, cfg)
+			if err != nil {
+				return fmt.Errorf("gettng common config: %w", err)
+			}
+			if err := config.ApplyToConfigSet(commonCfg.ConfigFile, cfg); err != nil {
+				return fmt.Errorf("applying app config: %w", err)
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `history purge` command")
+
+			params := &app.HistoryPurgeInput{}
+			if err := config.Unmarshall(cfg, params); err != nil {
+				return fmt.Errorf("unmarshalling config into to params: %w", err)
+			}
+
+			historyLoader, err := loader.NewFileLoader(params.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", params.Location, err)
+			}
+			store, err := history.NewStore(maxHistoryEntries, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+
+			a := app.New(app.WithHistoryStore(store))
+
+			return a.HistoryPurge(cmd.Context(), params)
+		},
+	}
+	utils.FormatCommand(purgeCmd)
+
+	if err := addConfigPurge(cfg); err != nil {
+		return nil, fmt.Errorf("adding purge command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(purgeCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return purgeCmd, nil
+}
+
+func addConfigPurge(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location config items: %w", err)
+	}
+	if err := app.AddHistoryPurgeConfig(cs); err != nil {
+		return fmt.Errorf("adding history purge config items: %w", err)
+	}
+
+	return nil
+}
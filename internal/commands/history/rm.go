@@ -57,11 +57,12 @@ func rmCommand() (*cobra.Command, error) {
 	cfg := config.NewConfigurationSet()
 
 	importCmd := &cobra.Command{
-		Use:     "rm",
-		Args:    cobra.ArbitraryArgs,
-		Short:   shortDescRm,
-		Long:    longDescRm,
-		Example: examplesRm,
+		Use:               "rm",
+		Args:              cobra.ArbitraryArgs,
+		ValidArgsFunction: helpers.CompleteHistoryIDs,
+		Short:             shortDescRm,
+		Long:              longDescRm,
+		Example:           examplesRm,
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			flags.BindFlags(cmd)
 			flags.PopulateConfigFromCommand(cmd, cfg)
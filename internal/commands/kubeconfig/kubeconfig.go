@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	maxHistoryEntries = 100
+	shortDesc         = "Work with the kubeconfig files written by kconnect"
+	longDesc          = `
+The kubeconfig command and sub-commands help with using the kubeconfig files
+that connecting to clusters writes to.
+`
+	examples = `
+  # Get the kubeconfig files of the payments alias group as a KUBECONFIG value
+  {{.CommandPath}} kubeconfig path @payments
+`
+)
+
+// Command creates the kubeconfig cobra command
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	kubeconfigCmd := &cobra.Command{
+		Use:     "kubeconfig",
+		Short:   shortDesc,
+		Long:    longDesc,
+		Example: examples,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := cmd.Help(); err != nil {
+				zap.S().Debugw("ingoring cobra error",
+					"error",
+					err.Error())
+			}
+		},
+	}
+	utils.FormatCommand(kubeconfigCmd)
+
+	if err := app.AddCommonConfigItems(cfg); err != nil {
+		return nil, fmt.Errorf("adding common config: %w", err)
+	}
+
+	commonFlags, err := flags.CreateFlagsFromConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating common command flags: %w", err)
+	}
+	kubeconfigCmd.PersistentFlags().AddFlagSet(commonFlags)
+
+	pathCmd, err := pathCommand()
+	if err != nil {
+		return nil, fmt.Errorf("creating kubeconfig path command: %w", err)
+	}
+	kubeconfigCmd.AddCommand(pathCmd)
+
+	return kubeconfigCmd, nil
+}
@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/internal/helpers"
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	shortDescPath = "Print the kubeconfig files of aliases and alias groups as a KUBECONFIG value"
+	longDescPath  = `
+Prints the kubeconfig files that the connection history entries of aliases and
+alias groups are written to, as a list that can be used as the value of the
+KUBECONFIG environment variable. The files are separated with the path list
+separator of the OS, i.e. ":" on Linux and macOS and ";" on Windows.
+
+An alias group is referred to by its name prefixed with @. Entries are added to a
+group with "alias pin --group". Pinning a kubeconfig file to each entry in the
+group gives a merged view of the clusters in the group when KUBECONFIG is set,
+without adding them to the default kubeconfig.
+
+The kubeconfig of an entry is the kubeconfig pinned to it, otherwise the
+kubeconfig it was created with, otherwise the default kubeconfig.
+`
+	examplesPath = `
+  # Use the clusters in the payments alias group
+  export KUBECONFIG=$({{.CommandPath}} kubeconfig path @payments)
+
+  # Use the clusters in the payments group and the clusters with the uat alias
+  export KUBECONFIG=$({{.CommandPath}} kubeconfig path @payments uat)
+
+  # Use the clusters in the payments group in PowerShell
+  $env:KUBECONFIG = {{.CommandPath}} kubeconfig path '@payments'
+`
+)
+
+func pathCommand() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	pathCmd := &cobra.Command{
+		Use:     "path [@group/alias]...",
+		Short:   shortDescPath,
+		Long:    longDescPath,
+		Example: examplesPath,
+		Args:    cobra.MinimumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			commonCfg, err := helpers.GetCommonConfig(cmd, cfg)
+			if err != nil {
+				return fmt.Errorf("gettng common config: %w", err)
+			}
+			if err := config.ApplyToConfigSet(commonCfg.ConfigFile, cfg); err != nil {
+				return fmt.Errorf("applying app config: %w", err)
+			}
+
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `kubeconfig path` command")
+			params := &app.KubeconfigPathInput{
+				Targets: args,
+			}
+
+			if err := config.Unmarshall(cfg, params); err != nil {
+				return fmt.Errorf("unmarshalling config into to params: %w", err)
+			}
+
+			historyLoader, err := loader.NewFileLoader(params.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", params.Location, err)
+			}
+			store, err := history.NewStore(maxHistoryEntries, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+
+			a := app.New(app.WithHistoryStore(store))
+
+			return a.KubeconfigPath(cmd.Context(), params)
+		},
+	}
+	utils.FormatCommand(pathCmd)
+
+	if err := addConfigPath(cfg); err != nil {
+		return nil, fmt.Errorf("adding path command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(pathCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return pathCmd, nil
+}
+
+func addConfigPath(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location config: %w", err)
+	}
+	if err := app.AddKubeconfigConfigItems(cs); err != nil {
+		return fmt.Errorf("adding kubeconfig config items: %w", err)
+	}
+
+	return nil
+}
@@ -106,10 +106,10 @@ func addConfig(cs config.ConfigurationSet) error {
 	if err := cs.SetShort("all", "a"); err != nil {
 		return fmt.Errorf("adding all short flag: %w", err)
 	}
-	if _, err := cs.String("alias", "", "comma delimited list of aliass"); err != nil {
+	if _, err := cs.StringSlice("alias", []string{}, "comma delimited list of aliases"); err != nil {
 		return fmt.Errorf("adding alias config: %w", err)
 	}
-	if _, err := cs.String("ids", "", "comma delimited list of ids"); err != nil {
+	if _, err := cs.StringSlice("ids", []string{}, "comma delimited list of ids"); err != nil {
 		return fmt.Errorf("adding ids config: %w", err)
 	}
 
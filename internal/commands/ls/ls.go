@@ -22,6 +22,7 @@ import (
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	"github.com/fidelity/kconnect/internal/commands/use"
 	"github.com/fidelity/kconnect/internal/helpers"
 	"github.com/fidelity/kconnect/pkg/app"
 	"github.com/fidelity/kconnect/pkg/config"
@@ -41,6 +42,9 @@ Each time kconnect creates a new kubectl context to connect to a Kubernetes
 cluster, it saves the settings for the new connection as an entry in the user's
 connection history.  The user can then reconnect using those same settings later
 via the connection history entry's ID or alias.
+
+Use the provider subcommands (e.g. ls eks) to list the clusters discovered by a
+cluster provider instead of the connection history.
 `
 	examples = `
   # Display all connection history entries as a table
@@ -66,6 +70,9 @@ via the connection history entry's ID or alias.
 
   # Reconnect using the connection history entry alias
   {{.CommandPath}} to mydev
+
+  # Display the clusters discovered by the EKS managed cluster provider as JSON
+  {{.CommandPath}} ls eks --output json
 `
 )
 
@@ -114,6 +121,13 @@ func Command() (*cobra.Command, error) {
 	}
 	utils.FormatCommand(lsCmd)
 
+	// Add the provider subcommands to list the discovered clusters
+	providerCmds, err := use.ListCommands()
+	if err != nil {
+		return nil, fmt.Errorf("creating provider list commands: %w", err)
+	}
+	lsCmd.AddCommand(providerCmds...)
+
 	if err := addConfig(cfg); err != nil {
 		return nil, fmt.Errorf("add command config: %w", err)
 	}
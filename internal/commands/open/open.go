@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package open
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/ci"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDesc = "Open a cluster from the connection history with k9s, Lens or its dashboard."
+	longDesc  = `
+Open a cluster in the connection history with another tool.
+
+The open command first reconnects to the cluster, in the same way as the to
+command, so that the credentials are fresh. The tool is then given an isolated
+kubeconfig that only contains the context for the cluster, so it doesn't change
+the current context of your kubeconfig. The isolated kubeconfigs are kept in
+~/.kconnect/kubeconfigs.
+
+The supported tools are:
+  * k9s - runs k9s in the terminal
+  * lens - starts the Lens desktop app
+  * dashboard - opens the managed dashboard in the browser, i.e. the AWS console
+    for EKS clusters and the Azure portal for AKS clusters
+
+The open command accepts the same history entry references as the to command.
+`
+	examples = `
+  # Open a cluster with k9s based on an alias
+  {{.CommandPath}} open uat-bu1
+
+  # Open the current cluster in Lens
+  {{.CommandPath}} open - --with lens
+
+  # Open the cluster in the AWS console or Azure portal
+  {{.CommandPath}} open uat-bu1 --with dashboard
+
+  # Choose the cluster to open interactively from the history list
+  {{.CommandPath}} open
+`
+)
+
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	openCmd := &cobra.Command{
+		Use:     "open [historyid/alias/-/LAST/LAST~N]",
+		Short:   shortDesc,
+		Long:    longDesc,
+		Example: examples,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `open` command")
+
+			input := &app.OpenInput{}
+			if len(args) > 0 {
+				input.AliasOrIDORPosition = args[0]
+			}
+
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into open params: %w", err)
+			}
+
+			historyLoader, err := loader.NewFileLoader(input.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", input.Location, err)
+			}
+			// using open command should never increase number of history items, so set to arbitrary large number
+			input.MaxItems = 10000
+			store, err := history.NewStore(input.MaxItems, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+
+			opts := []app.Option{app.WithHistoryStore(store)}
+			if input.CI {
+				opts = append(opts, app.WithCIEnvironment(ci.Detect()))
+			}
+			a := app.New(opts...)
+
+			return a.Open(cmd.Context(), input)
+		},
+	}
+	utils.FormatCommand(openCmd)
+
+	if err := addConfig(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(openCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return openCmd, nil
+}
+
+func addConfig(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if err := app.AddOpenConfigItems(cs); err != nil {
+		return fmt.Errorf("adding open config: %w", err)
+	}
+	if _, err := cs.String("password", "", "Password to use"); err != nil {
+		return fmt.Errorf("adding password config: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location items: %w", err)
+	}
+
+	cs.SetHistoryIgnore("password") //nolint
+	cs.SetSensitive("password")     //nolint
+
+	return nil
+}
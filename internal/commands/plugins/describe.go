@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDescDescribe = "Show the details of a plugin"
+	longDescDescribe  = `
+Show the details of a plugin including its configuration items and, for a
+discovery plugin, the identity providers it supports.
+`
+	examplesDescribe = `
+  # Show the details of the eks plugin
+  {{.CommandPath}} plugins describe eks
+
+  # Show the details of the saml plugin as yaml
+  {{.CommandPath}} plugins describe saml --output yaml
+`
+)
+
+func describeCommand() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	describeCmd := &cobra.Command{
+		Use:     "describe [name]",
+		Short:   shortDescDescribe,
+		Long:    longDescDescribe,
+		Example: examplesDescribe,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return preRun(cmd, cfg)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `plugins describe` command")
+			params := &app.PluginDescribeInput{}
+
+			if err := config.Unmarshall(cfg, params); err != nil {
+				return fmt.Errorf("unmarshalling config into to params: %w", err)
+			}
+			params.Name = args[0]
+
+			a := app.New()
+
+			return a.PluginDescribe(cmd.Context(), params)
+		},
+	}
+	utils.FormatCommand(describeCmd)
+
+	if err := addConfigOutput(cfg); err != nil {
+		return nil, fmt.Errorf("add describe command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(describeCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return describeCmd, nil
+}
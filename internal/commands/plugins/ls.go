@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDescLs = "List the registered plugins"
+	longDescLs  = `
+List the registered discovery and identity plugins. This includes the plugins built
+into kconnect and any external plugins found on the PATH.
+
+The status shows if a plugin has been disabled in the app configuration.
+`
+	examplesLs = `
+  # Display all the plugins as a table
+  {{.CommandPath}} plugins ls
+
+  # Display all the plugins as json
+  {{.CommandPath}} plugins ls --output json
+`
+)
+
+func lsCommand() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	lsCmd := &cobra.Command{
+		Use:     "ls",
+		Short:   shortDescLs,
+		Long:    longDescLs,
+		Example: examplesLs,
+		Args:    cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return preRun(cmd, cfg)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `plugins ls` command")
+			params := &app.PluginsListInput{}
+
+			if err := config.Unmarshall(cfg, params); err != nil {
+				return fmt.Errorf("unmarshalling config into to params: %w", err)
+			}
+
+			a := app.New()
+
+			return a.PluginsList(cmd.Context(), params)
+		},
+	}
+	utils.FormatCommand(lsCmd)
+
+	if err := addConfigOutput(cfg); err != nil {
+		return nil, fmt.Errorf("add ls command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(lsCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return lsCmd, nil
+}
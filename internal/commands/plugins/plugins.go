@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/internal/helpers"
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	shortDesc = "Query and manage the discovery and identity plugins."
+	longDesc  = `
+Plugins provide the discovery of clusters (e.g. eks, aks) and the identity
+providers used to authenticate (e.g. saml, aad). Plugins are either built into
+kconnect or are external plugins found on the PATH.
+
+The plugins command and sub-commands allow you to list the registered plugins,
+see their configuration items and enable or disable them in the app configuration.
+`
+	examples = `
+  # List the registered plugins
+  {{.CommandPath}} plugins ls
+
+  # Show the configuration items of the eks plugin
+  {{.CommandPath}} plugins describe eks
+
+  # Disable the rancher plugin
+  {{.CommandPath}} plugins disable rancher
+
+  # Enable the rancher plugin again
+  {{.CommandPath}} plugins enable rancher
+`
+)
+
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	pluginsCmd := &cobra.Command{
+		Use:     "plugins",
+		Short:   shortDesc,
+		Long:    longDesc,
+		Example: examples,
+		Run: func(cmd *cobra.Command, args []string) {
+			if err := cmd.Help(); err != nil {
+				zap.S().Debugw("ingoring cobra error",
+					"error",
+					err.Error())
+			}
+		},
+	}
+	utils.FormatCommand(pluginsCmd)
+
+	if err := app.AddCommonConfigItems(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+
+	commonFlags, err := flags.CreateFlagsFromConfig(cfg)
+	if err != nil {
+		return nil, fmt.Errorf("creating common command flags: %w", err)
+	}
+	pluginsCmd.PersistentFlags().AddFlagSet(commonFlags)
+
+	lsCmd, err := lsCommand()
+	if err != nil {
+		return nil, fmt.Errorf("creating plugins ls command: %w", err)
+	}
+	pluginsCmd.AddCommand(lsCmd)
+
+	describeCmd, err := describeCommand()
+	if err != nil {
+		return nil, fmt.Errorf("creating plugins describe command: %w", err)
+	}
+	pluginsCmd.AddCommand(describeCmd)
+
+	enableCmd, err := toggleCommand(true)
+	if err != nil {
+		return nil, fmt.Errorf("creating plugins enable command: %w", err)
+	}
+	pluginsCmd.AddCommand(enableCmd)
+
+	disableCmd, err := toggleCommand(false)
+	if err != nil {
+		return nil, fmt.Errorf("creating plugins disable command: %w", err)
+	}
+	pluginsCmd.AddCommand(disableCmd)
+
+	return pluginsCmd, nil
+}
+
+func preRun(cmd *cobra.Command, cfg config.ConfigurationSet) error {
+	flags.BindFlags(cmd)
+	flags.PopulateConfigFromCommand(cmd, cfg)
+	commonCfg, err := helpers.GetCommonConfig(cmd, cfg)
+	if err != nil {
+		return fmt.Errorf("gettng common config: %w", err)
+	}
+	if err := config.ApplyToConfigSet(commonCfg.ConfigFile, cfg); err != nil {
+		return fmt.Errorf("applying app config: %w", err)
+	}
+
+	return nil
+}
+
+func addConfigOutput(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if _, err := cs.String("output", "table", "Output format for the results"); err != nil {
+		return fmt.Errorf("adding output config item: %w", err)
+	}
+	if err := cs.SetShort("output", "o"); err != nil {
+		return fmt.Errorf("adding output short flag: %w", err)
+	}
+
+	cs.SetHistoryIgnore("output") //nolint
+
+	return nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package plugins
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDescEnable = "Enable a plugin that has been disabled"
+	longDescEnable  = `
+Enable a plugin by removing it from the disabled plugins in the app configuration.
+
+Use the --config flag to enable the plugin in a different configuration file.
+`
+	examplesEnable = `
+  # Enable the rancher plugin
+  {{.CommandPath}} plugins enable rancher
+`
+	shortDescDisable = "Disable a plugin"
+	longDescDisable  = `
+Disable a plugin by adding it to the disabled plugins in the app configuration. A
+disabled plugin is hidden from the use command and can't be used to connect.
+
+Use the --config flag to disable the plugin in a different configuration file.
+`
+	examplesDisable = `
+  # Disable the rancher plugin
+  {{.CommandPath}} plugins disable rancher
+
+  # Disable the aad plugin in a team configuration file
+  {{.CommandPath}} plugins disable aad --config ./team.yaml
+`
+)
+
+func toggleCommand(enable bool) (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	toggleCmd := &cobra.Command{
+		Use:     "disable [name]",
+		Short:   shortDescDisable,
+		Long:    longDescDisable,
+		Example: examplesDisable,
+		Args:    cobra.ExactArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			return preRun(cmd, cfg)
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debugf("running `plugins %s` command", cmd.Name())
+			params := &app.PluginToggleInput{}
+
+			if err := config.Unmarshall(cfg, params); err != nil {
+				return fmt.Errorf("unmarshalling config into to params: %w", err)
+			}
+			params.Name = args[0]
+
+			a := app.New()
+			if enable {
+				return a.PluginEnable(cmd.Context(), params)
+			}
+
+			return a.PluginDisable(cmd.Context(), params)
+		},
+	}
+	if enable {
+		toggleCmd.Use = "enable [name]"
+		toggleCmd.Short = shortDescEnable
+		toggleCmd.Long = longDescEnable
+		toggleCmd.Example = examplesEnable
+	}
+	utils.FormatCommand(toggleCmd)
+
+	if err := app.AddCommonConfigItems(cfg); err != nil {
+		return nil, fmt.Errorf("add %s command config: %w", toggleCmd.Name(), err)
+	}
+
+	if err := flags.CreateCommandFlags(toggleCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return toggleCmd, nil
+}
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"runtime/pprof"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/telemetry"
+)
+
+var (
+	profileRecorder *telemetry.Recorder
+	profileFile     *os.File
+)
+
+// startProfile will record the phases of the command so the timings can be printed
+// when the command finishes. If a file is given a pprof CPU profile is also written.
+func startProfile(enabled bool, file string) error {
+	if !enabled && file == "" {
+		return nil
+	}
+
+	if enabled {
+		profileRecorder = telemetry.NewRecorder()
+		telemetry.Enable(profileRecorder)
+	}
+
+	if file != "" {
+		f, err := os.Create(file)
+		if err != nil {
+			return fmt.Errorf("creating profile file %s: %w", file, err)
+		}
+		if err := pprof.StartCPUProfile(f); err != nil {
+			f.Close() //nolint: errcheck
+			return fmt.Errorf("starting cpu profile: %w", err)
+		}
+		profileFile = f
+	}
+
+	return nil
+}
+
+// StopProfile will print the timing of each phase of the command to stderr and
+// finish writing the pprof profile. It does nothing if --profile isn't set.
+func StopProfile(ctx context.Context, total time.Duration) {
+	if profileFile != nil {
+		pprof.StopCPUProfile()
+		if err := profileFile.Close(); err != nil {
+			zap.S().Warnw("failed closing profile file", "error", err.Error())
+		}
+		profileFile = nil
+	}
+
+	if profileRecorder == nil {
+		return
+	}
+	if err := telemetry.Flush(ctx); err != nil {
+		zap.S().Debugw("failed exporting traces", "error", err.Error())
+	}
+
+	fmt.Fprintln(os.Stderr, "")
+	if err := telemetry.WriteSummary(os.Stderr, profileRecorder.Spans(), total); err != nil {
+		zap.S().Warnw("failed writing profile summary", "error", err.Error())
+	}
+}
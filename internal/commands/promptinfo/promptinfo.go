@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package promptinfo
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDesc = "Show the alias, environment and time left of the current context for a shell prompt"
+	longDesc  = `
+Show the alias, environment and credentials time left of the current context on
+a single line, for embedding in a shell prompt so you always know which cluster
+you're pointed at.
+
+The fields are separated by spaces in this order:
+  * the alias of the connection history entry, or the context name if it has no alias
+  * the environment, which is the environment or env annotation of the entry or
+    else its alias group
+  * how long is left until the credentials expire, e.g. 45m, or expired
+
+A field that isn't known is shown as -. Nothing is shown if the current context
+wasn't created by kconnect. Only the kubeconfig and the connection history are
+read, so it's quick enough to run for every prompt.
+
+With --color the environment is colored red for production, yellow for test
+environments such as uat and green otherwise, and the time left is colored when
+the credentials expire within 15 minutes. Use --shell so that the color codes are
+escaped for the prompt of your shell.
+`
+	examples = `
+  # Show the current context, e.g. uat-bu1 uat 45m
+  {{.CommandPath}} prompt-info
+
+  # Read the fields in a script
+  read -r alias env expires <<< "$({{.CommandPath}} prompt-info)"
+
+  # Add the current context to the bash prompt
+  PROMPT_COMMAND='PS1="[$({{.CommandPath}} prompt-info --color --shell bash)] \w $ "'
+
+  # Add the current context to the zsh prompt
+  setopt PROMPT_SUBST
+  PROMPT='[$({{.CommandPath}} prompt-info --color --shell zsh)] %~ %# '
+`
+)
+
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	promptInfoCmd := &cobra.Command{
+		Use:     "prompt-info",
+		Short:   shortDesc,
+		Long:    longDesc,
+		Example: examples,
+		Args:    cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `prompt-info` command")
+
+			input := &app.PromptInfoInput{}
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into prompt-info params: %w", err)
+			}
+
+			historyLoader, err := loader.NewFileLoader(input.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", input.Location, err)
+			}
+			// showing the prompt info should never increase number of history items, so set to arbitrary large number
+			store, err := history.NewStore(10000, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+
+			a := app.New(app.WithHistoryStore(store), app.WithInteractive(false))
+
+			return a.PromptInfo(cmd.Context(), input)
+		},
+	}
+	utils.FormatCommand(promptInfoCmd)
+
+	if err := addConfig(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(promptInfoCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return promptInfoCmd, nil
+}
+
+func addConfig(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if err := app.AddPromptInfoConfigItems(cs); err != nil {
+		return fmt.Errorf("adding prompt-info config: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location items: %w", err)
+	}
+	if err := app.AddKubeconfigConfigItems(cs); err != nil {
+		return fmt.Errorf("adding kubeconfig config items: %w", err)
+	}
+
+	return nil
+}
@@ -20,6 +20,7 @@ import (
 	"flag"
 	"fmt"
 	"os"
+	"sync"
 	"time"
 
 	"github.com/blang/semver"
@@ -31,12 +32,25 @@ import (
 
 	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
+	kconnectv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
 	"github.com/fidelity/kconnect/internal/commands/alias"
+	"github.com/fidelity/kconnect/internal/commands/completion"
 	configcmd "github.com/fidelity/kconnect/internal/commands/config"
+	"github.com/fidelity/kconnect/internal/commands/credentialprocess"
+	"github.com/fidelity/kconnect/internal/commands/ctx"
+	"github.com/fidelity/kconnect/internal/commands/gettoken"
+	"github.com/fidelity/kconnect/internal/commands/handoff"
 	"github.com/fidelity/kconnect/internal/commands/history"
+	"github.com/fidelity/kconnect/internal/commands/kubeconfig"
 	"github.com/fidelity/kconnect/internal/commands/logout"
 	"github.com/fidelity/kconnect/internal/commands/ls"
+	"github.com/fidelity/kconnect/internal/commands/open"
+	"github.com/fidelity/kconnect/internal/commands/plugins"
+	"github.com/fidelity/kconnect/internal/commands/promptinfo"
+	"github.com/fidelity/kconnect/internal/commands/sakubeconfig"
+	"github.com/fidelity/kconnect/internal/commands/serve"
 	"github.com/fidelity/kconnect/internal/commands/to"
+	"github.com/fidelity/kconnect/internal/commands/update"
 	"github.com/fidelity/kconnect/internal/commands/use"
 	"github.com/fidelity/kconnect/internal/commands/version"
 	"github.com/fidelity/kconnect/internal/helpers"
@@ -45,14 +59,26 @@ import (
 	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/defaults"
 	"github.com/fidelity/kconnect/pkg/flags"
+	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/notices"
+	mockdiscovery "github.com/fidelity/kconnect/pkg/plugins/discovery/mock"
+	"github.com/fidelity/kconnect/pkg/plugins/external"
+	mockidentity "github.com/fidelity/kconnect/pkg/plugins/identity/mock"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+	"github.com/fidelity/kconnect/pkg/secrets"
 	"github.com/fidelity/kconnect/pkg/utils"
 )
 
 var (
 	cfg                  config.ConfigurationSet
 	versionCheckInterval time.Duration = 1440 * time.Minute
+	invalidConfigWarning sync.Once
 )
 
+// quietCommands are run by other tools, e.g. for every shell prompt or by the aws cli,
+// so they don't show notices or warnings and don't check for a newer version
+var quietCommands = map[string]bool{"prompt-info": true, "credential-process": true}
+
 const (
 	shortDesc = "The Kubernetes Connection Manager CLI"
 	longDesc  = `
@@ -117,6 +143,20 @@ for that cluster.
 // RootCmd creates the root kconnect command
 func RootCmd() (*cobra.Command, error) {
 	cfg = config.NewConfigurationSet()
+	external.RegisterPlugins()
+	if mockPluginsEnabled(os.Args) {
+		if err := registerMockPlugins(); err != nil {
+			return nil, err
+		}
+	}
+	config.SetSchemaFunc(app.ConfigSchema)
+
+	if err := applyPluginPolicy(); err != nil {
+		return nil, fmt.Errorf("applying plugin policy: %w", err)
+	}
+	if err := applySecretStore(); err != nil {
+		return nil, fmt.Errorf("applying secret store: %w", err)
+	}
 
 	rootCmd := &cobra.Command{
 		Use:     "kconnect",
@@ -149,8 +189,47 @@ func RootCmd() (*cobra.Command, error) {
 				zap.S().Debug("Not running in a terminal, setting no-input to true")
 				cmd.Flags().Set(app.NoInputConfigItem, "true") //nolint: errcheck
 			}
+			ciEnabled, err := cmd.Flags().GetBool(app.CIConfigItem)
+			if err != nil {
+				return fmt.Errorf("getting '--%s' flag: %w", app.CIConfigItem, err)
+			}
+			if ciEnabled {
+				zap.S().Debug("Running in CI, setting no-input to true")
+				cmd.Flags().Set(app.NoInputConfigItem, "true") //nolint: errcheck
+			}
+			// Notices are only shown once so they're not shown when nobody may read them
+			if inTerminal && !ciEnabled && !quietCommands[cmd.Name()] {
+				if err := showNotices(); err != nil {
+					zap.S().Debugw("problem showing notices", "error", err.Error())
+				}
+			}
+
+			traceHTTP, err := cmd.Flags().GetBool(app.TraceHTTPConfigItem)
+			if err != nil {
+				return fmt.Errorf("getting '--%s' flag: %w", app.TraceHTTPConfigItem, err)
+			}
+			if traceHTTP {
+				khttp.EnableTracing()
+			}
+			if err := setupRecording(cmd); err != nil {
+				return err
+			}
+
+			profile, err := cmd.Flags().GetBool(app.ProfileConfigItem)
+			if err != nil {
+				return fmt.Errorf("getting '--%s' flag: %w", app.ProfileConfigItem, err)
+			}
+			profileFile, err := cmd.Flags().GetString(app.ProfileFileConfigItem)
+			if err != nil {
+				return fmt.Errorf("getting '--%s' flag: %w", app.ProfileFileConfigItem, err)
+			}
+			if err := startProfile(profile, profileFile); err != nil {
+				return fmt.Errorf("starting profile: %w", err)
+			}
 
-			checkPrereqs()
+			if !quietCommands[cmd.Name()] {
+				checkPrereqs()
+			}
 			return nil
 		},
 		PersistentPostRunE: func(cmd *cobra.Command, args []string) error {
@@ -158,7 +237,7 @@ func RootCmd() (*cobra.Command, error) {
 			if err != nil {
 				return fmt.Errorf("gettng common config: %w", err)
 			}
-			if !commonCfg.DisableVersionCheck {
+			if !commonCfg.DisableVersionCheck && !commonCfg.Offline && !commonCfg.CI && cmd.Name() != "update" && !quietCommands[cmd.Name()] {
 				if err := reportNewerVersion(); err != nil {
 					zap.S().Warnf("problem reporting newer version: %s", err.Error())
 				}
@@ -214,12 +293,66 @@ func addRootCommands(rootCmd *cobra.Command) error {
 		return fmt.Errorf("creating ls command: %w", err)
 	}
 	rootCmd.AddCommand(lsCmd)
+	ctxCmd, err := ctx.Command()
+	if err != nil {
+		return fmt.Errorf("creating ctx command: %w", err)
+	}
+	rootCmd.AddCommand(ctxCmd)
+	openCmd, err := open.Command()
+	if err != nil {
+		return fmt.Errorf("creating open command: %w", err)
+	}
+	rootCmd.AddCommand(openCmd)
+	handoffCmd, err := handoff.Command()
+	if err != nil {
+		return fmt.Errorf("creating handoff command: %w", err)
+	}
+	rootCmd.AddCommand(handoffCmd)
+	kubeconfigCmd, err := kubeconfig.Command()
+	if err != nil {
+		return fmt.Errorf("creating kubeconfig command: %w", err)
+	}
+	rootCmd.AddCommand(kubeconfigCmd)
+	saCmd, err := sakubeconfig.Command()
+	if err != nil {
+		return fmt.Errorf("creating sa-kubeconfig command: %w", err)
+	}
+	rootCmd.AddCommand(saCmd)
+	getTokenCmd, err := gettoken.Command()
+	if err != nil {
+		return fmt.Errorf("creating get-token command: %w", err)
+	}
+	rootCmd.AddCommand(getTokenCmd)
+	credentialProcessCmd, err := credentialprocess.Command()
+	if err != nil {
+		return fmt.Errorf("creating credential-process command: %w", err)
+	}
+	rootCmd.AddCommand(credentialProcessCmd)
+	promptInfoCmd, err := promptinfo.Command()
+	if err != nil {
+		return fmt.Errorf("creating prompt-info command: %w", err)
+	}
+	rootCmd.AddCommand(promptInfoCmd)
+	serveCmd, err := serve.Command()
+	if err != nil {
+		return fmt.Errorf("creating serve command: %w", err)
+	}
+	rootCmd.AddCommand(serveCmd)
 	cfgCmd, err := configcmd.Command()
 	if err != nil {
 		return fmt.Errorf("creating config command: %w", err)
 	}
 	rootCmd.AddCommand(cfgCmd)
-	rootCmd.AddCommand(version.Command())
+	versionCmd, err := version.Command()
+	if err != nil {
+		return fmt.Errorf("creating version command: %w", err)
+	}
+	rootCmd.AddCommand(versionCmd)
+	updateCmd, err := update.Command()
+	if err != nil {
+		return fmt.Errorf("creating update command: %w", err)
+	}
+	rootCmd.AddCommand(updateCmd)
 
 	aliasCmd, err := alias.Command()
 	if err != nil {
@@ -238,9 +371,155 @@ func addRootCommands(rootCmd *cobra.Command) error {
 		return fmt.Errorf("creating history command: %w", err)
 	}
 	rootCmd.AddCommand(historyCmd)
+
+	pluginsCmd, err := plugins.Command()
+	if err != nil {
+		return fmt.Errorf("creating plugins command: %w", err)
+	}
+	rootCmd.AddCommand(pluginsCmd)
+
+	rootCmd.AddCommand(completion.Command())
+	return nil
+}
+
+// setupRecording will record or replay the requests to the providers if --record
+// or --replay is set, and refuse them if --offline is set
+func setupRecording(cmd *cobra.Command) error {
+	offline, err := cmd.Flags().GetBool(app.OfflineConfigItem)
+	if err != nil {
+		return fmt.Errorf("getting '--%s' flag: %w", app.OfflineConfigItem, err)
+	}
+	if offline {
+		zap.S().Info("offline, requests to the providers will be refused")
+		khttp.EnableOffline()
+	}
+
+	recordDir, err := cmd.Flags().GetString(app.RecordConfigItem)
+	if err != nil {
+		return fmt.Errorf("getting '--%s' flag: %w", app.RecordConfigItem, err)
+	}
+	replayDir, err := cmd.Flags().GetString(app.ReplayConfigItem)
+	if err != nil {
+		return fmt.Errorf("getting '--%s' flag: %w", app.ReplayConfigItem, err)
+	}
+	if recordDir != "" && replayDir != "" {
+		return khttp.ErrRecordAndReplay
+	}
+
+	if recordDir != "" {
+		zap.S().Infow("recording http requests", "directory", recordDir)
+		if err := khttp.EnableRecording(recordDir); err != nil {
+			return fmt.Errorf("enabling recording: %w", err)
+		}
+	}
+	if replayDir != "" {
+		zap.S().Infow("replaying http requests", "directory", replayDir)
+		if err := khttp.EnableReplay(replayDir); err != nil {
+			return fmt.Errorf("enabling replay: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// applyPluginPolicy will disable the plugins that are disabled in the app config. This
+// needs to happen before the commands are created so disabled plugins are hidden.
+func applyPluginPolicy() error {
+	cfg, err := readAppConfig()
+	if err != nil {
+		return err
+	}
+	if cfg == nil {
+		zap.S().Debug("app config is invalid, no plugin policy applied")
+		return nil
+	}
+
+	if cfg.Spec.Plugins != nil && len(cfg.Spec.Plugins.Disabled) > 0 {
+		zap.S().Debugw("disabling plugins", "plugins", cfg.Spec.Plugins.Disabled)
+		registry.DisablePlugins(cfg.Spec.Plugins.Disabled)
+	}
+
+	return nil
+}
+
+// applySecretStore will set the secret store chosen in the app config as the store
+// used for credentials. The file store is used if there isn't one configured.
+func applySecretStore() error {
+	cfg, err := readAppConfig()
+	if err != nil {
+		return err
+	}
+	if cfg == nil || cfg.Spec.SecretStore == nil {
+		return nil
+	}
+
+	store, err := secrets.New(cfg.Spec.SecretStore)
+	if err != nil {
+		return fmt.Errorf("creating secret store: %w", err)
+	}
+	zap.S().Debugw("using secret store", "type", store.Type())
+	secrets.SetDefault(store)
+
 	return nil
 }
 
+// mockPluginsEnabled returns true if the hidden --mock-plugins flag is set. The
+// arguments are checked directly as the plugins are registered before the commands
+// are created.
+func mockPluginsEnabled(args []string) bool {
+	for _, arg := range args {
+		if arg == "--"+app.MockPluginsConfigItem || arg == "--"+app.MockPluginsConfigItem+"=true" {
+			return true
+		}
+	}
+
+	return false
+}
+
+func registerMockPlugins() error {
+	zap.S().Debug("registering mock plugins")
+	if err := mockidentity.Register(); err != nil {
+		return err
+	}
+	if err := mockdiscovery.Register(); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// readAppConfig reads the app config from the --config flag location or the default
+// location. It returns nil, with a warning, if the app config is invalid. Unknown keys
+// don't make it invalid.
+func readAppConfig() (*kconnectv1alpha.Configuration, error) {
+	configPath, err := flags.GetFlagValueDirect(os.Args, app.ConfigPathConfigItem, "")
+	if err != nil && !config.IsValidationFailed(err) {
+		return nil, fmt.Errorf("getting config flag: %w", err)
+	}
+	if configPath == "" {
+		configPath = defaults.ConfigPath()
+	}
+
+	appCfg, err := config.NewAppConfigurationWithPath(configPath)
+	if err != nil {
+		return nil, fmt.Errorf("creating app configuration: %w", err)
+	}
+	cfg, err := appCfg.Get()
+	if err != nil {
+		if config.IsValidationFailed(err) {
+			// The command that's run reports the problems, but the settings from the
+			// app config that are applied before it runs are skipped
+			invalidConfigWarning.Do(func() {
+				zap.S().Warnw("app config is invalid, its plugin policy, secret store and notices aren't used", "error", err.Error())
+			})
+			return nil, nil
+		}
+		return nil, fmt.Errorf("getting app configuration: %w", err)
+	}
+
+	return cfg, nil
+}
+
 func ensureAppDirectory() error {
 	appDir := defaults.AppDirectory()
 
@@ -325,15 +604,44 @@ func reportNewerVersion() error {
 
 	if latestSemver.GT(currentSemver) {
 		fmt.Fprintln(os.Stderr, "")
-		fmt.Fprintf(os.Stderr, "\033[33mNew kconnect version available: v%s -> v%s\033[0m\n", currentSemver.String(), latestSemver.String())
-		fmt.Fprintf(os.Stderr, "\033[33mVisit %s for more details\033[0m\n", *cfg.Spec.VersionCheck.LatestReleaseURL)
+		fmt.Fprintln(os.Stderr, utils.Warning(fmt.Sprintf("New kconnect version available: v%s -> v%s", currentSemver.String(), latestSemver.String())))
+		fmt.Fprintln(os.Stderr, utils.Warning(fmt.Sprintf("Visit %s for more details", *cfg.Spec.VersionCheck.LatestReleaseURL)))
+		if utils.IsKubectlPlugin() {
+			fmt.Fprintln(os.Stderr, utils.Warning("Run kubectl krew upgrade connect to upgrade"))
+		} else {
+			fmt.Fprintln(os.Stderr, utils.Warning("Run kconnect update to upgrade"))
+		}
 	}
 
 	return nil
 }
 
+// showNotices will show the notices in the app config that haven't been shown before
+func showNotices() error {
+	cfg, err := readAppConfig()
+	if err != nil {
+		return err
+	}
+	if cfg == nil || len(cfg.Spec.Notices) == 0 {
+		return nil
+	}
+
+	now := time.Now()
+	store := notices.NewStore(defaults.NoticesPath())
+	pending, err := store.Pending(cfg.Spec.Notices, now)
+	if err != nil {
+		return fmt.Errorf("getting pending notices: %w", err)
+	}
+	if len(pending) == 0 {
+		return nil
+	}
+	notices.Render(os.Stderr, pending)
+
+	return store.MarkSeen(cfg.Spec.Notices, pending, now)
+}
+
 func checkPrereqs() {
 	if err := utils.CheckKubectlPrereq(); err != nil {
-		fmt.Fprintf(os.Stderr, "\033[33m%s\033[0m\n", err.Error())
+		fmt.Fprintln(os.Stderr, utils.Warning(err.Error()))
 	}
 }
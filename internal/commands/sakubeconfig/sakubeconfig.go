@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sakubeconfig
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/ci"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDesc = "Create a kubeconfig for a service account with a short-lived token"
+	longDesc  = `
+Create a standalone kubeconfig for a service account, e.g. for automation.
+
+The sa-kubeconfig command first reconnects to a cluster in the connection history,
+in the same way as the to command. Your identity is then used to create a token
+for the service account with the Kubernetes TokenRequest api, so your identity
+needs permission to create serviceaccounts/token in the namespace.
+
+The token expires after --duration. It can also be bound to a secret with
+--bound-secret, deleting the secret invalidates the token.
+
+The kubeconfig has the certificate authority of the cluster embedded and is
+written to stdout unless --output-file is used.
+`
+	examples = `
+  # Create a kubeconfig for the deployer service account in the ci namespace
+  {{.CommandPath}} sa-kubeconfig uat-bu1 --namespace ci --service-account deployer > deployer.kubeconfig
+
+  # Create a kubeconfig with a token that is valid for 10 minutes
+  {{.CommandPath}} sa-kubeconfig uat-bu1 -n ci --service-account deployer --duration 10m --output-file deployer.kubeconfig
+
+  # Create a kubeconfig with a token that is invalidated when the secret is deleted
+  {{.CommandPath}} sa-kubeconfig uat-bu1 -n ci --service-account deployer --bound-secret deployer-binding
+`
+)
+
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	saCmd := &cobra.Command{
+		Use:     "sa-kubeconfig [historyid/alias/-/LAST/LAST~N]",
+		Short:   shortDesc,
+		Long:    longDesc,
+		Example: examples,
+		Args:    cobra.MaximumNArgs(1),
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `sa-kubeconfig` command")
+
+			input := &app.ServiceAccountKubeconfigInput{}
+			if len(args) > 0 {
+				input.AliasOrIDORPosition = args[0]
+			}
+
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into sa-kubeconfig params: %w", err)
+			}
+
+			historyLoader, err := loader.NewFileLoader(input.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", input.Location, err)
+			}
+			// creating a service account kubeconfig should never increase number of history items, so set to arbitrary large number
+			input.MaxItems = 10000
+			store, err := history.NewStore(input.MaxItems, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+
+			opts := []app.Option{app.WithHistoryStore(store)}
+			if input.CI {
+				opts = append(opts, app.WithCIEnvironment(ci.Detect()))
+			}
+			a := app.New(opts...)
+
+			return a.ServiceAccountKubeconfig(cmd.Context(), input)
+		},
+	}
+	utils.FormatCommand(saCmd)
+
+	if err := addConfig(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(saCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return saCmd, nil
+}
+
+func addConfig(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if err := app.AddServiceAccountConfigItems(cs); err != nil {
+		return fmt.Errorf("adding service account config: %w", err)
+	}
+	if _, err := cs.String("password", "", "Password to use"); err != nil {
+		return fmt.Errorf("adding password config: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location items: %w", err)
+	}
+
+	cs.SetHistoryIgnore("password") //nolint
+	cs.SetSensitive("password")     //nolint
+
+	return nil
+}
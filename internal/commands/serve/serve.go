@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serve
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDesc = "Serve a local HTTP API for the connection history."
+	longDesc  = `
+Serve a small local HTTP API so that tools such as internal developer portals,
+launcher extensions and editor plugins can use the connection history without
+running kconnect and parsing its output.
+
+The API is:
+  * GET  /v1/history - lists the history entries, most recently used first
+  * GET  /v1/history/{id} - gets a history entry by its ID or alias
+  * GET  /v1/aliases - lists the aliases and the IDs of their history entries
+  * POST /v1/history/{id}/renew - reconnects to the cluster of the history entry,
+    in the same way as the to command, to renew its credentials. The current
+    context isn't changed.
+
+The API only reads the history apart from renewing credentials. A new token is
+created each time the server starts and written to the token file, which only
+the user can read. Requests must have the token as a bearer token.
+
+Renewing credentials is done non-interactively, so the identity provider must be
+able to authenticate without asking, e.g. using stored or ambient credentials.
+`
+	examples = `
+  # Serve the API on the default address
+  {{.CommandPath}} serve
+
+  # List the history entries
+  curl -H "Authorization: Bearer $(cat ~/.kconnect/serve-token)" http://127.0.0.1:7683/v1/history
+
+  # Renew the credentials of a history entry by its alias
+  curl -X POST -H "Authorization: Bearer $(cat ~/.kconnect/serve-token)" http://127.0.0.1:7683/v1/history/uat-bu1/renew
+`
+)
+
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	serveCmd := &cobra.Command{
+		Use:     "serve",
+		Short:   shortDesc,
+		Long:    longDesc,
+		Example: examples,
+		Args:    cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `serve` command")
+
+			input := &app.ServeInput{}
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into serve params: %w", err)
+			}
+
+			historyLoader, err := loader.NewFileLoader(input.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", input.Location, err)
+			}
+			// renewing should never increase number of history items, so set to arbitrary large number
+			input.MaxItems = 10000
+			store, err := history.NewStore(input.MaxItems, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+
+			a := app.New(app.WithHistoryStore(store), app.WithInteractive(false))
+
+			return a.Serve(cmd.Context(), input)
+		},
+	}
+	utils.FormatCommand(serveCmd)
+
+	if err := addConfig(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(serveCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return serveCmd, nil
+}
+
+func addConfig(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if err := app.AddServeConfigItems(cs); err != nil {
+		return fmt.Errorf("adding serve config: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location items: %w", err)
+	}
+	if err := app.AddKubeconfigConfigItems(cs); err != nil {
+		return fmt.Errorf("adding kubeconfig config items: %w", err)
+	}
+
+	return nil
+}
@@ -23,7 +23,9 @@ import (
 	"github.com/spf13/cobra"
 	"go.uber.org/zap"
 
+	"github.com/fidelity/kconnect/internal/helpers"
 	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/ci"
 	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/flags"
 	"github.com/fidelity/kconnect/pkg/history"
@@ -47,6 +49,11 @@ the connection history entry's ID or alias.
 The to command also accepts - or LAST as proxy references to the most recent
 connection history entry, or LAST~N for the Nth previous entry.
 
+The endpoint and certificate authority of the cluster are recorded in the history
+entry. If they have changed when reconnecting, e.g. because the cluster was
+rebuilt, the kubeconfig and history entry are updated. When the cluster details
+come from the discovery cache and are stale the cluster is discovered again.
+
 Although kconnect does not save the user's password in the connection history,
 the user can avoid having to enter their password interactively by setting the
 KCONNECT_PASSWORD environment variable or the --password command-line flag.
@@ -70,6 +77,9 @@ Otherwise kconnect will promot the user to enter their password.
   # Reconnect to cluster used before current one
   {{.CommandPath}} to LAST~1
 
+  # Ask before re-authenticating if the credentials of the entry have expired
+  {{.CommandPath}} to uat-bu1 --expired confirm
+
   # Reconnect based on an alias supplying a password
   {{.CommandPath}} to uat-bu1 --password supersecret
 
@@ -87,6 +97,12 @@ func Command() (*cobra.Command, error) {
 		Long:    longDesc,
 		Example: examples,
 		Args:    cobra.MaximumNArgs(1),
+		ValidArgsFunction: func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+			if len(args) > 0 {
+				return nil, cobra.ShellCompDirectiveNoFileComp
+			}
+			return helpers.CompleteAliasesAndIDs(cmd, args, toComplete)
+		},
 		PreRunE: func(cmd *cobra.Command, args []string) error {
 			flags.BindFlags(cmd)
 			flags.PopulateConfigFromCommand(cmd, cfg)
@@ -118,7 +134,11 @@ func Command() (*cobra.Command, error) {
 				return fmt.Errorf("creating history store: %w", err)
 			}
 
-			a := app.New(app.WithHistoryStore(store))
+			opts := []app.Option{app.WithHistoryStore(store)}
+			if input.CI {
+				opts = append(opts, app.WithCIEnvironment(ci.Detect()))
+			}
+			a := app.New(opts...)
 
 			return a.ConnectTo(cmd.Context(), input)
 		},
@@ -152,6 +172,12 @@ func addConfig(cs config.ConfigurationSet) error {
 	if err := app.AddKubeconfigConfigItems(cs); err != nil {
 		return fmt.Errorf("adding kubeconfig config items: %w", err)
 	}
+	if err := app.AddExplainConfigItems(cs); err != nil {
+		return fmt.Errorf("adding explain config items: %w", err)
+	}
+	if err := app.AddExpiredConfigItems(cs); err != nil {
+		return fmt.Errorf("adding expired config items: %w", err)
+	}
 
 	cs.SetHistoryIgnore("password") //nolint
 	cs.SetSensitive("password")     //nolint
@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"fmt"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/internal/update"
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+var (
+	shortDesc = "Update kconnect to the latest release"
+	longDesc  = `
+Update kconnect to the latest release on GitHub.
+
+The checksums of the release are signed. The signature is verified with the
+release signing key built into kconnect, and then the checksum of the downloaded
+archive is verified, before the kconnect binary is replaced.
+
+If kconnect was installed with Homebrew, Scoop or krew the binary isn't replaced,
+instead the command to update it with the package manager is printed.
+`
+	examples = `
+  # Update kconnect to the latest release
+  {{.CommandPath}} update
+
+  # Check if there is a newer release without updating
+  {{.CommandPath}} update --check
+`
+)
+
+// Command creates the update cobra command
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
+	updateCmd := &cobra.Command{
+		Use:     "update",
+		Short:   shortDesc,
+		Long:    longDesc,
+		Example: examples,
+		Args:    cobra.NoArgs,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			return nil
+		},
+		RunE: func(cmd *cobra.Command, args []string) error {
+			zap.S().Debug("running `update` command")
+
+			opts := update.Options{}
+			if err := config.Unmarshall(cfg, &opts); err != nil {
+				return fmt.Errorf("unmarshalling config into update options: %w", err)
+			}
+
+			return update.Run(cmd.Context(), opts)
+		},
+	}
+	utils.FormatCommand(updateCmd)
+
+	if err := addConfig(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(updateCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return updateCmd, nil
+}
+
+func addConfig(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if _, err := cs.Bool("check", false, "Only check if there is a newer release"); err != nil {
+		return fmt.Errorf("adding check config: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package commands
+
+import (
+	"context"
+	"time"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/telemetry"
+)
+
+// ReportUsage will report the anonymized usage of the command that was run if a usage
+// metrics endpoint is set in the app config. Nothing is reported otherwise. A failed
+// report is only logged as it mustn't fail the command.
+func ReportUsage(ctx context.Context, cmd *cobra.Command, duration time.Duration, cmdErr error) {
+	if cmd == nil {
+		return
+	}
+
+	appCfg, err := readAppConfig()
+	if err != nil || appCfg == nil {
+		return
+	}
+	if appCfg.Spec.UsageMetrics == nil || appCfg.Spec.UsageMetrics.Endpoint == "" {
+		return
+	}
+
+	errorClass := ""
+	if cmdErr != nil {
+		errorClass = string(ErrorCode(cmdErr))
+	}
+	event := telemetry.NewUsageEvent(cmd.Name(), duration, errorClass)
+	if cmd.HasParent() && cmd.Parent().Name() == "use" {
+		// The use commands are named after the discovery provider, e.g. use eks
+		event.Command = cmd.Parent().Name()
+		event.DiscoveryProvider = cmd.Name()
+	}
+	if idpFlag := cmd.Flags().Lookup("idp-protocol"); idpFlag != nil {
+		event.IdentityProvider = idpFlag.Value.String()
+	}
+
+	if err := telemetry.ReportUsage(ctx, appCfg.Spec.UsageMetrics.Endpoint, event); err != nil {
+		zap.S().Debugw("failed reporting usage metrics", "error", err.Error())
+	}
+}
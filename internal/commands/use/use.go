@@ -27,6 +27,7 @@ import (
 
 	"github.com/fidelity/kconnect/internal/helpers"
 	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/ci"
 	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/defaults"
 	"github.com/fidelity/kconnect/pkg/flags"
@@ -46,6 +47,7 @@ var (
 const (
 	shortDesc         = "Connect to a Kubernetes cluster provider and cluster."
 	shortDescProvider = "Connect to the %s cluster provider and choose a cluster."
+	shortDescList     = "List the clusters discovered by the %s cluster provider."
 	longDescHead      = `
 Connect to a managed Kubernetes cluster provider via the configured identity
 provider, prompting the user to enter or choose connection settings appropriate
@@ -69,8 +71,17 @@ token.
 `
 	longDescFoot = `
 The use command requires a target provider name as its first parameter. If no
-value is supplied for --idp-protocol the first supported protocol for the
-specified cluster provider.
+value is supplied for --idp-protocol then the protocol last used with the
+specified cluster provider is selected. If the provider hasn't been used before
+the protocol is detected from the environment (e.g. AWS_PROFILE or AZURE_CLIENT_ID
+being set), otherwise the first supported protocol for the provider is used.
+`
+	longDescResume = `
+If a use command fails after authenticating, e.g. the discovery is throttled or a
+prompt is aborted, then kconnect use --resume will continue from the last phase
+that succeeded without authenticating again. This is supported by the identity
+providers that can involve MFA or a browser login (saml, aws-iam, openshift-oauth,
+rancher-ad and external plugins).
 `
 	eksDescNote = `
 * Note: kconnect use eks requires aws-iam-authenticator.
@@ -83,6 +94,32 @@ specified cluster provider.
 
   # Connect to an EKS cluster and create an alias for its connection history entry.
   {{.CommandPath}} use eks --alias mycluster
+
+  # Choose several EKS clusters and create a context for each of them.
+  {{.CommandPath}} use eks --multi-select
+
+  # Continue the last use command that failed without authenticating again.
+  {{.CommandPath}} use --resume
+
+  # Connect to an EKS cluster in a GitHub Actions job, the following steps can use kubectl.
+  {{.CommandPath}} use eks --ci --cluster-id arn:aws:eks:eu-west-2:123456789012:cluster/dev
+`
+	longDescList = `
+Connect to %s via the configured identify provider and list the discovered
+clusters instead of connecting to one of them.
+
+With --output json or --output yaml the clusters are printed using a stable schema
+(kind DiscoveredClusters) that includes the endpoint, certificate authority data,
+account or subscription, region and tags of each cluster, so that infrastructure
+pipelines can use kconnect discovery as a data source. Fields will not be removed
+or renamed within the apiVersion.
+`
+	listExample = `
+  # List the discovered clusters as a table.
+  {{.CommandPath}} ls %[1]s
+
+  # List the discovered clusters as JSON, e.g. for a terraform external data source.
+  {{.CommandPath}} ls %[1]s --output json
 `
 	usageExampleFoot = `
   # Reconnect to a cluster by its connection history entry alias.
@@ -95,24 +132,46 @@ specified cluster provider.
 
 // Command creates the use command
 func Command() (*cobra.Command, error) {
-	longDesc := longDescHead + longDescBody + longDescFoot + eksDescNote
+	longDesc := longDescHead + longDescBody + longDescFoot + longDescResume + eksDescNote
+	cfg := config.NewConfigurationSet()
 	useCmd := &cobra.Command{
 		Use:     "use",
 		Short:   shortDesc,
 		Long:    longDesc,
 		Example: usageExample + usageExampleFoot,
-		Run: func(c *cobra.Command, _ []string) {
-			if err := c.Help(); err != nil {
-				zap.S().Debugw("ignoring cobra error", "error", err.Error())
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			return nil
+		},
+		RunE: func(c *cobra.Command, _ []string) error {
+			input := &resumeInput{}
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into use params: %w", err)
+			}
+			if !input.Resume {
+				if err := c.Help(); err != nil {
+					zap.S().Debugw("ignoring cobra error", "error", err.Error())
+				}
+				return nil
 			}
+
+			return resume(c, input)
 		},
 	}
 
 	utils.FormatCommand(useCmd)
 
+	if err := addResumeConfig(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+	if err := flags.CreateCommandFlags(useCmd, cfg); err != nil {
+		return nil, err
+	}
+
 	// Add the provider subcommands
 	for _, registration := range registry.ListDiscoveryPluginRegistrations() {
-		providerCmd, err := createProviderCmd(registration)
+		providerCmd, err := createProviderCmd(registration, false)
 		if err != nil {
 			return nil, fmt.Errorf("creating provider command for %s: %w", registration.Name, err)
 		}
@@ -122,22 +181,90 @@ func Command() (*cobra.Command, error) {
 	return useCmd, nil
 }
 
-func createProviderCmd(registration *registry.DiscoveryPluginRegistration) (*cobra.Command, error) {
+// resumeInput is the input to the use command when there's no provider
+type resumeInput struct {
+	app.UseResumeInput
+	Resume bool `json:"resume"`
+}
+
+// resume continues the last use command that failed after authenticating
+func resume(cmd *cobra.Command, input *resumeInput) error {
+	zap.S().Debug("resuming `use` command")
+
+	if err := ensureConfigFolder(defaults.AppDirectory()); err != nil {
+		return fmt.Errorf("ensuring app directory exists: %w", err)
+	}
+
+	historyLoader, err := loader.NewFileLoader(input.Location)
+	if err != nil {
+		return fmt.Errorf("getting history loader with path %s: %w", input.Location, err)
+	}
+	store, err := history.NewStore(input.MaxItems, historyLoader)
+	if err != nil {
+		return fmt.Errorf("creating history store: %w", err)
+	}
+
+	a := app.New(app.WithHistoryStore(store), app.WithInteractive(!input.NoInput))
+
+	return a.UseResume(cmd.Context(), &input.UseResumeInput)
+}
+
+func addResumeConfig(cs config.ConfigurationSet) error {
+	if err := app.AddCommonConfigItems(cs); err != nil {
+		return fmt.Errorf("adding common config: %w", err)
+	}
+	if _, err := cs.Bool("resume", false, "Continue the last use command that failed after authenticating, without authenticating again"); err != nil {
+		return fmt.Errorf("adding resume config: %w", err)
+	}
+	if err := app.AddHistoryConfigItems(cs); err != nil {
+		return fmt.Errorf("adding history config items: %w", err)
+	}
+
+	return nil
+}
+
+// ListCommands creates a command for each provider that discovers the clusters in the
+// same way as use, but lists them instead of connecting to one
+func ListCommands() ([]*cobra.Command, error) {
+	cmds := []*cobra.Command{}
+	for _, registration := range registry.ListDiscoveryPluginRegistrations() {
+		providerCmd, err := createProviderCmd(registration, true)
+		if err != nil {
+			return nil, fmt.Errorf("creating list command for %s: %w", registration.Name, err)
+		}
+		cmds = append(cmds, providerCmd)
+	}
+
+	return cmds, nil
+}
+
+func createProviderCmd(registration *registry.DiscoveryPluginRegistration, listClusters bool) (*cobra.Command, error) {
+	if registry.IsPluginDisabled(registration.Name) {
+		return createDisabledProviderCmd(registration), nil
+	}
+
 	params := &app.UseInput{
 		IgnoreAlias:       false,
+		ListClusters:      listClusters,
 		ConfigSet:         config.NewConfigurationSet(),
 		DiscoveryProvider: registration.Name,
 	}
 
+	providerShortDesc := fmt.Sprintf(shortDescProvider, registration.Name)
 	providerLongDesc := fmt.Sprintf(longDescProviderHead, registration.Name) + longDescBody
 	if registration.Name == "eks" {
 		providerLongDesc += eksDescNote
 	}
 	providerUsageExample := registration.UsageExample + usageExampleFoot
+	if listClusters {
+		providerShortDesc = fmt.Sprintf(shortDescList, registration.Name)
+		providerLongDesc = fmt.Sprintf(longDescList, registration.Name)
+		providerUsageExample = fmt.Sprintf(listExample, registration.Name)
+	}
 
 	providerCmd := &cobra.Command{
 		Use:     registration.Name,
-		Short:   fmt.Sprintf(shortDescProvider, registration.Name),
+		Short:   providerShortDesc,
 		Long:    providerLongDesc,
 		Example: providerUsageExample,
 		AdditionalSetupE: func(cmd *cobra.Command, args []string) error {
@@ -183,19 +310,26 @@ func createProviderCmd(registration *registry.DiscoveryPluginRegistration) (*cob
 				return fmt.Errorf("creating history store: %w", err)
 			}
 
-			a := app.New(app.WithHistoryStore(store), app.WithInteractive(!params.NoInput))
+			opts := []app.Option{app.WithHistoryStore(store), app.WithInteractive(!params.NoInput)}
+			if params.CI {
+				opts = append(opts, app.WithCIEnvironment(ci.Detect()))
+			}
+			a := app.New(opts...)
 
 			return a.Use(cmd.Context(), params)
 		},
 	}
 
-	if err := addConfig(params.ConfigSet, registration); err != nil {
+	if err := addConfig(params.ConfigSet, registration, listClusters); err != nil {
 		return nil, fmt.Errorf("add command config: %w", err)
 	}
 
 	if err := flags.CreateCommandFlags(providerCmd, params.ConfigSet); err != nil {
 		return nil, err
 	}
+	if err := providerCmd.RegisterFlagCompletionFunc("idp-protocol", helpers.CompleteIdpProtocols(registration)); err != nil {
+		return nil, fmt.Errorf("registering idp-protocol completion: %w", err)
+	}
 
 	providerCmd.SetUsageFunc(providerUsage(registration.Name))
 
@@ -203,7 +337,21 @@ func createProviderCmd(registration *registry.DiscoveryPluginRegistration) (*cob
 	return providerCmd, nil
 }
 
-func addConfig(cs config.ConfigurationSet, registration *registry.DiscoveryPluginRegistration) error {
+// createDisabledProviderCmd creates a hidden command for a provider thats been disabled
+// so that trying to use it gives a clear error
+func createDisabledProviderCmd(registration *registry.DiscoveryPluginRegistration) *cobra.Command {
+	return &cobra.Command{
+		Use:                registration.Name,
+		Short:              fmt.Sprintf(shortDescProvider, registration.Name),
+		Hidden:             true,
+		DisableFlagParsing: true,
+		RunE: func(cmd *cobra.Command, args []string) error {
+			return fmt.Errorf("using provider %s: %w", registration.Name, registry.ErrPluginDisabled)
+		},
+	}
+}
+
+func addConfig(cs config.ConfigurationSet, registration *registry.DiscoveryPluginRegistration, listClusters bool) error {
 	if err := app.AddCommonConfigItems(cs); err != nil {
 		return fmt.Errorf("adding common config %s: %w", registration.Name, err)
 	}
@@ -235,6 +383,11 @@ func addConfig(cs config.ConfigurationSet, registration *registry.DiscoveryPlugi
 	if err := app.AddCommonUseConfigItems(cs); err != nil {
 		return fmt.Errorf("adding common use config items: %w", err)
 	}
+	if listClusters {
+		if err := app.AddListClustersConfigItems(cs); err != nil {
+			return fmt.Errorf("adding list clusters config items: %w", err)
+		}
+	}
 
 	cs.SetHistoryIgnore("set-current") //nolint
 
@@ -250,6 +403,9 @@ func setupIdpProtocol(cmd *cobra.Command, args []string, params *app.UseInput) e
 	if idpProtocol == "" {
 		return ErrMissingIdpProtocol
 	}
+	if registry.IsPluginDisabled(idpProtocol) {
+		return fmt.Errorf("using idp-protocol %s: %w", idpProtocol, registry.ErrPluginDisabled)
+	}
 
 	params.IdpProtocol = idpProtocol
 	if !hasFlagValue {
@@ -291,19 +447,50 @@ func getIdpProtocol(args []string, params *app.UseInput) (string, bool, error) {
 	if err != nil {
 		return "", false, fmt.Errorf("getting idp-protocol from config: %w", err)
 	}
-	// Default to the first supported provider if empty
+	// Select an identity provider based on the history and environment if empty
 	if idProtocol == "" {
-		discoReg, err := registry.GetDiscoveryProviderRegistration(params.DiscoveryProvider)
+		selection, err := app.SelectIdentityProvider(params.DiscoveryProvider, selectionHistoryStore(args))
 		if err != nil {
 			return "", false, err
 		}
-		idProtocol = discoReg.SupportedIdentityProviders[0]
-		zap.S().Debugw("no idp-protocol, using default for provider", "idp-protocol", idProtocol)
+		if selection != nil {
+			idProtocol = selection.Name
+			zap.S().Infow("no idp-protocol specified, selected identity provider", "idp-protocol", idProtocol, "reason", selection.Reason)
+			if len(selection.Alternatives) > 0 {
+				zap.S().Infof("also detected %s, use --idp-protocol to choose a different identity provider", strings.Join(selection.Alternatives, ", "))
+			}
+		}
 	}
 
 	return idProtocol, false, nil
 }
 
+// selectionHistoryStore returns the history store used when selecting an identity provider. As
+// this runs before the flags are parsed the location is read directly from the args.
+func selectionHistoryStore(args []string) history.Store {
+	location, err := flags.GetFlagValueDirect(args, "history-location", "")
+	if err != nil || location == "" {
+		location = defaults.HistoryPath()
+	}
+	// Don't create a history file just to select an identity provider
+	if _, err := os.Stat(location); err != nil {
+		return nil
+	}
+
+	historyLoader, err := loader.NewFileLoader(location)
+	if err != nil {
+		zap.S().Debugw("ignoring history when selecting identity provider", "error", err.Error())
+		return nil
+	}
+	store, err := history.NewStore(defaults.MaxHistoryItems, historyLoader)
+	if err != nil {
+		zap.S().Debugw("ignoring history when selecting identity provider", "error", err.Error())
+		return nil
+	}
+
+	return store
+}
+
 func ensureConfigFolder(path string) error {
 	info, err := os.Stat(path)
 	if err != nil && !os.IsNotExist(err) {
@@ -346,6 +533,9 @@ func providerUsage(providerName string) func(cmd *cobra.Command) error {
 		}
 
 		for _, idProviderName := range clusterProviderReg.SupportedIdentityProviders {
+			if registry.IsPluginDisabled(idProviderName) {
+				continue
+			}
 			idProviderReg, err := registry.GetIdentityProviderRegistration(idProviderName)
 			if err != nil {
 				return err
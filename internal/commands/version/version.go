@@ -20,24 +20,110 @@ import (
 	"fmt"
 
 	"github.com/spf13/cobra"
+	"go.uber.org/zap"
 	"gopkg.in/yaml.v2"
 
 	"github.com/fidelity/kconnect/internal/version"
+	"github.com/fidelity/kconnect/pkg/app"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/flags"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
 	"github.com/fidelity/kconnect/pkg/utils"
 )
 
+var (
+	longDesc = `
+Display the version and build information of kconnect.
+
+With --check the helper binaries (kubectl, aws-iam-authenticator, aws, kubelogin
+and az) and the Kubernetes versions of the 5 most recently used clusters are also
+checked for combinations that are known to break, for example an exec plugin
+api version that kubectl no longer supports or a kubectl version that is more
+than 1 minor version from the cluster. The cluster versions are requested without
+credentials, so no identity provider is used.
+`
+	examples = `
+  # Display the version
+  {{.CommandPath}} version
+
+  # Check the compatibility of the helper binaries and recently used clusters
+  {{.CommandPath}} version --check
+`
+)
+
+type versionInput struct {
+	app.CompatibilityInput
+
+	Check bool `json:"check"`
+}
+
 // Command creates the version cobra command
-func Command() *cobra.Command {
+func Command() (*cobra.Command, error) {
+	cfg := config.NewConfigurationSet()
+
 	versionCmd := &cobra.Command{
-		Use:   "version",
-		Short: "Display version & build information",
+		Use:     "version",
+		Short:   "Display version & build information",
+		Long:    longDesc,
+		Example: examples,
+		PreRunE: func(cmd *cobra.Command, args []string) error {
+			flags.BindFlags(cmd)
+			flags.PopulateConfigFromCommand(cmd, cfg)
+			return nil
+		},
 		RunE: func(cmd *cobra.Command, args []string) error {
-			return doVersion(cmd)
+			if err := doVersion(cmd); err != nil {
+				return err
+			}
+
+			input := &versionInput{}
+			if err := config.Unmarshall(cfg, input); err != nil {
+				return fmt.Errorf("unmarshalling config into version params: %w", err)
+			}
+			if !input.Check {
+				return nil
+			}
+			zap.S().Debug("checking compatibility")
+
+			historyLoader, err := loader.NewFileLoader(input.Location)
+			if err != nil {
+				return fmt.Errorf("getting history loader with path %s: %w", input.Location, err)
+			}
+			store, err := history.NewStore(10000, historyLoader)
+			if err != nil {
+				return fmt.Errorf("creating history store: %w", err)
+			}
+			a := app.New(app.WithHistoryStore(store))
+
+			return a.CheckCompatibility(cmd.Context(), &input.CompatibilityInput)
 		},
 	}
 	utils.FormatCommand(versionCmd)
 
-	return versionCmd
+	if err := addConfig(cfg); err != nil {
+		return nil, fmt.Errorf("add command config: %w", err)
+	}
+
+	if err := flags.CreateCommandFlags(versionCmd, cfg); err != nil {
+		return nil, err
+	}
+
+	return versionCmd, nil
+}
+
+func addConfig(cs config.ConfigurationSet) error {
+	if _, err := cs.Bool("check", false, "Check the compatibility of the helper binaries and the recently used clusters"); err != nil {
+		return fmt.Errorf("adding check config: %w", err)
+	}
+	if err := app.AddHistoryLocationItems(cs); err != nil {
+		return fmt.Errorf("adding history location items: %w", err)
+	}
+	if err := app.AddKubeconfigConfigItems(cs); err != nil {
+		return fmt.Errorf("adding kubeconfig config items: %w", err)
+	}
+
+	return nil
 }
 
 func doVersion(_ *cobra.Command) error {
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package helpers
+
+import (
+	"os"
+	"strings"
+
+	"github.com/spf13/cobra"
+	"go.uber.org/zap"
+
+	historyv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/defaults"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+// CompletionFunc is a function that returns the shell completions for a flag or argument
+type CompletionFunc func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective)
+
+// CompleteAliases completes the aliases in the connection history
+func CompleteAliases(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	entries := completionHistory(cmd)
+
+	return history.AliasCompletions(entries, toComplete, args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteHistoryIDs completes the ids of the connection history entries. The ids
+// already given as arguments aren't completed again.
+func CompleteHistoryIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	entries := completionHistory(cmd)
+
+	return history.IDCompletions(entries, toComplete, args), cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteAliasesAndIDs completes the aliases and ids of the connection history entries
+func CompleteAliasesAndIDs(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	entries := completionHistory(cmd)
+	completions := history.AliasCompletions(entries, toComplete, args)
+	completions = append(completions, history.IDCompletions(entries, toComplete, args)...)
+
+	return completions, cobra.ShellCompDirectiveNoFileComp
+}
+
+// CompleteIdpProtocols returns a function that completes the identity providers that
+// are supported by the discovery provider and haven't been disabled
+func CompleteIdpProtocols(registration *registry.DiscoveryPluginRegistration) CompletionFunc {
+	return func(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+		completions := []string{}
+		for _, idp := range registration.SupportedIdentityProviders {
+			if registry.IsPluginDisabled(idp) || !strings.HasPrefix(idp, toComplete) {
+				continue
+			}
+			completions = append(completions, idp)
+		}
+
+		return completions, cobra.ShellCompDirectiveNoFileComp
+	}
+}
+
+// completionHistory loads the connection history from the location set by the
+// history-location flag. Completion never fails, so nothing is returned if the
+// history can't be read, and an empty history isn't created when there isn't one.
+func completionHistory(cmd *cobra.Command) []historyv1alpha.HistoryEntry {
+	location := ""
+	if flag := cmd.Flags().Lookup("history-location"); flag != nil {
+		location = flag.Value.String()
+	}
+	location = defaults.ExpandPath(location)
+	if location == "" {
+		location = defaults.HistoryPath()
+	}
+	if _, err := os.Stat(location); err != nil {
+		return nil
+	}
+
+	historyLoader, err := loader.NewFileLoader(location)
+	if err != nil {
+		zap.S().Debugw("getting history loader for completion", "error", err)
+		return nil
+	}
+	list, err := historyLoader.Load()
+	if err != nil {
+		zap.S().Debugw("loading history for completion", "error", err)
+		return nil
+	}
+
+	return list.Items
+}
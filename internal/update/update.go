@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"context"
+	"crypto/ed25519"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	"github.com/blang/semver"
+	"go.uber.org/zap"
+
+	appver "github.com/fidelity/kconnect/internal/version"
+	"github.com/fidelity/kconnect/pkg/prereqs"
+)
+
+const (
+	checksumsAsset = "kconnect_checksums.txt"
+	signatureAsset = "kconnect_checksums.txt.sig"
+)
+
+// signingKey is the base64 encoded ed25519 public key that the checksums of
+// the releases are signed with. It's set when the release is built.
+var signingKey string
+
+var (
+	ErrNoSigningKey     = errors.New("this build of kconnect has no release signing key, it can't update itself")
+	ErrAssetNotFound    = errors.New("release asset not found")
+	ErrInvalidSignature = errors.New("signature of the release checksums is invalid")
+)
+
+// Options are the options for updating kconnect
+type Options struct {
+	// CheckOnly reports if there is a newer version without updating
+	CheckOnly bool `json:"check"`
+}
+
+// Run updates the running kconnect binary to the latest release. If kconnect was
+// installed with a package manager the command to update it is printed instead.
+func Run(ctx context.Context, opts Options) error {
+	exePath, err := executablePath()
+	if err != nil {
+		return err
+	}
+
+	latest, err := appver.GetLatestRelease()
+	if err != nil {
+		return err
+	}
+	latestSemver, err := semver.Parse(strings.TrimPrefix(*latest.Version, "v"))
+	if err != nil {
+		return fmt.Errorf("parsing latest release version %s: %w", *latest.Version, err)
+	}
+
+	current := appver.Get().Version
+	if current == "" {
+		// Running a local build so set the version number
+		current = "0.0.0"
+	}
+	currentSemver, err := semver.Parse(current)
+	if err != nil {
+		return fmt.Errorf("parsing current version %s: %w", current, err)
+	}
+
+	if !latestSemver.GT(currentSemver) {
+		fmt.Fprintf(os.Stderr, "kconnect v%s is the latest version\n", currentSemver)
+		return nil
+	}
+	fmt.Fprintf(os.Stderr, "New kconnect version available: v%s -> v%s\n", currentSemver, latestSemver)
+
+	if updateCmd := packageManagerCommand(exePath); updateCmd != "" {
+		fmt.Fprintf(os.Stderr, "kconnect was installed with a package manager, update it with:\n  %s\n", updateCmd)
+		return nil
+	}
+	if opts.CheckOnly {
+		return nil
+	}
+
+	binary, err := downloadRelease(ctx, latest, runtime.GOOS, runtime.GOARCH)
+	if err != nil {
+		return err
+	}
+	if err := replaceExecutable(exePath, binary); err != nil {
+		return err
+	}
+	fmt.Fprintf(os.Stderr, "Updated %s to v%s\n", exePath, latestSemver)
+
+	return nil
+}
+
+// downloadRelease downloads the kconnect binary for the platform from the release. The
+// signature of the checksums and the checksum of the archive are verified.
+func downloadRelease(ctx context.Context, release *appver.Release, goos, goarch string) ([]byte, error) {
+	archiveName := archiveName(goos, goarch)
+	zap.S().Debugw("downloading release", "version", *release.Version, "archive", archiveName)
+
+	assets := map[string][]byte{}
+	for _, name := range []string{checksumsAsset, signatureAsset, archiveName} {
+		url, ok := release.Assets[name]
+		if !ok {
+			return nil, fmt.Errorf("getting %s from release %s: %w", name, *release.Version, ErrAssetNotFound)
+		}
+		data, err := prereqs.Fetch(ctx, url)
+		if err != nil {
+			return nil, err
+		}
+		assets[name] = data
+	}
+
+	if err := verifySignature(assets[checksumsAsset], assets[signatureAsset], signingKey); err != nil {
+		return nil, err
+	}
+	if err := prereqs.VerifyChecksum(assets[archiveName], assets[checksumsAsset], archiveName); err != nil {
+		return nil, fmt.Errorf("verifying %s: %w", archiveName, err)
+	}
+
+	if goos == "windows" {
+		return prereqs.ExtractFromZip(assets[archiveName], "kconnect.exe")
+	}
+
+	return prereqs.ExtractFromTarGz(assets[archiveName], "kconnect")
+}
+
+// archiveName returns the name of the release archive for the platform, this
+// matches the archives in .goreleaser.yml
+func archiveName(goos, goarch string) string {
+	switch goos {
+	case "windows":
+		return fmt.Sprintf("kconnect_%s_%s.zip", goos, goarch)
+	case "darwin":
+		return fmt.Sprintf("kconnect_macos_%s.tar.gz", goarch)
+	default:
+		return fmt.Sprintf("kconnect_%s_%s.tar.gz", goos, goarch)
+	}
+}
+
+// verifySignature verifies the base64 encoded ed25519 signature of the data
+func verifySignature(data, signature []byte, publicKey string) error {
+	if publicKey == "" {
+		return ErrNoSigningKey
+	}
+	key, err := base64.StdEncoding.DecodeString(publicKey)
+	if err != nil || len(key) != ed25519.PublicKeySize {
+		return fmt.Errorf("decoding release signing key: %w", ErrNoSigningKey)
+	}
+	sig, err := base64.StdEncoding.DecodeString(strings.TrimSpace(string(signature)))
+	if err != nil {
+		return fmt.Errorf("decoding signature: %w", ErrInvalidSignature)
+	}
+
+	if !ed25519.Verify(ed25519.PublicKey(key), data, sig) {
+		return ErrInvalidSignature
+	}
+
+	return nil
+}
+
+// packageManagerCommand returns the command to update kconnect if it was installed
+// with a package manager, based on the path of the executable
+func packageManagerCommand(exePath string) string {
+	exePath = strings.ReplaceAll(exePath, `\`, "/")
+	switch {
+	case strings.Contains(exePath, "/Cellar/") || strings.Contains(exePath, "/homebrew/") || strings.Contains(exePath, "/linuxbrew/"):
+		return "brew upgrade kconnect"
+	case strings.Contains(strings.ToLower(exePath), "/scoop/"):
+		return "scoop update kconnect"
+	case strings.Contains(exePath, "/.krew/"):
+		return "kubectl krew upgrade connect"
+	default:
+		return ""
+	}
+}
+
+// executablePath returns the path of the running kconnect binary with any
+// symlinks resolved, e.g. the Homebrew symlink in /usr/local/bin
+func executablePath() (string, error) {
+	exePath, err := os.Executable()
+	if err != nil {
+		return "", fmt.Errorf("getting executable path: %w", err)
+	}
+	resolved, err := filepath.EvalSymlinks(exePath)
+	if err != nil {
+		return "", fmt.Errorf("resolving executable path %s: %w", exePath, err)
+	}
+
+	return resolved, nil
+}
+
+// replaceExecutable replaces the binary at the path. The new binary is written
+// next to it and renamed over it, so a failed update leaves the old binary. On
+// Windows the running binary can't be replaced, only renamed, so it's moved aside.
+func replaceExecutable(exePath string, binary []byte) error {
+	newPath := exePath + ".new"
+	if err := ioutil.WriteFile(newPath, binary, 0755); err != nil { //nolint: gosec
+		return fmt.Errorf("writing %s: %w", newPath, err)
+	}
+
+	if runtime.GOOS == "windows" {
+		oldPath := exePath + ".old"
+		os.Remove(oldPath) //nolint: errcheck
+		if err := os.Rename(exePath, oldPath); err != nil {
+			os.Remove(newPath) //nolint: errcheck
+			return fmt.Errorf("moving aside %s: %w", exePath, err)
+		}
+	}
+
+	if err := os.Rename(newPath, exePath); err != nil {
+		os.Remove(newPath) //nolint: errcheck
+		return fmt.Errorf("replacing %s: %w", exePath, err)
+	}
+
+	return nil
+}
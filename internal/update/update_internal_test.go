@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package update
+
+import (
+	"archive/tar"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	appver "github.com/fidelity/kconnect/internal/version"
+)
+
+func TestPackageManagerCommand(t *testing.T) {
+	testCases := []struct {
+		name    string
+		exePath string
+		expect  string
+	}{
+		{
+			name:    "homebrew on macos",
+			exePath: "/usr/local/Cellar/kconnect/0.5.0/bin/kconnect",
+			expect:  "brew upgrade kconnect",
+		},
+		{
+			name:    "homebrew on linux",
+			exePath: "/home/linuxbrew/.linuxbrew/bin/kconnect",
+			expect:  "brew upgrade kconnect",
+		},
+		{
+			name:    "scoop",
+			exePath: `C:\Users\dev\scoop\apps\kconnect\current\kconnect.exe`,
+			expect:  "scoop update kconnect",
+		},
+		{
+			name:    "krew",
+			exePath: "/home/dev/.krew/store/connect/v0.5.0/kconnect",
+			expect:  "kubectl krew upgrade connect",
+		},
+		{
+			name:    "downloaded",
+			exePath: "/home/dev/bin/kconnect",
+			expect:  "",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(packageManagerCommand(tc.exePath)).To(Equal(tc.expect)) //nolint:scopelint
+		})
+	}
+}
+
+func TestArchiveName(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(archiveName("linux", "amd64")).To(Equal("kconnect_linux_amd64.tar.gz"))
+	g.Expect(archiveName("darwin", "arm64")).To(Equal("kconnect_macos_arm64.tar.gz"))
+	g.Expect(archiveName("windows", "amd64")).To(Equal("kconnect_windows_amd64.zip"))
+}
+
+func TestVerifySignature(t *testing.T) {
+	g := NewWithT(t)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	encodedKey := base64.StdEncoding.EncodeToString(publicKey)
+
+	data := []byte("checksums")
+	signature := []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, data)) + "\n")
+
+	g.Expect(verifySignature(data, signature, encodedKey)).To(Succeed())
+	g.Expect(errors.Is(verifySignature([]byte("tampered"), signature, encodedKey), ErrInvalidSignature)).To(BeTrue())
+	g.Expect(errors.Is(verifySignature(data, []byte("not base64!"), encodedKey), ErrInvalidSignature)).To(BeTrue())
+	g.Expect(errors.Is(verifySignature(data, signature, ""), ErrNoSigningKey)).To(BeTrue())
+}
+
+func TestDownloadRelease(t *testing.T) {
+	g := NewWithT(t)
+
+	publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+	g.Expect(err).NotTo(HaveOccurred())
+	signingKey = base64.StdEncoding.EncodeToString(publicKey)
+	defer func() { signingKey = "" }()
+
+	archive := tarGz(t, "kconnect", []byte("new kconnect"))
+	sum := sha256.Sum256(archive)
+	checksums := []byte(fmt.Sprintf("%s  kconnect_linux_amd64.tar.gz\n", hex.EncodeToString(sum[:])))
+	files := map[string][]byte{
+		"kconnect_linux_amd64.tar.gz": archive,
+		checksumsAsset:                checksums,
+		signatureAsset:                []byte(base64.StdEncoding.EncodeToString(ed25519.Sign(privateKey, checksums))),
+	}
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write(files[r.URL.Path[1:]]) //nolint: errcheck
+	}))
+	defer server.Close()
+
+	version := "0.6.0"
+	release := &appver.Release{Version: &version, Assets: map[string]string{}}
+	for name := range files {
+		release.Assets[name] = server.URL + "/" + name
+	}
+
+	binary, err := downloadRelease(context.Background(), release, "linux", "amd64")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(binary)).To(Equal("new kconnect"))
+
+	files["kconnect_linux_amd64.tar.gz"] = tarGz(t, "kconnect", []byte("tampered kconnect"))
+	_, err = downloadRelease(context.Background(), release, "linux", "amd64")
+	g.Expect(err).To(HaveOccurred())
+
+	_, err = downloadRelease(context.Background(), release, "linux", "arm64")
+	g.Expect(errors.Is(err, ErrAssetNotFound)).To(BeTrue())
+}
+
+func tarGz(t *testing.T, name string, content []byte) []byte {
+	buf := &bytes.Buffer{}
+	gzipWriter := gzip.NewWriter(buf)
+	tarWriter := tar.NewWriter(gzipWriter)
+	if err := tarWriter.WriteHeader(&tar.Header{Name: name, Mode: 0755, Size: int64(len(content))}); err != nil {
+		t.Fatal(err)
+	}
+	if _, err := tarWriter.Write(content); err != nil {
+		t.Fatal(err)
+	}
+	tarWriter.Close()
+	gzipWriter.Close()
+
+	return buf.Bytes()
+}
@@ -29,6 +29,8 @@ type Release struct {
 	Version *string
 	Date    *time.Time
 	URL     *string
+	// Assets are the download urls of the release assets by their name
+	Assets map[string]string
 }
 
 // GetLatestRelease gets the latest release detsils from GitHub
@@ -40,9 +42,15 @@ func GetLatestRelease() (*Release, error) {
 		return nil, fmt.Errorf("getting latest release from GitHub: %w", err)
 	}
 
+	assets := map[string]string{}
+	for _, asset := range release.Assets {
+		assets[asset.GetName()] = asset.GetBrowserDownloadURL()
+	}
+
 	return &Release{
 		Version: release.TagName,
 		Date:    &release.PublishedAt.Time,
 		URL:     release.HTMLURL,
+		Assets:  assets,
 	}, nil
 }
@@ -20,11 +20,14 @@ import (
 	"context"
 	"fmt"
 	"os"
+	"strings"
+	"text/template"
 
 	"go.uber.org/zap"
 	v1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 
 	apiv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/defaults"
 	"github.com/fidelity/kconnect/pkg/history"
 	"github.com/fidelity/kconnect/pkg/printer"
 )
@@ -51,6 +54,17 @@ type AliasRemoveInput struct {
 	All bool `json:"all"`
 }
 
+// AliasPinInput defines the inputs for AliasPin
+type AliasPinInput struct {
+	CommonConfig
+	HistoryLocationConfig
+	HistoryIdentifierConfig
+	Kubeconfig  string `json:"kubeconfig,omitempty"`
+	ContextName string `json:"context-name,omitempty"`
+	Group       string `json:"group,omitempty"`
+	Clear       bool   `json:"clear"`
+}
+
 // AliasList implements the alias listing functionality
 func (a *App) AliasList(ctx context.Context, input *AliasListInput) error {
 	zap.S().Infow("listing aliases")
@@ -164,6 +178,62 @@ func (a *App) AliasRemove(ctx context.Context, input *AliasRemoveInput) error {
 	return nil
 }
 
+// AliasPin will pin the kubeconfig to write to, and the name of the context, to a history
+// entry. They're used instead of the kubeconfig and context name of the connection when
+// reconnecting to the entry. The entry can also be added to an alias group.
+func (a *App) AliasPin(ctx context.Context, input *AliasPinInput) error {
+	zap.S().Infow("pinning kubeconfig to history entry", "id", input.ID, "alias", input.Alias, "kubeconfig", input.Kubeconfig, "context-name", input.ContextName, "group", input.Group, "clear", input.Clear)
+
+	if input.Alias == "" && input.ID == "" {
+		return ErrHistoryIDRequired
+	}
+	if input.Alias != "" && input.ID != "" {
+		return ErrAliasAndIDNotAllowed
+	}
+	if input.Clear && (input.Kubeconfig != "" || input.ContextName != "" || input.Group != "") {
+		return ErrPinAndClearNotAllowed
+	}
+	if !input.Clear && input.Kubeconfig == "" && input.ContextName == "" && input.Group == "" {
+		return ErrPinRequired
+	}
+
+	found, err := a.getAliasEntries(input.ID, input.Alias, false)
+	if err != nil {
+		return fmt.Errorf("getting history entry: %w", err)
+	}
+	if len(found) == 0 {
+		return history.ErrEntryNotFound
+	}
+	entry := found[0].DeepCopy()
+
+	if input.Clear {
+		entry.Spec.Kubeconfig = ""
+		entry.Spec.ContextName = ""
+		entry.Spec.Group = ""
+	}
+	if input.Kubeconfig != "" {
+		entry.Spec.Kubeconfig = defaults.ExpandPath(input.Kubeconfig)
+	}
+	if input.ContextName != "" {
+		if _, err := template.New("context-name").Parse(input.ContextName); err != nil {
+			return fmt.Errorf("parsing context name template: %w", err)
+		}
+		entry.Spec.ContextName = input.ContextName
+	}
+	if input.Group != "" {
+		entry.Spec.Group = strings.TrimPrefix(input.Group, GroupPrefix)
+	}
+	entry.Status.LastModified = v1.Now()
+
+	zap.S().Debugw("updating history entry with pinned kubeconfig", "id", entry.ObjectMeta.Name)
+	if err := a.historyStore.Update(entry); err != nil {
+		return fmt.Errorf("updating history entry: %w", err)
+	}
+	zap.S().Info("kubeconfig pinned to history entry")
+
+	return nil
+}
+
 func (a *App) getAliasEntries(id string, alias string, all bool) ([]*apiv1alpha.HistoryEntry, error) {
 	var found []*apiv1alpha.HistoryEntry
 
@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/prompt"
+)
+
+// answerPromptNames are the names of the prompts that can be answered that
+// aren't for a configuration item
+var answerPromptNames = []string{"alias", "cluster", "item", "use-alias"}
+
+// useAnswersFile will load the answers file and use it to answer prompts instead
+// of asking the user. The answers are validated against the configuration items.
+func (a *App) useAnswersFile(path string, cs config.ConfigurationSet) error {
+	answers, err := config.LoadAnswers(path, cs, answerPromptNames)
+	if err != nil {
+		return fmt.Errorf("loading answers: %w", err)
+	}
+	a.logger.Debugw("using answers file for prompts", "path", path, "answers", len(answers))
+
+	prompt.SetBackend(prompt.NewAnswersBackend(answers))
+
+	return nil
+}
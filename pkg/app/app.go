@@ -18,21 +18,26 @@ package app
 
 import (
 	"fmt"
+	"strings"
 
 	"github.com/spf13/pflag"
 	"go.uber.org/zap"
 
+	"github.com/fidelity/kconnect/pkg/ci"
 	"github.com/fidelity/kconnect/pkg/defaults"
 	"github.com/fidelity/kconnect/pkg/history"
 	khttp "github.com/fidelity/kconnect/pkg/http"
 	"github.com/fidelity/kconnect/pkg/prompt"
 	"github.com/fidelity/kconnect/pkg/provider"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
 )
 
 const (
 	// Name is the name of the application
 	Name = "kconnect"
+	// LoggerName is the name of the app logger, the provider loggers are named after it
+	LoggerName = "app"
 )
 
 // App represents the kconnect application and contains the
@@ -42,10 +47,14 @@ type App struct {
 	configDirectory string
 	sensitiveFlags  map[string]*pflag.Flag
 
-	selectCluster SelectClusterFunc
-	itemSelector  provider.SelectItemFunc
+	selectCluster       SelectClusterFunc
+	customSelect        bool
+	itemSelector        provider.SelectItemFunc
+	discoveryMiddleware []discovery.Middleware
 
 	interactive bool
+	ciEnv       ci.Environment
+	otlpEnabled bool
 	httpClient  khttp.Client
 	logger      *zap.SugaredLogger
 }
@@ -62,7 +71,7 @@ func New(opts ...Option) *App {
 		configDirectory: defaults.AppDirectory(),
 		selectCluster:   DefaultSelectCluster,
 		sensitiveFlags:  make(map[string]*pflag.Flag),
-		logger:          zap.S().With("app", Name),
+		logger:          zap.S().Named(LoggerName).With("app", Name),
 		httpClient:      khttp.NewHTTPClient(),
 		interactive:     true,
 		itemSelector:    provider.DefaultItemSelection,
@@ -80,6 +89,7 @@ func New(opts ...Option) *App {
 func WithSelectClusterFn(fn SelectClusterFunc) Option {
 	return func(a *App) {
 		a.selectCluster = fn
+		a.customSelect = true
 	}
 }
 
@@ -91,7 +101,7 @@ func WithHistoryStore(store history.Store) Option {
 
 func WithLogger(logger *zap.SugaredLogger) Option {
 	return func(a *App) {
-		a.logger = logger.With("app", Name)
+		a.logger = logger.Named(LoggerName).With("app", Name)
 	}
 }
 
@@ -110,6 +120,24 @@ func WithItemSelectorFunc(itemSelector provider.SelectItemFunc) Option {
 func WithSelectClusterFunc(selectFunc SelectClusterFunc) Option {
 	return func(a *App) {
 		a.selectCluster = selectFunc
+		a.customSelect = true
+	}
+}
+
+// WithDiscoveryMiddleware is an option to wrap the discovery providers with
+// additional middleware. This is in addition to the default logging and timing.
+func WithDiscoveryMiddleware(middleware ...discovery.Middleware) Option {
+	return func(a *App) {
+		a.discoveryMiddleware = append(a.discoveryMiddleware, middleware...)
+	}
+}
+
+// WithCIEnvironment is an option to run as a step of a job in the CI system, the
+// secrets are masked and the kubeconfig is written to the job
+func WithCIEnvironment(env ci.Environment) Option {
+	return func(a *App) {
+		a.ciEnv = env
+		a.interactive = false
 	}
 }
 
@@ -121,26 +149,115 @@ func WithInteractive(interactive bool) Option {
 
 // DefaultSelectCluster is the default cluster selection function. If there is only
 // 1 cluster then it automatically selects it. If there are more than 1 cluster then
-// a selection is displayed and the user must choose one
+// a selection is displayed and the user must choose one. The annotations added by
+// any enrichers are shown as extra columns.
 func DefaultSelectCluster(discoverOutput *discovery.DiscoverOutput) (*discovery.Cluster, error) {
-	clusterNameToID := make(map[string]string)
-	options := []string{}
-	for _, cluster := range discoverOutput.Clusters {
-		clusterNameToID[cluster.Name] = cluster.ID
-		options = append(options, cluster.Name)
-	}
+	options, message := clusterOptions(discoverOutput.DiscoveryProvider, discoverOutput.Clusters, "Select a cluster")
 
-	clusterName, err := prompt.Choose("cluster", "Select a cluster", true, prompt.OptionsFromStringSlice(options))
+	clusterID, err := prompt.Choose("cluster", message, true, prompt.OptionsFromMap(options))
 	if err != nil {
 		return nil, fmt.Errorf("choosing cluster: %w", err)
 	}
-
-	clusterID := clusterNameToID[clusterName]
 	zap.S().Debugw("selected cluster", "id", clusterID)
 
 	return discoverOutput.Clusters[clusterID], nil
 }
 
+// selectClusters asks the user to choose any number of the discovered clusters
+func selectClusters(discoverOutput *discovery.DiscoverOutput) ([]*discovery.Cluster, error) {
+	options, message := clusterOptions(discoverOutput.DiscoveryProvider, discoverOutput.Clusters, "Select the clusters (space to toggle)")
+
+	clusterIDs, err := prompt.ChooseMany("clusters", message, true, prompt.OptionsFromMap(options))
+	if err != nil {
+		return nil, fmt.Errorf("choosing clusters: %w", err)
+	}
+	zap.S().Debugw("selected clusters", "ids", clusterIDs)
+
+	clusters := make([]*discovery.Cluster, 0, len(clusterIDs))
+	for _, clusterID := range clusterIDs {
+		clusters = append(clusters, discoverOutput.Clusters[clusterID])
+	}
+
+	return clusters, nil
+}
+
+// clusterOptions returns the options to choose from for the clusters and the message
+// to display with them
+func clusterOptions(providerName string, clusters map[string]*discovery.Cluster, message string) (map[string]string, string) {
+	columns := clusterColumns(providerName, clusters)
+	options := make(map[string]string)
+	for _, cluster := range clusters {
+		options[clusterOption(cluster, columns, clusters)] = cluster.ID
+	}
+
+	if len(columns) > 0 {
+		message = fmt.Sprintf("%s (name, %s)", message, strings.Join(columns, ", "))
+	}
+
+	return options, message
+}
+
+// clusterColumns returns the columns to show for the clusters, which are the identity that
+// discovered them, the columns of the discovery provider and the annotations that the
+// registered enrichers want shown. Columns without a value for any cluster are left out.
+func clusterColumns(providerName string, clusters map[string]*discovery.Cluster) []string {
+	columns := []string{AnnotationIdentity}
+	if reg, err := registry.GetDiscoveryProviderRegistration(providerName); err == nil {
+		columns = append(columns, reg.Columns...)
+	}
+	columns = append(columns, enricherColumns()...)
+
+	withValues := []string{}
+	for _, column := range columns {
+		for _, cluster := range clusters {
+			if cluster.ColumnValue(column) != "" {
+				withValues = append(withValues, column)
+				break
+			}
+		}
+	}
+
+	return withValues
+}
+
+// enricherColumns returns the annotations that the registered enrichers want shown
+func enricherColumns() []string {
+	columns := []string{}
+	for _, enricher := range registry.ListEnrichers() {
+		columns = append(columns, enricher.Columns()...)
+	}
+
+	return columns
+}
+
+// clusterOption creates the option to display for a cluster. The name and the value
+// of each column are padded so that they line up with the other clusters.
+func clusterOption(cluster *discovery.Cluster, columns []string, clusters map[string]*discovery.Cluster) string {
+	if len(columns) == 0 {
+		return cluster.Name
+	}
+
+	nameWidth := 0
+	columnWidths := make([]int, len(columns))
+	for _, other := range clusters {
+		if len(other.Name) > nameWidth {
+			nameWidth = len(other.Name)
+		}
+		for i, column := range columns {
+			if len(other.ColumnValue(column)) > columnWidths[i] {
+				columnWidths[i] = len(other.ColumnValue(column))
+			}
+		}
+	}
+
+	cells := []string{fmt.Sprintf("%-*s", nameWidth, cluster.Name)}
+	for i, column := range columns {
+		cells = append(cells, fmt.Sprintf("%-*s", columnWidths[i], cluster.ColumnValue(column)))
+	}
+
+	return strings.TrimRight(strings.Join(cells, "  "), " ")
+}
+
 func (a *App) SetNonInteractive() {
 	a.interactive = false
 }
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+// pluginRegistration returns the registration for a discovery or identity plugin. If the
+// plugin isn't registered then an empty registration with no capabilities is returned.
+func pluginRegistration(name string) *registry.PluginRegistration {
+	if discoReg, err := registry.GetDiscoveryProviderRegistration(name); err == nil {
+		return &discoReg.PluginRegistration
+	}
+	if idReg, err := registry.GetIdentityProviderRegistration(name); err == nil {
+		return &idReg.PluginRegistration
+	}
+
+	return &registry.PluginRegistration{Name: name}
+}
+
+// checkInteractiveRequired will return an error if running non-interactively and
+// one of the plugins can only be used interactively
+func (a *App) checkInteractiveRequired(pluginNames ...string) error {
+	if a.interactive {
+		return nil
+	}
+
+	for _, name := range pluginNames {
+		if pluginRegistration(name).HasCapability(registry.CapabilityInteractiveRequired) {
+			return fmt.Errorf("using plugin %s: %w", name, ErrInteractiveRequired)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,107 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"strings"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/logging"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+)
+
+// sensitiveEnvNames are parts of the names of exec environment variables in the
+// kubeconfig that hold credentials, e.g. AWS_SECRET_ACCESS_KEY
+var sensitiveEnvNames = []string{"SECRET", "TOKEN", "PASSWORD", "KEY"}
+
+// maskSensitiveConfig will redact the values of the sensitive config items in the logs,
+// and mask them in the CI job log
+func (a *App) maskSensitiveConfig(cs config.ConfigurationSet) {
+	for _, item := range cs.GetAll() {
+		if !item.Sensitive || !item.HasValue() {
+			continue
+		}
+		value, ok := item.Value.(string)
+		if !ok {
+			continue
+		}
+		logging.Redact(value)
+		if a.ciEnv != nil {
+			a.ciEnv.MaskSecret(value)
+		}
+	}
+}
+
+// maskKubeconfigCredentials will mask the credentials of the users in the kubeconfig
+// in the CI job log
+func (a *App) maskKubeconfigCredentials(kubeConfig *api.Config) {
+	if a.ciEnv == nil {
+		return
+	}
+	for _, authInfo := range kubeConfig.AuthInfos {
+		for _, secret := range []string{authInfo.Token, authInfo.Password, string(authInfo.ClientKeyData)} {
+			if secret != "" {
+				a.ciEnv.MaskSecret(secret)
+			}
+		}
+		if authInfo.Exec == nil {
+			continue
+		}
+		for _, env := range authInfo.Exec.Env {
+			if isSensitiveEnv(env.Name) && env.Value != "" {
+				a.ciEnv.MaskSecret(env.Value)
+			}
+		}
+	}
+}
+
+func isSensitiveEnv(name string) bool {
+	for _, sensitive := range sensitiveEnvNames {
+		if strings.Contains(strings.ToUpper(name), sensitive) {
+			return true
+		}
+	}
+	return false
+}
+
+// reportToCI will make the kubeconfig available to the following steps of the CI job
+// and add the connection to the step summary
+func (a *App) reportToCI(input *UseInput, cluster *discovery.Cluster, contextName string) {
+	if a.ciEnv == nil {
+		return
+	}
+
+	if err := a.ciEnv.ExportVariable("KUBECONFIG", input.Kubeconfig); err != nil {
+		a.logger.Warnw("failed to export KUBECONFIG for the following steps", "ci", a.ciEnv.Name(), "error", err.Error())
+	}
+
+	summary := strings.Builder{}
+	summary.WriteString("### kconnect\n\n")
+	summary.WriteString("| | |\n|---|---|\n")
+	fmt.Fprintf(&summary, "| Cluster | %s |\n", cluster.Name)
+	fmt.Fprintf(&summary, "| Cluster ID | `%s` |\n", cluster.ID)
+	fmt.Fprintf(&summary, "| Provider | %s |\n", input.DiscoveryProvider)
+	fmt.Fprintf(&summary, "| Identity provider | %s |\n", input.IdentityProvider)
+	fmt.Fprintf(&summary, "| Context | `%s` |\n", contextName)
+	fmt.Fprintf(&summary, "| Kubeconfig | `%s` |\n\n", input.Kubeconfig)
+	if err := a.ciEnv.AddSummary(summary.String()); err != nil {
+		a.logger.Warnw("failed to add the step summary", "ci", a.ciEnv.Name(), "error", err.Error())
+	}
+}
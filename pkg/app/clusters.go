@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+
+	"github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/printer"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+// listClusters will print the clusters discovered by the provider instead of connecting
+// to one of them
+func (a *App) listClusters(ctx context.Context, identityProvider identity.Provider, clusterProvider discovery.Provider, input *UseInput) error {
+	clusterIdentity, err := a.authenticate(ctx, identityProvider, clusterProvider, input.ConfigSet)
+	if err != nil {
+		return err
+	}
+
+	a.logger.Infow("discovering clusters", "provider", input.DiscoveryProvider)
+	discoverOutput, err := clusterProvider.Discover(ctx, &discovery.DiscoverInput{
+		ConfigSet: input.ConfigSet,
+		Identity:  clusterIdentity,
+	})
+	if err != nil {
+		return fmt.Errorf("discovering clusters using %s: %w", clusterProvider.Name(), err)
+	}
+	discovered := toDiscoveredClusters(clusterProvider.Name(), discoverOutput.Clusters)
+
+	output := printer.OutputPrinterTable
+	if input.Output != nil {
+		output = *input.Output
+	}
+	objPrinter, err := printer.New(output)
+	if err != nil {
+		return fmt.Errorf("getting printer for output %s: %w", output, err)
+	}
+	if output == printer.OutputPrinterTable {
+		return objPrinter.Print(discovered.ToTable(clusterColumns(clusterProvider.Name(), discoverOutput.Clusters)...), os.Stdout)
+	}
+
+	return objPrinter.Print(discovered, os.Stdout)
+}
+
+func toDiscoveredClusters(providerName string, clusters map[string]*discovery.Cluster) *v1alpha1.DiscoveredClusters {
+	discovered := v1alpha1.NewDiscoveredClusters(providerName)
+	for _, cluster := range clusters {
+		discoveredCluster := v1alpha1.DiscoveredCluster{
+			ID:          cluster.ID,
+			Name:        cluster.Name,
+			Account:     cluster.Account,
+			Region:      cluster.Region,
+			Tags:        cluster.Tags,
+			Annotations: cluster.Annotations,
+		}
+		if cluster.ControlPlaneEndpoint != nil {
+			discoveredCluster.Endpoint = *cluster.ControlPlaneEndpoint
+		}
+		if cluster.CertificateAuthorityData != nil {
+			discoveredCluster.CertificateAuthorityData = *cluster.CertificateAuthorityData
+		}
+		discovered.Clusters = append(discovered.Clusters, discoveredCluster)
+	}
+
+	sort.Slice(discovered.Clusters, func(i, j int) bool {
+		if discovered.Clusters[i].Name != discovered.Clusters[j].Name {
+			return discovered.Clusters[i].Name < discovered.Clusters[j].Name
+		}
+		return discovered.Clusters[i].ID < discovered.Clusters[j].ID
+	})
+
+	return discovered
+}
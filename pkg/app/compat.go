@@ -0,0 +1,238 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"golang.org/x/mod/semver"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/k8s/connectivity"
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+	"github.com/fidelity/kconnect/pkg/prereqs"
+	"github.com/fidelity/kconnect/pkg/printer"
+)
+
+const (
+	compatOK      = "ok"
+	compatWarning = "warning"
+	compatError   = "error"
+	compatMissing = "not installed"
+	compatUnknown = "unknown"
+
+	// compatClusters is how many of the most recently used clusters are checked
+	compatClusters = 5
+	// compatTimeout is the time limit for getting the version of a cluster
+	compatTimeout = 5 * time.Second
+
+	execAPIv1alpha1 = "client.authentication.k8s.io/v1alpha1"
+	// kubectlNoV1alpha1 is the kubectl version that removed the v1alpha1 exec credential api
+	kubectlNoV1alpha1 = "v1.24.0"
+	// maxKubectlSkew is the number of minor versions that kubectl is supported
+	// to be older or newer than the cluster
+	maxKubectlSkew = 1
+)
+
+type CompatibilityInput struct {
+	HistoryLocationConfig
+	KubernetesConfig
+}
+
+// compatibilityCheck is the result of checking a helper binary or cluster
+type compatibilityCheck struct {
+	component string
+	version   string
+	status    string
+	message   string
+}
+
+// CheckCompatibility checks the helper binaries that kconnect and the generated kubeconfigs
+// use, and the Kubernetes versions of the recently used clusters, for combinations that
+// are known to break.
+func (a *App) CheckCompatibility(ctx context.Context, params *CompatibilityInput) error {
+	zap.S().Debug("checking compatibility")
+
+	checks := []*compatibilityCheck{}
+	kubectlVersion := ""
+	for _, binary := range []*prereqs.BinaryPreReq{prereqs.Kubectl(), prereqs.AWSIAMAuthenticator(), prereqs.AWSCLI(), prereqs.Kubelogin(), prereqs.AzureCLI()} {
+		check := checkBinary(binary)
+		if binary.Name() == "kubectl" {
+			kubectlVersion = check.version
+		}
+		checks = append(checks, check)
+	}
+
+	cfg, err := kubeconfig.Read(params.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	contexts, err := a.kconnectContexts(cfg)
+	if err != nil {
+		return fmt.Errorf("getting kconnect contexts: %w", err)
+	}
+	if len(contexts) > compatClusters {
+		contexts = contexts[:compatClusters]
+	}
+	for _, kconnectCtx := range contexts {
+		checks = append(checks, checkCluster(ctx, cfg, kconnectCtx.name, kubectlVersion))
+	}
+
+	if err := printCompatibility(checks); err != nil {
+		return err
+	}
+	for _, check := range checks {
+		if check.status == compatError {
+			return ErrIncompatible
+		}
+	}
+
+	return nil
+}
+
+func checkBinary(binary *prereqs.BinaryPreReq) *compatibilityCheck {
+	check := &compatibilityCheck{component: binary.Name(), status: compatOK}
+
+	if _, err := binary.Path(); err != nil {
+		check.status = compatMissing
+		check.message = binary.Help()
+		return check
+	}
+	version, err := binary.Version()
+	if err != nil {
+		check.status = compatUnknown
+		check.message = err.Error()
+		return check
+	}
+	check.version = version
+
+	if binary.MinVersion() != "" && semver.Compare(version, binary.MinVersion()) < 0 {
+		check.status = compatError
+		check.message = fmt.Sprintf("the minimum version is %s", binary.MinVersion())
+	}
+
+	return check
+}
+
+// checkCluster checks the exec credential plugin of the context and the version
+// of its cluster against the version of kubectl
+func checkCluster(ctx context.Context, cfg *api.Config, contextName, kubectlVersion string) *compatibilityCheck {
+	check := &compatibilityCheck{component: fmt.Sprintf("cluster %s", contextName), status: compatOK}
+
+	if kubeContext, ok := cfg.Contexts[contextName]; ok {
+		if authInfo, ok := cfg.AuthInfos[kubeContext.AuthInfo]; ok && authInfo.Exec != nil {
+			if status, message := checkExecPlugin(authInfo.Exec, kubectlVersion); status != compatOK {
+				check.status = status
+				check.message = message
+			}
+		}
+	}
+
+	version, err := connectivity.ServerVersion(ctx, cfg, contextName, compatTimeout)
+	if err != nil || version == "" {
+		if check.status == compatOK {
+			check.status = compatUnknown
+			check.message = "unable to get the cluster version"
+		}
+		return check
+	}
+	check.version = version
+
+	if check.status == compatOK && kubectlVersion != "" {
+		if skew := minorVersionSkew(kubectlVersion, version); skew > maxKubectlSkew {
+			check.status = compatWarning
+			check.message = fmt.Sprintf("kubectl %s is %d minor versions from the cluster, only 1 is supported", kubectlVersion, skew)
+		}
+	}
+
+	return check
+}
+
+// checkExecPlugin checks that the exec credential plugin can be found and that its
+// api version is supported by kubectl
+func checkExecPlugin(execConfig *api.ExecConfig, kubectlVersion string) (string, string) {
+	command := prereqs.ResolveCommand(execConfig.Command)
+	if _, err := exec.LookPath(command); err != nil {
+		return compatError, fmt.Sprintf("the exec plugin %s isn't installed", execConfig.Command)
+	}
+
+	if execConfig.APIVersion == execAPIv1alpha1 && kubectlVersion != "" && semver.Compare(kubectlVersion, kubectlNoV1alpha1) >= 0 {
+		return compatError, fmt.Sprintf("kubectl %s doesn't support the exec plugin api %s, it was removed in kubectl %s", kubectlVersion, execAPIv1alpha1, kubectlNoV1alpha1)
+	}
+
+	return compatOK, ""
+}
+
+// minorVersionSkew returns how many minor versions apart 2 Kubernetes versions are
+func minorVersionSkew(version1, version2 string) int {
+	minor := func(version string) int {
+		parts := strings.Split(semver.MajorMinor(version), ".")
+		if len(parts) != 2 { //nolint: gomnd
+			return -1
+		}
+		m, err := strconv.Atoi(parts[1])
+		if err != nil {
+			return -1
+		}
+		return m
+	}
+
+	minor1, minor2 := minor(version1), minor(version2)
+	if minor1 < 0 || minor2 < 0 || semver.Major(version1) != semver.Major(version2) {
+		return 0
+	}
+	if minor1 > minor2 {
+		return minor1 - minor2
+	}
+
+	return minor2 - minor1
+}
+
+func printCompatibility(checks []*compatibilityCheck) error {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: metav1.SchemeGroupVersion.String(),
+			Kind:       "Table",
+		},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Component", Type: "string"},
+			{Name: "Version", Type: "string"},
+			{Name: "Status", Type: "string"},
+			{Name: "Message", Type: "string"},
+		},
+	}
+	for _, check := range checks {
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{check.component, check.version, check.status, check.message},
+		})
+	}
+
+	objPrinter, err := printer.New(printer.OutputPrinterTable)
+	if err != nil {
+		return fmt.Errorf("getting table printer: %w", err)
+	}
+
+	return objPrinter.Print(table, os.Stdout)
+}
@@ -18,9 +18,12 @@ package app
 
 import (
 	"fmt"
+	"time"
 
 	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/defaults"
+	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/logging"
 	"github.com/fidelity/kconnect/pkg/printer"
 )
 
@@ -29,8 +32,52 @@ const (
 	NonInteractiveConfigItem = "non-interactive"
 	NoVersionCheckConfigItem = "no-version-check"
 	ConfigPathConfigItem     = "config"
+	ExplainConfigConfigItem  = "explain-config"
+	ClusterFilterConfigItem  = "cluster-filter"
+	InstallPreReqsConfigItem = "install-prereqs"
+	AnswersFileConfigItem    = "answers-file"
+	IdentityProxyConfigItem  = "identity-proxy"
+	DiscoveryProxyConfigItem = "discovery-proxy"
+	NoProxyConfigItem        = "no-proxy"
+	ProxyUsernameConfigItem  = "proxy-username"
+	ProxyPasswordConfigItem  = "proxy-password"
+	IDPCACertConfigItem      = "idp-ca-cert"
+	ClusterCACertConfigItem  = "cluster-ca-cert"
+	IDPClientCertConfigItem  = "idp-client-cert"
+	IDPClientKeyConfigItem   = "idp-client-key"
+	SSHJumpHostConfigItem    = "ssh-jump-host"
+	SSHIdentityConfigItem    = "ssh-identity-file"
+	SSHTunnelPortConfigItem  = "ssh-tunnel-port"
+	VerifyConnConfigItem     = "verify-connection"
+	OTLPEndpointConfigItem   = "otlp-endpoint"
+	OTLPHeadersConfigItem    = "otlp-headers"
+	HTTPMaxRetriesConfigItem = "http-max-retries"
+	HTTPBackoffConfigItem    = "http-retry-backoff"
+	HTTPTimeoutConfigItem    = "http-timeout"
+	TraceHTTPConfigItem      = "trace-http"
+	LogFormatConfigItem      = "log-format"
+	LogFileConfigItem        = "log-file"
+	LogLevelConfigItem       = "log-level"
+	ProfileConfigItem        = "profile"
+	ProfileFileConfigItem    = "profile-file"
+	MockPluginsConfigItem    = "mock-plugins"
+	RecordConfigItem         = "record"
+	ReplayConfigItem         = "replay"
+	OfflineConfigItem        = "offline"
+	DiscoveryCacheConfigItem = "discovery-cache-ttl"
+	RefreshConfigItem        = "refresh"
+	IdentitiesConfigItem     = "identities"
+	MultiSelectConfigItem    = "multi-select"
+	WSLInteropConfigItem     = "wsl-interop"
+	CIConfigItem             = "ci"
+	OverridePolicyConfigItem = "override-policy"
+	AuthAttemptsConfigItem   = "auth-attempts"
+	NotifyExpiryConfigItem   = "notify-expiry"
 )
 
+// DefaultAuthAttempts is the default number of times a rejected credential can be entered
+const DefaultAuthAttempts = 3
+
 type HistoryLocationConfig struct {
 	Location string `json:"history-location"`
 }
@@ -78,7 +125,7 @@ type KubernetesConfig struct {
 
 // AddKubeconfigConfigItems will add the kubeconfig related config items
 func AddKubeconfigConfigItems(cs config.ConfigurationSet) error {
-	if _, err := cs.String("kubeconfig", "", "Location of the kubeconfig to use. (default \"$HOME/.kube/config\")"); err != nil {
+	if _, err := cs.String("kubeconfig", "", "Location of the kubeconfig to use. (default is the first file in $KUBECONFIG or \"$HOME/.kube/config\")"); err != nil {
 		return fmt.Errorf("adding kubeconfig config: %w", err)
 	}
 	if err := cs.SetShort("kubeconfig", "k"); err != nil {
@@ -92,6 +139,17 @@ type CommonConfig struct {
 	Verbosity           int    `json:"verbosity"`
 	NoInput             bool   `json:"no-input"`
 	DisableVersionCheck bool   `json:"no-version-check"`
+	TraceHTTP           bool   `json:"trace-http"`
+	LogFormat           string `json:"log-format"`
+	LogFile             string `json:"log-file"`
+	LogLevel            string `json:"log-level"`
+	Profile             bool   `json:"profile"`
+	ProfileFile         string `json:"profile-file"`
+	MockPlugins         bool   `json:"mock-plugins"`
+	Record              string `json:"record"`
+	Replay              string `json:"replay"`
+	Offline             bool   `json:"offline"`
+	CI                  bool   `json:"ci"`
 }
 
 func AddCommonConfigItems(cs config.ConfigurationSet) error {
@@ -110,26 +168,266 @@ func AddCommonConfigItems(cs config.ConfigurationSet) error {
 	if _, err := cs.Bool(NoVersionCheckConfigItem, false, "If set to true kconnect will not check for a newer version"); err != nil {
 		return fmt.Errorf("adding non-version-check config: %w", err)
 	}
+	if _, err := cs.Bool(TraceHTTPConfigItem, false, "Log the method, url, status, latency and correlation ids of each request to the providers. Bodies and credentials aren't logged"); err != nil {
+		return fmt.Errorf("adding trace-http config: %w", err)
+	}
+	if _, err := cs.String(LogFormatConfigItem, logging.FormatConsole, "The format of the logs, console or json"); err != nil {
+		return fmt.Errorf("adding log-format config: %w", err)
+	}
+	if _, err := cs.String(LogFileConfigItem, "", "A file to also write the logs to, the logs are appended to the file"); err != nil {
+		return fmt.Errorf("adding log-file config: %w", err)
+	}
+	if _, err := cs.String(LogLevelConfigItem, "", "Comma separated log levels of components that override the verbosity, e.g. app=debug,http=warn"); err != nil {
+		return fmt.Errorf("adding log-level config: %w", err)
+	}
+	if _, err := cs.Bool(ProfileConfigItem, false, "Print how long each phase of the command took when it finishes, e.g. to find a slow provider"); err != nil {
+		return fmt.Errorf("adding profile config: %w", err)
+	}
+	if _, err := cs.String(ProfileFileConfigItem, "", "A file to write a pprof CPU profile of the command to"); err != nil {
+		return fmt.Errorf("adding profile-file config: %w", err)
+	}
+	if _, err := cs.String(RecordConfigItem, "", "A directory to save the requests to the providers, and their responses, to. Credentials are redacted so the recording can be shared to troubleshoot a problem"); err != nil {
+		return fmt.Errorf("adding record config: %w", err)
+	}
+	if _, err := cs.String(ReplayConfigItem, "", "A directory of requests saved with --record, the saved responses are used instead of sending the requests to the providers"); err != nil {
+		return fmt.Errorf("adding replay config: %w", err)
+	}
+	if _, err := cs.Bool(OfflineConfigItem, false, "Only use the cached discovered clusters and the connection history, requests to the providers are refused. The cached clusters are used even if they have expired"); err != nil {
+		return fmt.Errorf("adding offline config: %w", err)
+	}
+	if _, err := cs.Bool(CIConfigItem, false, "Run as a step of a CI job. Disables prompts and colors, writes the kubeconfig to the job's temp directory and masks secrets in the job log (GitHub Actions and Azure Pipelines)"); err != nil {
+		return fmt.Errorf("adding ci config: %w", err)
+	}
+	if _, err := cs.Bool(MockPluginsConfigItem, false, "Add the mock identity and discovery plugins, e.g. for demos and tests that have no cloud access"); err != nil {
+		return fmt.Errorf("adding mock-plugins config: %w", err)
+	}
+	if err := cs.SetHidden(MockPluginsConfigItem); err != nil {
+		return fmt.Errorf("setting mock-plugins hidden: %w", err)
+	}
 	cs.SetShort("verbosity", "v")                                       //nolint
 	cs.SetHistoryIgnore(ConfigPathConfigItem)                           //nolint
 	cs.SetHistoryIgnore("verbosity")                                    //nolint
 	cs.SetHistoryIgnore(NonInteractiveConfigItem)                       //nolint
 	cs.SetHistoryIgnore(NoInputConfigItem)                              //nolint
 	cs.SetHistoryIgnore(NoVersionCheckConfigItem)                       //nolint
+	cs.SetHistoryIgnore(TraceHTTPConfigItem)                            //nolint
+	cs.SetHistoryIgnore(LogFormatConfigItem)                            //nolint
+	cs.SetHistoryIgnore(LogFileConfigItem)                              //nolint
+	cs.SetHistoryIgnore(LogLevelConfigItem)                             //nolint
+	cs.SetHistoryIgnore(ProfileConfigItem)                              //nolint
+	cs.SetHistoryIgnore(ProfileFileConfigItem)                          //nolint
+	cs.SetHistoryIgnore(MockPluginsConfigItem)                          //nolint
+	cs.SetHistoryIgnore(RecordConfigItem)                               //nolint
+	cs.SetHistoryIgnore(ReplayConfigItem)                               //nolint
+	cs.SetHistoryIgnore(OfflineConfigItem)                              //nolint
+	cs.SetHistoryIgnore(CIConfigItem)                                   //nolint
 	cs.SetDeprecated(NonInteractiveConfigItem, "please use --no-input") //nolint
 
 	return nil
 }
 
 type CommonUseConfig struct {
-	Namespace string `json:"namespace,omitempty"`
+	Namespace         string        `json:"namespace,omitempty"`
+	ExplainConfig     bool          `json:"explain-config,omitempty"`
+	ClusterFilter     string        `json:"cluster-filter,omitempty"`
+	InstallPreReqs    bool          `json:"install-prereqs,omitempty"`
+	AnswersFile       string        `json:"answers-file,omitempty"`
+	VerifyConnection  bool          `json:"verify-connection,omitempty"`
+	DiscoveryCacheTTL time.Duration `json:"discovery-cache-ttl,omitempty"`
+	Refresh           bool          `json:"refresh,omitempty"`
+	Identities        []string      `json:"identities,omitempty"`
+	MultiSelect       bool          `json:"multi-select,omitempty"`
+	WSLInterop        bool          `json:"wsl-interop,omitempty"`
+	OverridePolicy    bool          `json:"override-policy,omitempty"`
+	AuthAttempts      int           `json:"auth-attempts,omitempty"`
+	ProxyConfig
+	CACertConfig
+	ClientCertConfig
+	HTTPConfig
+	TunnelConfig
+	TelemetryConfig
 }
 
 func AddCommonUseConfigItems(cs config.ConfigurationSet) error {
 	if _, err := cs.String("namespace", "", "Sets namespace for context in kubeconfig"); err != nil {
 		return fmt.Errorf("adding config item: %w", err)
 	}
-	cs.SetShort("namespace", "n") //nolint
+	if _, err := cs.String(ClusterFilterConfigItem, "", "Only show discovered clusters with a name that matches the filter. Can specify multiple filters by using commas, and supports wildcards (*)"); err != nil {
+		return fmt.Errorf("adding cluster-filter config: %w", err)
+	}
+	if _, err := cs.Bool(InstallPreReqsConfigItem, false, "Download any missing pre-requisites of the provider (e.g. aws-iam-authenticator) into the kconnect data directory"); err != nil {
+		return fmt.Errorf("adding install-prereqs config: %w", err)
+	}
+	if _, err := cs.String(AnswersFileConfigItem, "", "Path to a YAML file that maps prompt names to values, the values are used instead of asking for input"); err != nil {
+		return fmt.Errorf("adding answers-file config: %w", err)
+	}
+	if _, err := cs.Bool(VerifyConnConfigItem, false, "After writing the kubeconfig, check that the cluster is reachable and accepts the credentials and report its version"); err != nil {
+		return fmt.Errorf("adding verify-connection config: %w", err)
+	}
+	if _, err := cs.Duration(DiscoveryCacheConfigItem, time.Hour, "How long to cache the discovered clusters for, 0 disables the cache"); err != nil {
+		return fmt.Errorf("adding discovery-cache-ttl config: %w", err)
+	}
+	if _, err := cs.Bool(RefreshConfigItem, false, "Discover the clusters again instead of using the cached clusters"); err != nil {
+		return fmt.Errorf("adding refresh config: %w", err)
+	}
+	if _, err := cs.StringSlice(IdentitiesConfigItem, []string{}, "Discover the clusters with each of these identities at the same time, e.g. several AWS roles or Azure tenants. Each is used as the value of the identity provider's config item, e.g. --role-arn for saml"); err != nil {
+		return fmt.Errorf("adding identities config: %w", err)
+	}
+	if _, err := cs.Bool(MultiSelectConfigItem, false, "Choose several of the discovered clusters (space to toggle) and create a context for each of them"); err != nil {
+		return fmt.Errorf("adding multi-select config: %w", err)
+	}
+	if _, err := cs.Bool(WSLInteropConfigItem, false, "When running in WSL, also write the kubeconfig to the Windows user profile so that Windows tools can use the cluster"); err != nil {
+		return fmt.Errorf("adding wsl-interop config: %w", err)
+	}
+	if _, err := cs.Bool(OverridePolicyConfigItem, false, "Show and connect to clusters that are denied by the cluster policy in the app config. Each connection to a denied cluster is recorded in the audit log"); err != nil {
+		return fmt.Errorf("adding override-policy config: %w", err)
+	}
+	if _, err := cs.Int(AuthAttemptsConfigItem, DefaultAuthAttempts, "How many times a credential rejected by the identity provider, e.g. a wrong password, can be entered before giving up. Only the rejected credential is asked for again"); err != nil {
+		return fmt.Errorf("adding auth-attempts config: %w", err)
+	}
+	if err := AddExplainConfigItems(cs); err != nil {
+		return err
+	}
+	if err := AddProxyConfigItems(cs); err != nil {
+		return err
+	}
+	if err := AddCACertConfigItems(cs); err != nil {
+		return err
+	}
+	if err := AddClientCertConfigItems(cs); err != nil {
+		return err
+	}
+	if err := AddHTTPConfigItems(cs); err != nil {
+		return err
+	}
+	if err := AddTunnelConfigItems(cs); err != nil {
+		return err
+	}
+	if err := AddTelemetryConfigItems(cs); err != nil {
+		return err
+	}
+	cs.SetShort("namespace", "n")                 //nolint
+	cs.SetHistoryIgnore(ClusterFilterConfigItem)  //nolint
+	cs.SetHistoryIgnore(InstallPreReqsConfigItem) //nolint
+	cs.SetHistoryIgnore(AnswersFileConfigItem)    //nolint
+	cs.SetHistoryIgnore(VerifyConnConfigItem)     //nolint
+	cs.SetHistoryIgnore(DiscoveryCacheConfigItem) //nolint
+	cs.SetHistoryIgnore(RefreshConfigItem)        //nolint
+	cs.SetHistoryIgnore(IdentitiesConfigItem)     //nolint
+	cs.SetHistoryIgnore(MultiSelectConfigItem)    //nolint
+	cs.SetHistoryIgnore(OverridePolicyConfigItem) //nolint
+	cs.SetHistoryIgnore(AuthAttemptsConfigItem)   //nolint
+	return nil
+}
+
+// ProxyConfig is the proxy configuration for the requests of the identity and discovery providers
+type ProxyConfig struct {
+	IdentityProxy  string `json:"identity-proxy,omitempty"`
+	DiscoveryProxy string `json:"discovery-proxy,omitempty"`
+	NoProxy        string `json:"no-proxy,omitempty"`
+	ProxyUsername  string `json:"proxy-username,omitempty"`
+	ProxyPassword  string `json:"proxy-password,omitempty"`
+}
+
+// AddProxyConfigItems will add the config items for the proxies of the identity and discovery providers
+func AddProxyConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.String(IdentityProxyConfigItem, "", "The proxy to use for the requests of the identity provider, e.g. http://proxy:8080 or socks5://proxy:1080"); err != nil {
+		return fmt.Errorf("adding identity-proxy config: %w", err)
+	}
+	if _, err := cs.String(DiscoveryProxyConfigItem, "", "The proxy to use for the requests of the discovery provider, e.g. http://proxy:8080 or socks5://proxy:1080"); err != nil {
+		return fmt.Errorf("adding discovery-proxy config: %w", err)
+	}
+	if _, err := cs.String(NoProxyConfigItem, "", "Comma separated hosts, domains and CIDRs that aren't sent through the identity or discovery proxy"); err != nil {
+		return fmt.Errorf("adding no-proxy config: %w", err)
+	}
+	if _, err := cs.String(ProxyUsernameConfigItem, "", "The username for the identity and discovery proxies"); err != nil {
+		return fmt.Errorf("adding proxy-username config: %w", err)
+	}
+	if _, err := cs.String(ProxyPasswordConfigItem, "", "The password for the identity and discovery proxies"); err != nil {
+		return fmt.Errorf("adding proxy-password config: %w", err)
+	}
+	cs.SetSensitive(ProxyPasswordConfigItem) //nolint
+
+	return nil
+}
+
+// CACertConfig is the configuration for the certificate authorities to trust in addition to the system ones
+type CACertConfig struct {
+	IDPCACert     string `json:"idp-ca-cert,omitempty"`
+	ClusterCACert string `json:"cluster-ca-cert,omitempty"`
+}
+
+// AddCACertConfigItems will add the config items for the certificate authorities of the IdP and cluster
+func AddCACertConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.String(IDPCACertConfigItem, "", "Path to a PEM file of certificate authorities to trust for the identity provider requests, e.g. behind a TLS intercepting proxy"); err != nil {
+		return fmt.Errorf("adding idp-ca-cert config: %w", err)
+	}
+	if _, err := cs.String(ClusterCACertConfigItem, "", "Path to a PEM file of certificate authorities to add to the cluster in the kubeconfig, e.g. for a cluster with a private CA"); err != nil {
+		return fmt.Errorf("adding cluster-ca-cert config: %w", err)
+	}
+
+	return nil
+}
+
+// ClientCertConfig is the client certificate to present to an identity provider that requires mutual TLS
+type ClientCertConfig struct {
+	IDPClientCert string `json:"idp-client-cert,omitempty"`
+	IDPClientKey  string `json:"idp-client-key,omitempty"`
+}
+
+// AddClientCertConfigItems will add the config items for the client certificate of the IdP requests
+func AddClientCertConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.String(IDPClientCertConfigItem, "", "Path to a PEM file with the client certificate to present to an identity provider that requires mutual TLS. The file can also contain the key"); err != nil {
+		return fmt.Errorf("adding idp-client-cert config: %w", err)
+	}
+	if _, err := cs.String(IDPClientKeyConfigItem, "", "Path to a PEM file with the private key of the idp-client-cert, if it isn't in the certificate file"); err != nil {
+		return fmt.Errorf("adding idp-client-key config: %w", err)
+	}
+
+	return nil
+}
+
+// HTTPConfig is the retry and timeout configuration for the requests of the identity and discovery providers
+type HTTPConfig struct {
+	HTTPMaxRetries   int           `json:"http-max-retries"`
+	HTTPRetryBackoff time.Duration `json:"http-retry-backoff"`
+	HTTPTimeout      time.Duration `json:"http-timeout"`
+}
+
+// RetryPolicy returns the retry policy for the http clients
+func (c *HTTPConfig) RetryPolicy() *khttp.RetryPolicy {
+	return &khttp.RetryPolicy{
+		MaxRetries:     c.HTTPMaxRetries,
+		InitialBackoff: c.HTTPRetryBackoff,
+		MaxBackoff:     khttp.DefaultMaxBackoff,
+		Timeout:        c.HTTPTimeout,
+	}
+}
+
+// AddHTTPConfigItems will add the config items for retrying and timing out http requests
+func AddHTTPConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.Int(HTTPMaxRetriesConfigItem, khttp.DefaultMaxRetries, "How many times a failed request to an identity or discovery provider endpoint is retried, 0 disables retries"); err != nil {
+		return fmt.Errorf("adding http-max-retries config: %w", err)
+	}
+	if _, err := cs.Duration(HTTPBackoffConfigItem, khttp.DefaultInitialBackoff, "How long to wait before the first retry of a failed request, the wait doubles for each retry"); err != nil {
+		return fmt.Errorf("adding http-retry-backoff config: %w", err)
+	}
+	if _, err := cs.Duration(HTTPTimeoutConfigItem, khttp.DefaultRequestTimeout, "The time limit of each request to an identity or discovery provider endpoint, 0 disables the limit"); err != nil {
+		return fmt.Errorf("adding http-timeout config: %w", err)
+	}
+	cs.SetHistoryIgnore(HTTPMaxRetriesConfigItem) //nolint
+	cs.SetHistoryIgnore(HTTPBackoffConfigItem)    //nolint
+	cs.SetHistoryIgnore(HTTPTimeoutConfigItem)    //nolint
+
+	return nil
+}
+
+// AddExplainConfigItems will add the config item to explain where the configuration values came from
+func AddExplainConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.Bool(ExplainConfigConfigItem, false, "Print the final value of each configuration item and where it came from"); err != nil {
+		return fmt.Errorf("adding explain-config config: %w", err)
+	}
+	cs.SetHistoryIgnore(ExplainConfigConfigItem) //nolint
 	return nil
 }
 
@@ -170,6 +468,188 @@ func AddHistoryQueryConfig(cs config.ConfigurationSet) error {
 	return nil
 }
 
+type ListClustersConfig struct {
+	Output *printer.OutputPrinter `json:"output,omitempty"`
+}
+
+// AddListClustersConfigItems will add the config items for listing the discovered clusters
+func AddListClustersConfigItems(cs config.ConfigurationSet) error {
+	outputs := []string{}
+	for _, output := range printer.SupportedPrinters {
+		outputs = append(outputs, string(output))
+	}
+	if _, err := cs.Enum("output", string(printer.OutputPrinterTable), outputs, "Output format for the discovered clusters, json and yaml include the endpoint, certificate authority, account, region and tags"); err != nil {
+		return fmt.Errorf("adding output config item: %w", err)
+	}
+	if err := cs.SetShort("output", "o"); err != nil {
+		return fmt.Errorf("adding output short flag: %w", err)
+	}
+	cs.SetHistoryIgnore("output") //nolint
+	return nil
+}
+
+type OpenConfig struct {
+	With string `json:"with"`
+}
+
+// AddOpenConfigItems will add the config items for opening a cluster with another tool
+func AddOpenConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.Enum("with", OpenWithK9s, []string{OpenWithK9s, OpenWithLens, OpenWithDashboard}, "The tool to open the cluster with"); err != nil {
+		return fmt.Errorf("adding with config item: %w", err)
+	}
+	cs.SetHistoryIgnore("with") //nolint
+	return nil
+}
+
+type HandoffConfig struct {
+	To               string        `json:"to"`
+	Expiry           time.Duration `json:"expiry"`
+	RemoteKubeconfig string        `json:"remote-kubeconfig"`
+}
+
+// AddHandoffConfigItems will add the config items for handing off credentials
+func AddHandoffConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.String("to", "", "Where to hand off the credentials to, in the format kind:[user@]name. The kind is devcontainer, wsl or ssh"); err != nil {
+		return fmt.Errorf("adding to config item: %w", err)
+	}
+	if _, err := cs.Duration("expiry", time.Hour, "How long the handed off kubeconfig is kept, it's removed sooner if the credentials expire before"); err != nil {
+		return fmt.Errorf("adding expiry config item: %w", err)
+	}
+	if _, err := cs.String("remote-kubeconfig", ".kube/kconnect-handoff.yaml", "The path to write the kubeconfig to, relative paths are relative to the home directory"); err != nil {
+		return fmt.Errorf("adding remote-kubeconfig config item: %w", err)
+	}
+	cs.SetHistoryIgnore("to")                //nolint
+	cs.SetHistoryIgnore("expiry")            //nolint
+	cs.SetHistoryIgnore("remote-kubeconfig") //nolint
+	return nil
+}
+
+type ServiceAccountConfig struct {
+	Namespace      string        `json:"namespace"`
+	ServiceAccount string        `json:"service-account"`
+	Duration       time.Duration `json:"duration"`
+	Audiences      []string      `json:"audience"`
+	BoundSecret    string        `json:"bound-secret"`
+	OutputFile     string        `json:"output-file"`
+}
+
+// AddServiceAccountConfigItems will add the config items for creating a service account kubeconfig
+func AddServiceAccountConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.String("namespace", "default", "The namespace of the service account"); err != nil {
+		return fmt.Errorf("adding namespace config item: %w", err)
+	}
+	if err := cs.SetShort("namespace", "n"); err != nil {
+		return fmt.Errorf("setting namespace shorthand: %w", err)
+	}
+	if _, err := cs.String("service-account", "", "The name of the service account to create a token for"); err != nil {
+		return fmt.Errorf("adding service-account config item: %w", err)
+	}
+	if _, err := cs.Duration("duration", time.Hour, "How long the token is valid for, the cluster may issue a token with a different duration"); err != nil {
+		return fmt.Errorf("adding duration config item: %w", err)
+	}
+	if _, err := cs.StringSlice("audience", []string{}, "The audiences of the token, defaults to the audience of the cluster's api server"); err != nil {
+		return fmt.Errorf("adding audience config item: %w", err)
+	}
+	if _, err := cs.String("bound-secret", "", "The name of a secret in the namespace to bind the token to, deleting the secret invalidates the token"); err != nil {
+		return fmt.Errorf("adding bound-secret config item: %w", err)
+	}
+	if _, err := cs.String("output-file", "", "The file to write the kubeconfig to, otherwise it's written to stdout"); err != nil {
+		return fmt.Errorf("adding output-file config item: %w", err)
+	}
+	for _, name := range []string{"namespace", "service-account", "duration", "audience", "bound-secret", "output-file"} {
+		cs.SetHistoryIgnore(name) //nolint
+	}
+	return nil
+}
+
+type EntryAliasConfig struct {
+	Alias string `json:"alias"`
+}
+
+// AddEntryAliasConfigItems will add the config item for giving the history entry to use
+// by its alias, instead of as an argument
+func AddEntryAliasConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.String("alias", "", "Alias of the history entry to use, instead of giving it as an argument"); err != nil {
+		return fmt.Errorf("adding alias config item: %w", err)
+	}
+	if err := cs.SetShort("alias", "a"); err != nil {
+		return fmt.Errorf("setting alias shorthand: %w", err)
+	}
+	cs.SetHistoryIgnore("alias") //nolint
+	return nil
+}
+
+type GetTokenConfig struct {
+	Output       string        `json:"output"`
+	NotifyExpiry time.Duration `json:"notify-expiry"`
+}
+
+// AddGetTokenConfigItems will add the config items for getting a token for a cluster
+func AddGetTokenConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.Enum("output", TokenOutputToken, []string{TokenOutputToken, TokenOutputExecCredential}, "Output format, token is just the bearer token and exec-credential is the ExecCredential JSON of a kubectl credential plugin"); err != nil {
+		return fmt.Errorf("adding output config item: %w", err)
+	}
+	if err := cs.SetShort("output", "o"); err != nil {
+		return fmt.Errorf("setting output shorthand: %w", err)
+	}
+	if _, err := cs.Duration(NotifyExpiryConfigItem, 0, "Show a desktop notification when the credentials expire within this time, e.g. 15m, so you can reconnect before kubectl fails. 0 disables the notification"); err != nil {
+		return fmt.Errorf("adding %s config item: %w", NotifyExpiryConfigItem, err)
+	}
+	cs.SetHistoryIgnore("output")               //nolint
+	cs.SetHistoryIgnore(NotifyExpiryConfigItem) //nolint
+	return nil
+}
+
+type PromptInfoConfig struct {
+	Color bool   `json:"color"`
+	Shell string `json:"shell"`
+}
+
+// AddPromptInfoConfigItems will add the config items for the shell prompt information
+func AddPromptInfoConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.Bool("color", false, "Color the environment and time left by how much care is needed, e.g. red for production"); err != nil {
+		return fmt.Errorf("adding color config item: %w", err)
+	}
+	if _, err := cs.Enum("shell", PromptShellNone, []string{PromptShellNone, PromptShellBash, PromptShellZsh}, "The shell whose prompt the color codes are for, so that they're escaped as not taking up any space"); err != nil {
+		return fmt.Errorf("adding shell config item: %w", err)
+	}
+	cs.SetHistoryIgnore("color") //nolint
+	cs.SetHistoryIgnore("shell") //nolint
+	return nil
+}
+
+type ExpiredConfig struct {
+	Expired string `json:"expired"`
+}
+
+// AddExpiredConfigItems will add the config items for what to do when the credentials of a
+// history entry have expired
+func AddExpiredConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.Enum("expired", ExpiredRefresh, []string{ExpiredRefresh, ExpiredConfirm}, "What to do if the credentials from the last time the entry was used have expired, refresh re-authenticates and confirm asks before re-authenticating"); err != nil {
+		return fmt.Errorf("adding expired config item: %w", err)
+	}
+	cs.SetHistoryIgnore("expired") //nolint
+	return nil
+}
+
+type ServeConfig struct {
+	Address   string `json:"address"`
+	TokenFile string `json:"token-file"`
+}
+
+// AddServeConfigItems will add the config items for serving the local API
+func AddServeConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.String("address", ServeDefaultAddress, "The address to listen on, it should only be reachable locally"); err != nil {
+		return fmt.Errorf("adding address config item: %w", err)
+	}
+	if _, err := cs.String("token-file", "", "The file the token is written to, requests must use the token as a bearer token. (default \"$HOME/.kconnect/serve-token\")"); err != nil {
+		return fmt.Errorf("adding token-file config item: %w", err)
+	}
+	cs.SetHistoryIgnore("address")    //nolint
+	cs.SetHistoryIgnore("token-file") //nolint
+	return nil
+}
+
 type HistoryImportConfig struct {
 	Clean     bool   `json:"clean,omitempty"`
 	File      string `json:"file,omitempty"`
@@ -236,3 +716,65 @@ func AddHistoryRemoveConfig(cs config.ConfigurationSet) error {
 	}
 	return nil
 }
+
+type HistoryPurgeConfig struct {
+	Cluster        string `json:"cluster,omitempty"`
+	Before         string `json:"before,omitempty"`
+	ScrubUsernames bool   `json:"scrub-usernames,omitempty"`
+}
+
+func AddHistoryPurgeConfig(cs config.ConfigurationSet) error {
+	if _, err := cs.String("cluster", "", "Purge the entries with a provider id or alias that matches the pattern, supports wildcards (*)"); err != nil {
+		return fmt.Errorf("adding cluster config: %w", err)
+	}
+	if _, err := cs.String("before", "", "Purge the entries last used before the date, e.g. 2021-01-31 or 2021-01-31T12:00:00Z"); err != nil {
+		return fmt.Errorf("adding before config: %w", err)
+	}
+	if _, err := cs.Bool("scrub-usernames", false, "Remove the recorded usernames from the entries that are kept"); err != nil {
+		return fmt.Errorf("adding scrub-usernames config: %w", err)
+	}
+	return nil
+}
+
+// TunnelConfig is the configuration of the SSH tunnel to reach a cluster with a private endpoint
+type TunnelConfig struct {
+	SSHJumpHost     string `json:"ssh-jump-host,omitempty"`
+	SSHIdentityFile string `json:"ssh-identity-file,omitempty"`
+	SSHTunnelPort   int    `json:"ssh-tunnel-port,omitempty"`
+}
+
+// AddTunnelConfigItems will add the config items for the SSH tunnel to the cluster
+func AddTunnelConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.String(SSHJumpHostConfigItem, "", "Open a SSH tunnel through the jump host ([user@]host[:port]) to reach a cluster with a private endpoint, the kubeconfig uses the tunnel as its proxy-url"); err != nil {
+		return fmt.Errorf("adding ssh-jump-host config: %w", err)
+	}
+	if _, err := cs.String(SSHIdentityConfigItem, "", "Path to the private key for the SSH jump host, the ssh config and agent are used if not set"); err != nil {
+		return fmt.Errorf("adding ssh-identity-file config: %w", err)
+	}
+	if _, err := cs.Int(SSHTunnelPortConfigItem, 0, "The local port of the SSH tunnel, derived from the jump host if not set so that the tunnel is reused"); err != nil {
+		return fmt.Errorf("adding ssh-tunnel-port config: %w", err)
+	}
+
+	return nil
+}
+
+// TelemetryConfig is the configuration for exporting traces of the connect flow to an OpenTelemetry collector
+type TelemetryConfig struct {
+	OTLPEndpoint string `json:"otlp-endpoint,omitempty"`
+	OTLPHeaders  string `json:"otlp-headers,omitempty"`
+}
+
+// AddTelemetryConfigItems will add the config items for exporting traces
+func AddTelemetryConfigItems(cs config.ConfigurationSet) error {
+	if _, err := cs.String(OTLPEndpointConfigItem, "", "Export traces of the connect flow to the OpenTelemetry collector using OTLP over HTTP, e.g. http://localhost:4318"); err != nil {
+		return fmt.Errorf("adding otlp-endpoint config: %w", err)
+	}
+	if _, err := cs.String(OTLPHeadersConfigItem, "", "Comma separated name=value headers to send to the OpenTelemetry collector, e.g. for authentication"); err != nil {
+		return fmt.Errorf("adding otlp-headers config: %w", err)
+	}
+	cs.SetSensitive(OTLPHeadersConfigItem)      //nolint
+	cs.SetHistoryIgnore(OTLPEndpointConfigItem) //nolint
+	cs.SetHistoryIgnore(OTLPHeadersConfigItem)  //nolint
+
+	return nil
+}
@@ -17,10 +17,13 @@ limitations under the License.
 package app
 
 import (
+	"bytes"
 	"context"
+	"encoding/json"
 	"errors"
 	"fmt"
 	"io"
+	"io/ioutil"
 	"net/url"
 	"os"
 	"strings"
@@ -29,7 +32,9 @@ import (
 
 	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/oci"
 	"github.com/fidelity/kconnect/pkg/printer"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
 )
 
 // ConfigureInput is the input type for the configure command
@@ -40,6 +45,18 @@ type ConfigureInput struct {
 	Password       string                 `json:"password,omitempty"`
 }
 
+// ConfigValidateInput is the input type for the config validate command
+type ConfigValidateInput struct {
+	CommonConfig
+	File string `json:"file,omitempty"`
+}
+
+// ConfigSchemaInput is the input type for the config schema command
+type ConfigSchemaInput struct {
+	CommonConfig
+	Provider string
+}
+
 var ErrNotOKHTTPStatusCode = errors.New("non 200 status code")
 
 // Configuration implements the configure command
@@ -50,6 +67,70 @@ func (a *App) Configuration(ctx context.Context, input *ConfigureInput) error {
 	return a.importConfiguration(input)
 }
 
+// ConfigurationValidate implements the config validate command. Each problem found
+// is printed along with its location and a suggested fix if there is one.
+func (a *App) ConfigurationValidate(ctx context.Context, input *ConfigValidateInput) error {
+	location := input.File
+	if location == "" {
+		location = input.ConfigFile
+	}
+	zap.S().Debugw("validating configuration", "file", location)
+
+	data, err := ioutil.ReadFile(location)
+	if err != nil {
+		return fmt.Errorf("reading configuration file %s: %w", location, err)
+	}
+
+	schema, err := ConfigSchema()
+	if err != nil {
+		return fmt.Errorf("getting configuration schema: %w", err)
+	}
+
+	validationErrs, err := config.Validate(location, data, schema)
+	if err != nil {
+		return fmt.Errorf("validating configuration: %w", err)
+	}
+
+	if len(validationErrs) == 0 {
+		fmt.Fprintf(os.Stdout, "configuration %s is valid\n", location)
+		return nil
+	}
+
+	for _, validationErr := range validationErrs {
+		fmt.Fprintln(os.Stdout, validationErr.Error())
+	}
+
+	return fmt.Errorf("validating %s found %d problem(s): %w", location, len(validationErrs), ErrConfigInvalid)
+}
+
+// ConfigurationSchema implements the config schema command. It prints a JSON Schema
+// for the app configuration or for a single providers configuration items.
+func (a *App) ConfigurationSchema(ctx context.Context, input *ConfigSchemaInput) error {
+	schema, err := ConfigSchema()
+	if err != nil {
+		return fmt.Errorf("getting configuration schema: %w", err)
+	}
+
+	jsonSchema := schema.JSONSchema()
+	if input.Provider != "" {
+		providerItems, ok := schema.Providers[input.Provider]
+		if !ok {
+			return fmt.Errorf("getting schema for provider %s: %w", input.Provider, registry.ErrPluginNotFound)
+		}
+		jsonSchema = config.ItemsJSONSchema(providerItems)
+		jsonSchema.Schema = config.JSONSchemaDraft
+		jsonSchema.Title = fmt.Sprintf("kconnect %s configuration", input.Provider)
+	}
+
+	data, err := json.MarshalIndent(jsonSchema, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling json schema: %w", err)
+	}
+	fmt.Fprintln(os.Stdout, string(data))
+
+	return nil
+}
+
 func (a *App) printConfiguration(printerType *printer.OutputPrinter) error {
 	zap.S().Debug("printing configuration")
 
@@ -112,6 +193,20 @@ func (a *App) getReader(location, username, password string) (io.Reader, error)
 	switch {
 	case location == "-":
 		return os.Stdin, nil
+	case oci.IsReference(location):
+		ref, err := oci.ParseReference(location)
+		if err != nil {
+			return nil, fmt.Errorf("parsing location as oci reference %s: %w", location, err)
+		}
+		var creds *oci.Credentials
+		if username != "" && password != "" {
+			creds = &oci.Credentials{Username: username, Password: password}
+		}
+		data, err := oci.NewClient(a.httpClient, creds).PullConfig(ref)
+		if err != nil {
+			return nil, fmt.Errorf("pulling configuration artifact: %w", err)
+		}
+		return bytes.NewReader(data), nil
 	case strings.Index(location, "http://") == 0 || strings.Index(location, "https://") == 0:
 		url, err := url.Parse(location)
 		if err != nil {
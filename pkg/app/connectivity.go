@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/k8s/connectivity"
+	"github.com/fidelity/kconnect/pkg/telemetry"
+)
+
+// verifyConnection will check the kubeconfig works by connecting to the cluster. The
+// kubeconfig has already been written so problems are reported as warnings.
+func (a *App) verifyConnection(ctx context.Context, kubeConfig *api.Config, contextName string) {
+	a.logger.Debugw("verifying connection to cluster", "context", contextName)
+
+	ctx, span := telemetry.Start(ctx, "cluster.verify-connection", "context", contextName)
+	defer span.Finish()
+
+	result := connectivity.Verify(ctx, kubeConfig, contextName, connectivity.DefaultTimeout)
+	span.RecordError(result.Err)
+	span.SetAttributes("server-version", result.ServerVersion)
+	switch {
+	case !result.Reachable:
+		a.logger.Warnw("the cluster can't be reached, check the network path to the API server", "context", contextName, "error", result.Err.Error())
+	case !result.Authenticated:
+		a.logger.Warnw("the cluster is reachable but didn't accept the credentials", "context", contextName, "version", result.ServerVersion, "error", result.Err.Error())
+	case !result.CanListPods:
+		a.logger.Warnw("connected to the cluster but the identity can't list pods in the namespace", "context", contextName, "version", result.ServerVersion)
+	default:
+		a.logger.Infow("connected to the cluster", "context", contextName, "version", result.ServerVersion)
+	}
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/aws/awsconfig"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/logging"
+)
+
+const (
+	// awsProfileFlag is the flag of a history entry with the aws profile that has the credentials
+	awsProfileFlag = "aws-profile"
+	// samlIdentityProvider is the identity provider that saves the credentials of the
+	// entry to the profile, so they're refreshed by reconnecting
+	samlIdentityProvider = "saml"
+	// credentialProcessRefreshWindow is how long before the credentials expire that they're
+	// refreshed, so that the aws cli and sdks don't get credentials that are about to expire
+	credentialProcessRefreshWindow = 5 * time.Minute
+)
+
+type CredentialProcessInput struct {
+	ConnectToInput
+	EntryAliasConfig
+}
+
+// CredentialProcess writes the AWS credentials of a history entry in the
+// credential_process format. This allows the aws cli and sdks to use the same session as
+// kconnect. For the saml identity provider, if the credentials have expired the entry is
+// reconnected to, in the same way as the to command, to refresh them. For other identity
+// providers, e.g. aws-iam with an SSO profile, the credentials of the profile are
+// resolved by the aws sdk.
+func (a *App) CredentialProcess(ctx context.Context, params *CredentialProcessInput) error {
+	if err := params.useEntryAlias(&params.ConnectToInput); err != nil {
+		return err
+	}
+
+	entry, err := a.getHistoryEntry(&params.ConnectToInput)
+	if err != nil {
+		return fmt.Errorf("getting history entry: %w", err)
+	}
+	if entry == nil {
+		return history.ErrEntryNotFound
+	}
+	profile := entry.Spec.Flags[awsProfileFlag]
+	if entry.Spec.Provider != "eks" || profile == "" {
+		return ErrNoAWSProfile
+	}
+
+	var creds *awsconfig.Credentials
+	if entry.Spec.Identity == samlIdentityProvider {
+		// The same entry is reconnected to even if the argument was relative, e.g. LAST
+		params.AliasOrIDORPosition = entry.Name
+		creds, err = a.samlCredentials(ctx, params, profile)
+	} else {
+		creds, err = awsconfig.ResolveCredentials(ctx, profile)
+	}
+	if err != nil {
+		return err
+	}
+	logging.Redact(creds.SecretAccessKey, creds.SessionToken)
+	if a.ciEnv != nil {
+		a.ciEnv.MaskSecret(creds.SecretAccessKey)
+		a.ciEnv.MaskSecret(creds.SessionToken)
+	}
+
+	data, err := json.Marshal(creds.CredentialProcessOutput())
+	if err != nil {
+		return fmt.Errorf("marshalling credential process output: %w", err)
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+
+	return err
+}
+
+// samlCredentials loads the credentials the saml identity provider saved to the profile,
+// the entry is reconnected to if they have expired
+func (a *App) samlCredentials(ctx context.Context, params *CredentialProcessInput, profile string) (*awsconfig.Credentials, error) {
+	creds, err := awsconfig.LoadCredentials(profile)
+	if err == nil && !creds.Expired(credentialProcessRefreshWindow) {
+		return creds, nil
+	}
+
+	zap.S().Debugw("refreshing aws credentials", "profile", profile, "entry", params.AliasOrIDORPosition)
+	if _, err := a.connectIsolated(ctx, &params.ConnectToInput); err != nil {
+		return nil, fmt.Errorf("refreshing aws credentials: %w", err)
+	}
+	if creds, err = awsconfig.LoadCredentials(profile); err != nil {
+		return nil, fmt.Errorf("loading aws credentials: %w", err)
+	}
+
+	return creds, nil
+}
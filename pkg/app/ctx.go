@@ -0,0 +1,268 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	"k8s.io/apimachinery/pkg/util/duration"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	historyv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+	"github.com/fidelity/kconnect/pkg/printer"
+	"github.com/fidelity/kconnect/pkg/prompt"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const previousContext = "-"
+
+type SwitchContextInput struct {
+	CommonConfig
+	HistoryLocationConfig
+	KubernetesConfig
+
+	Context string
+	List    bool `json:"list"`
+}
+
+// kconnectContext is a context in the kubeconfig that was created by kconnect
+type kconnectContext struct {
+	name  string
+	alias string
+	entry *historyv1alpha.HistoryEntry
+}
+
+func (c *kconnectContext) lastUsed() time.Time {
+	if c.entry == nil {
+		return time.Time{}
+	}
+	return c.entry.Status.LastUsed.Time
+}
+
+// SwitchContext implements the switching between the kconnect contexts in the kubeconfig
+func (a *App) SwitchContext(ctx context.Context, params *SwitchContextInput) error {
+	zap.S().Debug("switching context")
+
+	cfg, err := kubeconfig.Read(params.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	contexts, err := a.kconnectContexts(cfg)
+	if err != nil {
+		return fmt.Errorf("getting kconnect contexts: %w", err)
+	}
+
+	if params.List || (params.Context == "" && !a.interactive) {
+		return printContexts(contexts, cfg.CurrentContext)
+	}
+
+	selected, err := a.selectContext(params.Context, contexts, cfg)
+	if err != nil {
+		return err
+	}
+	if selected.name == cfg.CurrentContext {
+		zap.S().Infow("context is already the current context", "context", selected.name)
+		return nil
+	}
+
+	if err := kubeconfig.SetCurrentContext(params.Kubeconfig, selected.name); err != nil {
+		return fmt.Errorf("setting current context: %w", err)
+	}
+	if selected.entry != nil {
+		selected.entry.Status.LastUsed = metav1.Now()
+		if err := a.historyStore.Update(selected.entry); err != nil {
+			zap.S().Warnf("failed to update the last used time of history entry %s: %s", selected.entry.Name, err)
+		}
+	}
+	fmt.Fprintf(os.Stderr, "Switched to context %q.\n", selected.name)
+
+	return nil
+}
+
+// kconnectContexts returns the contexts in the kubeconfig created by kconnect, with the
+// most recently used first
+func (a *App) kconnectContexts(cfg *api.Config) ([]*kconnectContext, error) {
+	entries, err := a.historyStore.GetAll()
+	if err != nil {
+		return nil, fmt.Errorf("getting history entries: %w", err)
+	}
+	entriesByID := map[string]*historyv1alpha.HistoryEntry{}
+	for i := range entries.Items {
+		entriesByID[entries.Items[i].Name] = &entries.Items[i]
+	}
+
+	contexts := []*kconnectContext{}
+	for name, kubeContext := range cfg.Contexts {
+		reference, err := historyv1alpha.GetHistoryReferenceFromContext(kubeContext)
+		if err != nil {
+			continue
+		}
+		kconnectCtx := &kconnectContext{name: name}
+		if entry, ok := entriesByID[reference.EntryID]; ok {
+			kconnectCtx.entry = entry
+			if entry.Spec.Alias != nil {
+				kconnectCtx.alias = *entry.Spec.Alias
+			}
+		}
+		contexts = append(contexts, kconnectCtx)
+	}
+
+	sort.SliceStable(contexts, func(i, j int) bool {
+		if !contexts[i].lastUsed().Equal(contexts[j].lastUsed()) {
+			return contexts[i].lastUsed().After(contexts[j].lastUsed())
+		}
+		return contexts[i].name < contexts[j].name
+	})
+
+	return contexts, nil
+}
+
+// selectContext finds the context to switch to. The query can be the name or alias of the
+// context, a fuzzy search of them or - for the previous context.
+func (a *App) selectContext(query string, contexts []*kconnectContext, cfg *api.Config) (*kconnectContext, error) {
+	if query == previousContext {
+		return previousKubectxContext(contexts, cfg)
+	}
+	if query == "" {
+		return a.chooseContext(contexts)
+	}
+
+	matches := []*kconnectContext{}
+	for _, kconnectCtx := range contexts {
+		if kconnectCtx.name == query || kconnectCtx.alias == query {
+			return kconnectCtx, nil
+		}
+		if utils.FuzzyMatch(query, kconnectCtx.name) || utils.FuzzyMatch(query, kconnectCtx.alias) {
+			matches = append(matches, kconnectCtx)
+		}
+	}
+
+	switch {
+	case len(matches) == 0:
+		return nil, fmt.Errorf("matching %s: %w", query, ErrContextNotFound)
+	case len(matches) == 1:
+		return matches[0], nil
+	case !a.interactive:
+		return nil, fmt.Errorf("matching %s: %w", query, ErrContextAmbiguous)
+	default:
+		return a.chooseContext(matches)
+	}
+}
+
+func (a *App) chooseContext(contexts []*kconnectContext) (*kconnectContext, error) {
+	if len(contexts) == 0 {
+		return nil, ErrContextNotFound
+	}
+
+	nameWidth := 0
+	for _, kconnectCtx := range contexts {
+		if len(kconnectCtx.name) > nameWidth {
+			nameWidth = len(kconnectCtx.name)
+		}
+	}
+	options := map[string]string{}
+	optionNames := []string{}
+	for _, kconnectCtx := range contexts {
+		option := fmt.Sprintf("%-*s  %s", nameWidth, kconnectCtx.name, kconnectCtx.alias)
+		options[option] = kconnectCtx.name
+		optionNames = append(optionNames, option)
+	}
+
+	selected, err := prompt.Choose("context", "Select a context", true, prompt.OptionsFromStringSlice(optionNames))
+	if err != nil {
+		return nil, fmt.Errorf("asking for context: %w", err)
+	}
+	for _, kconnectCtx := range contexts {
+		if kconnectCtx.name == options[selected] {
+			return kconnectCtx, nil
+		}
+	}
+
+	return nil, ErrContextNotFound
+}
+
+// previousKubectxContext returns the previous context saved by kconnect or kubectx. It
+// doesn't have to be a kconnect context.
+func previousKubectxContext(contexts []*kconnectContext, cfg *api.Config) (*kconnectContext, error) {
+	path, err := kubeconfig.PreviousContextFile()
+	if err != nil {
+		return nil, fmt.Errorf("getting previous context file: %w", err)
+	}
+	name, err := kubeconfig.ReadPreviousContext(path)
+	if err != nil {
+		return nil, err
+	}
+	if name == "" {
+		return nil, ErrNoPreviousContext
+	}
+
+	for _, kconnectCtx := range contexts {
+		if kconnectCtx.name == name {
+			return kconnectCtx, nil
+		}
+	}
+	if _, ok := cfg.Contexts[name]; !ok {
+		return nil, fmt.Errorf("previous context %s: %w", name, ErrContextNotFound)
+	}
+
+	return &kconnectContext{name: name}, nil
+}
+
+func printContexts(contexts []*kconnectContext, currentContext string) error {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: metav1.SchemeGroupVersion.String(),
+			Kind:       "Table",
+		},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Cur", Type: "string"},
+			{Name: "Name", Type: "string"},
+			{Name: "Alias", Type: "string"},
+			{Name: "Id", Type: "string"},
+			{Name: "Last used", Type: "string"},
+		},
+	}
+
+	for _, kconnectCtx := range contexts {
+		currentIndicator := ""
+		if kconnectCtx.name == currentContext {
+			currentIndicator = ">"
+		}
+		id, lastUsed := "", ""
+		if kconnectCtx.entry != nil {
+			id = kconnectCtx.entry.Name
+			lastUsed = duration.HumanDuration(time.Since(kconnectCtx.lastUsed()))
+		}
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{currentIndicator, kconnectCtx.name, kconnectCtx.alias, id, lastUsed},
+		})
+	}
+
+	objPrinter, err := printer.New(printer.OutputPrinterTable)
+	if err != nil {
+		return fmt.Errorf("getting table printer: %w", err)
+	}
+
+	return objPrinter.Print(table, os.Stdout)
+}
@@ -16,21 +16,49 @@ limitations under the License.
 
 package app
 
-import "errors"
+import (
+	"errors"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
 
 var (
 	ErrUnknownConfigItemType     = errors.New("unknown item type")
-	ErrClusterNotFound           = errors.New("cluster not found")
+	ErrClusterNotFound           = kerrors.WithCode(kerrors.CodeClusterNotFound, errors.New("cluster not found"))
+	ErrNoClustersFound           = kerrors.WithCode(kerrors.CodeNoClustersFound, errors.New("no clusters discovered"))
 	ErrAliasAlreadyUsed          = errors.New("alias already in use")
+	ErrAliasWithMultiSelect      = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("an alias can't be used when selecting several clusters"))
 	ErrSourceLocationRequired    = errors.New("source location is required for importing")
 	ErrHistoryLocationRequired   = errors.New("history location is required")
 	ErrHistoryIDRequired         = errors.New("history id is required")
 	ErrAliasRequired             = errors.New("alias is required")
 	ErrAliasAndIDNotAllowed      = errors.New("alias and id bith specified, only 1 is allowed")
+	ErrPinRequired               = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("a kubeconfig, context name or group to pin is required"))
+	ErrPinAndClearNotAllowed     = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("clear can't be used with a kubeconfig, context name or group to pin"))
 	ErrAliasNotFound             = errors.New("no alias found")
+	ErrGroupNotFound             = kerrors.WithCode(kerrors.CodeClusterNotFound, errors.New("no history entries in alias group"))
 	ErrNoEntriesFound            = errors.New("no entries found")
-	ErrUnknownProvider           = errors.New("unknown provider")
+	ErrUnknownProvider           = kerrors.WithCode(kerrors.CodePluginUnavailable, errors.New("unknown provider"))
 	ErrDiscoveryProviderRequired = errors.New("discovery provider required")
 	ErrIdentityProviderRequired  = errors.New("identity provider required")
-	ErrUnsuportedIdpProtocol     = errors.New("unsupported idp protocol")
+	ErrUnsuportedIdpProtocol     = kerrors.WithCode(kerrors.CodePluginUnavailable, errors.New("unsupported idp protocol"))
+	ErrConfigInvalid             = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("configuration is invalid"))
+	ErrInteractiveRequired       = kerrors.WithCode(kerrors.CodeInputRequired, errors.New("plugin can only be used interactively"))
+	ErrIdentitiesUnsupported     = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("identity provider can't discover with several identities"))
+	ErrContextNotFound           = kerrors.WithCode(kerrors.CodeClusterNotFound, errors.New("no kconnect context found"))
+	ErrContextAmbiguous          = kerrors.WithCode(kerrors.CodeInputRequired, errors.New("more than 1 kconnect context matches"))
+	ErrNoPreviousContext         = errors.New("no previous context")
+	ErrUnsupportedOpenWith       = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("unsupported tool to open the cluster with"))
+	ErrCredentialsExpired        = kerrors.WithCode(kerrors.CodeAuthFailed, errors.New("the credentials have already expired"))
+	ErrIncompatible              = kerrors.WithCode(kerrors.CodePrereqMissing, errors.New("incompatible versions found"))
+	ErrEmptyContextName          = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("the context name pinned to the history entry is empty"))
+	ErrReauthDeclined            = errors.New("re-authenticating with expired credentials declined")
+	ErrNothingToResume           = errors.New("no failed use command to resume")
+	ErrResumeIdentityExpired     = kerrors.WithCode(kerrors.CodeAuthFailed, errors.New("the saved identity has expired, use the use command to authenticate again"))
+	ErrNoDashboard               = kerrors.WithCode(kerrors.CodePluginUnavailable, errors.New("discovery provider has no managed dashboard"))
+	ErrPurgeCriteriaRequired     = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("a cluster pattern, a date to purge before or scrub-usernames is required"))
+	ErrInvalidPurgeDate          = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("the date must be in the form 2006-01-02 or RFC3339"))
+	ErrNoBearerToken             = kerrors.WithCode(kerrors.CodeAuthFailed, errors.New("the cluster uses a client certificate instead of a bearer token"))
+	ErrEntryArgAndAlias          = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("give the history entry as an argument or with --alias, not both"))
+	ErrNoAWSProfile              = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("the history entry doesn't have an aws profile with credentials, it must be for an eks cluster"))
 )
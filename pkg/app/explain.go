@@ -0,0 +1,78 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"io"
+	"sort"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/printer"
+)
+
+// explainConfig will print the final value of every configuration item and the
+// source of that value
+func explainConfig(cs config.ConfigurationSet, writer io.Writer) error {
+	objPrinter, err := printer.New(printer.OutputPrinterTable)
+	if err != nil {
+		return err
+	}
+
+	return objPrinter.Print(explainTable(cs), writer)
+}
+
+func explainTable(cs config.ConfigurationSet) *metav1.Table {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: metav1.SchemeGroupVersion.String(),
+			Kind:       "Table",
+		},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "Value", Type: "string"},
+			{Name: "Source", Type: "string"},
+		},
+	}
+
+	items := cs.GetAll()
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	for _, item := range items {
+		value := item.ValueString()
+		source := item.Source
+		if !item.HasValue() {
+			value = (&config.Item{Type: item.Type, Value: item.DefaultValue}).ValueString()
+			source = config.ItemSourceDefault
+		}
+		if source == "" {
+			source = config.ItemSourceResolved
+		}
+		if item.Sensitive && value != "" {
+			value = config.MaskedValue
+		}
+
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{item.Name, value, string(source)},
+		})
+	}
+
+	return table
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"path"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/fidelity/kconnect/pkg/handoff"
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+)
+
+type HandoffInput struct {
+	CommonConfig
+	HistoryLocationConfig
+	KubernetesConfig
+	HandoffConfig
+
+	Context string
+}
+
+// Handoff copies the minimum kubeconfig for a context, with short-lived credentials
+// instead of the exec credential plugins, to a devcontainer, WSL distro or remote
+// host. This means the identity provider flow doesn't have to be run there.
+func (a *App) Handoff(ctx context.Context, params *HandoffInput) error {
+	zap.S().Debugw("handing off credentials", "to", params.To)
+
+	target, err := handoff.ParseTarget(params.To)
+	if err != nil {
+		return fmt.Errorf("parsing handoff target: %w", err)
+	}
+
+	cfg, err := kubeconfig.Read(params.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	contextName := cfg.CurrentContext
+	if params.Context != "" {
+		contexts, err := a.kconnectContexts(cfg)
+		if err != nil {
+			return fmt.Errorf("getting kconnect contexts: %w", err)
+		}
+		selected, err := a.selectContext(params.Context, contexts, cfg)
+		if err != nil {
+			return err
+		}
+		contextName = selected.name
+	}
+
+	minified, err := kubeconfig.Minify(cfg, contextName)
+	if err != nil {
+		return fmt.Errorf("getting kubeconfig for context %s: %w", contextName, err)
+	}
+	// The history entry isn't available in the target
+	minified.Contexts[contextName].Extensions = nil
+
+	credentialsExpiry, err := kubeconfig.ResolveExecCredentials(ctx, minified)
+	if err != nil {
+		return fmt.Errorf("getting short-lived credentials: %w", err)
+	}
+	expiresAt := time.Now().Add(params.Expiry)
+	if credentialsExpiry != nil && credentialsExpiry.Before(expiresAt) {
+		expiresAt = *credentialsExpiry
+	}
+
+	if !expiresAt.After(time.Now()) {
+		return ErrCredentialsExpired
+	}
+
+	for name, cluster := range minified.Clusters {
+		if cluster.ProxyURL != "" {
+			zap.S().Warnw("the cluster uses a proxy that may not be reachable from the handoff target", "cluster", name, "proxy", cluster.ProxyURL)
+		}
+	}
+
+	data, err := clientcmd.Write(*minified)
+	if err != nil {
+		return fmt.Errorf("serializing kubeconfig: %w", err)
+	}
+	if err := handoff.Copy(ctx, target, data, params.RemoteKubeconfig, time.Until(expiresAt).Round(time.Second)); err != nil {
+		return err
+	}
+
+	remotePath := params.RemoteKubeconfig
+	if !path.IsAbs(remotePath) {
+		remotePath = path.Join("$HOME", remotePath)
+	}
+	fmt.Fprintf(os.Stderr, "Handed off context %q to %s until %s, use it there with:\n  export KUBECONFIG=%s\n",
+		contextName, target, expiresAt.Format(time.RFC3339), remotePath)
+
+	return nil
+}
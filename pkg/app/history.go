@@ -19,6 +19,7 @@ package app
 import (
 	"context"
 	"fmt"
+	"time"
 
 	"github.com/fidelity/kconnect/api/v1alpha1"
 	"github.com/fidelity/kconnect/pkg/flags"
@@ -46,6 +47,12 @@ type HistoryRemoveInput struct {
 	RemoveList []string
 }
 
+type HistoryPurgeInput struct {
+	CommonConfig
+	HistoryLocationConfig
+	HistoryPurgeConfig
+}
+
 // AliasList implements the alias listing functionality
 func (a *App) HistoryImport(ctx context.Context, input *HistoryImportInput) error {
 	zap.S().Infow("importing history")
@@ -171,6 +178,52 @@ func (a *App) HistoryRemove(ctx context.Context, input *HistoryRemoveInput) erro
 	return nil
 }
 
+// HistoryPurge removes the history entries for clusters that match a pattern or that were
+// last used before a date, and can scrub the recorded usernames from the other entries
+func (a *App) HistoryPurge(ctx context.Context, input *HistoryPurgeInput) error {
+	zap.S().Infow("purging history", "cluster", input.Cluster, "before", input.Before, "scrub-usernames", input.ScrubUsernames)
+
+	if input.Cluster == "" && input.Before == "" && !input.ScrubUsernames {
+		return ErrPurgeCriteriaRequired
+	}
+
+	spec := &history.PurgeSpec{
+		Cluster:        input.Cluster,
+		ScrubUsernames: input.ScrubUsernames,
+	}
+	if input.Before != "" {
+		before, err := parsePurgeDate(input.Before)
+		if err != nil {
+			return err
+		}
+		spec.Before = &before
+	}
+
+	result, err := a.historyStore.Purge(spec)
+	if err != nil {
+		return fmt.Errorf("purging history entries: %w", err)
+	}
+	for i := range result.Purged {
+		zap.S().Debugw("purged history entry", "id", result.Purged[i].ObjectMeta.Name, "provider-id", result.Purged[i].Spec.ProviderID)
+	}
+	zap.S().Infof("purged %d entries and scrubbed the usernames from %d entries", len(result.Purged), result.Scrubbed)
+
+	return nil
+}
+
+// parsePurgeDate parses a date, which is midnight UTC, or a RFC3339 time
+func parsePurgeDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	t, err := time.Parse(time.RFC3339, value)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("parsing date %s: %w", value, ErrInvalidPurgeDate)
+	}
+
+	return t, nil
+}
+
 func createFilter(filterString string) *history.FilterSpec {
 
 	filterParts := flags.ParseFlagMultiValueToMap(filterString)
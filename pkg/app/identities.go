@@ -0,0 +1,161 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"sync"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+	"github.com/fidelity/kconnect/pkg/telemetry"
+)
+
+// AnnotationIdentity is added to the clusters discovered with --identities and is the
+// identity that discovered the cluster
+const AnnotationIdentity = "kconnect.io/identity"
+
+// identityDiscovery is the discovery of the clusters with one of the identities
+type identityDiscovery struct {
+	value           string
+	configSet       config.ConfigurationSet
+	clusterProvider discovery.Provider
+	identity        identity.Identity
+	output          *discovery.DiscoverOutput
+	err             error
+
+	// cluster is the cluster that was chosen from the clusters discovered with all the identities
+	cluster *discovery.Cluster
+}
+
+// discoverWithIdentities will authenticate with each of the identities, one at a time as
+// they may ask for input, and then discover the clusters with all the identities at the
+// same time. The clusters are merged and annotated with the identity that discovered
+// them. The discovery for the chosen cluster is returned, or nil if no cluster was chosen.
+func (a *App) discoverWithIdentities(ctx context.Context, input *UseInput, newProviders func() (identity.Provider, discovery.Provider, error)) (*identityDiscovery, error) {
+	idReg, err := registry.GetIdentityProviderRegistration(input.IdentityProvider)
+	if err != nil {
+		return nil, fmt.Errorf("getting identity provider %s: %w", input.IdentityProvider, err)
+	}
+	item := idReg.IdentitiesConfigItem
+	if item == "" || !input.ConfigSet.Exists(item) {
+		return nil, fmt.Errorf("using --%s with %s: %w", IdentitiesConfigItem, input.IdentityProvider, ErrIdentitiesUnsupported)
+	}
+
+	discoveries := []*identityDiscovery{}
+	for _, value := range input.Identities {
+		cs := config.Copy(input.ConfigSet)
+		if err := cs.SetValue(item, value); err != nil {
+			return nil, fmt.Errorf("setting %s to %s: %w", item, value, err)
+		}
+		identityProvider, clusterProvider, err := newProviders()
+		if err != nil {
+			return nil, err
+		}
+		a.logger.Infow("authenticating identity", "provider", identityProvider.Name(), item, value)
+		id, err := a.authenticate(ctx, identityProvider, clusterProvider, cs)
+		if err != nil {
+			return nil, fmt.Errorf("using identity %s: %w", value, err)
+		}
+		discoveries = append(discoveries, &identityDiscovery{
+			value:           value,
+			configSet:       cs,
+			clusterProvider: clusterProvider,
+			identity:        id,
+		})
+	}
+
+	if !input.IgnoreAlias {
+		if err := a.resolveAndCheckAlias(input); err != nil {
+			return nil, fmt.Errorf("resolving and checking alias: %w", err)
+		}
+	}
+
+	a.logger.Infow("discovering clusters", "provider", input.DiscoveryProvider, "identities", len(discoveries))
+	wg := sync.WaitGroup{}
+	for _, d := range discoveries {
+		wg.Add(1)
+		go func(d *identityDiscovery) {
+			defer wg.Done()
+			discoverCtx, span := telemetry.Start(ctx, "discovery.identity", "identity", d.value)
+			d.output, d.err = d.clusterProvider.Discover(discoverCtx, &discovery.DiscoverInput{
+				ConfigSet: d.configSet,
+				Identity:  d.identity,
+			})
+			span.RecordError(d.err)
+			span.Finish()
+		}(d)
+	}
+	wg.Wait()
+
+	merged, owners, err := a.mergeDiscoveries(input, discoveries)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := a.chooseCluster(ctx, discoveries[0].clusterProvider, merged)
+	if err != nil {
+		return nil, err
+	}
+	if cluster == nil {
+		return nil, nil
+	}
+	chosen := owners[cluster.ID]
+	chosen.cluster = cluster
+
+	return chosen, nil
+}
+
+// mergeDiscoveries merges the clusters discovered with each identity. A cluster that more
+// than one identity can see is kept for the first identity. An identity that failed is
+// skipped with a warning unless all of the identities failed.
+func (a *App) mergeDiscoveries(input *UseInput, discoveries []*identityDiscovery) (*discovery.DiscoverOutput, map[string]*identityDiscovery, error) {
+	merged := &discovery.DiscoverOutput{
+		DiscoveryProvider: input.DiscoveryProvider,
+		IdentityProvider:  input.IdentityProvider,
+		Clusters:          make(map[string]*discovery.Cluster),
+	}
+	owners := make(map[string]*identityDiscovery)
+
+	var firstErr error
+	for _, d := range discoveries {
+		if d.err != nil {
+			if firstErr == nil {
+				firstErr = fmt.Errorf("discovering clusters with identity %s: %w", d.value, d.err)
+			}
+			a.logger.Warnw("failed discovering clusters with identity", "identity", d.value, "error", d.err.Error())
+			continue
+		}
+		for _, cluster := range d.output.Clusters {
+			if _, found := merged.Clusters[cluster.ID]; found {
+				continue
+			}
+			cluster.SetAnnotation(AnnotationIdentity, d.value)
+			merged.Clusters[cluster.ID] = cluster
+			owners[cluster.ID] = d
+		}
+	}
+
+	if len(merged.Clusters) == 0 && firstErr != nil {
+		return nil, nil, firstErr
+	}
+
+	return merged, owners, nil
+}
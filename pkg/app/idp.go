@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+// IdentityProviderSelection is the identity provider that has been selected automatically
+type IdentityProviderSelection struct {
+	// Name is the name of the selected identity provider
+	Name string
+	// Reason is why the identity provider was selected
+	Reason string
+	// Alternatives are other identity providers that were also detected
+	Alternatives []string
+}
+
+// SelectIdentityProvider will select the identity provider to use with a discovery provider
+// when one hasn't been specified. The identity provider from the most recently used history
+// entry for the discovery provider is preferred, then an identity provider detected from the
+// environment and finally the first supported identity provider. The history store is optional.
+func SelectIdentityProvider(discoveryProvider string, store history.Store) (*IdentityProviderSelection, error) {
+	discoReg, err := registry.GetDiscoveryProviderRegistration(discoveryProvider)
+	if err != nil {
+		return nil, fmt.Errorf("getting discovery provider registration for %s: %w", discoveryProvider, err)
+	}
+
+	candidates := []string{}
+	for _, supportedProtocol := range discoReg.SupportedIdentityProviders {
+		if !registry.IsPluginDisabled(supportedProtocol) {
+			candidates = append(candidates, supportedProtocol)
+		}
+	}
+	if len(candidates) == 0 {
+		return nil, nil
+	}
+
+	if selection := selectFromHistory(discoveryProvider, candidates, store); selection != nil {
+		return selection, nil
+	}
+
+	if selection := selectFromEnvironment(discoveryProvider, candidates); selection != nil {
+		return selection, nil
+	}
+
+	return &IdentityProviderSelection{
+		Name:   candidates[0],
+		Reason: "first supported identity provider",
+	}, nil
+}
+
+func selectFromHistory(discoveryProvider string, candidates []string, store history.Store) *IdentityProviderSelection {
+	if store == nil {
+		return nil
+	}
+
+	entries, err := store.GetByProvider(discoveryProvider)
+	if err != nil {
+		zap.S().Debugw("ignoring history when selecting identity provider", "error", err.Error())
+		return nil
+	}
+	sort.Slice(entries, func(i, j int) bool {
+		return entries[j].Status.LastUsed.Before(&entries[i].Status.LastUsed)
+	})
+
+	for _, entry := range entries {
+		for _, candidate := range candidates {
+			if entry.Spec.Identity == candidate {
+				return &IdentityProviderSelection{
+					Name:   candidate,
+					Reason: fmt.Sprintf("used in history entry %s", entry.Name),
+				}
+			}
+		}
+	}
+
+	return nil
+}
+
+func selectFromEnvironment(discoveryProvider string, candidates []string) *IdentityProviderSelection {
+	var selection *IdentityProviderSelection
+
+	for _, candidate := range candidates {
+		idReg, err := registry.GetIdentityProviderRegistration(candidate)
+		if err != nil || idReg.DetectFunc == nil {
+			continue
+		}
+		reason, detected := idReg.DetectFunc(discoveryProvider)
+		if !detected {
+			continue
+		}
+		zap.S().Debugw("detected identity provider", "idp-protocol", candidate, "reason", reason)
+
+		if selection == nil {
+			selection = &IdentityProviderSelection{
+				Name:   candidate,
+				Reason: reason,
+			}
+		} else {
+			selection.Alternatives = append(selection.Alternatives, candidate)
+		}
+	}
+
+	return selection
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/clientcmd"
+
+	apiv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/defaults"
+)
+
+// GroupPrefix is the prefix that refers to an alias group instead of an alias
+const GroupPrefix = "@"
+
+// KubeconfigPathInput defines the inputs for KubeconfigPath
+type KubeconfigPathInput struct {
+	CommonConfig
+	HistoryLocationConfig
+	KubernetesConfig
+
+	// Targets are the aliases and alias groups (prefixed with @) to get the kubeconfig files of
+	Targets []string
+}
+
+// KubeconfigPath will print a KUBECONFIG compatible list of the kubeconfig files that
+// the connection history entries of aliases and alias groups are written to. The paths
+// are separated with the path list separator of the OS.
+func (a *App) KubeconfigPath(ctx context.Context, input *KubeconfigPathInput) error {
+	zap.S().Infow("getting kubeconfig path list", "targets", input.Targets)
+
+	paths, err := a.kubeconfigPaths(input.Targets, input.Kubeconfig)
+	if err != nil {
+		return err
+	}
+
+	return writePathList(os.Stdout, paths)
+}
+
+func (a *App) kubeconfigPaths(targets []string, defaultKubeconfig string) ([]string, error) {
+	if len(targets) == 0 {
+		return nil, ErrAliasRequired
+	}
+	if defaultKubeconfig == "" {
+		defaultKubeconfig = clientcmd.NewDefaultPathOptions().GetDefaultFilename()
+	}
+
+	paths := []string{}
+	seen := map[string]bool{}
+	for _, target := range targets {
+		entries, err := a.targetEntries(target)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, entry := range entries {
+			path := entryKubeconfig(entry, defaultKubeconfig)
+			if seen[path] {
+				continue
+			}
+			seen[path] = true
+			paths = append(paths, path)
+		}
+	}
+
+	return paths, nil
+}
+
+// targetEntries gets the history entries of an alias, or of the aliases in a group if
+// the target is prefixed with @
+func (a *App) targetEntries(target string) ([]*apiv1alpha.HistoryEntry, error) {
+	if !strings.HasPrefix(target, GroupPrefix) {
+		entry, err := a.historyStore.GetByAlias(target)
+		if err != nil {
+			return nil, fmt.Errorf("getting history entry by alias %s: %w", target, err)
+		}
+		if entry == nil {
+			return nil, fmt.Errorf("alias %s: %w", target, ErrAliasNotFound)
+		}
+
+		return []*apiv1alpha.HistoryEntry{entry}, nil
+	}
+
+	group := strings.TrimPrefix(target, GroupPrefix)
+	list, err := a.historyStore.GetAllSortedByLastUsed()
+	if err != nil {
+		return nil, fmt.Errorf("getting history entries: %w", err)
+	}
+
+	entries := []*apiv1alpha.HistoryEntry{}
+	for i := range list.Items {
+		if list.Items[i].Spec.Group == group {
+			entries = append(entries, &list.Items[i])
+		}
+	}
+	if len(entries) == 0 {
+		return nil, fmt.Errorf("alias group %s: %w", group, ErrGroupNotFound)
+	}
+
+	return entries, nil
+}
+
+// entryKubeconfig returns the kubeconfig file that connecting to the entry writes to
+func entryKubeconfig(entry *apiv1alpha.HistoryEntry, defaultKubeconfig string) string {
+	path := entry.Spec.Kubeconfig
+	if path == "" {
+		path = entry.Spec.ConfigFile
+	}
+	if path == "" {
+		path = defaultKubeconfig
+	}
+
+	return filepath.Clean(defaults.ExpandPath(path))
+}
+
+func writePathList(w io.Writer, paths []string) error {
+	if _, err := fmt.Fprintln(w, strings.Join(paths, string(os.PathListSeparator))); err != nil {
+		return fmt.Errorf("writing path list: %w", err)
+	}
+
+	return nil
+}
@@ -19,12 +19,14 @@ package app
 import (
 	"context"
 	"errors"
-	"strings"
 
 	historyv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/defaults"
 	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+	"github.com/fidelity/kconnect/pkg/rancher"
 	"go.uber.org/zap"
 	"gopkg.in/ini.v1"
+	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/fidelity/kconnect/pkg/aws/awsconfig"
 )
@@ -35,8 +37,8 @@ type LogoutInput struct {
 	KubernetesConfig
 
 	All   bool
-	Alias string
-	IDs   string
+	Alias []string
+	IDs   []string
 }
 
 func (a *App) Logout(ctx context.Context, params *LogoutInput) error {
@@ -68,9 +70,8 @@ func (a *App) getClustersToLogout(params *LogoutInput) (*historyv1alpha.HistoryE
 		if err != nil {
 			return nil, err
 		}
-	case params.Alias != "":
-		aliasList := strings.Split(params.Alias, ",")
-		for _, alias := range aliasList {
+	case len(params.Alias) > 0 || len(params.IDs) > 0:
+		for _, alias := range params.Alias {
 			entry, err := a.historyStore.GetByAlias(alias)
 			if err != nil {
 				return nil, err
@@ -80,10 +81,7 @@ func (a *App) getClustersToLogout(params *LogoutInput) (*historyv1alpha.HistoryE
 			}
 			entries.Items = append(entries.Items, *entry)
 		}
-		fallthrough
-	case params.IDs != "":
-		idsList := strings.Split(params.IDs, ",")
-		for _, id := range idsList {
+		for _, id := range params.IDs {
 			entry, err := a.historyStore.GetByID(id)
 			if err != nil {
 				return nil, err
@@ -132,6 +130,11 @@ func (a *App) doLogout(params *LogoutInput, entry *historyv1alpha.HistoryEntry)
 func (a *App) doLogoutEKS(entry *historyv1alpha.HistoryEntry) error {
 
 	zap.S().Infof("logging out of entry (eks): name: %s, alias: %s", entry.Name, *entry.Spec.Alias)
+	// With the aws-iam identity the profile belongs to the user and so it's kept
+	if entry.Spec.Identity != "saml" {
+		zap.S().Infof("keeping aws profile for entry %s as it wasn't created by kconnect", entry.Name)
+		return nil
+	}
 	profileName, ok := entry.Spec.Flags["aws-profile"]
 	if !ok {
 		zap.S().Infof("no aws profile name found for entry %s", entry.Name)
@@ -158,25 +161,70 @@ func (a *App) doLogoutAKS(params *LogoutInput, entry *historyv1alpha.HistoryEntr
 func (a *App) doLogoutRancher(params *LogoutInput, entry *historyv1alpha.HistoryEntry) error {
 
 	zap.S().Infof("logging out of entry (rancher): name: %s, alias: %s", entry.Name, *entry.Spec.Alias)
+	if err := a.revokeRancherTokens(params.Kubeconfig, entry); err != nil {
+		zap.S().Warnw("failed revoking rancher tokens", "entry", entry.Name, "error", err.Error())
+	}
 	return a.deleteUserFromKubeconfigByEntryID(params.Kubeconfig, entry.Name)
 }
 
-func (a *App) deleteUserFromKubeconfigByEntryID(kubeconfigPath, entryID string) error {
+// revokeRancherTokens revokes the tokens kconnect created for the entry, which are the
+// token in the kubeconfig and the stored login token of the user.
+func (a *App) revokeRancherTokens(kubeconfigPath string, entry *historyv1alpha.HistoryEntry) error {
+	apiEndpoint := entry.Spec.Flags[rancher.APIEndpointConfigName]
+	username := entry.Spec.Flags[defaults.UsernameConfigItem]
+	if entry.Spec.Identity != "rancher-ad" || apiEndpoint == "" {
+		return nil
+	}
+	authProvider := rancher.AuthProviderActiveDirectory
+	if flag, ok := entry.Spec.Flags[rancher.AuthProviderConfigName]; ok {
+		authProvider = rancher.AuthProvider(flag)
+	}
+
+	tokenStore := rancher.NewTokenStore()
+	storedToken, err := tokenStore.Get(apiEndpoint, authProvider, username)
+	if err != nil {
+		return err
+	}
+	if storedToken == nil {
+		zap.S().Infof("no stored rancher token found for entry %s", entry.Name)
+		return nil
+	}
+
+	resolver, err := rancher.NewStaticEndpointsResolver(apiEndpoint)
+	if err != nil {
+		return err
+	}
 
 	config, err := kubeconfig.Read(kubeconfigPath)
 	if err != nil {
 		return err
 	}
-	kubeconfigUser := ""
-	for context := range config.Contexts {
-		historyRef, err := historyv1alpha.GetHistoryReferenceFromContext(config.Contexts[context])
-		if err != nil && errors.Is(err, historyv1alpha.ErrNoHistoryExtension) {
+	kubeconfigUser, err := kubeconfigUserForEntry(config, entry.Name)
+	if err != nil {
+		return err
+	}
+	if authInfo, ok := config.AuthInfos[kubeconfigUser]; ok && authInfo.Token != "" {
+		if err := rancher.RevokeToken(a.httpClient, resolver, storedToken.Token, rancher.TokenName(authInfo.Token)); err != nil {
 			return err
 		}
-		if historyRef.EntryID == entryID {
-			kubeconfigUser = config.Contexts[context].AuthInfo
-			break
-		}
+	}
+
+	if err := rancher.RevokeToken(a.httpClient, resolver, storedToken.Token, storedToken.Name()); err != nil {
+		return err
+	}
+
+	return tokenStore.Delete(apiEndpoint, authProvider, username)
+}
+
+func (a *App) deleteUserFromKubeconfigByEntryID(kubeconfigPath, entryID string) error {
+
+	config, err := kubeconfig.Read(kubeconfigPath)
+	if err != nil {
+		return err
+	}
+	kubeconfigUser, err := kubeconfigUserForEntry(config, entryID)
+	if err != nil {
+		return err
 	}
 	if kubeconfigUser == "" {
 		zap.S().Infof("no user found in kubeconfig for entry: %S", entryID)
@@ -185,3 +233,17 @@ func (a *App) deleteUserFromKubeconfigByEntryID(kubeconfigPath, entryID string)
 	delete(config.AuthInfos, kubeconfigUser)
 	return kubeconfig.Write(kubeconfigPath, config, false, false)
 }
+
+func kubeconfigUserForEntry(config *api.Config, entryID string) (string, error) {
+	for context := range config.Contexts {
+		historyRef, err := historyv1alpha.GetHistoryReferenceFromContext(config.Contexts[context])
+		if err != nil && errors.Is(err, historyv1alpha.ErrNoHistoryExtension) {
+			return "", err
+		}
+		if historyRef.EntryID == entryID {
+			return config.Contexts[context].AuthInfo, nil
+		}
+	}
+
+	return "", nil
+}
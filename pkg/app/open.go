@@ -0,0 +1,137 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+
+	"go.uber.org/zap"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/provider"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	OpenWithK9s       = "k9s"
+	OpenWithLens      = "lens"
+	OpenWithDashboard = "dashboard"
+)
+
+type OpenInput struct {
+	ConnectToInput
+	OpenConfig
+}
+
+// Open reconnects to the cluster of a history entry, so that the credentials
+// are fresh, and then opens the cluster with another tool. The tools are given
+// an isolated kubeconfig that only contains the context for the cluster.
+func (a *App) Open(ctx context.Context, params *OpenInput) error {
+	zap.S().Debugw("opening cluster", "with", params.With)
+
+	useParams, err := a.connectIsolated(ctx, &params.ConnectToInput)
+	if err != nil {
+		return err
+	}
+	kubeconfigPath := useParams.Kubeconfig
+
+	switch params.With {
+	case OpenWithK9s:
+		return openWithK9s(kubeconfigPath)
+	case OpenWithLens:
+		return openWithLens(kubeconfigPath)
+	case OpenWithDashboard:
+		return a.openDashboard(useParams)
+	default:
+		return fmt.Errorf("opening with %s: %w", params.With, ErrUnsupportedOpenWith)
+	}
+}
+
+// openWithK9s runs k9s in the terminal until the user quits it
+func openWithK9s(kubeconfigPath string) error {
+	k9sPath, err := exec.LookPath("k9s")
+	if err != nil {
+		return kerrors.WithCode(kerrors.CodePrereqMissing, fmt.Errorf("finding k9s: %w", err))
+	}
+
+	cmd := exec.Command(k9sPath, "--kubeconfig", kubeconfigPath) //nolint: gosec
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stdout
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running k9s: %w", err)
+	}
+
+	return nil
+}
+
+// openWithLens starts Lens with the isolated kubeconfig. Lens is a desktop
+// app so kconnect doesn't wait for it to exit.
+func openWithLens(kubeconfigPath string) error {
+	var cmd *exec.Cmd
+	if lensPath, err := exec.LookPath("lens"); err == nil {
+		cmd = exec.Command(lensPath) //nolint: gosec
+	} else if runtime.GOOS == "darwin" {
+		cmd = exec.Command("open", "-a", "Lens")
+	} else {
+		return kerrors.WithCode(kerrors.CodePrereqMissing, fmt.Errorf("finding lens: %w", err))
+	}
+	cmd.Env = append(os.Environ(), fmt.Sprintf("KUBECONFIG=%s", kubeconfigPath))
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("starting lens: %w", err)
+	}
+	fmt.Fprintf(os.Stderr, "Lens started, if it was already running add the kubeconfig %s to it\n", kubeconfigPath)
+
+	return cmd.Process.Release()
+}
+
+// openDashboard opens the managed dashboard of the cluster in the browser
+func (a *App) openDashboard(params *UseInput) error {
+	prov, err := registry.GetDiscoveryProvider(params.DiscoveryProvider, &provider.PluginCreationInput{
+		Logger:        a.logger.Named(params.DiscoveryProvider).With("provider", params.DiscoveryProvider),
+		IsInteractice: a.interactive,
+		ItemSelector:  a.itemSelector,
+		ScopedTo:      &params.IdentityProvider,
+	})
+	if err != nil {
+		return fmt.Errorf("getting discovery provider %s: %w", params.DiscoveryProvider, err)
+	}
+
+	resolver, ok := prov.(discovery.DashboardResolver)
+	if !ok {
+		return fmt.Errorf("opening dashboard for %s: %w", params.DiscoveryProvider, ErrNoDashboard)
+	}
+	dashboardURL, err := resolver.DashboardURL(params.ConfigSet, *params.ClusterID)
+	if err != nil {
+		return fmt.Errorf("getting dashboard url: %w", err)
+	}
+
+	fmt.Fprintln(os.Stdout, dashboardURL)
+	if err := utils.OpenBrowser(dashboardURL); err != nil {
+		a.logger.Warnw("failed opening the dashboard in the browser", "error", err.Error())
+	}
+
+	return nil
+}
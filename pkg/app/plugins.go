@@ -0,0 +1,351 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kconnectv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/printer"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+const (
+	PluginTypeDiscovery = "discovery"
+	PluginTypeIdentity  = "identity"
+
+	pluginSourceBuiltIn = "built-in"
+	pluginEnabled       = "enabled"
+	pluginDisabled      = "disabled"
+)
+
+// PluginsListInput defines the inputs for PluginsList
+type PluginsListInput struct {
+	CommonConfig
+	Output *printer.OutputPrinter `json:"output,omitempty"`
+}
+
+// PluginDescribeInput defines the inputs for PluginDescribe
+type PluginDescribeInput struct {
+	CommonConfig
+	Name   string
+	Output *printer.OutputPrinter `json:"output,omitempty"`
+}
+
+// PluginToggleInput defines the inputs for PluginEnable and PluginDisable
+type PluginToggleInput struct {
+	CommonConfig
+	Name string
+}
+
+// PluginInfo is the details of a registered plugin
+type PluginInfo struct {
+	Name                       string               `json:"name"`
+	Type                       string               `json:"type"`
+	Source                     string               `json:"source"`
+	Status                     string               `json:"status"`
+	APIVersion                 string               `json:"apiVersion,omitempty"`
+	Capabilities               []string             `json:"capabilities,omitempty"`
+	SupportedIdentityProviders []string             `json:"supportedIdentityProviders,omitempty"`
+	UsageExample               string               `json:"usageExample,omitempty"`
+	ConfigurationItems         []*PluginItemDetails `json:"configurationItems,omitempty"`
+}
+
+// PluginItemDetails is the details of a configuration item of a plugin
+type PluginItemDetails struct {
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	Default       string   `json:"default,omitempty"`
+	Required      bool     `json:"required,omitempty"`
+	Sensitive     bool     `json:"sensitive,omitempty"`
+	AllowedValues []string `json:"allowedValues,omitempty"`
+	Description   string   `json:"description,omitempty"`
+}
+
+// PluginsList will list the registered discovery and identity plugins
+func (a *App) PluginsList(ctx context.Context, input *PluginsListInput) error {
+	zap.S().Debug("listing plugins")
+
+	plugins, err := listPlugins(false)
+	if err != nil {
+		return err
+	}
+
+	objPrinter, err := printer.New(*input.Output)
+	if err != nil {
+		return fmt.Errorf("getting printer for output %s: %w", *input.Output, err)
+	}
+
+	if *input.Output == printer.OutputPrinterTable {
+		return objPrinter.Print(pluginsTable(plugins), os.Stdout)
+	}
+
+	return objPrinter.Print(plugins, os.Stdout)
+}
+
+// PluginDescribe will show the details of a plugin including its configuration items
+func (a *App) PluginDescribe(ctx context.Context, input *PluginDescribeInput) error {
+	zap.S().Debugw("describing plugin", "name", input.Name)
+
+	plugins, err := listPlugins(true)
+	if err != nil {
+		return err
+	}
+
+	matched := []*PluginInfo{}
+	for _, plugin := range plugins {
+		if plugin.Name == input.Name {
+			matched = append(matched, plugin)
+		}
+	}
+	if len(matched) == 0 {
+		return fmt.Errorf("describing plugin %s: %w", input.Name, registry.ErrPluginNotFound)
+	}
+
+	objPrinter, err := printer.New(*input.Output)
+	if err != nil {
+		return fmt.Errorf("getting printer for output %s: %w", *input.Output, err)
+	}
+
+	if *input.Output != printer.OutputPrinterTable {
+		return objPrinter.Print(matched, os.Stdout)
+	}
+
+	for _, plugin := range matched {
+		fmt.Fprintf(os.Stdout, "Name:                 %s\n", plugin.Name)
+		fmt.Fprintf(os.Stdout, "Type:                 %s\n", plugin.Type)
+		fmt.Fprintf(os.Stdout, "Source:               %s\n", plugin.Source)
+		fmt.Fprintf(os.Stdout, "Status:               %s\n", plugin.Status)
+		fmt.Fprintf(os.Stdout, "API Version:          %s\n", plugin.APIVersion)
+		fmt.Fprintf(os.Stdout, "Capabilities:         %s\n", strings.Join(plugin.Capabilities, ", "))
+		if plugin.Type == PluginTypeDiscovery {
+			fmt.Fprintf(os.Stdout, "Identity Providers:   %s\n", strings.Join(plugin.SupportedIdentityProviders, ", "))
+		}
+		fmt.Fprintln(os.Stdout, "Configuration Items:")
+		if err := objPrinter.Print(pluginItemsTable(plugin.ConfigurationItems), os.Stdout); err != nil {
+			return fmt.Errorf("printing configuration items: %w", err)
+		}
+		fmt.Fprintln(os.Stdout)
+	}
+
+	return nil
+}
+
+// PluginEnable will remove a plugin from the disabled plugins in the app configuration
+func (a *App) PluginEnable(ctx context.Context, input *PluginToggleInput) error {
+	return a.updatePluginPolicy(input, func(disabled []string) []string {
+		enabled := []string{}
+		for _, name := range disabled {
+			if name != input.Name {
+				enabled = append(enabled, name)
+			}
+		}
+		return enabled
+	})
+}
+
+// PluginDisable will add a plugin to the disabled plugins in the app configuration
+func (a *App) PluginDisable(ctx context.Context, input *PluginToggleInput) error {
+	if !isPluginRegistered(input.Name) {
+		return fmt.Errorf("disabling plugin %s: %w", input.Name, registry.ErrPluginNotFound)
+	}
+
+	return a.updatePluginPolicy(input, func(disabled []string) []string {
+		for _, name := range disabled {
+			if name == input.Name {
+				return disabled
+			}
+		}
+		disabled = append(disabled, input.Name)
+		sort.Strings(disabled)
+		return disabled
+	})
+}
+
+func (a *App) updatePluginPolicy(input *PluginToggleInput, update func(disabled []string) []string) error {
+	appConfig, err := config.NewAppConfigurationWithPath(input.ConfigFile)
+	if err != nil {
+		return fmt.Errorf("creating app config: %w", err)
+	}
+
+	cfg, err := appConfig.Get()
+	if err != nil {
+		return fmt.Errorf("getting app config: %w", err)
+	}
+
+	if cfg.Spec.Plugins == nil {
+		cfg.Spec.Plugins = &kconnectv1alpha.PluginPolicy{}
+	}
+	cfg.Spec.Plugins.Disabled = update(cfg.Spec.Plugins.Disabled)
+	if len(cfg.Spec.Plugins.Disabled) == 0 {
+		cfg.Spec.Plugins = nil
+	}
+
+	if err := appConfig.Save(cfg); err != nil {
+		return fmt.Errorf("saving app config: %w", err)
+	}
+	zap.S().Infow("updated plugin policy", "config", input.ConfigFile)
+
+	return nil
+}
+
+func isPluginRegistered(name string) bool {
+	if _, err := registry.GetDiscoveryProviderRegistration(name); err == nil {
+		return true
+	}
+	_, err := registry.GetIdentityProviderRegistration(name)
+
+	return err == nil
+}
+
+func listPlugins(includeItems bool) ([]*PluginInfo, error) {
+	plugins := []*PluginInfo{}
+
+	for _, discoReg := range registry.ListDiscoveryPluginRegistrations() {
+		info, err := newPluginInfo(PluginTypeDiscovery, &discoReg.PluginRegistration, includeItems)
+		if err != nil {
+			return nil, err
+		}
+		info.SupportedIdentityProviders = discoReg.SupportedIdentityProviders
+		plugins = append(plugins, info)
+	}
+	for _, idReg := range registry.ListIdentityPluginRegistrations() {
+		info, err := newPluginInfo(PluginTypeIdentity, &idReg.PluginRegistration, includeItems)
+		if err != nil {
+			return nil, err
+		}
+		plugins = append(plugins, info)
+	}
+
+	sort.Slice(plugins, func(i, j int) bool {
+		if plugins[i].Type != plugins[j].Type {
+			return plugins[i].Type < plugins[j].Type
+		}
+		return plugins[i].Name < plugins[j].Name
+	})
+
+	return plugins, nil
+}
+
+func newPluginInfo(pluginType string, registration *registry.PluginRegistration, includeItems bool) (*PluginInfo, error) {
+	info := &PluginInfo{
+		Name:         registration.Name,
+		Type:         pluginType,
+		Source:       pluginSourceBuiltIn,
+		Status:       pluginEnabled,
+		APIVersion:   registration.APIVersion,
+		UsageExample: registration.UsageExample,
+	}
+	if registration.Path != "" {
+		info.Source = registration.Path
+	}
+	if registry.IsPluginDisabled(registration.Name) {
+		info.Status = pluginDisabled
+	}
+	for _, capability := range registration.Capabilities {
+		info.Capabilities = append(info.Capabilities, string(capability))
+	}
+
+	if !includeItems {
+		return info, nil
+	}
+
+	cs, err := registration.ConfigurationItemsFunc("")
+	if err != nil {
+		return nil, fmt.Errorf("getting configuration items for %s: %w", registration.Name, err)
+	}
+	items := cs.GetAll()
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	for _, item := range items {
+		if item.Hidden {
+			continue
+		}
+		info.ConfigurationItems = append(info.ConfigurationItems, &PluginItemDetails{
+			Name:          item.Name,
+			Type:          string(item.Type),
+			Default:       (&config.Item{Type: item.Type, Value: item.DefaultValue}).ValueString(),
+			Required:      item.Required,
+			Sensitive:     item.Sensitive,
+			AllowedValues: item.AllowedValues,
+			Description:   item.Description,
+		})
+	}
+
+	return info, nil
+}
+
+func pluginsTable(plugins []*PluginInfo) *metav1.Table {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: metav1.SchemeGroupVersion.String(),
+			Kind:       "Table",
+		},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "Type", Type: "string"},
+			{Name: "Status", Type: "string"},
+			{Name: "Identity Providers", Type: "string"},
+			{Name: "Source", Type: "string"},
+		},
+	}
+
+	for _, plugin := range plugins {
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{plugin.Name, plugin.Type, plugin.Status, strings.Join(plugin.SupportedIdentityProviders, ","), plugin.Source},
+		})
+	}
+
+	return table
+}
+
+func pluginItemsTable(items []*PluginItemDetails) *metav1.Table {
+	table := &metav1.Table{
+		TypeMeta: metav1.TypeMeta{
+			APIVersion: metav1.SchemeGroupVersion.String(),
+			Kind:       "Table",
+		},
+		ColumnDefinitions: []metav1.TableColumnDefinition{
+			{Name: "Name", Type: "string"},
+			{Name: "Type", Type: "string"},
+			{Name: "Default", Type: "string"},
+			{Name: "Required", Type: "string"},
+			{Name: "Description", Type: "string"},
+		},
+	}
+
+	for _, item := range items {
+		required := ""
+		if item.Required {
+			required = "yes"
+		}
+		table.Rows = append(table.Rows, metav1.TableRow{
+			Cells: []interface{}{item.Name, item.Type, item.Default, required, item.Description},
+		})
+	}
+
+	return table
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+
+	kconnectv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/audit"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/defaults"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+)
+
+// loadClusterPolicy returns the cluster policy in the app config for the discovery
+// provider. It returns nil if there is no policy for the provider.
+func loadClusterPolicy(configFile, providerName string) (*discovery.ClusterPolicy, error) {
+	if configFile == "" {
+		configFile = defaults.ConfigPath()
+	}
+	appConfig, err := config.NewAppConfigurationWithPath(configFile)
+	if err != nil {
+		return nil, fmt.Errorf("creating app config: %w", err)
+	}
+	cfg, err := appConfig.Get()
+	if err != nil {
+		return nil, fmt.Errorf("getting app config: %w", err)
+	}
+
+	policy, ok := cfg.Spec.ClusterPolicies[providerName]
+	if !ok {
+		return nil, nil
+	}
+
+	return &discovery.ClusterPolicy{
+		Allow: clusterPatterns(policy.Allow),
+		Deny:  clusterPatterns(policy.Deny),
+	}, nil
+}
+
+func clusterPatterns(patterns []kconnectv1alpha.ClusterPattern) []discovery.ClusterPattern {
+	converted := make([]discovery.ClusterPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		converted = append(converted, discovery.ClusterPattern{
+			Name: pattern.Name,
+			Tags: pattern.Tags,
+		})
+	}
+
+	return converted
+}
+
+// enforceClusterPolicy checks the clusters that are being connected to against the
+// cluster policy. This also covers clusters that aren't discovered, e.g. when
+// reconnecting to a history entry. If the policy is overridden the connection to a
+// denied cluster is recorded in the audit log and the command fails if it can't be.
+func (a *App) enforceClusterPolicy(input *UseInput, clusters []*discovery.Cluster) error {
+	for _, cluster := range clusters {
+		err := input.clusterPolicy.Check(cluster)
+		if err == nil {
+			continue
+		}
+		if !input.OverridePolicy {
+			return fmt.Errorf("connecting to %s, use --%s to override the policy: %w", cluster.Name, OverridePolicyConfigItem, err)
+		}
+
+		reason := err.Error()
+		a.logger.Warnw("overriding the cluster policy, the connection is recorded in the audit log", "cluster", cluster.Name, "reason", reason)
+
+		record := audit.NewRecord(audit.ActionPolicyOverride)
+		record.Provider = input.DiscoveryProvider
+		record.Cluster = cluster.Name
+		record.ClusterID = cluster.ID
+		record.Reason = reason
+		if err := audit.Write(defaults.AuditLogPath(), record); err != nil {
+			return fmt.Errorf("recording cluster policy override: %w", err)
+		}
+	}
+
+	return nil
+}
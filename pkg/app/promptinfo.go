@@ -0,0 +1,156 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+	"unicode"
+
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+)
+
+const (
+	// PromptShellNone outputs the color codes without escaping them
+	PromptShellNone = "none"
+	// PromptShellBash escapes the color codes for PS1 in bash
+	PromptShellBash = "bash"
+	// PromptShellZsh escapes the color codes for PROMPT in zsh
+	PromptShellZsh = "zsh"
+
+	// promptInfoUnknown is shown for the fields that aren't known
+	promptInfoUnknown = "-"
+	// promptExpiryWarning is how long before the credentials expire that the time left
+	// is colored as a warning
+	promptExpiryWarning = 15 * time.Minute
+
+	colorRed    = "\033[31m"
+	colorYellow = "\033[33m"
+	colorGreen  = "\033[32m"
+	colorReset  = "\033[0m"
+)
+
+// productionEnvironments and testEnvironments are the environment labels that are
+// colored red and yellow, any other environment is green
+var (
+	productionEnvironments = map[string]bool{"prod": true, "production": true, "prd": true, "live": true}
+	testEnvironments       = map[string]bool{"uat": true, "stage": true, "staging": true, "stg": true, "test": true, "qa": true, "preprod": true}
+)
+
+type PromptInfoInput struct {
+	CommonConfig
+	HistoryLocationConfig
+	KubernetesConfig
+	PromptInfoConfig
+}
+
+// PromptInfo writes the alias, environment and credentials time left of the current
+// context as a single line, e.g. "uat-bu1 uat 45m", for showing in a shell prompt. The
+// fields that aren't known are -. Nothing is written if the current context wasn't
+// created by kconnect.
+func (a *App) PromptInfo(ctx context.Context, params *PromptInfoInput) error {
+	cfg, err := kubeconfig.Read(params.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("reading kubeconfig: %w", err)
+	}
+	contexts, err := a.kconnectContexts(cfg)
+	if err != nil {
+		return fmt.Errorf("getting kconnect contexts: %w", err)
+	}
+
+	var current *kconnectContext
+	for _, kconnectCtx := range contexts {
+		if kconnectCtx.name == cfg.CurrentContext {
+			current = kconnectCtx
+			break
+		}
+	}
+	if current == nil {
+		zap.S().Debugw("current context wasn't created by kconnect", "context", cfg.CurrentContext)
+		return nil
+	}
+
+	name := current.alias
+	if name == "" {
+		name = current.name
+	}
+	environment, timeLeft, expiresSoon := promptInfoUnknown, promptInfoUnknown, false
+	if current.entry != nil {
+		if label := current.entry.EnvironmentLabel(); label != "" {
+			environment = label
+		}
+		if left := current.entry.CredentialsTimeLeft(); left != "" {
+			timeLeft = left
+			expiresSoon = time.Until(current.entry.Status.CredentialsExpiry.Time) < promptExpiryWarning
+		}
+	}
+
+	if params.Color {
+		if environment != promptInfoUnknown {
+			environment = promptColor(environmentColor(environment), environment, params.Shell)
+		}
+		if expiresSoon {
+			color := colorYellow
+			if current.entry.CredentialsExpired() {
+				color = colorRed
+			}
+			timeLeft = promptColor(color, timeLeft, params.Shell)
+		}
+	}
+
+	_, err = fmt.Fprintln(os.Stdout, strings.Join([]string{name, environment, timeLeft}, " "))
+
+	return err
+}
+
+// environmentColor returns the color for the environment, red for production, yellow
+// for test environments and green for anything else. The words of the environment are
+// compared so that e.g. payments-prod is production but nonprod isn't.
+func environmentColor(environment string) string {
+	words := strings.FieldsFunc(strings.ToLower(environment), func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	color := colorGreen
+	for _, word := range words {
+		if productionEnvironments[word] {
+			return colorRed
+		}
+		if testEnvironments[word] {
+			color = colorYellow
+		}
+	}
+
+	return color
+}
+
+// promptColor colors the value, the color codes are escaped for the shell so that it
+// doesn't count them towards the length of the prompt
+func promptColor(color, value, shell string) string {
+	switch shell {
+	case PromptShellBash:
+		return `\[` + color + `\]` + value + `\[` + colorReset + `\]`
+	case PromptShellZsh:
+		return "%{" + color + "%}" + value + "%{" + colorReset + "%}"
+	default:
+		return color + value + colorReset
+	}
+}
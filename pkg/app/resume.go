@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fidelity/kconnect/pkg/cache"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/secrets"
+)
+
+const (
+	resumeCacheKey = "use-resume"
+	// resumeStateTTL is how long a failed use command can be resumed for, the identity
+	// may expire before then
+	resumeStateTTL = time.Hour
+)
+
+// UseResumeInput are the parameters to resume a use command that failed
+type UseResumeInput struct {
+	CommonConfig
+	HistoryConfig
+}
+
+// resumeState is the state of a use command that is saved after each phase
+// that succeeds, so that the command can be resumed if a later phase fails
+type resumeState struct {
+	DiscoveryProvider string            `json:"discoveryProvider"`
+	IdentityProvider  string            `json:"identityProvider"`
+	Flags             map[string]string `json:"flags"`
+	SetCurrent        bool              `json:"setCurrent"`
+	NoHistory         bool              `json:"noHistory,omitempty"`
+	Alias             string            `json:"alias,omitempty"`
+	ClusterID         string            `json:"clusterID,omitempty"`
+	Identity          json.RawMessage   `json:"identity"`
+}
+
+// UseResume resumes the last use command that failed after authenticating, using
+// the saved identity instead of authenticating again
+func (a *App) UseResume(ctx context.Context, input *UseResumeInput) error {
+	state := &resumeState{}
+	found, err := a.resumeCache().Get(resumeCacheKey, state)
+	if err != nil {
+		return fmt.Errorf("getting saved use state: %w", err)
+	}
+	if !found {
+		return ErrNothingToResume
+	}
+
+	cs, err := a.buildConnectToConfig(input.ConfigFile, state.DiscoveryProvider, state.IdentityProvider, state.Flags)
+	if err != nil {
+		return fmt.Errorf("building use config set: %w", err)
+	}
+
+	useParams := &UseInput{
+		IdentityProvider:  state.IdentityProvider,
+		DiscoveryProvider: state.DiscoveryProvider,
+		ConfigSet:         cs,
+		resume:            state,
+	}
+	if err := config.Unmarshall(cs, useParams); err != nil {
+		return fmt.Errorf("unmarshalling config into use params: %w", err)
+	}
+
+	useParams.HistoryConfig = input.HistoryConfig
+	useParams.NoHistory = input.NoHistory || state.NoHistory
+	useParams.SetCurrent = state.SetCurrent
+	if state.ClusterID != "" {
+		useParams.ClusterID = &state.ClusterID
+	}
+	if state.Alias != "" {
+		useParams.Alias = &state.Alias
+		useParams.IgnoreAlias = true
+	}
+
+	a.logger.Infow("resuming use command", "provider", state.DiscoveryProvider, "idp-protocol", state.IdentityProvider)
+
+	return a.Use(ctx, useParams)
+}
+
+// useIdentity authenticates using the identity provider, or restores the identity saved
+// by the use command that's being resumed. New connections are saved after each phase
+// if the identity provider can restore its identities.
+func (a *App) useIdentity(ctx context.Context, identityProvider identity.Provider, clusterProvider discovery.Provider, input *UseInput) (identity.Identity, error) {
+	restorer, canRestore := identityProvider.(identity.Restorer)
+
+	var id identity.Identity
+	var err error
+	if input.resume != nil && canRestore {
+		id, err = a.restoreIdentity(restorer, input.resume.Identity)
+	} else {
+		id, err = a.authenticateIdentity(ctx, identityProvider, input.ConfigSet)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Connections from the history and in CI jobs aren't saved as they don't prompt
+	if canRestore && input.EntryID == "" && a.ciEnv == nil {
+		data, err := json.Marshal(id)
+		if err != nil {
+			a.logger.Debugw("identity can't be saved, the use command can't be resumed", "error", err.Error())
+		} else {
+			input.resumeIdentity = data
+		}
+	}
+	a.saveResumeState(input, "")
+
+	if err := a.resolveDiscoveryConfig(ctx, clusterProvider, input.ConfigSet, id); err != nil {
+		return nil, err
+	}
+	a.saveResumeState(input, "")
+
+	return id, nil
+}
+
+func (a *App) restoreIdentity(restorer identity.Restorer, data json.RawMessage) (identity.Identity, error) {
+	id, err := restorer.RestoreIdentity(data)
+	if err != nil {
+		return nil, fmt.Errorf("restoring saved identity: %w", err)
+	}
+	if id.IsExpired() {
+		if err := a.resumeCache().Delete(resumeCacheKey); err != nil {
+			a.logger.Debugw("failed removing saved use state", "error", err.Error())
+		}
+		return nil, ErrResumeIdentityExpired
+	}
+
+	return id, nil
+}
+
+// saveResumeState saves the state of the use command so it can be resumed. Saving is
+// best effort, a failure doesn't fail the command.
+func (a *App) saveResumeState(input *UseInput, clusterID string) {
+	if input.resumeIdentity == nil {
+		return
+	}
+
+	state := &resumeState{
+		DiscoveryProvider: input.DiscoveryProvider,
+		IdentityProvider:  input.IdentityProvider,
+		Flags:             map[string]string{},
+		SetCurrent:        input.SetCurrent,
+		NoHistory:         input.NoHistory,
+		ClusterID:         clusterID,
+		Identity:          input.resumeIdentity,
+	}
+	for _, configItem := range input.ConfigSet.GetAll() {
+		if configItem.Sensitive || !configItem.HasValue() {
+			continue
+		}
+		state.Flags[configItem.Name] = configItem.ValueString()
+	}
+	if input.Alias != nil {
+		state.Alias = *input.Alias
+	}
+	if clusterID == "" && input.resume != nil {
+		state.ClusterID = input.resume.ClusterID
+	}
+
+	if err := a.resumeCache().Set(resumeCacheKey, state); err != nil {
+		a.logger.Debugw("failed saving use state, the use command can't be resumed", "error", err.Error())
+	}
+}
+
+// clearResumeState removes the saved state once the use command has succeeded
+func (a *App) clearResumeState(input *UseInput) {
+	if input.resumeIdentity == nil && input.resume == nil {
+		return
+	}
+	if err := a.resumeCache().Delete(resumeCacheKey); err != nil {
+		a.logger.Debugw("failed removing saved use state", "error", err.Error())
+	}
+}
+
+// resumeCache keeps the saved state in the secret store as it includes the identity
+func (a *App) resumeCache() cache.Cache {
+	return secrets.NewCache(secrets.Default(), resumeStateTTL)
+}
@@ -0,0 +1,130 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"sort"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/provider/common"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+// ConfigSchema builds the schema used to validate the app configuration. The valid
+// items are based on the common items and the items of the registered providers.
+func ConfigSchema() (*config.Schema, error) {
+	identityNames := []string{}
+	for _, registration := range registry.ListIdentityPluginRegistrations() {
+		identityNames = append(identityNames, registration.Name)
+	}
+	sort.Strings(identityNames)
+
+	global, err := commonSchemaItems(identityNames)
+	if err != nil {
+		return nil, err
+	}
+
+	schema := &config.Schema{
+		Global:    global,
+		Providers: make(map[string]config.ConfigurationSet),
+		Plugins:   append([]string{}, identityNames...),
+	}
+
+	for _, discoReg := range registry.ListDiscoveryPluginRegistrations() {
+		providerItems, err := providerSchemaItems(discoReg)
+		if err != nil {
+			return nil, err
+		}
+		schema.Providers[discoReg.Name] = providerItems
+		schema.Plugins = append(schema.Plugins, discoReg.Name)
+
+		// Values in the global section apply to all providers
+		if err := global.AddSet(providerItems); err != nil {
+			return nil, fmt.Errorf("adding %s items to global schema: %w", discoReg.Name, err)
+		}
+	}
+
+	sort.Strings(schema.Plugins)
+
+	return schema, nil
+}
+
+func providerSchemaItems(discoReg *registry.DiscoveryPluginRegistration) (config.ConfigurationSet, error) {
+	cs, err := commonSchemaItems(discoReg.SupportedIdentityProviders)
+	if err != nil {
+		return nil, err
+	}
+
+	discoCfg, err := discoReg.ConfigurationItemsFunc("")
+	if err != nil {
+		return nil, fmt.Errorf("getting configuration items for %s: %w", discoReg.Name, err)
+	}
+	if err := cs.AddSet(discoCfg); err != nil {
+		return nil, fmt.Errorf("adding configuration items for %s: %w", discoReg.Name, err)
+	}
+
+	for _, idProviderName := range discoReg.SupportedIdentityProviders {
+		idReg, err := registry.GetIdentityProviderRegistration(idProviderName)
+		if err != nil {
+			return nil, fmt.Errorf("getting identity provider registration %s: %w", idProviderName, err)
+		}
+		idCfg, err := idReg.ConfigurationItemsFunc(discoReg.Name)
+		if err != nil {
+			return nil, fmt.Errorf("getting configuration items for %s: %w", idProviderName, err)
+		}
+		if err := cs.AddSet(idCfg); err != nil {
+			return nil, fmt.Errorf("adding configuration items for %s: %w", idProviderName, err)
+		}
+	}
+
+	return cs, nil
+}
+
+func commonSchemaItems(idpProtocols []string) (config.ConfigurationSet, error) {
+	cs := config.NewConfigurationSet()
+
+	if err := AddCommonConfigItems(cs); err != nil {
+		return nil, fmt.Errorf("adding common config items: %w", err)
+	}
+	if err := AddHistoryConfigItems(cs); err != nil {
+		return nil, fmt.Errorf("adding history config items: %w", err)
+	}
+	if err := AddKubeconfigConfigItems(cs); err != nil {
+		return nil, fmt.Errorf("adding kubeconfig config items: %w", err)
+	}
+	if err := AddCommonUseConfigItems(cs); err != nil {
+		return nil, fmt.Errorf("adding common use config items: %w", err)
+	}
+	if err := AddHistoryQueryConfig(cs); err != nil {
+		return nil, fmt.Errorf("adding history query config items: %w", err)
+	}
+	if err := common.AddCommonIdentityConfig(cs); err != nil {
+		return nil, fmt.Errorf("adding common identity config items: %w", err)
+	}
+	if err := common.AddCommonClusterConfig(cs); err != nil {
+		return nil, fmt.Errorf("adding common cluster config items: %w", err)
+	}
+	if _, err := cs.Bool("set-current", true, "Sets the current context in the kubeconfig to the selected cluster"); err != nil {
+		return nil, fmt.Errorf("adding set-current config: %w", err)
+	}
+	if err := cs.SetAllowedValues("idp-protocol", idpProtocols); err != nil {
+		return nil, fmt.Errorf("setting allowed values for idp-protocol: %w", err)
+	}
+
+	return cs, nil
+}
@@ -0,0 +1,47 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fidelity/kconnect/pkg/prompt"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+)
+
+// useStdinSelection will read the cluster to use from stdin and set it as the cluster id,
+// so that the history entry has the selected cluster instead of -. Stdin can't be used
+// to answer prompts afterwards so prompting in the terminal is disabled.
+func (a *App) useStdinSelection(input *UseInput) error {
+	clusterID, err := discovery.ReadSelection(os.Stdin, input.DiscoveryProvider)
+	if err != nil {
+		return fmt.Errorf("selecting cluster from stdin: %w", err)
+	}
+	a.logger.Debugw("cluster selected from stdin", "id", clusterID)
+
+	if err := input.ConfigSet.SetValue("cluster-id", clusterID); err != nil {
+		return fmt.Errorf("setting cluster-id config: %w", err)
+	}
+	input.ClusterID = &clusterID
+
+	if prompt.IsTerminal() {
+		a.SetNonInteractive()
+	}
+
+	return nil
+}
@@ -0,0 +1,251 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"crypto/rand"
+	"crypto/subtle"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"strings"
+	"sync"
+	"syscall"
+	"time"
+
+	historyv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/defaults"
+	"github.com/fidelity/kconnect/pkg/history"
+)
+
+const (
+	// ServeDefaultAddress is the default address the API listens on, it's only reachable locally
+	ServeDefaultAddress = "127.0.0.1:7683"
+
+	serveTokenBytes      = 32
+	serveShutdownTimeout = 5 * time.Second
+)
+
+type ServeInput struct {
+	CommonConfig
+	HistoryConfig
+	KubernetesConfig
+	ServeConfig
+}
+
+// ServeTokenPath is the default path of the file the API token is written to
+func ServeTokenPath() string {
+	return filepath.Join(defaults.AppDirectory(), "serve-token")
+}
+
+// Serve runs a local HTTP API for the connection history until the context is done or
+// it's interrupted. The
+// API is for tools such as developer portals and editor plugins. Requests must have the
+// token, which is written to the token file, as a bearer token.
+func (a *App) Serve(ctx context.Context, params *ServeInput) error {
+	params.TokenFile = defaults.ExpandPath(params.TokenFile)
+	if params.TokenFile == "" {
+		params.TokenFile = ServeTokenPath()
+	}
+	token, err := newServeToken()
+	if err != nil {
+		return fmt.Errorf("creating api token: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(params.TokenFile), os.ModePerm); err != nil {
+		return fmt.Errorf("creating token file directory: %w", err)
+	}
+	if err := os.WriteFile(params.TokenFile, []byte(token), 0600); err != nil {
+		return fmt.Errorf("writing token file %s: %w", params.TokenFile, err)
+	}
+	defer os.Remove(params.TokenFile) //nolint: errcheck
+
+	listener, err := net.Listen("tcp", params.Address)
+	if err != nil {
+		return fmt.Errorf("listening on %s: %w", params.Address, err)
+	}
+
+	// The server is stopped by an interrupt so that the token file is removed
+	ctx, stop := signal.NotifyContext(ctx, os.Interrupt, syscall.SIGTERM)
+	defer stop()
+
+	server := &http.Server{
+		Handler:           a.serveHandler(params, token),
+		ReadHeaderTimeout: serveShutdownTimeout,
+	}
+	go func() {
+		<-ctx.Done()
+		shutdownCtx, cancel := context.WithTimeout(context.Background(), serveShutdownTimeout)
+		defer cancel()
+		server.Shutdown(shutdownCtx) //nolint: errcheck
+	}()
+
+	fmt.Fprintf(os.Stderr, "Serving the kconnect API on http://%s, the token is in %s\n", listener.Addr(), params.TokenFile)
+	if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+		return fmt.Errorf("serving api: %w", err)
+	}
+
+	return nil
+}
+
+// serveHandler returns the handler for the API:
+//
+//	GET  /v1/history             lists the history entries
+//	GET  /v1/history/{id}        gets a history entry by its id or alias
+//	GET  /v1/aliases             lists the aliases and the ids of their entries
+//	POST /v1/history/{id}/renew  reconnects to the cluster of the entry to renew its credentials
+func (a *App) serveHandler(params *ServeInput, token string) http.Handler {
+	// Renewing writes the kubeconfig and history, so only 1 renew is done at a time
+	var renewLock sync.Mutex
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/v1/history", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeServeError(w, http.StatusMethodNotAllowed, errors.New(http.StatusText(http.StatusMethodNotAllowed)))
+			return
+		}
+		list, err := a.historyStore.GetAllSortedByLastUsed()
+		if err != nil {
+			writeServeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		writeServeJSON(w, http.StatusOK, list)
+	})
+	mux.HandleFunc("/v1/history/", func(w http.ResponseWriter, r *http.Request) {
+		id := strings.TrimPrefix(r.URL.Path, "/v1/history/")
+		switch {
+		case r.Method == http.MethodGet && !strings.Contains(id, "/"):
+			entry, err := a.serveEntry(id)
+			if err != nil {
+				writeServeError(w, serveErrorStatus(err), err)
+				return
+			}
+			writeServeJSON(w, http.StatusOK, entry)
+		case r.Method == http.MethodPost && strings.HasSuffix(id, "/renew"):
+			renewLock.Lock()
+			defer renewLock.Unlock()
+			id = strings.TrimSuffix(id, "/renew")
+			if err := a.serveRenew(r.Context(), params, id); err != nil {
+				writeServeError(w, serveErrorStatus(err), err)
+				return
+			}
+			entry, err := a.serveEntry(id)
+			if err != nil {
+				writeServeError(w, serveErrorStatus(err), err)
+				return
+			}
+			writeServeJSON(w, http.StatusOK, entry)
+		default:
+			writeServeError(w, http.StatusNotFound, errors.New(http.StatusText(http.StatusNotFound)))
+		}
+	})
+	mux.HandleFunc("/v1/aliases", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet {
+			writeServeError(w, http.StatusMethodNotAllowed, errors.New(http.StatusText(http.StatusMethodNotAllowed)))
+			return
+		}
+		list, err := a.historyStore.GetAll()
+		if err != nil {
+			writeServeError(w, http.StatusInternalServerError, err)
+			return
+		}
+		aliases := map[string]string{}
+		for _, entry := range list.Items {
+			if entry.Spec.Alias != nil && *entry.Spec.Alias != "" {
+				aliases[*entry.Spec.Alias] = entry.Name
+			}
+		}
+		writeServeJSON(w, http.StatusOK, aliases)
+	})
+
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		auth := strings.TrimPrefix(r.Header.Get("Authorization"), "Bearer ")
+		if subtle.ConstantTimeCompare([]byte(auth), []byte(token)) != 1 {
+			writeServeError(w, http.StatusUnauthorized, errors.New(http.StatusText(http.StatusUnauthorized)))
+			return
+		}
+		a.logger.Debugw("serving api request", "method", r.Method, "path", r.URL.Path)
+		mux.ServeHTTP(w, r)
+	})
+}
+
+// serveEntry gets the history entry by its id or alias
+func (a *App) serveEntry(idOrAlias string) (*historyv1alpha.HistoryEntry, error) {
+	entry, err := a.historyStore.GetByID(idOrAlias)
+	if err != nil {
+		return nil, fmt.Errorf("getting history entry by id: %w", err)
+	}
+	if entry != nil {
+		return entry, nil
+	}
+	entry, err = a.historyStore.GetByAlias(idOrAlias)
+	if err != nil {
+		return nil, fmt.Errorf("getting history entry by alias: %w", err)
+	}
+	if entry == nil {
+		return nil, history.ErrEntryNotFound
+	}
+
+	return entry, nil
+}
+
+// serveRenew reconnects to the cluster of the history entry without changing the current context
+func (a *App) serveRenew(ctx context.Context, params *ServeInput, idOrAlias string) error {
+	if _, err := a.serveEntry(idOrAlias); err != nil {
+		return err
+	}
+
+	return a.ConnectTo(ctx, &ConnectToInput{
+		CommonConfig:        params.CommonConfig,
+		HistoryConfig:       params.HistoryConfig,
+		KubernetesConfig:    params.KubernetesConfig,
+		AliasOrIDORPosition: idOrAlias,
+	})
+}
+
+func newServeToken() (string, error) {
+	data := make([]byte, serveTokenBytes)
+	if _, err := rand.Read(data); err != nil {
+		return "", err
+	}
+
+	return hex.EncodeToString(data), nil
+}
+
+func serveErrorStatus(err error) int {
+	if errors.Is(err, history.ErrEntryNotFound) {
+		return http.StatusNotFound
+	}
+
+	return http.StatusInternalServerError
+}
+
+func writeServeJSON(w http.ResponseWriter, status int, value interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(value) //nolint: errcheck
+}
+
+func writeServeError(w http.ResponseWriter, status int, err error) {
+	writeServeJSON(w, status, map[string]string{"error": err.Error()})
+}
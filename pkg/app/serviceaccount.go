@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"time"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+	"github.com/fidelity/kconnect/pkg/k8s/serviceaccount"
+)
+
+type ServiceAccountKubeconfigInput struct {
+	ConnectToInput
+	ServiceAccountConfig
+}
+
+// ServiceAccountKubeconfig reconnects to the cluster of a history entry and uses the
+// identity to create a short-lived token for a service account with the TokenRequest
+// api. A standalone kubeconfig that uses the token is written, e.g. for automation.
+func (a *App) ServiceAccountKubeconfig(ctx context.Context, params *ServiceAccountKubeconfigInput) error {
+	zap.S().Debugw("creating service account kubeconfig", "namespace", params.Namespace, "service-account", params.ServiceAccount)
+
+	if params.ServiceAccount == "" {
+		return serviceaccount.ErrServiceAccountRequired
+	}
+
+	useParams, err := a.connectIsolated(ctx, &params.ConnectToInput)
+	if err != nil {
+		return err
+	}
+	cfg, err := kubeconfig.Read(useParams.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("reading kubeconfig: %w", err)
+	}
+
+	token, err := serviceaccount.RequestToken(ctx, cfg, cfg.CurrentContext, &serviceaccount.TokenRequestInput{
+		Namespace:      params.Namespace,
+		ServiceAccount: params.ServiceAccount,
+		Duration:       params.Duration,
+		Audiences:      params.Audiences,
+		BoundSecret:    params.BoundSecret,
+	})
+	if err != nil {
+		return fmt.Errorf("creating service account token: %w", err)
+	}
+	if a.ciEnv != nil {
+		a.ciEnv.MaskSecret(token.Token)
+	}
+
+	saConfig, err := serviceaccount.Kubeconfig(cfg, cfg.CurrentContext, params.Namespace, params.ServiceAccount, token)
+	if err != nil {
+		return err
+	}
+	data, err := clientcmd.Write(*saConfig)
+	if err != nil {
+		return fmt.Errorf("serializing kubeconfig: %w", err)
+	}
+
+	if params.OutputFile == "" {
+		_, err = os.Stdout.Write(data)
+		return err
+	}
+	if err := ioutil.WriteFile(params.OutputFile, data, 0600); err != nil {
+		return fmt.Errorf("writing kubeconfig %s: %w", params.OutputFile, err)
+	}
+	fmt.Fprintf(os.Stderr, "Wrote the kubeconfig for %s/%s to %s, the token expires at %s\n",
+		params.Namespace, params.ServiceAccount, params.OutputFile, token.Expiry.Format(time.RFC3339))
+
+	return nil
+}
@@ -0,0 +1,134 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/k8s/connectivity"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+	"github.com/fidelity/kconnect/pkg/telemetry"
+)
+
+// staleCheckTimeout is the time limit for checking the API server of a cached cluster
+const staleCheckTimeout = 5 * time.Second
+
+// refreshStaleCluster checks if the endpoint or certificate authority of the cluster of
+// a history entry have changed since the entry was last used, e.g. because the cluster
+// was rebuilt. A cluster found in the discovery cache is discovered again if its details
+// are different to the ones in the history entry, or if its API server doesn't exist or
+// has a certificate that isn't signed by the cached certificate authority. The kubeconfig
+// and history entry are then updated with the current details instead of stale ones.
+func (a *App) refreshStaleCluster(ctx context.Context, clusterProvider discovery.Provider, clusterIdentity identity.Identity, input *UseInput, cluster *discovery.Cluster) (*discovery.Cluster, error) {
+	entry, err := a.historyStore.GetByID(input.EntryID)
+	if err != nil {
+		return nil, fmt.Errorf("getting history entry: %w", err)
+	}
+	if entry == nil {
+		return cluster, nil
+	}
+
+	endpoint, caData := clusterDetails(cluster)
+	changed := entry.ClusterDetailsChanged(endpoint, caData)
+
+	live := pluginRegistration(clusterProvider.Name()).HasCapability(registry.CapabilitySupportsRefresh) && !khttp.OfflineEnabled()
+	cached := !live && (input.DiscoveryCacheTTL > 0 || khttp.OfflineEnabled())
+	switch {
+	case !cached:
+		a.logClusterChanged(changed)
+		return cluster, nil
+	case khttp.OfflineEnabled():
+		if len(changed) > 0 {
+			a.logger.Warnw("the cached cluster details are different to the ones in the history entry and can't be refreshed when offline", "changed", strings.Join(changed, ", "))
+		}
+		return cluster, nil
+	}
+
+	reason := ""
+	if len(changed) > 0 {
+		reason = fmt.Sprintf("the %s changed", strings.Join(changed, " and "))
+	} else if err := a.checkClusterEndpoint(ctx, endpoint, caData); err != nil {
+		if !errors.Is(err, connectivity.ErrEndpointNotFound) && !errors.Is(err, connectivity.ErrCertificateChanged) {
+			a.logger.Debugw("couldn't check the api server of the cached cluster", "error", err.Error())
+			return cluster, nil
+		}
+		reason = err.Error()
+	}
+	if reason == "" {
+		return cluster, nil
+	}
+
+	a.logger.Infow("the cached cluster details are stale, discovering the cluster again", "reason", reason)
+	refreshed, err := a.findCluster(discovery.WithRefresh(ctx), clusterProvider, clusterIdentity, input)
+	if err != nil {
+		return nil, fmt.Errorf("refreshing stale cluster: %w", err)
+	}
+	a.logClusterChanged(entry.ClusterDetailsChanged(clusterDetails(refreshed)))
+
+	return refreshed, nil
+}
+
+func (a *App) logClusterChanged(changed []string) {
+	if len(changed) == 0 {
+		return
+	}
+	a.logger.Infow("the cluster has changed since the history entry was last used, it may have been rebuilt. The kubeconfig and history entry will be updated", "changed", strings.Join(changed, ", "))
+}
+
+// checkClusterEndpoint checks the certificate of the API server is signed by the
+// certificate authority of the cluster
+func (a *App) checkClusterEndpoint(ctx context.Context, endpoint, caData string) error {
+	if endpoint == "" || caData == "" {
+		return nil
+	}
+
+	ctx, span := telemetry.Start(ctx, "cluster.check-endpoint", "endpoint", endpoint)
+	defer span.Finish()
+
+	// The certificate authority data is base64 encoded PEM for most providers
+	pemData, err := base64.StdEncoding.DecodeString(caData)
+	if err != nil {
+		pemData = []byte(caData)
+	}
+
+	err = connectivity.CheckEndpoint(ctx, endpoint, pemData, staleCheckTimeout)
+	span.RecordError(err)
+
+	return err
+}
+
+// clusterDetails returns the endpoint and certificate authority data of the cluster
+func clusterDetails(cluster *discovery.Cluster) (string, string) {
+	endpoint := ""
+	if cluster.ControlPlaneEndpoint != nil {
+		endpoint = *cluster.ControlPlaneEndpoint
+	}
+	caData := ""
+	if cluster.CertificateAuthorityData != nil {
+		caData = *cluster.CertificateAuthorityData
+	}
+
+	return endpoint, caData
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+	"sync"
+
+	"github.com/fidelity/kconnect/pkg/prompt"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/telemetry"
+)
+
+// refreshOption is shown while clusters are still being discovered. It starts with
+// a bracket so that it's sorted before the clusters.
+const refreshOption = "(still discovering, choose to refresh)"
+
+// streamCluster discovers the clusters in the background and shows them to choose
+// from as soon as the first ones are found, rather than waiting for all the regions
+// or subscriptions to be enumerated. While discovery is in progress there is an
+// option to refresh the list with the clusters found since it was shown.
+func (a *App) streamCluster(ctx context.Context, clusterProvider discovery.Provider, identity identity.Identity, params *UseInput) (*discovery.Cluster, error) {
+	discoverCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	type discoverResult struct {
+		output *discovery.DiscoverOutput
+		err    error
+	}
+
+	found := newStreamedClusters()
+	done := make(chan discoverResult, 1)
+	go func() {
+		output, err := clusterProvider.Discover(discoverCtx, &discovery.DiscoverInput{
+			ConfigSet:  params.ConfigSet,
+			Identity:   identity,
+			OnClusters: found.add,
+		})
+		done <- discoverResult{output: output, err: err}
+	}()
+
+	for {
+		select {
+		case result := <-done:
+			if result.err != nil {
+				return nil, fmt.Errorf("discovering clusters using %s: %w", clusterProvider.Name(), result.err)
+			}
+			return a.chooseCluster(ctx, clusterProvider, result.output)
+		case <-found.changed:
+		}
+
+		clusters := found.list()
+		options, message := clusterOptions(clusterProvider.Name(), clusters, "Select a cluster")
+		options[refreshOption] = ""
+
+		_, selectSpan := telemetry.Start(ctx, "cluster.select", "clusters", strconv.Itoa(len(clusters)))
+		clusterID, err := prompt.Choose("cluster", message, true, prompt.OptionsFromMap(options))
+		selectSpan.RecordError(err)
+		selectSpan.Finish()
+		if err != nil {
+			return nil, fmt.Errorf("selecting cluster: %w", err)
+		}
+		if clusterID != "" {
+			a.logger.Debugw("selected cluster before discovery finished", "id", clusterID, "found", len(clusters))
+			return clusters[clusterID], nil
+		}
+	}
+}
+
+// streamedClusters holds the clusters found so far and signals when there are more
+type streamedClusters struct {
+	lock     sync.Mutex
+	clusters map[string]*discovery.Cluster
+	changed  chan struct{}
+}
+
+func newStreamedClusters() *streamedClusters {
+	return &streamedClusters{
+		clusters: make(map[string]*discovery.Cluster),
+		changed:  make(chan struct{}, 1),
+	}
+}
+
+func (s *streamedClusters) add(clusters []*discovery.Cluster) {
+	s.lock.Lock()
+	for _, cluster := range clusters {
+		s.clusters[cluster.ID] = cluster
+	}
+	s.lock.Unlock()
+
+	select {
+	case s.changed <- struct{}{}:
+	default:
+	}
+}
+
+func (s *streamedClusters) list() map[string]*discovery.Cluster {
+	s.lock.Lock()
+	defer s.lock.Unlock()
+
+	clusters := make(map[string]*discovery.Cluster, len(s.clusters))
+	for id, cluster := range s.clusters {
+		clusters[id] = cluster
+	}
+
+	return clusters
+}
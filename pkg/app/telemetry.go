@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/fidelity/kconnect/pkg/telemetry"
+)
+
+// enableTelemetry will record spans of the connect flow if an OTLP endpoint is configured
+func (a *App) enableTelemetry(ctx context.Context, cfg *TelemetryConfig) error {
+	if cfg.OTLPEndpoint == "" || a.otlpEnabled {
+		return nil
+	}
+
+	headers, err := telemetry.ParseHeaders(cfg.OTLPHeaders)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", OTLPHeadersConfigItem, err)
+	}
+	a.logger.Debugw("exporting traces", "endpoint", cfg.OTLPEndpoint)
+	exporter, err := telemetry.NewOTLPExporter(ctx, cfg.OTLPEndpoint, headers)
+	if err != nil {
+		return fmt.Errorf("parsing %s: %w", OTLPEndpointConfigItem, err)
+	}
+	telemetry.Enable(exporter)
+	a.otlpEnabled = true
+
+	return nil
+}
+
+// flushTelemetry will export the recorded spans. Tracing mustn't stop the user
+// connecting so a failed export is a warning.
+func (a *App) flushTelemetry(ctx context.Context) {
+	if err := telemetry.Flush(ctx); err != nil {
+		a.logger.Warnw("failed exporting traces", "error", err.Error())
+	}
+}
@@ -20,52 +20,81 @@ import (
 	"bytes"
 	"context"
 	"fmt"
+	"path/filepath"
 	"regexp"
 	"strconv"
 	"strings"
+	"text/template"
 
 	"go.uber.org/zap"
 
 	historyv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
 	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/defaults"
 	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
 	"github.com/fidelity/kconnect/pkg/printer"
 	"github.com/fidelity/kconnect/pkg/prompt"
 	"github.com/fidelity/kconnect/pkg/provider/common"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
 	"github.com/fidelity/kconnect/pkg/provider/registry"
 )
 
+const (
+	// ExpiredRefresh re-authenticates when the credentials of a history entry have expired
+	ExpiredRefresh = "refresh"
+	// ExpiredConfirm asks the user before re-authenticating when the credentials of a
+	// history entry have expired
+	ExpiredConfirm = "confirm"
+)
+
 type ConnectToInput struct {
 	CommonConfig
 	HistoryConfig
 	KubernetesConfig
+	ExpiredConfig
 
 	AliasOrIDORPosition string
 	Password            string `json:"password"`
 	SetCurrent          bool   `json:"set-current,omitempty"`
+	ExplainConfig       bool   `json:"explain-config,omitempty"`
 }
 
 func (a *App) ConnectTo(ctx context.Context, params *ConnectToInput) error {
 	a.logger.Debug("running connectto")
 
+	useParams, _, err := a.connectToUseInput(params)
+	if err != nil {
+		return err
+	}
+
+	return a.Use(ctx, useParams)
+}
+
+// connectToUseInput creates the input to reconnect to the cluster of a history entry
+func (a *App) connectToUseInput(params *ConnectToInput) (*UseInput, *historyv1alpha.HistoryEntry, error) {
 	entry, err := a.getHistoryEntry(params)
 	if err != nil {
-		return fmt.Errorf("getting history entry: %w", err)
+		return nil, nil, fmt.Errorf("getting history entry: %w", err)
 	}
 	if entry == nil {
-		return history.ErrEntryNotFound
+		return nil, nil, history.ErrEntryNotFound
 	}
 	historyID := entry.ObjectMeta.Name
+	if err := a.checkCredentialsExpiry(entry, params.Expired); err != nil {
+		return nil, nil, err
+	}
 
-	cs, err := a.buildConnectToConfig(params.ConfigFile, entry.Spec.Provider, entry.Spec.Identity, entry)
+	cs, err := a.buildConnectToConfig(params.ConfigFile, entry.Spec.Provider, entry.Spec.Identity, entry.Spec.Flags)
 	if err != nil {
-		return fmt.Errorf("building connectTo config set: %w", err)
+		return nil, nil, fmt.Errorf("building connectTo config set: %w", err)
 	}
 
 	if params.Password != "" {
 		if err := cs.SetValue("password", params.Password); err != nil {
-			return fmt.Errorf("setting password config item: %w", err)
+			return nil, nil, fmt.Errorf("setting password config item: %w", err)
 		}
+		cs.SetSource("password", config.ItemSourceFlag) //nolint: errcheck
 	}
 
 	useParams := &UseInput{
@@ -75,16 +104,129 @@ func (a *App) ConnectTo(ctx context.Context, params *ConnectToInput) error {
 	}
 
 	if err := config.Unmarshall(cs, useParams); err != nil {
-		return fmt.Errorf("unmarshalling config into use params: %w", err)
+		return nil, nil, fmt.Errorf("unmarshalling config into use params: %w", err)
 	}
 
 	useParams.EntryID = historyID
 	useParams.ClusterID = &entry.Spec.ProviderID
 	useParams.SetCurrent = params.SetCurrent
+	useParams.ExplainConfig = params.ExplainConfig
 	useParams.IgnoreAlias = true
 	useParams.Alias = entry.Spec.Alias
+	useParams.pinnedKubeconfig = entry.Spec.Kubeconfig
+	useParams.contextNameTemplate = entry.Spec.ContextName
 
-	return a.Use(ctx, useParams)
+	return useParams, entry, nil
+}
+
+// useEntryAlias sets the history entry to connect to from the alias, if there is one.
+// The entry can't be given as an argument as well unless it's the same alias.
+func (c *EntryAliasConfig) useEntryAlias(params *ConnectToInput) error {
+	if c.Alias == "" {
+		return nil
+	}
+	if params.AliasOrIDORPosition != "" && params.AliasOrIDORPosition != c.Alias {
+		return ErrEntryArgAndAlias
+	}
+	params.AliasOrIDORPosition = c.Alias
+
+	return nil
+}
+
+// contextNameData is the data for the template of a context name pinned to a history entry
+type contextNameData struct {
+	// Alias is the alias of the history entry
+	Alias string
+	// ID is the id of the history entry
+	ID string
+	// Context is the name of the context created by the discovery provider
+	Context string
+	// ClusterName is the name of the cluster
+	ClusterName string
+	// Provider is the name of the discovery provider
+	Provider string
+}
+
+// renamePinnedContext renames the context in the kubeconfig created by the discovery
+// provider using the context name template pinned to the history entry
+func renamePinnedContext(input *UseInput, cluster *discovery.Cluster, output *discovery.GetConfigOutput) error {
+	tmpl, err := template.New("context-name").Parse(input.contextNameTemplate)
+	if err != nil {
+		return fmt.Errorf("parsing context name template: %w", err)
+	}
+
+	data := contextNameData{
+		ID:          input.EntryID,
+		Context:     *output.ContextName,
+		ClusterName: cluster.Name,
+		Provider:    input.DiscoveryProvider,
+	}
+	if input.Alias != nil {
+		data.Alias = *input.Alias
+	}
+
+	buf := &bytes.Buffer{}
+	if err := tmpl.Execute(buf, data); err != nil {
+		return fmt.Errorf("executing context name template: %w", err)
+	}
+	contextName := strings.TrimSpace(buf.String())
+	if contextName == "" {
+		return ErrEmptyContextName
+	}
+
+	if err := kubeconfig.RenameContext(output.KubeConfig, *output.ContextName, contextName); err != nil {
+		return fmt.Errorf("renaming context: %w", err)
+	}
+	output.ContextName = &contextName
+
+	return nil
+}
+
+// checkCredentialsExpiry will tell the user if the credentials of the history entry have
+// expired. With the confirm policy the user is asked before re-authenticating.
+func (a *App) checkCredentialsExpiry(entry *historyv1alpha.HistoryEntry, policy string) error {
+	if !entry.CredentialsExpired() {
+		return nil
+	}
+
+	description := entry.CredentialsExpiryDescription()
+	if policy != ExpiredConfirm {
+		a.logger.Infof("Credentials %s, will re-authenticate", description)
+		return nil
+	}
+	if !a.interactive {
+		return fmt.Errorf("credentials %s: %w", description, ErrReauthDeclined)
+	}
+
+	reauth, err := prompt.Confirm("reauthenticate", fmt.Sprintf("Credentials %s, re-authenticate?", description), true)
+	if err != nil {
+		return fmt.Errorf("confirming re-authentication: %w", err)
+	}
+	if !reauth {
+		return ErrReauthDeclined
+	}
+
+	return nil
+}
+
+// connectIsolated reconnects to the cluster of a history entry, so that the credentials
+// are fresh, and writes an isolated kubeconfig that only contains the context for the
+// cluster. The kubeconfig path is set in the returned use input.
+func (a *App) connectIsolated(ctx context.Context, params *ConnectToInput) (*UseInput, error) {
+	useParams, entry, err := a.connectToUseInput(params)
+	if err != nil {
+		return nil, err
+	}
+
+	useParams.Kubeconfig = filepath.Join(defaults.KubeconfigsDirectory(), fmt.Sprintf("%s.yaml", entry.ObjectMeta.Name))
+	useParams.pinnedKubeconfig = ""
+	useParams.SetCurrent = true
+
+	if err := a.Use(ctx, useParams); err != nil {
+		return nil, fmt.Errorf("refreshing credentials: %w", err)
+	}
+
+	return useParams, nil
 }
 
 func (a *App) getHistoryEntry(params *ConnectToInput) (*historyv1alpha.HistoryEntry, error) {
@@ -187,7 +329,7 @@ func (a *App) generateOptions(params *ConnectToInput, entries *historyv1alpha.Hi
 	return options, nil
 }
 
-func (a *App) buildConnectToConfig(configFile string, discoveryProvider string, idProvider string, historyEntry *historyv1alpha.HistoryEntry) (config.ConfigurationSet, error) {
+func (a *App) buildConnectToConfig(configFile string, discoveryProvider string, idProvider string, flags map[string]string) (config.ConfigurationSet, error) {
 	cs := config.NewConfigurationSet()
 
 	idProviderReg, err := registry.GetIdentityProviderRegistration(idProvider)
@@ -229,31 +371,17 @@ func (a *App) buildConnectToConfig(configFile string, discoveryProvider string,
 		return nil, fmt.Errorf("adding common use config items: %w", err)
 	}
 
-	for k, v := range historyEntry.Spec.Flags {
+	for k, v := range flags {
 		configItem := cs.Get(k)
 		if configItem == nil {
 			zap.S().Debugw("no config item found", "name", k)
 			continue
 		}
 
-		switch configItem.Type {
-		case config.ItemTypeString:
-			configItem.Value = v
-		case config.ItemTypeInt:
-			intVal, err := strconv.Atoi(v)
-			if err != nil {
-				return nil, err
-			}
-			configItem.Value = intVal
-		case config.ItemTypeBool:
-			boolVal, err := strconv.ParseBool(v)
-			if err != nil {
-				return nil, err
-			}
-			configItem.Value = boolVal
-		default:
-			return nil, fmt.Errorf("trying to set config item %s of type %s: %w", configItem.Name, configItem.Type, ErrUnknownConfigItemType)
+		if err := config.SetItemValue(configItem, v); err != nil {
+			return nil, fmt.Errorf("trying to set config item %s of type %s: %w", configItem.Name, configItem.Type, err)
 		}
+		configItem.Source = config.ItemSourceHistory
 	}
 
 	if err := config.ApplyToConfigSetWithProvider(configFile, cs, discoveryProvider); err != nil {
@@ -263,6 +391,7 @@ func (a *App) buildConnectToConfig(configFile string, discoveryProvider string,
 	for _, configItem := range cs.GetAll() {
 		if !configItem.HasValue() {
 			configItem.Value = configItem.DefaultValue
+			configItem.Source = config.ItemSourceDefault
 		}
 	}
 
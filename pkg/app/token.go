@@ -0,0 +1,139 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	clientauthv1beta1 "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+	"github.com/fidelity/kconnect/pkg/logging"
+	"github.com/fidelity/kconnect/pkg/notify"
+)
+
+const (
+	// TokenOutputToken outputs just the bearer token
+	TokenOutputToken = "token"
+	// TokenOutputExecCredential outputs the ExecCredential JSON of a kubectl credential plugin
+	TokenOutputExecCredential = "exec-credential"
+
+	execCredentialAPIVersion = "client.authentication.k8s.io/v1beta1"
+)
+
+type GetTokenInput struct {
+	ConnectToInput
+	EntryAliasConfig
+	GetTokenConfig
+}
+
+// GetToken reconnects to the cluster of a history entry, using the identity provider
+// recorded in the entry, and writes the fresh credentials for the cluster to stdout.
+// This is for scripts that call the api server directly instead of using kubectl.
+func (a *App) GetToken(ctx context.Context, params *GetTokenInput) error {
+	zap.S().Debugw("getting token", "output", params.Output)
+
+	if err := params.useEntryAlias(&params.ConnectToInput); err != nil {
+		return err
+	}
+
+	useParams, err := a.connectIsolated(ctx, &params.ConnectToInput)
+	if err != nil {
+		return err
+	}
+	cfg, err := kubeconfig.Read(useParams.Kubeconfig)
+	if err != nil {
+		return fmt.Errorf("reading kubeconfig: %w", err)
+	}
+
+	status, err := kubeconfig.ContextCredential(ctx, cfg, cfg.CurrentContext)
+	if err != nil {
+		return fmt.Errorf("getting credentials for context %s: %w", cfg.CurrentContext, err)
+	}
+	logging.Redact(status.Token, status.ClientKeyData)
+	if a.ciEnv != nil && status.Token != "" {
+		a.ciEnv.MaskSecret(status.Token)
+	}
+	if params.NotifyExpiry > 0 {
+		a.notifyCredentialsExpiry(ctx, useParams.EntryID, params.AliasOrIDORPosition, params.NotifyExpiry)
+	}
+
+	if params.Output == TokenOutputExecCredential {
+		return writeExecCredential(status)
+	}
+	if status.Token == "" {
+		return ErrNoBearerToken
+	}
+	_, err = fmt.Fprintln(os.Stdout, status.Token)
+
+	return err
+}
+
+// notifyCredentialsExpiry shows a desktop notification if the credentials that the
+// cluster was reconnected with, e.g. the AWS session, expire within the window. The
+// tokens are refreshed from these credentials, so once they expire kubectl fails until
+// the user signs in again. Each expiry is only notified once and a notification that
+// can't be shown is only logged.
+func (a *App) notifyCredentialsExpiry(ctx context.Context, entryID, ref string, window time.Duration) {
+	entry, err := a.historyStore.GetByID(entryID)
+	if err != nil || entry == nil || entry.Status.CredentialsExpiry == nil {
+		return
+	}
+	expiry := entry.Status.CredentialsExpiry.Time
+	if time.Until(expiry) > window {
+		return
+	}
+
+	if ref == "" || ref == "-" || strings.HasPrefix(ref, "LAST") {
+		ref = entryID
+		if entry.Spec.Alias != nil && *entry.Spec.Alias != "" {
+			ref = *entry.Spec.Alias
+		}
+	}
+	remaining := "expire in " + entry.CredentialsTimeLeft()
+	if !time.Now().Before(expiry) {
+		remaining = "have expired"
+	}
+	message := fmt.Sprintf("The credentials for %s %s. Run `kconnect to %s` to renew them.", ref, remaining, ref)
+	id := fmt.Sprintf("%s@%s", entryID, expiry.UTC().Format(time.RFC3339))
+	if err := notify.NewOnce(defaults.ExpiryNotificationsPath()).Send(ctx, id, "kconnect", message); err != nil {
+		a.logger.Warnw("failed showing the credentials expiry notification", "error", err.Error())
+	}
+}
+
+func writeExecCredential(status *clientauthv1beta1.ExecCredentialStatus) error {
+	credential := &clientauthv1beta1.ExecCredential{
+		Status: status,
+	}
+	credential.Kind = "ExecCredential"
+	credential.APIVersion = execCredentialAPIVersion
+
+	data, err := json.Marshal(credential)
+	if err != nil {
+		return fmt.Errorf("marshalling exec credential: %w", err)
+	}
+	_, err = fmt.Fprintln(os.Stdout, string(data))
+
+	return err
+}
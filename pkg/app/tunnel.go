@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"context"
+
+	"github.com/fidelity/kconnect/pkg/telemetry"
+	"github.com/fidelity/kconnect/pkg/tunnel"
+)
+
+// openTunnel will open the SSH tunnel if a jump host is configured and return the url
+// of its proxy. An empty url is returned if there's no jump host.
+func (a *App) openTunnel(ctx context.Context, cfg *TunnelConfig) (string, error) {
+	if cfg.SSHJumpHost == "" {
+		return "", nil
+	}
+
+	ctx, span := telemetry.Start(ctx, "tunnel.open", "jump-host", cfg.SSHJumpHost)
+	defer span.Finish()
+
+	tun, err := tunnel.Open(ctx, &tunnel.Config{
+		JumpHost:     cfg.SSHJumpHost,
+		IdentityFile: cfg.SSHIdentityFile,
+		LocalPort:    cfg.SSHTunnelPort,
+		Interactive:  a.interactive,
+	})
+	if err != nil {
+		span.RecordError(err)
+		return "", err
+	}
+	a.logger.Infow("connecting to the cluster through the ssh tunnel", "jump-host", cfg.SSHJumpHost, "proxy-url", tun.ProxyURL())
+
+	return tun.ProxyURL(), nil
+}
@@ -18,22 +18,32 @@ package app
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"os"
 	"strconv"
 
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
 	"k8s.io/apimachinery/pkg/runtime"
 	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
 
 	historyv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/cache"
 	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/defaults"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	khttp "github.com/fidelity/kconnect/pkg/http"
 	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+	"github.com/fidelity/kconnect/pkg/prereqs"
 	"github.com/fidelity/kconnect/pkg/prompt"
 	"github.com/fidelity/kconnect/pkg/provider"
 	"github.com/fidelity/kconnect/pkg/provider/common"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 	"github.com/fidelity/kconnect/pkg/provider/identity"
 	"github.com/fidelity/kconnect/pkg/provider/registry"
+	"github.com/fidelity/kconnect/pkg/telemetry"
+	"github.com/fidelity/kconnect/pkg/utils"
 )
 
 // UseInput are the parameters to the use function
@@ -44,6 +54,7 @@ type UseInput struct {
 	KubernetesConfig
 	common.IdentityProviderConfig
 	common.ClusterProviderConfig
+	ListClustersConfig
 
 	SetCurrent bool `json:"set-current,omitempty"`
 
@@ -51,68 +62,233 @@ type UseInput struct {
 	IdentityProvider  string
 
 	IgnoreAlias bool
+	// ListClusters prints the discovered clusters instead of connecting to one of them
+	ListClusters bool
 
 	ConfigSet config.ConfigurationSet
+
+	// resume is the state saved by the use command that's being resumed
+	resume *resumeState
+	// resumeIdentity is the identity to save so that the command can be resumed, it's
+	// nil if the command can't be resumed
+	resumeIdentity json.RawMessage
+	// pinnedKubeconfig and contextNameTemplate are pinned to the history entry that's
+	// being reconnected to, they override the kubeconfig written to and the context name
+	pinnedKubeconfig    string
+	contextNameTemplate string
+	// clusterPolicy restricts the clusters that can be connected to, it's nil if there
+	// is no policy for the discovery provider
+	clusterPolicy *discovery.ClusterPolicy
 }
 
 func (a *App) Use(ctx context.Context, input *UseInput) error {
+	if err := a.enableTelemetry(ctx, &input.TelemetryConfig); err != nil {
+		return fmt.Errorf("configuring telemetry: %w", err)
+	}
+	ctx, span := telemetry.Start(ctx, "kconnect.use", "identity-provider", input.IdentityProvider, "discovery-provider", input.DiscoveryProvider)
+	err := a.use(ctx, input)
+	if err != nil && input.resumeIdentity != nil {
+		a.logger.Infof("Command to continue without authenticating again: %s use --resume", utils.CommandName())
+	}
+	span.RecordError(err)
+	span.Finish()
+	a.flushTelemetry(ctx)
+
+	return err
+}
+
+func (a *App) use(ctx context.Context, input *UseInput) error {
 	a.logger.Debug("use command")
-	identityProvider, err := a.getIdentityProvider(&input.IdentityProvider, &input.DiscoveryProvider)
+	if input.AnswersFile != "" {
+		if err := a.useAnswersFile(input.AnswersFile, input.ConfigSet); err != nil {
+			return err
+		}
+	}
+	if input.ClusterID != nil && *input.ClusterID == discovery.SelectionFromStdin {
+		if err := a.useStdinSelection(input); err != nil {
+			return err
+		}
+	}
+	a.maskSensitiveConfig(input.ConfigSet)
+	if err := config.ValidateExclusive(input.ConfigSet); err != nil {
+		return fmt.Errorf("validating config: %w", err)
+	}
+	identityHTTPOpts, err := httpClientOptions(input.IdentityProxy, input.IDPCACert, &input.CommonUseConfig)
+	if err != nil {
+		return fmt.Errorf("configuring identity provider http client: %w", err)
+	}
+	if input.IDPClientCert != "" {
+		opt, err := khttp.WithClientCertFile(input.IDPClientCert, input.IDPClientKey)
+		if err != nil {
+			return fmt.Errorf("configuring identity provider client certificate: %w", err)
+		}
+		identityHTTPOpts = append(identityHTTPOpts, opt)
+	}
+	discoveryHTTPOpts, err := httpClientOptions(input.DiscoveryProxy, "", &input.CommonUseConfig)
+	if err != nil {
+		return fmt.Errorf("configuring discovery provider http client: %w", err)
+	}
+	input.clusterPolicy, err = loadClusterPolicy(input.ConfigFile, input.DiscoveryProvider)
 	if err != nil {
-		return fmt.Errorf("getting identity provider: %w", err)
+		return fmt.Errorf("loading cluster policy: %w", err)
 	}
-	clusterProvider, err := a.getDiscoveryProvider(&input.DiscoveryProvider, &input.IdentityProvider)
+	identityProvider, clusterProvider, err := a.useProviders(input, identityHTTPOpts, discoveryHTTPOpts)
 	if err != nil {
-		return fmt.Errorf("getting discovery provider: %w", err)
+		return err
 	}
 
 	if !isIdpSupported(identityProvider.Name(), clusterProvider) {
 		return fmt.Errorf("using identity provider %s: %w", input.IdentityProvider, ErrUnsuportedIdpProtocol)
 	}
+	if err := a.checkInteractiveRequired(identityProvider.Name(), clusterProvider.Name()); err != nil {
+		return err
+	}
+	if input.ListClusters {
+		return a.listClusters(ctx, identityProvider, clusterProvider, input)
+	}
 
-	err = clusterProvider.CheckPreReqs()
-	if err != nil {
+	if err := a.checkPreReqs(ctx, clusterProvider, input.InstallPreReqs); err != nil {
 		//TODO: how to report this???
-		fmt.Fprintf(os.Stderr, "\033[33m%s\033[0m\n", err.Error())
+		fmt.Fprintln(os.Stderr, utils.Warning(err.Error()))
 	}
 
-	authOutput, err := identityProvider.Authenticate(ctx, &identity.AuthenticateInput{
-		ConfigSet: input.ConfigSet,
-	})
-	if err != nil {
-		return fmt.Errorf("authenticating using provider %s: %w", identityProvider.Name(), err)
+	var clusters []*discovery.Cluster
+	var clusterIdentity identity.Identity
+	if len(input.Identities) > 0 && (input.ClusterID == nil || *input.ClusterID == "") {
+		discovered, err := a.discoverWithIdentities(ctx, input, func() (identity.Provider, discovery.Provider, error) {
+			return a.useProviders(input, identityHTTPOpts, discoveryHTTPOpts)
+		})
+		if err != nil {
+			return err
+		}
+		if discovered == nil {
+			return nil
+		}
+		// The kubeconfig and history entry are for the identity that discovered the cluster
+		clusters = []*discovery.Cluster{discovered.cluster}
+		clusterIdentity = discovered.identity
+		clusterProvider = discovered.clusterProvider
+		input.ConfigSet = discovered.configSet
+	} else {
+		clusterIdentity, err = a.useIdentity(ctx, identityProvider, clusterProvider, input)
+		if err != nil {
+			return err
+		}
+
+		// An alias can only be used for 1 history entry
+		if input.MultiSelect && input.Alias != nil && *input.Alias != "" {
+			return ErrAliasWithMultiSelect
+		}
+		if !input.IgnoreAlias && !input.MultiSelect {
+			if err := a.resolveAndCheckAlias(input); err != nil {
+				return fmt.Errorf("resolving and checking alias: %w", err)
+			}
+			a.saveResumeState(input, "")
+		}
+
+		var cluster *discovery.Cluster
+		switch {
+		case (input.ClusterID == nil || *input.ClusterID == "") && input.MultiSelect:
+			clusters, err = a.discoverClusters(ctx, clusterProvider, clusterIdentity, input)
+		case input.ClusterID == nil || *input.ClusterID == "":
+			cluster, err = a.discoverCluster(ctx, clusterProvider, clusterIdentity, input)
+		// When offline the cluster is found in the cached clusters instead of getting it
+		case pluginRegistration(clusterProvider.Name()).HasCapability(registry.CapabilitySupportsRefresh) && !khttp.OfflineEnabled():
+			cluster, err = a.getCluster(ctx, clusterProvider, clusterIdentity, input)
+		default:
+			cluster, err = a.findCluster(ctx, clusterProvider, clusterIdentity, input)
+		}
+		if err == nil && cluster != nil && input.EntryID != "" {
+			cluster, err = a.refreshStaleCluster(ctx, clusterProvider, clusterIdentity, input, cluster)
+		}
+		if err != nil {
+			return err
+		}
+		if cluster != nil {
+			a.saveResumeState(input, cluster.ID)
+			clusters = []*discovery.Cluster{cluster}
+		}
+		if len(clusters) == 0 {
+			return nil
+		}
 	}
 
-	if err := clusterProvider.Resolve(input.ConfigSet, authOutput.Identity); err != nil {
-		return fmt.Errorf("resolving config items: %w", err)
+	if err := a.enforceClusterPolicy(input, clusters); err != nil {
+		return err
 	}
 
-	if !input.IgnoreAlias {
-		if err := a.resolveAndCheckAlias(input); err != nil {
-			return fmt.Errorf("resolving and checking alias: %w", err)
+	if input.ExplainConfig {
+		if err := explainConfig(input.ConfigSet, os.Stderr); err != nil {
+			return fmt.Errorf("explaining config: %w", err)
 		}
 	}
 
-	var cluster *discovery.Cluster
-	if input.ClusterID == nil || *input.ClusterID == "" {
-		cluster, err = a.discoverCluster(ctx, clusterProvider, authOutput.Identity, input)
-	} else {
-		cluster, err = a.getCluster(ctx, clusterProvider, authOutput.Identity, input)
-	}
+	proxyURL, err := a.openTunnel(ctx, &input.TunnelConfig)
 	if err != nil {
-		return err
+		return fmt.Errorf("opening ssh tunnel: %w", err)
+	}
+
+	contextNames := []string{}
+	for i, cluster := range clusters {
+		// When there are several clusters the current context is the first of them
+		setCurrent := input.SetCurrent && i == 0
+		contextName, err := a.connectCluster(ctx, input, clusterProvider, clusterIdentity, cluster, proxyURL, setCurrent)
+		if err != nil {
+			return err
+		}
+		contextNames = append(contextNames, contextName)
 	}
-	if cluster == nil {
-		return nil
+	a.clearResumeState(input)
+
+	if input.MultiSelect {
+		a.logger.Infof("created %d contexts", len(contextNames))
+		for _, contextName := range contextNames {
+			fmt.Fprintln(os.Stdout, contextName)
+		}
 	}
 
+	return nil
+}
+
+// connectCluster creates the kubeconfig for the cluster and adds it to the history. The
+// name of the context is returned.
+func (a *App) connectCluster(ctx context.Context, input *UseInput, clusterProvider discovery.Provider, clusterIdentity identity.Identity, cluster *discovery.Cluster, proxyURL string, setCurrent bool) (string, error) {
+	allowed := input.clusterPolicy.Check(cluster) == nil
 	output, err := clusterProvider.GetConfig(ctx, &discovery.GetConfigInput{
 		Cluster:   cluster,
 		Namespace: &input.Namespace,
-		Identity:  authOutput.Identity,
+		Identity:  clusterIdentity,
+		ProxyURL:  proxyURL,
 	})
 	if err != nil {
-		return fmt.Errorf("creating kubeconfig for %s: %w", cluster.Name, err)
+		return "", fmt.Errorf("creating kubeconfig for %s: %w", cluster.Name, err)
+	}
+	// The tags of a cluster listed without its details are filled in by GetConfig, so
+	// an overridden policy is checked again to audit the connection if it's now denied
+	if allowed && input.OverridePolicy {
+		if err := a.enforceClusterPolicy(input, []*discovery.Cluster{cluster}); err != nil {
+			return "", err
+		}
+	}
+	a.maskKubeconfigCredentials(output.KubeConfig)
+	if input.contextNameTemplate != "" {
+		if err := renamePinnedContext(input, cluster, output); err != nil {
+			return "", err
+		}
+	}
+	if proxyURL != "" {
+		if err := kubeconfig.SetProxyURL(output.KubeConfig, *output.ContextName, proxyURL); err != nil {
+			return "", fmt.Errorf("setting cluster proxy url: %w", err)
+		}
+	}
+	if input.ClusterCACert != "" {
+		if err := kubeconfig.AddCertificateAuthority(output.KubeConfig, *output.ContextName, input.ClusterCACert); err != nil {
+			return "", fmt.Errorf("adding cluster ca certificate: %w", err)
+		}
+	}
+
+	if input.Kubeconfig == "" && a.ciEnv != nil {
+		input.Kubeconfig = a.ciEnv.KubeconfigPath()
 	}
 
 	historyID := input.EntryID
@@ -124,9 +300,19 @@ func (a *App) Use(ctx context.Context, input *UseInput) error {
 		entry.Spec.Identity = input.IdentityProvider
 		entry.Spec.Provider = input.DiscoveryProvider
 		entry.Spec.ProviderID = cluster.ID
+		entry.Spec.Annotations = cluster.Annotations
+		entry.SetClusterDetails(clusterDetails(cluster))
+		if expirer, ok := clusterIdentity.(identity.Expirer); ok && !expirer.ExpiresAt().IsZero() {
+			expiry := metav1.NewTime(expirer.ExpiresAt())
+			entry.Status.CredentialsExpiry = &expiry
+		}
 
-		if err := a.historyStore.Add(entry); err != nil {
-			return fmt.Errorf("adding connection to history: %w", err)
+		_, historySpan := telemetry.Start(ctx, "history.write")
+		err := a.historyStore.Add(entry)
+		historySpan.RecordError(err)
+		historySpan.Finish()
+		if err != nil {
+			return "", fmt.Errorf("adding connection to history: %w", err)
 		}
 
 		historyID = entry.ObjectMeta.Name
@@ -134,9 +320,13 @@ func (a *App) Use(ctx context.Context, input *UseInput) error {
 
 	kubeConfig := output.KubeConfig
 	contextName := *output.ContextName
+	resolveExecCommands(kubeConfig)
 	if historyID != "" {
 		historyRef := historyv1alpha.NewHistoryReference(historyID)
-		kubeConfig.Contexts[contextName].Extensions = make(map[string]runtime.Object)
+		// Keep any extensions added by the discovery provider
+		if kubeConfig.Contexts[contextName].Extensions == nil {
+			kubeConfig.Contexts[contextName].Extensions = make(map[string]runtime.Object)
+		}
 		kubeConfig.Contexts[contextName].Extensions["kconnect"] = historyRef
 	}
 
@@ -146,16 +336,207 @@ func (a *App) Use(ctx context.Context, input *UseInput) error {
 		input.Kubeconfig = pathOptions.GetDefaultFilename()
 	}
 
-	if err := kubeconfig.Write(input.Kubeconfig, kubeConfig, true, input.SetCurrent); err != nil {
-		return fmt.Errorf("writing cluster kubeconfig: %w", err)
+	kubeconfigPath := input.Kubeconfig
+	if input.pinnedKubeconfig != "" {
+		a.logger.Debugw("using kubeconfig pinned to history entry", "kubeconfig", input.pinnedKubeconfig)
+		kubeconfigPath = input.pinnedKubeconfig
+	}
+
+	_, writeSpan := telemetry.Start(ctx, "kubeconfig.write", "context", contextName)
+	err = kubeconfig.Write(kubeconfigPath, kubeConfig, true, setCurrent)
+	writeSpan.RecordError(err)
+	writeSpan.Finish()
+	if err != nil {
+		return "", kerrors.WithCode(kerrors.CodeKubeconfigWriteFailed, fmt.Errorf("writing cluster kubeconfig: %w", err))
+	}
+	if input.WSLInterop {
+		a.writeWindowsKubeconfig(kubeConfig, setCurrent)
+	}
+
+	a.reportToCI(input, cluster, contextName)
+
+	if input.VerifyConnection {
+		a.verifyConnection(ctx, kubeConfig, contextName)
+	}
+
+	return contextName, nil
+}
+
+// useProviders creates the identity and discovery providers to use. The discovery
+// provider is wrapped with the filtering, enriching, caching and cluster policy middleware.
+func (a *App) useProviders(input *UseInput, identityHTTPOpts, discoveryHTTPOpts []khttp.ClientOption) (identity.Provider, discovery.Provider, error) {
+	identityProvider, err := a.getIdentityProvider(&input.IdentityProvider, &input.DiscoveryProvider, identityHTTPOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting identity provider: %w", err)
+	}
+	clusterProvider, err := a.getDiscoveryProvider(&input.DiscoveryProvider, &input.IdentityProvider, discoveryHTTPOpts)
+	if err != nil {
+		return nil, nil, fmt.Errorf("getting discovery provider: %w", err)
+	}
+	// The filter only applies when choosing from the discovered clusters
+	if input.ClusterFilter != "" && (input.ClusterID == nil || *input.ClusterID == "") && !pluginRegistration(clusterProvider.Name()).HasCapability(registry.CapabilitySupportsFiltering) {
+		clusterProvider = discovery.Chain(clusterProvider, discovery.FilterMiddleware(discovery.NameFilter(input.ClusterFilter)))
+	}
+	// Enrich after filtering so only the clusters that can be selected are enriched
+	if enrichers := registry.ListEnrichers(); len(enrichers) > 0 {
+		clusterProvider = discovery.Chain(clusterProvider, discovery.EnrichMiddleware(a.logger, enrichers...))
+	}
+	// Cache after filtering and enriching so the cached clusters are ready to select from.
+	// When offline the last discovered clusters are used even if they have expired.
+	switch {
+	case khttp.OfflineEnabled():
+		discoveryCache := cache.NewReadOnly(defaults.CacheDirectory())
+		clusterProvider = discovery.Chain(clusterProvider, discovery.CacheMiddleware(discoveryCache, input.ClusterFilter, false, a.logger))
+	case input.DiscoveryCacheTTL > 0:
+		discoveryCache := cache.New(defaults.CacheDirectory(), input.DiscoveryCacheTTL)
+		clusterProvider = discovery.Chain(clusterProvider, discovery.CacheMiddleware(discoveryCache, input.ClusterFilter, input.Refresh, a.logger))
+	}
+	// The policy is applied to the cached clusters so that changes to it apply straight away
+	if input.clusterPolicy != nil && !input.OverridePolicy {
+		clusterProvider = discovery.Chain(clusterProvider, discovery.PolicyMiddleware(input.clusterPolicy))
+	}
+
+	return identityProvider, clusterProvider, nil
+}
+
+// authenticate will authenticate using the identity provider and then resolve the
+// config items of the discovery provider for the identity
+func (a *App) authenticate(ctx context.Context, identityProvider identity.Provider, clusterProvider discovery.Provider, cs config.ConfigurationSet) (identity.Identity, error) {
+	id, err := a.authenticateIdentity(ctx, identityProvider, cs)
+	if err != nil {
+		return nil, err
+	}
+	if err := a.resolveDiscoveryConfig(ctx, clusterProvider, cs, id); err != nil {
+		return nil, err
+	}
+
+	return id, nil
+}
+
+// authenticateIdentity will authenticate using the identity provider. When running
+// interactively the credentials rejected by the identity provider, e.g. a wrong password,
+// are asked for again until the auth-attempts limit is reached.
+func (a *App) authenticateIdentity(ctx context.Context, identityProvider identity.Provider, cs config.ConfigurationSet) (identity.Identity, error) {
+	maxAttempts := authAttempts(cs)
+	for attempt := 1; ; attempt++ {
+		authCtx, authSpan := telemetry.Start(ctx, "identity.authenticate", "provider", identityProvider.Name(), "attempt", strconv.Itoa(attempt))
+		authOutput, err := identityProvider.Authenticate(authCtx, &identity.AuthenticateInput{
+			ConfigSet: cs,
+		})
+		authSpan.RecordError(err)
+		authSpan.Finish()
+		if err == nil {
+			return authOutput.Identity, nil
+		}
+
+		rejected := identity.RejectedItems(err)
+		if !a.interactive || attempt >= maxAttempts || len(rejected) == 0 {
+			return nil, kerrors.WithCode(kerrors.CodeAuthFailed, fmt.Errorf("authenticating using provider %s: %w", identityProvider.Name(), err))
+		}
+		a.logger.Warnw("credentials rejected by the identity provider", "provider", identityProvider.Name(), "code", kerrors.CodeOf(err), "reason", err.Error(), "attempt", attempt, "max-attempts", maxAttempts)
+		if err := reenterItems(cs, rejected, attempt, maxAttempts); err != nil {
+			return nil, err
+		}
+	}
+}
+
+// reenterItems asks for the values of the rejected config items again
+func reenterItems(cs config.ConfigurationSet, items []string, attempt, maxAttempts int) error {
+	for _, name := range items {
+		item := cs.Get(name)
+		if item == nil {
+			return fmt.Errorf("getting rejected config item %s: %w", name, config.ErrConfigNotFound)
+		}
+		// Clear the rejected value so that it's asked for, whatever its source was
+		item.Value = nil
+
+		message := fmt.Sprintf("The %s was rejected, enter it again (attempt %d of %d)", name, attempt+1, maxAttempts)
+		if err := prompt.InputAndSet(cs, name, message, true); err != nil {
+			return fmt.Errorf("entering %s again: %w", name, err)
+		}
 	}
 
 	return nil
 }
 
+// authAttempts returns how many times the rejected credentials can be entered, at
+// least 1 attempt is always made
+func authAttempts(cs config.ConfigurationSet) int {
+	useCfg := &CommonUseConfig{}
+	if !cs.Exists(AuthAttemptsConfigItem) || config.Unmarshall(cs, useCfg) != nil || useCfg.AuthAttempts < 1 {
+		return 1
+	}
+
+	return useCfg.AuthAttempts
+}
+
+// resolveDiscoveryConfig will resolve the config items of the discovery provider for the identity
+func (a *App) resolveDiscoveryConfig(ctx context.Context, clusterProvider discovery.Provider, cs config.ConfigurationSet, id identity.Identity) error {
+	_, resolveSpan := telemetry.Start(ctx, "discovery.resolve", "provider", clusterProvider.Name())
+	err := clusterProvider.Resolve(cs, id)
+	resolveSpan.RecordError(err)
+	resolveSpan.Finish()
+	if err != nil {
+		return fmt.Errorf("resolving config items: %w", err)
+	}
+
+	if a.interactive {
+		if err := prompt.ResolveDependencies(cs); err != nil {
+			return fmt.Errorf("resolving dependent config items: %w", err)
+		}
+	}
+
+	return config.ValidateRequiredWhen(cs)
+}
+
+// checkPreReqs will check the pre-requisites of the discovery provider. If install is true
+// then any missing pre-requisites will be installed into the kconnect bin directory.
+func (a *App) checkPreReqs(ctx context.Context, clusterProvider discovery.Provider, install bool) error {
+	err := clusterProvider.CheckPreReqs()
+	if err == nil || !install {
+		return err
+	}
+
+	if err := prereqs.Install(ctx, clusterProvider.ListPreReqs()); err != nil {
+		return fmt.Errorf("installing pre-requisites: %w", err)
+	}
+
+	return clusterProvider.CheckPreReqs()
+}
+
+// resolveExecCommands will update the exec commands in the kubeconfig to use the
+// binaries installed by kconnect when they aren't on the PATH
+func resolveExecCommands(cfg *api.Config) {
+	for _, authInfo := range cfg.AuthInfos {
+		if authInfo == nil || authInfo.Exec == nil {
+			continue
+		}
+		authInfo.Exec.Command = prereqs.ResolveCommand(authInfo.Exec.Command)
+	}
+}
+
 func (a *App) discoverCluster(ctx context.Context, clusterProvider discovery.Provider, identity identity.Identity, params *UseInput) (*discovery.Cluster, error) {
 	a.logger.Infow("discovering clusters", "provider", params.DiscoveryProvider)
 
+	if a.interactive && !a.customSelect && prompt.IsTerminal() {
+		return a.streamCluster(ctx, clusterProvider, identity, params)
+	}
+
+	discoverOutput, err := clusterProvider.Discover(ctx, &discovery.DiscoverInput{
+		ConfigSet: params.ConfigSet,
+		Identity:  identity,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("discovering clusters using %s: %w", clusterProvider.Name(), err)
+	}
+
+	return a.chooseCluster(ctx, clusterProvider, discoverOutput)
+}
+
+// discoverClusters discovers the clusters and asks the user to choose any number of them
+func (a *App) discoverClusters(ctx context.Context, clusterProvider discovery.Provider, identity identity.Identity, params *UseInput) ([]*discovery.Cluster, error) {
+	a.logger.Infow("discovering clusters", "provider", params.DiscoveryProvider)
+
 	discoverOutput, err := clusterProvider.Discover(ctx, &discovery.DiscoverInput{
 		ConfigSet: params.ConfigSet,
 		Identity:  identity,
@@ -163,13 +544,36 @@ func (a *App) discoverCluster(ctx context.Context, clusterProvider discovery.Pro
 	if err != nil {
 		return nil, fmt.Errorf("discovering clusters using %s: %w", clusterProvider.Name(), err)
 	}
+	if len(discoverOutput.Clusters) == 0 {
+		return nil, ErrNoClustersFound
+	}
+
+	_, selectSpan := telemetry.Start(ctx, "cluster.select", "clusters", strconv.Itoa(len(discoverOutput.Clusters)))
+	clusters, err := selectClusters(discoverOutput)
+	selectSpan.RecordError(err)
+	selectSpan.Finish()
+	if err != nil {
+		return nil, fmt.Errorf("selecting clusters: %w", err)
+	}
+
+	return clusters, nil
+}
+
+// chooseCluster selects one of the discovered clusters
+func (a *App) chooseCluster(ctx context.Context, clusterProvider discovery.Provider, discoverOutput *discovery.DiscoverOutput) (*discovery.Cluster, error) {
+	discoReg := pluginRegistration(clusterProvider.Name())
+	if !discoReg.HasCapability(registry.CapabilitySupportsPagination) {
+		a.logger.Debugw("discovery plugin doesn't page through results, some clusters may not be shown", "provider", clusterProvider.Name())
+	}
 
 	if discoverOutput.Clusters == nil || len(discoverOutput.Clusters) == 0 {
-		a.logger.Warn("no clusters discovered")
-		return nil, nil
+		return nil, ErrNoClustersFound
 	}
 
+	_, selectSpan := telemetry.Start(ctx, "cluster.select", "clusters", strconv.Itoa(len(discoverOutput.Clusters)))
 	cluster, err := a.selectCluster(discoverOutput)
+	selectSpan.RecordError(err)
+	selectSpan.Finish()
 	if err != nil {
 		return nil, fmt.Errorf("selecting cluster: %w", err)
 	}
@@ -177,6 +581,27 @@ func (a *App) discoverCluster(ctx context.Context, clusterProvider discovery.Pro
 	return cluster, nil
 }
 
+// findCluster is used to get a cluster by its id when the discovery plugin doesn't
+// support getting a single cluster
+func (a *App) findCluster(ctx context.Context, clusterProvider discovery.Provider, identity identity.Identity, params *UseInput) (*discovery.Cluster, error) {
+	a.logger.Infow("discovering cluster details", "id", *params.ClusterID, "provider", params.DiscoveryProvider)
+
+	discoverOutput, err := clusterProvider.Discover(ctx, &discovery.DiscoverInput{
+		ConfigSet: params.ConfigSet,
+		Identity:  identity,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("discovering clusters using %s: %w", clusterProvider.Name(), err)
+	}
+
+	cluster, found := discoverOutput.Clusters[*params.ClusterID]
+	if !found {
+		return nil, fmt.Errorf("getting cluster with id %s: %w", *params.ClusterID, ErrClusterNotFound)
+	}
+
+	return cluster, nil
+}
+
 func (a *App) getCluster(ctx context.Context, clusterProvider discovery.Provider, identity identity.Identity, params *UseInput) (*discovery.Cluster, error) {
 	a.logger.Infow("getting cluster details", "id", *params.ClusterID, "provider", params.DiscoveryProvider)
 
@@ -204,19 +629,7 @@ func (a *App) filterConfig(params *UseInput) map[string]string {
 			continue
 		}
 
-		val := ""
-		switch configItem.Type {
-		case config.ItemTypeString:
-			val = configItem.Value.(string)
-		case config.ItemTypeBool:
-			boolVal := configItem.Value.(bool)
-			val = strconv.FormatBool(boolVal)
-		case config.ItemTypeInt:
-			intVal := configItem.Value.(int)
-			val = strconv.Itoa(intVal)
-		}
-
-		filteredConfig[configItem.Name] = val
+		filteredConfig[configItem.Name] = configItem.ValueString()
 	}
 
 	return filteredConfig
@@ -258,7 +671,7 @@ func (a *App) resolveAndCheckAlias(params *UseInput) error {
 		return ErrAliasAlreadyUsed
 	}
 
-	a.logger.Infof("Command to reconnect using this alias: kconnect to %s", *params.Alias)
+	a.logger.Infof("Command to reconnect using this alias: %s to %s", utils.CommandName(), *params.Alias)
 
 	return nil
 }
@@ -281,36 +694,82 @@ func (a *App) resolveAlias() (string, error) {
 	return alias, nil
 }
 
-func (a *App) getDiscoveryProvider(name *string, scopedToIdentityProvider *string) (discovery.Provider, error) {
+// httpClientOptions returns the options to create a http client that retries failed
+// requests, uses the proxy and trusts the certificate authorities in the CA file
+func httpClientOptions(proxy, caCertFile string, cfg *CommonUseConfig) ([]khttp.ClientOption, error) {
+	opts := []khttp.ClientOption{khttp.WithRetryPolicy(cfg.RetryPolicy())}
+	if proxy != "" {
+		opt, err := khttp.WithProxyConfig(&khttp.ProxyConfig{
+			URL:      proxy,
+			NoProxy:  cfg.NoProxy,
+			Username: cfg.ProxyUsername,
+			Password: cfg.ProxyPassword,
+		})
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+	if caCertFile != "" {
+		opt, err := khttp.WithCACertFile(caCertFile)
+		if err != nil {
+			return nil, err
+		}
+		opts = append(opts, opt)
+	}
+
+	return opts, nil
+}
+
+// providerHTTPClient returns the http client for a provider, which is the app client
+// unless the provider has its own client options such as a proxy
+func (a *App) providerHTTPClient(opts []khttp.ClientOption) khttp.Client {
+	if len(opts) == 0 {
+		return a.httpClient
+	}
+
+	return khttp.NewHTTPClient(opts...)
+}
+
+func (a *App) getDiscoveryProvider(name *string, scopedToIdentityProvider *string, httpClientOpts []khttp.ClientOption) (discovery.Provider, error) {
 	if name == nil || *name == "" {
 		return nil, ErrDiscoveryProviderRequired
 	}
 
 	prov, err := registry.GetDiscoveryProvider(*name, &provider.PluginCreationInput{
-		Logger:        a.logger.With("provider", name),
-		IsInteractice: a.interactive,
-		ItemSelector:  a.itemSelector,
-		ScopedTo:      scopedToIdentityProvider,
-		HTTPClient:    a.httpClient,
+		Logger:            a.logger.Named(*name).With("provider", name),
+		IsInteractice:     a.interactive,
+		ItemSelector:      a.itemSelector,
+		ScopedTo:          scopedToIdentityProvider,
+		HTTPClient:        a.providerHTTPClient(httpClientOpts),
+		HTTPClientOptions: httpClientOpts,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("getting discovery provider %s: %w", *name, err)
 	}
 
-	return prov, nil
+	middleware := []discovery.Middleware{
+		discovery.LoggingMiddleware(a.logger),
+		discovery.TimingMiddleware(a.logger),
+		discovery.TracingMiddleware(),
+	}
+	middleware = append(middleware, a.discoveryMiddleware...)
+
+	return discovery.Chain(prov, middleware...), nil
 }
 
-func (a *App) getIdentityProvider(name *string, scopedToDiscoveryProvider *string) (identity.Provider, error) {
+func (a *App) getIdentityProvider(name *string, scopedToDiscoveryProvider *string, httpClientOpts []khttp.ClientOption) (identity.Provider, error) {
 	if name == nil || *name == "" {
 		return nil, ErrIdentityProviderRequired
 	}
 
 	prov, err := registry.GetIdentityProvider(*name, &provider.PluginCreationInput{
-		Logger:        a.logger.With("provider", name),
-		IsInteractice: a.interactive,
-		ItemSelector:  a.itemSelector,
-		ScopedTo:      scopedToDiscoveryProvider,
-		HTTPClient:    a.httpClient,
+		Logger:            a.logger.Named(*name).With("provider", name),
+		IsInteractice:     a.interactive,
+		ItemSelector:      a.itemSelector,
+		ScopedTo:          scopedToDiscoveryProvider,
+		HTTPClient:        a.providerHTTPClient(httpClientOpts),
+		HTTPClientOptions: httpClientOpts,
 	})
 	if err != nil {
 		return nil, fmt.Errorf("getting identity provider %s: %w", *name, err)
@@ -0,0 +1,55 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package app
+
+import (
+	"path/filepath"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+// writeWindowsKubeconfig also writes the kubeconfig to the Windows user profile when
+// running in WSL so that Windows tools, e.g. kubectl.exe or Lens, can use the cluster.
+// Failing to write it doesn't fail the command as the WSL kubeconfig has been written.
+func (a *App) writeWindowsKubeconfig(kubeConfig *api.Config, setCurrent bool) {
+	if !utils.IsWSL() {
+		a.logger.Warnf("--%s is set but kconnect isn't running in WSL, the kubeconfig is only written once", WSLInteropConfigItem)
+		return
+	}
+
+	profile, err := utils.WindowsUserProfile()
+	if err != nil {
+		a.logger.Warnw("failed writing the kubeconfig to the windows user profile", "error", err.Error())
+		return
+	}
+	windowsPath := filepath.Join(profile, ".kube", "config")
+
+	for name, authInfo := range kubeConfig.AuthInfos {
+		if authInfo != nil && authInfo.Exec != nil {
+			a.logger.Warnw("the kubeconfig user runs a command to get credentials, it must also be installed in windows", "user", name, "command", authInfo.Exec.Command)
+		}
+	}
+
+	if err := kubeconfig.Write(windowsPath, kubeConfig, true, setCurrent); err != nil {
+		a.logger.Warnw("failed writing the kubeconfig to the windows user profile", "path", windowsPath, "error", err.Error())
+		return
+	}
+	a.logger.Infow("kubeconfig written to the windows user profile", "path", windowsPath)
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/user"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+const (
+	// ActionPolicyOverride is recorded when a cluster denied by the cluster policy is connected to
+	ActionPolicyOverride = "cluster-policy-override"
+)
+
+var writeLock sync.Mutex
+
+// Record is an entry in the audit log. Each record is written as a line of JSON so
+// that the log can be collected by the tools an organisation already uses.
+type Record struct {
+	Time     time.Time `json:"time"`
+	Action   string    `json:"action"`
+	User     string    `json:"user,omitempty"`
+	Host     string    `json:"host,omitempty"`
+	Provider string    `json:"provider,omitempty"`
+	Cluster  string    `json:"cluster,omitempty"`
+	// ClusterID is the provider specific id of the cluster
+	ClusterID string `json:"clusterID,omitempty"`
+	Reason    string `json:"reason,omitempty"`
+}
+
+// NewRecord creates a record of the action by the current user on this host
+func NewRecord(action string) *Record {
+	record := &Record{
+		Time:   time.Now().UTC(),
+		Action: action,
+	}
+	if currentUser, err := user.Current(); err == nil {
+		record.User = currentUser.Username
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		record.Host = hostname
+	}
+
+	return record
+}
+
+// Write will append the record to the audit log at the path. The log is only
+// readable by the user.
+func Write(path string, record *Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("marshalling audit record: %w", err)
+	}
+
+	writeLock.Lock()
+	defer writeLock.Unlock()
+
+	if err := os.MkdirAll(filepath.Dir(path), os.ModePerm); err != nil {
+		return fmt.Errorf("creating audit log directory: %w", err)
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening audit log %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		return fmt.Errorf("writing audit log %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package audit_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/audit"
+)
+
+func TestWrite(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "logs", "audit.log")
+
+	for _, cluster := range []string{"prod-1", "prod-2"} {
+		record := audit.NewRecord(audit.ActionPolicyOverride)
+		record.Provider = "eks"
+		record.Cluster = cluster
+		g.Expect(audit.Write(path, record)).To(Succeed())
+	}
+
+	file, err := os.Open(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	defer file.Close()
+
+	clusters := []string{}
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		record := &audit.Record{}
+		g.Expect(json.Unmarshal(scanner.Bytes(), record)).To(Succeed())
+		g.Expect(record.Action).To(Equal(audit.ActionPolicyOverride))
+		g.Expect(record.Time.IsZero()).To(BeFalse())
+		clusters = append(clusters, record.Cluster)
+	}
+	g.Expect(clusters).To(Equal([]string{"prod-1", "prod-2"}))
+
+	info, err := os.Stat(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+}
@@ -18,7 +18,6 @@ package awsconfig
 
 import (
 	"os"
-	"path"
 	"path/filepath"
 	"runtime"
 
@@ -38,7 +37,7 @@ func LocateConfigFile() (string, error) {
 	var name string
 	var err error
 	if runtime.GOOS == "windows" {
-		name = path.Join(os.Getenv("USERPROFILE"), ".aws", "credentials")
+		name = filepath.Join(os.Getenv("USERPROFILE"), ".aws", "credentials")
 	} else {
 		name, err = homedir.Expand("~/.aws/credentials")
 		if err != nil {
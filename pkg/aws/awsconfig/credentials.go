@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsconfig
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/config"
+	"gopkg.in/ini.v1"
+)
+
+// credentialProcessVersion is the version of the credential_process output format
+const credentialProcessVersion = 1
+
+var ErrNoCredentials = errors.New("no credentials for the profile in the aws credentials file")
+
+// Credentials are the temporary credentials of a profile in the aws credentials file
+type Credentials struct {
+	AccessKeyID     string
+	SecretAccessKey string
+	SessionToken    string
+	// Expires is when the credentials expire, it's zero if it isn't known
+	Expires time.Time
+}
+
+// Expired returns true if the credentials expire within the window, they're never
+// expired if the expiry isn't known
+func (c *Credentials) Expired(window time.Duration) bool {
+	return !c.Expires.IsZero() && time.Now().Add(window).After(c.Expires)
+}
+
+// CredentialProcessOutput is the output of a credential_process for the aws cli and sdks
+type CredentialProcessOutput struct {
+	Version         int    `json:"Version"`
+	AccessKeyID     string `json:"AccessKeyId"`
+	SecretAccessKey string `json:"SecretAccessKey"`
+	SessionToken    string `json:"SessionToken,omitempty"`
+	Expiration      string `json:"Expiration,omitempty"`
+}
+
+// CredentialProcessOutput returns the credentials in the credential_process format
+func (c *Credentials) CredentialProcessOutput() *CredentialProcessOutput {
+	output := &CredentialProcessOutput{
+		Version:         credentialProcessVersion,
+		AccessKeyID:     c.AccessKeyID,
+		SecretAccessKey: c.SecretAccessKey,
+		SessionToken:    c.SessionToken,
+	}
+	if !c.Expires.IsZero() {
+		output.Expiration = c.Expires.UTC().Format(time.RFC3339)
+	}
+
+	return output
+}
+
+// LoadCredentials reads the credentials of the profile from the aws credentials file,
+// e.g. the credentials saved by the saml identity provider
+func LoadCredentials(profile string) (*Credentials, error) {
+	path, err := LocateConfigFile()
+	if err != nil {
+		return nil, fmt.Errorf("locating aws credentials file: %w", err)
+	}
+	cfg, err := ini.Load(path)
+	if err != nil {
+		return nil, fmt.Errorf("loading aws credentials file %s: %w", path, err)
+	}
+	section, err := cfg.GetSection(profile)
+	if err != nil {
+		return nil, fmt.Errorf("profile %s: %w", profile, ErrNoCredentials)
+	}
+
+	creds := &Credentials{
+		AccessKeyID:     section.Key("aws_access_key_id").String(),
+		SecretAccessKey: section.Key("aws_secret_access_key").String(),
+		SessionToken:    section.Key("aws_session_token").String(),
+	}
+	if creds.AccessKeyID == "" || creds.SecretAccessKey == "" {
+		return nil, fmt.Errorf("profile %s: %w", profile, ErrNoCredentials)
+	}
+	if expires := section.Key("x_security_token_expires").String(); expires != "" {
+		creds.Expires, err = time.Parse(time.RFC3339, expires)
+		if err != nil {
+			return nil, fmt.Errorf("parsing expiry of profile %s: %w", profile, err)
+		}
+	}
+
+	return creds, nil
+}
+
+// ResolveCredentials resolves the credentials of the profile in the same way as the aws
+// cli and sdks, e.g. from the SSO cache or by assuming the role of the profile
+func ResolveCredentials(ctx context.Context, profile string) (*Credentials, error) {
+	cfg, err := config.LoadDefaultConfig(ctx, config.WithSharedConfigProfile(profile))
+	if err != nil {
+		return nil, fmt.Errorf("loading aws config for profile %s: %w", profile, err)
+	}
+	awsCreds, err := cfg.Credentials.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("retrieving credentials for profile %s: %w", profile, err)
+	}
+
+	creds := &Credentials{
+		AccessKeyID:     awsCreds.AccessKeyID,
+		SecretAccessKey: awsCreds.SecretAccessKey,
+		SessionToken:    awsCreds.SessionToken,
+	}
+	if awsCreds.CanExpire {
+		creds.Expires = awsCreds.Expires
+	}
+
+	return creds, nil
+}
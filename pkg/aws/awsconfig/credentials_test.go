@@ -0,0 +1,98 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package awsconfig_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/aws/awsconfig"
+)
+
+func TestLoadCredentials(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "credentials")
+	g.Expect(os.WriteFile(path, []byte(`[kconnect-saml-dev]
+aws_access_key_id        = ASIAEXAMPLE
+aws_secret_access_key    = secret
+aws_session_token        = session
+x_security_token_expires = 2030-01-02T03:04:05Z
+
+[no-keys]
+region = eu-west-1
+`), 0600)).To(Succeed())
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", path)
+
+	creds, err := awsconfig.LoadCredentials("kconnect-saml-dev")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(creds.Expired(5 * time.Minute)).To(BeFalse())
+	g.Expect(creds.CredentialProcessOutput()).To(Equal(&awsconfig.CredentialProcessOutput{
+		Version:         1,
+		AccessKeyID:     "ASIAEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+		Expiration:      "2030-01-02T03:04:05Z",
+	}))
+
+	creds.Expires = time.Now().Add(time.Minute)
+	g.Expect(creds.Expired(5 * time.Minute)).To(BeTrue())
+
+	_, err = awsconfig.LoadCredentials("no-keys")
+	g.Expect(errors.Is(err, awsconfig.ErrNoCredentials)).To(BeTrue())
+
+	_, err = awsconfig.LoadCredentials("missing")
+	g.Expect(errors.Is(err, awsconfig.ErrNoCredentials)).To(BeTrue())
+}
+
+func TestResolveCredentials(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the credential process")
+	}
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	script := filepath.Join(dir, "sso-credentials")
+	g.Expect(os.WriteFile(script, []byte(`#!/bin/sh
+echo '{"Version":1,"AccessKeyId":"ASIASSO","SecretAccessKey":"secret","SessionToken":"session","Expiration":"2030-01-02T03:04:05Z"}'
+`), 0700)).To(Succeed()) //nolint:gosec
+	configPath := filepath.Join(dir, "config")
+	g.Expect(os.WriteFile(configPath, []byte("[profile dev]\ncredential_process = "+script+"\n"), 0600)).To(Succeed())
+	t.Setenv("AWS_CONFIG_FILE", configPath)
+	t.Setenv("AWS_SHARED_CREDENTIALS_FILE", filepath.Join(dir, "credentials"))
+	t.Setenv("AWS_EC2_METADATA_DISABLED", "true")
+
+	creds, err := awsconfig.ResolveCredentials(context.TODO(), "dev")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(creds.CredentialProcessOutput()).To(Equal(&awsconfig.CredentialProcessOutput{
+		Version:         1,
+		AccessKeyID:     "ASIASSO",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+		Expiration:      "2030-01-02T03:04:05Z",
+	}))
+
+	_, err = awsconfig.ResolveCredentials(context.TODO(), "missing")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -17,62 +17,139 @@ limitations under the License.
 package aws
 
 import (
+	"context"
 	"fmt"
-
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/client"
-	"github.com/aws/aws-sdk-go/aws/credentials"
-	"github.com/aws/aws-sdk-go/aws/request"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/eks"
-	"github.com/aws/aws-sdk-go/service/eks/eksiface"
-	"github.com/aws/aws-sdk-go/service/iam"
-	"github.com/aws/aws-sdk-go/service/iam/iamiface"
+	"net/http"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	awsmiddleware "github.com/aws/aws-sdk-go-v2/aws/middleware"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	awshttp "github.com/aws/aws-sdk-go-v2/aws/transport/http"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/credentials"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
 
 	"github.com/fidelity/kconnect/internal/version"
+	khttp "github.com/fidelity/kconnect/pkg/http"
 )
 
-func NewSession(region, profile, accessKey, secretKey, sessionToken string) (*session.Session, error) {
-	cfg := aws.Config{
-		Region: aws.String(region),
-	}
+const (
+	// retryMaxAttempts is the number of attempts made for a request, which can be
+	// changed with max_attempts in the profile or AWS_MAX_ATTEMPTS
+	retryMaxAttempts = 5
+	// retryMaxBackoff is the longest the backoff between the attempts can be
+	retryMaxBackoff = 10 * time.Second
+
+	userAgentKey = "kconnect.fidelity.github.com"
+)
+
+// EKSAPI is the part of the EKS API that's used to discover clusters
+type EKSAPI interface {
+	ListClusters(ctx context.Context, params *eks.ListClustersInput, optFns ...func(*eks.Options)) (*eks.ListClustersOutput, error)
+	DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error)
+}
+
+// STSAPI is the part of the STS API that's used to get the caller identity
+type STSAPI interface {
+	GetCallerIdentity(ctx context.Context, params *sts.GetCallerIdentityInput, optFns ...func(*sts.Options)) (*sts.GetCallerIdentityOutput, error)
+}
+
+// IAMAPI is the part of the IAM API that's used to get the account alias
+type IAMAPI interface {
+	ListAccountAliases(ctx context.Context, params *iam.ListAccountAliasesInput, optFns ...func(*iam.Options)) (*iam.ListAccountAliasesOutput, error)
+}
 
+// OrganizationsAPI is the part of the Organizations API that's used to get the account name
+type OrganizationsAPI interface {
+	DescribeAccount(ctx context.Context, params *organizations.DescribeAccountInput, optFns ...func(*organizations.Options)) (*organizations.DescribeAccountOutput, error)
+}
+
+// NewConfig will create a new AWS config. When a profile is supplied the config is
+// loaded from the shared config and so the region, role_arn, source_profile,
+// credential_process and sso_session of the profile are used. The service endpoints
+// are overridden with any endpoints that have been supplied.
+func NewConfig(ctx context.Context, region, profile, accessKey, secretKey, sessionToken string, serviceEndpoints *Endpoints) (aws.Config, error) {
+	options := []func(*config.LoadOptions) error{
+		config.WithRetryer(newRetryer),
+		config.WithAPIOptions([]func(*middleware.Stack) error{
+			awsmiddleware.AddUserAgentKeyValue(userAgentKey, version.Get().String()),
+		}),
+		config.WithAssumeRoleCredentialOptions(func(o *stscreds.AssumeRoleOptions) {
+			o.TokenProvider = stscreds.StdinTokenProvider
+		}),
+	}
+	if region != "" {
+		options = append(options, config.WithRegion(region))
+	}
 	if profile != "" {
-		cfg.Credentials = credentials.NewSharedCredentials("", profile)
-	} else if accessKey != "" && secretKey != "" {
-		cfg.Credentials = credentials.NewStaticCredentials(accessKey, secretKey, sessionToken)
+		options = append(options, config.WithSharedConfigProfile(profile))
 	}
-
-	options := session.Options{
-		SharedConfigState: session.SharedConfigEnable,
-		Config:            cfg,
+	if profile == "" && accessKey != "" && secretKey != "" {
+		options = append(options, config.WithCredentialsProvider(credentials.NewStaticCredentialsProvider(accessKey, secretKey, sessionToken)))
+	}
+	if serviceEndpoints != nil && serviceEndpoints.IsSet() {
+		options = append(options, config.WithEndpointResolverWithOptions(serviceEndpoints.Resolver()))
 	}
+	options = append(options, config.WithHTTPClient(newHTTPClient()))
 
-	awsSession, err := session.NewSessionWithOptions(options)
+	cfg, err := config.LoadDefaultConfig(ctx, options...)
 	if err != nil {
-		return nil, fmt.Errorf("creating new aws session in region %s using creds: %w", region, err)
+		return aws.Config{}, fmt.Errorf("loading aws config in region %s: %w", region, err)
+	}
+	if cfg.Region == "" {
+		return aws.Config{}, ErrNoRegion
 	}
 
-	return awsSession, nil
+	return cfg, nil
 }
 
-func NewIAMClient(session client.ConfigProvider) iamiface.IAMAPI {
-	iamClient := iam.New(session)
-	iamClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+// newHTTPClient returns the client of the AWS SDK. It propagates the trace context to
+// AWS and, when that's enabled, traces and records or replays the requests.
+func newHTTPClient() *http.Client {
+	client := awshttp.NewBuildableClient()
+	var transport http.RoundTripper = client.GetTransport()
+	if khttp.TracingEnabled() || khttp.RecordingEnabled() {
+		transport = khttp.TraceTransport(khttp.RecordTransport(transport))
+	}
 
-	return iamClient
+	return &http.Client{
+		Transport: khttp.PropagateTransport(transport),
+		Timeout:   client.GetTimeout(),
+	}
 }
 
-func NewEKSClient(session client.ConfigProvider) eksiface.EKSAPI {
-	eksClient := eks.New(session)
-	eksClient.Handlers.Build.PushFrontNamed(getUserAgentHandler())
+// newRetryer returns the standard retryer, which backs off exponentially with jitter
+// and retries throttled requests
+func newRetryer() aws.Retryer {
+	return retry.NewStandard(func(o *retry.StandardOptions) {
+		o.MaxAttempts = retryMaxAttempts
+		o.MaxBackoff = retryMaxBackoff
+	})
+}
 
-	return eksClient
+func NewIAMClient(cfg aws.Config) *iam.Client {
+	return iam.NewFromConfig(cfg)
 }
 
-func getUserAgentHandler() request.NamedHandler {
-	return request.NamedHandler{
-		Name: "kconnect/user-agent",
-		Fn:   request.MakeAddToUserAgentHandler("kconnect.fidelity.github.com", version.Get().String()),
-	}
+func NewEKSClient(cfg aws.Config) *eks.Client {
+	return eks.NewFromConfig(cfg)
+}
+
+func NewEC2Client(cfg aws.Config) *ec2.Client {
+	return ec2.NewFromConfig(cfg)
+}
+
+func NewOrganizationsClient(cfg aws.Config) *organizations.Client {
+	return organizations.NewFromConfig(cfg)
+}
+
+func NewSTSClient(cfg aws.Config) *sts.Client {
+	return sts.NewFromConfig(cfg)
 }
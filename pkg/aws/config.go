@@ -17,7 +17,6 @@ limitations under the License.
 package aws
 
 import (
-	"github.com/aws/aws-sdk-go/aws/endpoints"
 	"github.com/fidelity/kconnect/pkg/config"
 )
 
@@ -25,9 +24,17 @@ const (
 	RegionConfigItem       = "region"
 	PartitionConfigItem    = "partition"
 	ProfileConfigItem      = "profile"
+	AWSProfileConfigItem   = "aws-profile"
 	AccessKeyConfigItem    = "access-key"
 	SecretKeyConfigItem    = "secret-key"
 	SessionTokenConfigItem = "session-token"
+	EKSEndpointConfigItem  = "eks-endpoint"
+	IAMEndpointConfigItem  = "iam-endpoint"
+	STSEndpointConfigItem  = "sts-endpoint"
+	AssumeRoleConfigItem   = "assume-role-arn"
+	ExternalIDConfigItem   = "external-id"
+	MFASerialConfigItem    = "mfa-serial"
+	MFATokenConfigItem     = "mfa-token"
 )
 
 // SharedConfig will return shared configuration items for AWS based cluster and identity providers
@@ -35,6 +42,7 @@ func SharedConfig() config.ConfigurationSet {
 	cs := config.NewConfigurationSet()
 	AddPartitionConfig(cs)
 	AddRegionConfig(cs)
+	AddEndpointConfig(cs)
 	cs.String("static-profile", "", "AWS profile to use. Only for advanced use cases") //nolint: errcheck
 	cs.SetHidden("static-profile")                                                     //nolint: errcheck
 
@@ -42,13 +50,13 @@ func SharedConfig() config.ConfigurationSet {
 }
 
 func AddRegionConfig(cs config.ConfigurationSet) {
-	cs.String(RegionConfigItem, "", "AWS region to connect to") //nolint: errcheck
-	cs.SetRequired(RegionConfigItem)                            //nolint: errcheck
+	cs.String(RegionConfigItem, "", "AWS region to connect to. Multiple regions can be separated by commas") //nolint: errcheck
+	cs.SetRequired(RegionConfigItem)                                                                         //nolint: errcheck
 }
 
 func AddPartitionConfig(cs config.ConfigurationSet) {
-	cs.String(PartitionConfigItem, endpoints.AwsPartition().ID(), "AWS partition to use") //nolint: errcheck
-	cs.SetRequired(ProfileConfigItem)                                                     //nolint: errcheck
+	cs.String(PartitionConfigItem, AWSPartitionID, "AWS partition to use, e.g. aws-us-gov or aws-cn") //nolint: errcheck
+	cs.SetRequired(ProfileConfigItem)                                                                 //nolint: errcheck
 }
 
 func AddIAMConfigs(cs config.ConfigurationSet) {
@@ -56,4 +64,23 @@ func AddIAMConfigs(cs config.ConfigurationSet) {
 	cs.String(AccessKeyConfigItem, "", "AWS access key to use")       //nolint: errcheck
 	cs.String(SecretKeyConfigItem, "", "AWS secret key to use")       //nolint: errcheck
 	cs.String(SessionTokenConfigItem, "", "AWS session token to use") //nolint: errcheck
+	cs.SetDeprecated(ProfileConfigItem, "please use --aws-profile")   //nolint: errcheck
+
+	cs.String(AWSProfileConfigItem, "", "AWS shared config profile to use, including its region, role_arn, source_profile, credential_process and sso_session") //nolint: errcheck
+}
+
+// AddEndpointConfig will add the config items to override the AWS service endpoints
+func AddEndpointConfig(cs config.ConfigurationSet) {
+	cs.String(EKSEndpointConfigItem, "", "Override the EKS endpoint, e.g. a FIPS or VPC interface endpoint") //nolint: errcheck
+	cs.String(IAMEndpointConfigItem, "", "Override the IAM endpoint, e.g. a FIPS or VPC interface endpoint") //nolint: errcheck
+	cs.String(STSEndpointConfigItem, "", "Override the STS endpoint, e.g. a FIPS or VPC interface endpoint") //nolint: errcheck
+}
+
+// AddAssumeRoleConfig will add the config items to assume a role after authenticating
+func AddAssumeRoleConfig(cs config.ConfigurationSet) {
+	cs.String(AssumeRoleConfigItem, "", "ARN of an AWS role to assume after authenticating")                   //nolint: errcheck
+	cs.String(ExternalIDConfigItem, "", "External ID to use when assuming the role")                           //nolint: errcheck
+	cs.String(MFASerialConfigItem, "", "Serial number or ARN of the MFA device to use when assuming the role") //nolint: errcheck
+	cs.String(MFATokenConfigItem, "", "MFA token code. If not supplied you will be asked for it")              //nolint: errcheck
+	cs.SetHistoryIgnore(MFATokenConfigItem)                                                                    //nolint: errcheck
 }
@@ -0,0 +1,80 @@
+/*
+Copyright 2020 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+)
+
+// Endpoints are overrides of the AWS service endpoints. They can be used for FIPS
+// endpoints or VPC interface endpoints. A service without an override uses the
+// default endpoint for the region.
+type Endpoints struct {
+	EKS string `json:"eks-endpoint"`
+	IAM string `json:"iam-endpoint"`
+	STS string `json:"sts-endpoint"`
+}
+
+// Resolver returns an endpoint resolver that uses the overrides and falls back
+// to the default endpoint of the service
+func (e *Endpoints) Resolver() aws.EndpointResolverWithOptions {
+	overrides := map[string]string{
+		eks.ServiceID: e.EKS,
+		iam.ServiceID: e.IAM,
+		sts.ServiceID: e.STS,
+	}
+
+	return aws.EndpointResolverWithOptionsFunc(func(service, region string, options ...interface{}) (aws.Endpoint, error) {
+		if url := overrides[service]; url != "" {
+			return aws.Endpoint{
+				URL:           url,
+				SigningRegion: region,
+				Source:        aws.EndpointSourceCustom,
+			}, nil
+		}
+
+		return aws.Endpoint{}, &aws.EndpointNotFoundError{}
+	})
+}
+
+// IsSet returns true if any of the endpoints have been overridden
+func (e *Endpoints) IsSet() bool {
+	return e.EKS != "" || e.IAM != "" || e.STS != ""
+}
+
+// ValidateRegion checks that the region belongs to the partition. A region that
+// isn't known is allowed so that new regions can be used.
+func ValidateRegion(partitionID, region string) error {
+	if partitionID == "" || region == "" {
+		return nil
+	}
+
+	partition, found := PartitionForRegion(region)
+	if !found {
+		return nil
+	}
+	if partition.ID != partitionID {
+		return fmt.Errorf("region %s is in partition %s and not %s: %w", region, partition.ID, partitionID, ErrRegionNotInPartition)
+	}
+
+	return nil
+}
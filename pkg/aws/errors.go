@@ -20,7 +20,10 @@ import "errors"
 
 var (
 	ErrNoProfile           = errors.New("no profile supplied")
+	ErrNoRegion            = errors.New("no AWS region supplied, use --region or set the region in the profile")
 	ErrUnexpectedIdentity  = errors.New("unexpected identity type")
 	ErrNoPartitionSupplied = errors.New("no AWS partition supplied")
 	ErrPartitionNotFound   = errors.New("AWS partition not found")
+
+	ErrRegionNotInPartition = errors.New("AWS region not in the partition")
 )
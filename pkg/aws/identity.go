@@ -17,7 +17,11 @@ limitations under the License.
 package aws
 
 import (
+	"encoding/json"
+	"fmt"
 	"time"
+
+	"github.com/fidelity/kconnect/pkg/provider/identity"
 )
 
 // Identity represents an AWS identity
@@ -47,6 +51,21 @@ func (i *Identity) IsExpired() bool {
 	return now.After(i.Expires)
 }
 
+// ExpiresAt returns when the AWS credentials expire
+func (i *Identity) ExpiresAt() time.Time {
+	return i.Expires
+}
+
 func (i *Identity) IdentityProviderName() string {
 	return i.IDProviderName
 }
+
+// RestoreIdentity creates an AWS identity from its JSON
+func RestoreIdentity(data []byte) (identity.Identity, error) {
+	id := &Identity{}
+	if err := json.Unmarshal(data, id); err != nil {
+		return nil, fmt.Errorf("unmarshalling aws identity: %w", err)
+	}
+
+	return id, nil
+}
@@ -0,0 +1,114 @@
+/*
+Copyright 2020 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import "regexp"
+
+const (
+	AWSPartitionID      = "aws"
+	AWSCnPartitionID    = "aws-cn"
+	AWSUsGovPartitionID = "aws-us-gov"
+	AWSIsoPartitionID   = "aws-iso"
+	AWSIsoBPartitionID  = "aws-iso-b"
+)
+
+// Partition is an AWS partition and its regions. The v2 SDK doesn't expose the
+// partitions, so they are listed here for choosing and validating the region.
+type Partition struct {
+	ID      string
+	Name    string
+	Regions []string
+	// DefaultRegion is used to make calls within the partition when no region
+	// has been supplied, e.g. for listing the enabled regions
+	DefaultRegion string
+
+	regionRegex *regexp.Regexp
+}
+
+// Partitions are the AWS partitions
+var Partitions = []Partition{
+	{
+		ID:   AWSPartitionID,
+		Name: "AWS Standard",
+		Regions: []string{
+			"af-south-1", "ap-east-1", "ap-northeast-1", "ap-northeast-2", "ap-northeast-3", "ap-south-1",
+			"ap-southeast-1", "ap-southeast-2", "ap-southeast-3", "ca-central-1", "eu-central-1", "eu-north-1",
+			"eu-south-1", "eu-west-1", "eu-west-2", "eu-west-3", "me-central-1", "me-south-1", "sa-east-1",
+			"us-east-1", "us-east-2", "us-west-1", "us-west-2",
+		},
+		DefaultRegion: "us-east-1",
+		regionRegex:   regexp.MustCompile(`^(us|eu|ap|sa|ca|me|af)\-\w+\-\d+$`),
+	},
+	{
+		ID:            AWSCnPartitionID,
+		Name:          "AWS China",
+		Regions:       []string{"cn-north-1", "cn-northwest-1"},
+		DefaultRegion: "cn-north-1",
+		regionRegex:   regexp.MustCompile(`^cn\-\w+\-\d+$`),
+	},
+	{
+		ID:            AWSUsGovPartitionID,
+		Name:          "AWS GovCloud (US)",
+		Regions:       []string{"us-gov-east-1", "us-gov-west-1"},
+		DefaultRegion: "us-gov-west-1",
+		regionRegex:   regexp.MustCompile(`^us\-gov\-\w+\-\d+$`),
+	},
+	{
+		ID:            AWSIsoPartitionID,
+		Name:          "AWS ISO (US)",
+		Regions:       []string{"us-iso-east-1", "us-iso-west-1"},
+		DefaultRegion: "us-iso-east-1",
+		regionRegex:   regexp.MustCompile(`^us\-iso\-\w+\-\d+$`),
+	},
+	{
+		ID:            AWSIsoBPartitionID,
+		Name:          "AWS ISOB (US)",
+		Regions:       []string{"us-isob-east-1"},
+		DefaultRegion: "us-isob-east-1",
+		regionRegex:   regexp.MustCompile(`^us\-isob\-\w+\-\d+$`),
+	},
+}
+
+// FindPartition returns the partition with the id
+func FindPartition(partitionID string) (Partition, bool) {
+	for _, partition := range Partitions {
+		if partition.ID == partitionID {
+			return partition, true
+		}
+	}
+
+	return Partition{}, false
+}
+
+// PartitionForRegion returns the partition that the region is in. A region that
+// isn't listed is matched using the region naming of the partitions.
+func PartitionForRegion(region string) (Partition, bool) {
+	for _, partition := range Partitions {
+		for _, partitionRegion := range partition.Regions {
+			if partitionRegion == region {
+				return partition, true
+			}
+		}
+	}
+	for _, partition := range Partitions {
+		if partition.regionRegex.MatchString(region) {
+			return partition, true
+		}
+	}
+
+	return Partition{}, false
+}
@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/ec2"
+	"github.com/aws/aws-sdk-go-v2/service/ec2/types"
+)
+
+const (
+	regionOptInNotRequired = "opt-in-not-required"
+	regionOptedIn          = "opted-in"
+)
+
+// PartitionDefaultRegion returns the region used to make calls within the partition
+// when no region has been supplied, e.g. for listing the enabled regions.
+func PartitionDefaultRegion(partitionID string) string {
+	if partition, found := FindPartition(partitionID); found {
+		return partition.DefaultRegion
+	}
+
+	return Partitions[0].DefaultRegion
+}
+
+// ListEnabledRegions returns the regions that are enabled for the account. This
+// includes the regions that don't need an opt-in and the opt-in regions that the
+// account has opted in to.
+func ListEnabledRegions(ctx context.Context, cfg aws.Config) ([]string, error) {
+	ec2Client := NewEC2Client(cfg)
+
+	output, err := ec2Client.DescribeRegions(ctx, &ec2.DescribeRegionsInput{
+		Filters: []types.Filter{
+			{
+				Name:   aws.String("opt-in-status"),
+				Values: []string{regionOptInNotRequired, regionOptedIn},
+			},
+		},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("describing regions: %w", err)
+	}
+
+	regions := []string{}
+	for _, region := range output.Regions {
+		regions = append(regions, aws.ToString(region.RegionName))
+	}
+	sort.Strings(regions)
+
+	return regions, nil
+}
+
+// SplitRegions splits a comma separated list of regions
+func SplitRegions(value string) []string {
+	regions := []string{}
+	for _, region := range strings.Split(value, ",") {
+		region = strings.TrimSpace(region)
+		if region != "" {
+			regions = append(regions, region)
+		}
+	}
+
+	return regions
+}
+
+// PrimaryRegion returns the first region of a comma separated list of regions. It's
+// used where only a single region can be used, such as creating a session for
+// authentication.
+func PrimaryRegion(value string) string {
+	regions := SplitRegions(value)
+	if len(regions) == 0 {
+		return ""
+	}
+
+	return regions[0]
+}
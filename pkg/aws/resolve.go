@@ -21,8 +21,6 @@ import (
 	"sort"
 	"strings"
 
-	"github.com/aws/aws-sdk-go/aws/endpoints"
-
 	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/prompt"
 )
@@ -42,17 +40,8 @@ func ResolveRegion(cfg config.ConfigurationSet) error {
 	}
 	partitionID := partitionCfg.Value.(string)
 
-	resolver := endpoints.DefaultResolver()
-	partitions := resolver.(endpoints.EnumPartitions).Partitions()
-
-	var partition endpoints.Partition
-	for _, p := range partitions {
-		if p.ID() == partitionID {
-			partition = p
-			break
-		}
-	}
-	if partition.ID() == "" {
+	partition, found := FindPartition(partitionID)
+	if !found {
 		return fmt.Errorf("finding partition with id %s: %w", partitionID, ErrPartitionNotFound)
 	}
 
@@ -63,9 +52,9 @@ func ResolveRegion(cfg config.ConfigurationSet) error {
 	}
 
 	options := []string{}
-	for _, region := range partition.Regions() {
-		if regionFilter == "" || strings.Contains(region.ID(), regionFilter) {
-			options = append(options, region.ID())
+	for _, region := range partition.Regions {
+		if regionFilter == "" || strings.Contains(region, regionFilter) {
+			options = append(options, region)
 		}
 	}
 	sort.Slice(options, func(i, j int) bool { return options[i] < options[j] })
@@ -79,12 +68,9 @@ func ResolveRegion(cfg config.ConfigurationSet) error {
 }
 
 func awsPartitionOptions() (map[string]string, error) {
-	resolver := endpoints.DefaultResolver()
-	partitions := resolver.(endpoints.EnumPartitions).Partitions()
-
 	options := map[string]string{}
-	for _, partition := range partitions {
-		options[partition.ID()] = partition.ID()
+	for _, partition := range Partitions {
+		options[partition.ID] = partition.ID
 	}
 
 	return options, nil
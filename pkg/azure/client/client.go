@@ -17,33 +17,88 @@ limitations under the License.
 package client
 
 import (
-	"fmt"
+	"net/http"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2020-09-01/containerservice"
-	"github.com/Azure/azure-sdk-for-go/services/resources/mgmt/2019-11-01/subscriptions"
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/arm"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armresources"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/resources/armsubscriptions"
 
 	"github.com/fidelity/kconnect/internal/version"
+	"github.com/fidelity/kconnect/pkg/azure/cloud"
+	khttp "github.com/fidelity/kconnect/pkg/http"
 )
 
 const (
-	userAgentTemplate = "kconnect.fidelity.github.com/%s"
+	applicationID = "kconnect"
 )
 
-// NewContainerClient will create a new Azure container services client
-func NewContainerClient(subscriptionID string, authorizer autorest.Authorizer) containerservice.ManagedClustersClient {
-	azclient := containerservice.NewManagedClustersClient(subscriptionID)
-	azclient.Authorizer = authorizer
-	azclient.UserAgent = fmt.Sprintf(userAgentTemplate, version.Get().String())
+// NewContainerClient will create a new Azure managed clusters client that uses the
+// resource manager endpoint of the environment
+func NewContainerClient(env cloud.Environment, subscriptionID string, credential azcore.TokenCredential, policies ...policy.Policy) (*armcontainerservice.ManagedClustersClient, error) {
+	return armcontainerservice.NewManagedClustersClient(subscriptionID, credential, NewClientOptions(env, policies...))
+}
+
+// NewSubscriptionsClient will create a new Azure subscriptions client that uses the
+// resource manager endpoint of the environment
+func NewSubscriptionsClient(env cloud.Environment, credential azcore.TokenCredential, policies ...policy.Policy) (*armsubscriptions.Client, error) {
+	return armsubscriptions.NewClient(credential, NewClientOptions(env, policies...))
+}
+
+// NewTenantsClient will create a new Azure tenants client that uses the resource manager
+// endpoint of the environment
+func NewTenantsClient(env cloud.Environment, credential azcore.TokenCredential, policies ...policy.Policy) (*armsubscriptions.TenantsClient, error) {
+	return armsubscriptions.NewTenantsClient(credential, NewClientOptions(env, policies...))
+}
 
-	return azclient
+// NewGroupsClient will create a new Azure resource groups client that uses the resource
+// manager endpoint of the environment
+func NewGroupsClient(env cloud.Environment, subscriptionID string, credential azcore.TokenCredential, policies ...policy.Policy) (*armresources.ResourceGroupsClient, error) {
+	return armresources.NewResourceGroupsClient(subscriptionID, credential, NewClientOptions(env, policies...))
 }
 
-// NewSubscriptionsClient will create a new Azure subscriptions client
-func NewSubscriptionsClient(authorizer autorest.Authorizer) subscriptions.Client {
-	subClient := subscriptions.NewClient()
-	subClient.Authorizer = authorizer
-	subClient.UserAgent = fmt.Sprintf(userAgentTemplate, version.Get().String())
+// NewClientOptions returns the options of the resource manager clients for the environment.
+// The policies are run once for each request, before it's retried. Throttled requests
+// aren't retried by the client as the Throttle retries them.
+func NewClientOptions(env cloud.Environment, policies ...policy.Policy) *arm.ClientOptions {
+	opts := &arm.ClientOptions{
+		ClientOptions: policy.ClientOptions{
+			Cloud:           cloud.ClientConfiguration(env),
+			PerCallPolicies: policies,
+			Retry: policy.RetryOptions{
+				StatusCodes: retryStatusCodes(),
+			},
+			Telemetry: policy.TelemetryOptions{
+				ApplicationID: applicationID + "/" + version.Get().Version,
+			},
+			Transport: NewTransport(),
+		},
+	}
+
+	return opts
+}
+
+// NewTransport returns the transport of the Azure SDK clients and credentials. It
+// propagates the trace context to Azure and, when that's enabled, traces and records or
+// replays the requests.
+func NewTransport() policy.Transporter {
+	transport := http.DefaultTransport
+	if khttp.TracingEnabled() || khttp.RecordingEnabled() {
+		transport = khttp.TraceTransport(khttp.RecordTransport(transport))
+	}
+
+	return &http.Client{Transport: khttp.PropagateTransport(transport)}
+}
 
-	return subClient
+// retryStatusCodes are the status codes of the requests retried by the client
+func retryStatusCodes() []int {
+	return []int{
+		http.StatusRequestTimeout,
+		http.StatusInternalServerError,
+		http.StatusBadGateway,
+		http.StatusServiceUnavailable,
+		http.StatusGatewayTimeout,
+	}
 }
@@ -0,0 +1,219 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client
+
+import (
+	"context"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"go.uber.org/zap"
+)
+
+const (
+	remainingSubscriptionReadsHeader = "x-ms-ratelimit-remaining-subscription-reads"
+	remainingTenantReadsHeader       = "x-ms-ratelimit-remaining-tenant-reads"
+	retryAfterHeader                 = "Retry-After"
+
+	// lowRemainingReads is the number of remaining reads below which the number of
+	// concurrent requests is reduced
+	lowRemainingReads = 100
+	// maxThrottledRetries is the number of times a throttled request is retried
+	maxThrottledRetries = 5
+	// defaultRetryAfter is the time to wait before retrying a throttled request if the
+	// response doesn't say how long to wait
+	defaultRetryAfter = 5 * time.Second
+	// maxRetryAfter is the longest time to wait before retrying a throttled request
+	maxRetryAfter = time.Minute
+)
+
+// Throttle limits the number of concurrent requests to the Azure resource manager so
+// that listing lots of subscriptions slows down instead of failing when the requests
+// are throttled. The limit is halved when a request is throttled (429) or the reads
+// remaining in the x-ms-ratelimit-remaining headers are low, and is increased by 1 after
+// each request that isn't, up to the maximum. Throttled requests are retried after the
+// time in their Retry-After header. The throttle is a policy of the Azure SDK clients.
+type Throttle struct {
+	max    int
+	logger *zap.SugaredLogger
+
+	lock   sync.Mutex
+	cond   *sync.Cond
+	limit  int
+	active int
+}
+
+// NewThrottle creates a throttle that allows up to max concurrent requests
+func NewThrottle(max int, logger *zap.SugaredLogger) *Throttle {
+	if max < 1 {
+		max = 1
+	}
+	if logger == nil {
+		logger = zap.S()
+	}
+
+	t := &Throttle{
+		max:    max,
+		limit:  max,
+		logger: logger,
+	}
+	t.cond = sync.NewCond(&t.lock)
+
+	return t
+}
+
+// Limit returns the current number of concurrent requests allowed
+func (t *Throttle) Limit() int {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	return t.limit
+}
+
+// Do sends the request with the next policy when the throttle allows it, retrying it if
+// it's throttled
+func (t *Throttle) Do(req *policy.Request) (*http.Response, error) {
+	ctx := req.Raw().Context()
+	for attempt := 0; ; attempt++ {
+		if err := req.RewindBody(); err != nil {
+			return nil, err
+		}
+		if err := t.acquire(ctx); err != nil {
+			return nil, err
+		}
+		resp, err := req.Next()
+		t.release()
+		if err != nil {
+			return resp, err
+		}
+		if resp.StatusCode != http.StatusTooManyRequests {
+			t.observe(resp)
+			return resp, nil
+		}
+
+		t.decrease("the request was throttled")
+		if attempt >= maxThrottledRetries {
+			return resp, nil
+		}
+		delay := retryAfter(resp)
+		t.logger.Infow("azure resource manager throttled the request, retrying", "url", req.Raw().URL.Path, "after", delay.String(), "attempt", attempt+1)
+		io.Copy(ioutil.Discard, resp.Body) //nolint: errcheck
+		resp.Body.Close()
+
+		select {
+		case <-time.After(delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+}
+
+func (t *Throttle) acquire(ctx context.Context) error {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	for t.active >= t.limit {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+		t.cond.Wait()
+	}
+	t.active++
+
+	return nil
+}
+
+func (t *Throttle) release() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.active--
+	t.cond.Broadcast()
+}
+
+// observe adjusts the limit using the number of reads remaining for the subscription
+// or tenant of a successful response
+func (t *Throttle) observe(resp *http.Response) {
+	remaining, found := remainingReads(resp)
+	if found && remaining < lowRemainingReads {
+		t.decrease("few reads remaining")
+		return
+	}
+
+	t.lock.Lock()
+	defer t.lock.Unlock()
+	if t.limit < t.max {
+		t.limit++
+		t.cond.Broadcast()
+	}
+}
+
+func (t *Throttle) decrease(reason string) {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	limit := t.limit / 2
+	if limit < 1 {
+		limit = 1
+	}
+	if limit != t.limit {
+		t.logger.Infow("reducing concurrent azure resource manager requests", "limit", limit, "reason", reason)
+		t.limit = limit
+	}
+}
+
+// remainingReads returns the lowest of the remaining subscription and tenant reads
+func remainingReads(resp *http.Response) (int, bool) {
+	lowest, found := 0, false
+	for _, header := range []string{remainingSubscriptionReadsHeader, remainingTenantReadsHeader} {
+		remaining, err := strconv.Atoi(resp.Header.Get(header))
+		if err != nil {
+			continue
+		}
+		if !found || remaining < lowest {
+			lowest, found = remaining, true
+		}
+	}
+
+	return lowest, found
+}
+
+// retryAfter returns how long to wait before retrying a throttled request. The header
+// is either a number of seconds or a date.
+func retryAfter(resp *http.Response) time.Duration {
+	delay := defaultRetryAfter
+	value := resp.Header.Get(retryAfterHeader)
+	if seconds, err := strconv.Atoi(value); err == nil && seconds >= 0 {
+		delay = time.Duration(seconds) * time.Second
+	} else if date, err := http.ParseTime(value); err == nil {
+		delay = time.Until(date)
+	}
+
+	if delay < 0 {
+		delay = 0
+	}
+	if delay > maxRetryAfter {
+		delay = maxRetryAfter
+	}
+
+	return delay
+}
@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package client_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/azure/client"
+)
+
+func TestThrottle(t *testing.T) {
+	g := NewWithT(t)
+
+	var requests int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch atomic.AddInt32(&requests, 1) {
+		case 1:
+			w.Header().Set("Retry-After", "0")
+			w.WriteHeader(http.StatusTooManyRequests)
+		case 2:
+			w.Header().Set("x-ms-ratelimit-remaining-subscription-reads", "11999")
+			w.WriteHeader(http.StatusOK)
+		default:
+			w.Header().Set("x-ms-ratelimit-remaining-subscription-reads", "50")
+			w.WriteHeader(http.StatusOK)
+		}
+	}))
+	defer server.Close()
+
+	throttle := client.NewThrottle(4, zap.NewNop().Sugar())
+	pipeline := runtime.NewPipeline("test", "v0.0.0", runtime.PipelineOptions{}, &policy.ClientOptions{
+		PerCallPolicies: []policy.Policy{throttle},
+		Retry:           policy.RetryOptions{MaxRetries: -1},
+	})
+
+	// The throttled request is retried and the limit is halved then increased again
+	req, err := runtime.NewRequest(context.Background(), http.MethodGet, server.URL)
+	g.Expect(err).NotTo(HaveOccurred())
+	resp, err := pipeline.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	resp.Body.Close()
+	g.Expect(resp.StatusCode).To(Equal(http.StatusOK))
+	g.Expect(atomic.LoadInt32(&requests)).To(Equal(int32(2)))
+	g.Expect(throttle.Limit()).To(Equal(3))
+
+	// Few remaining reads halves the limit
+	req, err = runtime.NewRequest(context.Background(), http.MethodGet, server.URL)
+	g.Expect(err).NotTo(HaveOccurred())
+	resp, err = pipeline.Do(req)
+	g.Expect(err).NotTo(HaveOccurred())
+	resp.Body.Close()
+	g.Expect(throttle.Limit()).To(Equal(1))
+}
@@ -0,0 +1,262 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"strings"
+
+	azcloud "github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+
+	"github.com/fidelity/kconnect/pkg/config"
+)
+
+// Name is the name of an Azure cloud
+type Name string
+
+var (
+	// AzurePublic is the general Azure public cloud
+	AzurePublic = Name("AzurePublic")
+	// AzureUSGovernment is the US Government specific Azure cloud
+	AzureUSGovernment = Name("AzureUSGovernment")
+	// AzureChina is the public Azure cloud in China
+	AzureChina = Name("AzureChina")
+	// AzureStack is an Azure Stack cloud with its endpoints read from the file set by AZURE_ENVIRONMENT_FILEPATH
+	AzureStack = Name("AzureStack")
+	// Custom is a cloud where the endpoints are supplied
+	Custom = Name("Custom")
+)
+
+const (
+	EnvironmentConfigItem             = "azure-environment"
+	ResourceManagerEndpointConfigItem = "azure-resource-manager-endpoint"
+	ActiveDirectoryEndpointConfigItem = "azure-ad-endpoint"
+
+	// EnvironmentFilepathName is the environment variable with the path of the file that
+	// describes the endpoints of an Azure Stack cloud
+	EnvironmentFilepathName = "AZURE_ENVIRONMENT_FILEPATH"
+)
+
+// Environment is the endpoints of an Azure cloud. The names and the file of an Azure
+// Stack cloud are the same as the Azure CLI and kubelogin use.
+type Environment struct {
+	Name                    string `json:"name"`
+	ResourceManagerEndpoint string `json:"resourceManagerEndpoint"`
+	ActiveDirectoryEndpoint string `json:"activeDirectoryEndpoint"`
+	// TokenAudience is the resource to request a token for to call the resource manager,
+	// the resource manager endpoint is used if it's empty
+	TokenAudience string `json:"tokenAudience"`
+}
+
+var (
+	// PublicCloud is the endpoints of the general Azure public cloud
+	PublicCloud = Environment{
+		Name:                    "AzurePublicCloud",
+		ResourceManagerEndpoint: "https://management.azure.com/",
+		ActiveDirectoryEndpoint: azcloud.AzurePublic.ActiveDirectoryAuthorityHost,
+		TokenAudience:           "https://management.azure.com/",
+	}
+	// USGovernmentCloud is the endpoints of the US Government specific Azure cloud
+	USGovernmentCloud = Environment{
+		Name:                    "AzureUSGovernmentCloud",
+		ResourceManagerEndpoint: "https://management.usgovcloudapi.net/",
+		ActiveDirectoryEndpoint: azcloud.AzureGovernment.ActiveDirectoryAuthorityHost,
+		TokenAudience:           "https://management.usgovcloudapi.net/",
+	}
+	// ChinaCloud is the endpoints of the public Azure cloud in China
+	ChinaCloud = Environment{
+		Name:                    "AzureChinaCloud",
+		ResourceManagerEndpoint: "https://management.chinacloudapi.cn/",
+		ActiveDirectoryEndpoint: azcloud.AzureChina.ActiveDirectoryAuthorityHost,
+		TokenAudience:           "https://management.chinacloudapi.cn/",
+	}
+)
+
+var (
+	ErrUnknownCloud            = errors.New("unknown azure cloud")
+	ErrCustomEndpointsRequired = errors.New("the Custom azure environment requires the resource manager and active directory endpoints")
+	ErrNoPortal                = errors.New("the azure cloud has no known portal")
+)
+
+// Config is the configuration of the Azure cloud to connect to
+type Config struct {
+	Name                    Name   `json:"azure-environment"`
+	ResourceManagerEndpoint string `json:"azure-resource-manager-endpoint"`
+	ActiveDirectoryEndpoint string `json:"azure-ad-endpoint"`
+}
+
+// AddConfig will add the config items used to select the Azure cloud
+func AddConfig(cs config.ConfigurationSet) {
+	cs.Enum(EnvironmentConfigItem, string(AzurePublic), names(), "The Azure cloud to connect to")                             //nolint: errcheck
+	cs.String(ResourceManagerEndpointConfigItem, "", "Override the Azure resource manager endpoint, e.g. for a Custom cloud") //nolint: errcheck
+	cs.String(ActiveDirectoryEndpointConfigItem, "", "Override the Azure AD endpoint, e.g. for a Custom cloud")               //nolint: errcheck
+}
+
+// Environment returns the endpoints of the cloud, with any endpoints that have been
+// overridden
+func (c *Config) Environment() (Environment, error) {
+	var env Environment
+	var err error
+
+	switch c.Name {
+	case AzurePublic, "":
+		env = PublicCloud
+	case AzureUSGovernment:
+		env = USGovernmentCloud
+	case AzureChina:
+		env = ChinaCloud
+	case AzureStack:
+		env, err = EnvironmentFromFile(os.Getenv(EnvironmentFilepathName))
+		if err != nil {
+			return Environment{}, fmt.Errorf("reading azure stack environment: %w", err)
+		}
+	case Custom:
+		if c.ResourceManagerEndpoint == "" || c.ActiveDirectoryEndpoint == "" {
+			return Environment{}, ErrCustomEndpointsRequired
+		}
+		env = Environment{Name: "AzureCustomCloud"}
+	default:
+		return Environment{}, fmt.Errorf("getting environment for %s: %w", c.Name, ErrUnknownCloud)
+	}
+
+	if c.ResourceManagerEndpoint != "" {
+		env.ResourceManagerEndpoint = c.ResourceManagerEndpoint
+		env.TokenAudience = c.ResourceManagerEndpoint
+	}
+	if c.ActiveDirectoryEndpoint != "" {
+		env.ActiveDirectoryEndpoint = c.ActiveDirectoryEndpoint
+	}
+
+	return env, nil
+}
+
+// EnvironmentFromName returns the endpoints of the cloud with the name used by the Azure
+// CLI and in AZURE_ENVIRONMENT, e.g. AzureUSGovernmentCloud. The name isn't case
+// sensitive and the endpoints of AzureStackCloud are read from the file set by
+// AZURE_ENVIRONMENT_FILEPATH.
+func EnvironmentFromName(name string) (Environment, error) {
+	switch strings.ToUpper(name) {
+	case strings.ToUpper(PublicCloud.Name):
+		return PublicCloud, nil
+	case strings.ToUpper(USGovernmentCloud.Name):
+		return USGovernmentCloud, nil
+	case strings.ToUpper(ChinaCloud.Name):
+		return ChinaCloud, nil
+	case "AZURESTACKCLOUD":
+		return EnvironmentFromFile(os.Getenv(EnvironmentFilepathName))
+	default:
+		return Environment{}, fmt.Errorf("getting environment for %s: %w", name, ErrUnknownCloud)
+	}
+}
+
+// EnvironmentFromFile reads the endpoints of a cloud from a JSON file, e.g. the file of
+// an Azure Stack cloud
+func EnvironmentFromFile(path string) (Environment, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return Environment{}, fmt.Errorf("reading environment file %s: %w", path, err)
+	}
+
+	env := Environment{}
+	if err := json.Unmarshal(data, &env); err != nil {
+		return Environment{}, fmt.Errorf("unmarshalling environment file %s: %w", path, err)
+	}
+
+	return env, nil
+}
+
+// PortalURL returns the URL of the Azure portal for the cloud
+func (c *Config) PortalURL() (string, error) {
+	switch c.Name {
+	case AzurePublic, "":
+		return "https://portal.azure.com", nil
+	case AzureUSGovernment:
+		return "https://portal.azure.us", nil
+	case AzureChina:
+		return "https://portal.azure.cn", nil
+	default:
+		return "", fmt.Errorf("getting portal for %s: %w", c.Name, ErrNoPortal)
+	}
+}
+
+// IsDefault returns true if the public cloud is used without overriding any endpoints
+func (c *Config) IsDefault() bool {
+	return (c.Name == AzurePublic || c.Name == "") && c.ResourceManagerEndpoint == "" && c.ActiveDirectoryEndpoint == ""
+}
+
+// ResourceManagerAudience returns the resource to request a token for when
+// calling the resource manager of the environment
+func ResourceManagerAudience(env Environment) string {
+	if env.TokenAudience != "" {
+		return env.TokenAudience
+	}
+
+	return env.ResourceManagerEndpoint
+}
+
+// ClientConfiguration returns the configuration of the cloud used by the Azure SDK
+// clients and credentials
+func ClientConfiguration(env Environment) azcloud.Configuration {
+	return azcloud.Configuration{
+		ActiveDirectoryAuthorityHost: env.ActiveDirectoryEndpoint,
+		Services: map[azcloud.ServiceName]azcloud.ServiceConfiguration{
+			azcloud.ResourceManager: {
+				Endpoint: env.ResourceManagerEndpoint,
+				Audience: ResourceManagerAudience(env),
+			},
+		},
+	}
+}
+
+// ActiveDirectoryHost returns the host name of the Azure AD endpoint of the environment
+func ActiveDirectoryHost(env Environment) (string, error) {
+	u, err := url.Parse(env.ActiveDirectoryEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("parsing active directory endpoint %s: %w", env.ActiveDirectoryEndpoint, err)
+	}
+
+	return u.Hostname(), nil
+}
+
+// KubeloginEnvironment returns the name of the environment used by kubelogin. Custom
+// clouds must be described by the file set by AZURE_ENVIRONMENT_FILEPATH.
+func KubeloginEnvironment(name Name) string {
+	switch name {
+	case AzureUSGovernment:
+		return "AzureUSGovernmentCloud"
+	case AzureChina:
+		return "AzureChinaCloud"
+	case AzureStack, Custom:
+		return "AzureStackCloud"
+	default:
+		return "AzurePublicCloud"
+	}
+}
+
+func names() []string {
+	return []string{
+		string(AzurePublic),
+		string(AzureUSGovernment),
+		string(AzureChina),
+		string(AzureStack),
+		string(Custom),
+	}
+}
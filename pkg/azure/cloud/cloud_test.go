@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cloud_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	azcloud "github.com/Azure/azure-sdk-for-go/sdk/azcore/cloud"
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/azure/cloud"
+)
+
+func TestEnvironment(t *testing.T) {
+	testCases := []struct {
+		name           string
+		config         cloud.Config
+		expectARM      string
+		expectAudience string
+		expectAADHost  string
+		expectErr      error
+	}{
+		{
+			name:           "default is public",
+			config:         cloud.Config{},
+			expectARM:      "https://management.azure.com/",
+			expectAudience: "https://management.azure.com/",
+			expectAADHost:  "login.microsoftonline.com",
+		},
+		{
+			name:           "us government",
+			config:         cloud.Config{Name: cloud.AzureUSGovernment},
+			expectARM:      "https://management.usgovcloudapi.net/",
+			expectAudience: "https://management.usgovcloudapi.net/",
+			expectAADHost:  "login.microsoftonline.us",
+		},
+		{
+			name:           "china",
+			config:         cloud.Config{Name: cloud.AzureChina},
+			expectARM:      "https://management.chinacloudapi.cn/",
+			expectAudience: "https://management.chinacloudapi.cn/",
+			expectAADHost:  "login.chinacloudapi.cn",
+		},
+		{
+			name: "custom",
+			config: cloud.Config{
+				Name:                    cloud.Custom,
+				ResourceManagerEndpoint: "https://management.contoso.com/",
+				ActiveDirectoryEndpoint: "https://login.contoso.com/",
+			},
+			expectARM:      "https://management.contoso.com/",
+			expectAudience: "https://management.contoso.com/",
+			expectAADHost:  "login.contoso.com",
+		},
+		{
+			name:      "custom without endpoints",
+			config:    cloud.Config{Name: cloud.Custom},
+			expectErr: cloud.ErrCustomEndpointsRequired,
+		},
+		{
+			name:      "unknown",
+			config:    cloud.Config{Name: cloud.Name("AzureGermany")},
+			expectErr: cloud.ErrUnknownCloud,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			env, err := tc.config.Environment() //nolint:scopelint
+			if tc.expectErr != nil {            //nolint:scopelint
+				g.Expect(errors.Is(err, tc.expectErr)).To(BeTrue()) //nolint:scopelint
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(env.ResourceManagerEndpoint).To(Equal(tc.expectARM))             //nolint:scopelint
+			g.Expect(cloud.ResourceManagerAudience(env)).To(Equal(tc.expectAudience)) //nolint:scopelint
+
+			host, err := cloud.ActiveDirectoryHost(env)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(host).To(Equal(tc.expectAADHost)) //nolint:scopelint
+
+			clientCfg := cloud.ClientConfiguration(env)
+			g.Expect(clientCfg.ActiveDirectoryAuthorityHost).To(ContainSubstring(tc.expectAADHost))     //nolint:scopelint
+			g.Expect(clientCfg.Services[azcloud.ResourceManager].Endpoint).To(Equal(tc.expectARM))      //nolint:scopelint
+			g.Expect(clientCfg.Services[azcloud.ResourceManager].Audience).To(Equal(tc.expectAudience)) //nolint:scopelint
+		})
+	}
+}
+
+func TestEnvironmentFromName(t *testing.T) {
+	g := NewWithT(t)
+
+	env, err := cloud.EnvironmentFromName("azureusgovernmentcloud")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env).To(Equal(cloud.USGovernmentCloud))
+
+	stackFile := filepath.Join(t.TempDir(), "azurestack.json")
+	g.Expect(os.WriteFile(stackFile, []byte(`{
+		"name": "AzureStackCloud",
+		"resourceManagerEndpoint": "https://management.local.azurestack.external/",
+		"activeDirectoryEndpoint": "https://login.microsoftonline.com/",
+		"tokenAudience": "https://management.adfs.azurestack.local/4de154de"
+	}`), 0600)).To(Succeed())
+	t.Setenv(cloud.EnvironmentFilepathName, stackFile)
+
+	env, err = cloud.EnvironmentFromName("AzureStackCloud")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env.ResourceManagerEndpoint).To(Equal("https://management.local.azurestack.external/"))
+	g.Expect(cloud.ResourceManagerAudience(env)).To(Equal("https://management.adfs.azurestack.local/4de154de"))
+
+	env, err = (&cloud.Config{Name: cloud.AzureStack}).Environment()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(env.Name).To(Equal("AzureStackCloud"))
+
+	_, err = cloud.EnvironmentFromName("AzureGermanCloud")
+	g.Expect(errors.Is(err, cloud.ErrUnknownCloud)).To(BeTrue())
+}
+
+func TestPortalURL(t *testing.T) {
+	testCases := []struct {
+		name         string
+		config       cloud.Config
+		expectPortal string
+		expectErr    error
+	}{
+		{
+			name:         "default is public",
+			config:       cloud.Config{},
+			expectPortal: "https://portal.azure.com",
+		},
+		{
+			name:         "us government",
+			config:       cloud.Config{Name: cloud.AzureUSGovernment},
+			expectPortal: "https://portal.azure.us",
+		},
+		{
+			name:         "china",
+			config:       cloud.Config{Name: cloud.AzureChina},
+			expectPortal: "https://portal.azure.cn",
+		},
+		{
+			name:      "azure stack",
+			config:    cloud.Config{Name: cloud.AzureStack},
+			expectErr: cloud.ErrNoPortal,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			portal, err := tc.config.PortalURL() //nolint:scopelint
+			if tc.expectErr != nil {             //nolint:scopelint
+				g.Expect(errors.Is(err, tc.expectErr)).To(BeTrue()) //nolint:scopelint
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(portal).To(Equal(tc.expectPortal)) //nolint:scopelint
+		})
+	}
+}
@@ -134,6 +134,7 @@ func (c *AzureADClient) GetOauth2TokenFromSamlAssertion(cfg *AuthenticationConfi
 	data.Set("scope", "openid")
 	data.Set("grant_type", "urn:ietf:params:oauth:grant-type:saml1_1-bearer")
 	data.Set("assertion", assertionEncoded)
+	data.Set("client_info", "1")
 
 	url := fmt.Sprintf("%soauth2/token", cfg.Authority.AuthorityURI)
 
@@ -174,6 +175,42 @@ func (c *AzureADClient) GetOauth2TokenFromUsernamePassword(cfg *AuthenticationCo
 		return nil, err
 	}
 
+	if resp.ResponseCode() != http.StatusOK {
+		oidcResp := &OIDCErrorResponse{}
+		if err := json.Unmarshal([]byte(resp.Body()), oidcResp); err != nil {
+			return nil, fmt.Errorf("unmarshalling oidc error response: %w", err)
+		}
+		return nil, oidcResp.credentialError()
+	}
+
+	token := &OauthToken{}
+	if err := json.Unmarshal([]byte(resp.Body()), token); err != nil {
+		return nil, fmt.Errorf("unmarshalling oauth token: %w", err)
+	}
+
+	return token, nil
+}
+
+// GetOauth2TokenFromRefreshToken will redeem a refresh token for a token for the resource
+func (c *AzureADClient) GetOauth2TokenFromRefreshToken(cfg *AuthenticationConfig, refreshToken string, resource string) (*OauthToken, error) {
+	params := map[string]string{
+		"grant_type":    "refresh_token",
+		"refresh_token": refreshToken,
+		"client_id":     cfg.ClientID,
+		"resource":      resource,
+		"client_info":   "1",
+	}
+
+	headers := make(map[string]string)
+	headers["Content-Type"] = "application/x-www-form-urlencoded; charset=utf-8"
+
+	body := c.endcodeQueryParams(params)
+
+	resp, err := c.httpClient.Post(cfg.Endpoints.TokenEndpoint, body, headers)
+	if err != nil {
+		return nil, err
+	}
+
 	if resp.ResponseCode() != http.StatusOK {
 		oidcResp := &OIDCErrorResponse{}
 		if err := json.Unmarshal([]byte(resp.Body()), oidcResp); err != nil {
@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/policy"
+)
+
+// TokenCredential returns a credential that gets tokens for the resource using the identity,
+// so that it can be used with the Azure SDK clients. The identity uses the v1 endpoints of
+// Azure AD so the tokens are always for the resource, whatever the scopes requested.
+func (a *ActiveDirectoryIdentity) TokenCredential(resource string) azcore.TokenCredential {
+	return &activeDirectoryCredential{
+		identity: a,
+		resource: resource,
+	}
+}
+
+type activeDirectoryCredential struct {
+	identity *ActiveDirectoryIdentity
+	resource string
+}
+
+// GetToken gets a token for the resource of the credential, a cached token is used if
+// there is one
+func (c *activeDirectoryCredential) GetToken(_ context.Context, _ policy.TokenRequestOptions) (azcore.AccessToken, error) {
+	token, err := c.identity.GetOAuthToken(c.resource)
+	if err != nil {
+		return azcore.AccessToken{}, fmt.Errorf("getting oauth token for %s: %w", c.resource, err)
+	}
+
+	return azcore.AccessToken{
+		Token:     token.AccessToken,
+		ExpiresOn: tokenExpiry(token),
+	}, nil
+}
+
+// tokenExpiry returns when the token expires, using the lifetime of the token if the
+// expiry time can't be parsed
+func tokenExpiry(token *OauthToken) time.Time {
+	if expiresOn, err := token.ExpiresOn.Int64(); err == nil && expiresOn > 0 {
+		return time.Unix(expiresOn, 0)
+	}
+	expiresIn, err := token.ExpiresIn.Int64()
+	if err != nil {
+		return time.Now()
+	}
+
+	return time.Now().Add(time.Duration(expiresIn) * time.Second)
+}
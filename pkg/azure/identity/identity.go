@@ -17,43 +17,49 @@ limitations under the License.
 package identity
 
 import (
-	"github.com/Azure/go-autorest/autorest"
+	"fmt"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"go.uber.org/zap"
 
 	khttp "github.com/fidelity/kconnect/pkg/http"
 )
 
-func NewAuthorizerIdentity(name, idProviderName string, authorizer autorest.Authorizer) *AuthorizerIdentity {
-	return &AuthorizerIdentity{
+// NewCredentialIdentity creates an identity that authenticates with an Azure SDK credential,
+// e.g. a managed identity
+func NewCredentialIdentity(name, idProviderName string, credential azcore.TokenCredential) *CredentialIdentity {
+	return &CredentialIdentity{
 		name:           name,
-		authorizer:     authorizer,
+		credential:     credential,
 		idProviderName: idProviderName,
 	}
 }
 
-type AuthorizerIdentity struct {
-	authorizer     autorest.Authorizer
+type CredentialIdentity struct {
+	credential     azcore.TokenCredential
 	name           string
 	idProviderName string
 }
 
-func (a *AuthorizerIdentity) Type() string {
-	return "azure-authorizer"
+func (a *CredentialIdentity) Type() string {
+	return "azure-credential"
 }
 
-func (a *AuthorizerIdentity) Name() string {
+func (a *CredentialIdentity) Name() string {
 	return a.name
 }
 
-func (a *AuthorizerIdentity) IsExpired() bool {
-	//TODO: how do we do this properly
+func (a *CredentialIdentity) IsExpired() bool {
+	// The credential gets a new token when its token expires
 	return false
 }
 
-func (a *AuthorizerIdentity) Authorizer() autorest.Authorizer {
-	return a.authorizer
+func (a *CredentialIdentity) Credential() azcore.TokenCredential {
+	return a.credential
 }
 
-func (a *AuthorizerIdentity) IdentityProviderName() string {
+func (a *CredentialIdentity) IdentityProviderName() string {
 	return a.idProviderName
 }
 
@@ -92,11 +98,86 @@ func (a *ActiveDirectoryIdentity) IdentityProviderName() string {
 	return a.idProviderName
 }
 
+// Tenants returns the tenant the user authenticated to followed by any additional tenants
+func (a *ActiveDirectoryIdentity) Tenants() []string {
+	tenants := []string{a.authCfg.Authority.Tenant}
+	for _, tenant := range a.authCfg.AdditionalTenants {
+		found := false
+		for _, existing := range tenants {
+			if strings.EqualFold(existing, tenant) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			tenants = append(tenants, tenant)
+		}
+	}
+
+	return tenants
+}
+
+// GetOAuthToken will get a token for the resource. If there is a token cache then a cached
+// token, or a token got using a cached refresh token, is used before authenticating.
 func (a *ActiveDirectoryIdentity) GetOAuthToken(resource string) (*OauthToken, error) {
 	if len(resource) == 0 {
 		return nil, ErrResourceRequired
 	}
 
+	if token := a.cachedOAuthToken(resource); token != nil {
+		return token, nil
+	}
+
+	token, err := a.authenticate(resource)
+	if err != nil {
+		return nil, err
+	}
+	a.cacheOAuthToken(resource, token)
+
+	return token, nil
+}
+
+func (a *ActiveDirectoryIdentity) cachedOAuthToken(resource string) *OauthToken {
+	cache := a.authCfg.TokenCache
+	if cache == nil {
+		return nil
+	}
+
+	token, found, err := cache.AccessToken(a.authCfg, resource)
+	if err != nil {
+		zap.S().Debugw("failed reading access token from token cache", "error", err.Error())
+		return nil
+	}
+	if found {
+		zap.S().Debugw("using cached access token", "resource", resource)
+		return token
+	}
+
+	refreshToken, found, err := cache.RefreshToken(a.authCfg)
+	if err != nil || !found {
+		return nil
+	}
+	token, err = NewClient(a.httpClient).GetOauth2TokenFromRefreshToken(a.authCfg, refreshToken, resource)
+	if err != nil {
+		zap.S().Debugw("failed redeeming cached refresh token", "error", err.Error())
+		return nil
+	}
+	zap.S().Debugw("using cached refresh token", "resource", resource)
+	a.cacheOAuthToken(resource, token)
+
+	return token
+}
+
+func (a *ActiveDirectoryIdentity) cacheOAuthToken(resource string, token *OauthToken) {
+	if a.authCfg.TokenCache == nil {
+		return
+	}
+	if err := a.authCfg.TokenCache.Store(a.authCfg, resource, token); err != nil {
+		zap.S().Debugw("failed storing token in token cache", "error", err.Error())
+	}
+}
+
+func (a *ActiveDirectoryIdentity) authenticate(resource string) (*OauthToken, error) {
 	var token *OauthToken
 	var err error
 
@@ -147,10 +228,11 @@ func (a *ActiveDirectoryIdentity) Clone(opts ...CloneOption) *ActiveDirectoryIde
 				Host:         a.authCfg.Authority.Host,
 				AuthorityURI: a.authCfg.Authority.AuthorityURI,
 			},
-			ClientID: a.authCfg.ClientID,
-			Username: a.authCfg.Username,
-			Password: a.authCfg.Password,
-			Scopes:   a.authCfg.Scopes,
+			ClientID:   a.authCfg.ClientID,
+			Username:   a.authCfg.Username,
+			Password:   a.authCfg.Password,
+			Scopes:     a.authCfg.Scopes,
+			TokenCache: a.authCfg.TokenCache,
 		},
 		realm: &UserRealm{
 			AccountType:           a.realm.AccountType,
@@ -163,6 +245,9 @@ func (a *ActiveDirectoryIdentity) Clone(opts ...CloneOption) *ActiveDirectoryIde
 		idProviderName: a.idProviderName,
 		httpClient:     a.httpClient,
 	}
+	if len(a.authCfg.AdditionalTenants) > 0 {
+		copyID.authCfg.AdditionalTenants = append([]string{}, a.authCfg.AdditionalTenants...)
+	}
 	if a.authCfg.Endpoints != nil {
 		copyID.authCfg.Endpoints = &Endpoints{
 			AuthorizationEndpoint: a.authCfg.Endpoints.AuthorizationEndpoint,
@@ -186,3 +271,18 @@ func WithClientID(clientID string) CloneOption {
 		a.authCfg.ClientID = clientID
 	}
 }
+
+// WithTenant changes the tenant, and so the authority and endpoints, of the cloned identity
+func WithTenant(tenantID string) CloneOption {
+	return func(a *ActiveDirectoryIdentity) {
+		authority := a.authCfg.Authority
+		authority.Tenant = tenantID
+		authority.AuthorityURI = fmt.Sprintf("https://%s/%s/", authority.Host, tenantID)
+
+		// The oauth endpoints are built from the authority without any requests
+		endpoints, err := NewOAuthEndpointsResolver(a.httpClient).Resolve(authority)
+		if err == nil {
+			a.authCfg.Endpoints = endpoints
+		}
+	}
+}
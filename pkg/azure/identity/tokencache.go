@@ -0,0 +1,357 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	credentialTypeAccessToken  = "AccessToken"
+	credentialTypeRefreshToken = "RefreshToken"
+
+	sectionAccessToken  = "AccessToken"
+	sectionRefreshToken = "RefreshToken"
+	sectionAccount      = "Account"
+
+	authorityTypeMSSTS = "MSSTS"
+
+	// tokenExpiryMargin is how long before it expires that a cached access token is not used
+	tokenExpiryMargin = 5 * time.Minute
+)
+
+var (
+	ErrNoClientInfo = errors.New("the token has no client info")
+)
+
+// TokenCache is a file based token cache in the format used by MSAL. The cache written by
+// the az CLI can be used so that the user isn't asked to authenticate again.
+type TokenCache struct {
+	path string
+	lock sync.Mutex
+	now  func() time.Time
+}
+
+// NewTokenCache creates a token cache using the MSAL cache file at the path
+func NewTokenCache(path string) *TokenCache {
+	return &TokenCache{
+		path: path,
+		now:  time.Now,
+	}
+}
+
+// DefaultTokenCachePath returns the path of the MSAL token cache used by the az CLI
+func DefaultTokenCachePath() string {
+	dir, err := os.UserHomeDir()
+	if err != nil {
+		return ""
+	}
+
+	return filepath.Join(dir, ".azure", "msal_token_cache.json")
+}
+
+// cacheSections are the sections of the cache file keyed by the cache key. The entries
+// are kept as raw json so that entries kconnect doesn't understand are preserved.
+type cacheSections map[string]map[string]json.RawMessage
+
+type cacheAccount struct {
+	HomeAccountID  string `json:"home_account_id"`
+	Environment    string `json:"environment"`
+	Realm          string `json:"realm"`
+	LocalAccountID string `json:"local_account_id"`
+	Username       string `json:"username"`
+	AuthorityType  string `json:"authority_type"`
+}
+
+type cacheCredential struct {
+	HomeAccountID     string `json:"home_account_id"`
+	Environment       string `json:"environment"`
+	CredentialType    string `json:"credential_type"`
+	ClientID          string `json:"client_id"`
+	Secret            string `json:"secret"`
+	Realm             string `json:"realm,omitempty"`
+	Target            string `json:"target,omitempty"`
+	CachedAt          string `json:"cached_at,omitempty"`
+	ExpiresOn         string `json:"expires_on,omitempty"`
+	ExtendedExpiresOn string `json:"extended_expires_on,omitempty"`
+	FamilyID          string `json:"family_id,omitempty"`
+}
+
+type clientInfo struct {
+	UID  string `json:"uid"`
+	UTID string `json:"utid"`
+}
+
+// AccessToken returns a cached access token for the resource that hasn't expired
+func (c *TokenCache) AccessToken(cfg *AuthenticationConfig, resource string) (*OauthToken, bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	sections, err := c.read()
+	if err != nil {
+		return nil, false, err
+	}
+	homeAccountID, found := findHomeAccountID(sections, cfg)
+	if !found {
+		return nil, false, nil
+	}
+
+	scope := resourceScope(resource)
+	for _, raw := range sections[sectionAccessToken] {
+		credential := &cacheCredential{}
+		if err := json.Unmarshal(raw, credential); err != nil {
+			continue
+		}
+		if !strings.EqualFold(credential.HomeAccountID, homeAccountID) ||
+			!strings.EqualFold(credential.Environment, string(cfg.Authority.Host)) ||
+			!strings.EqualFold(credential.ClientID, cfg.ClientID) ||
+			!strings.EqualFold(credential.Realm, cfg.Authority.Tenant) ||
+			!hasScope(credential.Target, scope) {
+			continue
+		}
+
+		expiresOn, err := strconv.ParseInt(credential.ExpiresOn, 10, 64)
+		if err != nil || c.now().Add(tokenExpiryMargin).After(time.Unix(expiresOn, 0)) {
+			continue
+		}
+
+		return &OauthToken{
+			Type:        "Bearer",
+			Resource:    resource,
+			AccessToken: credential.Secret,
+			ExpiresOn:   json.Number(credential.ExpiresOn),
+			ExpiresIn:   json.Number(strconv.FormatInt(expiresOn-c.now().Unix(), 10)),
+		}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// RefreshToken returns a cached refresh token for the user. Refresh tokens aren't specific
+// to a tenant or resource.
+func (c *TokenCache) RefreshToken(cfg *AuthenticationConfig) (string, bool, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	sections, err := c.read()
+	if err != nil {
+		return "", false, err
+	}
+	homeAccountID, found := findHomeAccountID(sections, cfg)
+	if !found {
+		return "", false, nil
+	}
+
+	for _, raw := range sections[sectionRefreshToken] {
+		credential := &cacheCredential{}
+		if err := json.Unmarshal(raw, credential); err != nil {
+			continue
+		}
+		if strings.EqualFold(credential.HomeAccountID, homeAccountID) &&
+			strings.EqualFold(credential.Environment, string(cfg.Authority.Host)) &&
+			strings.EqualFold(credential.ClientID, cfg.ClientID) &&
+			credential.Secret != "" {
+			return credential.Secret, true, nil
+		}
+	}
+
+	return "", false, nil
+}
+
+// Store will add the token for the resource to the cache, replacing any existing access
+// and refresh tokens for the user and resource
+func (c *TokenCache) Store(cfg *AuthenticationConfig, resource string, token *OauthToken) error {
+	if token.ClientInfo == "" {
+		return ErrNoClientInfo
+	}
+	info, err := decodeClientInfo(token.ClientInfo)
+	if err != nil {
+		return err
+	}
+
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	sections, err := c.read()
+	if err != nil {
+		return err
+	}
+
+	homeAccountID := fmt.Sprintf("%s.%s", info.UID, info.UTID)
+	environment := string(cfg.Authority.Host)
+	now := c.now().Unix()
+
+	account := &cacheAccount{
+		HomeAccountID:  homeAccountID,
+		Environment:    environment,
+		Realm:          cfg.Authority.Tenant,
+		LocalAccountID: info.UID,
+		Username:       cfg.Username,
+		AuthorityType:  authorityTypeMSSTS,
+	}
+	if err := sections.set(sectionAccount, cacheKey(homeAccountID, environment, cfg.Authority.Tenant), account); err != nil {
+		return err
+	}
+
+	expiresOn := token.ExpiresOn.String()
+	if expiresOn == "" {
+		if expiresIn, err := token.ExpiresIn.Int64(); err == nil {
+			expiresOn = strconv.FormatInt(now+expiresIn, 10)
+		}
+	}
+	scope := resourceScope(resource)
+	accessToken := &cacheCredential{
+		HomeAccountID:     homeAccountID,
+		Environment:       environment,
+		CredentialType:    credentialTypeAccessToken,
+		ClientID:          cfg.ClientID,
+		Secret:            token.AccessToken,
+		Realm:             cfg.Authority.Tenant,
+		Target:            scope,
+		CachedAt:          strconv.FormatInt(now, 10),
+		ExpiresOn:         expiresOn,
+		ExtendedExpiresOn: expiresOn,
+	}
+	accessTokenKey := cacheKey(homeAccountID, environment, strings.ToLower(credentialTypeAccessToken), cfg.ClientID, cfg.Authority.Tenant, scope)
+	if err := sections.set(sectionAccessToken, accessTokenKey, accessToken); err != nil {
+		return err
+	}
+
+	if token.RefreshToken != "" {
+		refreshToken := &cacheCredential{
+			HomeAccountID:  homeAccountID,
+			Environment:    environment,
+			CredentialType: credentialTypeRefreshToken,
+			ClientID:       cfg.ClientID,
+			Secret:         token.RefreshToken,
+		}
+		refreshTokenKey := cacheKey(homeAccountID, environment, strings.ToLower(credentialTypeRefreshToken), cfg.ClientID, "", "")
+		if err := sections.set(sectionRefreshToken, refreshTokenKey, refreshToken); err != nil {
+			return err
+		}
+	}
+
+	return c.write(sections)
+}
+
+func (c *TokenCache) read() (cacheSections, error) {
+	sections := cacheSections{}
+
+	data, err := os.ReadFile(c.path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return sections, nil
+		}
+		return nil, fmt.Errorf("reading token cache %s: %w", c.path, err)
+	}
+	if len(data) == 0 {
+		return sections, nil
+	}
+	if err := json.Unmarshal(data, &sections); err != nil {
+		return nil, fmt.Errorf("unmarshalling token cache %s: %w", c.path, err)
+	}
+
+	return sections, nil
+}
+
+func (c *TokenCache) write(sections cacheSections) error {
+	data, err := json.MarshalIndent(sections, "", "    ")
+	if err != nil {
+		return fmt.Errorf("marshalling token cache: %w", err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(c.path), 0700); err != nil {
+		return fmt.Errorf("creating token cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0600); err != nil {
+		return fmt.Errorf("writing token cache %s: %w", c.path, err)
+	}
+
+	return nil
+}
+
+func (s cacheSections) set(section, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshalling %s cache entry: %w", section, err)
+	}
+	if s[section] == nil {
+		s[section] = make(map[string]json.RawMessage)
+	}
+	s[section][key] = data
+
+	return nil
+}
+
+// findHomeAccountID returns the home account id of the cached account for the user
+func findHomeAccountID(sections cacheSections, cfg *AuthenticationConfig) (string, bool) {
+	for _, raw := range sections[sectionAccount] {
+		account := &cacheAccount{}
+		if err := json.Unmarshal(raw, account); err != nil {
+			continue
+		}
+		if strings.EqualFold(account.Username, cfg.Username) && strings.EqualFold(account.Environment, string(cfg.Authority.Host)) {
+			return account.HomeAccountID, true
+		}
+	}
+
+	return "", false
+}
+
+func decodeClientInfo(encoded string) (*clientInfo, error) {
+	data, err := base64.RawURLEncoding.DecodeString(strings.TrimRight(encoded, "="))
+	if err != nil {
+		return nil, fmt.Errorf("decoding client info: %w", err)
+	}
+	info := &clientInfo{}
+	if err := json.Unmarshal(data, info); err != nil {
+		return nil, fmt.Errorf("unmarshalling client info: %w", err)
+	}
+	if info.UID == "" || info.UTID == "" {
+		return nil, ErrNoClientInfo
+	}
+
+	return info, nil
+}
+
+// resourceScope returns the MSAL scope for a resource (i.e. audience) based token
+func resourceScope(resource string) string {
+	return resource + "/.default"
+}
+
+func hasScope(target, scope string) bool {
+	for _, targetScope := range strings.Fields(target) {
+		if strings.EqualFold(targetScope, scope) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func cacheKey(parts ...string) string {
+	return strings.ToLower(strings.Join(parts, "-"))
+}
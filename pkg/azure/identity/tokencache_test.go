@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strconv"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func testAuthConfig() *AuthenticationConfig {
+	return &AuthenticationConfig{
+		Authority: &AuthorityConfig{
+			Tenant: "tenant1",
+			Host:   AADHostWorldwide,
+		},
+		ClientID: "client1",
+		Username: "user@example.com",
+	}
+}
+
+func testToken(expiresOn time.Time) *OauthToken {
+	info := base64.RawURLEncoding.EncodeToString([]byte(`{"uid":"user1","utid":"tenant1"}`))
+
+	return &OauthToken{
+		AccessToken:  "access",
+		RefreshToken: "refresh",
+		ExpiresOn:    json.Number(strconv.FormatInt(expiresOn.Unix(), 10)),
+		ClientInfo:   info,
+	}
+}
+
+func TestTokenCache(t *testing.T) {
+	testCases := []struct {
+		name            string
+		expiresIn       time.Duration
+		resource        string
+		lookupResource  string
+		expectFound     bool
+		expectRefreshed bool
+	}{
+		{
+			name:           "access token for resource",
+			expiresIn:      time.Hour,
+			resource:       "https://management.azure.com/",
+			lookupResource: "https://management.azure.com/",
+			expectFound:    true,
+		},
+		{
+			name:           "access token expiring soon",
+			expiresIn:      time.Minute,
+			resource:       "https://management.azure.com/",
+			lookupResource: "https://management.azure.com/",
+			expectFound:    false,
+		},
+		{
+			name:           "access token for other resource",
+			expiresIn:      time.Hour,
+			resource:       "https://management.azure.com/",
+			lookupResource: "6dae42f8-4368-4678-94ff-3960e28e3630",
+			expectFound:    false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cache := NewTokenCache(filepath.Join(t.TempDir(), "msal_token_cache.json"))
+			cfg := testAuthConfig()
+			g.Expect(cache.Store(cfg, tc.resource, testToken(time.Now().Add(tc.expiresIn)))).To(Succeed()) //nolint:scopelint
+
+			token, found, err := cache.AccessToken(cfg, tc.lookupResource) //nolint:scopelint
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(found).To(Equal(tc.expectFound)) //nolint:scopelint
+			if tc.expectFound {                       //nolint:scopelint
+				g.Expect(token.AccessToken).To(Equal("access"))
+			}
+
+			refreshToken, found, err := cache.RefreshToken(cfg)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(found).To(BeTrue())
+			g.Expect(refreshToken).To(Equal("refresh"))
+		})
+	}
+}
+
+func TestTokenCacheKeepsOtherEntries(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "msal_token_cache.json")
+	existing := `{
+		"Account": {
+			"other.tenant-login.microsoftonline.com-tenant": {"home_account_id": "other.tenant", "environment": "login.microsoftonline.com", "username": "other@example.com"}
+		},
+		"AppMetadata": {
+			"appmetadata-login.microsoftonline.com-client1": {"client_id": "client1", "environment": "login.microsoftonline.com", "family_id": "1"}
+		}
+	}`
+	g.Expect(os.WriteFile(path, []byte(existing), 0600)).To(Succeed())
+
+	cache := NewTokenCache(path)
+	g.Expect(cache.Store(testAuthConfig(), "https://management.azure.com/", testToken(time.Now().Add(time.Hour)))).To(Succeed())
+
+	data, err := os.ReadFile(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	sections := cacheSections{}
+	g.Expect(json.Unmarshal(data, &sections)).To(Succeed())
+	g.Expect(sections["AppMetadata"]).To(HaveKey("appmetadata-login.microsoftonline.com-client1"))
+	g.Expect(sections["Account"]).To(HaveKey("other.tenant-login.microsoftonline.com-tenant"))
+	g.Expect(sections["Account"]).To(HaveKey("user1.tenant1-login.microsoftonline.com-tenant1"))
+	g.Expect(sections["AccessToken"]).To(HaveKey("user1.tenant1-login.microsoftonline.com-accesstoken-client1-tenant1-https://management.azure.com//.default"))
+	g.Expect(sections["RefreshToken"]).To(HaveKey("user1.tenant1-login.microsoftonline.com-refreshtoken-client1--"))
+}
+
+func TestTokenCacheNoClientInfo(t *testing.T) {
+	g := NewWithT(t)
+
+	cache := NewTokenCache(filepath.Join(t.TempDir(), "msal_token_cache.json"))
+	err := cache.Store(testAuthConfig(), "https://management.azure.com/", &OauthToken{AccessToken: "access"})
+	g.Expect(err).To(MatchError(ErrNoClientInfo))
+}
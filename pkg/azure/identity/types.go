@@ -21,6 +21,8 @@ import (
 	"encoding/xml"
 
 	"github.com/fidelity/kconnect/pkg/azure/wstrust"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	provid "github.com/fidelity/kconnect/pkg/provider/identity"
 )
 
 type Client interface {
@@ -29,6 +31,7 @@ type Client interface {
 	GetWsTrustResponse(cfg *AuthenticationConfig, cloudAudienceURN string, endpoint *wstrust.Endpoint) (*WSTrustResponse, error)
 	GetOauth2TokenFromSamlAssertion(cfg *AuthenticationConfig, assertion string, resource string) (*OauthToken, error)
 	GetOauth2TokenFromUsernamePassword(cfg *AuthenticationConfig, resource string) (*OauthToken, error)
+	GetOauth2TokenFromRefreshToken(cfg *AuthenticationConfig, refreshToken string, resource string) (*OauthToken, error)
 }
 
 type AuthorityConfig struct {
@@ -44,6 +47,10 @@ type AuthenticationConfig struct {
 	Password  string
 	Scopes    []string
 	Endpoints *Endpoints
+	// TokenCache is used to share tokens with other tools, it's optional
+	TokenCache *TokenCache
+	// AdditionalTenants are the other tenants that the user can authenticate to
+	AdditionalTenants []string
 }
 
 type Endpoints struct {
@@ -116,6 +123,7 @@ type OauthToken struct {
 	AccessToken  string      `json:"access_token"`
 	RefreshToken string      `json:"refresh_token"`
 	IDToken      string      `json:"id_token"`
+	ClientInfo   string      `json:"client_info"`
 }
 
 // OIDCErrorResponse represents an error message from the Azure AD OIDC service
@@ -133,6 +141,44 @@ func (r *OIDCErrorResponse) Error() string {
 	return r.ErrorDescription
 }
 
+// ErrorCode returns the code for the error. Azure AD returns AADSTS50074, 50076 or
+// 50079 when multi-factor authentication is required for the user, 500121 when the
+// multi-factor authentication was denied or not completed, 50126 or 50034 when the
+// username or password is wrong and 50053 or 50057 when the account is locked or
+// disabled.
+func (r *OIDCErrorResponse) ErrorCode() kerrors.Code {
+	for _, code := range r.ErrorCodes {
+		switch code {
+		case 50074, 50076, 50079:
+			return kerrors.CodeMFARequired
+		case 500121:
+			return kerrors.CodeMFADenied
+		case 50126, 50034:
+			return kerrors.CodeInvalidCredentials
+		case 50053, 50057:
+			return kerrors.CodeAccountLocked
+		}
+	}
+
+	return kerrors.CodeAuthFailed
+}
+
+// credentialError returns the error as a credential error when Azure AD rejected the
+// password grant because of the user's password or account, so that the password can
+// be entered again. Other errors, e.g. MFA being required, are returned as they are.
+func (r *OIDCErrorResponse) credentialError() error {
+	if r.ErrorType != "invalid_grant" {
+		return r
+	}
+
+	switch code := r.ErrorCode(); code {
+	case kerrors.CodeInvalidCredentials, kerrors.CodeAccountLocked:
+		return provid.NewCredentialError(code, r, "password")
+	default:
+		return r
+	}
+}
+
 type EnvelopeParams struct {
 	SchemaLocation        string
 	SoapAction            string
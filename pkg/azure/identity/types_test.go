@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	provid "github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+func TestOIDCErrorResponseCredentialError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		resp     *OIDCErrorResponse
+		code     kerrors.Code
+		rejected []string
+	}{
+		{
+			name:     "wrong password",
+			resp:     &OIDCErrorResponse{ErrorType: "invalid_grant", ErrorCodes: []int{50126}},
+			code:     kerrors.CodeInvalidCredentials,
+			rejected: []string{"password"},
+		},
+		{
+			name: "locked account",
+			resp: &OIDCErrorResponse{ErrorType: "invalid_grant", ErrorCodes: []int{50053}},
+			code: kerrors.CodeAccountLocked,
+		},
+		{
+			name: "mfa required",
+			resp: &OIDCErrorResponse{ErrorType: "invalid_grant", ErrorCodes: []int{50076}},
+			code: kerrors.CodeMFARequired,
+		},
+		{
+			name: "invalid client",
+			resp: &OIDCErrorResponse{ErrorType: "invalid_client", ErrorCodes: []int{50126}},
+			code: kerrors.CodeInvalidCredentials,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tc.resp.credentialError()
+			g.Expect(kerrors.CodeOf(err)).To(Equal(tc.code))
+			g.Expect(provid.RejectedItems(err)).To(Equal(tc.rejected))
+		})
+	}
+}
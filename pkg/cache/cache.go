@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+var (
+	ErrKeyRequired = errors.New("cache key is required")
+)
+
+// Cache is a store of values that expire after a time to live
+type Cache interface {
+	// Get will unmarshall the value for the key into out. It returns false if
+	// there is no value for the key or it has expired.
+	Get(key string, out interface{}) (bool, error)
+	// Set will store the value for the key
+	Set(key string, value interface{}) error
+	// Delete will remove the value for the key
+	Delete(key string) error
+}
+
+type entry struct {
+	Expires time.Time       `json:"expires"`
+	Value   json.RawMessage `json:"value"`
+}
+
+// New creates a cache that stores each value as a file in the directory. A ttl of zero or
+// less disables the cache.
+func New(directory string, ttl time.Duration) Cache {
+	return &fileCache{
+		directory: directory,
+		ttl:       ttl,
+		now:       time.Now,
+	}
+}
+
+// NewReadOnly creates a cache that returns the values stored in the directory even if
+// they have expired, and doesn't change them. It's used when the last known values
+// are good enough, e.g. when offline.
+func NewReadOnly(directory string) Cache {
+	return &fileCache{
+		directory: directory,
+		readOnly:  true,
+		now:       time.Now,
+	}
+}
+
+type fileCache struct {
+	directory string
+	ttl       time.Duration
+	readOnly  bool
+	now       func() time.Time
+}
+
+func (c *fileCache) Get(key string, out interface{}) (bool, error) {
+	if c.ttl <= 0 && !c.readOnly {
+		return false, nil
+	}
+	path, err := c.path(key)
+	if err != nil {
+		return false, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, fmt.Errorf("reading cache file %s: %w", path, err)
+	}
+
+	cached := &entry{}
+	if err := json.Unmarshal(data, cached); err != nil {
+		// A corrupt file is treated as a miss and is replaced on the next set
+		return false, nil //nolint: nilerr
+	}
+	if !c.readOnly && !c.now().Before(cached.Expires) {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(cached.Value, out); err != nil {
+		return false, fmt.Errorf("unmarshalling cached value: %w", err)
+	}
+
+	return true, nil
+}
+
+func (c *fileCache) Set(key string, value interface{}) error {
+	if c.ttl <= 0 || c.readOnly {
+		return nil
+	}
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+
+	valueData, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshalling value: %w", err)
+	}
+	data, err := json.Marshal(&entry{
+		Expires: c.now().Add(c.ttl),
+		Value:   valueData,
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling cache entry: %w", err)
+	}
+
+	if err := os.MkdirAll(c.directory, os.ModePerm); err != nil {
+		return fmt.Errorf("creating cache directory %s: %w", c.directory, err)
+	}
+	if err := os.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing cache file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (c *fileCache) Delete(key string) error {
+	if c.readOnly {
+		return nil
+	}
+	path, err := c.path(key)
+	if err != nil {
+		return err
+	}
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing cache file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// path returns the file for the key. The key is hashed as it can contain
+// characters that aren't valid in a file name.
+func (c *fileCache) path(key string) (string, error) {
+	if key == "" {
+		return "", ErrKeyRequired
+	}
+	hash := sha256.Sum256([]byte(key))
+
+	return filepath.Join(c.directory, hex.EncodeToString(hash[:])+".json"), nil
+}
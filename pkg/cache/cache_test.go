@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package cache
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+type cachedValue struct {
+	Name  string `json:"name"`
+	Count int    `json:"count"`
+}
+
+func TestCache(t *testing.T) {
+	testCases := []struct {
+		name        string
+		ttl         time.Duration
+		elapsed     time.Duration
+		expectFound bool
+	}{
+		{
+			name:        "value before expiry",
+			ttl:         time.Hour,
+			elapsed:     time.Minute,
+			expectFound: true,
+		},
+		{
+			name:        "value after expiry",
+			ttl:         time.Hour,
+			elapsed:     2 * time.Hour,
+			expectFound: false,
+		},
+		{
+			name:        "cache disabled",
+			ttl:         0,
+			elapsed:     0,
+			expectFound: false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			start := time.Now()
+			c := &fileCache{directory: t.TempDir(), ttl: tc.ttl, now: func() time.Time { return start }} //nolint:scopelint
+			g.Expect(c.Set("subscriptions/tenant", &cachedValue{Name: "dev", Count: 2})).To(Succeed())
+
+			c.now = func() time.Time { return start.Add(tc.elapsed) } //nolint:scopelint
+			out := &cachedValue{}
+			found, err := c.Get("subscriptions/tenant", out)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(found).To(Equal(tc.expectFound)) //nolint:scopelint
+			if tc.expectFound {                       //nolint:scopelint
+				g.Expect(out).To(Equal(&cachedValue{Name: "dev", Count: 2}))
+			}
+		})
+	}
+}
+
+func TestCacheMissingAndDelete(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	c := New(dir, time.Hour)
+
+	found, err := c.Get("missing", &cachedValue{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+
+	g.Expect(c.Set("key", &cachedValue{Name: "dev"})).To(Succeed())
+	g.Expect(c.Delete("key")).To(Succeed())
+	found, err = c.Get("key", &cachedValue{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+
+	g.Expect(c.Delete("key")).To(Succeed())
+	g.Expect(c.Set("", &cachedValue{})).To(MatchError(ErrKeyRequired))
+}
+
+func TestCacheCorruptFile(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	c := New(dir, time.Hour).(*fileCache)
+	path, err := c.path("key")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(os.WriteFile(path, []byte("not json"), 0600)).To(Succeed())
+	g.Expect(filepath.Dir(path)).To(Equal(dir))
+
+	found, err := c.Get("key", &cachedValue{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+}
+
+func TestCacheReadOnly(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	start := time.Now()
+	c := &fileCache{directory: dir, ttl: time.Hour, now: func() time.Time { return start }}
+	g.Expect(c.Set("key", &cachedValue{Name: "dev"})).To(Succeed())
+
+	readOnly := NewReadOnly(dir).(*fileCache)
+	readOnly.now = func() time.Time { return start.Add(2 * time.Hour) }
+
+	// Expired values are still returned
+	out := &cachedValue{}
+	found, err := readOnly.Get("key", out)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+	g.Expect(out.Name).To(Equal("dev"))
+
+	// The values aren't changed
+	g.Expect(readOnly.Set("key", &cachedValue{Name: "prod"})).To(Succeed())
+	g.Expect(readOnly.Delete("key")).To(Succeed())
+	found, err = readOnly.Get("key", out)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+	g.Expect(out.Name).To(Equal("dev"))
+}
@@ -0,0 +1,208 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package ci integrates kconnect with the CI system it is running in, e.g. so that
+// secrets are masked in the job logs and the kubeconfig can be used by later steps.
+package ci
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+const kubeconfigFileName = "kconnect-kubeconfig"
+
+// Environment is the CI system kconnect is running in
+type Environment interface {
+	// Name is the name of the CI system
+	Name() string
+	// KubeconfigPath is the path in the job the kubeconfig is written to when
+	// one isn't specified. An empty path means the default kubeconfig is used.
+	KubeconfigPath() string
+	// MaskSecret will hide the value in the job logs
+	MaskSecret(value string)
+	// ExportVariable will set the environment variable for the following steps of the job
+	ExportVariable(name, value string) error
+	// AddSummary will add the markdown to the summary of the step
+	AddSummary(markdown string) error
+}
+
+// Enabled returns true if --ci is in the args or KCONNECT_CI is set to true. It is used
+// before the flags are parsed, e.g. to configure the logging.
+func Enabled(args []string) bool {
+	for _, arg := range args {
+		if arg == "--ci" {
+			return true
+		}
+		if strings.HasPrefix(arg, "--ci=") {
+			enabled, _ := strconv.ParseBool(strings.TrimPrefix(arg, "--ci="))
+			return enabled
+		}
+	}
+	enabled, _ := strconv.ParseBool(os.Getenv("KCONNECT_CI"))
+
+	return enabled
+}
+
+// Detect returns the CI system using its environment variables. A generic
+// environment is returned if the CI system isn't known.
+func Detect() Environment {
+	return detect(os.Stdout)
+}
+
+func detect(out io.Writer) Environment {
+	switch {
+	case os.Getenv("GITHUB_ACTIONS") == "true":
+		return &gitHubActions{out: out}
+	case strings.EqualFold(os.Getenv("TF_BUILD"), "true"):
+		return &azurePipelines{out: out}
+	default:
+		return &generic{}
+	}
+}
+
+// gitHubActions uses the workflow commands and environment files of GitHub Actions
+type gitHubActions struct {
+	out io.Writer
+}
+
+func (g *gitHubActions) Name() string {
+	return "github-actions"
+}
+
+func (g *gitHubActions) KubeconfigPath() string {
+	return tempPath(os.Getenv("RUNNER_TEMP"))
+}
+
+func (g *gitHubActions) MaskSecret(value string) {
+	// Each line of a multi-line secret has to be masked
+	for _, line := range secretLines(value) {
+		fmt.Fprintf(g.out, "::add-mask::%s\n", line)
+	}
+}
+
+func (g *gitHubActions) ExportVariable(name, value string) error {
+	return appendToFile(os.Getenv("GITHUB_ENV"), fmt.Sprintf("%s=%s\n", name, value))
+}
+
+func (g *gitHubActions) AddSummary(markdown string) error {
+	return appendToFile(os.Getenv("GITHUB_STEP_SUMMARY"), markdown)
+}
+
+// azurePipelines uses the logging commands of Azure Pipelines
+type azurePipelines struct {
+	out io.Writer
+}
+
+func (a *azurePipelines) Name() string {
+	return "azure-pipelines"
+}
+
+func (a *azurePipelines) KubeconfigPath() string {
+	return tempPath(os.Getenv("AGENT_TEMPDIRECTORY"))
+}
+
+func (a *azurePipelines) MaskSecret(value string) {
+	for _, line := range secretLines(value) {
+		fmt.Fprintf(a.out, "##vso[task.setsecret]%s\n", line)
+	}
+}
+
+func (a *azurePipelines) ExportVariable(name, value string) error {
+	fmt.Fprintf(a.out, "##vso[task.setvariable variable=%s]%s\n", name, value)
+	return nil
+}
+
+func (a *azurePipelines) AddSummary(markdown string) error {
+	// The summary is uploaded from a file, which must exist when the job finishes
+	dir := os.Getenv("AGENT_TEMPDIRECTORY")
+	if dir == "" {
+		return nil
+	}
+	summaryFile, err := os.CreateTemp(dir, "kconnect-summary-*.md")
+	if err != nil {
+		return fmt.Errorf("creating summary file: %w", err)
+	}
+	defer summaryFile.Close()
+	if _, err := summaryFile.WriteString(markdown); err != nil {
+		return fmt.Errorf("writing summary file: %w", err)
+	}
+	fmt.Fprintf(a.out, "##vso[task.uploadsummary]%s\n", summaryFile.Name())
+
+	return nil
+}
+
+// generic is used for a CI system without integration, the prompts and colors are
+// still disabled but secrets can't be masked
+type generic struct{}
+
+func (g *generic) Name() string {
+	return "generic"
+}
+
+func (g *generic) KubeconfigPath() string {
+	return ""
+}
+
+func (g *generic) MaskSecret(value string) {}
+
+func (g *generic) ExportVariable(name, value string) error {
+	return nil
+}
+
+func (g *generic) AddSummary(markdown string) error {
+	return nil
+}
+
+func tempPath(dir string) string {
+	if dir == "" {
+		return ""
+	}
+	return filepath.Join(dir, kubeconfigFileName)
+}
+
+func secretLines(value string) []string {
+	lines := []string{}
+	for _, line := range strings.Split(value, "\n") {
+		line = strings.TrimSpace(line)
+		if line != "" {
+			lines = append(lines, line)
+		}
+	}
+
+	return lines
+}
+
+func appendToFile(path, content string) error {
+	if path == "" {
+		return nil
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+	if err != nil {
+		return fmt.Errorf("opening %s: %w", path, err)
+	}
+	defer file.Close()
+
+	if _, err := file.WriteString(content); err != nil {
+		return fmt.Errorf("writing %s: %w", path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,83 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ci
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestGitHubActions(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	t.Setenv("GITHUB_ACTIONS", "true")
+	t.Setenv("RUNNER_TEMP", dir)
+	t.Setenv("GITHUB_ENV", filepath.Join(dir, "env"))
+	t.Setenv("GITHUB_STEP_SUMMARY", filepath.Join(dir, "summary"))
+
+	out := &bytes.Buffer{}
+	env := detect(out)
+	g.Expect(env.Name()).To(Equal("github-actions"))
+	g.Expect(env.KubeconfigPath()).To(Equal(filepath.Join(dir, kubeconfigFileName)))
+
+	env.MaskSecret("token\nsecond-line\n")
+	g.Expect(out.String()).To(Equal("::add-mask::token\n::add-mask::second-line\n"))
+
+	g.Expect(env.ExportVariable("KUBECONFIG", "/tmp/config")).To(Succeed())
+	g.Expect(os.ReadFile(filepath.Join(dir, "env"))).To(BeEquivalentTo("KUBECONFIG=/tmp/config\n"))
+
+	g.Expect(env.AddSummary("### kconnect\n")).To(Succeed())
+	g.Expect(os.ReadFile(filepath.Join(dir, "summary"))).To(BeEquivalentTo("### kconnect\n"))
+}
+
+func TestAzurePipelines(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	t.Setenv("GITHUB_ACTIONS", "")
+	t.Setenv("TF_BUILD", "True")
+	t.Setenv("AGENT_TEMPDIRECTORY", dir)
+
+	out := &bytes.Buffer{}
+	env := detect(out)
+	g.Expect(env.Name()).To(Equal("azure-pipelines"))
+
+	env.MaskSecret("token")
+	g.Expect(env.ExportVariable("KUBECONFIG", "/tmp/config")).To(Succeed())
+	g.Expect(out.String()).To(Equal("##vso[task.setsecret]token\n##vso[task.setvariable variable=KUBECONFIG]/tmp/config\n"))
+
+	out.Reset()
+	g.Expect(env.AddSummary("### kconnect\n")).To(Succeed())
+	g.Expect(out.String()).To(HavePrefix("##vso[task.uploadsummary]" + dir))
+}
+
+func TestEnabled(t *testing.T) {
+	g := NewWithT(t)
+
+	t.Setenv("KCONNECT_CI", "")
+	g.Expect(Enabled([]string{"kconnect", "use", "eks", "--ci"})).To(BeTrue())
+	g.Expect(Enabled([]string{"kconnect", "use", "eks", "--ci=false"})).To(BeFalse())
+	g.Expect(Enabled([]string{"kconnect", "use", "eks"})).To(BeFalse())
+
+	t.Setenv("KCONNECT_CI", "true")
+	g.Expect(Enabled([]string{"kconnect", "use", "eks"})).To(BeTrue())
+}
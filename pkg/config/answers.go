@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"io/ioutil"
+
+	"gopkg.in/yaml.v3"
+)
+
+// LoadAnswers will read an answers file that maps the name of each prompt to the value
+// to use instead of asking the user. The names must be configuration items in the
+// supplied set or one of the additional prompt names.
+func LoadAnswers(path string, cs ConfigurationSet, promptNames []string) (map[string]string, error) {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading answers file %s: %w", path, err)
+	}
+
+	validationErrs, err := ValidateAnswers(path, data, cs, promptNames)
+	if err != nil {
+		return nil, err
+	}
+	if len(validationErrs) > 0 {
+		return nil, fmt.Errorf("validating answers file: %w", validationErrs)
+	}
+
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(data, root); err != nil {
+		return nil, fmt.Errorf("parsing answers file %s: %w", path, err)
+	}
+
+	answers := map[string]string{}
+	if len(root.Content) == 0 {
+		return answers, nil
+	}
+	node := root.Content[0]
+	for i := 0; i < len(node.Content); i += 2 {
+		answers[node.Content[i].Value] = node.Content[i+1].Value
+	}
+
+	return answers, nil
+}
+
+// ValidateAnswers will validate the answers file data against the configuration items
+// and additional prompt names and return any problems found
+func ValidateAnswers(file string, data []byte, cs ConfigurationSet, promptNames []string) (ValidationErrors, error) {
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(data, root); err != nil {
+		return nil, fmt.Errorf("parsing answers file %s: %w", file, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	v := &validator{
+		file:  file,
+		lists: map[string]bool{},
+	}
+	node := root.Content[0]
+	if !v.expectMapping(node, "answers") {
+		return v.errs, nil
+	}
+
+	validKeys := append(itemNames(cs), promptNames...)
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+
+		if value.Kind != yaml.ScalarNode {
+			v.addError(value, key.Value, "expected a single value", "")
+			continue
+		}
+
+		item := cs.Get(key.Value)
+		switch {
+		case item != nil:
+			v.validateItemValue(value, key.Value, item)
+		case contains(promptNames, key.Value):
+		default:
+			v.unknownKey(key, key.Value, validKeys)
+		}
+	}
+
+	if len(v.errs) == 0 {
+		return nil, nil
+	}
+
+	return v.errs, nil
+}
@@ -0,0 +1,115 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/config"
+)
+
+func TestValidateAnswers(t *testing.T) {
+	testCases := []struct {
+		name         string
+		data         string
+		expectErrors []string
+	}{
+		{
+			name: "valid answers",
+			data: `username: bob
+idp-protocol: saml
+cluster: dev
+`,
+			expectErrors: []string{},
+		},
+		{
+			name: "unknown answer",
+			data: `usernme: bob
+`,
+			expectErrors: []string{`answers.yaml:1: usernme: unknown key "usernme" (did you mean "username"?)`},
+		},
+		{
+			name: "invalid value",
+			data: `idp-protocol: sam
+max-history: lots
+`,
+			expectErrors: []string{
+				`answers.yaml:1: idp-protocol: value "sam" is not allowed (did you mean "saml"?)`,
+				`answers.yaml:2: max-history: value "lots" is not a valid int (use a whole number, e.g. 10)`,
+			},
+		},
+		{
+			name: "not a single value",
+			data: `username:
+- bob
+`,
+			expectErrors: []string{`answers.yaml:2: username: expected a single value`},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			validationErrs, err := config.ValidateAnswers("answers.yaml", []byte(tc.data), createAnswersConfigSet(t), []string{"cluster"}) //nolint:scopelint
+			g.Expect(err).NotTo(HaveOccurred())
+
+			actual := []string{}
+			for _, validationErr := range validationErrs {
+				actual = append(actual, validationErr.Error())
+			}
+			g.Expect(actual).To(Equal(tc.expectErrors)) //nolint:scopelint
+		})
+	}
+}
+
+func TestLoadAnswers(t *testing.T) {
+	g := NewWithT(t)
+
+	path := filepath.Join(t.TempDir(), "answers.yaml")
+	g.Expect(os.WriteFile(path, []byte("username: bob\nmax-history: 10\ncluster: dev\n"), 0600)).To(Succeed())
+
+	answers, err := config.LoadAnswers(path, createAnswersConfigSet(t), []string{"cluster"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(answers).To(Equal(map[string]string{
+		"username":    "bob",
+		"max-history": "10",
+		"cluster":     "dev",
+	}))
+
+	g.Expect(os.WriteFile(path, []byte("usernme: bob\n"), 0600)).To(Succeed())
+	_, err = config.LoadAnswers(path, createAnswersConfigSet(t), []string{"cluster"})
+	g.Expect(config.IsValidationFailed(err)).To(BeTrue())
+}
+
+func createAnswersConfigSet(t *testing.T) config.ConfigurationSet {
+	g := NewWithT(t)
+
+	cs := config.NewConfigurationSet()
+	_, err := cs.String("username", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = cs.Int("max-history", 100, "")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = cs.Enum("idp-protocol", "", []string{"aws-iam", "saml"}, "")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	return cs
+}
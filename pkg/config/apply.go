@@ -19,6 +19,8 @@ package config
 import (
 	"fmt"
 	"strconv"
+	"strings"
+	"time"
 )
 
 // ApplyToConfigSet will apply the saved app configuration to the supplied config set.
@@ -38,13 +40,18 @@ func applyConfiguration(configPath string, cs ConfigurationSet, provider string)
 		return fmt.Errorf("creating app config store: %w", err)
 	}
 
-	cfg, err := appConfig.Get()
+	rawCfg, err := appConfig.Get()
 	if err != nil {
 		return fmt.Errorf("getting app config: %w", err)
 	}
+	cfg, err := Interpolate(rawCfg)
+	if err != nil {
+		return fmt.Errorf("interpolating app config: %w", err)
+	}
 
 	for _, item := range cs.GetAll() {
-		if item.HasValue() {
+		// A value that came from a flags default can be overridden by the app config
+		if item.HasValue() && item.Source != ItemSourceDefault {
 			continue
 		}
 
@@ -53,9 +60,11 @@ func applyConfiguration(configPath string, cs ConfigurationSet, provider string)
 		if hasProvider {
 			providerVal, hasProviderVal := providerValues[item.Name]
 			if hasProviderVal {
-				if err := setItemValue(item, providerVal); err != nil {
+				if err := SetItemValue(item, providerVal); err != nil {
 					return fmt.Errorf("setting item value for %s from provider config: %w", item.Name, err)
 				}
+				item.Source = ItemSourceProviderConfig
+				markSecretReference(item, rawCfg.Spec.Providers[provider][item.Name])
 				continue
 			}
 		}
@@ -63,9 +72,11 @@ func applyConfiguration(configPath string, cs ConfigurationSet, provider string)
 		// apply global value if we have one
 		globalVal, hasGlobalVal := cfg.Spec.Global[item.Name]
 		if hasGlobalVal {
-			if err := setItemValue(item, globalVal); err != nil {
+			if err := SetItemValue(item, globalVal); err != nil {
 				return fmt.Errorf("setting item value for %s from global config: %w", item.Name, err)
 			}
+			item.Source = ItemSourceGlobalConfig
+			markSecretReference(item, rawCfg.Spec.Global[item.Name])
 			continue
 		}
 	}
@@ -73,26 +84,89 @@ func applyConfiguration(configPath string, cs ConfigurationSet, provider string)
 	return nil
 }
 
-func setItemValue(item *Item, value string) error {
+// markSecretReference will mark the item as sensitive if its raw value references a
+// secret, so that the resolved value isn't saved in the history or shown
+func markSecretReference(item *Item, rawValue string) {
+	if HasSecretReference(rawValue) {
+		item.Sensitive = true
+	}
+}
+
+// SetItemValue will parse the supplied string value based on the type of the
+// item and set it as the items value.
+func SetItemValue(item *Item, value string) error {
+	parsed, err := ParseItemValue(item, value)
+	if err != nil {
+		return err
+	}
+	item.Value = parsed
+
+	return nil
+}
+
+// ParseItemValue will parse a string value into the value type of the item. Lists
+// are comma separated and maps are comma separated key=value pairs.
+func ParseItemValue(item *Item, value string) (interface{}, error) {
 	switch item.Type {
 	case ItemTypeString:
-		item.Value = value
-		return nil
+		return value, nil
 	case ItemTypeInt:
 		intVal, err := strconv.ParseInt(value, 10, 32)
 		if err != nil {
-			return fmt.Errorf("parsing config as int: %w", err)
+			return nil, fmt.Errorf("parsing config as int: %w", err)
 		}
-		item.Value = intVal
-		return nil
+		return int(intVal), nil
 	case ItemTypeBool:
 		boolVal, err := strconv.ParseBool(value)
 		if err != nil {
-			return fmt.Errorf("parsing config as bool: %w", err)
+			return nil, fmt.Errorf("parsing config as bool: %w", err)
+		}
+		return boolVal, nil
+	case ItemTypeDuration:
+		durationVal, err := time.ParseDuration(value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing config as duration: %w", err)
 		}
-		item.Value = boolVal
-		return nil
+		return durationVal, nil
+	case ItemTypeStringSlice:
+		return parseStringSlice(value), nil
+	case ItemTypeEnum:
+		if value != "" && !contains(item.AllowedValues, value) {
+			return nil, fmt.Errorf("value %s must be one of %s: %w", value, strings.Join(item.AllowedValues, ", "), ErrValueNotAllowed)
+		}
+		return value, nil
+	case ItemTypeStringMap:
+		mapVal, err := parseStringMap(value)
+		if err != nil {
+			return nil, fmt.Errorf("parsing config as map: %w", err)
+		}
+		return mapVal, nil
 	default:
-		return ErrUnknownItemType
+		return nil, ErrUnknownItemType
+	}
+}
+
+func parseStringSlice(value string) []string {
+	sliceVal := []string{}
+	for _, part := range strings.Split(value, ",") {
+		part = strings.TrimSpace(part)
+		if part != "" {
+			sliceVal = append(sliceVal, part)
+		}
 	}
+
+	return sliceVal
+}
+
+func parseStringMap(value string) (map[string]string, error) {
+	mapVal := map[string]string{}
+	for _, pair := range parseStringSlice(value) {
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || strings.TrimSpace(parts[0]) == "" {
+			return nil, fmt.Errorf("expected key=value but got %s: %w", pair, ErrInvalidMapValue)
+		}
+		mapVal[strings.TrimSpace(parts[0])] = strings.TrimSpace(parts[1])
+	}
+
+	return mapVal, nil
 }
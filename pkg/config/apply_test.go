@@ -0,0 +1,95 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/config"
+)
+
+const testAppConfig = `apiVersion: kconnect.fidelity.github.com/v1alpha1
+kind: Configuration
+spec:
+  global:
+    region: eu-west-1
+    admin: "true"
+  providers:
+    aks:
+      admin: "false"
+`
+
+func TestApplyToConfigSetWithProvider(t *testing.T) {
+	testCases := []struct {
+		name         string
+		provider     string
+		regionSource config.ItemSource
+		expectRegion string
+		expectAdmin  bool
+	}{
+		{
+			name:         "global values override defaults",
+			provider:     "eks",
+			regionSource: config.ItemSourceDefault,
+			expectRegion: "eu-west-1",
+			expectAdmin:  true,
+		},
+		{
+			name:         "provider values override global values",
+			provider:     "aks",
+			regionSource: config.ItemSourceDefault,
+			expectRegion: "eu-west-1",
+			expectAdmin:  false,
+		},
+		{
+			name:         "flag values aren't overridden",
+			provider:     "aks",
+			regionSource: config.ItemSourceFlag,
+			expectRegion: "us-east-1",
+			expectAdmin:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			configPath := filepath.Join(t.TempDir(), "config.yaml")
+			g.Expect(ioutil.WriteFile(configPath, []byte(testAppConfig), 0600)).To(Succeed())
+
+			cs := config.NewConfigurationSet()
+			region, err := cs.String("region", "us-east-1", "")
+			g.Expect(err).NotTo(HaveOccurred())
+			admin, err := cs.Bool("admin", false, "")
+			g.Expect(err).NotTo(HaveOccurred())
+
+			// Simulate the values being populated from the flags
+			region.Value = "us-east-1"
+			region.Source = tc.regionSource //nolint:scopelint
+			admin.Value = false
+			admin.Source = config.ItemSourceDefault
+
+			g.Expect(config.ApplyToConfigSetWithProvider(configPath, cs, tc.provider)).To(Succeed()) //nolint:scopelint
+			g.Expect(cs.ValueString("region")).To(Equal(tc.expectRegion))                            //nolint:scopelint
+			g.Expect(admin.Value).To(Equal(tc.expectAdmin))                                          //nolint:scopelint
+		})
+	}
+}
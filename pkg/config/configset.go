@@ -19,9 +19,14 @@ package config
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"strings"
+	"time"
 )
 
+// MaskedValue is shown instead of the value of a sensitive item
+const MaskedValue = "********"
+
 var (
 	ErrConfigExistsAlready = errors.New("config item with same name already exists in set")
 	ErrConfigNotFound      = errors.New("configuration item not found")
@@ -43,8 +48,20 @@ type Item struct {
 	Deprecated        bool
 	DeprecatedMessage string
 	HistoryIgnore     bool
+	AllowedValues     []string
+	Source            ItemSource
+	// RequiredWhen are the conditions when the item is required, it's required if
+	// any of them are met
+	RequiredWhen []Condition
+	// ValuesResolver is optional and returns the values the item can have from live
+	// data, so that the user can choose one instead of entering it
+	ValuesResolver ValuesResolver
 }
 
+// ValuesResolver returns the values that an item can have, e.g. the regions that are
+// enabled for an account, keyed by the name to show for them
+type ValuesResolver func() (map[string]string, error)
+
 func (i *Item) HasValue() bool {
 	if i == nil {
 		return false
@@ -54,19 +71,77 @@ func (i *Item) HasValue() bool {
 		return false
 	}
 
-	if i.Type == ItemTypeString {
+	switch i.Type {
+	case ItemTypeString, ItemTypeEnum:
 		return i.Value.(string) != ""
+	case ItemTypeStringSlice:
+		return len(i.Value.([]string)) > 0
+	case ItemTypeStringMap:
+		return len(i.Value.(map[string]string)) > 0
 	}
 
 	return true
 }
 
+// ValueString returns the value of the item formatted as a string. Lists are
+// comma separated and maps are comma separated key=value pairs.
+func (i *Item) ValueString() string {
+	if i == nil || i.Value == nil {
+		return ""
+	}
+
+	switch i.Type {
+	case ItemTypeString, ItemTypeEnum:
+		return i.Value.(string)
+	case ItemTypeInt:
+		intVal := i.Value.(int)
+		return fmt.Sprintf("%d", intVal)
+	case ItemTypeBool:
+		boolVal := i.Value.(bool)
+		return fmt.Sprintf("%t", boolVal)
+	case ItemTypeDuration:
+		durationVal := i.Value.(time.Duration)
+		return durationVal.String()
+	case ItemTypeStringSlice:
+		sliceVal := i.Value.([]string)
+		return strings.Join(sliceVal, ",")
+	case ItemTypeStringMap:
+		mapVal := i.Value.(map[string]string)
+		pairs := []string{}
+		for k, v := range mapVal {
+			pairs = append(pairs, fmt.Sprintf("%s=%s", k, v))
+		}
+		sort.Strings(pairs)
+		return strings.Join(pairs, ",")
+	default:
+		return ""
+	}
+}
+
+// DisplayValue returns the value of the item formatted as a string to show to the
+// user or write to the logs. The value of a sensitive item is masked.
+func (i *Item) DisplayValue() string {
+	if i.Sensitive && i.HasValue() {
+		return MaskedValue
+	}
+
+	return i.ValueString()
+}
+
 type ItemType string
 
 var (
 	ItemTypeString = ItemType("string")
 	ItemTypeInt    = ItemType("int")
 	ItemTypeBool   = ItemType("bool")
+	// ItemTypeDuration is an item with a time.Duration value
+	ItemTypeDuration = ItemType("duration")
+	// ItemTypeStringSlice is an item with a []string value
+	ItemTypeStringSlice = ItemType("stringSlice")
+	// ItemTypeEnum is an item with a string value that must be one of its AllowedValues
+	ItemTypeEnum = ItemType("enum")
+	// ItemTypeStringMap is an item with a map[string]string value
+	ItemTypeStringMap = ItemType("stringMap")
 )
 
 type ConfigurationSet interface {
@@ -84,11 +159,21 @@ type ConfigurationSet interface {
 	SetHidden(name string) error
 	SetDeprecated(name string, message string) error
 	SetValue(name string, value interface{}) error
+	SetSource(name string, source ItemSource) error
 	SetShort(name string, shorthand string) error
+	SetAllowedValues(name string, values []string) error
+	SetRequiredWhen(name string, when Condition) error
+	SetMutuallyExclusive(names ...string) error
+	SetValuesResolver(name string, resolver ValuesResolver) error
+	MutuallyExclusive() [][]string
 
 	String(name string, defaultValue string, description string) (*Item, error)
 	Int(name string, defaultValue int, description string) (*Item, error)
 	Bool(name string, defaultValue bool, description string) (*Item, error)
+	Duration(name string, defaultValue time.Duration, description string) (*Item, error)
+	StringSlice(name string, defaultValue []string, description string) (*Item, error)
+	Enum(name string, defaultValue string, allowedValues []string, description string) (*Item, error)
+	StringMap(name string, defaultValue map[string]string, description string) (*Item, error)
 }
 
 func NewConfigurationSet() ConfigurationSet {
@@ -97,8 +182,25 @@ func NewConfigurationSet() ConfigurationSet {
 	}
 }
 
+// Copy returns a copy of the set. Changing the values of the items in the copy doesn't
+// change the items in the set.
+func Copy(set ConfigurationSet) ConfigurationSet {
+	copied := &configSet{
+		config: make(map[string]*Item),
+	}
+	for _, item := range set.GetAll() {
+		itemCopy := *item
+		copied.config[item.Name] = &itemCopy
+	}
+	copied.exclusive = append(copied.exclusive, set.MutuallyExclusive()...)
+
+	return copied
+}
+
 type configSet struct {
 	config map[string]*Item
+	// exclusive are the groups of items where only one of each group can have a value
+	exclusive [][]string
 }
 
 func (s *configSet) Exists(name string) bool {
@@ -115,6 +217,10 @@ func (s *configSet) ExistsWithValue(name string) bool {
 		return false
 	}
 
+	if item.Type != ItemTypeString {
+		return true
+	}
+
 	val := item.Value.(string)
 	return !strings.HasPrefix(val, ListPrefix)
 }
@@ -125,7 +231,7 @@ func (s *configSet) ValueIsList(name string) bool {
 		return false
 	}
 
-	if !item.HasValue() {
+	if !item.HasValue() || item.Type != ItemTypeString {
 		return false
 	}
 
@@ -139,18 +245,7 @@ func (s *configSet) ValueString(name string) string {
 		return ""
 	}
 
-	switch item.Type {
-	case ItemTypeString:
-		return item.Value.(string)
-	case ItemTypeInt:
-		intVal := item.Value.(int)
-		return fmt.Sprintf("%d", intVal)
-	case ItemTypeBool:
-		boolVal := item.Value.(bool)
-		return fmt.Sprintf("%t", boolVal)
-	default:
-		return ""
-	}
+	return item.ValueString()
 }
 
 func (s *configSet) Get(name string) *Item {
@@ -188,6 +283,9 @@ func (s *configSet) AddSet(setToAdd ConfigurationSet) error {
 			}
 		}
 	}
+	for _, group := range setToAdd.MutuallyExclusive() {
+		s.addExclusive(group)
+	}
 
 	return nil
 }
@@ -203,6 +301,19 @@ func (s *configSet) SetSensitive(name string) error {
 	return nil
 }
 
+// SetValuesResolver sets the resolver used to get the values the item can have when
+// asking the user for its value
+func (s *configSet) SetValuesResolver(name string, resolver ValuesResolver) error {
+	item := s.Get(name)
+	if item == nil {
+		return ErrConfigNotFound
+	}
+
+	item.ValuesResolver = resolver
+
+	return nil
+}
+
 func (s *configSet) SetHistoryIgnore(name string) error {
 	item := s.Get(name)
 	if item == nil {
@@ -255,6 +366,18 @@ func (s *configSet) SetValue(name string, value interface{}) error {
 	}
 
 	item.Value = value
+	item.Source = ItemSourceResolved
+
+	return nil
+}
+
+func (s *configSet) SetSource(name string, source ItemSource) error {
+	item := s.Get(name)
+	if item == nil {
+		return ErrConfigNotFound
+	}
+
+	item.Source = source
 
 	return nil
 }
@@ -270,6 +393,17 @@ func (s *configSet) SetShort(name string, shorthand string) error {
 	return nil
 }
 
+func (s *configSet) SetAllowedValues(name string, values []string) error {
+	item := s.Get(name)
+	if item == nil {
+		return ErrConfigNotFound
+	}
+
+	item.AllowedValues = values
+
+	return nil
+}
+
 func (s *configSet) String(name string, defaultValue string, description string) (*Item, error) {
 	item := &Item{
 		Name:         name,
@@ -314,3 +448,64 @@ func (s *configSet) Bool(name string, defaultValue bool, description string) (*I
 
 	return item, nil
 }
+
+func (s *configSet) Duration(name string, defaultValue time.Duration, description string) (*Item, error) {
+	item := &Item{
+		Name:         name,
+		Type:         ItemTypeDuration,
+		DefaultValue: defaultValue,
+		Description:  description,
+	}
+
+	if err := s.Add(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *configSet) StringSlice(name string, defaultValue []string, description string) (*Item, error) {
+	item := &Item{
+		Name:         name,
+		Type:         ItemTypeStringSlice,
+		DefaultValue: defaultValue,
+		Description:  description,
+	}
+
+	if err := s.Add(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *configSet) Enum(name string, defaultValue string, allowedValues []string, description string) (*Item, error) {
+	item := &Item{
+		Name:          name,
+		Type:          ItemTypeEnum,
+		DefaultValue:  defaultValue,
+		Description:   description,
+		AllowedValues: allowedValues,
+	}
+
+	if err := s.Add(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
+
+func (s *configSet) StringMap(name string, defaultValue map[string]string, description string) (*Item, error) {
+	item := &Item{
+		Name:         name,
+		Type:         ItemTypeStringMap,
+		DefaultValue: defaultValue,
+		Description:  description,
+	}
+
+	if err := s.Add(item); err != nil {
+		return nil, err
+	}
+
+	return item, nil
+}
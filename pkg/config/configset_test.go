@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/config"
+)
+
+func TestCopy(t *testing.T) {
+	g := NewWithT(t)
+
+	cs := config.NewConfigurationSet()
+	cs.String("role-arn", "", "The role")      //nolint: errcheck
+	cs.String("region", "eu-west-2", "Region") //nolint: errcheck
+	cs.SetSensitive("role-arn")                //nolint: errcheck
+	g.Expect(cs.SetValue("role-arn", "arn:aws:iam::000000000000:role/dev")).To(Succeed())
+
+	copied := config.Copy(cs)
+	g.Expect(copied.SetValue("role-arn", "arn:aws:iam::000000000000:role/prod")).To(Succeed())
+
+	g.Expect(cs.ValueString("role-arn")).To(Equal("arn:aws:iam::000000000000:role/dev"))
+	g.Expect(copied.ValueString("role-arn")).To(Equal("arn:aws:iam::000000000000:role/prod"))
+	g.Expect(copied.Get("region").DefaultValue).To(Equal("eu-west-2"))
+	g.Expect(copied.Get("role-arn").Sensitive).To(BeTrue())
+}
+
+func TestDisplayValue(t *testing.T) {
+	g := NewWithT(t)
+
+	cs := config.NewConfigurationSet()
+	_, err := cs.String("username", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cs.SetValue("username", "bob")).To(Succeed())
+	_, err = cs.String("password", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cs.SetSensitive("password")).To(Succeed())
+
+	g.Expect(cs.Get("username").DisplayValue()).To(Equal("bob"))
+	g.Expect(cs.Get("password").DisplayValue()).To(Equal(""))
+
+	g.Expect(cs.SetValue("password", "s3cr3t")).To(Succeed())
+	g.Expect(cs.Get("password").DisplayValue()).To(Equal(config.MaskedValue))
+}
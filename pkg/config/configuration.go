@@ -22,6 +22,9 @@ import (
 	"io/ioutil"
 	"os"
 	"path/filepath"
+	"sync"
+
+	"go.uber.org/zap"
 
 	"k8s.io/apimachinery/pkg/runtime"
 	"sigs.k8s.io/yaml"
@@ -35,6 +38,10 @@ const (
 	ListPrefix = "$"
 )
 
+// reportedUnknown holds the unknown keys that have been logged, as the app
+// configuration is loaded more than once by a command
+var reportedUnknown sync.Map
+
 type AppConfiguration interface {
 	Get() (*kconnectv1alpha.Configuration, error)
 	Save(configuration *kconnectv1alpha.Configuration) error
@@ -48,8 +55,7 @@ func NewAppConfiguration() (AppConfiguration, error) {
 }
 
 func NewAppConfigurationWithPath(path string) (AppConfiguration, error) {
-
-	configPath, err := filepath.Abs(path)
+	configPath, err := filepath.Abs(defaults.ExpandPath(path))
 	if err != nil {
 		return nil, fmt.Errorf("getting config absolute file path for %s: %w", path, err)
 	}
@@ -91,10 +97,14 @@ func GetValue(name string, provider string) (string, error) {
 		return "", fmt.Errorf("creating application configuration: %w", err)
 	}
 
-	cfg, err := appCfg.Get()
+	rawCfg, err := appCfg.Get()
 	if err != nil {
 		return "", fmt.Errorf("getting application configuration: %w", err)
 	}
+	cfg, err := Interpolate(rawCfg)
+	if err != nil {
+		return "", fmt.Errorf("interpolating application configuration: %w", err)
+	}
 
 	if provider != "" {
 		providerValues, hasProvider := cfg.Spec.Providers[provider]
@@ -128,6 +138,10 @@ func (a *appConfiguration) Get() (*kconnectv1alpha.Configuration, error) {
 		return kconnectv1alpha.NewConfiguration(), nil
 	}
 
+	if err := validateOnLoad(a.path, data); err != nil {
+		return nil, err
+	}
+
 	_, apiCodecs, err := kconnectv1alpha.NewSchemeAndCodecs()
 	if err != nil {
 		return nil, fmt.Errorf("getting kconnect codec: %w", err)
@@ -165,6 +179,10 @@ func (a *appConfiguration) Parse(reader io.Reader) (*kconnectv1alpha.Configurati
 		return nil, fmt.Errorf("reading all from reader: %w", err)
 	}
 
+	if err := validateOnLoad("", data); err != nil {
+		return nil, err
+	}
+
 	_, apiCodecs, err := kconnectv1alpha.NewSchemeAndCodecs()
 	if err != nil {
 		return nil, fmt.Errorf("getting kconnect codec: %w", err)
@@ -177,3 +195,33 @@ func (a *appConfiguration) Parse(reader io.Reader) (*kconnectv1alpha.Configurati
 
 	return appConfiguration, nil
 }
+
+// validateOnLoad validates the app configuration when it's loaded. Only problems with
+// the known keys fail loading, unknown keys are logged as warnings.
+func validateOnLoad(path string, data []byte) error {
+	schema, err := getSchema()
+	if err != nil {
+		return fmt.Errorf("getting configuration schema: %w", err)
+	}
+
+	validationErrs, err := Validate(path, data, schema)
+	if err != nil {
+		return err
+	}
+	// Unknown keys are only warnings when loading, so that a configuration with a provider
+	// for a plugin that isn't installed, or from a newer version, can still be used.
+	// They're reported as problems by config validate.
+	for _, validationErr := range validationErrs {
+		if !validationErr.Unknown {
+			continue
+		}
+		if _, reported := reportedUnknown.LoadOrStore(validationErr.Error(), true); !reported {
+			zap.S().Warnw("ignoring unknown key in configuration", "problem", validationErr.Error())
+		}
+	}
+	if known := validationErrs.Known(); len(known) > 0 {
+		return known
+	}
+
+	return nil
+}
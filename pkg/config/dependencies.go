@@ -0,0 +1,226 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
+
+// Condition is a condition on the value of another item
+type Condition struct {
+	// Name is the name of the other item
+	Name string
+	// Value is the value the other item must have, any value meets the
+	// condition if it's empty
+	Value string
+}
+
+func (c Condition) met(cs ConfigurationSet) bool {
+	item := cs.Get(c.Name)
+	if !item.HasValue() {
+		return false
+	}
+
+	return c.Value == "" || item.ValueString() == c.Value
+}
+
+func (c Condition) String() string {
+	if c.Value == "" {
+		return fmt.Sprintf("%s is set", c.Name)
+	}
+
+	return fmt.Sprintf("%s is %s", c.Name, c.Value)
+}
+
+// SetRequiredWhen makes the item required when the condition is met, e.g. a jump host
+// is required when the private access is an ssh tunnel
+func (s *configSet) SetRequiredWhen(name string, when Condition) error {
+	item := s.Get(name)
+	if item == nil {
+		return ErrConfigNotFound
+	}
+	if !s.Exists(when.Name) {
+		return fmt.Errorf("condition on %s: %w", when.Name, ErrConfigNotFound)
+	}
+
+	item.RequiredWhen = append(item.RequiredWhen, when)
+
+	return nil
+}
+
+// SetMutuallyExclusive makes it invalid for more than one of the items to have a
+// value, e.g. a subscription can be specified by either its id or name
+func (s *configSet) SetMutuallyExclusive(names ...string) error {
+	for _, name := range names {
+		if !s.Exists(name) {
+			return fmt.Errorf("getting %s: %w", name, ErrConfigNotFound)
+		}
+	}
+
+	s.addExclusive(names)
+
+	return nil
+}
+
+func (s *configSet) addExclusive(names []string) {
+	for _, group := range s.exclusive {
+		if strings.Join(group, ",") == strings.Join(names, ",") {
+			return
+		}
+	}
+	s.exclusive = append(s.exclusive, names)
+}
+
+// MutuallyExclusive returns the groups of items where only one of each group can have a value
+func (s *configSet) MutuallyExclusive() [][]string {
+	return s.exclusive
+}
+
+// IsRequired returns true if the item is required, or one of the conditions when it's
+// required is met. The item isn't required if another item that's mutually exclusive
+// with it has a value.
+func IsRequired(cs ConfigurationSet, item *Item) bool {
+	for _, group := range cs.MutuallyExclusive() {
+		if !contains(group, item.Name) {
+			continue
+		}
+		for _, name := range group {
+			if name != item.Name && cs.ExistsWithValue(name) {
+				return false
+			}
+		}
+	}
+
+	return item.Required || requiredCondition(cs, item) != nil
+}
+
+// requiredCondition returns the first condition when the item is required that is met
+func requiredCondition(cs ConfigurationSet, item *Item) *Condition {
+	for i := range item.RequiredWhen {
+		if item.RequiredWhen[i].met(cs) {
+			return &item.RequiredWhen[i]
+		}
+	}
+
+	return nil
+}
+
+// ValidateRequired will check that the required items, including the items that are
+// required because of the value of another item, have values
+func ValidateRequired(cs ConfigurationSet) error {
+	return validateRequired(cs, true)
+}
+
+// ValidateRequiredWhen will check that the items that are required because of the value
+// of another item have values
+func ValidateRequiredWhen(cs ConfigurationSet) error {
+	return validateRequired(cs, false)
+}
+
+func validateRequired(cs ConfigurationSet, includeRequired bool) error {
+	errsValidation := &kerrors.ValidationFailed{}
+
+	for _, item := range sortedItems(cs) {
+		if cs.ExistsWithValue(item.Name) || !IsRequired(cs, item) {
+			continue
+		}
+		if when := requiredCondition(cs, item); when != nil {
+			errsValidation.AddFailure(fmt.Sprintf("%s is required when %s", item.Name, when))
+		} else if includeRequired {
+			errsValidation.AddFailure(fmt.Sprintf("%s is required", item.Name))
+		}
+	}
+
+	if len(errsValidation.Failures()) > 0 {
+		return errsValidation
+	}
+
+	return nil
+}
+
+// ValidateExclusive will check that only one of each mutually exclusive group of items
+// has a value. This should be checked before resolving the values, as resolving can set
+// an item from another in the group, e.g. the subscription id from its name.
+func ValidateExclusive(cs ConfigurationSet) error {
+	errsValidation := &kerrors.ValidationFailed{}
+
+	for _, group := range cs.MutuallyExclusive() {
+		withValue := 0
+		for _, name := range group {
+			if cs.ExistsWithValue(name) {
+				withValue++
+			}
+		}
+		if withValue > 1 {
+			errsValidation.AddFailure(fmt.Sprintf("only one of %s can be set", joinNames(group)))
+		}
+	}
+
+	if len(errsValidation.Failures()) > 0 {
+		return errsValidation
+	}
+
+	return nil
+}
+
+// ResolutionOrder returns the items in the order their values should be resolved, e.g.
+// by asking the user. An item is after the items its required conditions depend on, so
+// that the conditions are known before deciding to ask for it. Otherwise the items are
+// in name order.
+func ResolutionOrder(cs ConfigurationSet) []*Item {
+	ordered := []*Item{}
+	visited := map[string]bool{}
+
+	var visit func(item *Item)
+	visit = func(item *Item) {
+		if item == nil || visited[item.Name] {
+			return
+		}
+		visited[item.Name] = true
+		for _, when := range item.RequiredWhen {
+			visit(cs.Get(when.Name))
+		}
+		ordered = append(ordered, item)
+	}
+	for _, item := range sortedItems(cs) {
+		visit(item)
+	}
+
+	return ordered
+}
+
+func sortedItems(cs ConfigurationSet) []*Item {
+	items := cs.GetAll()
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+
+	return items
+}
+
+// joinNames joins the names as a list, e.g. a, b and c
+func joinNames(names []string) string {
+	if len(names) < 2 {
+		return strings.Join(names, "")
+	}
+
+	return fmt.Sprintf("%s and %s", strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
+
+func dependenciesConfig(g *WithT) config.ConfigurationSet {
+	cs := config.NewConfigurationSet()
+	cs.String("subscription-id", "", "The subscription id")     //nolint: errcheck
+	cs.String("subscription-name", "", "The subscription name") //nolint: errcheck
+	cs.String("private-access", "", "The private access")       //nolint: errcheck
+	cs.String("jump-host", "", "The jump host")                 //nolint: errcheck
+
+	g.Expect(cs.SetMutuallyExclusive("subscription-id", "subscription-name")).To(Succeed())
+	g.Expect(cs.SetRequiredWhen("jump-host", config.Condition{Name: "private-access", Value: "ssh-tunnel"})).To(Succeed())
+
+	return cs
+}
+
+func TestValidateRequiredWhen(t *testing.T) {
+	testCases := []struct {
+		name          string
+		privateAccess string
+		jumpHost      string
+		expectedErr   string
+	}{
+		{name: "condition not met", privateAccess: ""},
+		{name: "condition met for other value", privateAccess: "bastion"},
+		{name: "condition met with value", privateAccess: "ssh-tunnel", jumpHost: "bastion-host"},
+		{name: "condition met without value", privateAccess: "ssh-tunnel", expectedErr: "jump-host is required when private-access is ssh-tunnel"},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			cs := dependenciesConfig(g)
+			if tc.privateAccess != "" {
+				g.Expect(cs.SetValue("private-access", tc.privateAccess)).To(Succeed())
+			}
+			if tc.jumpHost != "" {
+				g.Expect(cs.SetValue("jump-host", tc.jumpHost)).To(Succeed())
+			}
+
+			err := config.ValidateRequiredWhen(cs)
+			if tc.expectedErr == "" {
+				g.Expect(err).NotTo(HaveOccurred())
+				return
+			}
+
+			var validationErr *kerrors.ValidationFailed
+			g.Expect(errors.As(err, &validationErr)).To(BeTrue())
+			g.Expect(validationErr.Failures()).To(ConsistOf(tc.expectedErr))
+		})
+	}
+}
+
+func TestSetRequiredWhenUnknownItem(t *testing.T) {
+	g := NewWithT(t)
+	cs := dependenciesConfig(g)
+
+	g.Expect(cs.SetRequiredWhen("unknown", config.Condition{Name: "private-access"})).To(MatchError(config.ErrConfigNotFound))
+	g.Expect(cs.SetRequiredWhen("jump-host", config.Condition{Name: "unknown"})).To(MatchError(config.ErrConfigNotFound))
+	g.Expect(cs.SetMutuallyExclusive("jump-host", "unknown")).To(MatchError(config.ErrConfigNotFound))
+}
+
+func TestValidateExclusive(t *testing.T) {
+	g := NewWithT(t)
+	cs := dependenciesConfig(g)
+	g.Expect(cs.SetValue("subscription-id", "0000")).To(Succeed())
+	g.Expect(config.ValidateExclusive(cs)).To(Succeed())
+
+	g.Expect(cs.SetValue("subscription-name", "dev")).To(Succeed())
+	err := config.ValidateExclusive(cs)
+
+	var validationErr *kerrors.ValidationFailed
+	g.Expect(errors.As(err, &validationErr)).To(BeTrue())
+	g.Expect(validationErr.Failures()).To(ConsistOf("only one of subscription-id and subscription-name can be set"))
+}
+
+func TestIsRequiredExclusive(t *testing.T) {
+	g := NewWithT(t)
+	cs := dependenciesConfig(g)
+	cs.Get("subscription-id").Required = true
+
+	g.Expect(config.IsRequired(cs, cs.Get("subscription-id"))).To(BeTrue())
+
+	g.Expect(cs.SetValue("subscription-name", "dev")).To(Succeed())
+	g.Expect(config.IsRequired(cs, cs.Get("subscription-id"))).To(BeFalse())
+	g.Expect(config.ValidateRequired(cs)).To(Succeed())
+}
+
+func TestResolutionOrder(t *testing.T) {
+	g := NewWithT(t)
+	cs := config.NewConfigurationSet()
+	cs.String("a-target", "", "The target") //nolint: errcheck
+	cs.String("b-name", "", "The name")     //nolint: errcheck
+	cs.String("z-access", "", "The access") //nolint: errcheck
+	g.Expect(cs.SetRequiredWhen("a-target", config.Condition{Name: "z-access"})).To(Succeed())
+
+	names := []string{}
+	for _, item := range config.ResolutionOrder(cs) {
+		names = append(names, item.Name)
+	}
+
+	g.Expect(names).To(Equal([]string{"z-access", "a-target", "b-name"}))
+}
+
+func TestDependenciesCopied(t *testing.T) {
+	g := NewWithT(t)
+	cs := dependenciesConfig(g)
+
+	copied := config.Copy(cs)
+	g.Expect(copied.MutuallyExclusive()).To(Equal([][]string{{"subscription-id", "subscription-name"}}))
+	g.Expect(copied.Get("jump-host").RequiredWhen).To(HaveLen(1))
+
+	merged := config.NewConfigurationSet()
+	g.Expect(merged.AddSet(cs)).To(Succeed())
+	g.Expect(merged.AddSet(cs)).To(Succeed())
+	g.Expect(merged.MutuallyExclusive()).To(HaveLen(1))
+}
@@ -19,5 +19,7 @@ package config
 import "errors"
 
 var (
-	ErrListNotFound = errors.New("list not found")
+	ErrListNotFound    = errors.New("list not found")
+	ErrValueNotAllowed = errors.New("value is not allowed")
+	ErrInvalidMapValue = errors.New("invalid map value")
 )
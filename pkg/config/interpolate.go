@@ -0,0 +1,291 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/user"
+	"runtime"
+	"strings"
+
+	kconnectv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/logging"
+	"github.com/fidelity/kconnect/pkg/secrets"
+)
+
+const (
+	// VariablePrefix is the prefix for a variable reference in a configuration value
+	VariablePrefix = "${"
+	// VariableSuffix is the suffix for a variable reference in a configuration value
+	VariableSuffix = "}"
+	// SecretPrefix is the prefix of a variable name that references a value in the secret store
+	SecretPrefix = "secret:"
+
+	escapedVariablePrefix = "$${"
+	defaultSeparator      = ":-"
+)
+
+var (
+	ErrVariableNotDefined   = errors.New("variable is not defined")
+	ErrVariableCycle        = errors.New("variable references itself")
+	ErrUnterminatedVariable = errors.New("variable reference is not terminated")
+	ErrEmptyVariableName    = errors.New("variable reference has no name")
+	ErrSecretNotFound       = errors.New("secret not found in the secret store")
+)
+
+// BuiltInVariables returns the variables that are always available for use in
+// configuration values
+func BuiltInVariables() map[string]string {
+	vars := map[string]string{
+		"os": runtime.GOOS,
+	}
+
+	if currentUser, err := user.Current(); err == nil {
+		username := currentUser.Username
+		// On Windows the username is in the form DOMAIN\user
+		if idx := strings.LastIndex(username, "\\"); idx != -1 {
+			username = username[idx+1:]
+		}
+		vars["username"] = username
+	}
+	if home, err := os.UserHomeDir(); err == nil {
+		vars["home"] = home
+	}
+	if hostname, err := os.Hostname(); err == nil {
+		vars["hostname"] = hostname
+	}
+
+	return vars
+}
+
+// Interpolate will return a copy of the configuration with the variable references in
+// the global, provider and list values resolved. A reference is in the form ${name} and
+// is resolved using the user defined variables, then the built-in variables and then
+// the environment variables, so a user defined variable can override a built-in. A
+// default value can be supplied using ${name:-default} and a literal ${ can be
+// specified using $${. A value in the secret store is referenced using ${secret:key}.
+func Interpolate(cfg *kconnectv1alpha.Configuration) (*kconnectv1alpha.Configuration, error) {
+	resolved := cfg.DeepCopy()
+	i := newInterpolator(cfg.Spec.Variables)
+	i.lookupSecret = secretLookup(cfg.Spec.SecretStore)
+
+	for name, value := range resolved.Spec.Global {
+		interpolated, err := i.interpolate(value)
+		if err != nil {
+			return nil, fmt.Errorf("interpolating global value %s: %w", name, err)
+		}
+		resolved.Spec.Global[name] = interpolated
+	}
+
+	for providerName, providerValues := range resolved.Spec.Providers {
+		for name, value := range providerValues {
+			interpolated, err := i.interpolate(value)
+			if err != nil {
+				return nil, fmt.Errorf("interpolating %s provider value %s: %w", providerName, name, err)
+			}
+			providerValues[name] = interpolated
+		}
+	}
+
+	for listName, list := range resolved.Spec.Lists {
+		for idx := range list {
+			interpolated, err := i.interpolate(list[idx].Value)
+			if err != nil {
+				return nil, fmt.Errorf("interpolating list %s value %s: %w", listName, list[idx].Name, err)
+			}
+			list[idx].Value = interpolated
+		}
+	}
+
+	return resolved, nil
+}
+
+// InterpolateValue will resolve the variable references in a single value. See Interpolate
+// for details of how the references are resolved.
+func InterpolateValue(value string, variables map[string]string) (string, error) {
+	return newInterpolator(variables).interpolate(value)
+}
+
+// HasVariables returns true if the value contains a variable reference
+func HasVariables(value string) bool {
+	return strings.Contains(strings.ReplaceAll(value, escapedVariablePrefix, ""), VariablePrefix)
+}
+
+// HasSecretReference returns true if the value references a value in the secret store
+func HasSecretReference(value string) bool {
+	remaining := strings.ReplaceAll(value, escapedVariablePrefix, "")
+	for {
+		start := strings.Index(remaining, VariablePrefix)
+		if start == -1 {
+			return false
+		}
+		remaining = remaining[start+len(VariablePrefix):]
+		if strings.HasPrefix(strings.TrimSpace(remaining), SecretPrefix) {
+			return true
+		}
+	}
+}
+
+// secretLookup returns a function that gets secrets from the configured secret store. The
+// store is only created if a secret is referenced.
+func secretLookup(storeCfg *kconnectv1alpha.SecretStore) func(string) (string, bool, error) {
+	var store secrets.Store
+	return func(key string) (string, bool, error) {
+		if store == nil {
+			created, err := secrets.New(storeCfg)
+			if err != nil {
+				return "", false, fmt.Errorf("creating secret store: %w", err)
+			}
+			store = created
+		}
+
+		return store.Get(key)
+	}
+}
+
+// checkVariableSyntax will check that the variable references in a value are well
+// formed without resolving them
+func checkVariableSyntax(value string) error {
+	i := &interpolator{
+		builtIns:     map[string]string{},
+		lookupEnv:    func(string) (string, bool) { return "", true },
+		lookupSecret: func(string) (string, bool, error) { return "", true, nil },
+		resolving:    map[string]bool{},
+	}
+	_, err := i.interpolate(value)
+
+	return err
+}
+
+func newInterpolator(variables map[string]string) *interpolator {
+	return &interpolator{
+		variables:    variables,
+		builtIns:     BuiltInVariables(),
+		lookupEnv:    os.LookupEnv,
+		lookupSecret: secrets.Default().Get,
+		resolving:    map[string]bool{},
+	}
+}
+
+type interpolator struct {
+	variables    map[string]string
+	builtIns     map[string]string
+	lookupEnv    func(string) (string, bool)
+	lookupSecret func(string) (string, bool, error)
+	resolving    map[string]bool
+}
+
+func (i *interpolator) interpolate(value string) (string, error) {
+	var b strings.Builder
+
+	remaining := value
+	for {
+		start := strings.Index(remaining, VariablePrefix)
+		if start == -1 {
+			b.WriteString(remaining)
+			return b.String(), nil
+		}
+
+		// An escaped reference is written out as a literal ${
+		if start > 0 && remaining[start-1] == '$' {
+			b.WriteString(remaining[:start-1])
+			b.WriteString(VariablePrefix)
+			remaining = remaining[start+len(VariablePrefix):]
+			continue
+		}
+
+		b.WriteString(remaining[:start])
+		remaining = remaining[start+len(VariablePrefix):]
+
+		end := strings.Index(remaining, VariableSuffix)
+		if end == -1 {
+			return "", fmt.Errorf("interpolating %q: %w", value, ErrUnterminatedVariable)
+		}
+		reference := remaining[:end]
+		remaining = remaining[end+len(VariableSuffix):]
+
+		resolved, err := i.resolve(reference)
+		if err != nil {
+			return "", err
+		}
+		b.WriteString(resolved)
+	}
+}
+
+func (i *interpolator) resolve(reference string) (string, error) {
+	name := reference
+	defaultValue := ""
+	hasDefault := false
+	if idx := strings.Index(reference, defaultSeparator); idx != -1 {
+		name = reference[:idx]
+		defaultValue = reference[idx+len(defaultSeparator):]
+		hasDefault = true
+	}
+	name = strings.TrimSpace(name)
+	if name == "" {
+		return "", ErrEmptyVariableName
+	}
+	if strings.HasPrefix(name, SecretPrefix) {
+		return i.resolveSecret(strings.TrimPrefix(name, SecretPrefix), defaultValue, hasDefault)
+	}
+
+	if value, ok := i.variables[name]; ok {
+		if i.resolving[name] {
+			return "", fmt.Errorf("resolving variable %s: %w", name, ErrVariableCycle)
+		}
+		// User defined variables can reference other variables
+		i.resolving[name] = true
+		defer delete(i.resolving, name)
+
+		return i.interpolate(value)
+	}
+	if value, ok := i.builtIns[name]; ok {
+		return value, nil
+	}
+	if value, ok := i.lookupEnv(name); ok {
+		return value, nil
+	}
+	if hasDefault {
+		return i.interpolate(defaultValue)
+	}
+
+	return "", fmt.Errorf("resolving variable %s: %w", name, ErrVariableNotDefined)
+}
+
+func (i *interpolator) resolveSecret(key, defaultValue string, hasDefault bool) (string, error) {
+	key = strings.TrimSpace(key)
+	if key == "" {
+		return "", ErrEmptyVariableName
+	}
+
+	value, found, err := i.lookupSecret(key)
+	if err != nil {
+		return "", fmt.Errorf("getting secret %s: %w", key, err)
+	}
+	if found {
+		// Secrets must never be logged
+		logging.Redact(value)
+		return value, nil
+	}
+	if hasDefault {
+		return i.interpolate(defaultValue)
+	}
+
+	return "", fmt.Errorf("resolving secret %s: %w", key, ErrSecretNotFound)
+}
@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"os"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/config"
+)
+
+func TestInterpolateValue(t *testing.T) {
+	os.Setenv("KCONNECT_TEST_ROLE", "admin") //nolint: errcheck
+	defer os.Unsetenv("KCONNECT_TEST_ROLE")  //nolint: errcheck
+
+	builtIns := config.BuiltInVariables()
+
+	testCases := []struct {
+		name        string
+		value       string
+		variables   map[string]string
+		expect      string
+		expectError bool
+	}{
+		{
+			name:   "no variables",
+			value:  "plain value",
+			expect: "plain value",
+		},
+		{
+			name:   "list reference is unchanged",
+			value:  "$roles",
+			expect: "$roles",
+		},
+		{
+			name:      "user defined variable",
+			value:     "${domain}\\bob",
+			variables: map[string]string{"domain": "CORP"},
+			expect:    "CORP\\bob",
+		},
+		{
+			name:      "user defined variable referencing built-in",
+			value:     "${user}",
+			variables: map[string]string{"user": "${username}@corp.com"},
+			expect:    builtIns["username"] + "@corp.com",
+		},
+		{
+			name:   "environment variable",
+			value:  "arn:aws:iam::000000000000:role/${KCONNECT_TEST_ROLE}",
+			expect: "arn:aws:iam::000000000000:role/admin",
+		},
+		{
+			name:   "default value used",
+			value:  "${KCONNECT_TEST_UNSET:-fallback}",
+			expect: "fallback",
+		},
+		{
+			name:   "escaped reference",
+			value:  "$${literal}",
+			expect: "${literal}",
+		},
+		{
+			name:   "secret default value used",
+			value:  "${secret:kconnect-test-unset:-fallback}",
+			expect: "fallback",
+		},
+		{
+			name:        "undefined variable",
+			value:       "${KCONNECT_TEST_UNSET}",
+			expectError: true,
+		},
+		{
+			name:        "unterminated reference",
+			value:       "${username",
+			expectError: true,
+		},
+		{
+			name:        "variable cycle",
+			value:       "${a}",
+			variables:   map[string]string{"a": "${b}", "b": "${a}"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := config.InterpolateValue(tc.value, tc.variables) //nolint:scopelint
+			if tc.expectError {                                            //nolint:scopelint
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual).To(Equal(tc.expect)) //nolint:scopelint
+		})
+	}
+}
+
+func TestHasSecretReference(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(config.HasSecretReference("${secret:rancher-password}")).To(BeTrue())
+	g.Expect(config.HasSecretReference("${domain}\\${secret:username}")).To(BeTrue())
+	g.Expect(config.HasSecretReference("${username}")).To(BeFalse())
+	g.Expect(config.HasSecretReference("$${secret:literal}")).To(BeFalse())
+	g.Expect(config.HasSecretReference("secret:plain")).To(BeFalse())
+}
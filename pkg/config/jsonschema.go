@@ -0,0 +1,249 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"sort"
+
+	kconnectv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/notices"
+	"github.com/fidelity/kconnect/pkg/secrets"
+)
+
+const (
+	// JSONSchemaDraft is the version of JSON Schema that is generated
+	JSONSchemaDraft = "https://json-schema.org/draft/2019-09/schema"
+
+	jsonTypeObject = "object"
+	jsonTypeString = "string"
+	jsonTypeArray  = "array"
+
+	jsonFormatDateTime = "date-time"
+
+	// referencePattern matches values that are a list or variable reference
+	referencePattern = `^\$`
+)
+
+// JSONSchema represents a JSON Schema document. Only the parts
+// of the specification needed to describe the app configuration are included.
+type JSONSchema struct {
+	Schema               string                 `json:"$schema,omitempty"`
+	Title                string                 `json:"title,omitempty"`
+	Description          string                 `json:"description,omitempty"`
+	Type                 string                 `json:"type,omitempty"`
+	Properties           map[string]*JSONSchema `json:"properties,omitempty"`
+	AdditionalProperties *JSONSchema            `json:"additionalProperties,omitempty"`
+	Items                *JSONSchema            `json:"items,omitempty"`
+	Required             []string               `json:"required,omitempty"`
+	Enum                 []string               `json:"enum,omitempty"`
+	AnyOf                []*JSONSchema          `json:"anyOf,omitempty"`
+	Pattern              string                 `json:"pattern,omitempty"`
+	Format               string                 `json:"format,omitempty"`
+	Default              string                 `json:"default,omitempty"`
+	Deprecated           bool                   `json:"deprecated,omitempty"`
+	WriteOnly            bool                   `json:"writeOnly,omitempty"`
+
+	// The kconnect extensions describe the config item as all values are strings in the config
+	ItemType     ItemType `json:"x-kconnect-type,omitempty"`
+	ItemRequired bool     `json:"x-kconnect-required,omitempty"`
+	Sensitive    bool     `json:"x-kconnect-sensitive,omitempty"`
+}
+
+// JSONSchema will create a JSON Schema that describes the app configuration file
+func (s *Schema) JSONSchema() *JSONSchema {
+	providers := &JSONSchema{
+		Type:        jsonTypeObject,
+		Description: "Configuration values for a specific provider, these override the global values",
+		Properties:  map[string]*JSONSchema{},
+	}
+	for name, items := range s.Providers {
+		providers.Properties[name] = ItemsJSONSchema(items)
+	}
+
+	clusterPatterns := &JSONSchema{
+		Type: jsonTypeArray,
+		Items: &JSONSchema{
+			Type: jsonTypeObject,
+			Properties: map[string]*JSONSchema{
+				"name": {Type: jsonTypeString, Description: "The pattern for the cluster name, * matches any characters"},
+				"tags": {
+					Type:                 jsonTypeObject,
+					Description:          "The patterns for the values of the cluster tags, * matches any characters",
+					AdditionalProperties: &JSONSchema{Type: jsonTypeString},
+				},
+			},
+		},
+	}
+	clusterPolicies := &JSONSchema{
+		Type:        jsonTypeObject,
+		Description: "The clusters that can be connected to for a specific discovery provider",
+		Properties:  map[string]*JSONSchema{},
+	}
+	for name := range s.Providers {
+		clusterPolicies.Properties[name] = &JSONSchema{
+			Type: jsonTypeObject,
+			Properties: map[string]*JSONSchema{
+				"allow": clusterPatterns,
+				"deny":  clusterPatterns,
+			},
+		}
+	}
+
+	spec := &JSONSchema{
+		Type: jsonTypeObject,
+		Properties: map[string]*JSONSchema{
+			"global":    ItemsJSONSchema(s.Global),
+			"providers": providers,
+			"lists": {
+				Type:        jsonTypeObject,
+				Description: "Named lists of values that a user can choose from, referenced using $name",
+				AdditionalProperties: &JSONSchema{
+					Type: jsonTypeArray,
+					Items: &JSONSchema{
+						Type: jsonTypeObject,
+						Properties: map[string]*JSONSchema{
+							"name":  {Type: jsonTypeString, Description: "The name to display to the user"},
+							"value": {Type: jsonTypeString, Description: "The value to use if the item is selected"},
+						},
+						Required: []string{"name", "value"},
+					},
+				},
+			},
+			"variables": {
+				Type:                 jsonTypeObject,
+				Description:          "User defined variables that can be referenced in values using ${name}",
+				AdditionalProperties: &JSONSchema{Type: jsonTypeString},
+			},
+			"plugins": {
+				Type:        jsonTypeObject,
+				Description: "The policy for which plugins can be used",
+				Properties: map[string]*JSONSchema{
+					"disabled": {
+						Type:        jsonTypeArray,
+						Description: "The names of the plugins that can't be used",
+						Items:       &JSONSchema{Type: jsonTypeString, Enum: s.Plugins},
+					},
+				},
+			},
+			"usageMetrics": {
+				Type:        jsonTypeObject,
+				Description: "Where anonymized usage metrics are reported, no metrics are reported unless an endpoint is set",
+				Properties: map[string]*JSONSchema{
+					"endpoint": {Type: jsonTypeString, Description: "The URL the usage metrics are posted to"},
+				},
+			},
+			"secretStore": {
+				Type:        jsonTypeObject,
+				Description: "Where sensitive values, such as the tokens kconnect creates, are kept",
+				Properties: map[string]*JSONSchema{
+					"type": {Type: jsonTypeString, Enum: secrets.Types(), Default: secrets.TypeFile},
+					"vault": {
+						Type: jsonTypeObject,
+						Properties: map[string]*JSONSchema{
+							"address":   {Type: jsonTypeString, Description: "The URL of the Vault server, it defaults to VAULT_ADDR"},
+							"namespace": {Type: jsonTypeString, Description: "The Vault Enterprise namespace, it defaults to VAULT_NAMESPACE"},
+							"mount":     {Type: jsonTypeString, Description: "The path the KV version 2 secrets engine is mounted at", Default: "secret"},
+							"path":      {Type: jsonTypeString, Description: "The path the secrets are kept under", Default: "kconnect"},
+						},
+					},
+					"awsSecretsManager": {
+						Type: jsonTypeObject,
+						Properties: map[string]*JSONSchema{
+							"region":   {Type: jsonTypeString, Description: "The AWS region of the secrets, it defaults to the region of the profile"},
+							"profile":  {Type: jsonTypeString, Description: "The AWS profile used to access the secrets"},
+							"prefix":   {Type: jsonTypeString, Description: "The prefix of the names of the secrets", Default: "kconnect/"},
+							"kmsKeyID": {Type: jsonTypeString, Description: "The KMS key used to encrypt new secrets"},
+						},
+					},
+				},
+			},
+			"clusterPolicies": clusterPolicies,
+			"notices": {
+				Type:        jsonTypeArray,
+				Description: "Messages that are shown once when a command starts",
+				Items: &JSONSchema{
+					Type: jsonTypeObject,
+					Properties: map[string]*JSONSchema{
+						"id":      {Type: jsonTypeString, Description: "Identifies the notice, a notice is shown once for each id"},
+						"message": {Type: jsonTypeString, Description: "The text of the notice"},
+						"level":   {Type: jsonTypeString, Enum: notices.Levels(), Default: notices.LevelInfo},
+						"expires": {Type: jsonTypeString, Format: jsonFormatDateTime, Description: "When the notice stops being shown"},
+					},
+					Required: []string{"id", "message"},
+				},
+			},
+		},
+	}
+
+	return &JSONSchema{
+		Schema: JSONSchemaDraft,
+		Title:  "kconnect configuration",
+		Type:   jsonTypeObject,
+		Properties: map[string]*JSONSchema{
+			"apiVersion": {Type: jsonTypeString, Enum: []string{kconnectv1alpha.SchemeGroupVersion.String()}},
+			"kind":       {Type: jsonTypeString, Enum: []string{"Configuration"}},
+			"spec":       spec,
+		},
+	}
+}
+
+// ItemsJSONSchema will create a JSON Schema for an object with a property for
+// each of the configuration items in the set
+func ItemsJSONSchema(cs ConfigurationSet) *JSONSchema {
+	schema := &JSONSchema{
+		Type:       jsonTypeObject,
+		Properties: map[string]*JSONSchema{},
+	}
+	if cs == nil {
+		return schema
+	}
+
+	items := cs.GetAll()
+	sort.Slice(items, func(i, j int) bool {
+		return items[i].Name < items[j].Name
+	})
+	for _, item := range items {
+		schema.Properties[item.Name] = itemJSONSchema(item)
+	}
+
+	return schema
+}
+
+func itemJSONSchema(item *Item) *JSONSchema {
+	schema := &JSONSchema{
+		Type:         jsonTypeString,
+		Description:  item.Description,
+		Deprecated:   item.Deprecated,
+		WriteOnly:    item.Sensitive,
+		ItemType:     item.Type,
+		ItemRequired: item.Required,
+		Sensitive:    item.Sensitive,
+	}
+	if item.DefaultValue != nil {
+		schema.Default = (&Item{Type: item.Type, Value: item.DefaultValue}).ValueString()
+	}
+
+	if len(item.AllowedValues) > 0 {
+		// A list or variable reference can be used instead of one of the allowed values
+		schema.AnyOf = []*JSONSchema{
+			{Enum: item.AllowedValues},
+			{Pattern: referencePattern},
+		}
+	}
+
+	return schema
+}
@@ -0,0 +1,62 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/config"
+)
+
+func TestJSONSchema(t *testing.T) {
+	g := NewWithT(t)
+
+	jsonSchema := createTestSchema(t).JSONSchema()
+	g.Expect(jsonSchema.Schema).To(Equal(config.JSONSchemaDraft))
+
+	spec := jsonSchema.Properties["spec"]
+	g.Expect(spec).NotTo(BeNil())
+
+	maxHistory := spec.Properties["global"].Properties["max-history"]
+	g.Expect(maxHistory).NotTo(BeNil())
+	g.Expect(maxHistory.Type).To(Equal("string"))
+	g.Expect(maxHistory.ItemType).To(Equal(config.ItemTypeInt))
+	g.Expect(maxHistory.Default).To(Equal("100"))
+
+	idpProtocol := spec.Properties["providers"].Properties["eks"].Properties["idp-protocol"]
+	g.Expect(idpProtocol).NotTo(BeNil())
+	g.Expect(idpProtocol.AnyOf).To(HaveLen(2))
+	g.Expect(idpProtocol.AnyOf[0].Enum).To(Equal([]string{"aws-iam", "saml"}))
+
+	secretStore := spec.Properties["secretStore"]
+	g.Expect(secretStore).NotTo(BeNil())
+	g.Expect(secretStore.Properties["type"].Enum).To(ContainElement("vault"))
+	g.Expect(secretStore.Properties["vault"].Properties).To(HaveKey("address"))
+	g.Expect(secretStore.Properties["awsSecretsManager"].Properties).To(HaveKey("kmsKeyID"))
+
+	eksPolicy := spec.Properties["clusterPolicies"].Properties["eks"]
+	g.Expect(eksPolicy).NotTo(BeNil())
+	g.Expect(eksPolicy.Properties["deny"].Items.Properties).To(HaveKey("tags"))
+
+	notice := spec.Properties["notices"].Items
+	g.Expect(notice).NotTo(BeNil())
+	g.Expect(notice.Required).To(Equal([]string{"id", "message"}))
+	g.Expect(notice.Properties["level"].Enum).To(Equal([]string{"info", "warning"}))
+	g.Expect(notice.Properties["expires"].Format).To(Equal("date-time"))
+}
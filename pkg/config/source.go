@@ -0,0 +1,39 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+// ItemSource represents where the value of a configuration item came from
+type ItemSource string
+
+var (
+	// ItemSourceDefault is used when the value is the items default value
+	ItemSourceDefault = ItemSource("default")
+	// ItemSourceFlag is used when the value was supplied as a command line flag
+	ItemSourceFlag = ItemSource("flag")
+	// ItemSourceEnvironment is used when the value was supplied as an environment variable
+	ItemSourceEnvironment = ItemSource("env")
+	// ItemSourceHistory is used when the value came from a history entry
+	ItemSourceHistory = ItemSource("history")
+	// ItemSourceProviderConfig is used when the value came from the provider section of the app config
+	ItemSourceProviderConfig = ItemSource("config (provider)")
+	// ItemSourceGlobalConfig is used when the value came from the global section of the app config
+	ItemSourceGlobalConfig = ItemSource("config (global)")
+	// ItemSourcePrompt is used when the value was entered or chosen by the user
+	ItemSourcePrompt = ItemSource("prompt")
+	// ItemSourceResolved is used when the value was set by a provider whilst resolving
+	ItemSourceResolved = ItemSource("resolved")
+)
@@ -0,0 +1,737 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/notices"
+	"github.com/fidelity/kconnect/pkg/secrets"
+)
+
+const (
+	maxSuggestionDistance = 3
+)
+
+var (
+	topLevelKeys = []string{"apiVersion", "kind", "spec"}
+	specKeys     = []string{"global", "providers", "lists", "variables", "plugins", "usageMetrics", "importedFrom", "versionCheck", "secretStore", "clusterPolicies", "notices"}
+	pluginsKeys  = []string{"disabled"}
+	usageKeys    = []string{"endpoint"}
+	listItemKeys = []string{"name", "value"}
+
+	secretStoreKeys      = []string{"type", "vault", "awsSecretsManager"}
+	vaultSecretStoreKeys = []string{"address", "namespace", "mount", "path"}
+	awsSMSecretStoreKeys = []string{"region", "profile", "prefix", "kmsKeyID"}
+	clusterPolicyKeys    = []string{"allow", "deny"}
+	clusterPatternKeys   = []string{"name", "tags"}
+	noticeKeys           = []string{"id", "message", "level", "expires"}
+
+	schemaLock   sync.Mutex
+	schemaFunc   SchemaFunc
+	cachedSchema *Schema
+)
+
+// Schema describes the configuration items that are valid in the app configuration
+type Schema struct {
+	// Global holds the items that can be set in the global section
+	Global ConfigurationSet
+	// Providers holds the items that can be set for each provider
+	Providers map[string]ConfigurationSet
+	// Plugins holds the names of all the discovery and identity plugins
+	Plugins []string
+}
+
+// SchemaFunc is a function that returns the schema used to validate the app configuration
+type SchemaFunc func() (*Schema, error)
+
+// SetSchemaFunc sets the function used to get the schema when the app configuration
+// is loaded. If no function is set only the structure of the configuration is validated.
+func SetSchemaFunc(fn SchemaFunc) {
+	schemaLock.Lock()
+	defer schemaLock.Unlock()
+
+	schemaFunc = fn
+	cachedSchema = nil
+}
+
+func getSchema() (*Schema, error) {
+	schemaLock.Lock()
+	defer schemaLock.Unlock()
+
+	if schemaFunc == nil {
+		return nil, nil
+	}
+	if cachedSchema != nil {
+		return cachedSchema, nil
+	}
+
+	// The schema is built from all the registered plugins, so it's only built once
+	schema, err := schemaFunc()
+	if err != nil {
+		return nil, err
+	}
+	cachedSchema = schema
+
+	return schema, nil
+}
+
+// ValidationError represents a problem found in the app configuration
+type ValidationError struct {
+	// File is the path of the file that contains the problem
+	File string
+	// Line is the line in the file that contains the problem
+	Line int
+	// Path is the location of the problem within the configuration (e.g. spec.global.username)
+	Path string
+	// Message describes the problem
+	Message string
+	// Suggestion is a suggested fix for the problem
+	Suggestion string
+	// Unknown is true if the problem is a key or provider that isn't known. It may be for
+	// a plugin that isn't installed or from a newer version of kconnect.
+	Unknown bool
+}
+
+func (e *ValidationError) Error() string {
+	location := ""
+	if e.File != "" {
+		location = e.File + ":"
+	}
+	if e.Line > 0 {
+		location = fmt.Sprintf("%s%d:", location, e.Line)
+	}
+	if location != "" {
+		location += " "
+	}
+
+	msg := fmt.Sprintf("%s%s: %s", location, e.Path, e.Message)
+	if e.Suggestion != "" {
+		msg = fmt.Sprintf("%s (%s)", msg, e.Suggestion)
+	}
+
+	return msg
+}
+
+// ValidationErrors is a list of the problems found in the app configuration
+type ValidationErrors []*ValidationError
+
+func (e ValidationErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i := range e {
+		msgs[i] = e[i].Error()
+	}
+
+	return fmt.Sprintf("configuration is invalid:\n%s", strings.Join(msgs, "\n"))
+}
+
+// ErrorCode returns the code for an invalid app configuration
+func (e ValidationErrors) ErrorCode() kerrors.Code {
+	return kerrors.CodeConfigInvalid
+}
+
+// Known returns the problems that aren't unknown keys or providers
+func (e ValidationErrors) Known() ValidationErrors {
+	var known ValidationErrors
+	for _, validationErr := range e {
+		if !validationErr.Unknown {
+			known = append(known, validationErr)
+		}
+	}
+
+	return known
+}
+
+// IsValidationFailed returns true if the error is or wraps ValidationErrors
+func IsValidationFailed(err error) bool {
+	var validationErrs ValidationErrors
+	return errors.As(err, &validationErrs)
+}
+
+// Validate will validate the app configuration data against the supplied schema and
+// return any problems found. If the schema is nil only the structure of the configuration
+// is validated. The file is only used when reporting problems.
+func Validate(file string, data []byte, schema *Schema) (ValidationErrors, error) {
+	root := &yaml.Node{}
+	if err := yaml.Unmarshal(data, root); err != nil {
+		return nil, fmt.Errorf("parsing configuration %s: %w", file, err)
+	}
+	if len(root.Content) == 0 {
+		return nil, nil
+	}
+
+	v := &validator{
+		file:   file,
+		schema: schema,
+		lists:  map[string]bool{},
+	}
+	v.validateRoot(root.Content[0])
+
+	if len(v.errs) == 0 {
+		return nil, nil
+	}
+
+	return v.errs, nil
+}
+
+type validator struct {
+	file   string
+	schema *Schema
+	lists  map[string]bool
+	errs   ValidationErrors
+}
+
+func (v *validator) addError(node *yaml.Node, path, message, suggestion string) {
+	v.errs = append(v.errs, &ValidationError{
+		File:       v.file,
+		Line:       node.Line,
+		Path:       path,
+		Message:    message,
+		Suggestion: suggestion,
+	})
+}
+
+func (v *validator) validateRoot(node *yaml.Node) {
+	if !v.expectMapping(node, "") {
+		return
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		if !contains(topLevelKeys, key.Value) {
+			v.unknownKey(key, key.Value, topLevelKeys)
+			continue
+		}
+		if key.Value == "spec" {
+			v.validateSpec(value)
+		}
+	}
+}
+
+func (v *validator) validateSpec(node *yaml.Node) {
+	if !v.expectMapping(node, "spec") {
+		return
+	}
+
+	// Lists are gathered first so that list references can be checked
+	if lists := mappingValue(node, "lists"); lists != nil {
+		v.validateLists(lists)
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := "spec." + key.Value
+
+		switch key.Value {
+		case "global":
+			var items ConfigurationSet
+			if v.schema != nil {
+				items = v.schema.Global
+			}
+			v.validateValues(value, path, items)
+		case "providers":
+			v.validateProviders(value)
+		case "variables":
+			v.validateValues(value, path, nil)
+		case "plugins":
+			v.validatePlugins(value)
+		case "usageMetrics":
+			v.validateUsageMetrics(value)
+		case "secretStore":
+			v.validateSecretStore(value)
+		case "clusterPolicies":
+			v.validateClusterPolicies(value)
+		case "notices":
+			v.validateNotices(value)
+		case "lists", "importedFrom", "versionCheck":
+		default:
+			v.unknownKey(key, path, specKeys)
+		}
+	}
+}
+
+func (v *validator) validateProviders(node *yaml.Node) {
+	if !v.expectMapping(node, "spec.providers") {
+		return
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := "spec.providers." + key.Value
+
+		if v.schema == nil {
+			v.validateValues(value, path, nil)
+			continue
+		}
+
+		items, ok := v.schema.Providers[key.Value]
+		if !ok {
+			v.unknownKey(key, path, providerNames(v.schema))
+			continue
+		}
+		v.validateValues(value, path, items)
+	}
+}
+
+func (v *validator) validatePlugins(node *yaml.Node) {
+	if !v.expectMapping(node, "spec.plugins") {
+		return
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := "spec.plugins." + key.Value
+
+		if key.Value != "disabled" {
+			v.unknownKey(key, path, pluginsKeys)
+			continue
+		}
+		if value.Kind != yaml.SequenceNode {
+			v.addError(value, path, "expected a list of plugin names", "")
+			continue
+		}
+
+		for _, nameNode := range value.Content {
+			if nameNode.Kind != yaml.ScalarNode {
+				v.addError(nameNode, path, "expected a plugin name", "")
+				continue
+			}
+			if v.schema != nil && len(v.schema.Plugins) > 0 && !contains(v.schema.Plugins, nameNode.Value) {
+				suggestion := fmt.Sprintf("valid plugins are: %s", strings.Join(v.schema.Plugins, ", "))
+				if closest := closestMatch(nameNode.Value, v.schema.Plugins); closest != "" {
+					suggestion = fmt.Sprintf("did you mean %q?", closest)
+				}
+				v.addError(nameNode, path, fmt.Sprintf("unknown plugin %q", nameNode.Value), suggestion)
+			}
+		}
+	}
+}
+
+func (v *validator) validateUsageMetrics(node *yaml.Node) {
+	if !v.expectMapping(node, "spec.usageMetrics") {
+		return
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := "spec.usageMetrics." + key.Value
+
+		if key.Value != "endpoint" {
+			v.unknownKey(key, path, usageKeys)
+			continue
+		}
+		if value.Kind != yaml.ScalarNode {
+			v.addError(value, path, "expected a URL", "")
+			continue
+		}
+		endpoint, err := url.Parse(value.Value)
+		if err != nil || (endpoint.Scheme != "http" && endpoint.Scheme != "https") || endpoint.Host == "" {
+			v.addError(value, path, fmt.Sprintf("invalid endpoint %q", value.Value), "expected a http or https URL")
+		}
+	}
+}
+
+func (v *validator) validateSecretStore(node *yaml.Node) {
+	if !v.expectMapping(node, "spec.secretStore") {
+		return
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := "spec.secretStore." + key.Value
+
+		switch key.Value {
+		case "type":
+			if value.Kind != yaml.ScalarNode {
+				v.addError(value, path, "expected a secret store type", "")
+				continue
+			}
+			v.checkAllowedValue(value, path, value.Value, secrets.Types())
+		case "vault":
+			v.validateScalarKeys(value, path, vaultSecretStoreKeys)
+		case "awsSecretsManager":
+			v.validateScalarKeys(value, path, awsSMSecretStoreKeys)
+		default:
+			v.unknownKey(key, path, secretStoreKeys)
+		}
+	}
+}
+
+func (v *validator) validateClusterPolicies(node *yaml.Node) {
+	if !v.expectMapping(node, "spec.clusterPolicies") {
+		return
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := "spec.clusterPolicies." + key.Value
+
+		if v.schema != nil {
+			if _, ok := v.schema.Providers[key.Value]; !ok {
+				v.unknownKey(key, path, providerNames(v.schema))
+				continue
+			}
+		}
+		if !v.expectMapping(value, path) {
+			continue
+		}
+
+		for j := 0; j < len(value.Content); j += 2 {
+			policyKey, patterns := value.Content[j], value.Content[j+1]
+			patternsPath := path + "." + policyKey.Value
+
+			if !contains(clusterPolicyKeys, policyKey.Value) {
+				v.unknownKey(policyKey, patternsPath, clusterPolicyKeys)
+				continue
+			}
+			if patterns.Kind != yaml.SequenceNode {
+				v.addError(patterns, patternsPath, "expected a list of cluster patterns", "")
+				continue
+			}
+			for k, pattern := range patterns.Content {
+				v.validateClusterPattern(pattern, fmt.Sprintf("%s[%d]", patternsPath, k))
+			}
+		}
+	}
+}
+
+func (v *validator) validateClusterPattern(node *yaml.Node, path string) {
+	if !v.expectMapping(node, path) {
+		return
+	}
+	if len(node.Content) == 0 {
+		v.addError(node, path, "the pattern matches all clusters", "set the name or tags to match")
+		return
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		keyPath := path + "." + key.Value
+
+		switch key.Value {
+		case "name":
+			if value.Kind != yaml.ScalarNode {
+				v.addError(value, keyPath, "expected a cluster name pattern", "")
+			}
+		case "tags":
+			v.validateValues(value, keyPath, nil)
+		default:
+			v.unknownKey(key, keyPath, clusterPatternKeys)
+		}
+	}
+}
+
+func (v *validator) validateNotices(node *yaml.Node) {
+	if node.Kind != yaml.SequenceNode {
+		v.addError(node, "spec.notices", "expected a list of notices", "")
+		return
+	}
+
+	ids := map[string]bool{}
+	for i, notice := range node.Content {
+		path := fmt.Sprintf("spec.notices[%d]", i)
+		if !v.expectMapping(notice, path) {
+			continue
+		}
+		v.validateScalarKeys(notice, path, noticeKeys)
+
+		id := mappingValue(notice, "id")
+		switch {
+		case id == nil || id.Value == "":
+			v.addError(notice, path, "the notice has no id", "set a unique id, the notice is shown once for each id")
+		case ids[id.Value]:
+			v.addError(id, path+".id", fmt.Sprintf("id %q is used by another notice", id.Value), "")
+		default:
+			ids[id.Value] = true
+		}
+		if message := mappingValue(notice, "message"); message == nil || message.Value == "" {
+			v.addError(notice, path, "the notice has no message", "")
+		}
+		if level := mappingValue(notice, "level"); level != nil {
+			v.checkAllowedValue(level, path+".level", level.Value, notices.Levels())
+		}
+		if expires := mappingValue(notice, "expires"); expires != nil {
+			if _, err := time.Parse(time.RFC3339, expires.Value); err != nil {
+				v.addError(expires, path+".expires", fmt.Sprintf("invalid time %q", expires.Value), "use the RFC 3339 format, e.g. 2021-06-01T00:00:00Z")
+			}
+		}
+	}
+}
+
+// validateScalarKeys checks that the node is a map of the valid keys to single values
+func (v *validator) validateScalarKeys(node *yaml.Node, path string, validKeys []string) {
+	if !v.expectMapping(node, path) {
+		return
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		keyPath := path + "." + key.Value
+
+		if !contains(validKeys, key.Value) {
+			v.unknownKey(key, keyPath, validKeys)
+			continue
+		}
+		if value.Kind != yaml.ScalarNode {
+			v.addError(value, keyPath, "expected a single value", "")
+		}
+	}
+}
+
+func (v *validator) validateValues(node *yaml.Node, path string, items ConfigurationSet) {
+	if !v.expectMapping(node, path) {
+		return
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		itemPath := path + "." + key.Value
+
+		if value.Kind != yaml.ScalarNode {
+			v.addError(value, itemPath, "expected a single value", "")
+			continue
+		}
+
+		if HasVariables(value.Value) {
+			// The actual value isn't known until the variables are resolved
+			if err := checkVariableSyntax(value.Value); err != nil {
+				v.addError(value, itemPath, err.Error(), "use ${name} or ${name:-default}")
+			}
+			continue
+		}
+
+		if strings.HasPrefix(value.Value, ListPrefix) {
+			listName := strings.TrimPrefix(value.Value, ListPrefix)
+			if !v.lists[listName] {
+				v.addError(value, itemPath, fmt.Sprintf("list %q is not defined", listName), "add the list to spec.lists")
+			}
+			continue
+		}
+
+		if items == nil {
+			continue
+		}
+
+		item := items.Get(key.Value)
+		if item == nil {
+			v.unknownKey(key, itemPath, itemNames(items))
+			continue
+		}
+		v.validateItemValue(value, itemPath, item)
+	}
+}
+
+func (v *validator) validateItemValue(node *yaml.Node, path string, item *Item) {
+	if node.Value == "" {
+		return
+	}
+
+	switch item.Type {
+	case ItemTypeInt:
+		if _, err := strconv.ParseInt(node.Value, 10, 32); err != nil {
+			v.addError(node, path, fmt.Sprintf("value %q is not a valid int", node.Value), "use a whole number, e.g. 10")
+			return
+		}
+	case ItemTypeBool:
+		if _, err := strconv.ParseBool(node.Value); err != nil {
+			v.addError(node, path, fmt.Sprintf("value %q is not a valid bool", node.Value), "use true or false")
+			return
+		}
+	case ItemTypeDuration:
+		if _, err := time.ParseDuration(node.Value); err != nil {
+			v.addError(node, path, fmt.Sprintf("value %q is not a valid duration", node.Value), "use a number with a unit, e.g. 30s or 1h")
+			return
+		}
+	case ItemTypeStringMap:
+		if _, err := parseStringMap(node.Value); err != nil {
+			v.addError(node, path, fmt.Sprintf("value %q is not a valid map", node.Value), "use comma separated key=value pairs, e.g. team=a,env=dev")
+			return
+		}
+	case ItemTypeStringSlice:
+		if len(item.AllowedValues) > 0 {
+			for _, sliceVal := range parseStringSlice(node.Value) {
+				v.checkAllowedValue(node, path, sliceVal, item.AllowedValues)
+			}
+		}
+		return
+	}
+
+	v.checkAllowedValue(node, path, node.Value, item.AllowedValues)
+}
+
+func (v *validator) checkAllowedValue(node *yaml.Node, path, value string, allowedValues []string) {
+	if len(allowedValues) == 0 || contains(allowedValues, value) {
+		return
+	}
+
+	suggestion := fmt.Sprintf("valid values are: %s", strings.Join(allowedValues, ", "))
+	if closest := closestMatch(value, allowedValues); closest != "" {
+		suggestion = fmt.Sprintf("did you mean %q?", closest)
+	}
+	v.addError(node, path, fmt.Sprintf("value %q is not allowed", value), suggestion)
+}
+
+func (v *validator) validateLists(node *yaml.Node) {
+	if !v.expectMapping(node, "spec.lists") {
+		return
+	}
+
+	for i := 0; i < len(node.Content); i += 2 {
+		key, value := node.Content[i], node.Content[i+1]
+		path := "spec.lists." + key.Value
+		v.lists[key.Value] = true
+
+		if value.Kind != yaml.SequenceNode {
+			v.addError(value, path, "expected a list of name/value pairs", "")
+			continue
+		}
+
+		for j, listItem := range value.Content {
+			itemPath := fmt.Sprintf("%s[%d]", path, j)
+			if !v.expectMapping(listItem, itemPath) {
+				continue
+			}
+			for k := 0; k < len(listItem.Content); k += 2 {
+				itemKey := listItem.Content[k]
+				if !contains(listItemKeys, itemKey.Value) {
+					v.unknownKey(itemKey, itemPath+"."+itemKey.Value, listItemKeys)
+				}
+			}
+		}
+	}
+}
+
+func (v *validator) expectMapping(node *yaml.Node, path string) bool {
+	if node.Kind == yaml.MappingNode {
+		return true
+	}
+	if path == "" {
+		path = "configuration"
+	}
+	v.addError(node, path, "expected a map of keys and values", "")
+
+	return false
+}
+
+func (v *validator) unknownKey(key *yaml.Node, path string, validKeys []string) {
+	suggestion := ""
+	if closest := closestMatch(key.Value, validKeys); closest != "" {
+		suggestion = fmt.Sprintf("did you mean %q?", closest)
+	}
+	v.addError(key, path, fmt.Sprintf("unknown key %q", key.Value), suggestion)
+	v.errs[len(v.errs)-1].Unknown = true
+}
+
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i < len(node.Content); i += 2 {
+		if node.Content[i].Value == key {
+			return node.Content[i+1]
+		}
+	}
+
+	return nil
+}
+
+func providerNames(schema *Schema) []string {
+	names := []string{}
+	for name := range schema.Providers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func itemNames(cs ConfigurationSet) []string {
+	names := []string{}
+	for _, item := range cs.GetAll() {
+		names = append(names, item.Name)
+	}
+	sort.Strings(names)
+
+	return names
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+// closestMatch returns the candidate that is closest to the value, or an
+// empty string if none of the candidates are close enough to be a likely typo
+func closestMatch(value string, candidates []string) string {
+	closest := ""
+	closestDistance := maxSuggestionDistance + 1
+	for _, candidate := range candidates {
+		distance := levenshtein(strings.ToLower(value), strings.ToLower(candidate))
+		if distance < closestDistance {
+			closest = candidate
+			closestDistance = distance
+		}
+	}
+
+	return closest
+}
+
+func levenshtein(a, b string) int {
+	ar, br := []rune(a), []rune(b)
+	prev := make([]int, len(br)+1)
+	curr := make([]int, len(br)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+
+	for i := 1; i <= len(ar); i++ {
+		curr[0] = i
+		for j := 1; j <= len(br); j++ {
+			cost := 1
+			if ar[i-1] == br[j-1] {
+				cost = 0
+			}
+			curr[j] = minInt(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev, curr = curr, prev
+	}
+
+	return prev[len(br)]
+}
+
+func minInt(values ...int) int {
+	m := values[0]
+	for _, v := range values[1:] {
+		if v < m {
+			m = v
+		}
+	}
+
+	return m
+}
@@ -0,0 +1,319 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package config_test
+
+import (
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/config"
+)
+
+func TestValidate(t *testing.T) {
+	testCases := []struct {
+		name         string
+		data         string
+		expectErrors []string
+	}{
+		{
+			name: "valid configuration",
+			data: `apiVersion: kconnect.fidelity.github.com/v1alpha1
+kind: Configuration
+spec:
+  global:
+    username: bob
+    max-history: "10"
+  providers:
+    eks:
+      idp-protocol: saml
+`,
+			expectErrors: []string{},
+		},
+		{
+			name: "valid secret store",
+			data: `spec:
+  global:
+    username: ${secret:username}
+  secretStore:
+    type: vault
+    vault:
+      address: https://vault.example.com
+      mount: kv
+`,
+			expectErrors: []string{},
+		},
+		{
+			name: "invalid secret store",
+			data: `spec:
+  secretStore:
+    type: vualt
+    vault:
+      adress: https://vault.example.com
+`,
+			expectErrors: []string{
+				`config.yaml:3: spec.secretStore.type: value "vualt" is not allowed (did you mean "vault"?)`,
+				`config.yaml:5: spec.secretStore.vault.adress: unknown key "adress" (did you mean "address"?)`,
+			},
+		},
+		{
+			name: "valid cluster policy",
+			data: `spec:
+  clusterPolicies:
+    eks:
+      allow:
+      - name: prod-*
+        tags:
+          team: payments
+      deny:
+      - name: "*-legacy"
+`,
+			expectErrors: []string{},
+		},
+		{
+			name: "invalid cluster policy",
+			data: `spec:
+  clusterPolicies:
+    eks:
+      deny:
+      - nmae: "*-legacy"
+      - {}
+    ekz:
+      allow: []
+`,
+			expectErrors: []string{
+				`config.yaml:5: spec.clusterPolicies.eks.deny[0].nmae: unknown key "nmae" (did you mean "name"?)`,
+				`config.yaml:6: spec.clusterPolicies.eks.deny[1]: the pattern matches all clusters (set the name or tags to match)`,
+				`config.yaml:7: spec.clusterPolicies.ekz: unknown key "ekz" (did you mean "eks"?)`,
+			},
+		},
+		{
+			name: "valid notices",
+			data: `spec:
+  notices:
+  - id: idp-move
+    message: prod clusters are moving to the new IdP on June 1
+    level: warning
+    expires: 2021-06-01T00:00:00Z
+`,
+			expectErrors: []string{},
+		},
+		{
+			name: "invalid notices",
+			data: `spec:
+  notices:
+  - id: idp-move
+    message: prod clusters are moving
+    level: warn
+  - id: idp-move
+    message: again
+    expires: June 1
+  - message: no id
+`,
+			expectErrors: []string{
+				`config.yaml:5: spec.notices[0].level: value "warn" is not allowed (did you mean "warning"?)`,
+				`config.yaml:6: spec.notices[1].id: id "idp-move" is used by another notice`,
+				`config.yaml:8: spec.notices[1].expires: invalid time "June 1" (use the RFC 3339 format, e.g. 2021-06-01T00:00:00Z)`,
+				`config.yaml:9: spec.notices[2]: the notice has no id (set a unique id, the notice is shown once for each id)`,
+			},
+		},
+		{
+			name: "unknown top level key",
+			data: `apiVersion: kconnect.fidelity.github.com/v1alpha1
+kind: Configuration
+sepc:
+  global:
+    username: bob
+`,
+			expectErrors: []string{`config.yaml:3: sepc: unknown key "sepc" (did you mean "spec"?)`},
+		},
+		{
+			name: "unknown global key",
+			data: `spec:
+  global:
+    usrname: bob
+`,
+			expectErrors: []string{`config.yaml:3: spec.global.usrname: unknown key "usrname" (did you mean "username"?)`},
+		},
+		{
+			name: "type mismatch",
+			data: `spec:
+  global:
+    max-history: lots
+`,
+			expectErrors: []string{`config.yaml:3: spec.global.max-history: value "lots" is not a valid int (use a whole number, e.g. 10)`},
+		},
+		{
+			name: "invalid enum value",
+			data: `spec:
+  providers:
+    eks:
+      idp-protocol: saml2
+`,
+			expectErrors: []string{`config.yaml:4: spec.providers.eks.idp-protocol: value "saml2" is not allowed (did you mean "saml"?)`},
+		},
+		{
+			name: "unknown provider",
+			data: `spec:
+  providers:
+    ekss:
+      idp-protocol: saml
+`,
+			expectErrors: []string{`config.yaml:3: spec.providers.ekss: unknown key "ekss" (did you mean "eks"?)`},
+		},
+		{
+			name: "undefined list",
+			data: `spec:
+  global:
+    username: $users
+`,
+			expectErrors: []string{`config.yaml:3: spec.global.username: list "users" is not defined (add the list to spec.lists)`},
+		},
+		{
+			name: "variable reference",
+			data: `spec:
+  variables:
+    domain: CORP
+  global:
+    username: ${domain}\\${username}
+`,
+			expectErrors: []string{},
+		},
+		{
+			name: "unterminated variable reference",
+			data: `spec:
+  global:
+    username: ${username
+`,
+			expectErrors: []string{`config.yaml:3: spec.global.username: interpolating "${username": variable reference is not terminated (use ${name} or ${name:-default})`},
+		},
+		{
+			name: "disabled plugins",
+			data: `spec:
+  plugins:
+    disabled:
+    - eks
+    - sam
+`,
+			expectErrors: []string{`config.yaml:5: spec.plugins.disabled: unknown plugin "sam" (did you mean "saml"?)`},
+		},
+		{
+			name: "usage metrics endpoint",
+			data: `spec:
+  usageMetrics:
+    endpoint: metrics.example.com
+`,
+			expectErrors: []string{`config.yaml:3: spec.usageMetrics.endpoint: invalid endpoint "metrics.example.com" (expected a http or https URL)`},
+		},
+		{
+			name: "defined list",
+			data: `spec:
+  global:
+    username: $users
+  lists:
+    users:
+    - name: Bob
+      value: bob
+`,
+			expectErrors: []string{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			validationErrs, err := config.Validate("config.yaml", []byte(tc.data), createTestSchema(t)) //nolint:scopelint
+			g.Expect(err).NotTo(HaveOccurred())
+
+			actual := []string{}
+			for _, validationErr := range validationErrs {
+				actual = append(actual, validationErr.Error())
+			}
+			g.Expect(actual).To(Equal(tc.expectErrors)) //nolint:scopelint
+		})
+	}
+}
+
+func createTestSchema(t *testing.T) *config.Schema {
+	g := NewWithT(t)
+
+	global := config.NewConfigurationSet()
+	_, err := global.String("username", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = global.Int("max-history", 100, "")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	eks := config.NewConfigurationSet()
+	_, err = eks.String("idp-protocol", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(eks.SetAllowedValues("idp-protocol", []string{"aws-iam", "saml"})).To(Succeed())
+
+	return &config.Schema{
+		Global: global,
+		Providers: map[string]config.ConfigurationSet{
+			"eks": eks,
+		},
+		Plugins: []string{"aws-iam", "eks", "saml"},
+	}
+}
+
+func TestAppConfigurationUnknownKeys(t *testing.T) {
+	g := NewWithT(t)
+
+	schemaBuilds := 0
+	config.SetSchemaFunc(func() (*config.Schema, error) {
+		schemaBuilds++
+		return createTestSchema(t), nil
+	})
+	defer config.SetSchemaFunc(nil)
+
+	dir := t.TempDir()
+	unknownPath := filepath.Join(dir, "unknown.yaml")
+	g.Expect(ioutil.WriteFile(unknownPath, []byte(`spec:
+  global:
+    username: bob
+    newer-flag: "true"
+  providers:
+    external-plugin:
+      idp-protocol: saml
+`), 0600)).To(Succeed())
+	invalidPath := filepath.Join(dir, "invalid.yaml")
+	g.Expect(ioutil.WriteFile(invalidPath, []byte(`spec:
+  global:
+    max-history: lots
+    newer-flag: "true"
+`), 0600)).To(Succeed())
+
+	appCfg, err := config.NewAppConfigurationWithPath(unknownPath)
+	g.Expect(err).NotTo(HaveOccurred())
+	cfg, err := appCfg.Get()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.Spec.Global["username"]).To(Equal("bob"))
+	g.Expect(cfg.Spec.Providers).To(HaveKey("external-plugin"))
+
+	appCfg, err = config.NewAppConfigurationWithPath(invalidPath)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = appCfg.Get()
+	g.Expect(config.IsValidationFailed(err)).To(BeTrue())
+	g.Expect(err.Error()).To(ContainSubstring("max-history"))
+	g.Expect(err.Error()).NotTo(ContainSubstring("newer-flag"))
+
+	g.Expect(schemaBuilds).To(Equal(1))
+}
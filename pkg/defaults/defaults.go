@@ -18,7 +18,10 @@ package defaults
 
 import (
 	"os"
-	"path"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
 )
 
 const (
@@ -31,23 +34,105 @@ const (
 	PasswordConfigItem = "password"
 )
 
+// windowsEnvVar matches a variable in the %USERPROFILE% form used by cmd
+var windowsEnvVar = regexp.MustCompile(`%[A-Za-z_][A-Za-z0-9_()]*%`)
+
 func AppDirectory() string {
 	dir, err := os.UserHomeDir()
 	if err != nil {
 		return ""
 	}
 
-	return path.Join(dir, RootFolderName)
+	return filepath.Join(dir, RootFolderName)
 }
 
 func HistoryPath() string {
 	appDir := AppDirectory()
 
-	return path.Join(appDir, "history.yaml")
+	return filepath.Join(appDir, "history.yaml")
+}
+
+// BinDirectory is where kconnect installs the pre-requisite binaries
+func BinDirectory() string {
+	appDir := AppDirectory()
+
+	return filepath.Join(appDir, "bin")
+}
+
+// CacheDirectory is where kconnect caches the results of provider queries
+func CacheDirectory() string {
+	appDir := AppDirectory()
+
+	return filepath.Join(appDir, "cache")
+}
+
+// KubeconfigsDirectory is where kconnect writes the isolated kubeconfigs
+// used when opening a cluster with another tool
+func KubeconfigsDirectory() string {
+	appDir := AppDirectory()
+
+	return filepath.Join(appDir, "kubeconfigs")
+}
+
+// AuditLogPath is where kconnect records the actions that an organisation may need
+// to audit, such as overriding the cluster policy
+func AuditLogPath() string {
+	appDir := AppDirectory()
+
+	return filepath.Join(appDir, "audit.log")
+}
+
+// NoticesPath is where kconnect records the notices from the app config that have
+// been shown
+func NoticesPath() string {
+	appDir := AppDirectory()
+
+	return filepath.Join(appDir, "notices.json")
+}
+
+// ExpiryNotificationsPath is where kconnect records the credentials expiry notifications
+// that have been sent
+func ExpiryNotificationsPath() string {
+	appDir := AppDirectory()
+
+	return filepath.Join(appDir, "expiry-notifications.json")
+}
+
+// PluginsCachePath is where kconnect caches the descriptions of the external plugins
+func PluginsCachePath() string {
+	appDir := AppDirectory()
+
+	return filepath.Join(appDir, "plugins.json")
 }
 
 func ConfigPath() string {
 	appDir := AppDirectory()
 
-	return path.Join(appDir, "config.yaml")
+	return filepath.Join(appDir, "config.yaml")
+}
+
+// ExpandPath will expand a leading ~ to the user's home directory and the environment
+// variables in a path supplied by the user. On Windows the variables can also be in the
+// %USERPROFILE% form, as they are in cmd and in paths copied from Explorer, as they
+// aren't expanded by PowerShell or when they're in the app config.
+func ExpandPath(p string) string {
+	if p == "" {
+		return p
+	}
+
+	if p == "~" || strings.HasPrefix(p, "~/") || strings.HasPrefix(p, `~\`) {
+		if home, err := os.UserHomeDir(); err == nil {
+			p = home + p[1:]
+		}
+	}
+	if runtime.GOOS == "windows" {
+		p = windowsEnvVar.ReplaceAllStringFunc(p, func(v string) string {
+			if value, ok := os.LookupEnv(strings.Trim(v, "%")); ok {
+				return value
+			}
+			return v
+		})
+	}
+
+	return filepath.Clean(os.ExpandEnv(p))
 }
@@ -0,0 +1,85 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package defaults_test
+
+import (
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+)
+
+func TestExpandPath(t *testing.T) {
+	home := t.TempDir()
+	t.Setenv("HOME", home)
+	t.Setenv("USERPROFILE", home)
+	t.Setenv("KUBE_DIR", filepath.Join(home, ".kube"))
+
+	testCases := []struct {
+		name   string
+		path   string
+		expect string
+	}{
+		{
+			name:   "empty",
+			path:   "",
+			expect: "",
+		},
+		{
+			name:   "home",
+			path:   "~",
+			expect: home,
+		},
+		{
+			name:   "relative to home",
+			path:   "~/.kube/config",
+			expect: filepath.Join(home, ".kube", "config"),
+		},
+		{
+			name:   "environment variable",
+			path:   "$KUBE_DIR/prod.config",
+			expect: filepath.Join(home, ".kube", "prod.config"),
+		},
+		{
+			name:   "tilde not at start",
+			path:   "/tmp/~/config",
+			expect: filepath.Clean("/tmp/~/config"),
+		},
+	}
+	if runtime.GOOS == "windows" {
+		testCases = append(testCases, struct {
+			name   string
+			path   string
+			expect string
+		}{
+			name:   "windows environment variable",
+			path:   `%USERPROFILE%\.kube\config`,
+			expect: filepath.Join(home, ".kube", "config"),
+		})
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			g.Expect(defaults.ExpandPath(tc.path)).To(Equal(tc.expect)) //nolint:scopelint
+		})
+	}
+}
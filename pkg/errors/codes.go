@@ -0,0 +1,124 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors
+
+import (
+	"errors"
+)
+
+// Code is a machine readable category of a failure. Wrappers and CI can use the
+// code, or the exit code for it, instead of the error message.
+type Code string
+
+const (
+	CodeUnknown               Code = "UNKNOWN"
+	CodeConfigInvalid         Code = "CONFIG_INVALID"
+	CodeInputRequired         Code = "INPUT_REQUIRED"
+	CodePluginUnavailable     Code = "PLUGIN_UNAVAILABLE"
+	CodeAuthFailed            Code = "AUTH_FAILED"
+	CodeMFARequired           Code = "MFA_REQUIRED"
+	CodeInvalidCredentials    Code = "INVALID_CREDENTIALS"
+	CodeMFADenied             Code = "MFA_DENIED"
+	CodeAccountLocked         Code = "ACCOUNT_LOCKED"
+	CodeNoClustersFound       Code = "NO_CLUSTERS_FOUND"
+	CodeClusterNotFound       Code = "CLUSTER_NOT_FOUND"
+	CodeClusterDenied         Code = "CLUSTER_DENIED"
+	CodeKubeconfigWriteFailed Code = "KUBECONFIG_WRITE_FAILED"
+	CodePrereqMissing         Code = "PREREQ_MISSING"
+	CodeNetworkError          Code = "NETWORK_ERROR"
+	CodeTimeout               Code = "TIMEOUT"
+	CodeCanceled              Code = "CANCELED"
+)
+
+var exitCodes = map[Code]int{
+	CodeUnknown:               1,
+	CodeConfigInvalid:         2,
+	CodeInputRequired:         3,
+	CodePluginUnavailable:     4,
+	CodeAuthFailed:            10,
+	CodeMFARequired:           11,
+	CodeInvalidCredentials:    12,
+	CodeMFADenied:             13,
+	CodeAccountLocked:         14,
+	CodeNoClustersFound:       20,
+	CodeClusterNotFound:       21,
+	CodeClusterDenied:         22,
+	CodeKubeconfigWriteFailed: 30,
+	CodePrereqMissing:         40,
+	CodeNetworkError:          50,
+	CodeTimeout:               51,
+	CodeCanceled:              130,
+}
+
+// ExitCode returns the process exit code for the code
+func ExitCode(code Code) int {
+	if exitCode, ok := exitCodes[code]; ok {
+		return exitCode
+	}
+
+	return exitCodes[CodeUnknown]
+}
+
+// Coder is implemented by errors that have a code
+type Coder interface {
+	ErrorCode() Code
+}
+
+type codedError struct {
+	code Code
+	err  error
+}
+
+func (e *codedError) Error() string {
+	return e.err.Error()
+}
+
+func (e *codedError) Unwrap() error {
+	return e.err
+}
+
+func (e *codedError) ErrorCode() Code {
+	return e.code
+}
+
+// WithCode will add the code to the error. If the error already has a code it's
+// returned as is, the code closest to the cause of the failure is the most specific.
+func WithCode(code Code, err error) error {
+	if err == nil {
+		return nil
+	}
+	if CodeOf(err) != CodeUnknown {
+		return err
+	}
+
+	return &codedError{code: code, err: err}
+}
+
+// CodeOf returns the code of the error or of an error it wraps. CodeUnknown is
+// returned if none of the errors have a code.
+func CodeOf(err error) Code {
+	var coder Coder
+	if errors.As(err, &coder) {
+		return coder.ErrorCode()
+	}
+
+	return CodeUnknown
+}
+
+func (e *ValidationFailed) ErrorCode() Code {
+	return CodeConfigInvalid
+}
@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package errors_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
+
+func TestCodeOf(t *testing.T) {
+	errMFA := kerrors.WithCode(kerrors.CodeMFARequired, errors.New("mfa token required"))
+
+	testCases := []struct {
+		name     string
+		err      error
+		expected kerrors.Code
+	}{
+		{
+			name:     "no code",
+			err:      errors.New("failed"),
+			expected: kerrors.CodeUnknown,
+		},
+		{
+			name:     "wrapped code",
+			err:      fmt.Errorf("authenticating: %w", errMFA),
+			expected: kerrors.CodeMFARequired,
+		},
+		{
+			name:     "most specific code is kept",
+			err:      kerrors.WithCode(kerrors.CodeAuthFailed, fmt.Errorf("authenticating: %w", errMFA)),
+			expected: kerrors.CodeMFARequired,
+		},
+		{
+			name:     "validation failed",
+			err:      fmt.Errorf("validating: %w", &kerrors.ValidationFailed{}),
+			expected: kerrors.CodeConfigInvalid,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) { //nolint:scopelint
+			g := NewWithT(t)
+
+			g.Expect(kerrors.CodeOf(tc.err)).To(Equal(tc.expected)) //nolint:scopelint
+		})
+	}
+}
+
+func TestWithCode(t *testing.T) {
+	g := NewWithT(t)
+
+	errDenied := errors.New("access denied")
+	err := kerrors.WithCode(kerrors.CodeAuthFailed, errDenied)
+
+	g.Expect(err.Error()).To(Equal("access denied"))
+	g.Expect(errors.Is(err, errDenied)).To(BeTrue())
+	g.Expect(kerrors.WithCode(kerrors.CodeAuthFailed, nil)).To(BeNil())
+}
+
+func TestExitCode(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(kerrors.ExitCode(kerrors.CodeUnknown)).To(Equal(1))
+	g.Expect(kerrors.ExitCode(kerrors.CodeAuthFailed)).To(Equal(10))
+	g.Expect(kerrors.ExitCode(kerrors.CodeInvalidCredentials)).To(Equal(12))
+	g.Expect(kerrors.ExitCode(kerrors.CodeAccountLocked)).To(Equal(14))
+	g.Expect(kerrors.ExitCode(kerrors.Code("SOMETHING_NEW"))).To(Equal(1))
+}
@@ -27,6 +27,12 @@ import (
 	"github.com/spf13/viper"
 )
 
+const (
+	// sourceAnnotation is the flag annotation used to record that a flags value
+	// was set from somewhere other than the command line
+	sourceAnnotation = "kconnect_source"
+)
+
 var (
 	// ErrFlagMissing is an error when there is no flag with a given name
 	ErrFlagMissing = errors.New("flag missing")
@@ -88,6 +94,12 @@ func CreateFlagsFromConfig(cs config.ConfigurationSet) (*pflag.FlagSet, error) {
 			} else {
 				fs.Bool(configItem.Name, defVal, configItem.Description)
 			}
+		case config.ItemTypeDuration, config.ItemTypeStringSlice, config.ItemTypeStringMap, config.ItemTypeEnum:
+			description := configItem.Description
+			if configItem.Type == config.ItemTypeEnum && len(configItem.AllowedValues) > 0 {
+				description = fmt.Sprintf("%s. Possible values: %s", description, strings.Join(configItem.AllowedValues, ", "))
+			}
+			fs.VarP(newItemValue(configItem), configItem.Name, configItem.Shorthand, description)
 		default:
 			return nil, config.ErrUnknownItemType
 		}
@@ -119,10 +131,26 @@ func PopulateConfigFromFlags(flags *pflag.FlagSet, cs config.ConfigurationSet) {
 		case "int":
 			val, _ := flags.GetInt(f.Name)
 			cs.SetValue(f.Name, val) //nolint: errcheck
+		default:
+			if val, ok := f.Value.(*itemValue); ok {
+				cs.SetValue(f.Name, val.value) //nolint: errcheck
+			}
 		}
+		cs.SetSource(f.Name, flagSource(f)) //nolint: errcheck
 	})
 }
 
+func flagSource(f *pflag.Flag) config.ItemSource {
+	if source, ok := f.Annotations[sourceAnnotation]; ok && len(source) > 0 {
+		return config.ItemSource(source[0])
+	}
+	if f.Changed {
+		return config.ItemSourceFlag
+	}
+
+	return config.ItemSourceDefault
+}
+
 func PopulateConfigFromCommand(cmd *cobra.Command, cs config.ConfigurationSet) {
 	PopulateConfigFromFlags(cmd.Flags(), cs)
 	PopulateConfigFromFlags(cmd.PersistentFlags(), cs)
@@ -144,7 +172,12 @@ func BindFlags(cmd *cobra.Command) {
 
 		if !f.Changed && viper.IsSet(f.Name) {
 			val := viper.Get(f.Name)
-			cmd.Flags().Set(f.Name, fmt.Sprintf("%v", val)) //nolint: errcheck
+			if _, isItemValue := f.Value.(*itemValue); isItemValue {
+				// List values need to be in the comma separated form
+				val = strings.Join(viper.GetStringSlice(f.Name), ",")
+			}
+			cmd.Flags().Set(f.Name, fmt.Sprintf("%v", val))                                                     //nolint: errcheck
+			cmd.Flags().SetAnnotation(f.Name, sourceAnnotation, []string{string(config.ItemSourceEnvironment)}) //nolint: errcheck
 		}
 	})
 }
@@ -158,9 +191,12 @@ func GetFlagValueDirect(args []string, longName, shortName string) (string, erro
 		flagShortname = fmt.Sprintf("-%s", shortName)
 	}
 	for i, arg := range args {
-		if arg == flagLongName || (flagShortname != "" && arg == flagShortname) {
+		if (arg == flagLongName || (flagShortname != "" && arg == flagShortname)) && i+1 < len(args) {
 			return args[i+1], nil
 		}
+		if strings.HasPrefix(arg, flagLongName+"=") {
+			return strings.TrimPrefix(arg, flagLongName+"="), nil
+		}
 	}
 
 	// look in app config
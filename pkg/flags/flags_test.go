@@ -19,10 +19,12 @@ package flags_test
 import (
 	"fmt"
 	"testing"
+	"time"
 
 	. "github.com/onsi/gomega"
 	"github.com/spf13/pflag"
 
+	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/flags"
 )
 
@@ -96,6 +98,100 @@ func TestParseFlagMultiValueToMap(t *testing.T) {
 	}
 }
 
+func TestPopulateConfigFromFlagsItemTypes(t *testing.T) {
+	testCases := []struct {
+		name        string
+		args        []string
+		expectError bool
+		expect      map[string]string
+	}{
+		{
+			name: "defaults",
+			args: []string{},
+			expect: map[string]string{
+				"timeout": "30s",
+				"regions": "us-east-1",
+				"login":   "user",
+				"tags":    "",
+			},
+		},
+		{
+			name: "values set",
+			args: []string{"--timeout", "1m30s", "--regions", "eu-west-1, eu-west-2", "--login", "admin", "--tags", "team=a,env=dev"},
+			expect: map[string]string{
+				"timeout": "1m30s",
+				"regions": "eu-west-1,eu-west-2",
+				"login":   "admin",
+				"tags":    "env=dev,team=a",
+			},
+		},
+		{
+			name:        "invalid enum value",
+			args:        []string{"--login", "root"},
+			expectError: true,
+		},
+		{
+			name:        "invalid duration",
+			args:        []string{"--timeout", "soon"},
+			expectError: true,
+		},
+		{
+			name:        "invalid map",
+			args:        []string{"--tags", "team"},
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cs := config.NewConfigurationSet()
+			_, err := cs.Duration("timeout", 30*time.Second, "")
+			g.Expect(err).NotTo(HaveOccurred())
+			_, err = cs.StringSlice("regions", []string{"us-east-1"}, "")
+			g.Expect(err).NotTo(HaveOccurred())
+			_, err = cs.Enum("login", "user", []string{"user", "admin"}, "")
+			g.Expect(err).NotTo(HaveOccurred())
+			_, err = cs.StringMap("tags", map[string]string{}, "")
+			g.Expect(err).NotTo(HaveOccurred())
+
+			fs, err := flags.CreateFlagsFromConfig(cs)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			err = fs.Parse(tc.args) //nolint:scopelint
+			if tc.expectError {     //nolint:scopelint
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+
+			flags.PopulateConfigFromFlags(fs, cs)
+			for name, expected := range tc.expect { //nolint:scopelint
+				g.Expect(cs.ValueString(name)).To(Equal(expected))
+			}
+		})
+	}
+}
+
+func TestPopulateConfigFromFlagsSource(t *testing.T) {
+	g := NewWithT(t)
+
+	cs := config.NewConfigurationSet()
+	_, err := cs.String("region", "us-east-1", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = cs.String("username", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	fs, err := flags.CreateFlagsFromConfig(cs)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(fs.Parse([]string{"--username", "bob"})).To(Succeed())
+
+	flags.PopulateConfigFromFlags(fs, cs)
+	g.Expect(cs.Get("region").Source).To(Equal(config.ItemSourceDefault))
+	g.Expect(cs.Get("username").Source).To(Equal(config.ItemSourceFlag))
+}
+
 func createTestFlagSet(t *testing.T, name, value string) *pflag.FlagSet {
 	fs := pflag.NewFlagSet("", pflag.PanicOnError)
 	fs.String(name, "", "test flag")
@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package flags
+
+import (
+	"github.com/fidelity/kconnect/pkg/config"
+)
+
+// itemValue is a flag value that uses the parsing and formatting of a config
+// item. Its used for the item types that don't map directly to a pflag type so
+// that flags, the app config and history all use the same string format.
+type itemValue struct {
+	item  *config.Item
+	value interface{}
+}
+
+func newItemValue(item *config.Item) *itemValue {
+	return &itemValue{
+		item:  item,
+		value: item.DefaultValue,
+	}
+}
+
+func (v *itemValue) String() string {
+	formatted := &config.Item{
+		Type:  v.item.Type,
+		Value: v.value,
+	}
+
+	return formatted.ValueString()
+}
+
+func (v *itemValue) Set(value string) error {
+	parsed, err := config.ParseItemValue(v.item, value)
+	if err != nil {
+		return err
+	}
+	v.value = parsed
+
+	return nil
+}
+
+func (v *itemValue) Type() string {
+	return string(v.item.Type)
+}
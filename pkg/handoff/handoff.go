@@ -0,0 +1,159 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handoff
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+	"time"
+)
+
+// Kind is the kind of place that credentials are handed off to
+type Kind string
+
+var (
+	// KindDevcontainer is a running devcontainer, or any docker container
+	KindDevcontainer = Kind("devcontainer")
+	// KindWSL is a Windows Subsystem for Linux distro
+	KindWSL = Kind("wsl")
+	// KindSSH is a remote host that is reachable with ssh
+	KindSSH = Kind("ssh")
+)
+
+var (
+	ErrInvalidTarget = errors.New("target must be in the format kind:[user@]name, kind is devcontainer, wsl or ssh")
+	ErrUnknownKind   = errors.New("unknown handoff target kind")
+	ErrInvalidName   = errors.New("target name or user can't start with -")
+)
+
+// Target is the devcontainer, WSL distro or remote host to hand off credentials to
+type Target struct {
+	Kind Kind
+	User string
+	Name string
+}
+
+// ParseTarget parses a target in the format kind:[user@]name, e.g. ssh:dev@buildbox
+func ParseTarget(target string) (*Target, error) {
+	parts := strings.SplitN(target, ":", 2) //nolint: gomnd
+	if len(parts) != 2 || parts[1] == "" {  //nolint: gomnd
+		return nil, ErrInvalidTarget
+	}
+
+	t := &Target{Kind: Kind(parts[0]), Name: parts[1]}
+	switch t.Kind {
+	case KindDevcontainer, KindWSL, KindSSH:
+	default:
+		return nil, fmt.Errorf("parsing target kind %s: %w", t.Kind, ErrUnknownKind)
+	}
+
+	if at := strings.LastIndex(t.Name, "@"); at != -1 {
+		t.User = t.Name[:at]
+		t.Name = t.Name[at+1:]
+	}
+	if t.Name == "" {
+		return nil, ErrInvalidTarget
+	}
+	// The name and user are passed to ssh, docker or wsl.exe and mustn't be read as options
+	if strings.HasPrefix(t.Name, "-") || strings.HasPrefix(t.User, "-") {
+		return nil, fmt.Errorf("parsing target %s: %w", target, ErrInvalidName)
+	}
+
+	return t, nil
+}
+
+func (t *Target) String() string {
+	if t.User == "" {
+		return fmt.Sprintf("%s:%s", t.Kind, t.Name)
+	}
+
+	return fmt.Sprintf("%s:%s@%s", t.Kind, t.User, t.Name)
+}
+
+// Copy copies the kubeconfig to the path in the target. A path that isn't
+// absolute is relative to the home directory in the target. The kubeconfig
+// is only readable by the user and is deleted from the target when it expires.
+func Copy(ctx context.Context, target *Target, kubeconfig []byte, kubeconfigPath string, expiresIn time.Duration) error {
+	cmd := target.command(ctx, copyScript(kubeconfigPath, expiresIn))
+	cmd.Stdin = bytes.NewReader(kubeconfig)
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("copying kubeconfig to %s: %w", target, err)
+	}
+
+	return nil
+}
+
+// command returns the command that runs the shell script in the target
+func (t *Target) command(ctx context.Context, script string) *exec.Cmd {
+	switch t.Kind {
+	case KindDevcontainer:
+		args := []string{"exec", "-i"}
+		if t.User != "" {
+			args = append(args, "-u", t.User)
+		}
+		args = append(args, "--", t.Name, "sh", "-c", script)
+		return exec.CommandContext(ctx, "docker", args...) //nolint: gosec
+	case KindWSL:
+		args := []string{"-d", t.Name}
+		if t.User != "" {
+			args = append(args, "-u", t.User)
+		}
+		args = append(args, "-e", "sh", "-c", script)
+		return exec.CommandContext(ctx, "wsl.exe", args...) //nolint: gosec
+	default:
+		host := t.Name
+		if t.User != "" {
+			host = fmt.Sprintf("%s@%s", t.User, t.Name)
+		}
+		// ssh runs the command with the shell of the remote user, so quote it for sh
+		return exec.CommandContext(ctx, "ssh", "--", host, fmt.Sprintf("sh -c %s", quote(script))) //nolint: gosec
+	}
+}
+
+// copyScript returns the shell script that writes stdin to the kubeconfig path and
+// deletes it in the background when it expires
+func copyScript(kubeconfigPath string, expiresIn time.Duration) string {
+	target := quote(kubeconfigPath)
+	if !path.IsAbs(kubeconfigPath) {
+		target = fmt.Sprintf(`"$HOME"/%s`, target)
+	}
+
+	lines := []string{
+		"set -e",
+		"umask 077",
+		fmt.Sprintf("p=%s", target),
+		`mkdir -p "$(dirname "$p")"`,
+		`cat > "$p"`,
+		fmt.Sprintf(`nohup sh -c 'sleep %d; rm -f "$1"' _ "$p" >/dev/null 2>&1 &`, int(expiresIn.Seconds())),
+	}
+
+	return strings.Join(lines, "\n")
+}
+
+// quote single quotes a string for sh
+func quote(s string) string {
+	return fmt.Sprintf("'%s'", strings.ReplaceAll(s, "'", `'\''`))
+}
@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package handoff
+
+import (
+	"context"
+	"errors"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseTarget(t *testing.T) {
+	testCases := []struct {
+		name      string
+		target    string
+		expect    *Target
+		expectErr error
+	}{
+		{
+			name:   "devcontainer",
+			target: "devcontainer:festive_hopper",
+			expect: &Target{Kind: KindDevcontainer, Name: "festive_hopper"},
+		},
+		{
+			name:   "wsl with user",
+			target: "wsl:dev@Ubuntu-22.04",
+			expect: &Target{Kind: KindWSL, User: "dev", Name: "Ubuntu-22.04"},
+		},
+		{
+			name:   "ssh",
+			target: "ssh:dev@buildbox.example.com",
+			expect: &Target{Kind: KindSSH, User: "dev", Name: "buildbox.example.com"},
+		},
+		{
+			name:      "no kind",
+			target:    "buildbox",
+			expectErr: ErrInvalidTarget,
+		},
+		{
+			name:      "no name",
+			target:    "ssh:dev@",
+			expectErr: ErrInvalidTarget,
+		},
+		{
+			name:      "unknown kind",
+			target:    "vm:buildbox",
+			expectErr: ErrUnknownKind,
+		},
+		{
+			name:      "name is an option",
+			target:    "ssh:-oProxyCommand=touch /tmp/x",
+			expectErr: ErrInvalidName,
+		},
+		{
+			name:      "user is an option",
+			target:    "devcontainer:-v@festive_hopper",
+			expectErr: ErrInvalidName,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			target, err := ParseTarget(tc.target) //nolint:scopelint
+			if tc.expectErr != nil {              //nolint:scopelint
+				g.Expect(errors.Is(err, tc.expectErr)).To(BeTrue()) //nolint:scopelint
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(target).To(Equal(tc.expect))          //nolint:scopelint
+			g.Expect(target.String()).To(Equal(tc.target)) //nolint:scopelint
+		})
+	}
+}
+
+func TestCopyScript(t *testing.T) {
+	g := NewWithT(t)
+
+	home := t.TempDir()
+	cmd := exec.Command("sh", "-c", copyScript(".kube/it's-handoff.yaml", time.Second))
+	cmd.Env = append(os.Environ(), "HOME="+home)
+	cmd.Stdin = strings.NewReader("kubeconfig")
+	g.Expect(cmd.Run()).To(Succeed())
+
+	kubeconfigPath := filepath.Join(home, ".kube", "it's-handoff.yaml")
+	data, err := os.ReadFile(kubeconfigPath)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(Equal("kubeconfig"))
+
+	info, err := os.Stat(kubeconfigPath)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(info.Mode().Perm()).To(Equal(os.FileMode(0600)))
+
+	g.Eventually(func() bool {
+		_, err := os.Stat(kubeconfigPath)
+		return os.IsNotExist(err)
+	}, 5*time.Second, 100*time.Millisecond).Should(BeTrue())
+}
+
+func TestCommandEndsOptions(t *testing.T) {
+	g := NewWithT(t)
+
+	ssh := (&Target{Kind: KindSSH, User: "dev", Name: "buildbox"}).command(context.TODO(), "true")
+	g.Expect(ssh.Args[1:3]).To(Equal([]string{"--", "dev@buildbox"}))
+
+	docker := (&Target{Kind: KindDevcontainer, Name: "festive_hopper"}).command(context.TODO(), "true")
+	g.Expect(docker.Args[3:5]).To(Equal([]string{"--", "festive_hopper"}))
+}
@@ -0,0 +1,81 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"fmt"
+	"strings"
+
+	historyv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+)
+
+// AliasCompletions returns the shell completions for the aliases of the entries that
+// start with the prefix. Each completion is in the form alias<tab>description, the
+// description is shown by the shells that support it.
+func AliasCompletions(entries []historyv1alpha.HistoryEntry, prefix string, exclude []string) []string {
+	completions := []string{}
+	for i := range entries {
+		entry := &entries[i]
+		if entry.Spec.Alias == nil || *entry.Spec.Alias == "" {
+			continue
+		}
+		alias := *entry.Spec.Alias
+		if !strings.HasPrefix(alias, prefix) || contains(exclude, alias) {
+			continue
+		}
+		completions = append(completions, fmt.Sprintf("%s\t%s", alias, entryDescription(entry, false)))
+	}
+
+	return completions
+}
+
+// IDCompletions returns the shell completions for the ids of the entries that start
+// with the prefix, in the same form as AliasCompletions
+func IDCompletions(entries []historyv1alpha.HistoryEntry, prefix string, exclude []string) []string {
+	completions := []string{}
+	for i := range entries {
+		entry := &entries[i]
+		id := entry.ObjectMeta.Name
+		if !strings.HasPrefix(strings.ToLower(id), strings.ToLower(prefix)) || contains(exclude, id) {
+			continue
+		}
+		completions = append(completions, fmt.Sprintf("%s\t%s", id, entryDescription(entry, true)))
+	}
+
+	return completions
+}
+
+// entryDescription describes the entry by its provider and cluster, and its alias if
+// it has one and it's requested
+func entryDescription(entry *historyv1alpha.HistoryEntry, withAlias bool) string {
+	description := fmt.Sprintf("%s %s", entry.Spec.Provider, entry.Spec.ProviderID)
+	if withAlias && entry.Spec.Alias != nil && *entry.Spec.Alias != "" {
+		description = fmt.Sprintf("%s (%s)", *entry.Spec.Alias, description)
+	}
+
+	return description
+}
+
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	historyv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/history"
+)
+
+func TestCompletions(t *testing.T) {
+	g := NewWithT(t)
+
+	entries := []historyv1alpha.HistoryEntry{
+		completionEntry("01exm3ty400w9sr28jawc8fkae", "dev-1", "eks", "arn:aws:eks:eu-west-1:000000000000:cluster/dev-1"),
+		completionEntry("01exm3tvw2f5snkj18rk1ngmyb", "", "aks", "dev-aks"),
+		completionEntry("01exm3tkq9t1z3nqx7gkm0w8bc", "prod-1", "eks", "arn:aws:eks:eu-west-1:000000000000:cluster/prod-1"),
+	}
+
+	g.Expect(history.AliasCompletions(entries, "", nil)).To(Equal([]string{
+		"dev-1\teks arn:aws:eks:eu-west-1:000000000000:cluster/dev-1",
+		"prod-1\teks arn:aws:eks:eu-west-1:000000000000:cluster/prod-1",
+	}))
+	g.Expect(history.AliasCompletions(entries, "pr", nil)).To(HaveLen(1))
+	g.Expect(history.AliasCompletions(entries, "", []string{"dev-1"})).To(HaveLen(1))
+
+	g.Expect(history.IDCompletions(entries, "01EXM3T", []string{"01exm3tkq9t1z3nqx7gkm0w8bc"})).To(Equal([]string{
+		"01exm3ty400w9sr28jawc8fkae\tdev-1 (eks arn:aws:eks:eu-west-1:000000000000:cluster/dev-1)",
+		"01exm3tvw2f5snkj18rk1ngmyb\taks dev-aks",
+	}))
+	g.Expect(history.IDCompletions(entries, "02", nil)).To(BeEmpty())
+}
+
+func completionEntry(id, alias, provider, providerID string) historyv1alpha.HistoryEntry {
+	return historyv1alpha.HistoryEntry{
+		ObjectMeta: metav1.ObjectMeta{Name: id},
+		Spec: historyv1alpha.HistoryEntrySpec{
+			Alias:      &alias,
+			Provider:   provider,
+			ProviderID: providerID,
+		},
+	}
+}
@@ -25,6 +25,7 @@ type Store interface {
 	Add(entry *historyv1alpha.HistoryEntry) error
 	Remove(entries []*historyv1alpha.HistoryEntry) error
 	SetHistoryList(historyList *historyv1alpha.HistoryEntryList) error
+	Purge(spec *PurgeSpec) (*PurgeResult, error)
 
 	GetAll() (*historyv1alpha.HistoryEntryList, error)
 	GetByID(id string) (*historyv1alpha.HistoryEntry, error)
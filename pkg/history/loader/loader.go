@@ -36,6 +36,7 @@ type Loader interface {
 }
 
 func NewFileLoader(path string) (Loader, error) {
+	path = defaults.ExpandPath(path)
 	if path == "" {
 		path = defaults.HistoryPath()
 	}
@@ -49,11 +50,15 @@ func NewFileLoader(path string) (Loader, error) {
 		if !os.IsNotExist(err) {
 			return nil, fmt.Errorf("getting details of file %s: %w", historyFile, err)
 		}
-		emptyHistoryFile, err := os.Create(historyFile)
-		if err != nil {
+		// Another kconnect process may have created it since, so it's only created if it
+		// still doesn't exist rather than truncating it
+		emptyHistoryFile, err := os.OpenFile(historyFile, os.O_RDWR|os.O_CREATE|os.O_EXCL, 0666)
+		if err != nil && !os.IsExist(err) {
 			return nil, fmt.Errorf("creating empty history file %s: %w", historyFile, err)
 		}
-		emptyHistoryFile.Close()
+		if err == nil {
+			emptyHistoryFile.Close()
+		}
 
 	} else if info.IsDir() {
 		return nil, fmt.Errorf("supplied path is a directory %s: %w", historyFile, err)
@@ -97,9 +102,50 @@ func (f *fileLoader) Save(historyList *historyv1alpha.HistoryEntryList) error {
 		return fmt.Errorf("marshalling history list: %w", err)
 	}
 
-	if err := ioutil.WriteFile(f.path, data, os.ModePerm); err != nil {
+	if err := writeFileAtomic(f.path, data); err != nil {
 		return fmt.Errorf("saving history file to %s: %w", f.path, err)
 	}
 
 	return nil
 }
+
+// writeFileAtomic writes the data to a temporary file in the same directory and then
+// renames it over the file, so that readers see either the old or the new file and
+// never a partially written one. The mode of the existing file is kept.
+func writeFileAtomic(path string, data []byte) error {
+	if resolved, err := filepath.EvalSymlinks(path); err == nil {
+		path = resolved
+	}
+	mode := os.FileMode(0600)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	tmpFile, err := ioutil.TempFile(filepath.Dir(path), "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return fmt.Errorf("creating temporary file: %w", err)
+	}
+	tmpPath := tmpFile.Name()
+	defer os.Remove(tmpPath) //nolint: errcheck
+
+	if _, err := tmpFile.Write(data); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("writing temporary file %s: %w", tmpPath, err)
+	}
+	if err := tmpFile.Sync(); err != nil {
+		tmpFile.Close()
+		return fmt.Errorf("syncing temporary file %s: %w", tmpPath, err)
+	}
+	if err := tmpFile.Close(); err != nil {
+		return fmt.Errorf("closing temporary file %s: %w", tmpPath, err)
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return fmt.Errorf("setting mode of temporary file %s: %w", tmpPath, err)
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return fmt.Errorf("replacing %s: %w", path, err)
+	}
+
+	return nil
+}
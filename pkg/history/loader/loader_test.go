@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	historyv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/history"
+	"github.com/fidelity/kconnect/pkg/history/loader"
+)
+
+func TestConcurrentAdd(t *testing.T) {
+	g := NewWithT(t)
+
+	historyPath := filepath.Join(t.TempDir(), "history.yaml")
+
+	const processes = 10
+	var wg sync.WaitGroup
+	errs := make(chan error, processes)
+	for i := 0; i < processes; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+
+			// Each store has its own loader, like separate kconnect processes
+			historyLoader, err := loader.NewFileLoader(historyPath)
+			if err != nil {
+				errs <- err
+				return
+			}
+			store, err := history.NewStore(100, historyLoader)
+			if err != nil {
+				errs <- err
+				return
+			}
+			entry := historyv1alpha.NewHistoryEntry()
+			entry.Spec.Provider = "eks"
+			entry.Spec.ProviderID = fmt.Sprintf("cluster-%d", i)
+			errs <- store.Add(entry)
+		}(i)
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		g.Expect(err).NotTo(HaveOccurred())
+	}
+
+	historyLoader, err := loader.NewFileLoader(historyPath)
+	g.Expect(err).NotTo(HaveOccurred())
+	list, err := historyLoader.Load()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(list.Items).To(HaveLen(processes))
+
+	// Only the history and its lock file are left, the temporary files are renamed
+	files, err := os.ReadDir(filepath.Dir(historyPath))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(files).To(HaveLen(2))
+}
+
+func TestLockWaitsForRelease(t *testing.T) {
+	g := NewWithT(t)
+
+	historyPath := filepath.Join(t.TempDir(), "history.yaml")
+	first, err := loader.NewFileLoader(historyPath)
+	g.Expect(err).NotTo(HaveOccurred())
+	second, err := loader.NewFileLoader(historyPath)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	unlock, err := first.(loader.Locker).Lock()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	locked := make(chan struct{})
+	go func() {
+		unlockSecond, err := second.(loader.Locker).Lock()
+		if err == nil {
+			unlockSecond() //nolint: errcheck
+		}
+		close(locked)
+	}()
+
+	g.Consistently(locked, 200*time.Millisecond).ShouldNot(BeClosed())
+	g.Expect(unlock()).To(Succeed())
+	g.Eventually(locked, time.Second).Should(BeClosed())
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package loader
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"time"
+)
+
+const (
+	lockTimeout       = 10 * time.Second
+	lockRetryInterval = 50 * time.Millisecond
+)
+
+var (
+	ErrLockTimeout = errors.New("timed out waiting for another kconnect process to release the history file lock")
+
+	errLocked = errors.New("file is locked")
+)
+
+// UnlockFunc releases a lock
+type UnlockFunc func() error
+
+// Locker is an optional interface for loaders that can stop other kconnect processes
+// changing the history between it being loaded and saved
+type Locker interface {
+	// Lock waits until it has the history lock. The lock must be released with the
+	// returned function.
+	Lock() (UnlockFunc, error)
+}
+
+// Lock takes an advisory lock on a file next to the history file. The history file
+// itself isn't locked as it's replaced when it's saved.
+func (f *fileLoader) Lock() (UnlockFunc, error) {
+	lockPath := f.path + ".lock"
+	lockFile, err := os.OpenFile(lockPath, os.O_CREATE|os.O_RDWR, 0600)
+	if err != nil {
+		return nil, fmt.Errorf("opening history lock file %s: %w", lockPath, err)
+	}
+
+	deadline := time.Now().Add(lockTimeout)
+	for {
+		err := tryLockFile(lockFile)
+		if err == nil {
+			break
+		}
+		if !errors.Is(err, errLocked) || time.Now().After(deadline) {
+			lockFile.Close()
+			if errors.Is(err, errLocked) {
+				err = ErrLockTimeout
+			}
+			return nil, fmt.Errorf("locking history file %s: %w", f.path, err)
+		}
+		time.Sleep(lockRetryInterval)
+	}
+
+	return func() error {
+		unlockErr := unlockFile(lockFile)
+		if err := lockFile.Close(); err != nil && unlockErr == nil {
+			unlockErr = err
+		}
+		if unlockErr != nil {
+			return fmt.Errorf("unlocking history file %s: %w", f.path, unlockErr)
+		}
+
+		return nil
+	}, nil
+}
@@ -0,0 +1,108 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history
+
+import (
+	"strings"
+	"time"
+
+	historyv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+)
+
+// PurgeSpec chooses the history entries to purge and whether the usernames recorded
+// in the entries that are kept are scrubbed
+type PurgeSpec struct {
+	// Cluster is a pattern, that supports wildcards (*), of the provider ids or aliases
+	// of the entries to purge
+	Cluster string
+	// Before purges the entries that were last used before the time
+	Before *time.Time
+	// ScrubUsernames removes the usernames from the flags of the entries that are kept
+	ScrubUsernames bool
+}
+
+// PurgeResult is the outcome of purging the history
+type PurgeResult struct {
+	// Purged are the entries that were removed
+	Purged []historyv1alpha.HistoryEntry
+	// Scrubbed is the number of the kept entries that had usernames removed
+	Scrubbed int
+}
+
+// Matches returns true if the entry should be purged. Both the cluster pattern and the
+// time have to match if both are set, and no entries match if neither is set.
+func (p *PurgeSpec) Matches(entry *historyv1alpha.HistoryEntry) bool {
+	if p.Cluster == "" && p.Before == nil {
+		return false
+	}
+	if p.Cluster != "" && !entryMatchesCluster(entry, p.Cluster) {
+		return false
+	}
+	if p.Before != nil && !entry.Status.LastUsed.Time.Before(*p.Before) {
+		return false
+	}
+
+	return true
+}
+
+// Purge removes the entries that match the spec from the list and scrubs the usernames
+// from the entries that are kept if requested
+func Purge(list *historyv1alpha.HistoryEntryList, spec *PurgeSpec) *PurgeResult {
+	result := &PurgeResult{}
+	kept := []historyv1alpha.HistoryEntry{}
+	for i := range list.Items {
+		entry := list.Items[i]
+		if spec.Matches(&entry) {
+			result.Purged = append(result.Purged, entry)
+			continue
+		}
+		if spec.ScrubUsernames && ScrubUsernames(&entry) {
+			result.Scrubbed++
+		}
+		kept = append(kept, entry)
+	}
+	list.Items = kept
+
+	return result
+}
+
+// ScrubUsernames removes the flags that are usernames from the entry. It returns
+// true if any were removed.
+func ScrubUsernames(entry *historyv1alpha.HistoryEntry) bool {
+	scrubbed := false
+	for name := range entry.Spec.Flags {
+		if IsUsernameFlag(name) {
+			delete(entry.Spec.Flags, name)
+			scrubbed = true
+		}
+	}
+
+	return scrubbed
+}
+
+// IsUsernameFlag returns true if the flag is a username, e.g. username or proxy-username
+func IsUsernameFlag(name string) bool {
+	return name == "username" || strings.HasSuffix(name, "-username")
+}
+
+func entryMatchesCluster(entry *historyv1alpha.HistoryEntry, pattern string) bool {
+	if equalsWithWildcard(pattern, entry.Spec.ProviderID) {
+		return true
+	}
+
+	return entry.Spec.Alias != nil && *entry.Spec.Alias != "" && equalsWithWildcard(pattern, *entry.Spec.Alias)
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package history_test
+
+import (
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	historyv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/history"
+)
+
+func TestPurge(t *testing.T) {
+	newYear := time.Date(2021, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	testCases := []struct {
+		name             string
+		spec             *history.PurgeSpec
+		expectedPurged   []string
+		expectedScrubbed int
+	}{
+		{
+			name:           "cluster pattern matches provider id or alias",
+			spec:           &history.PurgeSpec{Cluster: "*prod*"},
+			expectedPurged: []string{"prod-eks", "aks"},
+		},
+		{
+			name:           "last used before",
+			spec:           &history.PurgeSpec{Before: &newYear},
+			expectedPurged: []string{"dev-eks", "aks"},
+		},
+		{
+			name:           "cluster and before both have to match",
+			spec:           &history.PurgeSpec{Cluster: "*prod*", Before: &newYear},
+			expectedPurged: []string{"aks"},
+		},
+		{
+			name:             "scrub usernames of the kept entries",
+			spec:             &history.PurgeSpec{Cluster: "*prod*", ScrubUsernames: true},
+			expectedPurged:   []string{"prod-eks", "aks"},
+			expectedScrubbed: 1,
+		},
+		{
+			name:             "only scrub usernames",
+			spec:             &history.PurgeSpec{ScrubUsernames: true},
+			expectedScrubbed: 3,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) { //nolint:scopelint
+			g := NewWithT(t)
+
+			list := &historyv1alpha.HistoryEntryList{
+				Items: []historyv1alpha.HistoryEntry{
+					purgeEntry("dev-eks", "arn:aws:eks:eu-west-1:000000000000:cluster/dev", "", time.Date(2020, 6, 1, 0, 0, 0, 0, time.UTC), map[string]string{"username": "bob", "region": "eu-west-1"}),
+					purgeEntry("prod-eks", "arn:aws:eks:eu-west-1:111111111111:cluster/prod", "", time.Date(2021, 2, 1, 0, 0, 0, 0, time.UTC), map[string]string{"username": "bob"}),
+					purgeEntry("aks", "aks-cluster", "prod-aks", time.Date(2020, 12, 1, 0, 0, 0, 0, time.UTC), map[string]string{"proxy-username": "proxy"}),
+				},
+			}
+
+			result := history.Purge(list, tc.spec) //nolint:scopelint
+
+			purged := []string{}
+			for _, entry := range result.Purged {
+				purged = append(purged, entry.ObjectMeta.Name)
+			}
+			g.Expect(purged).To(ConsistOf(tc.expectedPurged))            //nolint:scopelint
+			g.Expect(result.Scrubbed).To(Equal(tc.expectedScrubbed))     //nolint:scopelint
+			g.Expect(list.Items).To(HaveLen(3 - len(tc.expectedPurged))) //nolint:scopelint
+			for _, entry := range list.Items {
+				if tc.spec.ScrubUsernames { //nolint:scopelint
+					g.Expect(entry.Spec.Flags).NotTo(HaveKey("username"))
+					g.Expect(entry.Spec.Flags).NotTo(HaveKey("proxy-username"))
+				}
+			}
+		})
+	}
+}
+
+func purgeEntry(id, providerID, alias string, lastUsed time.Time, flags map[string]string) historyv1alpha.HistoryEntry {
+	return historyv1alpha.HistoryEntry{
+		ObjectMeta: metav1.ObjectMeta{Name: id},
+		Spec: historyv1alpha.HistoryEntrySpec{
+			Alias:      &alias,
+			Provider:   "eks",
+			ProviderID: providerID,
+			Flags:      flags,
+		},
+		Status: historyv1alpha.HistoryEntryStatus{
+			LastUsed: metav1.NewTime(lastUsed),
+		},
+	}
+}
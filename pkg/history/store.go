@@ -50,7 +50,13 @@ type storeImpl struct {
 	maxHistory int
 }
 
-func (s *storeImpl) Add(entry *historyv1alpha.HistoryEntry) error {
+func (s *storeImpl) Add(entry *historyv1alpha.HistoryEntry) (err error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlockHistory(unlock, &err)
+
 	historyList, err := s.loader.Load()
 	if err != nil {
 		return fmt.Errorf("reading history file: %w", err)
@@ -63,6 +69,13 @@ func (s *storeImpl) Add(entry *historyv1alpha.HistoryEntry) error {
 		if (entry.Spec.Alias != nil || *entry.Spec.Alias != "") && (existingEntry.Spec.Alias == nil || *existingEntry.Spec.Alias == "") {
 			s.updateAlias(historyList, existingEntry.Name, entry.Spec.Alias)
 		}
+		if entry.Spec.Annotations != nil {
+			s.updateAnnotations(historyList, existingEntry.Name, entry.Spec.Annotations)
+		}
+		s.updateCredentialsExpiry(historyList, existingEntry.Name, entry.Status.CredentialsExpiry)
+		if entry.Status.ClusterEndpoint != "" || entry.Status.ClusterCAHash != "" {
+			s.updateClusterDetails(historyList, existingEntry.Name, entry.Status.ClusterEndpoint, entry.Status.ClusterCAHash)
+		}
 	} else {
 		historyList.Items = append(historyList.Items, *entry)
 	}
@@ -73,12 +86,23 @@ func (s *storeImpl) Add(entry *historyv1alpha.HistoryEntry) error {
 	return s.loader.Save(historyList)
 }
 
-func (s *storeImpl) SetHistoryList(historyList *historyv1alpha.HistoryEntryList) error {
+func (s *storeImpl) SetHistoryList(historyList *historyv1alpha.HistoryEntryList) (err error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlockHistory(unlock, &err)
 
 	return s.loader.Save(historyList)
 }
 
-func (s *storeImpl) Remove(entries []*historyv1alpha.HistoryEntry) error {
+func (s *storeImpl) Remove(entries []*historyv1alpha.HistoryEntry) (err error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlockHistory(unlock, &err)
+
 	historyList, err := s.loader.Load()
 	if err != nil {
 		return fmt.Errorf("reading history file: %w", err)
@@ -93,6 +117,32 @@ func (s *storeImpl) Remove(entries []*historyv1alpha.HistoryEntry) error {
 	return s.loader.Save(historyList)
 }
 
+// Purge removes the entries that match the spec, and scrubs the usernames from the other
+// entries if requested, while the history is locked
+func (s *storeImpl) Purge(spec *PurgeSpec) (result *PurgeResult, err error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return nil, err
+	}
+	defer unlockHistory(unlock, &err)
+
+	historyList, err := s.loader.Load()
+	if err != nil {
+		return nil, fmt.Errorf("reading history file: %w", err)
+	}
+
+	result = Purge(historyList, spec)
+	if len(result.Purged) == 0 && result.Scrubbed == 0 {
+		return result, nil
+	}
+
+	if err := s.loader.Save(historyList); err != nil {
+		return nil, err
+	}
+
+	return result, nil
+}
+
 func (s *storeImpl) GetAll() (*historyv1alpha.HistoryEntryList, error) {
 	historyList, err := s.loader.Load()
 	if err != nil {
@@ -185,7 +235,13 @@ func (s *storeImpl) GetLastModified(n int) (*historyv1alpha.HistoryEntry, error)
 	return &lastModifiedEntry, nil
 }
 
-func (s *storeImpl) Update(entry *historyv1alpha.HistoryEntry) error {
+func (s *storeImpl) Update(entry *historyv1alpha.HistoryEntry) (err error) {
+	unlock, err := s.lock()
+	if err != nil {
+		return err
+	}
+	defer unlockHistory(unlock, &err)
+
 	list, err := s.loader.Load()
 	if err != nil {
 		return fmt.Errorf("reading history file: %w", err)
@@ -213,6 +269,30 @@ func (s *storeImpl) Update(entry *historyv1alpha.HistoryEntry) error {
 	return s.loader.Save(list)
 }
 
+// lock will lock the history, if the loader supports it, so that other kconnect processes
+// can't change it between it being loaded and saved by this one
+func (s *storeImpl) lock() (loader.UnlockFunc, error) {
+	locker, ok := s.loader.(loader.Locker)
+	if !ok {
+		return func() error { return nil }, nil
+	}
+
+	unlock, err := locker.Lock()
+	if err != nil {
+		return nil, fmt.Errorf("locking history: %w", err)
+	}
+
+	return unlock, nil
+}
+
+// unlockHistory releases the history lock. A failure to unlock is only returned if
+// the change to the history didn't fail.
+func unlockHistory(unlock loader.UnlockFunc, err *error) {
+	if unlockErr := unlock(); unlockErr != nil && *err == nil {
+		*err = unlockErr
+	}
+}
+
 func (s *storeImpl) trimHistory(historyList *historyv1alpha.HistoryEntryList) {
 	diff := len(historyList.Items) - s.maxHistory
 	if diff < 1 {
@@ -280,6 +360,34 @@ func (s *storeImpl) updateAlias(historyList *historyv1alpha.HistoryEntryList, id
 	}
 }
 
+func (s *storeImpl) updateAnnotations(historyList *historyv1alpha.HistoryEntryList, id string, annotations map[string]string) {
+	for i := range historyList.Items {
+		if historyList.Items[i].ObjectMeta.Name == id {
+			historyList.Items[i].Spec.Annotations = annotations
+			return
+		}
+	}
+}
+
+func (s *storeImpl) updateCredentialsExpiry(historyList *historyv1alpha.HistoryEntryList, id string, expiry *v1.Time) {
+	for i := range historyList.Items {
+		if historyList.Items[i].ObjectMeta.Name == id {
+			historyList.Items[i].Status.CredentialsExpiry = expiry
+			return
+		}
+	}
+}
+
+func (s *storeImpl) updateClusterDetails(historyList *historyv1alpha.HistoryEntryList, id string, endpoint, caHash string) {
+	for i := range historyList.Items {
+		if historyList.Items[i].ObjectMeta.Name == id {
+			historyList.Items[i].Status.ClusterEndpoint = endpoint
+			historyList.Items[i].Status.ClusterCAHash = caHash
+			return
+		}
+	}
+}
+
 func (s *storeImpl) sortByLastUsed(historyList *historyv1alpha.HistoryEntryList) {
 	sort.Slice(historyList.Items, func(i, j int) bool {
 		return !historyList.Items[i].Status.LastUsed.Before(&historyList.Items[j].Status.LastUsed)
@@ -17,6 +17,7 @@ limitations under the License.
 package time
 
 import (
+	"fmt"
 	"time"
 
 	"github.com/fidelity/kconnect/pkg/aws/awsconfig"
@@ -47,3 +48,39 @@ func GetRemainingTime(expiresAt time.Time) string {
 	}
 	return timeRemaining.String()
 }
+
+// DescribeExpiry describes when the credentials expire relative to now, e.g. "expires in 45m"
+// or "expired 2h ago"
+func DescribeExpiry(expiresAt, now time.Time) string {
+	if now.After(expiresAt) {
+		return fmt.Sprintf("expired %s ago", approxDuration(now.Sub(expiresAt)))
+	}
+
+	return fmt.Sprintf("expires in %s", approxDuration(expiresAt.Sub(now)))
+}
+
+// DescribeRemaining describes how long is left until the credentials expire, e.g. "45m",
+// or "expired" if they have already expired
+func DescribeRemaining(expiresAt, now time.Time) string {
+	if now.After(expiresAt) {
+		return "expired"
+	}
+
+	return approxDuration(expiresAt.Sub(now))
+}
+
+// approxDuration formats the duration in its largest whole unit, e.g. 2h rather than 2h3m10s
+func approxDuration(d time.Duration) string {
+	const day = 24 * time.Hour
+
+	switch {
+	case d >= day:
+		return fmt.Sprintf("%dd", d/day)
+	case d >= time.Hour:
+		return fmt.Sprintf("%dh", d/time.Hour)
+	case d >= time.Minute:
+		return fmt.Sprintf("%dm", d/time.Minute)
+	default:
+		return fmt.Sprintf("%ds", d/time.Second)
+	}
+}
@@ -17,6 +17,7 @@ limitations under the License.
 package http
 
 import (
+	"context"
 	"encoding/base64"
 	"fmt"
 	"io/ioutil"
@@ -28,15 +29,43 @@ import (
 
 const (
 	MediaTypeJSON = "application/json"
+
+	// LoggerName is the name of the logger for the http requests, e.g. to set its level
+	LoggerName = "http"
 )
 
 // NewHTTPClient creates a new http client
-func NewHTTPClient() Client {
+func NewHTTPClient(opts ...ClientOption) Client {
 	client := &http.Client{}
+	for _, opt := range opts {
+		opt(client)
+	}
+	if RecordingEnabled() {
+		recordClient(client)
+	}
+	if TracingEnabled() {
+		traceClient(client)
+	}
+	propagateClient(client)
 
 	return &netHTTPClient{client}
 }
 
+// traceClient wraps the transport of the client so that requests are traced. Each
+// attempt of a retried request is traced.
+func traceClient(c *http.Client) {
+	if retry, ok := c.Transport.(*retryTransport); ok {
+		retry.next = TraceTransport(retry.next)
+		return
+	}
+	c.Transport = TraceTransport(c.Transport)
+}
+
+// logger returns the logger for the http requests
+func logger() *zap.SugaredLogger {
+	return zap.S().Named(LoggerName)
+}
+
 // netHttpClient is a http client based on net/http
 type netHTTPClient struct {
 	client *http.Client
@@ -46,10 +75,14 @@ func (n *netHTTPClient) Do(req *ClientRequest) (ClientResponse, error) {
 	var r *http.Request
 	var err error
 
+	ctx := req.Context
+	if ctx == nil {
+		ctx = context.Background()
+	}
 	if req.Body == nil {
-		r, err = http.NewRequest(req.Method, req.URL, nil)
+		r, err = http.NewRequestWithContext(ctx, req.Method, req.URL, nil)
 	} else {
-		r, err = http.NewRequest(req.Method, req.URL, strings.NewReader(*req.Body))
+		r, err = http.NewRequestWithContext(ctx, req.Method, req.URL, strings.NewReader(*req.Body))
 	}
 	if err != nil {
 		return nil, fmt.Errorf("creating http request: %w", err)
@@ -58,7 +91,7 @@ func (n *netHTTPClient) Do(req *ClientRequest) (ClientResponse, error) {
 		r.Header.Add(k, v)
 	}
 
-	zap.S().Debugw("http request", "url", req.URL, "method", req.Method, "headers", req.Headers)
+	logger().Debugw("http request", "url", req.URL, "method", req.Method, "headers", req.Headers)
 
 	resp, err := n.client.Do(r)
 	if err != nil {
@@ -109,8 +142,8 @@ func createResponse(resp *http.Response) (ClientResponse, error) {
 		headers[k] = v[0]
 	}
 
-	zap.S().Debugw("http response", "status", resp.Status, "headers", headers)
-	zap.S().Debug(string(body))
+	logger().Debugw("http response", "status", resp.Status, "headers", headers)
+	logger().Debug(string(body))
 
 	return &netHTTPResponse{
 		code:    resp.StatusCode,
@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"sync/atomic"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
+
+var (
+	ErrOffline = kerrors.WithCode(kerrors.CodeNetworkError, errors.New("kconnect is offline, requests to the providers aren't sent"))
+
+	offlineEnabled int32
+)
+
+// EnableOffline will refuse every request sent with a client created by NewHTTPClient
+// or wrapped with RecordTransport, apart from requests that are replayed
+func EnableOffline() {
+	atomic.StoreInt32(&offlineEnabled, 1)
+}
+
+// DisableOffline will allow requests to be sent again
+func DisableOffline() {
+	atomic.StoreInt32(&offlineEnabled, 0)
+}
+
+// OfflineEnabled returns true if requests are refused because kconnect is offline
+func OfflineEnabled() bool {
+	return atomic.LoadInt32(&offlineEnabled) == 1
+}
+
+func refuseRequest(req *http.Request) error {
+	return fmt.Errorf("sending %s request to %s: %w", req.Method, SanitizeURL(req.URL), ErrOffline)
+}
@@ -0,0 +1,173 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+
+	"golang.org/x/net/http/httpproxy"
+)
+
+var (
+	ErrUnsupportedProxyScheme = errors.New("unsupported proxy scheme, http, https or socks5 is required")
+	ErrNoCACertificates       = errors.New("no pem encoded certificates found")
+)
+
+// ClientOption is an option to use when creating a http client
+type ClientOption func(*http.Client)
+
+// WithProxy will send the requests of the client through the proxy. Credentials for an
+// authenticated proxy can be given in the URL.
+func WithProxy(proxyURL *url.URL) ClientOption {
+	return func(c *http.Client) {
+		clientTransport(c).Proxy = http.ProxyURL(proxyURL)
+	}
+}
+
+// WithoutRedirects will return redirect responses instead of following them, e.g. when
+// the token is in the location of the redirect
+func WithoutRedirects() ClientOption {
+	return func(c *http.Client) {
+		c.CheckRedirect = func(req *http.Request, via []*http.Request) error {
+			return http.ErrUseLastResponse
+		}
+	}
+}
+
+// ProxyConfig is the proxy for the requests of a client
+type ProxyConfig struct {
+	// URL is the proxy to use, e.g. http://proxy:8080 or socks5://proxy:1080
+	URL string
+	// NoProxy is a comma separated list of hosts, domains and CIDRs that aren't proxied
+	NoProxy string
+	// Username and Password are the proxy credentials if they aren't in the URL
+	Username string
+	Password string
+}
+
+// WithProxyConfig will send the requests of the client through the proxy unless the host
+// matches the no proxy list
+func WithProxyConfig(cfg *ProxyConfig) (ClientOption, error) {
+	proxyURL, err := ParseProxyURL(cfg.URL)
+	if err != nil {
+		return nil, err
+	}
+	if cfg.Username != "" {
+		proxyURL.User = url.UserPassword(cfg.Username, cfg.Password)
+	}
+
+	proxyFunc := (&httpproxy.Config{
+		HTTPProxy:  proxyURL.String(),
+		HTTPSProxy: proxyURL.String(),
+		NoProxy:    cfg.NoProxy,
+	}).ProxyFunc()
+
+	return func(c *http.Client) {
+		clientTransport(c).Proxy = func(req *http.Request) (*url.URL, error) {
+			return proxyFunc(req.URL)
+		}
+	}, nil
+}
+
+// WithCACertFile will trust the certificates in the PEM file as well as the system
+// certificates, e.g. for endpoints behind a TLS intercepting proxy
+func WithCACertFile(path string) (ClientOption, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading ca certificate file %s: %w", path, err)
+	}
+
+	pool, err := x509.SystemCertPool()
+	if err != nil || pool == nil {
+		pool = x509.NewCertPool()
+	}
+	if !pool.AppendCertsFromPEM(data) {
+		return nil, fmt.Errorf("loading ca certificate file %s: %w", path, ErrNoCACertificates)
+	}
+
+	return func(c *http.Client) {
+		transport := clientTransport(c)
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{} //nolint: gosec
+		}
+		transport.TLSClientConfig.RootCAs = pool
+	}, nil
+}
+
+// WithClientCertFile will present the certificate in the PEM files when a server asks
+// for a client certificate, e.g. for an IdP that requires mutual TLS. The key can be in
+// the certificate file, in which case the key file can be empty.
+func WithClientCertFile(certFile, keyFile string) (ClientOption, error) {
+	if keyFile == "" {
+		keyFile = certFile
+	}
+	cert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("loading client certificate %s: %w", certFile, err)
+	}
+
+	return func(c *http.Client) {
+		transport := clientTransport(c)
+		if transport.TLSClientConfig == nil {
+			transport.TLSClientConfig = &tls.Config{} //nolint: gosec
+		}
+		transport.TLSClientConfig.Certificates = []tls.Certificate{cert}
+	}, nil
+}
+
+// clientTransport returns the transport of the client so that more than one option can
+// change it. The default transport is cloned if the client doesn't have its own.
+func clientTransport(c *http.Client) *http.Transport {
+	// The transport is wrapped when retries are enabled
+	if retry, ok := c.Transport.(*retryTransport); ok {
+		transport, ok := retry.next.(*http.Transport)
+		if !ok {
+			transport = http.DefaultTransport.(*http.Transport).Clone()
+			retry.next = transport
+		}
+		return transport
+	}
+
+	if transport, ok := c.Transport.(*http.Transport); ok {
+		return transport
+	}
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	c.Transport = transport
+
+	return transport
+}
+
+// ParseProxyURL will parse and validate the URL of a proxy
+func ParseProxyURL(rawURL string) (*url.URL, error) {
+	proxyURL, err := url.Parse(rawURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing proxy url: %w", err)
+	}
+
+	switch proxyURL.Scheme {
+	case "http", "https", "socks5":
+		return proxyURL, nil
+	default:
+		return nil, fmt.Errorf("proxy scheme %s: %w", proxyURL.Scheme, ErrUnsupportedProxyScheme)
+	}
+}
@@ -0,0 +1,88 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"net/http"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// tracerName is the name of the tracer of the request spans
+const tracerName = "github.com/fidelity/kconnect/pkg/http"
+
+// PropagateTransport wraps the transport so that a request sent with a context that has
+// a span, i.e. when telemetry is enabled, is recorded as a child span and the trace
+// context is sent to the provider in the traceparent header. Like tracing, the url of
+// the span is sanitized and the headers and bodies aren't recorded.
+func PropagateTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &propagateTransport{next: next}
+}
+
+// propagateClient wraps the transport of the client so that the trace context is
+// propagated. Each attempt of a retried request is a span.
+func propagateClient(c *http.Client) {
+	if retry, ok := c.Transport.(*retryTransport); ok {
+		retry.next = PropagateTransport(retry.next)
+		return
+	}
+	c.Transport = PropagateTransport(c.Transport)
+}
+
+type propagateTransport struct {
+	next http.RoundTripper
+}
+
+func (t *propagateTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	if !trace.SpanContextFromContext(req.Context()).IsValid() {
+		return t.next.RoundTrip(req)
+	}
+
+	ctx, span := otel.Tracer(tracerName).Start(req.Context(), "HTTP "+req.Method,
+		trace.WithSpanKind(trace.SpanKindClient),
+		trace.WithAttributes(
+			attribute.String("http.method", req.Method),
+			attribute.String("http.url", SanitizeURL(req.URL)),
+		),
+	)
+	defer span.End()
+
+	// A round tripper mustn't change the request, so the header is set on a copy
+	req = req.Clone(ctx)
+	otel.GetTextMapPropagator().Inject(ctx, propagation.HeaderCarrier(req.Header))
+
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		span.RecordError(err)
+		span.SetStatus(codes.Error, err.Error())
+		return resp, err
+	}
+
+	span.SetAttributes(attribute.Int("http.status_code", resp.StatusCode))
+	if resp.StatusCode >= http.StatusBadRequest {
+		span.SetStatus(codes.Error, resp.Status)
+	}
+
+	return resp, nil
+}
@@ -0,0 +1,84 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http_test
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/propagation"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/sdk/trace/tracetest"
+	"go.opentelemetry.io/otel/trace"
+
+	khttp "github.com/fidelity/kconnect/pkg/http"
+)
+
+func TestPropagateTransport(t *testing.T) {
+	g := NewWithT(t)
+
+	spans := tracetest.NewSpanRecorder()
+	provider := sdktrace.NewTracerProvider(sdktrace.WithSpanProcessor(spans))
+	otel.SetTracerProvider(provider)
+	otel.SetTextMapPropagator(propagation.TraceContext{})
+	defer func() {
+		otel.SetTracerProvider(trace.NewNoopTracerProvider())
+		otel.SetTextMapPropagator(propagation.NewCompositeTextMapPropagator())
+	}()
+
+	var traceparents []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		traceparents = append(traceparents, r.Header.Get("traceparent"))
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	client := khttp.NewHTTPClient()
+
+	// Without a span in the context the request isn't traced
+	_, err := client.Get(server.URL+"/untraced", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	ctx, parent := provider.Tracer("test").Start(context.Background(), "kconnect.use")
+	_, err = client.Do(&khttp.ClientRequest{
+		Context: ctx,
+		Method:  http.MethodGet,
+		URL:     server.URL + "/token?client_secret=hunter2",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	parent.End()
+
+	g.Expect(traceparents).To(HaveLen(2))
+	g.Expect(traceparents[0]).To(BeEmpty())
+	g.Expect(traceparents[1]).To(ContainSubstring(parent.SpanContext().TraceID().String()))
+
+	ended := spans.Ended()
+	g.Expect(ended).To(HaveLen(2))
+	g.Expect(ended[0].Name()).To(Equal("HTTP GET"))
+	g.Expect(ended[0].SpanKind()).To(Equal(trace.SpanKindClient))
+	g.Expect(ended[0].Parent().SpanID()).To(Equal(parent.SpanContext().SpanID()))
+	g.Expect(traceparents[1]).To(ContainSubstring(ended[0].SpanContext().SpanID().String()))
+	for _, attr := range ended[0].Attributes() {
+		if attr.Key == "http.url" {
+			g.Expect(attr.Value.AsString()).To(Equal(server.URL + "/token?client_secret=REDACTED"))
+		}
+	}
+}
@@ -0,0 +1,471 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+var (
+	ErrRecordAndReplay     = errors.New("requests can't be recorded and replayed at the same time")
+	ErrNoRecordedResponse  = errors.New("no recorded response for the request")
+	ErrNoRecordedResponses = errors.New("no recorded responses found")
+
+	recorderMu sync.Mutex
+	recorder   *interactionRecorder
+
+	// sensitiveHeaders are the parts of header names that mean the value is a credential
+	sensitiveHeaders = []string{
+		"authorization",
+		"cookie",
+		"token",
+		"secret",
+		"password",
+		"signature",
+		"credential",
+		"api-key",
+	}
+
+	// sensitiveBodyFields are the names of the JSON fields that are credentials as a whole,
+	// e.g. a kubeconfig that contains a bearer token
+	sensitiveBodyFields = []string{"config", "kubeconfig"}
+
+	// sensitiveInputs are the parts of the names of HTML form inputs that mean the value is a
+	// credential, as well as the sensitive query parameters
+	sensitiveInputs = []string{"relaystate"}
+
+	xmlElementRegex = regexp.MustCompile(`(?s)<([A-Za-z0-9_:]+)>([^<]*)</([A-Za-z0-9_:]+)>`)
+	htmlInputRegex  = regexp.MustCompile(`(?is)<input\b[^>]*>`)
+	inputNameRegex  = regexp.MustCompile(`(?is)\bname\s*=\s*["']?([^"'\s>]+)`)
+	inputValueRegex = regexp.MustCompile(`(?is)(\bvalue\s*=\s*)("[^"]*"|'[^']*'|[^\s>]+)`)
+	// credentialLineRegex matches the lines of a YAML document, e.g. a kubeconfig, that
+	// are credentials
+	credentialLineRegex = regexp.MustCompile(`(?m)^(\s*-?\s*(?:token|password|client-key-data|id-token|refresh-token|access-token)\s*:\s*).+$`)
+)
+
+// Interaction is a request and its response that's been recorded. The credentials in
+// the headers, query parameters and bodies are redacted.
+type Interaction struct {
+	Request  RecordedRequest  `json:"request"`
+	Response RecordedResponse `json:"response"`
+}
+
+// RecordedRequest is the sanitized request of an interaction
+type RecordedRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers,omitempty"`
+	Body    string            `json:"body,omitempty"`
+}
+
+// RecordedResponse is the sanitized response of an interaction
+type RecordedResponse struct {
+	StatusCode int               `json:"statusCode"`
+	Headers    map[string]string `json:"headers,omitempty"`
+	Body       string            `json:"body,omitempty"`
+}
+
+// EnableRecording will save every request, and its response, sent with a client created
+// by NewHTTPClient or wrapped with RecordTransport to a file in the directory
+func EnableRecording(dir string) error {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	if recorder != nil && recorder.replay {
+		return ErrRecordAndReplay
+	}
+
+	if err := os.MkdirAll(dir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating recording directory %s: %w", dir, err)
+	}
+	recorder = &interactionRecorder{dir: dir}
+
+	return nil
+}
+
+// EnableReplay will respond to every request sent with a client created by NewHTTPClient
+// or wrapped with RecordTransport with the responses recorded in the directory. Requests
+// are matched using their method and sanitized url, requests that match more than one
+// recording get the responses in the order they were recorded.
+func EnableReplay(dir string) error {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	if recorder != nil && !recorder.replay {
+		return ErrRecordAndReplay
+	}
+
+	interactions, err := loadInteractions(dir)
+	if err != nil {
+		return err
+	}
+	if len(interactions) == 0 {
+		return fmt.Errorf("replaying from %s: %w", dir, ErrNoRecordedResponses)
+	}
+
+	recorder = &interactionRecorder{
+		dir:       dir,
+		replay:    true,
+		responses: make(map[string][]*Interaction),
+	}
+	for _, interaction := range interactions {
+		key := replayKey(interaction.Request.Method, interaction.Request.URL)
+		recorder.responses[key] = append(recorder.responses[key], interaction)
+	}
+
+	return nil
+}
+
+// DisableRecording will stop recording or replaying requests
+func DisableRecording() {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+	recorder = nil
+}
+
+// RecordingEnabled returns true if requests are being recorded or replayed, or are
+// refused because kconnect is offline
+func RecordingEnabled() bool {
+	return currentRecorder() != nil || OfflineEnabled()
+}
+
+// RecordTransport wraps the transport so that requests are recorded or replayed when
+// that's enabled
+func RecordTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &recordTransport{next: next}
+}
+
+func currentRecorder() *interactionRecorder {
+	recorderMu.Lock()
+	defer recorderMu.Unlock()
+
+	return recorder
+}
+
+// recordClient wraps the transport of the client so that requests are recorded or
+// replayed. Each attempt of a retried request is recorded.
+func recordClient(c *http.Client) {
+	if retry, ok := c.Transport.(*retryTransport); ok {
+		retry.next = RecordTransport(retry.next)
+		return
+	}
+	c.Transport = RecordTransport(c.Transport)
+}
+
+type recordTransport struct {
+	next http.RoundTripper
+}
+
+func (t *recordTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := currentRecorder()
+	if r != nil && r.replay {
+		return r.respond(req)
+	}
+	if OfflineEnabled() {
+		return nil, refuseRequest(req)
+	}
+	if r == nil {
+		return t.next.RoundTrip(req)
+	}
+
+	reqBody, err := readRequestBody(req)
+	if err != nil {
+		return nil, err
+	}
+	resp, err := t.next.RoundTrip(req)
+	if err != nil {
+		return nil, err
+	}
+
+	respBody, err := ioutil.ReadAll(resp.Body)
+	resp.Body.Close() //nolint: errcheck
+	if err != nil {
+		return nil, fmt.Errorf("reading response body: %w", err)
+	}
+	resp.Body = ioutil.NopCloser(bytes.NewReader(respBody))
+
+	interaction := &Interaction{
+		Request: RecordedRequest{
+			Method:  req.Method,
+			URL:     SanitizeURL(req.URL),
+			Headers: sanitizeHeaders(req.Header),
+			Body:    sanitizeBody(req.Header.Get("Content-Type"), reqBody),
+		},
+		Response: RecordedResponse{
+			StatusCode: resp.StatusCode,
+			Headers:    sanitizeHeaders(resp.Header),
+			Body:       sanitizeBody(resp.Header.Get("Content-Type"), respBody),
+		},
+	}
+	if err := r.save(interaction); err != nil {
+		logger().Warnw("failed recording http interaction", "url", interaction.Request.URL, "error", err.Error())
+	}
+
+	return resp, nil
+}
+
+type interactionRecorder struct {
+	dir    string
+	replay bool
+
+	mu        sync.Mutex
+	count     int
+	responses map[string][]*Interaction
+}
+
+// save writes the interaction to the next numbered file so the order is kept
+func (r *interactionRecorder) save(interaction *Interaction) error {
+	r.mu.Lock()
+	r.count++
+	path := filepath.Join(r.dir, fmt.Sprintf("%04d.json", r.count))
+	r.mu.Unlock()
+
+	data, err := json.MarshalIndent(interaction, "", "  ")
+	if err != nil {
+		return fmt.Errorf("marshalling interaction: %w", err)
+	}
+	if err := ioutil.WriteFile(path, data, 0600); err != nil {
+		return fmt.Errorf("writing interaction file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// respond returns the next recorded response for the request
+func (r *interactionRecorder) respond(req *http.Request) (*http.Response, error) {
+	sanitizedURL := SanitizeURL(req.URL)
+	key := replayKey(req.Method, sanitizedURL)
+
+	r.mu.Lock()
+	recorded := r.responses[key]
+	if len(recorded) == 0 {
+		r.mu.Unlock()
+		return nil, fmt.Errorf("replaying %s %s: %w", req.Method, sanitizedURL, ErrNoRecordedResponse)
+	}
+	interaction := recorded[0]
+	r.responses[key] = recorded[1:]
+	r.mu.Unlock()
+
+	header := http.Header{}
+	for name, value := range interaction.Response.Headers {
+		header.Set(name, value)
+	}
+
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", interaction.Response.StatusCode, http.StatusText(interaction.Response.StatusCode)),
+		StatusCode:    interaction.Response.StatusCode,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        header,
+		Body:          ioutil.NopCloser(strings.NewReader(interaction.Response.Body)),
+		ContentLength: int64(len(interaction.Response.Body)),
+		Request:       req,
+	}, nil
+}
+
+func loadInteractions(dir string) ([]*Interaction, error) {
+	paths, err := filepath.Glob(filepath.Join(dir, "*.json"))
+	if err != nil {
+		return nil, fmt.Errorf("listing recordings in %s: %w", dir, err)
+	}
+	sort.Strings(paths)
+
+	interactions := []*Interaction{}
+	for _, path := range paths {
+		data, err := ioutil.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("reading recording %s: %w", path, err)
+		}
+		interaction := &Interaction{}
+		if err := json.Unmarshal(data, interaction); err != nil {
+			return nil, fmt.Errorf("unmarshalling recording %s: %w", path, err)
+		}
+		interactions = append(interactions, interaction)
+	}
+
+	return interactions, nil
+}
+
+func replayKey(method, sanitizedURL string) string {
+	return method + " " + sanitizedURL
+}
+
+// readRequestBody reads the body of the request and replaces it so it can still be sent
+func readRequestBody(req *http.Request) ([]byte, error) {
+	if req.Body == nil {
+		return nil, nil
+	}
+	body, err := ioutil.ReadAll(req.Body)
+	req.Body.Close() //nolint: errcheck
+	if err != nil {
+		return nil, fmt.Errorf("reading request body: %w", err)
+	}
+	req.Body = ioutil.NopCloser(bytes.NewReader(body))
+
+	return body, nil
+}
+
+func sanitizeHeaders(header http.Header) map[string]string {
+	if len(header) == 0 {
+		return nil
+	}
+
+	sanitized := make(map[string]string)
+	for name := range header {
+		if isSensitiveHeader(name) {
+			sanitized[name] = redacted
+			continue
+		}
+		sanitized[name] = header.Get(name)
+	}
+
+	return sanitized
+}
+
+func isSensitiveHeader(name string) bool {
+	lower := strings.ToLower(name)
+	for _, sensitive := range sensitiveHeaders {
+		if strings.Contains(lower, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
+
+// sanitizeBody redacts the values in the body that look like credentials. JSON and form
+// bodies are redacted by name, XML bodies by element name, HTML bodies by form input name
+// and text bodies, e.g. YAML, by the lines that are credentials. Other bodies aren't
+// recorded as it isn't known what they contain.
+func sanitizeBody(contentType string, body []byte) string {
+	if len(body) == 0 {
+		return ""
+	}
+
+	switch {
+	case strings.Contains(contentType, "json"):
+		var value interface{}
+		if err := json.Unmarshal(body, &value); err != nil {
+			return redactLines(string(body))
+		}
+		data, err := json.Marshal(redactJSON(value))
+		if err != nil {
+			return redactLines(string(body))
+		}
+		return string(data)
+	case strings.Contains(contentType, "x-www-form-urlencoded"):
+		values, err := url.ParseQuery(string(body))
+		if err != nil {
+			return ""
+		}
+		for name := range values {
+			if isSensitiveQueryParam(name) {
+				values[name] = []string{redacted}
+			}
+		}
+		return values.Encode()
+	case strings.Contains(contentType, "html"):
+		return redactLines(redactHTMLInputs(string(body)))
+	case strings.Contains(contentType, "xml"):
+		return xmlElementRegex.ReplaceAllStringFunc(string(body), func(element string) string {
+			match := xmlElementRegex.FindStringSubmatch(element)
+			if match[1] != match[3] || !isSensitiveQueryParam(match[1]) {
+				return element
+			}
+			return fmt.Sprintf("<%s>%s</%s>", match[1], redacted, match[3])
+		})
+	case strings.Contains(contentType, "yaml"), strings.HasPrefix(contentType, "text/plain"):
+		return redactLines(string(body))
+	default:
+		return ""
+	}
+}
+
+// redactHTMLInputs redacts the values of the form inputs that are credentials, e.g. the
+// SAMLResponse in the page an IdP posts to the service provider
+func redactHTMLInputs(body string) string {
+	return htmlInputRegex.ReplaceAllStringFunc(body, func(input string) string {
+		name := inputNameRegex.FindStringSubmatch(input)
+		if name == nil || !isSensitiveInput(name[1]) {
+			return input
+		}
+		return inputValueRegex.ReplaceAllString(input, `${1}"`+redacted+`"`)
+	})
+}
+
+func isSensitiveInput(name string) bool {
+	lower := strings.ToLower(name)
+	for _, sensitive := range sensitiveInputs {
+		if strings.Contains(lower, sensitive) {
+			return true
+		}
+	}
+
+	return isSensitiveQueryParam(name)
+}
+
+// redactLines redacts the lines of the text that are credentials, e.g. the token of a
+// user in a kubeconfig
+func redactLines(text string) string {
+	return credentialLineRegex.ReplaceAllString(text, "${1}"+redacted)
+}
+
+func isSensitiveBodyField(name string) bool {
+	lower := strings.ToLower(name)
+	for _, sensitive := range sensitiveBodyFields {
+		if lower == sensitive {
+			return true
+		}
+	}
+
+	return isSensitiveQueryParam(name)
+}
+
+func redactJSON(value interface{}) interface{} {
+	switch v := value.(type) {
+	case map[string]interface{}:
+		for name, field := range v {
+			if _, isString := field.(string); isString && isSensitiveBodyField(name) {
+				v[name] = redacted
+				continue
+			}
+			v[name] = redactJSON(field)
+		}
+	case string:
+		return redactLines(v)
+	case []interface{}:
+		for i, item := range v {
+			v[i] = redactJSON(item)
+		}
+	}
+
+	return value
+}
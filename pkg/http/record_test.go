@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http_test
+
+import (
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	khttp "github.com/fidelity/kconnect/pkg/http"
+)
+
+func TestRecordAndReplay(t *testing.T) {
+	g := NewWithT(t)
+	defer khttp.DisableRecording()
+
+	dir := t.TempDir()
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+		w.Header().Set("Content-Type", "application/json")
+		w.Header().Set("Set-Cookie", "session=abc")
+		w.Write([]byte(`{"name":"cluster1","token":"secret-token","items":[{"password":"p"}]}`)) //nolint: errcheck
+	}))
+	defer server.Close()
+
+	g.Expect(khttp.EnableRecording(dir)).To(Succeed())
+	client := khttp.NewHTTPClient()
+	resp, err := client.Get(server.URL+"/clusters?access_token=abc", map[string]string{"Authorization": "Bearer abc"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Body()).To(ContainSubstring("secret-token"))
+
+	recorded, err := ioutil.ReadFile(filepath.Join(dir, "0001.json"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(recorded)).NotTo(ContainSubstring("secret-token"))
+	g.Expect(string(recorded)).NotTo(ContainSubstring("session=abc"))
+	g.Expect(string(recorded)).NotTo(ContainSubstring("Bearer abc"))
+	g.Expect(string(recorded)).NotTo(ContainSubstring(`\"p\"`))
+
+	khttp.DisableRecording()
+	g.Expect(khttp.EnableReplay(dir)).To(Succeed())
+	client = khttp.NewHTTPClient()
+	resp, err = client.Get(server.URL+"/clusters?access_token=def", nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(calls).To(Equal(1))
+	g.Expect(resp.ResponseCode()).To(Equal(http.StatusOK))
+	g.Expect(resp.Body()).To(ContainSubstring(`"name":"cluster1"`))
+
+	_, err = client.Get(server.URL+"/clusters?access_token=def", nil)
+	g.Expect(errors.Is(err, khttp.ErrNoRecordedResponse)).To(BeTrue())
+	g.Expect(errors.Is(khttp.EnableRecording(dir), khttp.ErrRecordAndReplay)).To(BeTrue())
+}
+
+func TestOffline(t *testing.T) {
+	g := NewWithT(t)
+	defer khttp.DisableOffline()
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	khttp.EnableOffline()
+	g.Expect(khttp.RecordingEnabled()).To(BeTrue())
+	client := khttp.NewHTTPClient()
+	_, err := client.Get(server.URL+"/clusters?access_token=abc", nil)
+	g.Expect(errors.Is(err, khttp.ErrOffline)).To(BeTrue())
+	g.Expect(calls).To(Equal(0))
+}
+
+func TestRecordRedactsBodies(t *testing.T) {
+	testCases := []struct {
+		name        string
+		contentType string
+		body        string
+		secrets     []string
+		kept        []string
+	}{
+		{
+			name:        "rancher kubeconfig",
+			contentType: "application/json",
+			body:        `{"type":"generateKubeconfigOutput","config":"apiVersion: v1\nkind: Config\nusers:\n- name: u\n  user:\n    token: kubeconfig-u-abc:secret\n"}`,
+			secrets:     []string{"kubeconfig-u-abc:secret"},
+			kept:        []string{"generateKubeconfigOutput"},
+		},
+		{
+			name:        "kubeconfig in another field",
+			contentType: "application/json",
+			body:        `{"data":{"value":"users:\n- name: u\n  user:\n    token: kubeconfig-u-abc:secret\n"}}`,
+			secrets:     []string{"kubeconfig-u-abc:secret"},
+			kept:        []string{`token: REDACTED`},
+		},
+		{
+			name:        "okta saml form",
+			contentType: "text/html; charset=utf-8",
+			body: `<html><body><form method="POST" action="https://signin.aws.amazon.com/saml">` +
+				`<input type="hidden" name="SAMLResponse" value="PHNhbWxwOlJlc3BvbnNlPg=="/>` +
+				`<input value='relay1' type="hidden" name="RelayState"/>` +
+				`<input type="hidden" name="other" value="visible"/></form></body></html>`,
+			secrets: []string{"PHNhbWxwOlJlc3BvbnNlPg==", "relay1"},
+			kept:    []string{`value=\"visible\"`, "signin.aws.amazon.com"},
+		},
+		{
+			name:        "unknown content type",
+			contentType: "application/octet-stream",
+			body:        "binary-secret",
+			secrets:     []string{"binary-secret"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			defer khttp.DisableRecording()
+
+			dir := t.TempDir()
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				w.Header().Set("Content-Type", tc.contentType) //nolint:scopelint
+				w.Write([]byte(tc.body))                       //nolint: errcheck,scopelint
+			}))
+			defer server.Close()
+
+			g.Expect(khttp.EnableRecording(dir)).To(Succeed())
+			_, err := khttp.NewHTTPClient().Get(server.URL, nil)
+			g.Expect(err).NotTo(HaveOccurred())
+
+			recorded, err := ioutil.ReadFile(filepath.Join(dir, "0001.json"))
+			g.Expect(err).NotTo(HaveOccurred())
+			for _, secret := range tc.secrets { //nolint:scopelint
+				g.Expect(string(recorded)).NotTo(ContainSubstring(secret))
+			}
+			for _, kept := range tc.kept { //nolint:scopelint
+				g.Expect(string(recorded)).To(ContainSubstring(kept))
+			}
+		})
+	}
+}
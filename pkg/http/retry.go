@@ -0,0 +1,227 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+const (
+	DefaultMaxRetries     = 3
+	DefaultInitialBackoff = 500 * time.Millisecond
+	DefaultMaxBackoff     = 30 * time.Second
+	DefaultRequestTimeout = 60 * time.Second
+)
+
+// RetryPolicy is how the requests of a client are retried when they fail
+type RetryPolicy struct {
+	// MaxRetries is how many times a request is retried, 0 disables retries
+	MaxRetries int
+	// InitialBackoff is the wait before the first retry, it doubles for each retry
+	InitialBackoff time.Duration
+	// MaxBackoff is the longest wait between retries, including waits from Retry-After
+	MaxBackoff time.Duration
+	// Timeout is the time limit of each attempt, 0 means no limit
+	Timeout time.Duration
+}
+
+// DefaultRetryPolicy returns the retry policy used if none is configured
+func DefaultRetryPolicy() *RetryPolicy {
+	return &RetryPolicy{
+		MaxRetries:     DefaultMaxRetries,
+		InitialBackoff: DefaultInitialBackoff,
+		MaxBackoff:     DefaultMaxBackoff,
+		Timeout:        DefaultRequestTimeout,
+	}
+}
+
+// WithRetryPolicy will retry requests that fail with a network error or a status code
+// that means the server is temporarily unavailable. The wait between retries backs off
+// exponentially with jitter and honors the Retry-After header.
+func WithRetryPolicy(policy *RetryPolicy) ClientOption {
+	return func(c *http.Client) {
+		next := c.Transport
+		if next == nil {
+			next = http.DefaultTransport
+		}
+		c.Transport = &retryTransport{
+			next:   next,
+			policy: policy,
+		}
+	}
+}
+
+type retryTransport struct {
+	next   http.RoundTripper
+	policy *RetryPolicy
+}
+
+func (t *retryTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	for attempt := 0; ; attempt++ {
+		attemptReq, cancel, err := t.attemptRequest(req, attempt)
+		if err != nil {
+			return nil, err
+		}
+
+		resp, err := t.next.RoundTrip(attemptReq)
+		if attempt >= t.policy.MaxRetries || !t.shouldRetry(req, resp, err) {
+			if err != nil {
+				cancel()
+				return nil, err
+			}
+			resp.Body = &cancelOnClose{ReadCloser: resp.Body, cancel: cancel}
+			return resp, nil
+		}
+
+		wait := t.backoff(attempt, resp)
+		if resp != nil {
+			io.Copy(ioutil.Discard, resp.Body) //nolint: errcheck
+			resp.Body.Close()
+		}
+		cancel()
+		logger().Debugw("retrying http request", "url", req.URL.String(), "attempt", attempt+1, "wait", wait.String())
+
+		select {
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		case <-time.After(wait):
+		}
+	}
+}
+
+// attemptRequest returns the request to send for the attempt with its own timeout. The
+// body is recreated for retries.
+func (t *retryTransport) attemptRequest(req *http.Request, attempt int) (*http.Request, context.CancelFunc, error) {
+	ctx, cancel := req.Context(), context.CancelFunc(func() {})
+	if t.policy.Timeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, t.policy.Timeout)
+	}
+
+	attemptReq := req.Clone(ctx)
+	if attempt > 0 && req.Body != nil && req.Body != http.NoBody {
+		body, err := req.GetBody()
+		if err != nil {
+			cancel()
+			return nil, nil, err
+		}
+		attemptReq.Body = body
+	}
+
+	return attemptReq, cancel, nil
+}
+
+// shouldRetry returns true if the request can be retried. Requests that aren't idempotent
+// are only retried if the server can't have processed them.
+func (t *retryTransport) shouldRetry(req *http.Request, resp *http.Response, err error) bool {
+	if req.Context().Err() != nil {
+		return false
+	}
+	if req.Body != nil && req.Body != http.NoBody && req.GetBody == nil {
+		return false
+	}
+	idempotent := isIdempotent(req.Method)
+
+	if err != nil {
+		return idempotent || isDialError(err)
+	}
+
+	switch resp.StatusCode {
+	case http.StatusTooManyRequests, http.StatusServiceUnavailable:
+		return true
+	case http.StatusBadGateway, http.StatusGatewayTimeout:
+		return idempotent
+	default:
+		return false
+	}
+}
+
+// backoff returns how long to wait before the next attempt
+func (t *retryTransport) backoff(attempt int, resp *http.Response) time.Duration {
+	wait := t.policy.InitialBackoff << uint(attempt)
+	if wait <= 0 || wait > t.policy.MaxBackoff {
+		wait = t.policy.MaxBackoff
+	}
+	// Full jitter on the upper half to spread the retries of several clients
+	if half := int64(wait / 2); half > 0 {
+		wait = time.Duration(half + rand.Int63n(half)) //nolint: gosec
+	}
+
+	if retryAfter := parseRetryAfter(resp); retryAfter > wait {
+		wait = retryAfter
+	}
+	if wait > t.policy.MaxBackoff {
+		wait = t.policy.MaxBackoff
+	}
+
+	return wait
+}
+
+// parseRetryAfter returns the wait from the Retry-After header, which is either a
+// number of seconds or a http date
+func parseRetryAfter(resp *http.Response) time.Duration {
+	if resp == nil {
+		return 0
+	}
+	value := resp.Header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if date, err := http.ParseTime(value); err == nil {
+		return time.Until(date)
+	}
+
+	return 0
+}
+
+func isIdempotent(method string) bool {
+	switch method {
+	case "", http.MethodGet, http.MethodHead, http.MethodOptions, http.MethodPut, http.MethodDelete:
+		return true
+	default:
+		return false
+	}
+}
+
+// isDialError returns true if the connection couldn't be made, so the request wasn't sent
+func isDialError(err error) bool {
+	var opErr *net.OpError
+	return errors.As(err, &opErr) && opErr.Op == "dial"
+}
+
+// cancelOnClose cancels the context of the attempt when the response body is closed
+type cancelOnClose struct {
+	io.ReadCloser
+	cancel context.CancelFunc
+}
+
+func (c *cancelOnClose) Close() error {
+	err := c.ReadCloser.Close()
+	c.cancel()
+
+	return err
+}
@@ -0,0 +1,162 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http_test
+
+import (
+	"context"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	khttp "github.com/fidelity/kconnect/pkg/http"
+)
+
+func testRetryPolicy() *khttp.RetryPolicy {
+	return &khttp.RetryPolicy{
+		MaxRetries:     2,
+		InitialBackoff: time.Millisecond,
+		MaxBackoff:     10 * time.Millisecond,
+		Timeout:        time.Second,
+	}
+}
+
+func TestRetryPolicy(t *testing.T) {
+	testCases := []struct {
+		name         string
+		method       string
+		statusCodes  []int
+		expectStatus int
+		expectCalls  int32
+	}{
+		{
+			name:         "retries until success",
+			method:       http.MethodGet,
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusBadGateway, http.StatusOK},
+			expectStatus: http.StatusOK,
+			expectCalls:  3,
+		},
+		{
+			name:         "gives up after max retries",
+			method:       http.MethodGet,
+			statusCodes:  []int{http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusServiceUnavailable, http.StatusOK},
+			expectStatus: http.StatusServiceUnavailable,
+			expectCalls:  3,
+		},
+		{
+			name:         "post retried when throttled",
+			method:       http.MethodPost,
+			statusCodes:  []int{http.StatusTooManyRequests, http.StatusCreated},
+			expectStatus: http.StatusCreated,
+			expectCalls:  2,
+		},
+		{
+			name:         "post not retried on bad gateway",
+			method:       http.MethodPost,
+			statusCodes:  []int{http.StatusBadGateway, http.StatusCreated},
+			expectStatus: http.StatusBadGateway,
+			expectCalls:  1,
+		},
+		{
+			name:         "client errors not retried",
+			method:       http.MethodGet,
+			statusCodes:  []int{http.StatusUnauthorized, http.StatusOK},
+			expectStatus: http.StatusUnauthorized,
+			expectCalls:  1,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			var calls int32
+			server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+				call := atomic.AddInt32(&calls, 1)
+				body, _ := ioutil.ReadAll(r.Body)
+				if r.Method == http.MethodPost && string(body) != "payload" {
+					w.WriteHeader(http.StatusBadRequest)
+					return
+				}
+				w.WriteHeader(tc.statusCodes[call-1]) //nolint:scopelint
+			}))
+			defer server.Close()
+
+			body := "payload"
+			client := khttp.NewHTTPClient(khttp.WithRetryPolicy(testRetryPolicy()))
+			resp, err := client.Do(&khttp.ClientRequest{
+				URL:    server.URL,
+				Method: tc.method, //nolint:scopelint
+				Body:   &body,
+			})
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(resp.ResponseCode()).To(Equal(tc.expectStatus))     //nolint:scopelint
+			g.Expect(atomic.LoadInt32(&calls)).To(Equal(tc.expectCalls)) //nolint:scopelint
+		})
+	}
+}
+
+func TestRetryPolicyTimeout(t *testing.T) {
+	g := NewWithT(t)
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) == 1 {
+			time.Sleep(200 * time.Millisecond)
+		}
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.Timeout = 50 * time.Millisecond
+	resp, err := khttp.NewHTTPClient(khttp.WithRetryPolicy(policy)).Get(server.URL, nil)
+
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.ResponseCode()).To(Equal(http.StatusOK))
+	g.Expect(atomic.LoadInt32(&calls)).To(Equal(int32(2)))
+}
+
+func TestRetryPolicyContextCancelled(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Retry-After", "1")
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	policy := testRetryPolicy()
+	policy.MaxBackoff = time.Minute
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+
+	start := time.Now()
+	_, err := khttp.NewHTTPClient(khttp.WithRetryPolicy(policy)).Do(&khttp.ClientRequest{
+		URL:     server.URL,
+		Method:  http.MethodGet,
+		Context: ctx,
+	})
+
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(time.Since(start)).To(BeNumerically("<", time.Second))
+}
@@ -0,0 +1,153 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package http
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+const redacted = "REDACTED"
+
+var (
+	tracingEnabled int32
+
+	// correlationHeaders are the response headers that identify a request to the
+	// provider, these can be given to the provider's support when a request fails
+	correlationHeaders = []string{
+		"X-Request-Id",
+		"X-Correlation-Id",
+		"X-Ms-Request-Id",
+		"X-Ms-Correlation-Request-Id",
+		"X-Ms-Client-Request-Id",
+		"X-Amzn-Requestid",
+		"X-Amz-Request-Id",
+		"X-Amz-Id-2",
+	}
+
+	// sensitiveQueryParams are the parts of query parameter names that mean the value
+	// is a credential
+	sensitiveQueryParams = []string{
+		"token",
+		"password",
+		"secret",
+		"code",
+		"assertion",
+		"samlresponse",
+		"signature",
+		"credential",
+		"key",
+	}
+)
+
+// EnableTracing will log the metadata of every request sent with a client created by
+// NewHTTPClient or wrapped with TraceTransport
+func EnableTracing() {
+	atomic.StoreInt32(&tracingEnabled, 1)
+}
+
+// TracingEnabled returns true if requests are being traced
+func TracingEnabled() bool {
+	return atomic.LoadInt32(&tracingEnabled) == 1
+}
+
+// TraceTransport wraps the transport so that the method, url, status, latency and
+// correlation ids of each request are logged when tracing is enabled. The bodies and
+// headers of the requests, which can contain credentials, are never logged.
+func TraceTransport(next http.RoundTripper) http.RoundTripper {
+	if next == nil {
+		next = http.DefaultTransport
+	}
+	return &traceTransport{next: next}
+}
+
+// TraceRequest sends the request with the send function and traces it, this is for
+// clients that aren't based on a http.RoundTripper
+func TraceRequest(req *http.Request, send func(*http.Request) (*http.Response, error)) (*http.Response, error) {
+	if !TracingEnabled() {
+		return send(req)
+	}
+
+	start := time.Now()
+	resp, err := send(req)
+	latency := time.Since(start)
+
+	fields := []interface{}{
+		"method", req.Method,
+		"url", SanitizeURL(req.URL),
+		"latency", latency.String(),
+	}
+	if err != nil {
+		fields = append(fields, "error", err.Error())
+		logger().Infow("http trace", fields...)
+		return resp, err
+	}
+
+	fields = append(fields, "status", resp.StatusCode)
+	for _, header := range correlationHeaders {
+		if value := resp.Header.Get(header); value != "" {
+			fields = append(fields, strings.ToLower(header), value)
+		}
+	}
+	logger().Infow("http trace", fields...)
+
+	return resp, nil
+}
+
+type traceTransport struct {
+	next http.RoundTripper
+}
+
+func (t *traceTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return TraceRequest(req, t.next.RoundTrip)
+}
+
+// SanitizeURL returns the url without the user info and with the values of query
+// parameters that look like credentials redacted
+func SanitizeURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	sanitized := *u
+	sanitized.User = nil
+	sanitized.Fragment = ""
+	sanitized.RawFragment = ""
+
+	query := sanitized.Query()
+	for name := range query {
+		if isSensitiveQueryParam(name) {
+			query[name] = []string{redacted}
+		}
+	}
+	sanitized.RawQuery = query.Encode()
+
+	return sanitized.String()
+}
+
+func isSensitiveQueryParam(name string) bool {
+	lower := strings.ToLower(name)
+	for _, sensitive := range sensitiveQueryParams {
+		if strings.Contains(lower, sensitive) {
+			return true
+		}
+	}
+
+	return false
+}
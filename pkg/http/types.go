@@ -16,6 +16,8 @@ limitations under the License.
 
 package http
 
+import "context"
+
 const (
 	StatusCodeOK = 200
 )
@@ -33,6 +35,8 @@ type ClientRequest struct {
 	Body    *string
 	Method  string
 	Headers map[string]string
+	// Context is used to cancel the request, including waits between retries
+	Context context.Context
 }
 
 // ClientResponse represents a http client response
@@ -0,0 +1,92 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectivity
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"time"
+)
+
+const httpsPort = "443"
+
+var (
+	ErrEndpointNotFound   = errors.New("the API server endpoint doesn't exist")
+	ErrCertificateChanged = errors.New("the API server certificate isn't signed by the certificate authority")
+	ErrInvalidCA          = errors.New("no certificates found in the certificate authority data")
+)
+
+// CheckEndpoint connects to the API server and checks that its certificate is signed by
+// the certificate authority (in PEM format). Only the TLS handshake is done, so no
+// requests or credentials are sent. ErrEndpointNotFound is returned if the host of the
+// endpoint doesn't resolve and ErrCertificateChanged if the certificate isn't trusted,
+// e.g. because the cluster has been rebuilt. Other errors, such as the API server not
+// being reachable from this network, are returned as they are.
+func CheckEndpoint(ctx context.Context, endpoint string, caData []byte, timeout time.Duration) error {
+	serverURL, err := url.Parse(endpoint)
+	if err != nil {
+		return fmt.Errorf("parsing endpoint %s: %w", endpoint, err)
+	}
+	port := serverURL.Port()
+	if port == "" {
+		port = httpsPort
+	}
+
+	roots := x509.NewCertPool()
+	if !roots.AppendCertsFromPEM(caData) {
+		return ErrInvalidCA
+	}
+
+	dialer := &tls.Dialer{
+		NetDialer: &net.Dialer{Timeout: timeout},
+		Config: &tls.Config{
+			RootCAs:    roots,
+			ServerName: serverURL.Hostname(),
+			MinVersion: tls.VersionTLS12,
+		},
+	}
+	ctx, cancel := context.WithTimeout(ctx, timeout)
+	defer cancel()
+
+	conn, err := dialer.DialContext(ctx, "tcp", net.JoinHostPort(serverURL.Hostname(), port))
+	if err != nil {
+		return classifyDialError(endpoint, err)
+	}
+
+	return conn.Close()
+}
+
+func classifyDialError(endpoint string, err error) error {
+	var dnsErr *net.DNSError
+	if errors.As(err, &dnsErr) && dnsErr.IsNotFound {
+		return fmt.Errorf("resolving %s: %w", endpoint, ErrEndpointNotFound)
+	}
+
+	var unknownAuthority x509.UnknownAuthorityError
+	var hostname x509.HostnameError
+	var invalid x509.CertificateInvalidError
+	if errors.As(err, &unknownAuthority) || errors.As(err, &hostname) || errors.As(err, &invalid) {
+		return fmt.Errorf("verifying certificate of %s: %w", endpoint, ErrCertificateChanged)
+	}
+
+	return fmt.Errorf("connecting to %s: %w", endpoint, err)
+}
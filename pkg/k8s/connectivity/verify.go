@@ -0,0 +1,234 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectivity
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const (
+	versionPath      = "/version"
+	accessReviewPath = "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews"
+
+	// DefaultTimeout is the time limit for the requests to the cluster, this includes
+	// getting the credentials from an exec plugin
+	DefaultTimeout = 15 * time.Second
+)
+
+var (
+	ErrUnauthenticated = errors.New("the credentials were rejected by the cluster")
+	ErrUnexpectedCode  = errors.New("unexpected response code")
+)
+
+// Result is the result of verifying the connection to a cluster
+type Result struct {
+	// Reachable is true if the API server responded
+	Reachable bool
+	// Authenticated is true if the API server accepted the credentials
+	Authenticated bool
+	// ServerVersion is the git version of the API server
+	ServerVersion string
+	// CanListPods is true if the identity can list pods in the namespace of the context
+	CanListPods bool
+	// Err is the reason the verification failed
+	Err error
+}
+
+type versionInfo struct {
+	GitVersion string `json:"gitVersion"`
+}
+
+type accessReview struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Spec       accessReviewSpec   `json:"spec"`
+	Status     accessReviewStatus `json:"status,omitempty"`
+}
+
+type accessReviewSpec struct {
+	ResourceAttributes accessReviewAttributes `json:"resourceAttributes"`
+}
+
+type accessReviewAttributes struct {
+	Namespace string `json:"namespace,omitempty"`
+	Verb      string `json:"verb"`
+	Resource  string `json:"resource"`
+}
+
+type accessReviewStatus struct {
+	Allowed bool `json:"allowed"`
+}
+
+// Verify will connect to the cluster of the context with the credentials in the
+// kubeconfig. It gets the server version and then checks the credentials by asking
+// the cluster if the identity can list pods.
+func Verify(ctx context.Context, cfg *api.Config, contextName string, timeout time.Duration) *Result {
+	result := &Result{}
+
+	restConfig, err := clientcmd.NewNonInteractiveClientConfig(*cfg, contextName, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+	if err != nil {
+		result.Err = fmt.Errorf("creating client config: %w", err)
+		return result
+	}
+	transport, err := rest.TransportFor(restConfig)
+	if err != nil {
+		result.Err = fmt.Errorf("creating transport: %w", err)
+		return result
+	}
+	client := &http.Client{Transport: transport, Timeout: timeout}
+	host := strings.TrimSuffix(restConfig.Host, "/")
+
+	version, code, err := getVersion(ctx, client, host)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Reachable = true
+	if code == http.StatusUnauthorized {
+		result.Err = ErrUnauthenticated
+		return result
+	}
+	result.ServerVersion = version
+
+	namespace := ""
+	if kubeContext, ok := cfg.Contexts[contextName]; ok {
+		namespace = kubeContext.Namespace
+	}
+	allowed, err := reviewAccess(ctx, client, host, namespace)
+	if err != nil {
+		result.Err = err
+		return result
+	}
+	result.Authenticated = true
+	result.CanListPods = allowed
+
+	return result
+}
+
+// ServerVersion gets the git version of the API server of the context without any
+// credentials, so that exec plugins and identity providers aren't used. An empty
+// version is returned if the cluster doesn't allow anonymous requests for it.
+func ServerVersion(ctx context.Context, cfg *api.Config, contextName string, timeout time.Duration) (string, error) {
+	anonymousCfg := cfg.DeepCopy()
+	if kubeContext, ok := anonymousCfg.Contexts[contextName]; ok {
+		kubeContext.AuthInfo = ""
+	}
+	restConfig, err := clientcmd.NewNonInteractiveClientConfig(*anonymousCfg, contextName, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+	if err != nil {
+		return "", fmt.Errorf("creating client config: %w", err)
+	}
+	transport, err := rest.TransportFor(restConfig)
+	if err != nil {
+		return "", fmt.Errorf("creating transport: %w", err)
+	}
+	client := &http.Client{Transport: transport, Timeout: timeout}
+
+	version, _, err := getVersion(ctx, client, strings.TrimSuffix(restConfig.Host, "/"))
+
+	return version, err
+}
+
+func getVersion(ctx context.Context, client *http.Client, host string) (string, int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, host+versionPath, nil)
+	if err != nil {
+		return "", 0, fmt.Errorf("creating version request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return "", 0, fmt.Errorf("requesting server version: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK:
+	case http.StatusUnauthorized:
+		return "", resp.StatusCode, nil
+	case http.StatusForbidden:
+		// The version can be hidden from the identity, the access review still
+		// shows if the credentials work
+		return "", resp.StatusCode, nil
+	default:
+		return "", resp.StatusCode, fmt.Errorf("getting server version %d: %w", resp.StatusCode, ErrUnexpectedCode)
+	}
+
+	info := &versionInfo{}
+	if err := json.NewDecoder(resp.Body).Decode(info); err != nil {
+		return "", resp.StatusCode, fmt.Errorf("decoding server version: %w", err)
+	}
+
+	return info.GitVersion, resp.StatusCode, nil
+}
+
+func reviewAccess(ctx context.Context, client *http.Client, host, namespace string) (bool, error) {
+	review := &accessReview{
+		APIVersion: "authorization.k8s.io/v1",
+		Kind:       "SelfSubjectAccessReview",
+		Spec: accessReviewSpec{
+			ResourceAttributes: accessReviewAttributes{
+				Namespace: namespace,
+				Verb:      "list",
+				Resource:  "pods",
+			},
+		},
+	}
+	body, err := json.Marshal(review)
+	if err != nil {
+		return false, fmt.Errorf("marshalling access review: %w", err)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, host+accessReviewPath, bytes.NewReader(body))
+	if err != nil {
+		return false, fmt.Errorf("creating access review request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return false, fmt.Errorf("requesting access review: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusOK, http.StatusCreated:
+	case http.StatusUnauthorized:
+		return false, ErrUnauthenticated
+	case http.StatusForbidden:
+		// The credentials were accepted but the identity can't create access reviews
+		return false, nil
+	default:
+		data, _ := ioutil.ReadAll(resp.Body)
+		return false, fmt.Errorf("access review %d %s: %w", resp.StatusCode, strings.TrimSpace(string(data)), ErrUnexpectedCode)
+	}
+
+	if err := json.NewDecoder(resp.Body).Decode(review); err != nil {
+		return false, fmt.Errorf("decoding access review: %w", err)
+	}
+
+	return review.Status.Allowed, nil
+}
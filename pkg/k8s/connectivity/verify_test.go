@@ -0,0 +1,180 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package connectivity_test
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/json"
+	"encoding/pem"
+	"errors"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/k8s/connectivity"
+)
+
+func testKubeconfig(server, token string) *api.Config {
+	cfg := api.NewConfig()
+	cfg.Clusters["cluster1"] = &api.Cluster{Server: server, InsecureSkipTLSVerify: true}
+	cfg.AuthInfos["user1"] = &api.AuthInfo{Token: token}
+	cfg.Contexts["context1"] = &api.Context{Cluster: "cluster1", AuthInfo: "user1", Namespace: "team1"}
+
+	return cfg
+}
+
+func TestVerify(t *testing.T) {
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer valid" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		switch r.URL.Path {
+		case "/version":
+			json.NewEncoder(w).Encode(map[string]string{"gitVersion": "v1.21.2"}) //nolint: errcheck
+		case "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews":
+			review := map[string]interface{}{}
+			json.NewDecoder(r.Body).Decode(&review) //nolint: errcheck
+			spec := review["spec"].(map[string]interface{})["resourceAttributes"].(map[string]interface{})
+			review["status"] = map[string]interface{}{"allowed": spec["namespace"] == "team1"}
+			w.WriteHeader(http.StatusCreated)
+			json.NewEncoder(w).Encode(review) //nolint: errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	testCases := []struct {
+		name     string
+		server   string
+		token    string
+		expected connectivity.Result
+		errMatch error
+	}{
+		{
+			name:     "valid credentials",
+			server:   server.URL,
+			token:    "valid",
+			expected: connectivity.Result{Reachable: true, Authenticated: true, ServerVersion: "v1.21.2", CanListPods: true},
+		},
+		{
+			name:     "rejected credentials",
+			server:   server.URL,
+			token:    "expired",
+			expected: connectivity.Result{Reachable: true},
+			errMatch: connectivity.ErrUnauthenticated,
+		},
+		{
+			name:     "unreachable",
+			server:   "https://127.0.0.1:1",
+			token:    "valid",
+			expected: connectivity.Result{},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			expected := tc.expected //nolint:scopelint
+			errMatch := tc.errMatch //nolint:scopelint
+
+			result := connectivity.Verify(context.Background(), testKubeconfig(tc.server, tc.token), "context1", 5*time.Second) //nolint:scopelint
+			g.Expect(result.Reachable).To(Equal(expected.Reachable))
+			g.Expect(result.Authenticated).To(Equal(expected.Authenticated))
+			g.Expect(result.ServerVersion).To(Equal(expected.ServerVersion))
+			g.Expect(result.CanListPods).To(Equal(expected.CanListPods))
+			if expected.Authenticated {
+				g.Expect(result.Err).NotTo(HaveOccurred())
+				return
+			}
+			g.Expect(result.Err).To(HaveOccurred())
+			if errMatch != nil {
+				g.Expect(result.Err).To(MatchError(errMatch))
+			}
+		})
+	}
+}
+
+func TestServerVersion(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "" {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]string{"gitVersion": "v1.23.4"}) //nolint: errcheck
+	}))
+	defer server.Close()
+
+	cfg := testKubeconfig(server.URL, "valid")
+	cfg.AuthInfos["user1"].Exec = &api.ExecConfig{Command: "does-not-exist"}
+
+	version, err := connectivity.ServerVersion(context.Background(), cfg, "context1", time.Second)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(version).To(Equal("v1.23.4"))
+}
+
+func TestCheckEndpoint(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {}))
+	defer server.Close()
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+
+	g.Expect(connectivity.CheckEndpoint(context.Background(), server.URL, caData, time.Second)).To(Succeed())
+
+	rebuiltCA := testCA(t)
+	err := connectivity.CheckEndpoint(context.Background(), server.URL, rebuiltCA, time.Second)
+	g.Expect(errors.Is(err, connectivity.ErrCertificateChanged)).To(BeTrue())
+
+	err = connectivity.CheckEndpoint(context.Background(), server.URL, []byte("not a certificate"), time.Second)
+	g.Expect(errors.Is(err, connectivity.ErrInvalidCA)).To(BeTrue())
+}
+
+func testCA(t *testing.T) []byte {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "kubernetes"},
+		NotBefore:             time.Now(),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der})
+}
@@ -0,0 +1,79 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"bytes"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"os"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+var (
+	ErrContextNotFound  = errors.New("context not found in kubeconfig")
+	ErrClusterNotFound  = errors.New("cluster not found in kubeconfig")
+	ErrNoCACertificates = errors.New("no pem encoded certificates found")
+	ErrNoExecCredential = errors.New("exec plugin returned no credentials")
+	ErrUserNotFound     = errors.New("user not found in kubeconfig")
+	ErrNoCredentials    = errors.New("user has no credentials in kubeconfig")
+)
+
+// AddCertificateAuthority will add the certificates in the PEM file to the certificate
+// authority data of the cluster of the context. The existing certificate authority of the
+// cluster is kept so that both are trusted.
+func AddCertificateAuthority(cfg *api.Config, contextName, caFile string) error {
+	caData, err := os.ReadFile(caFile)
+	if err != nil {
+		return fmt.Errorf("reading ca certificate file %s: %w", caFile, err)
+	}
+	if !x509.NewCertPool().AppendCertsFromPEM(caData) {
+		return fmt.Errorf("loading ca certificate file %s: %w", caFile, ErrNoCACertificates)
+	}
+
+	kubeContext, ok := cfg.Contexts[contextName]
+	if !ok {
+		return fmt.Errorf("context %s: %w", contextName, ErrContextNotFound)
+	}
+	cluster, ok := cfg.Clusters[kubeContext.Cluster]
+	if !ok {
+		return fmt.Errorf("cluster %s: %w", kubeContext.Cluster, ErrClusterNotFound)
+	}
+
+	// The data and the file can't both be set so the file is embedded
+	existing := cluster.CertificateAuthorityData
+	if cluster.CertificateAuthority != "" {
+		existing, err = os.ReadFile(cluster.CertificateAuthority)
+		if err != nil {
+			return fmt.Errorf("reading cluster certificate authority %s: %w", cluster.CertificateAuthority, err)
+		}
+		cluster.CertificateAuthority = ""
+	}
+
+	if !bytes.Contains(existing, bytes.TrimSpace(caData)) {
+		if len(existing) > 0 && !bytes.HasSuffix(existing, []byte("\n")) {
+			existing = append(existing, '\n')
+		}
+		existing = append(existing, caData...)
+	}
+	cluster.CertificateAuthorityData = existing
+	cluster.InsecureSkipTLSVerify = false
+
+	return nil
+}
@@ -0,0 +1,57 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig_test
+
+import (
+	"bytes"
+	"encoding/pem"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+)
+
+func TestAddCertificateAuthority(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewTLSServer(nil)
+	defer server.Close()
+	caData := pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: server.Certificate().Raw})
+	caFile := filepath.Join(t.TempDir(), "ca.pem")
+	g.Expect(os.WriteFile(caFile, caData, 0600)).To(Succeed())
+
+	existingCA := []byte("-----BEGIN CERTIFICATE-----\nexisting\n-----END CERTIFICATE-----")
+	cfg := api.NewConfig()
+	cfg.Clusters["cluster1"] = &api.Cluster{Server: "https://cluster1", CertificateAuthorityData: existingCA}
+	cfg.Contexts["context1"] = &api.Context{Cluster: "cluster1"}
+
+	g.Expect(kubeconfig.AddCertificateAuthority(cfg, "context1", caFile)).To(Succeed())
+	data := cfg.Clusters["cluster1"].CertificateAuthorityData
+	g.Expect(bytes.HasPrefix(data, existingCA)).To(BeTrue())
+	g.Expect(bytes.Contains(data, caData)).To(BeTrue())
+
+	// Adding the same certificate again doesn't duplicate it
+	g.Expect(kubeconfig.AddCertificateAuthority(cfg, "context1", caFile)).To(Succeed())
+	g.Expect(cfg.Clusters["cluster1"].CertificateAuthorityData).To(Equal(data))
+
+	g.Expect(kubeconfig.AddCertificateAuthority(cfg, "missing", caFile)).To(MatchError(kubeconfig.ErrContextNotFound))
+}
@@ -0,0 +1,43 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// RenameContext will rename the context in the config. The current context is also
+// renamed if it's the context.
+func RenameContext(cfg *api.Config, from, to string) error {
+	kubeContext, ok := cfg.Contexts[from]
+	if !ok {
+		return fmt.Errorf("context %s: %w", from, ErrContextNotFound)
+	}
+	if from == to {
+		return nil
+	}
+
+	delete(cfg.Contexts, from)
+	cfg.Contexts[to] = kubeContext
+	if cfg.CurrentContext == from {
+		cfg.CurrentContext = to
+	}
+
+	return nil
+}
@@ -0,0 +1,42 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+)
+
+func TestRenameContext(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := api.NewConfig()
+	cfg.Contexts["context1"] = &api.Context{Cluster: "cluster1"}
+	cfg.CurrentContext = "context1"
+
+	g.Expect(kubeconfig.RenameContext(cfg, "context1", "ci")).To(Succeed())
+	g.Expect(cfg.Contexts).To(HaveKey("ci"))
+	g.Expect(cfg.Contexts).NotTo(HaveKey("context1"))
+	g.Expect(cfg.Contexts["ci"].Cluster).To(Equal("cluster1"))
+	g.Expect(cfg.CurrentContext).To(Equal("ci"))
+
+	g.Expect(kubeconfig.RenameContext(cfg, "missing", "ci")).To(MatchError(kubeconfig.ErrContextNotFound))
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"context"
+	"fmt"
+	"os"
+	"strings"
+
+	clientauthv1beta1 "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// ContextCredential returns the credentials of the user of the context. If the user
+// has an exec credential plugin it's run to get fresh credentials, otherwise the
+// token or client certificate in the kubeconfig is returned.
+func ContextCredential(ctx context.Context, cfg *api.Config, contextName string) (*clientauthv1beta1.ExecCredentialStatus, error) {
+	kubeContext, ok := cfg.Contexts[contextName]
+	if !ok {
+		return nil, fmt.Errorf("context %s: %w", contextName, ErrContextNotFound)
+	}
+	authInfo, ok := cfg.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return nil, fmt.Errorf("user %s: %w", kubeContext.AuthInfo, ErrUserNotFound)
+	}
+
+	if authInfo.Exec != nil {
+		status, err := runExecPlugin(ctx, authInfo.Exec)
+		if err != nil {
+			return nil, fmt.Errorf("getting credentials for user %s: %w", kubeContext.AuthInfo, err)
+		}
+		return status, nil
+	}
+
+	status := &clientauthv1beta1.ExecCredentialStatus{
+		Token:                 authInfo.Token,
+		ClientCertificateData: string(authInfo.ClientCertificateData),
+		ClientKeyData:         string(authInfo.ClientKeyData),
+	}
+	if status.Token == "" && authInfo.TokenFile != "" {
+		data, err := os.ReadFile(authInfo.TokenFile)
+		if err != nil {
+			return nil, fmt.Errorf("reading token file %s: %w", authInfo.TokenFile, err)
+		}
+		status.Token = strings.TrimSpace(string(data))
+	}
+	if status.Token == "" && status.ClientCertificateData == "" {
+		return nil, fmt.Errorf("user %s: %w", kubeContext.AuthInfo, ErrNoCredentials)
+	}
+
+	return status, nil
+}
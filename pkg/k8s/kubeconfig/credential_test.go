@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig_test
+
+import (
+	"context"
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+)
+
+func TestContextCredential(t *testing.T) {
+	g := NewWithT(t)
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	g.Expect(os.WriteFile(tokenFile, []byte("filetoken\n"), 0600)).To(Succeed())
+
+	cfg := api.NewConfig()
+	cfg.AuthInfos["exec"] = &api.AuthInfo{
+		Exec: &api.ExecConfig{
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+			Command:    "sh",
+			Args:       []string{"-c", `echo '{"kind":"ExecCredential","status":{"token":"exectoken","expirationTimestamp":"2030-01-02T03:04:05Z"}}'`},
+		},
+	}
+	cfg.AuthInfos["static"] = &api.AuthInfo{Token: "statictoken"}
+	cfg.AuthInfos["file"] = &api.AuthInfo{TokenFile: tokenFile}
+	cfg.AuthInfos["none"] = &api.AuthInfo{}
+	for _, name := range []string{"exec", "static", "file", "none", "missing"} {
+		cfg.Contexts[name] = &api.Context{Cluster: "cluster", AuthInfo: name}
+	}
+
+	status, err := kubeconfig.ContextCredential(context.Background(), cfg, "exec")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(status.Token).To(Equal("exectoken"))
+	g.Expect(status.ExpirationTimestamp).NotTo(BeNil())
+
+	status, err = kubeconfig.ContextCredential(context.Background(), cfg, "static")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(status.Token).To(Equal("statictoken"))
+	g.Expect(status.ExpirationTimestamp).To(BeNil())
+
+	status, err = kubeconfig.ContextCredential(context.Background(), cfg, "file")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(status.Token).To(Equal("filetoken"))
+
+	_, err = kubeconfig.ContextCredential(context.Background(), cfg, "none")
+	g.Expect(errors.Is(err, kubeconfig.ErrNoCredentials)).To(BeTrue())
+
+	_, err = kubeconfig.ContextCredential(context.Background(), cfg, "missing")
+	g.Expect(errors.Is(err, kubeconfig.ErrUserNotFound)).To(BeTrue())
+
+	_, err = kubeconfig.ContextCredential(context.Background(), cfg, "unknown")
+	g.Expect(errors.Is(err, kubeconfig.ErrContextNotFound)).To(BeTrue())
+}
@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"os"
+	"os/exec"
+	"time"
+
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+	clientauthv1beta1 "k8s.io/client-go/pkg/apis/clientauthentication/v1beta1"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+const execInfoEnv = "KUBERNETES_EXEC_INFO"
+
+// Minify returns a copy of the kubeconfig that only contains the named context
+// with its cluster and user. Any files the context refers to are embedded.
+func Minify(cfg *api.Config, contextName string) (*api.Config, error) {
+	minified := cfg.DeepCopy()
+	if contextName != "" {
+		minified.CurrentContext = contextName
+	}
+	if _, ok := minified.Contexts[minified.CurrentContext]; !ok {
+		return nil, fmt.Errorf("context %s: %w", minified.CurrentContext, ErrContextNotFound)
+	}
+
+	if err := api.MinifyConfig(minified); err != nil {
+		return nil, fmt.Errorf("minifying kubeconfig: %w", err)
+	}
+	if err := api.FlattenConfig(minified); err != nil {
+		return nil, fmt.Errorf("embedding kubeconfig files: %w", err)
+	}
+
+	return minified, nil
+}
+
+// ResolveExecCredentials runs the exec credential plugins of the users in the
+// kubeconfig and replaces them with the credentials they return. This allows the
+// kubeconfig to be used where the plugins and the identity provider aren't available.
+// The earliest expiry of the credentials is returned, or nil if they don't expire.
+func ResolveExecCredentials(ctx context.Context, cfg *api.Config) (*time.Time, error) {
+	var expiry *time.Time
+
+	for name, authInfo := range cfg.AuthInfos {
+		if authInfo.Exec == nil {
+			continue
+		}
+
+		status, err := runExecPlugin(ctx, authInfo.Exec)
+		if err != nil {
+			return nil, fmt.Errorf("getting credentials for user %s: %w", name, err)
+		}
+
+		authInfo.Exec = nil
+		authInfo.Token = status.Token
+		if status.ClientCertificateData != "" {
+			authInfo.ClientCertificateData = []byte(status.ClientCertificateData)
+			authInfo.ClientKeyData = []byte(status.ClientKeyData)
+		}
+
+		if status.ExpirationTimestamp != nil && (expiry == nil || status.ExpirationTimestamp.Time.Before(*expiry)) {
+			expires := status.ExpirationTimestamp.Time
+			expiry = &expires
+		}
+	}
+
+	return expiry, nil
+}
+
+func runExecPlugin(ctx context.Context, execConfig *api.ExecConfig) (*clientauthv1beta1.ExecCredentialStatus, error) {
+	execInfo, err := json.Marshal(&clientauthv1beta1.ExecCredential{
+		TypeMeta: metav1.TypeMeta{Kind: "ExecCredential", APIVersion: execConfig.APIVersion},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshalling exec info: %w", err)
+	}
+
+	cmd := exec.CommandContext(ctx, execConfig.Command, execConfig.Args...) //nolint: gosec
+	cmd.Env = os.Environ()
+	for _, env := range execConfig.Env {
+		cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", env.Name, env.Value))
+	}
+	cmd.Env = append(cmd.Env, fmt.Sprintf("%s=%s", execInfoEnv, execInfo))
+
+	stdout := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running exec plugin %s: %w", execConfig.Command, err)
+	}
+
+	credential := &clientauthv1beta1.ExecCredential{}
+	if err := json.Unmarshal(stdout.Bytes(), credential); err != nil {
+		return nil, fmt.Errorf("unmarshalling exec credential: %w", err)
+	}
+	if credential.Status == nil {
+		return nil, ErrNoExecCredential
+	}
+
+	return credential.Status, nil
+}
@@ -0,0 +1,91 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+)
+
+func TestMinify(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := api.NewConfig()
+	cfg.Clusters["cluster1"] = &api.Cluster{Server: "https://cluster1"}
+	cfg.Clusters["cluster2"] = &api.Cluster{Server: "https://cluster2"}
+	cfg.AuthInfos["user1"] = &api.AuthInfo{Token: "token1"}
+	cfg.AuthInfos["user2"] = &api.AuthInfo{Token: "token2"}
+	cfg.Contexts["context1"] = &api.Context{Cluster: "cluster1", AuthInfo: "user1"}
+	cfg.Contexts["context2"] = &api.Context{Cluster: "cluster2", AuthInfo: "user2"}
+	cfg.CurrentContext = "context1"
+
+	minified, err := kubeconfig.Minify(cfg, "context2")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(minified.CurrentContext).To(Equal("context2"))
+	g.Expect(minified.Contexts).To(HaveLen(1))
+	g.Expect(minified.Clusters).To(HaveKey("cluster2"))
+	g.Expect(minified.Clusters).To(HaveLen(1))
+	g.Expect(minified.AuthInfos).To(HaveKey("user2"))
+	g.Expect(minified.AuthInfos).To(HaveLen(1))
+	g.Expect(cfg.Contexts).To(HaveLen(2))
+
+	_, err = kubeconfig.Minify(cfg, "missing")
+	g.Expect(err).To(MatchError(kubeconfig.ErrContextNotFound))
+}
+
+func TestResolveExecCredentials(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := api.NewConfig()
+	cfg.AuthInfos["exec"] = &api.AuthInfo{
+		Exec: &api.ExecConfig{
+			APIVersion: "client.authentication.k8s.io/v1beta1",
+			Command:    "sh",
+			Args:       []string{"-c", `echo '{"kind":"ExecCredential","status":{"token":"'$TOKEN'","expirationTimestamp":"2030-01-02T03:04:05Z"}}'`},
+			Env:        []api.ExecEnvVar{{Name: "TOKEN", Value: "exectoken"}},
+		},
+	}
+	cfg.AuthInfos["static"] = &api.AuthInfo{Token: "statictoken"}
+
+	expiry, err := kubeconfig.ResolveExecCredentials(context.Background(), cfg)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cfg.AuthInfos["exec"].Exec).To(BeNil())
+	g.Expect(cfg.AuthInfos["exec"].Token).To(Equal("exectoken"))
+	g.Expect(cfg.AuthInfos["static"].Token).To(Equal("statictoken"))
+	g.Expect(expiry).NotTo(BeNil())
+	g.Expect(expiry.UTC().Format("2006-01-02T15:04:05Z")).To(Equal("2030-01-02T03:04:05Z"))
+}
+
+func TestResolveExecCredentialsNoStatus(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := api.NewConfig()
+	cfg.AuthInfos["exec"] = &api.AuthInfo{
+		Exec: &api.ExecConfig{
+			Command: "sh",
+			Args:    []string{"-c", `echo '{"kind":"ExecCredential"}'`},
+		},
+	}
+
+	_, err := kubeconfig.ResolveExecCredentials(context.Background(), cfg)
+	g.Expect(err).To(MatchError(ContainSubstring(kubeconfig.ErrNoExecCredential.Error())))
+}
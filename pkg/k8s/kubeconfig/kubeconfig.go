@@ -23,6 +23,8 @@ import (
 
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
 )
 
 // Write will write the kubeconfig to the specified file. If there
@@ -32,7 +34,7 @@ func Write(path string, clusterConfig *api.Config, merge, setCurrent bool) error
 
 	pathOptions := clientcmd.NewDefaultPathOptions()
 	if path != "" {
-		pathOptions.LoadingRules.ExplicitPath = path
+		pathOptions.LoadingRules.ExplicitPath = defaults.ExpandPath(path)
 	}
 	var newConfig *api.Config
 	if merge {
@@ -57,6 +59,9 @@ func Write(path string, clusterConfig *api.Config, merge, setCurrent bool) error
 	}
 
 	if setCurrent {
+		if startingConfig, err := pathOptions.GetStartingConfig(); err == nil {
+			recordPreviousContext(startingConfig.CurrentContext, clusterConfig.CurrentContext)
+		}
 		zap.S().Infow("setting current context", "context", clusterConfig.CurrentContext)
 		newConfig.CurrentContext = clusterConfig.CurrentContext
 	}
@@ -69,6 +74,32 @@ func Write(path string, clusterConfig *api.Config, merge, setCurrent bool) error
 	return nil
 }
 
+// SetCurrentContext will change the current context in the kubeconfig to the
+// named context. The context that was current is saved as the kubectx previous context.
+func SetCurrentContext(path, contextName string) error {
+	pathOptions := clientcmd.NewDefaultPathOptions()
+	if path != "" {
+		pathOptions.LoadingRules.ExplicitPath = path
+	}
+	existingConfig, err := pathOptions.GetStartingConfig()
+	if err != nil {
+		return fmt.Errorf("getting existing kubeconfig: %w", err)
+	}
+	if _, ok := existingConfig.Contexts[contextName]; !ok {
+		return fmt.Errorf("context %s: %w", contextName, ErrContextNotFound)
+	}
+
+	recordPreviousContext(existingConfig.CurrentContext, contextName)
+	zap.S().Infow("setting current context", "context", contextName)
+	existingConfig.CurrentContext = contextName
+
+	if err := clientcmd.ModifyConfig(pathOptions, *existingConfig, true); err != nil {
+		return fmt.Errorf("writing kubeconfig: %w", err)
+	}
+
+	return nil
+}
+
 func Read(path string) (*api.Config, error) {
 
 	pathOptions := clientcmd.NewDefaultPathOptions()
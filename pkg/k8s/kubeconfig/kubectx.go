@@ -0,0 +1,82 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// PreviousContextFile returns the location of the file kubectx uses to remember
+// the previous context, so that `kubectx -` and `kconnect ctx -` can be mixed
+func PreviousContextFile() (string, error) {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", fmt.Errorf("getting home directory: %w", err)
+	}
+
+	return filepath.Join(home, ".kube", "kubectx"), nil
+}
+
+// ReadPreviousContext will read the name of the previous context from the file. An
+// empty name is returned if there is no previous context.
+func ReadPreviousContext(path string) (string, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if errors.Is(err, os.ErrNotExist) {
+			return "", nil
+		}
+		return "", fmt.Errorf("reading previous context file %s: %w", path, err)
+	}
+
+	return strings.TrimSpace(string(data)), nil
+}
+
+// WritePreviousContext will write the name of the previous context to the file in
+// the same format as kubectx
+func WritePreviousContext(path, contextName string) error {
+	zap.S().Debugw("writing previous context", "path", path, "context", contextName)
+
+	if err := os.MkdirAll(filepath.Dir(path), 0700); err != nil {
+		return fmt.Errorf("creating directory for previous context file: %w", err)
+	}
+	if err := os.WriteFile(path, []byte(contextName), 0600); err != nil {
+		return fmt.Errorf("writing previous context file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+// recordPreviousContext will save the current context as the previous context when
+// it is about to change. Failing to save it doesn't stop the context changing.
+func recordPreviousContext(currentContext, newContext string) {
+	if currentContext == "" || currentContext == newContext {
+		return
+	}
+	path, err := PreviousContextFile()
+	if err == nil {
+		err = WritePreviousContext(path, currentContext)
+	}
+	if err != nil {
+		zap.S().Warnf("failed to save the previous context for kubectx: %s", err)
+	}
+}
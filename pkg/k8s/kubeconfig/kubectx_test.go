@@ -0,0 +1,61 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig_test
+
+import (
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+)
+
+func TestSetCurrentContext(t *testing.T) {
+	g := NewWithT(t)
+	t.Setenv("HOME", t.TempDir())
+
+	cfg := api.NewConfig()
+	cfg.Clusters["cluster1"] = &api.Cluster{Server: "https://cluster1"}
+	cfg.Contexts["context1"] = &api.Context{Cluster: "cluster1"}
+	cfg.Contexts["context2"] = &api.Context{Cluster: "cluster1"}
+	cfg.CurrentContext = "context1"
+	path := filepath.Join(t.TempDir(), "config")
+	g.Expect(clientcmd.WriteToFile(*cfg, path)).To(Succeed())
+
+	previousFile, err := kubeconfig.PreviousContextFile()
+	g.Expect(err).NotTo(HaveOccurred())
+	previous, err := kubeconfig.ReadPreviousContext(previousFile)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(previous).To(BeEmpty())
+
+	g.Expect(kubeconfig.SetCurrentContext(path, "context2")).To(Succeed())
+	current, err := kubeconfig.GetCurrentContext(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(current.Cluster).To(Equal("cluster1"))
+	written, err := clientcmd.LoadFromFile(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(written.CurrentContext).To(Equal("context2"))
+
+	previous, err = kubeconfig.ReadPreviousContext(previousFile)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(previous).To(Equal("context1"))
+
+	g.Expect(kubeconfig.SetCurrentContext(path, "missing")).To(MatchError(kubeconfig.ErrContextNotFound))
+}
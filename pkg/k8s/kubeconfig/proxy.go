@@ -0,0 +1,39 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig
+
+import (
+	"fmt"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+// SetProxyURL will set the proxy that kubectl connects to the cluster of the context
+// through, e.g. the SOCKS proxy of a SSH tunnel
+func SetProxyURL(cfg *api.Config, contextName, proxyURL string) error {
+	kubeContext, ok := cfg.Contexts[contextName]
+	if !ok {
+		return fmt.Errorf("context %s: %w", contextName, ErrContextNotFound)
+	}
+	cluster, ok := cfg.Clusters[kubeContext.Cluster]
+	if !ok {
+		return fmt.Errorf("cluster %s: %w", kubeContext.Cluster, ErrClusterNotFound)
+	}
+	cluster.ProxyURL = proxyURL
+
+	return nil
+}
@@ -0,0 +1,41 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package kubeconfig_test
+
+import (
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/k8s/kubeconfig"
+)
+
+func TestSetProxyURL(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := api.NewConfig()
+	cfg.Clusters["cluster1"] = &api.Cluster{Server: "https://cluster1"}
+	cfg.Contexts["context1"] = &api.Context{Cluster: "cluster1"}
+	cfg.Contexts["context2"] = &api.Context{Cluster: "missing"}
+
+	g.Expect(kubeconfig.SetProxyURL(cfg, "context1", "socks5://127.0.0.1:21080")).To(Succeed())
+	g.Expect(cfg.Clusters["cluster1"].ProxyURL).To(Equal("socks5://127.0.0.1:21080"))
+
+	g.Expect(kubeconfig.SetProxyURL(cfg, "missing", "socks5://127.0.0.1:21080")).To(MatchError(kubeconfig.ErrContextNotFound))
+	g.Expect(kubeconfig.SetProxyURL(cfg, "context2", "socks5://127.0.0.1:21080")).To(MatchError(kubeconfig.ErrClusterNotFound))
+}
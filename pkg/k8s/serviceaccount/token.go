@@ -0,0 +1,195 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"k8s.io/client-go/rest"
+	"k8s.io/client-go/tools/clientcmd"
+	"k8s.io/client-go/tools/clientcmd/api"
+)
+
+var (
+	ErrServiceAccountRequired = errors.New("service account is required")
+	ErrTokenRequestFailed     = errors.New("token request failed")
+	ErrContextNotFound        = errors.New("context not found in kubeconfig")
+	ErrClusterNotFound        = errors.New("cluster not found in kubeconfig")
+)
+
+// TokenRequestInput is the input to requesting a service account token
+type TokenRequestInput struct {
+	// Namespace is the namespace of the service account
+	Namespace string
+	// ServiceAccount is the name of the service account
+	ServiceAccount string
+	// Duration is how long the token is valid for, the cluster can choose a different duration
+	Duration time.Duration
+	// Audiences are the intended audiences of the token, the cluster's api server if empty
+	Audiences []string
+	// BoundSecret is the name of a secret in the namespace that the token is bound to, the
+	// token is invalidated when the secret is deleted
+	BoundSecret string
+}
+
+// Token is a service account token
+type Token struct {
+	Token  string
+	Expiry time.Time
+}
+
+type tokenRequest struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Spec       tokenRequestSpec   `json:"spec"`
+	Status     tokenRequestStatus `json:"status,omitempty"`
+}
+
+type tokenRequestSpec struct {
+	Audiences         []string        `json:"audiences"`
+	ExpirationSeconds *int64          `json:"expirationSeconds,omitempty"`
+	BoundObjectRef    *boundObjectRef `json:"boundObjectRef,omitempty"`
+}
+
+type boundObjectRef struct {
+	Kind       string `json:"kind"`
+	APIVersion string `json:"apiVersion"`
+	Name       string `json:"name"`
+}
+
+type tokenRequestStatus struct {
+	Token               string    `json:"token"`
+	ExpirationTimestamp time.Time `json:"expirationTimestamp"`
+}
+
+// RequestToken uses the TokenRequest api to create a short-lived token for the service
+// account. The request is made with the credentials of the context in the kubeconfig.
+func RequestToken(ctx context.Context, cfg *api.Config, contextName string, input *TokenRequestInput) (*Token, error) {
+	if input.ServiceAccount == "" {
+		return nil, ErrServiceAccountRequired
+	}
+	namespace := input.Namespace
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	restConfig, err := clientcmd.NewNonInteractiveClientConfig(*cfg, contextName, &clientcmd.ConfigOverrides{}, nil).ClientConfig()
+	if err != nil {
+		return nil, fmt.Errorf("creating client config: %w", err)
+	}
+	transport, err := rest.TransportFor(restConfig)
+	if err != nil {
+		return nil, fmt.Errorf("creating transport: %w", err)
+	}
+	client := &http.Client{Transport: transport}
+
+	request := &tokenRequest{
+		APIVersion: "authentication.k8s.io/v1",
+		Kind:       "TokenRequest",
+		Spec:       tokenRequestSpec{Audiences: input.Audiences},
+	}
+	if input.Duration > 0 {
+		seconds := int64(input.Duration.Seconds())
+		request.Spec.ExpirationSeconds = &seconds
+	}
+	if input.BoundSecret != "" {
+		request.Spec.BoundObjectRef = &boundObjectRef{Kind: "Secret", APIVersion: "v1", Name: input.BoundSecret}
+	}
+	body, err := json.Marshal(request)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling token request: %w", err)
+	}
+
+	tokenURL := fmt.Sprintf("%s/api/v1/namespaces/%s/serviceaccounts/%s/token",
+		strings.TrimSuffix(restConfig.Host, "/"), url.PathEscape(namespace), url.PathEscape(input.ServiceAccount))
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, tokenURL, bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("creating token request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("requesting token: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusCreated {
+		message, _ := ioutil.ReadAll(resp.Body) //nolint: errcheck
+		return nil, fmt.Errorf("requesting token for %s/%s, status %d %s: %w", namespace, input.ServiceAccount, resp.StatusCode, strings.TrimSpace(string(message)), ErrTokenRequestFailed)
+	}
+
+	response := &tokenRequest{}
+	if err := json.NewDecoder(resp.Body).Decode(response); err != nil {
+		return nil, fmt.Errorf("decoding token request: %w", err)
+	}
+
+	return &Token{
+		Token:  response.Status.Token,
+		Expiry: response.Status.ExpirationTimestamp,
+	}, nil
+}
+
+// Kubeconfig creates a standalone kubeconfig that uses the service account token to
+// access the cluster of the context. The certificate authority is embedded.
+func Kubeconfig(cfg *api.Config, contextName, namespace, serviceAccount string, token *Token) (*api.Config, error) {
+	kubeContext, ok := cfg.Contexts[contextName]
+	if !ok {
+		return nil, fmt.Errorf("context %s: %w", contextName, ErrContextNotFound)
+	}
+	cluster, ok := cfg.Clusters[kubeContext.Cluster]
+	if !ok {
+		return nil, fmt.Errorf("cluster %s: %w", kubeContext.Cluster, ErrClusterNotFound)
+	}
+	if namespace == "" {
+		namespace = "default"
+	}
+
+	saCluster := cluster.DeepCopy()
+	if saCluster.CertificateAuthority != "" {
+		data, err := ioutil.ReadFile(saCluster.CertificateAuthority)
+		if err != nil {
+			return nil, fmt.Errorf("reading certificate authority %s: %w", saCluster.CertificateAuthority, err)
+		}
+		saCluster.CertificateAuthorityData = data
+		saCluster.CertificateAuthority = ""
+	}
+	saCluster.LocationOfOrigin = ""
+	saCluster.Extensions = nil
+
+	name := fmt.Sprintf("%s@%s", serviceAccount, contextName)
+	saConfig := api.NewConfig()
+	saConfig.Clusters[contextName] = saCluster
+	saConfig.AuthInfos[name] = &api.AuthInfo{Token: token.Token}
+	saConfig.Contexts[name] = &api.Context{
+		Cluster:   contextName,
+		AuthInfo:  name,
+		Namespace: namespace,
+	}
+	saConfig.CurrentContext = name
+
+	return saConfig, nil
+}
@@ -0,0 +1,104 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package serviceaccount_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/k8s/serviceaccount"
+)
+
+func testKubeconfig(server string) *api.Config {
+	cfg := api.NewConfig()
+	cfg.Clusters["cluster1"] = &api.Cluster{Server: server, InsecureSkipTLSVerify: true}
+	cfg.AuthInfos["user1"] = &api.AuthInfo{Token: "usertoken"}
+	cfg.Contexts["context1"] = &api.Context{Cluster: "cluster1", AuthInfo: "user1"}
+
+	return cfg
+}
+
+func TestRequestToken(t *testing.T) {
+	g := NewWithT(t)
+
+	var received map[string]interface{}
+	server := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Bearer usertoken" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Path != "/api/v1/namespaces/ci/serviceaccounts/deployer/token" {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewDecoder(r.Body).Decode(&received) //nolint: errcheck
+		received["status"] = map[string]interface{}{"token": "satoken", "expirationTimestamp": "2030-01-02T03:04:05Z"}
+		w.WriteHeader(http.StatusCreated)
+		json.NewEncoder(w).Encode(received) //nolint: errcheck
+	}))
+	defer server.Close()
+
+	cfg := testKubeconfig(server.URL)
+	token, err := serviceaccount.RequestToken(context.Background(), cfg, "context1", &serviceaccount.TokenRequestInput{
+		Namespace:      "ci",
+		ServiceAccount: "deployer",
+		Duration:       time.Hour,
+		BoundSecret:    "deployer-binding",
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(token.Token).To(Equal("satoken"))
+	g.Expect(token.Expiry.Equal(time.Date(2030, 1, 2, 3, 4, 5, 0, time.UTC))).To(BeTrue())
+
+	spec := received["spec"].(map[string]interface{})
+	g.Expect(spec["expirationSeconds"]).To(BeNumerically("==", 3600))
+	g.Expect(spec["boundObjectRef"]).To(HaveKeyWithValue("name", "deployer-binding"))
+
+	_, err = serviceaccount.RequestToken(context.Background(), cfg, "context1", &serviceaccount.TokenRequestInput{
+		Namespace:      "other",
+		ServiceAccount: "deployer",
+	})
+	g.Expect(errors.Is(err, serviceaccount.ErrTokenRequestFailed)).To(BeTrue())
+
+	_, err = serviceaccount.RequestToken(context.Background(), cfg, "context1", &serviceaccount.TokenRequestInput{})
+	g.Expect(err).To(MatchError(serviceaccount.ErrServiceAccountRequired))
+}
+
+func TestKubeconfig(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := testKubeconfig("https://cluster1")
+	cfg.Clusters["cluster1"].CertificateAuthorityData = []byte("ca")
+
+	saConfig, err := serviceaccount.Kubeconfig(cfg, "context1", "ci", "deployer", &serviceaccount.Token{Token: "satoken"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(saConfig.CurrentContext).To(Equal("deployer@context1"))
+	g.Expect(saConfig.Contexts["deployer@context1"].Namespace).To(Equal("ci"))
+	g.Expect(saConfig.AuthInfos["deployer@context1"].Token).To(Equal("satoken"))
+	g.Expect(saConfig.Clusters["context1"].Server).To(Equal("https://cluster1"))
+	g.Expect(saConfig.Clusters["context1"].CertificateAuthorityData).To(Equal([]byte("ca")))
+
+	_, err = serviceaccount.Kubeconfig(cfg, "missing", "ci", "deployer", &serviceaccount.Token{Token: "satoken"})
+	g.Expect(errors.Is(err, serviceaccount.ErrContextNotFound)).To(BeTrue())
+}
@@ -19,8 +19,11 @@ package logging
 import (
 	"errors"
 	"fmt"
+	"io"
 	"log"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/sirupsen/logrus"
 	"go.uber.org/zap"
@@ -29,57 +32,197 @@ import (
 
 const (
 	thirdPartyVerboseLevel = 9
+
+	FormatConsole = "console"
+	FormatJSON    = "json"
+
+	samplingInitial    = 100
+	samplingThereafter = 100
 )
 
 var (
 	ErrInvalidFomat = errors.New("invalid log format")
+	ErrInvalidLevel = errors.New("invalid component log level, expected component=level")
 )
 
+// Options are the options for the kconnect logging
+type Options struct {
+	// Verbosity is the logging verbosity, greater than 0 is debug
+	Verbosity int
+	// Format is the format of the logs, console or json
+	Format string
+	// File is a file the logs are also written to
+	File string
+	// NoColor stops the console logs being colored, e.g. when running in CI
+	NoColor bool
+	// ComponentLevels are the levels of the named loggers, e.g. app or http. These
+	// override the level from the verbosity.
+	ComponentLevels map[string]zapcore.Level
+}
+
 // Configure will configure the logging for kconnect and the dependent saml2aws package
-func Configure(verbosity int) error {
+func Configure(opts *Options) error {
+	if opts.Format == "" {
+		opts.Format = FormatConsole
+	}
+	if opts.Format != FormatConsole && opts.Format != FormatJSON {
+		return fmt.Errorf("log format %s: %w", opts.Format, ErrInvalidFomat)
+	}
 
-	configureLogrus(verbosity)
+	var file *os.File
+	if opts.File != "" {
+		var err error
+		file, err = os.OpenFile(opts.File, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			return fmt.Errorf("opening log file %s: %w", opts.File, err)
+		}
+	}
+
+	configureLogrus(opts, file)
 
-	if err := configureZap(verbosity); err != nil {
+	if err := configureZap(opts, file); err != nil {
 		return fmt.Errorf("configuring zap logging: %w", err)
 	}
 
-	log.SetOutput(os.Stderr)
+	log.SetOutput(newRedactingWriter(os.Stderr))
 	log.SetFlags(0)
 
 	return nil
 }
 
+// ParseComponentLevels parses comma separated component=level pairs, e.g. app=debug,http=warn
+func ParseComponentLevels(value string) (map[string]zapcore.Level, error) {
+	levels := make(map[string]zapcore.Level)
+	for _, pair := range strings.Split(value, ",") {
+		pair = strings.TrimSpace(pair)
+		if pair == "" {
+			continue
+		}
+		parts := strings.SplitN(pair, "=", 2)
+		if len(parts) != 2 || parts[0] == "" {
+			return nil, fmt.Errorf("%s: %w", pair, ErrInvalidLevel)
+		}
+		var level zapcore.Level
+		if err := level.Set(parts[1]); err != nil {
+			return nil, fmt.Errorf("%s: %w", pair, ErrInvalidLevel)
+		}
+		levels[parts[0]] = level
+	}
+
+	return levels, nil
+}
+
 // configureLogrus will configure logrus which is used by saml2aws
-func configureLogrus(verbosity int) {
-	logrus.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
-	logrus.SetOutput(os.Stderr)
+func configureLogrus(opts *Options, file *os.File) {
+	if opts.Format == FormatJSON {
+		logrus.SetFormatter(&logrus.JSONFormatter{})
+	} else {
+		logrus.SetFormatter(&logrus.TextFormatter{DisableTimestamp: true})
+	}
 
-	if verbosity >= thirdPartyVerboseLevel {
+	var out io.Writer = os.Stderr
+	if file != nil {
+		out = io.MultiWriter(os.Stderr, file)
+	}
+	logrus.SetOutput(newRedactingWriter(out))
+
+	if opts.Verbosity >= thirdPartyVerboseLevel {
 		logrus.SetLevel(logrus.DebugLevel)
 	}
 }
 
-func configureZap(verbosity int) error {
-	logConfig := zap.NewProductionConfig()
+func configureZap(opts *Options, file *os.File) error {
+	level := zap.InfoLevel
+	if opts.Verbosity > 0 {
+		level = zap.DebugLevel
+	}
 
-	logConfig.Encoding = "console"
-	logConfig.EncoderConfig.EncodeLevel = zapcore.LowercaseColorLevelEncoder
-	logConfig.EncoderConfig.TimeKey = ""
-	logConfig.EncoderConfig.CallerKey = ""
-	logConfig.EncoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+	// The cores log at the most verbose level, the component core filters the entries
+	minLevel := level
+	for _, componentLevel := range opts.ComponentLevels {
+		if componentLevel < minLevel {
+			minLevel = componentLevel
+		}
+	}
 
-	if verbosity > 0 {
-		logConfig.Level.SetLevel(zap.DebugLevel)
-	} else {
-		logConfig.Level.SetLevel(zap.InfoLevel)
+	cores := []zapcore.Core{
+		zapcore.NewCore(newEncoder(opts.Format, false, opts.NoColor), zapcore.Lock(newRedactingWriter(os.Stderr)), minLevel),
+	}
+	if file != nil {
+		cores = append(cores, zapcore.NewCore(newEncoder(opts.Format, true, true), zapcore.Lock(newRedactingWriter(file)), minLevel))
 	}
 
-	loggerMgr, err := logConfig.Build()
-	if err != nil {
-		return fmt.Errorf("building zap logger: %w", err)
+	var core zapcore.Core = &componentCore{
+		Core:         zapcore.NewTee(cores...),
+		defaultLevel: level,
+		levels:       opts.ComponentLevels,
 	}
+	core = zapcore.NewSamplerWithOptions(core, time.Second, samplingInitial, samplingThereafter)
+
+	loggerMgr := zap.New(core, zap.AddStacktrace(zap.ErrorLevel), zap.ErrorOutput(zapcore.Lock(os.Stderr)))
 	zap.ReplaceGlobals(loggerMgr)
 
 	return nil
 }
+
+// newEncoder creates the encoder for the format. The console output to a terminal is
+// colored, unless noColor is set, and has no timestamps. The timestamps are kept for json
+// and log files.
+func newEncoder(format string, toFile, noColor bool) zapcore.Encoder {
+	encoderConfig := zap.NewProductionEncoderConfig()
+	encoderConfig.CallerKey = ""
+	encoderConfig.EncodeTime = zapcore.ISO8601TimeEncoder
+
+	if format == FormatJSON {
+		return zapcore.NewJSONEncoder(encoderConfig)
+	}
+
+	encoderConfig.EncodeLevel = zapcore.LowercaseLevelEncoder
+	if !noColor {
+		encoderConfig.EncodeLevel = zapcore.LowercaseColorLevelEncoder
+	}
+	if !toFile {
+		encoderConfig.TimeKey = ""
+	}
+
+	return zapcore.NewConsoleEncoder(encoderConfig)
+}
+
+// componentCore filters the entries using the level of the named logger. The most
+// specific part of the logger name that has a level is used, e.g. for app.eks the
+// level of eks is used before the level of app.
+type componentCore struct {
+	zapcore.Core
+	defaultLevel zapcore.Level
+	levels       map[string]zapcore.Level
+}
+
+func (c *componentCore) With(fields []zapcore.Field) zapcore.Core {
+	return &componentCore{
+		Core:         c.Core.With(fields),
+		defaultLevel: c.defaultLevel,
+		levels:       c.levels,
+	}
+}
+
+func (c *componentCore) Check(entry zapcore.Entry, checked *zapcore.CheckedEntry) *zapcore.CheckedEntry {
+	if !c.levelFor(entry.LoggerName).Enabled(entry.Level) {
+		return checked
+	}
+
+	return c.Core.Check(entry, checked)
+}
+
+func (c *componentCore) levelFor(loggerName string) zapcore.Level {
+	if level, ok := c.levels[loggerName]; ok {
+		return level
+	}
+	names := strings.Split(loggerName, ".")
+	for i := len(names) - 1; i >= 0; i-- {
+		if level, ok := c.levels[names[i]]; ok {
+			return level
+		}
+	}
+
+	return c.defaultLevel
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap/zapcore"
+)
+
+func TestParseComponentLevels(t *testing.T) {
+	testCases := []struct {
+		name        string
+		value       string
+		expected    map[string]zapcore.Level
+		expectError bool
+	}{
+		{
+			name:     "empty",
+			value:    "",
+			expected: map[string]zapcore.Level{},
+		},
+		{
+			name:  "multiple components",
+			value: "app=debug, http=warn",
+			expected: map[string]zapcore.Level{
+				"app":  zapcore.DebugLevel,
+				"http": zapcore.WarnLevel,
+			},
+		},
+		{
+			name:        "missing level",
+			value:       "app",
+			expectError: true,
+		},
+		{
+			name:        "unknown level",
+			value:       "app=chatty",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) { //nolint:scopelint
+			g := NewWithT(t)
+
+			levels, err := ParseComponentLevels(tc.value) //nolint:scopelint
+			if tc.expectError {                           //nolint:scopelint
+				g.Expect(err).To(MatchError(ErrInvalidLevel))
+				return
+			}
+
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(levels).To(Equal(tc.expected)) //nolint:scopelint
+		})
+	}
+}
+
+func TestComponentCoreLevelFor(t *testing.T) {
+	g := NewWithT(t)
+
+	core := &componentCore{
+		defaultLevel: zapcore.InfoLevel,
+		levels: map[string]zapcore.Level{
+			"app":  zapcore.WarnLevel,
+			"eks":  zapcore.DebugLevel,
+			"http": zapcore.ErrorLevel,
+		},
+	}
+
+	g.Expect(core.levelFor("")).To(Equal(zapcore.InfoLevel))
+	g.Expect(core.levelFor("app")).To(Equal(zapcore.WarnLevel))
+	g.Expect(core.levelFor("app.eks")).To(Equal(zapcore.DebugLevel))
+	g.Expect(core.levelFor("app.aad")).To(Equal(zapcore.WarnLevel))
+	g.Expect(core.levelFor("app.http")).To(Equal(zapcore.ErrorLevel))
+}
+
+func TestRedactingWriter(t *testing.T) {
+	g := NewWithT(t)
+
+	out := &bytes.Buffer{}
+	writer := &redactingWriter{Writer: out, redactions: &redactionSet{}}
+	writer.redactions.add("s3cr3t-password", "abc", "s3cr3t-password")
+
+	n, err := writer.Write([]byte("logging in with s3cr3t-password as abc\n"))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(n).To(Equal(len("logging in with s3cr3t-password as abc\n")))
+	g.Expect(out.String()).To(Equal("logging in with ******** as abc\n"))
+	g.Expect(writer.redactions.values).To(HaveLen(1))
+}
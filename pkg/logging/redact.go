@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package logging
+
+import (
+	"bytes"
+	"io"
+	"sync"
+
+	"go.uber.org/zap/zapcore"
+)
+
+const (
+	redactedValue = "********"
+	// minRedactLength is the length of the shortest value that is redacted, shorter values
+	// would redact unrelated parts of the logs
+	minRedactLength = 4
+)
+
+var redactions = &redactionSet{}
+
+// Redact will replace the values, e.g. the values of sensitive config items, in
+// everything that is logged from now on
+func Redact(values ...string) {
+	redactions.add(values...)
+}
+
+type redactionSet struct {
+	lock   sync.RWMutex
+	values [][]byte
+}
+
+func (r *redactionSet) add(values ...string) {
+	r.lock.Lock()
+	defer r.lock.Unlock()
+
+	for _, value := range values {
+		if len(value) < minRedactLength || r.contains(value) {
+			continue
+		}
+		r.values = append(r.values, []byte(value))
+	}
+}
+
+func (r *redactionSet) contains(value string) bool {
+	for _, existing := range r.values {
+		if string(existing) == value {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (r *redactionSet) redact(p []byte) []byte {
+	r.lock.RLock()
+	defer r.lock.RUnlock()
+
+	for _, value := range r.values {
+		if bytes.Contains(p, value) {
+			p = bytes.ReplaceAll(p, value, []byte(redactedValue))
+		}
+	}
+
+	return p
+}
+
+// redactingWriter redacts the values before writing the logs
+type redactingWriter struct {
+	io.Writer
+	redactions *redactionSet
+}
+
+func (w *redactingWriter) Write(p []byte) (int, error) {
+	if _, err := w.Writer.Write(w.redactions.redact(p)); err != nil {
+		return 0, err
+	}
+
+	return len(p), nil
+}
+
+func (w *redactingWriter) Sync() error {
+	if syncer, ok := w.Writer.(zapcore.WriteSyncer); ok {
+		return syncer.Sync()
+	}
+
+	return nil
+}
+
+func newRedactingWriter(w io.Writer) *redactingWriter {
+	return &redactingWriter{Writer: w, redactions: redactions}
+}
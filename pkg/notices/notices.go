@@ -0,0 +1,149 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notices
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	kconnectv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	// LevelInfo is the level of a notice that is for information, this is the default
+	LevelInfo = "info"
+	// LevelWarning is the level of a notice that requires action, it's highlighted
+	LevelWarning = "warning"
+)
+
+// Levels returns the levels of notice
+func Levels() []string {
+	return []string{LevelInfo, LevelWarning}
+}
+
+// seenNotices is the content of the file that records the notices that have been shown
+type seenNotices struct {
+	Seen map[string]time.Time `json:"seen"`
+}
+
+// NewStore creates a store that records the notices that have been shown in the file at the path
+func NewStore(path string) *Store {
+	return &Store{path: path}
+}
+
+// Store records which notices have been shown so that each notice is only shown once
+type Store struct {
+	path string
+}
+
+// Pending returns the notices that haven't been shown and haven't expired
+func (s *Store) Pending(notices []kconnectv1alpha.Notice, now time.Time) ([]kconnectv1alpha.Notice, error) {
+	seen, err := s.read()
+	if err != nil {
+		return nil, err
+	}
+
+	pending := []kconnectv1alpha.Notice{}
+	for _, notice := range notices {
+		if notice.ID == "" || notice.Message == "" {
+			continue
+		}
+		if _, shown := seen.Seen[notice.ID]; shown {
+			continue
+		}
+		if notice.Expires != nil && !now.Before(notice.Expires.Time) {
+			continue
+		}
+		pending = append(pending, notice)
+	}
+
+	return pending, nil
+}
+
+// MarkSeen records that the notices have been shown. Notices that are no longer in
+// the app config are forgotten so that the file doesn't keep growing.
+func (s *Store) MarkSeen(current []kconnectv1alpha.Notice, shown []kconnectv1alpha.Notice, now time.Time) error {
+	seen, err := s.read()
+	if err != nil {
+		return err
+	}
+
+	currentIDs := map[string]bool{}
+	for _, notice := range current {
+		currentIDs[notice.ID] = true
+	}
+	for id := range seen.Seen {
+		if !currentIDs[id] {
+			delete(seen.Seen, id)
+		}
+	}
+	for _, notice := range shown {
+		seen.Seen[notice.ID] = now.UTC()
+	}
+
+	data, err := json.Marshal(seen)
+	if err != nil {
+		return fmt.Errorf("marshalling seen notices: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(s.path), os.ModePerm); err != nil {
+		return fmt.Errorf("creating seen notices directory: %w", err)
+	}
+	if err := os.WriteFile(s.path, data, 0600); err != nil {
+		return fmt.Errorf("writing seen notices %s: %w", s.path, err)
+	}
+
+	return nil
+}
+
+func (s *Store) read() (*seenNotices, error) {
+	seen := &seenNotices{Seen: map[string]time.Time{}}
+
+	data, err := os.ReadFile(s.path)
+	if os.IsNotExist(err) {
+		return seen, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading seen notices %s: %w", s.path, err)
+	}
+	if err := json.Unmarshal(data, seen); err != nil {
+		return nil, fmt.Errorf("unmarshalling seen notices %s: %w", s.path, err)
+	}
+	if seen.Seen == nil {
+		seen.Seen = map[string]time.Time{}
+	}
+
+	return seen, nil
+}
+
+// Render writes the notices for the user to read, warnings are highlighted
+func Render(w io.Writer, notices []kconnectv1alpha.Notice) {
+	for _, notice := range notices {
+		message := fmt.Sprintf("NOTICE: %s", notice.Message)
+		if notice.Level == LevelWarning {
+			message = utils.Warning(message)
+		}
+		fmt.Fprintln(w, message)
+	}
+	if len(notices) > 0 {
+		fmt.Fprintln(w, "")
+	}
+}
@@ -0,0 +1,77 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notices_test
+
+import (
+	"bytes"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+
+	kconnectv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/notices"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+func TestStore(t *testing.T) {
+	g := NewWithT(t)
+
+	now := time.Date(2021, 5, 1, 9, 0, 0, 0, time.UTC)
+	expired := metav1.NewTime(now.Add(-time.Hour))
+	current := []kconnectv1alpha.Notice{
+		{ID: "idp-move", Message: "prod clusters are moving to the new IdP on June 1", Level: notices.LevelWarning},
+		{ID: "maintenance", Message: "the dev clusters were upgraded", Expires: &expired},
+		{ID: "no-message"},
+	}
+
+	store := notices.NewStore(filepath.Join(t.TempDir(), "notices.json"))
+
+	pending, err := store.Pending(current, now)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pending).To(HaveLen(1))
+	g.Expect(pending[0].ID).To(Equal("idp-move"))
+
+	g.Expect(store.MarkSeen(current, pending, now)).To(Succeed())
+	pending, err = store.Pending(current, now)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pending).To(BeEmpty())
+
+	// A notice that is removed from the config and added again is shown again
+	g.Expect(store.MarkSeen(nil, nil, now)).To(Succeed())
+	pending, err = store.Pending(current, now)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(pending).To(HaveLen(1))
+}
+
+func TestRender(t *testing.T) {
+	g := NewWithT(t)
+	utils.DisableColors()
+
+	out := &bytes.Buffer{}
+	notices.Render(out, []kconnectv1alpha.Notice{
+		{ID: "idp-move", Message: "prod clusters are moving to the new IdP on June 1", Level: notices.LevelWarning},
+		{ID: "docs", Message: "see the new docs site"},
+	})
+
+	g.Expect(out.String()).To(Equal(`NOTICE: prod clusters are moving to the new IdP on June 1
+NOTICE: see the new docs site
+
+`))
+}
@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+const (
+	titleEnv   = "KCONNECT_NOTIFY_TITLE"
+	messageEnv = "KCONNECT_NOTIFY_MESSAGE"
+
+	// forgetAfter is how long a sent notification is remembered for
+	forgetAfter = 7 * 24 * time.Hour
+
+	// The title and message are read from the environment by the scripts so that they
+	// don't need to be escaped
+	appleScript      = `display notification (system attribute "` + messageEnv + `") with title (system attribute "` + titleEnv + `")`
+	powershellScript = `[Windows.UI.Notifications.ToastNotificationManager, Windows.UI.Notifications, ContentType = WindowsRuntime] | Out-Null
+$template = [Windows.UI.Notifications.ToastNotificationManager]::GetTemplateContent([Windows.UI.Notifications.ToastTemplateType]::ToastText02)
+$text = $template.GetElementsByTagName('text')
+$text.Item(0).AppendChild($template.CreateTextNode($env:` + titleEnv + `)) | Out-Null
+$text.Item(1).AppendChild($template.CreateTextNode($env:` + messageEnv + `)) | Out-Null
+[Windows.UI.Notifications.ToastNotificationManager]::CreateToastNotifier('kconnect').Show([Windows.UI.Notifications.ToastNotification]::new($template))`
+)
+
+var ErrUnsupportedOS = errors.New("desktop notifications aren't supported on this operating system")
+
+// Send shows a desktop notification. It uses osascript on macOS, a PowerShell toast
+// on Windows and notify-send, from libnotify, on Linux.
+func Send(ctx context.Context, title, message string) error {
+	cmd, err := command(ctx, runtime.GOOS, title, message)
+	if err != nil {
+		return err
+	}
+
+	if out, err := cmd.CombinedOutput(); err != nil {
+		return fmt.Errorf("running %s: %s: %w", filepath.Base(cmd.Path), strings.TrimSpace(string(out)), err)
+	}
+
+	return nil
+}
+
+func command(ctx context.Context, goos, title, message string) (*exec.Cmd, error) {
+	var cmd *exec.Cmd
+	switch goos {
+	case "darwin":
+		cmd = exec.CommandContext(ctx, "osascript", "-e", appleScript)
+	case "windows":
+		cmd = exec.CommandContext(ctx, "powershell", "-NoProfile", "-NonInteractive", "-Command", powershellScript)
+	case "linux", "freebsd", "openbsd", "netbsd":
+		cmd = exec.CommandContext(ctx, "notify-send", "--app-name", "kconnect", "--", title, message)
+	default:
+		return nil, ErrUnsupportedOS
+	}
+	cmd.Env = append(os.Environ(), titleEnv+"="+title, messageEnv+"="+message)
+
+	return cmd, nil
+}
+
+// NewOnce creates a notifier that records the notifications it has sent in the file at the path
+func NewOnce(path string) *Once {
+	return &Once{path: path, send: Send}
+}
+
+// Once sends each notification only once, e.g. so that a notification isn't
+// shown every time kubectl runs a credential plugin
+type Once struct {
+	path string
+	send func(ctx context.Context, title, message string) error
+}
+
+// Send sends the notification unless one with the id has already been sent
+func (o *Once) Send(ctx context.Context, id, title, message string) error {
+	sent, err := o.read()
+	if err != nil {
+		return err
+	}
+	if _, ok := sent[id]; ok {
+		return nil
+	}
+
+	if err := o.send(ctx, title, message); err != nil {
+		return err
+	}
+
+	now := time.Now()
+	for sentID, at := range sent {
+		if now.Sub(at) > forgetAfter {
+			delete(sent, sentID)
+		}
+	}
+	sent[id] = now.UTC()
+
+	return o.write(sent)
+}
+
+func (o *Once) read() (map[string]time.Time, error) {
+	sent := map[string]time.Time{}
+
+	data, err := os.ReadFile(o.path)
+	if os.IsNotExist(err) {
+		return sent, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("reading sent notifications %s: %w", o.path, err)
+	}
+	if err := json.Unmarshal(data, &sent); err != nil {
+		return nil, fmt.Errorf("unmarshalling sent notifications %s: %w", o.path, err)
+	}
+
+	return sent, nil
+}
+
+func (o *Once) write(sent map[string]time.Time) error {
+	data, err := json.Marshal(sent)
+	if err != nil {
+		return fmt.Errorf("marshalling sent notifications: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(o.path), os.ModePerm); err != nil {
+		return fmt.Errorf("creating sent notifications directory: %w", err)
+	}
+	if err := os.WriteFile(o.path, data, 0600); err != nil {
+		return fmt.Errorf("writing sent notifications %s: %w", o.path, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package notify
+
+import (
+	"context"
+	"path/filepath"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestCommand(t *testing.T) {
+	testCases := []struct {
+		goos string
+		args []string
+	}{
+		{goos: "darwin", args: []string{"osascript", "-e", appleScript}},
+		{goos: "windows", args: []string{"powershell", "-NoProfile", "-NonInteractive", "-Command", powershellScript}},
+		{goos: "linux", args: []string{"notify-send", "--app-name", "kconnect", "--", "kconnect", "-renew \"now\""}},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.goos, func(t *testing.T) {
+			g := NewWithT(t)
+
+			cmd, err := command(context.Background(), tc.goos, "kconnect", `-renew "now"`)
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(cmd.Args).To(Equal(tc.args))
+			g.Expect(cmd.Env).To(ContainElements(titleEnv+"=kconnect", messageEnv+`=-renew "now"`))
+		})
+	}
+
+	g := NewWithT(t)
+	_, err := command(context.Background(), "plan9", "kconnect", "renew")
+	g.Expect(err).To(MatchError(ErrUnsupportedOS))
+}
+
+func TestOnce(t *testing.T) {
+	g := NewWithT(t)
+
+	sent := []string{}
+	once := NewOnce(filepath.Join(t.TempDir(), "notifications.json"))
+	once.send = func(ctx context.Context, title, message string) error {
+		sent = append(sent, message)
+		return nil
+	}
+
+	g.Expect(once.Send(context.Background(), "entry1@10:00", "kconnect", "first")).To(Succeed())
+	g.Expect(once.Send(context.Background(), "entry1@10:00", "kconnect", "again")).To(Succeed())
+	g.Expect(once.Send(context.Background(), "entry1@11:00", "kconnect", "renewed")).To(Succeed())
+	g.Expect(sent).To(Equal([]string{"first", "renewed"}))
+}
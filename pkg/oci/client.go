@@ -0,0 +1,289 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+	"strings"
+
+	"go.uber.org/zap"
+
+	khttp "github.com/fidelity/kconnect/pkg/http"
+)
+
+const (
+	// MediaTypeConfig is the media type for a kconnect configuration layer
+	MediaTypeConfig = "application/vnd.fidelity.kconnect.config.v1+yaml"
+
+	mediaTypeOCIManifest    = "application/vnd.oci.image.manifest.v1+json"
+	mediaTypeDockerManifest = "application/vnd.docker.distribution.manifest.v2+json"
+	annotationTitle         = "org.opencontainers.image.title"
+
+	statusUnauthorized = 401
+)
+
+var (
+	ErrInvalidCredentials = errors.New("invalid registry credentials")
+	ErrUnexpectedStatus   = errors.New("unexpected status code from registry")
+	ErrNoConfigLayer      = errors.New("no configuration layer found in artifact")
+	ErrDigestMismatch     = errors.New("digest of the downloaded layer doesn't match")
+	ErrUnsupportedAuth    = errors.New("unsupported registry authentication challenge")
+)
+
+type manifest struct {
+	MediaType string       `json:"mediaType"`
+	Layers    []descriptor `json:"layers"`
+}
+
+type descriptor struct {
+	MediaType   string            `json:"mediaType"`
+	Digest      string            `json:"digest"`
+	Size        int64             `json:"size"`
+	Annotations map[string]string `json:"annotations,omitempty"`
+}
+
+type tokenResponse struct {
+	Token       string `json:"token"`
+	AccessToken string `json:"access_token"`
+}
+
+// Client is used to pull artifacts from an OCI registry
+type Client struct {
+	httpClient  khttp.Client
+	credentials *Credentials
+	authHeader  string
+}
+
+// NewClient creates a new client for pulling artifacts. If no credentials are supplied
+// the docker config and credential helpers are used to find them.
+func NewClient(httpClient khttp.Client, credentials *Credentials) *Client {
+	return &Client{
+		httpClient:  httpClient,
+		credentials: credentials,
+	}
+}
+
+// PullConfig will pull the kconnect configuration from an artifact. The layer with the
+// kconnect config media type is used, otherwise the first layer whose title ends with
+// .yaml or .yml, or the only layer in the artifact.
+func (c *Client) PullConfig(ref *Reference) ([]byte, error) {
+	zap.S().Debugw("pulling configuration artifact", "reference", ref.String())
+
+	if c.credentials == nil {
+		creds, err := DockerCredentials(ref.Registry)
+		if err != nil {
+			return nil, fmt.Errorf("getting docker credentials for %s: %w", ref.Registry, err)
+		}
+		c.credentials = creds
+	}
+
+	manifestURL := fmt.Sprintf("%s/manifests/%s", ref.baseURL(), ref.Reference)
+	body, err := c.get(manifestURL, map[string]string{
+		"Accept": strings.Join([]string{mediaTypeOCIManifest, mediaTypeDockerManifest}, ", "),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting manifest for %s: %w", ref.String(), err)
+	}
+
+	m := &manifest{}
+	if err := json.Unmarshal([]byte(body), m); err != nil {
+		return nil, fmt.Errorf("parsing manifest for %s: %w", ref.String(), err)
+	}
+
+	layer := configLayer(m.Layers)
+	if layer == nil {
+		return nil, fmt.Errorf("pulling %s: %w", ref.String(), ErrNoConfigLayer)
+	}
+
+	blobURL := fmt.Sprintf("%s/blobs/%s", ref.baseURL(), layer.Digest)
+	blob, err := c.get(blobURL, map[string]string{})
+	if err != nil {
+		return nil, fmt.Errorf("getting layer %s: %w", layer.Digest, err)
+	}
+
+	data := []byte(blob)
+	if err := verifyDigest(layer.Digest, data); err != nil {
+		return nil, err
+	}
+
+	return data, nil
+}
+
+func (c *Client) get(requestURL string, headers map[string]string) (string, error) {
+	if c.authHeader != "" {
+		headers["Authorization"] = c.authHeader
+	}
+
+	resp, err := c.httpClient.Get(requestURL, headers)
+	if err != nil {
+		return "", err
+	}
+
+	if resp.ResponseCode() == statusUnauthorized && c.authHeader == "" {
+		authHeader, err := c.authenticate(resp.Headers()["Www-Authenticate"])
+		if err != nil {
+			return "", fmt.Errorf("authenticating with registry: %w", err)
+		}
+		c.authHeader = authHeader
+
+		return c.get(requestURL, headers)
+	}
+	if resp.ResponseCode() != khttp.StatusCodeOK {
+		return "", fmt.Errorf("received status code %d for %s: %w", resp.ResponseCode(), requestURL, ErrUnexpectedStatus)
+	}
+
+	return resp.Body(), nil
+}
+
+// authenticate will respond to the authentication challenge from the registry and return
+// the authorization header to use for subsequent requests
+func (c *Client) authenticate(challenge string) (string, error) {
+	scheme, params := parseChallenge(challenge)
+
+	switch strings.ToLower(scheme) {
+	case "basic":
+		if c.credentials == nil {
+			return "", fmt.Errorf("registry requires basic auth but no credentials found: %w", ErrInvalidCredentials)
+		}
+		headers := map[string]string{}
+		khttp.SetBasicAuthHeaders(headers, c.credentials.Username, c.credentials.Password)
+		return headers["Authorization"], nil
+	case "bearer":
+		return c.bearerToken(params)
+	default:
+		return "", fmt.Errorf("challenge %q: %w", challenge, ErrUnsupportedAuth)
+	}
+}
+
+func (c *Client) bearerToken(params map[string]string) (string, error) {
+	realm, ok := params["realm"]
+	if !ok {
+		return "", fmt.Errorf("bearer challenge has no realm: %w", ErrUnsupportedAuth)
+	}
+
+	query := url.Values{}
+	if service, ok := params["service"]; ok {
+		query.Set("service", service)
+	}
+	if scope, ok := params["scope"]; ok {
+		query.Set("scope", scope)
+	}
+	tokenURL := realm
+	if len(query) > 0 {
+		tokenURL = fmt.Sprintf("%s?%s", realm, query.Encode())
+	}
+
+	headers := map[string]string{}
+	if c.credentials != nil {
+		khttp.SetBasicAuthHeaders(headers, c.credentials.Username, c.credentials.Password)
+	}
+
+	resp, err := c.httpClient.Get(tokenURL, headers)
+	if err != nil {
+		return "", fmt.Errorf("getting token: %w", err)
+	}
+	if resp.ResponseCode() != khttp.StatusCodeOK {
+		return "", fmt.Errorf("received status code %d getting token: %w", resp.ResponseCode(), ErrInvalidCredentials)
+	}
+
+	token := &tokenResponse{}
+	if err := json.Unmarshal([]byte(resp.Body()), token); err != nil {
+		return "", fmt.Errorf("parsing token response: %w", err)
+	}
+	if token.Token == "" {
+		token.Token = token.AccessToken
+	}
+
+	return "Bearer " + token.Token, nil
+}
+
+// parseChallenge parses a WWW-Authenticate header in the form: Bearer realm="...",service="..."
+func parseChallenge(challenge string) (string, map[string]string) {
+	params := map[string]string{}
+
+	parts := strings.SplitN(strings.TrimSpace(challenge), " ", 2)
+	if len(parts) < 2 {
+		return parts[0], params
+	}
+
+	for _, param := range splitParams(parts[1]) {
+		kv := strings.SplitN(param, "=", 2)
+		if len(kv) != 2 {
+			continue
+		}
+		params[strings.ToLower(strings.TrimSpace(kv[0]))] = strings.Trim(strings.TrimSpace(kv[1]), `"`)
+	}
+
+	return parts[0], params
+}
+
+// splitParams splits on commas that aren't within quotes, a scope can contain commas
+func splitParams(value string) []string {
+	params := []string{}
+	inQuotes := false
+	start := 0
+	for i, r := range value {
+		switch {
+		case r == '"':
+			inQuotes = !inQuotes
+		case r == ',' && !inQuotes:
+			params = append(params, value[start:i])
+			start = i + 1
+		}
+	}
+
+	return append(params, value[start:])
+}
+
+func configLayer(layers []descriptor) *descriptor {
+	for i := range layers {
+		if layers[i].MediaType == MediaTypeConfig {
+			return &layers[i]
+		}
+	}
+	for i := range layers {
+		title := layers[i].Annotations[annotationTitle]
+		if strings.HasSuffix(title, ".yaml") || strings.HasSuffix(title, ".yml") {
+			return &layers[i]
+		}
+	}
+	if len(layers) == 1 {
+		return &layers[0]
+	}
+
+	return nil
+}
+
+func verifyDigest(digest string, data []byte) error {
+	parts := strings.SplitN(digest, ":", 2)
+	if len(parts) != 2 || parts[0] != "sha256" {
+		zap.S().Debugw("unable to verify digest with unsupported algorithm", "digest", digest)
+		return nil
+	}
+
+	sum := sha256.Sum256(data)
+	if hex.EncodeToString(sum[:]) != parts[1] {
+		return fmt.Errorf("verifying layer %s: %w", digest, ErrDigestMismatch)
+	}
+
+	return nil
+}
@@ -0,0 +1,143 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci_test
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/oci"
+)
+
+const (
+	testConfig = `apiVersion: kconnect.fidelity.github.com/v1alpha1
+kind: Configuration
+spec:
+  global:
+    username: bob
+`
+	testToken = "abcdef"
+)
+
+func TestParseReference(t *testing.T) {
+	testCases := []struct {
+		name        string
+		location    string
+		expectError bool
+		expect      *oci.Reference
+	}{
+		{
+			name:     "with tag",
+			location: "oci://ghcr.io/org/config:v1",
+			expect:   &oci.Reference{Registry: "ghcr.io", Repository: "org/config", Reference: "v1"},
+		},
+		{
+			name:     "no tag",
+			location: "oci://localhost:5000/config",
+			expect:   &oci.Reference{Registry: "localhost:5000", Repository: "config", Reference: "latest"},
+		},
+		{
+			name:     "with digest",
+			location: "oci://ghcr.io/org/config@sha256:1234",
+			expect:   &oci.Reference{Registry: "ghcr.io", Repository: "org/config", Reference: "sha256:1234"},
+		},
+		{
+			name:     "docker hub official",
+			location: "oci://docker.io/config",
+			expect:   &oci.Reference{Registry: "docker.io", Repository: "library/config", Reference: "latest"},
+		},
+		{
+			name:        "no repository",
+			location:    "oci://ghcr.io",
+			expectError: true,
+		},
+		{
+			name:        "not oci",
+			location:    "https://ghcr.io/org/config",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := oci.ParseReference(tc.location) //nolint:scopelint
+			if tc.expectError {                            //nolint:scopelint
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual).To(Equal(tc.expect)) //nolint:scopelint
+		})
+	}
+}
+
+func TestPullConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	sum := sha256.Sum256([]byte(testConfig))
+	digest := "sha256:" + hex.EncodeToString(sum[:])
+
+	var server *httptest.Server
+	server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/token" {
+			user, pass, ok := r.BasicAuth()
+			if !ok || user != "alice" || pass != "secret" {
+				w.WriteHeader(http.StatusUnauthorized)
+				return
+			}
+			fmt.Fprintf(w, `{"token":"%s"}`, testToken)
+			return
+		}
+		if r.Header.Get("Authorization") != "Bearer "+testToken {
+			w.Header().Set("WWW-Authenticate", fmt.Sprintf(`Bearer realm="%s/token",service="test",scope="repository:org/config:pull"`, server.URL))
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+
+		switch r.URL.Path {
+		case "/v2/org/config/manifests/v1":
+			fmt.Fprintf(w, `{"mediaType":"application/vnd.oci.image.manifest.v1+json","layers":[{"mediaType":"%s","digest":"%s","size":%d}]}`, oci.MediaTypeConfig, digest, len(testConfig))
+		case "/v2/org/config/blobs/" + digest:
+			fmt.Fprint(w, testConfig)
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	ref, err := oci.ParseReference(oci.Scheme + strings.TrimPrefix(server.URL, "http://") + "/org/config:v1")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	client := oci.NewClient(khttp.NewHTTPClient(), &oci.Credentials{Username: "alice", Password: "secret"})
+	data, err := client.PullConfig(ref)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).To(Equal(testConfig))
+
+	badClient := oci.NewClient(khttp.NewHTTPClient(), &oci.Credentials{Username: "alice", Password: "wrong"})
+	_, err = badClient.PullConfig(ref)
+	g.Expect(err).To(MatchError(ContainSubstring("invalid registry credentials")))
+}
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+const (
+	dockerHubCredentialsKey = "https://index.docker.io/v1/"
+	credentialHelperPrefix  = "docker-credential-"
+)
+
+// Credentials are the credentials used to authenticate with a registry
+type Credentials struct {
+	Username string
+	Password string
+}
+
+// dockerConfig is the subset of the docker config file used for credentials
+type dockerConfig struct {
+	Auths       map[string]dockerAuth `json:"auths"`
+	CredsStore  string                `json:"credsStore"`
+	CredHelpers map[string]string     `json:"credHelpers"`
+}
+
+type dockerAuth struct {
+	Auth string `json:"auth"`
+}
+
+type credentialHelperOutput struct {
+	Username string `json:"Username"`
+	Secret   string `json:"Secret"`
+}
+
+// DockerCredentials will get the credentials for a registry using the docker config
+// file, including any configured credential helpers. If there are no credentials
+// for the registry then nil is returned.
+func DockerCredentials(registry string) (*Credentials, error) {
+	cfg, err := readDockerConfig()
+	if err != nil {
+		return nil, err
+	}
+	if cfg == nil {
+		return nil, nil
+	}
+
+	key := registry
+	if registry == dockerHubRegistry {
+		key = dockerHubCredentialsKey
+	}
+
+	if helper, ok := cfg.CredHelpers[key]; ok {
+		return helperCredentials(helper, key)
+	}
+
+	for authKey, auth := range cfg.Auths {
+		if authKey != key && strings.TrimPrefix(strings.TrimPrefix(authKey, "https://"), "http://") != key {
+			continue
+		}
+		if auth.Auth == "" {
+			break
+		}
+		decoded, err := base64.StdEncoding.DecodeString(auth.Auth)
+		if err != nil {
+			return nil, fmt.Errorf("decoding auth for %s: %w", registry, err)
+		}
+		parts := strings.SplitN(string(decoded), ":", 2)
+		if len(parts) != 2 {
+			return nil, fmt.Errorf("auth for %s is not in the form username:password: %w", registry, ErrInvalidCredentials)
+		}
+
+		return &Credentials{Username: parts[0], Password: parts[1]}, nil
+	}
+
+	if cfg.CredsStore != "" {
+		return helperCredentials(cfg.CredsStore, key)
+	}
+
+	return nil, nil
+}
+
+func readDockerConfig() (*dockerConfig, error) {
+	configDir := os.Getenv("DOCKER_CONFIG")
+	if configDir == "" {
+		home, err := os.UserHomeDir()
+		if err != nil {
+			return nil, fmt.Errorf("getting home directory: %w", err)
+		}
+		configDir = filepath.Join(home, ".docker")
+	}
+	configPath := filepath.Join(configDir, "config.json")
+
+	data, err := ioutil.ReadFile(configPath)
+	if err != nil {
+		if os.IsNotExist(err) {
+			zap.S().Debugw("no docker config file found", "path", configPath)
+			return nil, nil
+		}
+		return nil, fmt.Errorf("reading docker config %s: %w", configPath, err)
+	}
+
+	cfg := &dockerConfig{}
+	if err := json.Unmarshal(data, cfg); err != nil {
+		return nil, fmt.Errorf("parsing docker config %s: %w", configPath, err)
+	}
+
+	return cfg, nil
+}
+
+func helperCredentials(helper, registry string) (*Credentials, error) {
+	helperName := credentialHelperPrefix + helper
+	zap.S().Debugw("getting registry credentials from helper", "helper", helperName, "registry", registry)
+
+	var stdout bytes.Buffer
+	cmd := exec.Command(helperName, "get") //nolint: gosec
+	cmd.Stdin = strings.NewReader(registry)
+	cmd.Stdout = &stdout
+	if err := cmd.Run(); err != nil {
+		// The helpers exit with an error if there are no credentials for the registry
+		zap.S().Debugw("credential helper returned an error", "helper", helperName, "error", err.Error())
+		return nil, nil
+	}
+
+	output := &credentialHelperOutput{}
+	if err := json.Unmarshal(stdout.Bytes(), output); err != nil {
+		return nil, fmt.Errorf("parsing output from %s: %w", helperName, err)
+	}
+
+	return &Credentials{Username: output.Username, Password: output.Secret}, nil
+}
@@ -0,0 +1,111 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oci
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+)
+
+const (
+	// Scheme is the prefix used for a location that is an OCI artifact
+	Scheme = "oci://"
+
+	defaultTag = "latest"
+
+	dockerHubRegistry    = "docker.io"
+	dockerHubAPIRegistry = "registry-1.docker.io"
+)
+
+var (
+	ErrInvalidReference = errors.New("invalid oci artifact reference")
+)
+
+// Reference is a reference to an artifact in a registry
+type Reference struct {
+	// Registry is the host (and optional port) of the registry
+	Registry string
+	// Repository is the name of the repository in the registry
+	Repository string
+	// Reference is the tag or digest of the artifact
+	Reference string
+}
+
+// IsReference returns true if the location is an OCI artifact reference
+func IsReference(location string) bool {
+	return strings.HasPrefix(location, Scheme)
+}
+
+// ParseReference will parse a location in the form oci://registry/repository[:tag|@digest]. If
+// no tag or digest is supplied then latest is used.
+func ParseReference(location string) (*Reference, error) {
+	if !IsReference(location) {
+		return nil, fmt.Errorf("location %s must start with %s: %w", location, Scheme, ErrInvalidReference)
+	}
+	name := strings.TrimPrefix(location, Scheme)
+
+	parts := strings.SplitN(name, "/", 2)
+	if len(parts) != 2 || parts[0] == "" || parts[1] == "" {
+		return nil, fmt.Errorf("location %s must contain a registry and repository: %w", location, ErrInvalidReference)
+	}
+	ref := &Reference{
+		Registry:   parts[0],
+		Repository: parts[1],
+		Reference:  defaultTag,
+	}
+
+	if idx := strings.Index(ref.Repository, "@"); idx != -1 {
+		ref.Reference = ref.Repository[idx+1:]
+		ref.Repository = ref.Repository[:idx]
+	} else if idx := strings.LastIndex(ref.Repository, ":"); idx != -1 {
+		ref.Reference = ref.Repository[idx+1:]
+		ref.Repository = ref.Repository[:idx]
+	}
+	if ref.Repository == "" || ref.Reference == "" {
+		return nil, fmt.Errorf("location %s has an empty repository or tag: %w", location, ErrInvalidReference)
+	}
+
+	if ref.Registry == dockerHubRegistry && !strings.Contains(ref.Repository, "/") {
+		ref.Repository = "library/" + ref.Repository
+	}
+
+	return ref, nil
+}
+
+// String returns the reference in the oci://registry/repository:tag form
+func (r *Reference) String() string {
+	if strings.Contains(r.Reference, ":") {
+		return fmt.Sprintf("%s%s/%s@%s", Scheme, r.Registry, r.Repository, r.Reference)
+	}
+
+	return fmt.Sprintf("%s%s/%s:%s", Scheme, r.Registry, r.Repository, r.Reference)
+}
+
+func (r *Reference) baseURL() string {
+	registry := r.Registry
+	if registry == dockerHubRegistry {
+		registry = dockerHubAPIRegistry
+	}
+
+	scheme := "https"
+	if strings.HasPrefix(registry, "localhost") || strings.HasPrefix(registry, "127.0.0.1") {
+		scheme = "http"
+	}
+
+	return fmt.Sprintf("%s://%s/v2/%s", scheme, registry, r.Repository)
+}
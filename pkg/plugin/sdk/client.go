@@ -0,0 +1,116 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdk
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"github.com/hashicorp/go-hclog"
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+var ErrUnexpectedPluginType = errors.New("plugin returned an unexpected type")
+
+// Client is used by kconnect to start and talk to a plugin
+type Client struct {
+	client   *plugin.Client
+	protocol plugin.ClientProtocol
+}
+
+// NewClient will start the plugin at the supplied path and negotiate the protocol
+// version. The plugin is stopped when Close is called.
+func NewClient(path string, logger hclog.Logger) (*Client, error) {
+	client := plugin.NewClient(&plugin.ClientConfig{
+		HandshakeConfig: Handshake,
+		VersionedPlugins: map[int]plugin.PluginSet{
+			ProtocolVersion: PluginSet(),
+		},
+		Cmd:              exec.Command(path), //nolint: gosec
+		AllowedProtocols: []plugin.Protocol{plugin.ProtocolGRPC},
+		Managed:          true,
+		Logger:           logger,
+		SyncStderr:       os.Stderr,
+	})
+
+	protocol, err := client.Client()
+	if err != nil {
+		client.Kill()
+		return nil, fmt.Errorf("starting plugin %s: %w", path, err)
+	}
+
+	return &Client{
+		client:   client,
+		protocol: protocol,
+	}, nil
+}
+
+// Discovery returns the discovery plugin. The plugin may not implement a discovery
+// plugin in which case calls will return ErrNotImplemented.
+func (c *Client) Discovery() (DiscoveryPlugin, error) {
+	raw, err := c.protocol.Dispense(DiscoveryPluginName)
+	if err != nil {
+		return nil, fmt.Errorf("dispensing discovery plugin: %w", err)
+	}
+	discoveryPlugin, ok := raw.(DiscoveryPlugin)
+	if !ok {
+		return nil, ErrUnexpectedPluginType
+	}
+
+	return discoveryPlugin, nil
+}
+
+// Identity returns the identity plugin. The plugin may not implement an identity
+// plugin in which case calls will return ErrNotImplemented.
+func (c *Client) Identity() (IdentityPlugin, error) {
+	raw, err := c.protocol.Dispense(IdentityPluginName)
+	if err != nil {
+		return nil, fmt.Errorf("dispensing identity plugin: %w", err)
+	}
+	identityPlugin, ok := raw.(IdentityPlugin)
+	if !ok {
+		return nil, ErrUnexpectedPluginType
+	}
+
+	return identityPlugin, nil
+}
+
+// Close will stop the plugin
+func (c *Client) Close() {
+	c.client.Kill()
+}
+
+// CleanupClients will stop all the plugins that have been started
+func CleanupClients() {
+	plugin.CleanupClients()
+}
+
+func convertError(err error) error {
+	if err == nil {
+		return nil
+	}
+
+	if status.Code(err) == codes.Unimplemented {
+		return ErrNotImplemented
+	}
+
+	return errors.New(status.Convert(err).Message())
+}
@@ -0,0 +1,166 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+
+	"github.com/fidelity/kconnect/pkg/plugin/pluginpb"
+)
+
+// DiscoveryGRPCPlugin is the go-plugin implementation for a discovery plugin
+type DiscoveryGRPCPlugin struct {
+	plugin.Plugin
+	Impl DiscoveryPlugin
+}
+
+func (p *DiscoveryGRPCPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	pluginpb.RegisterDiscoveryPluginServer(s, &discoveryServer{impl: p.Impl})
+	return nil
+}
+
+func (p *DiscoveryGRPCPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &discoveryClient{client: pluginpb.NewDiscoveryPluginClient(c)}, nil
+}
+
+// discoveryServer runs in the plugin process and calls the plugin implementation
+type discoveryServer struct {
+	impl DiscoveryPlugin
+}
+
+func (s *discoveryServer) Describe(ctx context.Context, req *pluginpb.DescribeRequest) (*pluginpb.Description, error) {
+	return s.impl.Describe(ctx, req)
+}
+
+func (s *discoveryServer) CheckPreReqs(ctx context.Context, req *pluginpb.CheckPreReqsRequest) (*pluginpb.Empty, error) {
+	return &pluginpb.Empty{}, s.impl.CheckPreReqs(ctx, req)
+}
+
+func (s *discoveryServer) Validate(ctx context.Context, req *pluginpb.ValidateRequest) (*pluginpb.Empty, error) {
+	return &pluginpb.Empty{}, s.impl.Validate(ctx, req)
+}
+
+func (s *discoveryServer) Resolve(ctx context.Context, req *pluginpb.ResolveRequest) (*pluginpb.ResolveResponse, error) {
+	return s.impl.Resolve(ctx, req)
+}
+
+func (s *discoveryServer) Discover(req *pluginpb.DiscoverRequest, stream pluginpb.DiscoveryPlugin_DiscoverServer) error {
+	progress := func(message string) {
+		stream.Send(&pluginpb.DiscoverResponse{ //nolint: errcheck
+			Result: &pluginpb.DiscoverResponse_Progress{Progress: &pluginpb.Progress{Message: message}},
+		})
+	}
+
+	result, err := s.impl.Discover(stream.Context(), req, progress)
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&pluginpb.DiscoverResponse{
+		Result: &pluginpb.DiscoverResponse_Clusters{Clusters: result},
+	})
+}
+
+func (s *discoveryServer) GetCluster(ctx context.Context, req *pluginpb.GetClusterRequest) (*pluginpb.GetClusterResponse, error) {
+	return s.impl.GetCluster(ctx, req)
+}
+
+func (s *discoveryServer) GetConfig(ctx context.Context, req *pluginpb.GetConfigRequest) (*pluginpb.GetConfigResponse, error) {
+	return s.impl.GetConfig(ctx, req)
+}
+
+// discoveryClient runs in kconnect and calls the plugin using gRPC
+type discoveryClient struct {
+	client pluginpb.DiscoveryPluginClient
+}
+
+func (c *discoveryClient) Describe(ctx context.Context, req *pluginpb.DescribeRequest) (*pluginpb.Description, error) {
+	resp, err := c.client.Describe(ctx, req)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return resp, nil
+}
+
+func (c *discoveryClient) CheckPreReqs(ctx context.Context, req *pluginpb.CheckPreReqsRequest) error {
+	_, err := c.client.CheckPreReqs(ctx, req)
+	return convertError(err)
+}
+
+func (c *discoveryClient) Validate(ctx context.Context, req *pluginpb.ValidateRequest) error {
+	_, err := c.client.Validate(ctx, req)
+	return convertError(err)
+}
+
+func (c *discoveryClient) Resolve(ctx context.Context, req *pluginpb.ResolveRequest) (*pluginpb.ResolveResponse, error) {
+	resp, err := c.client.Resolve(ctx, req)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return resp, nil
+}
+
+func (c *discoveryClient) Discover(ctx context.Context, req *pluginpb.DiscoverRequest, progress ProgressFunc) (*pluginpb.DiscoverResult, error) {
+	stream, err := c.client.Discover(ctx, req)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("discover: %w", io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return nil, convertError(err)
+		}
+
+		switch result := resp.Result.(type) {
+		case *pluginpb.DiscoverResponse_Progress:
+			if progress != nil {
+				progress(result.Progress.Message)
+			}
+		case *pluginpb.DiscoverResponse_Clusters:
+			return result.Clusters, nil
+		}
+	}
+}
+
+func (c *discoveryClient) GetCluster(ctx context.Context, req *pluginpb.GetClusterRequest) (*pluginpb.GetClusterResponse, error) {
+	resp, err := c.client.GetCluster(ctx, req)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return resp, nil
+}
+
+func (c *discoveryClient) GetConfig(ctx context.Context, req *pluginpb.GetConfigRequest) (*pluginpb.GetConfigResponse, error) {
+	resp, err := c.client.GetConfig(ctx, req)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return resp, nil
+}
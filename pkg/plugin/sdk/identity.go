@@ -0,0 +1,109 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdk
+
+import (
+	"context"
+	"fmt"
+	"io"
+
+	"github.com/hashicorp/go-plugin"
+	"google.golang.org/grpc"
+
+	"github.com/fidelity/kconnect/pkg/plugin/pluginpb"
+)
+
+// IdentityGRPCPlugin is the go-plugin implementation for an identity plugin
+type IdentityGRPCPlugin struct {
+	plugin.Plugin
+	Impl IdentityPlugin
+}
+
+func (p *IdentityGRPCPlugin) GRPCServer(broker *plugin.GRPCBroker, s *grpc.Server) error {
+	pluginpb.RegisterIdentityPluginServer(s, &identityServer{impl: p.Impl})
+	return nil
+}
+
+func (p *IdentityGRPCPlugin) GRPCClient(ctx context.Context, broker *plugin.GRPCBroker, c *grpc.ClientConn) (interface{}, error) {
+	return &identityClient{client: pluginpb.NewIdentityPluginClient(c)}, nil
+}
+
+// identityServer runs in the plugin process and calls the plugin implementation
+type identityServer struct {
+	impl IdentityPlugin
+}
+
+func (s *identityServer) Describe(ctx context.Context, req *pluginpb.DescribeRequest) (*pluginpb.Description, error) {
+	return s.impl.Describe(ctx, req)
+}
+
+func (s *identityServer) Authenticate(req *pluginpb.AuthenticateRequest, stream pluginpb.IdentityPlugin_AuthenticateServer) error {
+	progress := func(message string) {
+		stream.Send(&pluginpb.AuthenticateResponse{ //nolint: errcheck
+			Result: &pluginpb.AuthenticateResponse_Progress{Progress: &pluginpb.Progress{Message: message}},
+		})
+	}
+
+	id, err := s.impl.Authenticate(stream.Context(), req, progress)
+	if err != nil {
+		return err
+	}
+
+	return stream.Send(&pluginpb.AuthenticateResponse{
+		Result: &pluginpb.AuthenticateResponse_Identity{Identity: id},
+	})
+}
+
+// identityClient runs in kconnect and calls the plugin using gRPC
+type identityClient struct {
+	client pluginpb.IdentityPluginClient
+}
+
+func (c *identityClient) Describe(ctx context.Context, req *pluginpb.DescribeRequest) (*pluginpb.Description, error) {
+	resp, err := c.client.Describe(ctx, req)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	return resp, nil
+}
+
+func (c *identityClient) Authenticate(ctx context.Context, req *pluginpb.AuthenticateRequest, progress ProgressFunc) (*pluginpb.Identity, error) {
+	stream, err := c.client.Authenticate(ctx, req)
+	if err != nil {
+		return nil, convertError(err)
+	}
+
+	for {
+		resp, err := stream.Recv()
+		if err == io.EOF {
+			return nil, fmt.Errorf("authenticate: %w", io.ErrUnexpectedEOF)
+		}
+		if err != nil {
+			return nil, convertError(err)
+		}
+
+		switch result := resp.Result.(type) {
+		case *pluginpb.AuthenticateResponse_Progress:
+			if progress != nil {
+				progress(result.Progress.Message)
+			}
+		case *pluginpb.AuthenticateResponse_Identity:
+			return result.Identity, nil
+		}
+	}
+}
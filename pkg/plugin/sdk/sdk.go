@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// Package sdk is used to write long-running kconnect plugins that kconnect talks
+// to using gRPC. A plugin binary implements DiscoveryPlugin and/or IdentityPlugin
+// and calls Serve from its main:
+//
+//	func main() {
+//		sdk.Serve(&sdk.ServeConfig{
+//			Discovery: &myDiscoveryPlugin{},
+//		})
+//	}
+//
+// The binary must be named kconnect-plugin-<name> and be on the PATH. The plugin
+// process is started once and is kept running for the lifetime of the kconnect
+// command, so it can cache expensive state between calls.
+package sdk
+
+import (
+	"context"
+	"errors"
+
+	"github.com/hashicorp/go-plugin"
+
+	"github.com/fidelity/kconnect/pkg/plugin/pluginpb"
+)
+
+const (
+	// ProtocolVersion is the version of the plugin protocol. It's negotiated
+	// with the plugin when it's started.
+	ProtocolVersion = 1
+
+	// PluginPrefix is the prefix of the name of a plugin executable
+	PluginPrefix = "kconnect-plugin-"
+
+	// DiscoveryPluginName is the name the discovery plugin is dispensed as
+	DiscoveryPluginName = "discovery"
+	// IdentityPluginName is the name the identity plugin is dispensed as
+	IdentityPluginName = "identity"
+)
+
+// ErrNotImplemented is returned when the plugin doesn't implement the requested plugin type
+var ErrNotImplemented = errors.New("plugin type not implemented by plugin")
+
+// Handshake is used to make sure that the kconnect and the plugin agree on the
+// protocol and that the plugin was started by kconnect
+var Handshake = plugin.HandshakeConfig{
+	ProtocolVersion:  ProtocolVersion,
+	MagicCookieKey:   "KCONNECT_PLUGIN_MAGIC_COOKIE",
+	MagicCookieValue: "7f2d1c6e-kconnect-plugin",
+}
+
+// ProgressFunc is used by a plugin to report progress of a long-running operation
+type ProgressFunc func(message string)
+
+// DiscoveryPlugin is the interface that a plugin that discovers clusters implements
+type DiscoveryPlugin interface {
+	// Describe returns the name and configuration items of the plugin
+	Describe(ctx context.Context, req *pluginpb.DescribeRequest) (*pluginpb.Description, error)
+	// CheckPreReqs checks the pre-requisites of the plugin
+	CheckPreReqs(ctx context.Context, req *pluginpb.CheckPreReqsRequest) error
+	// Validate validates the configuration
+	Validate(ctx context.Context, req *pluginpb.ValidateRequest) error
+	// Resolve returns any configuration values the plugin can resolve
+	Resolve(ctx context.Context, req *pluginpb.ResolveRequest) (*pluginpb.ResolveResponse, error)
+	// Discover returns the clusters that the identity has access to
+	Discover(ctx context.Context, req *pluginpb.DiscoverRequest, progress ProgressFunc) (*pluginpb.DiscoverResult, error)
+	// GetCluster returns the details of a single cluster
+	GetCluster(ctx context.Context, req *pluginpb.GetClusterRequest) (*pluginpb.GetClusterResponse, error)
+	// GetConfig returns the kubeconfig for a cluster
+	GetConfig(ctx context.Context, req *pluginpb.GetConfigRequest) (*pluginpb.GetConfigResponse, error)
+}
+
+// IdentityPlugin is the interface that a plugin that authenticates users implements
+type IdentityPlugin interface {
+	// Describe returns the name and configuration items of the plugin
+	Describe(ctx context.Context, req *pluginpb.DescribeRequest) (*pluginpb.Description, error)
+	// Authenticate authenticates the user and returns their identity
+	Authenticate(ctx context.Context, req *pluginpb.AuthenticateRequest, progress ProgressFunc) (*pluginpb.Identity, error)
+}
+
+// ServeConfig is the configuration for serving a plugin. At least one of the
+// plugin types must be set.
+type ServeConfig struct {
+	Discovery DiscoveryPlugin
+	Identity  IdentityPlugin
+}
+
+// Serve will serve the plugins to kconnect. It doesn't return until kconnect
+// stops the plugin.
+func Serve(cfg *ServeConfig) {
+	plugins := plugin.PluginSet{}
+	if cfg.Discovery != nil {
+		plugins[DiscoveryPluginName] = &DiscoveryGRPCPlugin{Impl: cfg.Discovery}
+	}
+	if cfg.Identity != nil {
+		plugins[IdentityPluginName] = &IdentityGRPCPlugin{Impl: cfg.Identity}
+	}
+
+	plugin.Serve(&plugin.ServeConfig{
+		HandshakeConfig: Handshake,
+		VersionedPlugins: map[int]plugin.PluginSet{
+			ProtocolVersion: plugins,
+		},
+		GRPCServer: plugin.DefaultGRPCServer,
+	})
+}
+
+// PluginSet returns the plugins used by kconnect when dispensing from a plugin
+func PluginSet() plugin.PluginSet {
+	return plugin.PluginSet{
+		DiscoveryPluginName: &DiscoveryGRPCPlugin{},
+		IdentityPluginName:  &IdentityGRPCPlugin{},
+	}
+}
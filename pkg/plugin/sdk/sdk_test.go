@@ -0,0 +1,126 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package sdk_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/hashicorp/go-plugin"
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/plugin/pluginpb"
+	"github.com/fidelity/kconnect/pkg/plugin/sdk"
+)
+
+var errNotAuthorized = errors.New("not authorized")
+
+type fakeDiscoveryPlugin struct{}
+
+func (f *fakeDiscoveryPlugin) Describe(ctx context.Context, req *pluginpb.DescribeRequest) (*pluginpb.Description, error) {
+	return &pluginpb.Description{Name: "fake", SupportedIdentityProviders: []string{"static-token"}}, nil
+}
+
+func (f *fakeDiscoveryPlugin) CheckPreReqs(ctx context.Context, req *pluginpb.CheckPreReqsRequest) error {
+	return nil
+}
+
+func (f *fakeDiscoveryPlugin) Validate(ctx context.Context, req *pluginpb.ValidateRequest) error {
+	return nil
+}
+
+func (f *fakeDiscoveryPlugin) Resolve(ctx context.Context, req *pluginpb.ResolveRequest) (*pluginpb.ResolveResponse, error) {
+	return &pluginpb.ResolveResponse{Config: map[string]string{"region": "east"}}, nil
+}
+
+func (f *fakeDiscoveryPlugin) Discover(ctx context.Context, req *pluginpb.DiscoverRequest, progress sdk.ProgressFunc) (*pluginpb.DiscoverResult, error) {
+	if req.Identity.GetToken() != "abc" {
+		return nil, errNotAuthorized
+	}
+
+	progress("listing clusters")
+	progress("found 1 cluster")
+
+	return &pluginpb.DiscoverResult{
+		Clusters: []*pluginpb.Cluster{{Id: "cluster1", Name: req.Config["region"] + "-cluster1"}},
+	}, nil
+}
+
+func (f *fakeDiscoveryPlugin) GetCluster(ctx context.Context, req *pluginpb.GetClusterRequest) (*pluginpb.GetClusterResponse, error) {
+	return &pluginpb.GetClusterResponse{Cluster: &pluginpb.Cluster{Id: req.ClusterId}}, nil
+}
+
+func (f *fakeDiscoveryPlugin) GetConfig(ctx context.Context, req *pluginpb.GetConfigRequest) (*pluginpb.GetConfigResponse, error) {
+	return &pluginpb.GetConfigResponse{Kubeconfig: []byte("apiVersion: v1"), ContextName: req.Cluster.Id}, nil
+}
+
+func TestDiscoveryPlugin(t *testing.T) {
+	g := NewWithT(t)
+
+	client, _ := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		sdk.DiscoveryPluginName: &sdk.DiscoveryGRPCPlugin{Impl: &fakeDiscoveryPlugin{}},
+		sdk.IdentityPluginName:  &sdk.IdentityGRPCPlugin{},
+	})
+	defer client.Close()
+
+	raw, err := client.Dispense(sdk.DiscoveryPluginName)
+	g.Expect(err).NotTo(HaveOccurred())
+	discoveryPlugin := raw.(sdk.DiscoveryPlugin)
+	ctx := context.Background()
+
+	desc, err := discoveryPlugin.Describe(ctx, &pluginpb.DescribeRequest{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(desc.Name).To(Equal("fake"))
+
+	messages := []string{}
+	progress := func(message string) {
+		messages = append(messages, message)
+	}
+	result, err := discoveryPlugin.Discover(ctx, &pluginpb.DiscoverRequest{
+		Config:   map[string]string{"region": "west"},
+		Identity: &pluginpb.Identity{Token: "abc"},
+	}, progress)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(messages).To(Equal([]string{"listing clusters", "found 1 cluster"}))
+	g.Expect(result.Clusters).To(HaveLen(1))
+	g.Expect(result.Clusters[0].Name).To(Equal("west-cluster1"))
+
+	_, err = discoveryPlugin.Discover(ctx, &pluginpb.DiscoverRequest{}, progress)
+	g.Expect(err).To(MatchError(errNotAuthorized.Error()))
+
+	configResp, err := discoveryPlugin.GetConfig(ctx, &pluginpb.GetConfigRequest{Cluster: &pluginpb.Cluster{Id: "cluster1"}})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(configResp.ContextName).To(Equal("cluster1"))
+}
+
+func TestIdentityPluginNotImplemented(t *testing.T) {
+	g := NewWithT(t)
+
+	// The test server registers every plugin in the set, so only register the
+	// discovery plugin to simulate a plugin binary without an identity plugin
+	client, _ := plugin.TestPluginGRPCConn(t, map[string]plugin.Plugin{
+		sdk.DiscoveryPluginName: &sdk.DiscoveryGRPCPlugin{Impl: &fakeDiscoveryPlugin{}},
+	})
+	defer client.Close()
+
+	identityPlugin, err := (&sdk.IdentityGRPCPlugin{}).GRPCClient(context.Background(), nil, client.Conn)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = identityPlugin.(sdk.IdentityPlugin).Describe(context.Background(), &pluginpb.DescribeRequest{})
+	g.Expect(err).To(MatchError(sdk.ErrNotImplemented))
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	awsgo "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
+	"github.com/aws/smithy-go/middleware"
+	smithyhttp "github.com/aws/smithy-go/transport/http"
+
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+)
+
+const (
+	accessReviewPath    = "/apis/authorization.k8s.io/v1/selfsubjectaccessreviews"
+	accessReviewTimeout = 10 * time.Second
+	tokenPrefix         = "k8s-aws-v1."
+	tokenExpiry         = 60 * time.Second
+	clusterIDHeader     = "x-k8s-aws-id"
+)
+
+var (
+	ErrUnauthorized  = errors.New("the identity isn't authorized by the cluster")
+	ErrAccessDenied  = errors.New("the identity doesn't have access to the cluster")
+	ErrInvalidCACert = errors.New("invalid cluster certificate authority")
+
+	ErrAccessReviewFailed = errors.New("access review failed")
+)
+
+type accessReview struct {
+	APIVersion string             `json:"apiVersion"`
+	Kind       string             `json:"kind"`
+	Spec       accessReviewSpec   `json:"spec"`
+	Status     accessReviewStatus `json:"status,omitempty"`
+}
+
+type accessReviewSpec struct {
+	ResourceAttributes accessReviewAttributes `json:"resourceAttributes"`
+}
+
+type accessReviewAttributes struct {
+	Namespace string `json:"namespace,omitempty"`
+	Verb      string `json:"verb"`
+	Resource  string `json:"resource"`
+}
+
+type accessReviewStatus struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"`
+}
+
+// verifyAccess checks that the identity can access the cluster by asking the cluster
+// if it can list pods. This catches a missing access entry or aws-auth mapping before
+// the kubeconfig is written. Any problem is a warning as the kubeconfig is still written.
+func (p *eksClusterProvider) verifyAccess(ctx context.Context, cluster *discovery.Cluster, certData []byte, namespace string) {
+	p.logger.Debugw("verifying access to cluster", "cluster", cluster.Name)
+
+	err := p.reviewAccess(ctx, cluster, certData, namespace)
+	switch {
+	case err == nil:
+		p.logger.Debugw("verified access to cluster", "cluster", cluster.Name)
+	case errors.Is(err, ErrUnauthorized), errors.Is(err, ErrAccessDenied):
+		p.logger.Warnw("kubeconfig may not work, check the access entries or the aws-auth mapping of the cluster", "cluster", cluster.Name, "identity", p.callerARN(ctx), "reason", err.Error())
+	default:
+		p.logger.Warnw("unable to verify access to the cluster", "cluster", cluster.Name, "error", err.Error())
+	}
+}
+
+func (p *eksClusterProvider) reviewAccess(ctx context.Context, cluster *discovery.Cluster, certData []byte, namespace string) error {
+	token, err := p.getToken(ctx, cluster.Name)
+	if err != nil {
+		return fmt.Errorf("getting token: %w", err)
+	}
+
+	certPool := x509.NewCertPool()
+	if !certPool.AppendCertsFromPEM(certData) {
+		return ErrInvalidCACert
+	}
+	transport := &http.Transport{
+		TLSClientConfig: &tls.Config{RootCAs: certPool, MinVersion: tls.VersionTLS12},
+	}
+	if p.clusterProxyURL != "" {
+		proxyURL, err := url.Parse(p.clusterProxyURL)
+		if err != nil {
+			return fmt.Errorf("parsing cluster proxy url: %w", err)
+		}
+		transport.Proxy = http.ProxyURL(proxyURL)
+	}
+	client := &http.Client{
+		Timeout:   accessReviewTimeout,
+		Transport: transport,
+	}
+
+	review := &accessReview{
+		APIVersion: "authorization.k8s.io/v1",
+		Kind:       "SelfSubjectAccessReview",
+		Spec: accessReviewSpec{
+			ResourceAttributes: accessReviewAttributes{
+				Namespace: namespace,
+				Verb:      "list",
+				Resource:  "pods",
+			},
+		},
+	}
+	body, err := json.Marshal(review)
+	if err != nil {
+		return fmt.Errorf("marshalling access review: %w", err)
+	}
+
+	reviewURL := strings.TrimSuffix(*cluster.ControlPlaneEndpoint, "/") + accessReviewPath
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, reviewURL, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating access review request: %w", err)
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("requesting access review: %w", err)
+	}
+	defer resp.Body.Close()
+
+	switch resp.StatusCode {
+	case http.StatusCreated, http.StatusOK:
+	case http.StatusUnauthorized:
+		return ErrUnauthorized
+	case http.StatusForbidden:
+		return ErrAccessDenied
+	default:
+		return fmt.Errorf("status %d: %w", resp.StatusCode, ErrAccessReviewFailed)
+	}
+
+	result := &accessReview{}
+	if err := json.NewDecoder(resp.Body).Decode(result); err != nil {
+		return fmt.Errorf("decoding access review: %w", err)
+	}
+	if !result.Status.Allowed {
+		return ErrAccessDenied
+	}
+
+	return nil
+}
+
+// getToken creates a token for the cluster in the same way as aws-iam-authenticator,
+// which is a presigned STS GetCallerIdentity request
+func (p *eksClusterProvider) getToken(ctx context.Context, clusterName string) (string, error) {
+	presigned, err := p.stsPresigner.PresignGetCallerIdentity(ctx, &sts.GetCallerIdentityInput{}, func(o *sts.PresignOptions) {
+		o.ClientOptions = append(o.ClientOptions, func(o *sts.Options) {
+			o.APIOptions = append(o.APIOptions, smithyhttp.AddHeaderValue(clusterIDHeader, clusterName), addPresignExpiry)
+		})
+	})
+	if err != nil {
+		return "", fmt.Errorf("presigning sts request: %w", err)
+	}
+
+	return tokenPrefix + base64.RawURLEncoding.EncodeToString([]byte(presigned.URL)), nil
+}
+
+// addPresignExpiry sets how long the presigned request is valid for, which the signer
+// reads from the X-Amz-Expires query parameter
+func addPresignExpiry(stack *middleware.Stack) error {
+	return stack.Build.Add(middleware.BuildMiddlewareFunc("kconnect/PresignExpiry", func(ctx context.Context, in middleware.BuildInput, next middleware.BuildHandler) (middleware.BuildOutput, middleware.Metadata, error) {
+		if req, ok := in.Request.(*smithyhttp.Request); ok {
+			query := req.URL.Query()
+			query.Set("X-Amz-Expires", strconv.Itoa(int(tokenExpiry/time.Second)))
+			req.URL.RawQuery = query.Encode()
+		}
+
+		return next.HandleBuild(ctx, in)
+	}), middleware.After)
+}
+
+func (p *eksClusterProvider) callerARN(ctx context.Context) string {
+	output, err := p.stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return "unknown"
+	}
+
+	return awsgo.ToString(output.Arn)
+}
@@ -0,0 +1,90 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"fmt"
+
+	awsgo "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/iam"
+	"github.com/aws/aws-sdk-go-v2/service/organizations"
+
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+)
+
+// setAccountAliases will annotate the clusters with the alias of their account, so that
+// clusters with the same name in different accounts can be told apart. An account without
+// an alias, or that the identity can't look up, isn't annotated.
+func (p *eksClusterProvider) setAccountAliases(ctx context.Context, clusters []*discovery.Cluster) {
+	for _, cluster := range clusters {
+		if cluster.Account == "" {
+			continue
+		}
+		if alias := p.accountAlias(ctx, cluster.Account); alias != "" {
+			cluster.SetAnnotation(AnnotationAccountAlias, alias)
+		}
+	}
+}
+
+// accountAlias returns the alias of the account. The IAM account alias is used for the
+// account of the caller, otherwise the name of the account in AWS Organizations.
+func (p *eksClusterProvider) accountAlias(ctx context.Context, accountID string) string {
+	if p.accountAliases == nil {
+		p.accountAliases = make(map[string]string)
+	}
+	if alias, ok := p.accountAliases[accountID]; ok {
+		return alias
+	}
+
+	alias, err := p.lookupAccountAlias(ctx, accountID)
+	if err != nil {
+		p.logger.Debugw("looking up account alias", "account", accountID, "error", err.Error())
+	}
+	p.accountAliases[accountID] = alias
+
+	return alias
+}
+
+func (p *eksClusterProvider) lookupAccountAlias(ctx context.Context, accountID string) (string, error) {
+	callerIdentity, err := p.getCallerIdentity(ctx)
+	if err != nil {
+		return "", err
+	}
+
+	if callerIdentity.AccountID == accountID {
+		output, err := p.iamClient.ListAccountAliases(ctx, &iam.ListAccountAliasesInput{})
+		if err != nil {
+			return "", fmt.Errorf("listing iam account aliases: %w", err)
+		}
+		if len(output.AccountAliases) > 0 {
+			return output.AccountAliases[0], nil
+		}
+	}
+
+	output, err := p.orgClient.DescribeAccount(ctx, &organizations.DescribeAccountInput{
+		AccountId: awsgo.String(accountID),
+	})
+	if err != nil {
+		return "", fmt.Errorf("describing organizations account: %w", err)
+	}
+	if output.Account == nil {
+		return "", nil
+	}
+
+	return awsgo.ToString(output.Account.Name), nil
+}
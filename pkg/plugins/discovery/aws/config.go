@@ -23,12 +23,21 @@ import (
 
 	"k8s.io/client-go/tools/clientcmd/api"
 
+	"github.com/fidelity/kconnect/pkg/aws"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 )
 
 func (p *eksClusterProvider) GetConfig(ctx context.Context, input *discovery.GetConfigInput) (*discovery.GetConfigOutput, error) {
+	p.clusterProxyURL = input.ProxyURL
+	if err := p.describeSelected(ctx, input.Cluster); err != nil {
+		return nil, fmt.Errorf("describing cluster %s: %w", input.Cluster.Name, err)
+	}
+
 	clusterName := fmt.Sprintf("eks-%s", input.Cluster.Name)
 	userName := p.identity.ProfileName
+	if userName == "" {
+		userName = p.identity.IDProviderName
+	}
 	contextName := fmt.Sprintf("%s@%s", userName, clusterName)
 
 	certData, err := base64.StdEncoding.DecodeString(*input.Cluster.CertificateAuthorityData)
@@ -59,12 +68,24 @@ func (p *eksClusterProvider) GetConfig(ctx context.Context, input *discovery.Get
 			"-i",
 			input.Cluster.Name,
 		},
-		Env: []api.ExecEnvVar{
+	}
+	// Without a profile the authenticator uses the ambient credentials
+	if p.identity.ProfileName != "" {
+		execConfig.Env = []api.ExecEnvVar{
 			{
 				Name:  "AWS_PROFILE",
 				Value: p.identity.ProfileName,
 			},
-		},
+		}
+	}
+
+	// Outside of the commercial partition the token must be signed by the
+	// regional STS endpoint of the partition
+	if p.config != nil && p.config.Partition != "" && p.config.Partition != aws.AWSPartitionID {
+		execConfig.Env = append(execConfig.Env,
+			api.ExecEnvVar{Name: "AWS_REGION", Value: p.clusterRegion(input.Cluster)},
+			api.ExecEnvVar{Name: "AWS_STS_REGIONAL_ENDPOINTS", Value: "regional"},
+		)
 	}
 
 	cfg.AuthInfos = map[string]*api.AuthInfo{
@@ -80,6 +101,11 @@ func (p *eksClusterProvider) GetConfig(ctx context.Context, input *discovery.Get
 		cfg.Contexts[contextName].Namespace = *input.Namespace
 	}
 
+	p.checkEndpointReachable(input.Cluster)
+	if p.config != nil && p.config.VerifyAccess {
+		p.verifyAccess(ctx, input.Cluster, certData, cfg.Contexts[contextName].Namespace)
+	}
+
 	return &discovery.GetConfigOutput{
 		KubeConfig:  cfg,
 		ContextName: &contextName,
@@ -0,0 +1,46 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"net/url"
+
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+
+	"github.com/fidelity/kconnect/pkg/aws"
+	"github.com/fidelity/kconnect/pkg/config"
+)
+
+// DashboardURL returns the URL of the EKS cluster in the AWS console
+func (p *eksClusterProvider) DashboardURL(cs config.ConfigurationSet, clusterID string) (string, error) {
+	clusterName, region, err := p.parseClusterID(clusterID)
+	if err != nil {
+		return "", err
+	}
+	clusterARN, _ := arn.Parse(clusterID) //nolint: errcheck
+
+	domain := "console.aws.amazon.com"
+	switch clusterARN.Partition {
+	case aws.AWSCnPartitionID:
+		domain = "console.amazonaws.cn"
+	case aws.AWSUsGovPartitionID:
+		domain = "console.amazonaws-us-gov.com"
+	}
+
+	return fmt.Sprintf("https://%s.%s/eks/home?region=%s#/clusters/%s", region, domain, region, url.PathEscape(clusterName)), nil
+}
@@ -19,24 +19,37 @@ package aws
 import (
 	"context"
 	"fmt"
+	"sync"
+	"time"
 
-	awsgo "github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/service/eks"
+	awsgo "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/aws/retry"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 
+	"github.com/fidelity/kconnect/pkg/aws"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 )
 
+const (
+	// describeWorkers is the maximum number of clusters described at the same time
+	describeWorkers = 10
+	// throttleRetries is the number of times a throttled request is retried
+	throttleRetries  = 5
+	throttleMinDelay = 50 * time.Millisecond
+	throttleMaxDelay = 5 * time.Second
+
+	// eksARNService is the service in the ARN of an EKS cluster
+	eksARNService = "eks"
+)
+
 func (p *eksClusterProvider) Discover(ctx context.Context, input *discovery.DiscoverInput) (*discovery.DiscoverOutput, error) {
-	if err := p.setup(input.ConfigSet, input.Identity); err != nil {
+	if err := p.setup(ctx, input.ConfigSet, input.Identity); err != nil {
 		return nil, fmt.Errorf("setting up eks provider: %w", err)
 	}
 
-	p.logger.Info("discovering EKS clusters")
-
-	clusters, err := p.listClusters()
-	if err != nil {
-		return nil, fmt.Errorf("listing clusters: %w", err)
-	}
+	p.logger.Infow("discovering EKS clusters", "regions", p.regions)
 
 	discoverOutput := &discovery.DiscoverOutput{
 		DiscoveryProvider: ProviderName,
@@ -44,53 +57,285 @@ func (p *eksClusterProvider) Discover(ctx context.Context, input *discovery.Disc
 		Clusters:          make(map[string]*discovery.Cluster),
 	}
 
-	if len(clusters) == 0 {
-		p.logger.Info("no EKS clusters discovered")
-		return discoverOutput, nil
-	}
+	for _, region := range p.regions {
+		eksClient := p.eksClients[region]
 
-	for _, clusterName := range clusters {
-		clusterDetail, err := p.getClusterConfig(*clusterName)
+		clusters, err := p.listClusters(ctx, eksClient)
 		if err != nil {
-			return nil, fmt.Errorf("getting cluster config: %w", err)
+			return nil, fmt.Errorf("listing clusters in region %s: %w", region, err)
+		}
+		if len(clusters) == 0 {
+			p.logger.Debugw("no EKS clusters in region", "region", region)
+			continue
 		}
-		discoverOutput.Clusters[clusterDetail.ID] = clusterDetail
 
+		var details []*discovery.Cluster
+		if p.config.LazyDescribe {
+			details, err = p.listedClusters(ctx, region, clusters)
+		} else {
+			details, err = p.describeClusters(ctx, eksClient, clusters)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("getting cluster config in region %s: %w", region, err)
+		}
+		p.setAccountAliases(ctx, details)
+		for _, clusterDetail := range details {
+			discoverOutput.Clusters[clusterDetail.ID] = clusterDetail
+		}
+		input.ClustersFound(details...)
+	}
+
+	if len(discoverOutput.Clusters) == 0 {
+		p.logger.Info("no EKS clusters discovered")
 	}
 
 	return discoverOutput, nil
 }
 
-func (p *eksClusterProvider) listClusters() ([]*string, error) {
-	input := &eks.ListClustersInput{}
+// describeClusters will get the details of the clusters concurrently using a bounded
+// number of workers. The clusters are returned in the order they were listed and
+// the first error cancels the remaining requests.
+func (p *eksClusterProvider) describeClusters(ctx context.Context, eksClient aws.EKSAPI, clusterNames []string) ([]*discovery.Cluster, error) {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+
+	workers := describeWorkers
+	if len(clusterNames) < workers {
+		workers = len(clusterNames)
+	}
+
+	indexes := make(chan int)
+	results := make([]*discovery.Cluster, len(clusterNames))
+	limiter := &throttle{}
+
+	var firstErr error
+	errOnce := sync.Once{}
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for index := range indexes {
+				cluster, err := p.describeCluster(ctx, eksClient, limiter, clusterNames[index])
+				if err != nil {
+					errOnce.Do(func() {
+						firstErr = err
+						cancel()
+					})
+					continue
+				}
+				results[index] = cluster
+			}
+		}()
+	}
+
+feed:
+	for i := range clusterNames {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+
+	return results, nil
+}
+
+// describeCluster will get the details of a cluster, backing off and retrying if
+// the request is throttled by AWS
+func (p *eksClusterProvider) describeCluster(ctx context.Context, eksClient aws.EKSAPI, limiter *throttle, clusterName string) (*discovery.Cluster, error) {
+	for attempt := 0; ; attempt++ {
+		if err := limiter.wait(ctx); err != nil {
+			return nil, err
+		}
+
+		cluster, err := p.getClusterConfig(ctx, eksClient, clusterName)
+		if err == nil {
+			limiter.succeeded()
+			return cluster, nil
+		}
+		if !isThrottlingError(err) || attempt >= throttleRetries {
+			return nil, err
+		}
+
+		p.logger.Debugw("describing cluster throttled, backing off", "cluster", clusterName, "attempt", attempt+1)
+		limiter.throttled()
+	}
+}
+
+func isThrottlingError(err error) bool {
+	return retry.IsErrorThrottles(retry.DefaultThrottles).IsErrorThrottle(err).Bool()
+}
+
+// throttle is shared by the workers so that all the requests slow down when AWS
+// starts throttling and then speed back up as the requests succeed
+type throttle struct {
+	lock  sync.Mutex
+	delay time.Duration
+}
+
+func (t *throttle) wait(ctx context.Context) error {
+	t.lock.Lock()
+	delay := t.delay
+	t.lock.Unlock()
+
+	if delay == 0 {
+		return ctx.Err()
+	}
+
+	timer := time.NewTimer(delay)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
+
+func (t *throttle) throttled() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.delay *= 2
+	if t.delay < throttleMinDelay {
+		t.delay = throttleMinDelay
+	}
+	if t.delay > throttleMaxDelay {
+		t.delay = throttleMaxDelay
+	}
+}
+
+func (t *throttle) succeeded() {
+	t.lock.Lock()
+	defer t.lock.Unlock()
+
+	t.delay /= 2
+	if t.delay < throttleMinDelay {
+		t.delay = 0
+	}
+}
+
+func (p *eksClusterProvider) listClusters(ctx context.Context, eksClient aws.EKSAPI) ([]string, error) {
+	paginator := eks.NewListClustersPaginator(eksClient, &eks.ListClustersInput{})
 
-	clusters := []*string{}
-	err := p.eksClient.ListClustersPages(input, func(page *eks.ListClustersOutput, lastPage bool) bool {
+	clusters := []string{}
+	for paginator.HasMorePages() {
+		page, err := paginator.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("listing clusters: %w", err)
+		}
 		clusters = append(clusters, page.Clusters...)
-		return true
-	})
+	}
+
+	return clusters, nil
+}
+
+// listedClusters creates the clusters from just their names, without describing
+// them. The ARN of each cluster is built from the account of the caller.
+func (p *eksClusterProvider) listedClusters(ctx context.Context, region string, clusterNames []string) ([]*discovery.Cluster, error) {
+	callerIdentity, err := p.getCallerIdentity(ctx)
 	if err != nil {
-		return nil, fmt.Errorf("listing clusters: %w", err)
+		return nil, err
+	}
+
+	clusters := []*discovery.Cluster{}
+	for _, name := range clusterNames {
+		clusterARN := arn.ARN{
+			Partition: callerIdentity.Partition,
+			Service:   eksARNService,
+			Region:    region,
+			AccountID: callerIdentity.AccountID,
+			Resource:  "cluster/" + name,
+		}
+		clusters = append(clusters, &discovery.Cluster{
+			ID:      clusterARN.String(),
+			Name:    name,
+			Account: clusterARN.AccountID,
+			Region:  region,
+		})
 	}
 
 	return clusters, nil
 }
 
-func (p *eksClusterProvider) getClusterConfig(clusterName string) (*discovery.Cluster, error) {
+// getCallerIdentity returns the ARN of the caller, getting it the first time it's needed
+func (p *eksClusterProvider) getCallerIdentity(ctx context.Context) (*arn.ARN, error) {
+	if p.callerIdentity != nil {
+		return p.callerIdentity, nil
+	}
+
+	output, err := p.stsClient.GetCallerIdentity(ctx, &sts.GetCallerIdentityInput{})
+	if err != nil {
+		return nil, fmt.Errorf("getting caller identity: %w", err)
+	}
+	callerARN, err := arn.Parse(awsgo.ToString(output.Arn))
+	if err != nil {
+		return nil, fmt.Errorf("parsing caller identity arn: %w", err)
+	}
+	p.callerIdentity = &callerARN
+
+	return p.callerIdentity, nil
+}
+
+// describeSelected will describe a cluster that was discovered without its details
+func (p *eksClusterProvider) describeSelected(ctx context.Context, cluster *discovery.Cluster) error {
+	if cluster.ControlPlaneEndpoint != nil && cluster.CertificateAuthorityData != nil {
+		return nil
+	}
+
+	p.logger.Debugw("describing selected cluster", "id", cluster.ID)
+	eksClient, err := p.eksClientForRegion(ctx, p.clusterRegion(cluster))
+	if err != nil {
+		return err
+	}
+	described, err := p.getClusterConfig(ctx, eksClient, cluster.Name)
+	if err != nil {
+		return err
+	}
+
+	cluster.ControlPlaneEndpoint = described.ControlPlaneEndpoint
+	cluster.CertificateAuthorityData = described.CertificateAuthorityData
+	cluster.Tags = described.Tags
+	for key, value := range described.Annotations {
+		cluster.SetAnnotation(key, value)
+	}
+
+	return nil
+}
+
+func (p *eksClusterProvider) getClusterConfig(ctx context.Context, eksClient aws.EKSAPI, clusterName string) (*discovery.Cluster, error) {
 
 	input := &eks.DescribeClusterInput{
 		Name: awsgo.String(clusterName),
 	}
 
-	output, err := p.eksClient.DescribeCluster(input)
+	output, err := eksClient.DescribeCluster(ctx, input)
 	if err != nil {
 		return nil, fmt.Errorf("describing cluster %s: %w", clusterName, err)
 	}
 
-	return &discovery.Cluster{
+	cluster := &discovery.Cluster{
 		ID:                       *output.Cluster.Arn,
 		Name:                     *output.Cluster.Name,
 		ControlPlaneEndpoint:     output.Cluster.Endpoint,
 		CertificateAuthorityData: output.Cluster.CertificateAuthority.Data,
-	}, nil
+		Tags:                     output.Cluster.Tags,
+	}
+	if clusterARN, err := arn.Parse(cluster.ID); err == nil {
+		cluster.Account = clusterARN.AccountID
+		cluster.Region = clusterARN.Region
+	}
+	setPlatformAnnotations(cluster, output.Cluster)
+
+	return cluster, nil
 }
@@ -0,0 +1,238 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	awsgo "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/eks"
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"github.com/aws/smithy-go"
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+)
+
+var errDescribeFailed = errors.New("describe failed")
+
+// fakeEKS describes clusters after an optional delay. A cluster can be throttled a
+// number of times before it's described, fail or block until the request is cancelled.
+type fakeEKS struct {
+	lock      sync.Mutex
+	calls     map[string]int
+	throttles map[string]int
+	failures  map[string]bool
+	delays    map[string]time.Duration
+	block     bool
+	started   chan string
+}
+
+func newFakeEKS() *fakeEKS {
+	return &fakeEKS{
+		calls:     map[string]int{},
+		throttles: map[string]int{},
+		failures:  map[string]bool{},
+		delays:    map[string]time.Duration{},
+	}
+}
+
+func (f *fakeEKS) ListClusters(ctx context.Context, params *eks.ListClustersInput, optFns ...func(*eks.Options)) (*eks.ListClustersOutput, error) {
+	return &eks.ListClustersOutput{}, nil
+}
+
+func (f *fakeEKS) DescribeCluster(ctx context.Context, params *eks.DescribeClusterInput, optFns ...func(*eks.Options)) (*eks.DescribeClusterOutput, error) {
+	name := awsgo.ToString(params.Name)
+
+	f.lock.Lock()
+	f.calls[name]++
+	throttled := f.calls[name] <= f.throttles[name]
+	f.lock.Unlock()
+
+	if f.started != nil {
+		f.started <- name
+	}
+	if f.block {
+		<-ctx.Done()
+		return nil, ctx.Err()
+	}
+	if throttled {
+		return nil, &smithy.GenericAPIError{Code: "ThrottlingException", Message: "Rate exceeded"}
+	}
+	if f.failures[name] {
+		return nil, errDescribeFailed
+	}
+
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(f.delays[name]):
+	}
+
+	return &eks.DescribeClusterOutput{
+		Cluster: &types.Cluster{
+			Arn:                  awsgo.String("arn:aws:eks:eu-west-1:000000000000:cluster/" + name),
+			Name:                 awsgo.String(name),
+			Endpoint:             awsgo.String("https://" + name + ".eks.amazonaws.com"),
+			CertificateAuthority: &types.Certificate{Data: awsgo.String("Y2VydA==")},
+		},
+	}, nil
+}
+
+func (f *fakeEKS) callCount(name string) int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.calls[name]
+}
+
+func (f *fakeEKS) totalCalls() int {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	total := 0
+	for _, calls := range f.calls {
+		total += calls
+	}
+
+	return total
+}
+
+func newTestProvider() *eksClusterProvider {
+	return &eksClusterProvider{
+		logger: zap.NewNop().Sugar(),
+	}
+}
+
+func clusterNames(count int) []string {
+	names := []string{}
+	for i := 0; i < count; i++ {
+		names = append(names, fmt.Sprintf("cluster-%02d", i))
+	}
+
+	return names
+}
+
+func TestDescribeClustersOrder(t *testing.T) {
+	g := NewWithT(t)
+
+	names := clusterNames(3 * describeWorkers)
+	fake := newFakeEKS()
+	// The clusters listed first take the longest to describe
+	for i, name := range names {
+		fake.delays[name] = time.Duration(len(names)-i) * time.Millisecond
+	}
+
+	clusters, err := newTestProvider().describeClusters(context.Background(), fake, names)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(clusters).To(HaveLen(len(names)))
+	for i, cluster := range clusters {
+		g.Expect(cluster.Name).To(Equal(names[i]))
+		g.Expect(cluster.Account).To(Equal("000000000000"))
+		g.Expect(cluster.Region).To(Equal("eu-west-1"))
+	}
+}
+
+func TestDescribeClustersThrottling(t *testing.T) {
+	g := NewWithT(t)
+
+	fake := newFakeEKS()
+	fake.throttles["cluster-00"] = 2
+
+	start := time.Now()
+	clusters, err := newTestProvider().describeClusters(context.Background(), fake, clusterNames(1))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(clusters).To(HaveLen(1))
+	g.Expect(fake.callCount("cluster-00")).To(Equal(3))
+	// The backoff doubles after each throttled request
+	g.Expect(time.Since(start)).To(BeNumerically(">=", throttleMinDelay*3))
+}
+
+func TestDescribeClustersThrottlingRetriesExhausted(t *testing.T) {
+	g := NewWithT(t)
+
+	fake := newFakeEKS()
+	fake.throttles["cluster-00"] = throttleRetries + 1
+
+	_, err := newTestProvider().describeClusters(context.Background(), fake, clusterNames(1))
+	g.Expect(err).To(HaveOccurred())
+	g.Expect(isThrottlingError(err)).To(BeTrue())
+	g.Expect(fake.callCount("cluster-00")).To(Equal(throttleRetries + 1))
+}
+
+func TestDescribeClustersErrorNotRetried(t *testing.T) {
+	g := NewWithT(t)
+
+	fake := newFakeEKS()
+	fake.failures["cluster-00"] = true
+
+	_, err := newTestProvider().describeClusters(context.Background(), fake, clusterNames(1))
+	g.Expect(err).To(MatchError(errDescribeFailed))
+	g.Expect(fake.callCount("cluster-00")).To(Equal(1))
+}
+
+func TestDescribeClustersCancelled(t *testing.T) {
+	g := NewWithT(t)
+
+	names := clusterNames(5 * describeWorkers)
+	fake := newFakeEKS()
+	fake.block = true
+	fake.started = make(chan string, len(names))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	defer cancel()
+
+	done := make(chan error)
+	go func() {
+		_, err := newTestProvider().describeClusters(ctx, fake, names)
+		done <- err
+	}()
+
+	// Cancel once all the workers are describing a cluster
+	for i := 0; i < describeWorkers; i++ {
+		<-fake.started
+	}
+	cancel()
+
+	var err error
+	g.Eventually(done, 5*time.Second).Should(Receive(&err))
+	g.Expect(errors.Is(err, context.Canceled)).To(BeTrue())
+	// The clusters that weren't started aren't described
+	g.Expect(fake.totalCalls()).To(Equal(describeWorkers))
+}
+
+func TestDescribeClustersFirstErrorCancels(t *testing.T) {
+	g := NewWithT(t)
+
+	names := clusterNames(5 * describeWorkers)
+	fake := newFakeEKS()
+	fake.failures[names[0]] = true
+	for _, name := range names[1:] {
+		fake.delays[name] = time.Minute
+	}
+
+	start := time.Now()
+	_, err := newTestProvider().describeClusters(context.Background(), fake, names)
+	g.Expect(err).To(MatchError(errDescribeFailed))
+	g.Expect(time.Since(start)).To(BeNumerically("<", 5*time.Second))
+	// Only the clusters that the workers had started are described
+	g.Expect(fake.totalCalls()).To(BeNumerically("<=", describeWorkers))
+}
@@ -21,7 +21,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/aws/aws-sdk-go/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
 
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 )
@@ -32,17 +32,22 @@ const (
 
 // Get will get the details of a EKS cluster. The clusterID maps to a ARN
 func (p *eksClusterProvider) GetCluster(ctx context.Context, input *discovery.GetClusterInput) (*discovery.GetClusterOutput, error) {
-	if err := p.setup(input.ConfigSet, input.Identity); err != nil {
+	if err := p.setup(ctx, input.ConfigSet, input.Identity); err != nil {
 		return nil, fmt.Errorf("setting up eks provider: %w", err)
 	}
 
 	p.logger.Infow("getting EKS cluster", "id", input.ClusterID)
-	clusterName, err := p.getClusterName(input.ClusterID)
+	clusterName, region, err := p.parseClusterID(input.ClusterID)
 	if err != nil {
 		return nil, fmt.Errorf("getting cluster name for cluster id %s: %w", input.ClusterID, err)
 	}
 
-	cluster, err := p.getClusterConfig(clusterName)
+	eksClient, err := p.eksClientForRegion(ctx, region)
+	if err != nil {
+		return nil, err
+	}
+
+	cluster, err := p.getClusterConfig(ctx, eksClient, clusterName)
 	if err != nil {
 		return nil, fmt.Errorf("getting cluster config for %s: %w", input.ClusterID, err)
 	}
@@ -52,16 +57,30 @@ func (p *eksClusterProvider) GetCluster(ctx context.Context, input *discovery.Ge
 	}, nil
 }
 
-func (p *eksClusterProvider) getClusterName(clusterID string) (string, error) {
+// parseClusterID returns the name and region of the cluster from its ARN
+func (p *eksClusterProvider) parseClusterID(clusterID string) (string, string, error) {
 	clusterARN, err := arn.Parse(clusterID)
 	if err != nil {
-		return "", fmt.Errorf("parsing cluster id as ARN: %w", err)
+		return "", "", fmt.Errorf("parsing cluster id as ARN: %w", err)
 	}
 
 	parts := strings.Split(clusterARN.Resource, "/")
 	if len(parts) != expectedNameParts {
-		return "", ErrUnexpectedClusterFormat
+		return "", "", ErrUnexpectedClusterFormat
+	}
+
+	return parts[1], clusterARN.Region, nil
+}
+
+// clusterRegion returns the region of the cluster, falling back to the first
+// region if the cluster id isn't an ARN
+func (p *eksClusterProvider) clusterRegion(cluster *discovery.Cluster) string {
+	if _, region, err := p.parseClusterID(cluster.ID); err == nil && region != "" {
+		return region
+	}
+	if len(p.regions) > 0 {
+		return p.regions[0]
 	}
 
-	return parts[1], nil
+	return p.identity.Region
 }
@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aws
+
+import (
+	"fmt"
+	"net"
+	"net/url"
+	"strconv"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/eks/types"
+	"golang.org/x/net/proxy"
+
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+)
+
+const (
+	AnnotationKubernetesVersion     = "eks.amazonaws.com/kubernetes-version"
+	AnnotationPlatformVersion       = "eks.amazonaws.com/platform-version"
+	AnnotationStatus                = "eks.amazonaws.com/status"
+	AnnotationEndpointPublicAccess  = "eks.amazonaws.com/endpoint-public-access"
+	AnnotationEndpointPrivateAccess = "eks.amazonaws.com/endpoint-private-access"
+	AnnotationEndpointAccess        = "eks.amazonaws.com/endpoint-access"
+	AnnotationVPCID                 = "eks.amazonaws.com/vpc-id"
+	AnnotationAccountAlias          = "aws.amazon.com/account-alias"
+
+	endpointAccessPublic        = "public"
+	endpointAccessPrivate       = "private"
+	endpointAccessPublicPrivate = "public-and-private"
+
+	endpointDialTimeout = 3 * time.Second
+)
+
+// setPlatformAnnotations will add the EKS platform details of the cluster as annotations
+func setPlatformAnnotations(cluster *discovery.Cluster, eksCluster *types.Cluster) {
+	setAnnotation(cluster, AnnotationKubernetesVersion, eksCluster.Version)
+	setAnnotation(cluster, AnnotationPlatformVersion, eksCluster.PlatformVersion)
+	if eksCluster.Status != "" {
+		cluster.SetAnnotation(AnnotationStatus, string(eksCluster.Status))
+	}
+
+	if vpcConfig := eksCluster.ResourcesVpcConfig; vpcConfig != nil {
+		cluster.SetAnnotation(AnnotationEndpointPublicAccess, strconv.FormatBool(vpcConfig.EndpointPublicAccess))
+		cluster.SetAnnotation(AnnotationEndpointPrivateAccess, strconv.FormatBool(vpcConfig.EndpointPrivateAccess))
+		setAnnotation(cluster, AnnotationVPCID, vpcConfig.VpcId)
+		if access := endpointAccess(vpcConfig); access != "" {
+			cluster.SetAnnotation(AnnotationEndpointAccess, access)
+		}
+	}
+}
+
+// endpointAccess returns how the cluster endpoint can be accessed
+func endpointAccess(vpcConfig *types.VpcConfigResponse) string {
+	public := vpcConfig.EndpointPublicAccess
+	private := vpcConfig.EndpointPrivateAccess
+	switch {
+	case public && private:
+		return endpointAccessPublicPrivate
+	case public:
+		return endpointAccessPublic
+	case private:
+		return endpointAccessPrivate
+	default:
+		return ""
+	}
+}
+
+func setAnnotation(cluster *discovery.Cluster, key string, value *string) {
+	if value != nil && *value != "" {
+		cluster.SetAnnotation(key, *value)
+	}
+}
+
+// isPrivateOnly returns true if the cluster endpoint can only be accessed from within its VPC
+func isPrivateOnly(cluster *discovery.Cluster) bool {
+	return cluster.Annotations[AnnotationEndpointPublicAccess] == "false" &&
+		cluster.Annotations[AnnotationEndpointPrivateAccess] == "true"
+}
+
+// checkEndpointReachable will warn if the cluster only has a private endpoint and
+// it can't be reached from this network. The endpoint is dialed through the cluster
+// proxy if there is one, e.g. a SSH tunnel.
+func (p *eksClusterProvider) checkEndpointReachable(cluster *discovery.Cluster) {
+	if !isPrivateOnly(cluster) || cluster.ControlPlaneEndpoint == nil {
+		return
+	}
+
+	endpoint, err := url.Parse(*cluster.ControlPlaneEndpoint)
+	if err != nil {
+		p.logger.Debugw("parsing cluster endpoint", "endpoint", *cluster.ControlPlaneEndpoint, "error", err.Error())
+		return
+	}
+	port := endpoint.Port()
+	if port == "" {
+		port = "443"
+	}
+
+	dialer, err := clusterDialer(p.clusterProxyURL)
+	if err != nil {
+		p.logger.Debugw("creating cluster dialer", "error", err.Error())
+		return
+	}
+	conn, err := dialer.Dial("tcp", net.JoinHostPort(endpoint.Hostname(), port))
+	if err != nil {
+		if p.clusterProxyURL != "" {
+			p.logger.Warnw("the cluster only has a private endpoint and it can't be reached through the tunnel, check that the jump host is in the VPC of the cluster", "cluster", cluster.Name, "endpoint", *cluster.ControlPlaneEndpoint)
+			return
+		}
+		p.logger.Warnw("the cluster only has a private endpoint and it can't be reached from this network, connect via the VPC (e.g. VPN, --ssh-jump-host or an HTTPS_PROXY)", "cluster", cluster.Name, "endpoint", *cluster.ControlPlaneEndpoint)
+		return
+	}
+	conn.Close() //nolint: errcheck
+}
+
+// clusterDialer returns the dialer for connections to the cluster, which goes through
+// the proxy if one is set
+func clusterDialer(proxyURL string) (proxy.Dialer, error) {
+	direct := &net.Dialer{Timeout: endpointDialTimeout}
+	if proxyURL == "" {
+		return direct, nil
+	}
+
+	u, err := url.Parse(proxyURL)
+	if err != nil {
+		return nil, fmt.Errorf("parsing cluster proxy url: %w", err)
+	}
+
+	return proxy.FromURL(u, direct)
+}
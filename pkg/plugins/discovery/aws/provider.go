@@ -17,19 +17,21 @@ limitations under the License.
 package aws
 
 import (
+	"context"
 	"fmt"
 
-	"github.com/aws/aws-sdk-go/service/eks/eksiface"
+	"github.com/aws/aws-sdk-go-v2/aws/arn"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 	"go.uber.org/zap"
 
 	"github.com/fidelity/kconnect/pkg/aws"
 	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/prereqs"
 	"github.com/fidelity/kconnect/pkg/provider"
 	"github.com/fidelity/kconnect/pkg/provider/common"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 	"github.com/fidelity/kconnect/pkg/provider/identity"
 	"github.com/fidelity/kconnect/pkg/provider/registry"
-	"github.com/fidelity/kconnect/pkg/utils"
 )
 
 const (
@@ -41,6 +43,33 @@ const (
   # Discover EKS clusters using SAML with a specific role
   {{.CommandPath}} use eks --idp-protocol saml --role-arn arn:aws:iam::000000000000:role/KubernetesAdmin
 
+  # Discover EKS clusters using SAML, completing the IdP login and any MFA in the browser
+  {{.CommandPath}} use eks --idp-protocol saml --saml-flow browser --idp-endpoint https://idp.example.com/saml/login
+
+  # Discover EKS clusters using SAML, configured from the metadata of the IdP
+  {{.CommandPath}} use eks --idp-protocol saml --idp-metadata-url https://adfs.example.com/FederationMetadata/2007-06/FederationMetadata.xml
+
+  # Discover EKS clusters using the credentials of the EC2 instance or IRSA
+  {{.CommandPath}} use eks --idp-protocol aws-ambient
+
+  # Discover EKS clusters using an existing AWS CLI profile
+  {{.CommandPath}} use eks --idp-protocol aws-iam --aws-profile myprofile
+
+  # Discover EKS clusters in several regions
+  {{.CommandPath}} use eks --idp-protocol aws-iam --region eu-west-1,us-east-1
+
+  # Discover EKS clusters with a private endpoint through a SSH tunnel to a bastion
+  {{.CommandPath}} use eks --idp-protocol aws-iam --ssh-jump-host ec2-user@bastion.example.com
+
+  # Discover EKS clusters in many regions quickly by only describing the selected cluster
+  {{.CommandPath}} use eks --idp-protocol aws-iam --region eu-west-1,us-east-1 --lazy-describe
+
+  # Discover EKS clusters with several roles at the same time
+  {{.CommandPath}} use eks --idp-protocol saml --identities arn:aws:iam::000000000000:role/Dev,arn:aws:iam::111111111111:role/Prod
+
+  # Connect to an EKS cluster selected from the output of ls in a pipeline
+  {{.CommandPath}} ls eks --idp-protocol aws-iam --output json | jq '.clusters[] | select(.name == "prod")' | {{.CommandPath}} use eks --idp-protocol aws-iam --cluster-id -
+
   # Discover an EKS cluster and add an alias to its connection history entry
   {{.CommandPath}} use eks --alias mycluster
   `
@@ -52,9 +81,11 @@ func init() {
 			Name:                   ProviderName,
 			UsageExample:           UsageExample,
 			ConfigurationItemsFunc: ConfigurationItems,
+			Capabilities:           []registry.Capability{registry.CapabilitySupportsPagination, registry.CapabilitySupportsRefresh},
 		},
 		CreateFunc:                 New,
-		SupportedIdentityProviders: []string{"aws-iam", "saml"},
+		SupportedIdentityProviders: []string{"aws-iam", "aws-ambient", "saml"},
+		Columns:                    []string{AnnotationAccountAlias, AnnotationVPCID, AnnotationEndpointAccess, discovery.ColumnTags},
 	}); err != nil {
 		zap.S().Fatalw("Failed to register EKS discovery plugin", "error", err)
 	}
@@ -74,13 +105,31 @@ type eksClusteProviderConfig struct {
 	RegionFilter *string `json:"region-filter"`
 	RoleArn      *string `json:"role-arn"`
 	RoleFilter   *string `json:"role-filter"`
+	Partition    string  `json:"partition"`
+	VerifyAccess bool    `json:"verify-access"`
+	LazyDescribe bool    `json:"lazy-describe"`
+	aws.Endpoints
 }
 
 // EKSClusterProvider will discover EKS clusters in AWS
 type eksClusterProvider struct {
-	config    *eksClusteProviderConfig
-	identity  *aws.Identity
-	eksClient eksiface.EKSAPI
+	config     *eksClusteProviderConfig
+	identity   *aws.Identity
+	regions    []string
+	eksClients map[string]aws.EKSAPI
+	stsClient  aws.STSAPI
+	iamClient  aws.IAMAPI
+	orgClient  aws.OrganizationsAPI
+	// stsPresigner presigns the STS requests that are used as cluster tokens
+	stsPresigner *sts.PresignClient
+
+	// callerIdentity is the ARN of the caller, used to build the cluster ARNs
+	callerIdentity *arn.ARN
+	// accountAliases are the aliases of the accounts that have been looked up
+	accountAliases map[string]string
+
+	// clusterProxyURL is the proxy that connections to the cluster go through
+	clusterProxyURL string
 
 	interactive bool
 	logger      *zap.SugaredLogger
@@ -91,7 +140,7 @@ func (p *eksClusterProvider) Name() string {
 	return ProviderName
 }
 
-func (p *eksClusterProvider) setup(cs config.ConfigurationSet, userID identity.Identity) error {
+func (p *eksClusterProvider) setup(ctx context.Context, cs config.ConfigurationSet, userID identity.Identity) error {
 	cfg := &eksClusteProviderConfig{}
 	if err := config.Unmarshall(cs, cfg); err != nil {
 		return fmt.Errorf("unmarshalling config items into eksClusteProviderConfig: %w", err)
@@ -104,32 +153,74 @@ func (p *eksClusterProvider) setup(cs config.ConfigurationSet, userID identity.I
 	}
 	p.identity = awsID
 
-	p.logger.Debugw("creating AWS session", "region", *p.config.Region)
-	sess, err := aws.NewSession(p.identity.Region, p.identity.ProfileName, p.identity.AWSAccessKey, p.identity.AWSSecretKey, p.identity.AWSSessionToken)
-	if err != nil {
-		return fmt.Errorf("creating aws session: %w", err)
+	// The region config can be a list of regions, otherwise the region
+	// of the identity is used
+	p.regions = []string{}
+	if p.config.Region != nil {
+		p.regions = aws.SplitRegions(*p.config.Region)
+	}
+	if len(p.regions) == 0 && p.identity.Region != "" {
+		p.regions = []string{p.identity.Region}
+	}
+	if len(p.regions) == 0 {
+		return aws.ErrNoRegion
 	}
 
-	p.eksClient = aws.NewEKSClient(sess)
+	p.eksClients = make(map[string]aws.EKSAPI)
+	for _, region := range p.regions {
+		if _, err := p.eksClientForRegion(ctx, region); err != nil {
+			return err
+		}
+	}
 
 	return nil
 }
 
-func (p *eksClusterProvider) ListPreReqs() []*provider.PreReq {
-	return []*provider.PreReq{}
+// eksClientForRegion returns the EKS client for the region, creating it if needed. The
+// STS, IAM and Organizations clients are created using the config for the first region.
+func (p *eksClusterProvider) eksClientForRegion(ctx context.Context, region string) (aws.EKSAPI, error) {
+	if eksClient, ok := p.eksClients[region]; ok {
+		return eksClient, nil
+	}
+
+	p.logger.Debugw("creating AWS config", "region", region, "partition", p.config.Partition)
+	awsCfg, err := aws.NewConfig(ctx, region, p.identity.ProfileName, p.identity.AWSAccessKey, p.identity.AWSSecretKey, p.identity.AWSSessionToken, &p.config.Endpoints)
+	if err != nil {
+		return nil, fmt.Errorf("creating aws config for region %s: %w", region, err)
+	}
+
+	eksClient := aws.NewEKSClient(awsCfg)
+	p.eksClients[region] = eksClient
+	if p.stsClient == nil {
+		stsClient := aws.NewSTSClient(awsCfg)
+		p.stsClient = stsClient
+		p.stsPresigner = sts.NewPresignClient(stsClient)
+		p.iamClient = aws.NewIAMClient(awsCfg)
+		p.orgClient = aws.NewOrganizationsClient(awsCfg)
+	}
+
+	return eksClient, nil
+}
+
+func (p *eksClusterProvider) ListPreReqs() []provider.PreReq {
+	return []provider.PreReq{
+		prereqs.AWSIAMAuthenticator(),
+	}
 }
 
 func (p *eksClusterProvider) CheckPreReqs() error {
-	return utils.CheckAWSIAMAuthPrereq()
+	return prereqs.Check(p.ListPreReqs())
 }
 
 // ConfigurationItems returns the configuration items for this provider
 func ConfigurationItems(scopeTo string) (config.ConfigurationSet, error) {
 	cs := aws.SharedConfig()
 
-	cs.String("region-filter", "", "A filter to apply to the AWS regions list, e.g. 'us-' will only show US regions")                 //nolint: errcheck
-	cs.String("role-arn", "", "ARN of the AWS role to be assumed")                                                                    //nolint: errcheck
-	cs.String("role-filter", "", "A filter to apply to the roles list, e.g. 'EKS' will only show roles that contain EKS in the name") //nolint: errcheck
+	cs.Bool("lazy-describe", false, "Only list the cluster names when discovering and describe the selected cluster, which is quicker but the clusters won't have the EKS annotations") //nolint: errcheck
+	cs.String("region-filter", "", "A filter to apply to the AWS regions list, e.g. 'us-' will only show US regions")                                                                   //nolint: errcheck
+	cs.String("role-arn", "", "ARN of the AWS role to be assumed")                                                                                                                      //nolint: errcheck
+	cs.String("role-filter", "", "A filter to apply to the roles list, e.g. 'EKS' will only show roles that contain EKS in the name")                                                   //nolint: errcheck
+	cs.Bool("verify-access", false, "Check that the identity can access the cluster before writing the kubeconfig")                                                                     //nolint: errcheck
 
 	return cs, nil
 }
@@ -17,31 +17,84 @@ limitations under the License.
 package aws
 
 import (
+	"context"
 	"fmt"
+	"strings"
 
+	"github.com/fidelity/kconnect/pkg/aws"
 	"github.com/fidelity/kconnect/pkg/config"
-	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/prompt"
 	"github.com/fidelity/kconnect/pkg/provider/identity"
 )
 
 func (p *eksClusterProvider) Validate(cfg config.ConfigurationSet) error {
-	errsValidation := &kerrors.ValidationFailed{}
+	return config.ValidateRequired(cfg)
+}
+
+// Resolve will resolve the values for the AWS specific flags that have no value. It will
+// query AWS and interactively ask the user for selections.
+func (p *eksClusterProvider) Resolve(config config.ConfigurationSet, userID identity.Identity) error {
+	if config.ExistsWithValue(aws.RegionConfigItem) {
+		return nil
+	}
+	awsID, ok := userID.(*aws.Identity)
+	if !ok {
+		return ErrNotAWSIdentity
+	}
+	if awsID.Region != "" {
+		return nil
+	}
+	if !p.interactive {
+		return aws.ErrNoRegion
+	}
+
+	return p.resolveRegions(config, awsID)
+}
+
+// resolveRegions will ask the user to select the regions to discover clusters in from
+// the regions that are enabled for the account
+func (p *eksClusterProvider) resolveRegions(cfg config.ConfigurationSet, awsID *aws.Identity) error {
+	partition := cfg.ValueString(aws.PartitionConfigItem)
+	endpoints := &aws.Endpoints{
+		EKS: cfg.ValueString(aws.EKSEndpointConfigItem),
+		IAM: cfg.ValueString(aws.IAMEndpointConfigItem),
+		STS: cfg.ValueString(aws.STSEndpointConfigItem),
+	}
+
+	ctx := context.Background()
+	awsCfg, err := aws.NewConfig(ctx, aws.PartitionDefaultRegion(partition), awsID.ProfileName, awsID.AWSAccessKey, awsID.AWSSecretKey, awsID.AWSSessionToken, endpoints)
+	if err != nil {
+		return fmt.Errorf("creating aws config: %w", err)
+	}
 
-	for _, item := range cfg.GetAll() {
-		if item.Required && !cfg.ExistsWithValue(item.Name) {
-			errsValidation.AddFailure(fmt.Sprintf("%s is required", item.Name))
+	p.logger.Debugw("listing enabled AWS regions", "partition", partition)
+	regions, err := aws.ListEnabledRegions(ctx, awsCfg)
+	if err != nil {
+		return fmt.Errorf("listing enabled regions: %w", err)
+	}
+
+	regionFilter := cfg.ValueString("region-filter")
+	options := []string{}
+	for _, region := range regions {
+		if regionFilter == "" || strings.Contains(region, regionFilter) {
+			options = append(options, region)
 		}
 	}
 
-	if len(errsValidation.Failures()) > 0 {
-		return errsValidation
+	selected, err := prompt.ChooseMany(aws.RegionConfigItem, "Select the AWS regions to discover clusters in", true, prompt.OptionsFromStringSlice(options))
+	if err != nil {
+		return fmt.Errorf("choosing regions: %w", err)
+	}
+	if len(selected) == 0 {
+		return aws.ErrNoRegion
 	}
 
-	return nil
-}
+	value := strings.Join(selected, ",")
+	if err := cfg.SetValue(aws.RegionConfigItem, value); err != nil {
+		return fmt.Errorf("setting %s config: %w", aws.RegionConfigItem, err)
+	}
+	cfg.SetSource(aws.RegionConfigItem, config.ItemSourcePrompt) //nolint: errcheck
+	p.logger.Debugw("resolved config item", "name", aws.RegionConfigItem, "value", value)
 
-// Resolve will resolve the values for the AWS specific flags that have no value. It will
-// query AWS and interactively ask the user for selections.
-func (p *eksClusterProvider) Resolve(config config.ConfigurationSet, userID identity.Identity) error {
 	return nil
 }
@@ -18,19 +18,24 @@ package azure
 
 import (
 	"context"
+	"errors"
 	"fmt"
+	"net/http"
 	"os"
+	"strings"
 
 	"k8s.io/client-go/tools/clientcmd"
 	"k8s.io/client-go/tools/clientcmd/api"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2020-09-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
 
-	azclient "github.com/fidelity/kconnect/pkg/azure/client"
+	"github.com/fidelity/kconnect/pkg/azure/cloud"
 	"github.com/fidelity/kconnect/pkg/azure/id"
 	azid "github.com/fidelity/kconnect/pkg/azure/identity"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/utils"
 )
 
 const (
@@ -40,21 +45,39 @@ const (
 func (p *aksClusterProvider) GetConfig(ctx context.Context, input *discovery.GetConfigInput) (*discovery.GetConfigOutput, error) {
 	p.logger.Debug("getting cluster config")
 
-	cfg, err := p.getKubeconfig(ctx, input.Cluster)
+	resourceID, err := id.FromClusterID(input.Cluster.ID)
+	if err != nil {
+		return nil, fmt.Errorf("parsing cluster id: %w", err)
+	}
+
+	cfg, admin, err := p.getKubeconfig(ctx, input.Cluster)
 	if err != nil {
 		return nil, fmt.Errorf("getting kubeconfig: %w", err)
 	}
-	if !p.config.Admin {
+	switch {
+	case admin:
+		p.logger.Debug("using admin credentials")
+	case !usesAzureAD(cfg):
+		p.logger.Debug("cluster doesn't use azure ad, using its user credentials")
+	default:
+		tenantID := p.tenantForSubscription(resourceID.SubscriptionID)
 		if p.config.LoginType == LoginTypeToken {
-			if err := p.addTokenToAuthProvider(cfg, input.Identity); err != nil {
+			if err := p.addTokenToAuthProvider(cfg, input.Identity, tenantID); err != nil {
 				return nil, fmt.Errorf("adding oauth token to kubeconfig: %w", err)
 			}
 		} else {
-			p.addKubelogin(cfg)
+			p.addKubelogin(cfg, tenantID)
 		}
 		p.printLoginDetails()
 	}
 
+	if err := p.applyPrivateAccess(ctx, cfg, resourceID); err != nil {
+		return nil, fmt.Errorf("applying private access: %w", err)
+	}
+	if p.config.RunCommand {
+		p.checkWithRunCommand(ctx, cfg, input.Cluster, resourceID)
+	}
+
 	if input.Namespace != nil && *input.Namespace != "" {
 		p.logger.Debugw("setting kubernetes namespace", "namespace", *input.Namespace)
 		cfg.Contexts[cfg.CurrentContext].Namespace = *input.Namespace
@@ -69,18 +92,21 @@ func (p *aksClusterProvider) GetConfig(ctx context.Context, input *discovery.Get
 
 func (p *aksClusterProvider) printLoginDetails() {
 	if p.config.LoginType == LoginTypeResourceOwnerPassword {
-		fmt.Fprintf(os.Stderr, "\033[33mSet the AAD_USER_PRINCIPAL_NAME and AAD_USER_PRINCIPAL_PASSWORD environment variables before running kubectl\033[0m\n")
+		fmt.Fprintln(os.Stderr, utils.Warning("Set the AAD_USER_PRINCIPAL_NAME and AAD_USER_PRINCIPAL_PASSWORD environment variables before running kubectl"))
 	}
 	if p.config.LoginType == LoginTypeServicePrincipal {
-		fmt.Fprintf(os.Stderr, "\033[33mSet the AAD_SERVICE_PRINCIPAL_CLIENT_ID and AAD_SERVICE_PRINCIPAL_CLIENT_SECRET environment variables before running kubectl\033[0m\n")
+		fmt.Fprintln(os.Stderr, utils.Warning("Set the AAD_SERVICE_PRINCIPAL_CLIENT_ID and AAD_SERVICE_PRINCIPAL_CLIENT_SECRET environment variables before running kubectl"))
+	}
+	if p.config.LoginType == LoginTypeWorkloadIdentity {
+		fmt.Fprintln(os.Stderr, utils.Warning("Set the AZURE_CLIENT_ID, AZURE_TENANT_ID and AZURE_FEDERATED_TOKEN_FILE environment variables before running kubectl, these are set by the Azure workload identity webhook"))
 	}
 
-	if p.config.AzureEnvironment == EnvironmentStackCloud {
-		fmt.Fprintf(os.Stderr, "\033[33mSet the Azure Stack URLs in a config file and set the AZURE_ENVIRONMENT_FILEPATH environment variable to the path of that file\033[0m\n")
+	if p.config.Name == cloud.AzureStack || p.config.Name == cloud.Custom {
+		fmt.Fprintln(os.Stderr, utils.Warning("Set the Azure Stack URLs in a config file and set the AZURE_ENVIRONMENT_FILEPATH environment variable to the path of that file"))
 	}
 }
 
-func (p *aksClusterProvider) addKubelogin(cfg *api.Config) {
+func (p *aksClusterProvider) addKubelogin(cfg *api.Config, tenantID string) {
 	contextName := cfg.CurrentContext
 	context := cfg.Contexts[contextName]
 	userName := context.AuthInfo
@@ -91,13 +117,13 @@ func (p *aksClusterProvider) addKubelogin(cfg *api.Config) {
 		Args: []string{
 			"get-token",
 			"--environment",
-			mapAzureEnvironment(p.config.AzureEnvironment),
+			cloud.KubeloginEnvironment(p.config.Name),
 			"--server-id",
 			AKSAADServerAppID,
 			"--client-id",
 			p.config.ClientID,
 			"--tenant-id",
-			p.config.TenantID,
+			tenantID,
 			"--login",
 			string(p.config.LoginType),
 		},
@@ -110,7 +136,7 @@ func (p *aksClusterProvider) addKubelogin(cfg *api.Config) {
 	}
 }
 
-func (p *aksClusterProvider) addTokenToAuthProvider(cfg *api.Config, userID identity.Identity) error {
+func (p *aksClusterProvider) addTokenToAuthProvider(cfg *api.Config, userID identity.Identity, tenantID string) error {
 	id, ok := userID.(*azid.ActiveDirectoryIdentity)
 	if !ok {
 		return ErrTokenNeedsAD
@@ -125,7 +151,11 @@ func (p *aksClusterProvider) addTokenToAuthProvider(cfg *api.Config, userID iden
 	apiServerID := providerConfig["apiserver-id"]
 	clientID := providerConfig["client-id"]
 
-	updatedID := id.Clone(azid.WithClientID(clientID))
+	opts := []azid.CloneOption{azid.WithClientID(clientID)}
+	if tenantID != "" && tenantID != id.Tenants()[0] {
+		opts = append(opts, azid.WithTenant(tenantID))
+	}
+	updatedID := id.Clone(opts...)
 
 	token, err := updatedID.GetOAuthToken(apiServerID)
 	if err != nil {
@@ -140,48 +170,77 @@ func (p *aksClusterProvider) addTokenToAuthProvider(cfg *api.Config, userID iden
 	return nil
 }
 
-func (p *aksClusterProvider) getKubeconfig(ctx context.Context, cluster *discovery.Cluster) (*api.Config, error) {
+// getKubeconfig gets the kubeconfig for the cluster and whether it uses the admin credentials.
+// The user credentials are used if the admin credentials are disabled for the cluster.
+func (p *aksClusterProvider) getKubeconfig(ctx context.Context, cluster *discovery.Cluster) (*api.Config, bool, error) {
 	resourceID, err := id.FromClusterID(cluster.ID)
 	if err != nil {
-		return nil, fmt.Errorf("parsing cluster id: %w", err)
+		return nil, false, fmt.Errorf("parsing cluster id: %w", err)
 	}
 
-	client := azclient.NewContainerClient(resourceID.SubscriptionID, p.authorizer)
-
-	var credentialList containerservice.CredentialResults
-	if p.config.Admin {
-		credentialList, err = client.ListClusterAdminCredentials(ctx, resourceID.ResourceGroupName, resourceID.ResourceName)
-	} else {
-		credentialList, err = client.ListClusterUserCredentials(ctx, resourceID.ResourceGroupName, resourceID.ResourceName)
+	client, err := p.containerClient(ctx, resourceID.SubscriptionID)
+	if err != nil {
+		return nil, false, err
+	}
+
+	admin := p.config.Admin
+	var credentialList armcontainerservice.CredentialResults
+	if admin {
+		var resp armcontainerservice.ManagedClustersClientListClusterAdminCredentialsResponse
+		resp, err = client.ListClusterAdminCredentials(ctx, resourceID.ResourceGroupName, resourceID.ResourceName, nil)
+		credentialList = resp.CredentialResults
+		if err != nil && isLocalAccountsDisabled(err) {
+			p.logger.Warnw("admin credentials are disabled for the cluster, using user credentials instead", "cluster", resourceID.ResourceName)
+			admin = false
+		}
+	}
+	if !admin {
+		var resp armcontainerservice.ManagedClustersClientListClusterUserCredentialsResponse
+		resp, err = client.ListClusterUserCredentials(ctx, resourceID.ResourceGroupName, resourceID.ResourceName, nil)
+		credentialList = resp.CredentialResults
 	}
 	if err != nil {
-		return nil, fmt.Errorf("getting user credentials: %w", err)
+		return nil, false, fmt.Errorf("getting user credentials: %w", err)
 	}
 
-	if credentialList.Kubeconfigs == nil || len(*credentialList.Kubeconfigs) < 1 {
-		return nil, ErrNoKubeconfigs
+	if len(credentialList.Kubeconfigs) < 1 || credentialList.Kubeconfigs[0] == nil {
+		return nil, false, ErrNoKubeconfigs
 	}
 
-	config := *(*credentialList.Kubeconfigs)[0].Value
+	config := credentialList.Kubeconfigs[0].Value
 	kubeCfg, err := clientcmd.Load(config)
 	if err != nil {
-		return nil, fmt.Errorf("loading kubeconfig: %w", err)
+		return nil, false, fmt.Errorf("loading kubeconfig: %w", err)
 	}
 
-	return kubeCfg, err
+	return kubeCfg, admin, nil
 }
 
-func mapAzureEnvironment(env Environment) string {
-	switch env {
-	case EnvironmentPublicCloud:
-		return "AzurePublicCloud"
-	case EnvironmentChinaCloud:
-		return "AzureChinaCloud"
-	case EnvironmentUSGovCloud:
-		return "AzureUSGovernmentCloud"
-	case EnvironmentStackCloud:
-		return "AzureStackCloud"
-	default:
-		return ""
+// isLocalAccountsDisabled returns true if the error is because the admin credentials
+// can't be used as local accounts are disabled for the cluster
+func isLocalAccountsDisabled(err error) bool {
+	var respErr *azcore.ResponseError
+	if !errors.As(err, &respErr) {
+		return false
+	}
+	if respErr.StatusCode != http.StatusBadRequest {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(err.Error()), "local accounts")
+}
+
+// usesAzureAD returns true if the user of the kubeconfig authenticates with Azure AD. The
+// user credentials of a cluster without Azure AD integration use a client certificate.
+func usesAzureAD(cfg *api.Config) bool {
+	kubeContext, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return false
 	}
+	authInfo, ok := cfg.AuthInfos[kubeContext.AuthInfo]
+	if !ok {
+		return false
+	}
+
+	return authInfo.AuthProvider != nil || authInfo.Exec != nil
 }
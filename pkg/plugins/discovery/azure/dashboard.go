@@ -0,0 +1,48 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"fmt"
+
+	"github.com/fidelity/kconnect/pkg/azure/cloud"
+	"github.com/fidelity/kconnect/pkg/azure/id"
+	"github.com/fidelity/kconnect/pkg/config"
+)
+
+// DashboardURL returns the URL of the AKS cluster in the Azure portal
+func (p *aksClusterProvider) DashboardURL(cs config.ConfigurationSet, clusterID string) (string, error) {
+	cfg := &aksClusterProviderConfig{}
+	if err := config.Unmarshall(cs, cfg); err != nil {
+		return "", fmt.Errorf("unmarshalling config items into aksClusterProviderConfig: %w", err)
+	}
+	if cfg.Name == cloud.AzurePublic && cfg.AzureEnvironment != EnvironmentPublicCloud {
+		cfg.Name = cloudFromEnvironment(cfg.AzureEnvironment)
+	}
+
+	portal, err := cfg.Config.PortalURL()
+	if err != nil {
+		return "", err
+	}
+
+	resourceID, err := id.FromClusterID(clusterID)
+	if err != nil {
+		return "", fmt.Errorf("getting resource id: %w", err)
+	}
+
+	return fmt.Sprintf("%s/#resource%s/overview", portal, resourceID.String()), nil
+}
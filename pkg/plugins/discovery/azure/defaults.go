@@ -17,14 +17,30 @@ limitations under the License.
 package azure
 
 const (
-	TenantIDConfigItem         = "tenant-id"
-	ClientIDConfigItem         = "client-id"
-	AADHostConfigItem          = "aad-host"
-	SubscriptionIDConfigItem   = "subscription-id"
-	SubscriptionNameConfigItem = "subscription-name"
-	ResourceGroupConfigItem    = "resource-group"
-	AdminConfigItem            = "admin"
-	ClusterNameConfigItem      = "cluster-name"
-	LoginTypeConfigItem        = "login-type"
-	AzureEnvironmentConfigItem = "azure-env"
+	TenantIDConfigItem          = "tenant-id"
+	AdditionalTenantsConfigItem = "additional-tenant-ids"
+	AllTenantsConfigItem        = "all-tenants"
+	ClientIDConfigItem          = "client-id"
+	AADHostConfigItem           = "aad-host"
+	AADAuthorityConfigItem      = "aad-authority-uri"
+	AADProxyConfigItem          = "aad-proxy"
+	TokenCacheConfigItem        = "token-cache"
+	SubscriptionIDConfigItem    = "subscription-id"
+	SubscriptionNameConfigItem  = "subscription-name"
+	AllSubscriptionsConfigItem  = "all-subscriptions"
+	SubscriptionCacheConfigItem = "subscription-cache-ttl"
+	ResourceGroupConfigItem     = "resource-group"
+	AdminConfigItem             = "admin"
+	ClusterNameConfigItem       = "cluster-name"
+	ClusterNameFilterConfigItem = "cluster-name-filter"
+	ClusterTagsConfigItem       = "cluster-tags"
+	LoginTypeConfigItem         = "login-type"
+	AzureEnvironmentConfigItem  = "azure-env"
+	PrivateAccessConfigItem     = "private-access"
+	JumpHostConfigItem          = "jump-host"
+	TunnelPortConfigItem        = "tunnel-port"
+	BastionNameConfigItem       = "bastion-name"
+	BastionGroupConfigItem      = "bastion-resource-group"
+	BastionTargetConfigItem     = "bastion-target-id"
+	RunCommandConfigItem        = "run-command-fallback"
 )
@@ -19,21 +19,32 @@ package azure
 import (
 	"context"
 	"fmt"
+	"sort"
+	"sync"
 
-	"github.com/Azure/azure-sdk-for-go/services/containerservice/mgmt/2020-09-01/containerservice"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
 
-	azclient "github.com/fidelity/kconnect/pkg/azure/client"
 	"github.com/fidelity/kconnect/pkg/azure/id"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 )
 
+const (
+	// listWorkers is the maximum number of subscriptions listed at the same time
+	listWorkers = 5
+)
+
 func (p *aksClusterProvider) Discover(ctx context.Context, input *discovery.DiscoverInput) (*discovery.DiscoverOutput, error) {
 	if err := p.setup(input.ConfigSet, input.Identity); err != nil {
 		return nil, fmt.Errorf("setting up aks provider: %w", err)
 	}
 	p.logger.Info("discovering AKS clusters")
 
-	clusters, err := p.listClusters(ctx)
+	subscriptionIDs, err := p.discoverySubscriptions(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	clusters, err := p.listAllClusters(ctx, subscriptionIDs, input.ClustersFound)
 	if err != nil {
 		return nil, fmt.Errorf("listing clusters: %w", err)
 	}
@@ -50,48 +61,218 @@ func (p *aksClusterProvider) Discover(ctx context.Context, input *discovery.Disc
 	return discoverOutput, nil
 }
 
-func (p *aksClusterProvider) listClusters(ctx context.Context) ([]*discovery.Cluster, error) {
-	p.logger.Debugw("listing clusters", "subscription", *p.config.SubscriptionID)
-	client := azclient.NewContainerClient(*p.config.SubscriptionID, p.authorizer)
+// discoverySubscriptions returns the subscriptions to list the clusters in. This is
+// the chosen subscription or all the subscriptions if all-subscriptions is set.
+func (p *aksClusterProvider) discoverySubscriptions(ctx context.Context) ([]string, error) {
+	if p.config.SubscriptionID != nil && *p.config.SubscriptionID != "" {
+		return []string{*p.config.SubscriptionID}, nil
+	}
+	if !p.config.AllSubscriptions {
+		return nil, ErrNoSubscriptions
+	}
+
+	subscriptions, err := p.listSubscriptions(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting subscriptions: %w", err)
+	}
+	if len(subscriptions) == 0 {
+		return nil, ErrNoSubscriptions
+	}
+
+	subscriptionIDs := []string{}
+	for _, id := range subscriptions {
+		subscriptionIDs = append(subscriptionIDs, id)
+	}
+	sort.Strings(subscriptionIDs)
+
+	return subscriptionIDs, nil
+}
+
+// listAllClusters will list the clusters in the subscriptions concurrently using a
+// bounded number of workers. A subscription that can't be listed is skipped with a
+// warning unless none of the subscriptions can be listed. The clusters in each
+// subscription are passed to found as soon as they're listed.
+func (p *aksClusterProvider) listAllClusters(ctx context.Context, subscriptionIDs []string, found func(...*discovery.Cluster)) ([]*discovery.Cluster, error) {
+	if len(subscriptionIDs) == 1 {
+		return p.listClusters(ctx, subscriptionIDs[0])
+	}
+
+	workers := listWorkers
+	if len(subscriptionIDs) < workers {
+		workers = len(subscriptionIDs)
+	}
+
+	type listResult struct {
+		subscriptionID string
+		clusters       []*discovery.Cluster
+		err            error
+	}
+
+	ids := make(chan string)
+	results := make(chan listResult, len(subscriptionIDs))
+
+	wg := sync.WaitGroup{}
+	for i := 0; i < workers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for subscriptionID := range ids {
+				clusters, err := p.listClusters(ctx, subscriptionID)
+				results <- listResult{subscriptionID: subscriptionID, clusters: clusters, err: err}
+			}
+		}()
+	}
+
+	go func() {
+		for _, subscriptionID := range subscriptionIDs {
+			ids <- subscriptionID
+		}
+		close(ids)
+		wg.Wait()
+		close(results)
+	}()
 
 	clusters := []*discovery.Cluster{}
-	var list containerservice.ManagedClusterListResultPage
-	var err error
-	if p.config.ResourceGroup == nil || *p.config.ResourceGroup == "" {
-		list, err = client.List(ctx)
-	} else {
-		list, err = client.ListByResourceGroup(ctx, *p.config.ResourceGroup)
+	var firstErr error
+	listed := 0
+	for result := range results {
+		listed++
+		if result.err != nil {
+			if firstErr == nil {
+				firstErr = result.err
+			}
+			p.logger.Warnw("failed listing AKS clusters in subscription", "subscription", result.subscriptionID, "error", result.err.Error())
+			continue
+		}
+		p.logger.Infow("listed AKS clusters in subscription", "subscription", result.subscriptionID, "clusters", len(result.clusters), "progress", fmt.Sprintf("%d/%d", listed, len(subscriptionIDs)))
+		clusters = append(clusters, result.clusters...)
+		found(result.clusters...)
+	}
+
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	if len(clusters) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+
+	return clusters, nil
+}
+
+func (p *aksClusterProvider) listClusters(ctx context.Context, subscriptionID string) ([]*discovery.Cluster, error) {
+	p.logger.Debugw("listing clusters", "subscription", subscriptionID)
+	client, err := p.containerClient(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
 	}
+
+	managedClusters, err := p.listManagedClusters(ctx, client)
 	if err != nil {
-		return nil, fmt.Errorf("querying for AKS clusters: %w", err)
+		return nil, fmt.Errorf("querying for AKS clusters in subscription %s: %w", subscriptionID, err)
 	}
 
-	for _, val := range list.Values() {
-		if p.config.ClusterName == "" || p.config.ClusterName == *val.Name {
-			clusterID, err := id.ToClusterID(*val.ID)
+	clusters := []*discovery.Cluster{}
+	nameFilter := discovery.NameFilter(p.config.ClusterNameFilter)
+	for _, val := range managedClusters {
+		if !p.includeCluster(val, nameFilter) {
+			continue
+		}
+		clusterID, err := id.ToClusterID(*val.ID)
+		if err != nil {
+			return nil, fmt.Errorf("create cluster id: %w", err)
+		}
+
+		cluster := &discovery.Cluster{
+			Name:    *val.Name,
+			ID:      clusterID,
+			Account: subscriptionID,
+			Tags:    clusterTags(val.Tags),
+		}
+		if val.Location != nil {
+			cluster.Region = *val.Location
+		}
+
+		controlPlaneEndpoint := ""
+		if val.Properties != nil && val.Properties.Fqdn != nil {
+			controlPlaneEndpoint = fmt.Sprintf("https://%s:443", *val.Properties.Fqdn)
+		}
+		if val.Properties != nil && val.Properties.PrivateFQDN != nil {
+			controlPlaneEndpoint = fmt.Sprintf("https://%s:443", *val.Properties.PrivateFQDN)
+		}
+		if controlPlaneEndpoint != "" {
+			cluster.ControlPlaneEndpoint = &controlPlaneEndpoint
+		}
+
+		clusters = append(clusters, cluster)
+	}
+
+	return clusters, nil
+}
+
+// listManagedClusters lists all the pages of the clusters in the subscription of the client,
+// or only those in the resource group if one is set
+func (p *aksClusterProvider) listManagedClusters(ctx context.Context, client *armcontainerservice.ManagedClustersClient) ([]*armcontainerservice.ManagedCluster, error) {
+	managedClusters := []*armcontainerservice.ManagedCluster{}
+	if p.config.ResourceGroup != nil && *p.config.ResourceGroup != "" {
+		pager := client.NewListByResourceGroupPager(*p.config.ResourceGroup, nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
 			if err != nil {
-				return nil, fmt.Errorf("create cluster id: %w", err)
+				return nil, err
 			}
+			managedClusters = append(managedClusters, page.Value...)
+		}
 
-			cluster := &discovery.Cluster{
-				Name: *val.Name,
-				ID:   clusterID,
-			}
+		return managedClusters, nil
+	}
 
-			controlPlaneEndpoint := ""
-			if val.Fqdn != nil {
-				controlPlaneEndpoint = fmt.Sprintf("https://%s:443", *val.Fqdn)
-			}
-			if val.PrivateFQDN != nil {
-				controlPlaneEndpoint = fmt.Sprintf("https://%s:443", *val.PrivateFQDN)
-			}
-			if controlPlaneEndpoint != "" {
-				cluster.ControlPlaneEndpoint = &controlPlaneEndpoint
-			}
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, err
+		}
+		managedClusters = append(managedClusters, page.Value...)
+	}
+
+	return managedClusters, nil
+}
 
-			clusters = append(clusters, cluster)
+// clusterTags converts the tags of a cluster, a tag without a value is kept with an empty value
+func clusterTags(tags map[string]*string) map[string]string {
+	if len(tags) == 0 {
+		return nil
+	}
+	converted := make(map[string]string, len(tags))
+	for key, value := range tags {
+		converted[key] = ""
+		if value != nil {
+			converted[key] = *value
 		}
 	}
 
-	return clusters, nil
+	return converted
+}
+
+// includeCluster returns true if the cluster matches the cluster name, the name
+// filter and all of the cluster tags
+func (p *aksClusterProvider) includeCluster(cluster *armcontainerservice.ManagedCluster, nameFilter discovery.ClusterFilterFunc) bool {
+	if cluster.Name == nil {
+		return false
+	}
+	if p.config.ClusterName != "" && p.config.ClusterName != *cluster.Name {
+		return false
+	}
+	if !nameFilter(&discovery.Cluster{Name: *cluster.Name}) {
+		return false
+	}
+
+	for key, value := range p.config.ClusterTags {
+		tagValue, ok := cluster.Tags[key]
+		if !ok || tagValue == nil || *tagValue != value {
+			return false
+		}
+	}
+
+	return true
 }
@@ -19,10 +19,15 @@ package azure
 import "errors"
 
 var (
-	ErrUnsupportedIdentity  = errors.New("unsupported identity, oidc.Identity orazure.AuthorizerIdentity required")
+	ErrUnsupportedIdentity  = errors.New("unsupported identity, azure.ActiveDirectoryIdentity or azure.CredentialIdentity required")
 	ErrNoKubeconfigs        = errors.New("no kubeconfigs available for the managed cluster cluster")
 	ErrNoSubscriptions      = errors.New("no subscriptions found")
-	ErrSubscriptionNameOrID = errors.New("subscription name and id cannot be both supplied")
 	ErrSubscriptionNotFound = errors.New("subscription not found")
+	ErrSubscriptionMatches  = errors.New("subscription name matches more than one subscription")
 	ErrTokenNeedsAD         = errors.New("the 'token' login type requires using aad idp-protocol")
+	ErrJumpHostRequired     = errors.New("a jump host is required when using the ssh-tunnel private access")
+	ErrBastionRequired      = errors.New("the bastion name and target id are required when using the bastion private access")
+	ErrNoClusterServer      = errors.New("no server found for the cluster in the kubeconfig")
+	ErrRunCommandFailed     = errors.New("aks run command failed")
+	ErrRunCommandTimeout    = errors.New("timed out waiting for the aks run command result")
 )
@@ -20,7 +20,6 @@ import (
 	"context"
 	"fmt"
 
-	azclient "github.com/fidelity/kconnect/pkg/azure/client"
 	"github.com/fidelity/kconnect/pkg/azure/id"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 )
@@ -37,8 +36,11 @@ func (p *aksClusterProvider) GetCluster(ctx context.Context, input *discovery.Ge
 		return nil, fmt.Errorf("getting resource id: %w", err)
 	}
 
-	client := azclient.NewContainerClient(resourceID.SubscriptionID, p.authorizer)
-	result, err := client.Get(ctx, resourceID.ResourceGroupName, resourceID.ResourceName)
+	client, err := p.containerClient(ctx, resourceID.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	result, err := client.Get(ctx, resourceID.ResourceGroupName, resourceID.ResourceName, nil)
 	if err != nil {
 		return nil, fmt.Errorf("getting cluster: %w", err)
 	}
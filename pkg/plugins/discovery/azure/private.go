@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"fmt"
+	"net/url"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/azure/id"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	defaultTunnelPort     = 8443
+	defaultAPIServerPort  = "443"
+	defaultJumpHostUser   = "azureuser"
+	bastionLocalSSHPort   = 50022
+	tunnelLocalServerHost = "127.0.0.1"
+)
+
+// applyPrivateAccess will change the kubeconfig of a private cluster so that its API server
+// is reached using the chosen private access. Nothing is changed for public clusters.
+func (p *aksClusterProvider) applyPrivateAccess(ctx context.Context, cfg *api.Config, resourceID *id.ResourceIdentifier) error {
+	if p.config.PrivateAccess == "" || p.config.PrivateAccess == PrivateAccessFQDN {
+		return nil
+	}
+
+	client, err := p.containerClient(ctx, resourceID.SubscriptionID)
+	if err != nil {
+		return err
+	}
+	result, err := client.Get(ctx, resourceID.ResourceGroupName, resourceID.ResourceName, nil)
+	if err != nil {
+		return fmt.Errorf("getting cluster %s: %w", resourceID.ResourceName, err)
+	}
+	if !isPrivateCluster(&result.ManagedCluster) {
+		p.logger.Debugw("cluster isn't private, ignoring private access", "cluster", resourceID.ResourceName, "private-access", p.config.PrivateAccess)
+		return nil
+	}
+
+	kubeCluster := p.currentCluster(cfg)
+	if kubeCluster == nil || kubeCluster.Server == "" {
+		return ErrNoClusterServer
+	}
+	serverURL, err := url.Parse(kubeCluster.Server)
+	if err != nil {
+		return fmt.Errorf("parsing cluster server %s: %w", kubeCluster.Server, err)
+	}
+	serverHost := serverURL.Hostname()
+	serverPort := serverURL.Port()
+	if serverPort == "" {
+		serverPort = defaultAPIServerPort
+	}
+	forward := fmt.Sprintf("%d:%s:%s", p.config.TunnelPort, serverHost, serverPort)
+
+	switch p.config.PrivateAccess {
+	case PrivateAccessSSHTunnel:
+		if p.config.JumpHost == "" {
+			return ErrJumpHostRequired
+		}
+		p.useTunnel(kubeCluster, serverHost)
+		printTunnelCommands(fmt.Sprintf("ssh -N -L %s %s", forward, p.config.JumpHost))
+	case PrivateAccessBastion:
+		if p.config.BastionName == "" || p.config.BastionTarget == "" {
+			return ErrBastionRequired
+		}
+		bastionGroup := p.config.BastionGroup
+		if bastionGroup == "" {
+			bastionGroup = resourceID.ResourceGroupName
+		}
+		p.useTunnel(kubeCluster, serverHost)
+		printTunnelCommands(
+			fmt.Sprintf("az network bastion tunnel --name %s --resource-group %s --target-resource-id %s --resource-port 22 --port %d", p.config.BastionName, bastionGroup, p.config.BastionTarget, bastionLocalSSHPort),
+			fmt.Sprintf("ssh -N -L %s -p %d %s@%s", forward, bastionLocalSSHPort, p.jumpHostUser(), tunnelLocalServerHost),
+		)
+	case PrivateAccessCommandInvoke:
+		fmt.Fprintln(os.Stderr, utils.Warning("The private cluster can only be reached from its virtual network, use az aks command invoke to run kubectl in the cluster:"))
+		fmt.Fprintf(os.Stderr, "  az aks command invoke --resource-group %s --name %s --command \"kubectl get pods --all-namespaces\"\n", resourceID.ResourceGroupName, resourceID.ResourceName)
+	}
+
+	return nil
+}
+
+// useTunnel will point the cluster at the local end of the tunnel. The certificate of the
+// API server is still checked against its private FQDN.
+func (p *aksClusterProvider) useTunnel(kubeCluster *api.Cluster, serverHost string) {
+	p.logger.Debugw("using tunnel to private cluster", "server", serverHost, "port", p.config.TunnelPort)
+	kubeCluster.Server = fmt.Sprintf("https://%s:%d", tunnelLocalServerHost, p.config.TunnelPort)
+	kubeCluster.TLSServerName = serverHost
+}
+
+// jumpHostUser returns the user of the jump host, this is used to log in to the VM that
+// the bastion tunnels to
+func (p *aksClusterProvider) jumpHostUser() string {
+	if p.config.JumpHost == "" {
+		return defaultJumpHostUser
+	}
+	jumpHost, err := url.Parse("ssh://" + p.config.JumpHost)
+	if err != nil || jumpHost.User == nil || jumpHost.User.Username() == "" {
+		return defaultJumpHostUser
+	}
+
+	return jumpHost.User.Username()
+}
+
+func (p *aksClusterProvider) currentCluster(cfg *api.Config) *api.Cluster {
+	kubeContext, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return nil
+	}
+
+	return cfg.Clusters[kubeContext.Cluster]
+}
+
+func printTunnelCommands(commands ...string) {
+	fmt.Fprintln(os.Stderr, utils.Warning("The private cluster is reached using a tunnel, run the following before running kubectl:"))
+	for _, command := range commands {
+		fmt.Fprintf(os.Stderr, "  %s\n", command)
+	}
+}
+
+func isPrivateCluster(cluster *armcontainerservice.ManagedCluster) bool {
+	if cluster.Properties == nil {
+		return false
+	}
+	props := cluster.Properties
+	if props.APIServerAccessProfile != nil && props.APIServerAccessProfile.EnablePrivateCluster != nil {
+		return *props.APIServerAccessProfile.EnablePrivateCluster
+	}
+
+	return props.PrivateFQDN != nil && *props.PrivateFQDN != ""
+}
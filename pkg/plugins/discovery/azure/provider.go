@@ -18,21 +18,26 @@ package azure
 
 import (
 	"fmt"
+	"time"
 
 	"go.uber.org/zap"
 
-	"github.com/Azure/go-autorest/autorest"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 	"github.com/go-playground/validator/v10"
 
+	azclient "github.com/fidelity/kconnect/pkg/azure/client"
+	"github.com/fidelity/kconnect/pkg/azure/cloud"
 	azid "github.com/fidelity/kconnect/pkg/azure/identity"
+	"github.com/fidelity/kconnect/pkg/cache"
 	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/defaults"
 	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/prereqs"
 	"github.com/fidelity/kconnect/pkg/provider"
 	"github.com/fidelity/kconnect/pkg/provider/common"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 	"github.com/fidelity/kconnect/pkg/provider/identity"
 	"github.com/fidelity/kconnect/pkg/provider/registry"
-	"github.com/fidelity/kconnect/pkg/utils"
 )
 
 const (
@@ -45,6 +50,33 @@ const (
   export AZURE_CLIENT_ID="76849"
   export AZURE_CLIENT_SECRET="supersecret"
   {{.CommandPath}} use aks --idp-protocol az-env
+
+  # Discover AKS clusters in all the subscriptions you can access
+  {{.CommandPath}} use aks --idp-protocol aad --all-subscriptions
+
+  # Discover only the AKS clusters of a team
+  {{.CommandPath}} use aks --idp-protocol aad --cluster-tags team=platform --cluster-name-filter "platform-*"
+
+  # Discover AKS clusters in the Azure US Government cloud
+  {{.CommandPath}} use aks --idp-protocol aad --azure-environment AzureUSGovernment
+
+  # Connect to a private AKS cluster through an SSH jump host
+  {{.CommandPath}} use aks --idp-protocol aad --private-access ssh-tunnel --jump-host azureuser@jumpbox.example.com
+
+  # Check a private AKS cluster using the run command API when its API server can't be reached
+  {{.CommandPath}} use aks --idp-protocol aad --run-command-fallback
+
+  # Discover AKS clusters in all the tenants and subscriptions you can access
+  {{.CommandPath}} use aks --idp-protocol aad --all-tenants --all-subscriptions
+
+  # Discover AKS clusters sharing the tokens of the az CLI
+  {{.CommandPath}} use aks --idp-protocol aad --token-cache $HOME/.azure/msal_token_cache.json --login-type azurecli
+
+  # Discover AKS clusters using the managed identity of an Azure VM
+  {{.CommandPath}} use aks --idp-protocol az-env --credential-type managed-identity --all-subscriptions
+
+  # Discover AKS clusters from a pod using Azure workload identity
+  {{.CommandPath}} use aks --idp-protocol az-env --credential-type workload-identity --login-type workloadidentity
 `
 )
 
@@ -54,6 +86,7 @@ func init() {
 			Name:                   ProviderName,
 			UsageExample:           UsageExample,
 			ConfigurationItemsFunc: ConfigurationItems,
+			Capabilities:           []registry.Capability{registry.CapabilitySupportsRefresh},
 		},
 		CreateFunc:                 New,
 		SupportedIdentityProviders: []string{"aad", "az-env"},
@@ -72,25 +105,49 @@ func New(input *provider.PluginCreationInput) (discovery.Provider, error) {
 		logger:      input.Logger,
 		interactive: input.IsInteractice,
 		httpClient:  input.HTTPClient,
+		throttle:    azclient.NewThrottle(listWorkers, input.Logger),
 	}, nil
 }
 
 type aksClusterProviderConfig struct {
 	common.ClusterProviderConfig
-	SubscriptionID   *string     `json:"subscription-id"`
-	SubscriptionName *string     `json:"subscription-name"`
-	ResourceGroup    *string     `json:"resource-group"`
-	Admin            bool        `json:"admin"`
-	ClusterName      string      `json:"cluster-name"`
-	TenantID         string      `json:"tenant-id"`
-	ClientID         string      `json:"client-id"`
-	LoginType        LoginType   `json:"login-type"`
-	AzureEnvironment Environment `json:"azure-env"`
+	SubscriptionID    *string           `json:"subscription-id"`
+	SubscriptionName  *string           `json:"subscription-name"`
+	AllSubscriptions  bool              `json:"all-subscriptions"`
+	SubscriptionCache time.Duration     `json:"subscription-cache-ttl"`
+	ResourceGroup     *string           `json:"resource-group"`
+	Admin             bool              `json:"admin"`
+	ClusterName       string            `json:"cluster-name"`
+	ClusterNameFilter string            `json:"cluster-name-filter"`
+	ClusterTags       map[string]string `json:"cluster-tags"`
+	TenantID          string            `json:"tenant-id"`
+	ClientID          string            `json:"client-id"`
+	LoginType         LoginType         `json:"login-type"`
+	AzureEnvironment  Environment       `json:"azure-env"`
+	PrivateAccess     PrivateAccess     `json:"private-access"`
+	JumpHost          string            `json:"jump-host"`
+	TunnelPort        int               `json:"tunnel-port"`
+	BastionName       string            `json:"bastion-name"`
+	BastionGroup      string            `json:"bastion-resource-group"`
+	BastionTarget     string            `json:"bastion-target-id"`
+	RunCommand        bool              `json:"run-command-fallback"`
+	cloud.Config
 }
 
 type aksClusterProvider struct {
-	config     *aksClusterProviderConfig
-	authorizer autorest.Authorizer
+	config      *aksClusterProviderConfig
+	credential  azcore.TokenCredential
+	environment cloud.Environment
+
+	// adIdentity is used to get credentials for the other tenants of the user
+	adIdentity              *azid.ActiveDirectoryIdentity
+	subscriptionCredentials map[string]azcore.TokenCredential
+	subscriptionTenants     map[string]string
+	subscriptionCache       cache.Cache
+	identityName            string
+
+	// throttle limits the concurrent requests to the resource manager
+	throttle *azclient.Throttle
 
 	httpClient  khttp.Client
 	interactive bool
@@ -112,20 +169,30 @@ func (p *aksClusterProvider) setup(cs config.ConfigurationSet, userID identity.I
 	}
 
 	p.config = cfg
+	p.subscriptionCache = cache.New(defaults.CacheDirectory(), cfg.SubscriptionCache)
+	p.identityName = userID.Name()
 
-	// TODO: should we just return a AuthorizerIdentity from the aad provider?
-	switch userID.(type) { //nolint:gocritic,gosimple
+	// The deprecated azure-env is used if a cloud hasn't been chosen
+	if cfg.Name == cloud.AzurePublic && cfg.AzureEnvironment != EnvironmentPublicCloud {
+		cfg.Name = cloudFromEnvironment(cfg.AzureEnvironment)
+	}
+	env, err := cfg.Config.Environment()
+	if err != nil {
+		return fmt.Errorf("getting azure environment %s: %w", cfg.Name, err)
+	}
+	p.environment = env
+
+	switch id := userID.(type) {
 	case *azid.ActiveDirectoryIdentity:
-		id := userID.(*azid.ActiveDirectoryIdentity)
-		p.logger.Debugw("creating bearer authorizer")
-		bearerAuth, err := getBearerAuthFromIdentity(id, "https://management.azure.com/")
+		p.logger.Debugw("creating credential from the azure ad identity")
+		credential, err := credentialFromIdentity(id, cloud.ResourceManagerAudience(env))
 		if err != nil {
-			return fmt.Errorf("getting bearer authorizer: %w", err)
+			return fmt.Errorf("getting credential: %w", err)
 		}
-		p.authorizer = bearerAuth
-	case *azid.AuthorizerIdentity:
-		id := userID.(*azid.AuthorizerIdentity)
-		p.authorizer = id.Authorizer()
+		p.credential = credential
+		p.adIdentity = id
+	case *azid.CredentialIdentity:
+		p.credential = id.Credential()
 	default:
 		return ErrUnsupportedIdentity
 	}
@@ -133,37 +200,56 @@ func (p *aksClusterProvider) setup(cs config.ConfigurationSet, userID identity.I
 	return nil
 }
 
-func (p *aksClusterProvider) ListPreReqs() []*provider.PreReq {
-	return []*provider.PreReq{}
+func (p *aksClusterProvider) ListPreReqs() []provider.PreReq {
+	return []provider.PreReq{
+		prereqs.Kubelogin(),
+	}
 }
 
 func (p *aksClusterProvider) CheckPreReqs() error {
-	return utils.CheckKubeloginPrereq()
+	return prereqs.Check(p.ListPreReqs())
 }
 
 // ConfigurationItems returns the configuration items for this provider
 func ConfigurationItems(scopeTo string) (config.ConfigurationSet, error) {
 	cs := config.NewConfigurationSet()
 
-	cs.String(SubscriptionIDConfigItem, "", "The Azure subscription to use (specified by ID)")                                                                                               //nolint: errcheck
-	cs.String(SubscriptionNameConfigItem, "", "The Azure subscription to use (specified by name)")                                                                                           //nolint: errcheck
-	cs.String(ResourceGroupConfigItem, "", "The Azure resource group to use")                                                                                                                //nolint: errcheck
-	cs.Bool(AdminConfigItem, false, "Generate admin user kubeconfig")                                                                                                                        //nolint: errcheck
-	cs.String(ClusterNameConfigItem, "", "The name of the AKS cluster")                                                                                                                      //nolint: errcheck
-	cs.String(LoginTypeConfigItem, string(LoginTypeDeviceCode), "The login method to use when connecting to the AKS cluster as a non-admin. Possible values: devicecode,spn,ropc,msi,token") //nolint: errcheck
-	cs.String(AzureEnvironmentConfigItem, string(EnvironmentPublicCloud), "The Azure environment the clusters are in. Possible values: public,china,usgov,stack")                            //nolint: errcheck
-
-	cs.SetShort(ResourceGroupConfigItem, "r") //nolint: errcheck
+	cs.String(SubscriptionIDConfigItem, "", "The Azure subscription to use (specified by ID)")                                                                                         //nolint: errcheck
+	cs.String(SubscriptionNameConfigItem, "", "The Azure subscription to use (specified by name)")                                                                                     //nolint: errcheck
+	cs.Bool(AllSubscriptionsConfigItem, false, "Discover clusters in all the subscriptions that can be accessed")                                                                      //nolint: errcheck
+	cs.Duration(SubscriptionCacheConfigItem, time.Hour, "How long to cache the list of subscriptions for, 0 disables the cache")                                                       //nolint: errcheck
+	cs.String(ResourceGroupConfigItem, "", "The Azure resource group to use")                                                                                                          //nolint: errcheck
+	cs.Bool(AdminConfigItem, false, "Generate admin user kubeconfig. The user credentials are used for clusters with local accounts disabled")                                         //nolint: errcheck
+	cs.String(ClusterNameConfigItem, "", "The name of the AKS cluster")                                                                                                                //nolint: errcheck
+	cs.String(ClusterNameFilterConfigItem, "", "Only discover clusters with a name that matches the filter. Can specify multiple filters by using commas, and supports wildcards (*)") //nolint: errcheck
+	cs.StringMap(ClusterTagsConfigItem, map[string]string{}, "Only discover clusters that have all of the tags, e.g. team=platform,env=dev")                                           //nolint: errcheck
+	cs.Enum(LoginTypeConfigItem, string(LoginTypeDeviceCode), loginTypeValues(), "The login method to use when connecting to the AKS cluster as a non-admin")                          //nolint: errcheck
+	cs.Enum(AzureEnvironmentConfigItem, string(EnvironmentPublicCloud), environmentValues(), "The Azure environment the clusters are in")                                              //nolint: errcheck
+	cs.Enum(PrivateAccessConfigItem, string(PrivateAccessFQDN), privateAccessValues(), "How to reach the API server of a private cluster")                                             //nolint: errcheck
+	cs.String(JumpHostConfigItem, "", "The SSH jump host used to reach a private cluster, e.g. azureuser@jumpbox.example.com")                                                         //nolint: errcheck
+	cs.Int(TunnelPortConfigItem, defaultTunnelPort, "The local port of the tunnel to a private cluster")                                                                               //nolint: errcheck
+	cs.String(BastionNameConfigItem, "", "The name of the Azure Bastion used to reach a private cluster")                                                                              //nolint: errcheck
+	cs.String(BastionGroupConfigItem, "", "The resource group of the Azure Bastion. Defaults to the resource group of the cluster")                                                    //nolint: errcheck
+	cs.String(BastionTargetConfigItem, "", "The resource id of the VM the Azure Bastion tunnels to, the VM must be able to reach the private cluster")                                 //nolint: errcheck
+	cs.Bool(RunCommandConfigItem, false, "If the API server can't be reached, check the health and version of the cluster using the AKS run command API instead")                      //nolint: errcheck
+	cloud.AddConfig(cs)
+
+	cs.SetShort(ResourceGroupConfigItem, "r")                                                                                         //nolint: errcheck
+	cs.SetDeprecated(AzureEnvironmentConfigItem, "please use --azure-environment")                                                    //nolint: errcheck
+	cs.SetMutuallyExclusive(SubscriptionIDConfigItem, SubscriptionNameConfigItem)                                                     //nolint: errcheck
+	cs.SetRequiredWhen(JumpHostConfigItem, config.Condition{Name: PrivateAccessConfigItem, Value: string(PrivateAccessSSHTunnel)})    //nolint: errcheck
+	cs.SetRequiredWhen(BastionNameConfigItem, config.Condition{Name: PrivateAccessConfigItem, Value: string(PrivateAccessBastion)})   //nolint: errcheck
+	cs.SetRequiredWhen(BastionTargetConfigItem, config.Condition{Name: PrivateAccessConfigItem, Value: string(PrivateAccessBastion)}) //nolint: errcheck
 
 	return cs, nil
 }
 
-func getBearerAuthFromIdentity(id *azid.ActiveDirectoryIdentity, resource string) (autorest.Authorizer, error) {
-	token, err := id.GetOAuthToken(resource)
-	if err != nil {
+// credentialFromIdentity returns a credential for the resource that uses the identity. A
+// token is got so that an identity that can't access the resource fails straight away.
+func credentialFromIdentity(id *azid.ActiveDirectoryIdentity, resource string) (azcore.TokenCredential, error) {
+	if _, err := id.GetOAuthToken(resource); err != nil {
 		return nil, fmt.Errorf("getting oauth token from identity: %w", err)
 	}
 
-	bearerAuth := azid.NewExplicitBearerAuthorizer(token.AccessToken)
-	return bearerAuth, nil
+	return id.TokenCredential(resource), nil
 }
@@ -19,28 +19,20 @@ package azure
 import (
 	"context"
 	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 
 	azclient "github.com/fidelity/kconnect/pkg/azure/client"
 	"github.com/fidelity/kconnect/pkg/config"
-	kerrors "github.com/fidelity/kconnect/pkg/errors"
 	"github.com/fidelity/kconnect/pkg/prompt"
 	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/utils"
 )
 
 func (p *aksClusterProvider) Validate(cfg config.ConfigurationSet) error {
-	errsValidation := &kerrors.ValidationFailed{}
-
-	for _, item := range cfg.GetAll() {
-		if item.Required && !cfg.ExistsWithValue(item.Name) {
-			errsValidation.AddFailure(fmt.Sprintf("%s is required", item.Name))
-		}
-	}
-
-	if len(errsValidation.Failures()) > 0 {
-		return errsValidation
-	}
-
-	return nil
+	return config.ValidateRequired(cfg)
 }
 
 // Resolve will resolve the values for the AWS specific flags that have no value. It will
@@ -50,22 +42,42 @@ func (p *aksClusterProvider) Resolve(cfg config.ConfigurationSet, userID identit
 		return fmt.Errorf("setting up aks provider: %w", err)
 	}
 	p.logger.Debug("resolving Azure configuration items")
-
-	if cfg.ExistsWithValue(SubscriptionIDConfigItem) && cfg.ExistsWithValue(SubscriptionNameConfigItem) {
-		return ErrSubscriptionNameOrID
+	if err := p.setValuesResolvers(cfg); err != nil {
+		return fmt.Errorf("setting values resolvers: %w", err)
 	}
 
 	if err := p.resolveSubscripionName(cfg); err != nil {
 		return fmt.Errorf("resolving subscription name: %w", err)
 	}
+	if p.config.AllSubscriptions && !cfg.ExistsWithValue(SubscriptionIDConfigItem) {
+		p.logger.Debug("discovering clusters in all subscriptions")
+		return nil
+	}
 
-	if err := prompt.ChooseAndSet(cfg, SubscriptionIDConfigItem, "Choose the Azure subscription", true, p.subscriptionOptions); err != nil {
+	if err := prompt.InputAndSet(cfg, SubscriptionIDConfigItem, "Choose the Azure subscription", true); err != nil {
 		return fmt.Errorf("resolving %s: %w", SubscriptionIDConfigItem, err)
 	}
 
 	return nil
 }
 
+// setValuesResolvers will set the resolvers that look up the values of the config items
+// from Azure, so that the user can choose them when they are asked for
+func (p *aksClusterProvider) setValuesResolvers(cfg config.ConfigurationSet) error {
+	resolvers := map[string]config.ValuesResolver{
+		SubscriptionIDConfigItem: p.subscriptionOptions,
+		ResourceGroupConfigItem:  p.resourceGroupOptions(cfg),
+		BastionGroupConfigItem:   p.resourceGroupOptions(cfg),
+	}
+	for name, resolver := range resolvers {
+		if err := cfg.SetValuesResolver(name, resolver); err != nil {
+			return fmt.Errorf("setting values resolver for %s: %w", name, err)
+		}
+	}
+
+	return nil
+}
+
 func (p *aksClusterProvider) resolveSubscripionName(cfg config.ConfigurationSet) error {
 	if !cfg.ExistsWithValue(SubscriptionNameConfigItem) {
 		return nil
@@ -78,9 +90,28 @@ func (p *aksClusterProvider) resolveSubscripionName(cfg config.ConfigurationSet)
 	if err != nil {
 		return fmt.Errorf("getting subscriptions: %w", err)
 	}
-	id, ok := options[subscriptionName]
-	if !ok {
-		return fmt.Errorf("looking up subscription %s: %w", subscriptionName, err)
+
+	var id string
+	matches := matchSubscriptions(subscriptionName, options)
+	switch {
+	case len(matches) == 0:
+		return fmt.Errorf("looking up subscription %s: %w", subscriptionName, ErrSubscriptionNotFound)
+	case len(matches) == 1:
+		id = options[matches[0]]
+	case !p.interactive:
+		return fmt.Errorf("subscription %s matches %s: %w", subscriptionName, strings.Join(matches, ", "), ErrSubscriptionMatches)
+	default:
+		matchedOptions := make(map[string]string)
+		for _, match := range matches {
+			matchedOptions[match] = options[match]
+		}
+		id, err = prompt.Choose(SubscriptionIDConfigItem, fmt.Sprintf("Choose the Azure subscription matching %s", subscriptionName), true, prompt.OptionsFromMap(matchedOptions))
+		if err != nil {
+			return fmt.Errorf("choosing subscription: %w", err)
+		}
+	}
+	if len(matches) == 1 && matches[0] != subscriptionName {
+		p.logger.Infow("using subscription that matches the name", "name", subscriptionName, "subscription", matches[0])
 	}
 
 	if err := cfg.SetValue(SubscriptionIDConfigItem, id); err != nil {
@@ -95,17 +126,154 @@ func (p *aksClusterProvider) resolveSubscripionName(cfg config.ConfigurationSet)
 }
 
 func (p *aksClusterProvider) subscriptionOptions() (map[string]string, error) {
-	client := azclient.NewSubscriptionsClient(p.authorizer)
+	return p.listSubscriptions(context.TODO())
+}
 
-	res, err := client.List(context.TODO())
-	if err != nil {
-		return nil, fmt.Errorf("getting subscription list: %w", err)
+// resourceGroupOptions returns a resolver for the names of the resource groups in the
+// subscription. There are no values if the subscription isn't known yet.
+func (p *aksClusterProvider) resourceGroupOptions(cfg config.ConfigurationSet) config.ValuesResolver {
+	return func() (map[string]string, error) {
+		subscriptionID := cfg.ValueString(SubscriptionIDConfigItem)
+		if subscriptionID == "" {
+			return nil, nil
+		}
+
+		ctx := context.TODO()
+		credential, err := p.credentialForSubscription(ctx, subscriptionID)
+		if err != nil {
+			return nil, fmt.Errorf("getting credential for subscription %s: %w", subscriptionID, err)
+		}
+
+		client, err := azclient.NewGroupsClient(p.environment, subscriptionID, credential, p.throttle)
+		if err != nil {
+			return nil, fmt.Errorf("creating resource groups client: %w", err)
+		}
+
+		groups := make(map[string]string)
+		pager := client.NewListPager(nil)
+		for pager.More() {
+			page, err := pager.NextPage(ctx)
+			if err != nil {
+				return nil, fmt.Errorf("listing resource groups in subscription %s: %w", subscriptionID, err)
+			}
+			for _, group := range page.Value {
+				if group.Name != nil {
+					groups[*group.Name] = *group.Name
+				}
+			}
+		}
+
+		return groups, nil
 	}
+}
+
+// listSubscriptions returns the ids of the subscriptions keyed by their display name. The
+// subscriptions of all the tenants of the identity are listed.
+func (p *aksClusterProvider) listSubscriptions(ctx context.Context) (map[string]string, error) {
+	p.subscriptionCredentials = make(map[string]azcore.TokenCredential)
+	p.subscriptionTenants = make(map[string]string)
 
 	subs := make(map[string]string)
-	for _, sub := range res.Values() {
-		subs[*sub.DisplayName] = *sub.SubscriptionID
+	for _, tenant := range p.tenantCredentials() {
+		tenantSubs, err := p.tenantSubscriptions(ctx, tenant)
+		if err != nil {
+			return nil, err
+		}
+
+		for _, sub := range tenantSubs {
+			// A subscription can be visible from more than one tenant, e.g. using Azure Lighthouse
+			if _, seen := p.subscriptionCredentials[sub.ID]; seen {
+				continue
+			}
+			subs[sub.Name] = sub.ID
+			p.subscriptionCredentials[sub.ID] = tenant.credential
+			p.subscriptionTenants[sub.ID] = sub.TenantID
+		}
 	}
 
 	return subs, nil
 }
+
+type cachedSubscription struct {
+	ID       string `json:"id"`
+	Name     string `json:"name"`
+	TenantID string `json:"tenant-id"`
+}
+
+// tenantSubscriptions returns the subscriptions that can be accessed from the tenant. The
+// subscriptions are cached per tenant and user.
+func (p *aksClusterProvider) tenantSubscriptions(ctx context.Context, tenant tenantCredential) ([]cachedSubscription, error) {
+	cacheKey := fmt.Sprintf("aks/subscriptions/%s/%s/%s", p.environment.Name, tenant.tenantID, p.identityName)
+
+	subs := []cachedSubscription{}
+	found, err := p.subscriptionCache.Get(cacheKey, &subs)
+	if err != nil {
+		p.logger.Warnw("failed reading cached subscriptions", "error", err.Error())
+	}
+	if found {
+		p.logger.Debugw("using cached subscriptions", "tenant", tenant.tenantID)
+		return subs, nil
+	}
+
+	client, err := azclient.NewSubscriptionsClient(p.environment, tenant.credential, p.throttle)
+	if err != nil {
+		return nil, fmt.Errorf("creating subscriptions client: %w", err)
+	}
+
+	subs = []cachedSubscription{}
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("getting subscription list for tenant %s: %w", tenant.tenantID, err)
+		}
+		for _, sub := range page.Value {
+			if sub.SubscriptionID == nil || sub.DisplayName == nil {
+				continue
+			}
+			cached := cachedSubscription{
+				ID:   *sub.SubscriptionID,
+				Name: *sub.DisplayName,
+			}
+			if sub.TenantID != nil {
+				cached.TenantID = *sub.TenantID
+			}
+			subs = append(subs, cached)
+		}
+	}
+
+	if err := p.subscriptionCache.Set(cacheKey, subs); err != nil {
+		p.logger.Warnw("failed caching subscriptions", "error", err.Error())
+	}
+
+	return subs, nil
+}
+
+// matchSubscriptions returns the names of the subscriptions that match the name. An
+// exact match is preferred, then a match ignoring case, then names that contain the
+// name and finally names that fuzzy match it.
+func matchSubscriptions(name string, subscriptions map[string]string) []string {
+	if _, ok := subscriptions[name]; ok {
+		return []string{name}
+	}
+
+	matchers := []func(string) bool{
+		func(subName string) bool { return strings.EqualFold(subName, name) },
+		func(subName string) bool { return strings.Contains(strings.ToLower(subName), strings.ToLower(name)) },
+		func(subName string) bool { return utils.FuzzyMatch(name, subName) },
+	}
+	for _, matcher := range matchers {
+		matches := []string{}
+		for subName := range subscriptions {
+			if matcher(subName) {
+				matches = append(matches, subName)
+			}
+		}
+		if len(matches) > 0 {
+			sort.Strings(matches)
+			return matches
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,249 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	azruntime "github.com/Azure/azure-sdk-for-go/sdk/azcore/runtime"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore/to"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
+	"k8s.io/apimachinery/pkg/runtime"
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/azure/id"
+	azid "github.com/fidelity/kconnect/pkg/azure/identity"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	// AnnotationAPIServerReachable is false if the API server couldn't be reached when the
+	// kubeconfig was generated
+	AnnotationAPIServerReachable = "aks.azure.com/api-server-reachable"
+	// AnnotationRunCommandHealth is the health of the cluster reported by the run command
+	AnnotationRunCommandHealth = "aks.azure.com/run-command-health"
+	// AnnotationKubernetesVersion is the version of the API server reported by the run command
+	AnnotationKubernetesVersion = "aks.azure.com/kubernetes-version"
+
+	// PrivateConnectivityExtension is the name of the kubeconfig context extension added
+	// when the API server can't be reached from this network
+	PrivateConnectivityExtension = "aks.azure.com/private-connectivity"
+
+	runCommandHealthCommand = "kubectl get --raw /readyz && kubectl version -o json"
+	runCommandTimeout       = 5 * time.Minute
+	runCommandPollInterval  = 5 * time.Second
+	apiServerDialTimeout    = 3 * time.Second
+
+	runCommandStateFailed = "Failed"
+)
+
+// privateConnectivity is added to the kubeconfig context of a cluster whose API server
+// can't be reached from the network kubeconfig was generated on
+type privateConnectivity struct {
+	Message       string `json:"message"`
+	Server        string `json:"server"`
+	Health        string `json:"health,omitempty"`
+	ServerVersion string `json:"serverVersion,omitempty"`
+}
+
+// checkWithRunCommand will check if the API server of the cluster can be reached. If it can't
+// the health and version of the cluster are checked using the AKS run command API, which goes
+// through the resource manager, and the cluster and context are annotated as needing a VPN
+// or private connectivity. Failures are reported as warnings as the kubeconfig is still usable
+// from the cluster's network.
+func (p *aksClusterProvider) checkWithRunCommand(ctx context.Context, cfg *api.Config, cluster *discovery.Cluster, resourceID *id.ResourceIdentifier) {
+	kubeCluster := p.currentCluster(cfg)
+	if kubeCluster == nil || kubeCluster.Server == "" {
+		return
+	}
+	if kubeCluster.TLSServerName != "" {
+		p.logger.Debugw("cluster is reached through a tunnel, skipping run command check", "cluster", resourceID.ResourceName)
+		return
+	}
+	if apiServerReachable(kubeCluster.Server) {
+		p.logger.Debugw("api server is reachable, skipping run command check", "server", kubeCluster.Server)
+		return
+	}
+
+	p.logger.Infow("the api server can't be reached, checking the cluster using the aks run command api", "cluster", resourceID.ResourceName)
+	connectivity := &privateConnectivity{
+		Message: "The API server can only be reached over a VPN or private network connection",
+		Server:  kubeCluster.Server,
+	}
+	cluster.SetAnnotation(AnnotationAPIServerReachable, "false")
+
+	result, err := p.runCommand(ctx, cfg, resourceID, runCommandHealthCommand)
+	if err != nil {
+		p.logger.Warnw("checking the cluster using the aks run command api", "cluster", resourceID.ResourceName, "error", err.Error())
+	} else {
+		connectivity.Health, connectivity.ServerVersion = parseHealthLogs(stringValue(result.Properties.Logs))
+		cluster.SetAnnotation(AnnotationRunCommandHealth, connectivity.Health)
+		if connectivity.ServerVersion != "" {
+			cluster.SetAnnotation(AnnotationKubernetesVersion, connectivity.ServerVersion)
+		}
+		p.logger.Infow("checked the cluster using the aks run command api", "cluster", resourceID.ResourceName, "health", connectivity.Health, "version", connectivity.ServerVersion)
+	}
+
+	if err := addPrivateConnectivityExtension(cfg, connectivity); err != nil {
+		p.logger.Warnw("annotating the kubeconfig context", "error", err.Error())
+	}
+	fmt.Fprintln(os.Stderr, utils.Warning(fmt.Sprintf("The API server of %s can't be reached from this network, connect to a VPN or the cluster's network before running kubectl", resourceID.ResourceName)))
+}
+
+// runCommand runs the command in the cluster using the AKS run command API and waits for
+// its result
+func (p *aksClusterProvider) runCommand(ctx context.Context, cfg *api.Config, resourceID *id.ResourceIdentifier, command string) (*armcontainerservice.RunCommandResult, error) {
+	client, err := p.containerClient(ctx, resourceID.SubscriptionID)
+	if err != nil {
+		return nil, err
+	}
+
+	request := armcontainerservice.RunCommandRequest{Command: to.Ptr(command)}
+	if usesAzureAD(cfg) {
+		if token := p.clusterToken(resourceID.SubscriptionID); token != "" {
+			request.ClusterToken = to.Ptr(token)
+		}
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, runCommandTimeout)
+	defer cancel()
+
+	poller, err := client.BeginRunCommand(ctx, resourceID.ResourceGroupName, resourceID.ResourceName, request, nil)
+	if err != nil {
+		return nil, fmt.Errorf("sending run command request: %w", err)
+	}
+	resp, err := poller.PollUntilDone(ctx, &azruntime.PollUntilDoneOptions{Frequency: runCommandPollInterval})
+	if errors.Is(err, context.DeadlineExceeded) {
+		return nil, ErrRunCommandTimeout
+	}
+	if err != nil {
+		return nil, fmt.Errorf("getting run command result: %w", err)
+	}
+
+	result := &resp.RunCommandResult
+	if result.Properties == nil {
+		return nil, fmt.Errorf("run command returned no result: %w", ErrRunCommandFailed)
+	}
+	if stringValue(result.Properties.ProvisioningState) == runCommandStateFailed {
+		return nil, fmt.Errorf("%s: %w", stringValue(result.Properties.Reason), ErrRunCommandFailed)
+	}
+
+	return result, nil
+}
+
+// clusterToken gets a token for the AKS AAD server app, which is needed to run commands in
+// clusters that use Azure AD. No token is returned if it can't be got, the run command API
+// will report the problem.
+func (p *aksClusterProvider) clusterToken(subscriptionID string) string {
+	if p.adIdentity == nil {
+		return ""
+	}
+
+	opts := []azid.CloneOption{}
+	if tenantID := p.tenantForSubscription(subscriptionID); tenantID != "" && tenantID != p.adIdentity.Tenants()[0] {
+		opts = append(opts, azid.WithTenant(tenantID))
+	}
+	token, err := p.adIdentity.Clone(opts...).GetOAuthToken(AKSAADServerAppID)
+	if err != nil {
+		p.logger.Debugw("getting token for the aks aad server app", "error", err.Error())
+		return ""
+	}
+
+	return token.AccessToken
+}
+
+// parseHealthLogs returns the health and server version from the logs of the health command,
+// which are the response of /readyz followed by the output of kubectl version
+func parseHealthLogs(logs string) (string, string) {
+	health := "unknown"
+	versionStart := strings.Index(logs, "{")
+	if versionStart < 0 {
+		versionStart = len(logs)
+	}
+	if readyz := strings.TrimSpace(logs[:versionStart]); readyz != "" {
+		health = strings.SplitN(readyz, "\n", 2)[0] //nolint: gomnd
+	}
+
+	version := struct {
+		ServerVersion struct {
+			GitVersion string `json:"gitVersion"`
+		} `json:"serverVersion"`
+	}{}
+	if err := json.Unmarshal([]byte(logs[versionStart:]), &version); err != nil {
+		return health, ""
+	}
+
+	return health, version.ServerVersion.GitVersion
+}
+
+// apiServerReachable returns true if a connection can be made to the API server
+func apiServerReachable(server string) bool {
+	serverURL, err := url.Parse(server)
+	if err != nil {
+		return false
+	}
+	port := serverURL.Port()
+	if port == "" {
+		port = defaultAPIServerPort
+	}
+
+	conn, err := net.DialTimeout("tcp", net.JoinHostPort(serverURL.Hostname(), port), apiServerDialTimeout)
+	if err != nil {
+		return false
+	}
+	conn.Close() //nolint: errcheck
+
+	return true
+}
+
+// addPrivateConnectivityExtension annotates the current context of the kubeconfig as
+// needing private connectivity
+func addPrivateConnectivityExtension(cfg *api.Config, connectivity *privateConnectivity) error {
+	kubeContext, ok := cfg.Contexts[cfg.CurrentContext]
+	if !ok {
+		return nil
+	}
+
+	data, err := json.Marshal(connectivity)
+	if err != nil {
+		return fmt.Errorf("marshalling private connectivity: %w", err)
+	}
+	if kubeContext.Extensions == nil {
+		kubeContext.Extensions = map[string]runtime.Object{}
+	}
+	kubeContext.Extensions[PrivateConnectivityExtension] = &runtime.Unknown{Raw: data, ContentType: runtime.ContentTypeJSON}
+
+	return nil
+}
+
+// stringValue returns the value of the string or an empty string if it's nil
+func stringValue(value *string) string {
+	if value == nil {
+		return ""
+	}
+
+	return *value
+}
@@ -0,0 +1,105 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package azure
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/resourcemanager/containerservice/armcontainerservice"
+
+	azclient "github.com/fidelity/kconnect/pkg/azure/client"
+	"github.com/fidelity/kconnect/pkg/azure/cloud"
+	azid "github.com/fidelity/kconnect/pkg/azure/identity"
+)
+
+type tenantCredential struct {
+	tenantID   string
+	credential azcore.TokenCredential
+}
+
+// isMultiTenant returns true if the identity can access more than one tenant
+func (p *aksClusterProvider) isMultiTenant() bool {
+	return p.adIdentity != nil && len(p.adIdentity.Tenants()) > 1
+}
+
+// tenantCredentials returns a credential for each tenant of the identity, starting with the
+// tenant the user authenticated to. A tenant that a token can't be got for is skipped.
+func (p *aksClusterProvider) tenantCredentials() []tenantCredential {
+	credentials := []tenantCredential{{tenantID: p.config.TenantID, credential: p.credential}}
+	if !p.isMultiTenant() {
+		return credentials
+	}
+
+	tenants := p.adIdentity.Tenants()
+	credentials[0].tenantID = tenants[0]
+	for _, tenantID := range tenants[1:] {
+		tenantIdentity := p.adIdentity.Clone(azid.WithTenant(tenantID))
+		credential, err := credentialFromIdentity(tenantIdentity, cloud.ResourceManagerAudience(p.environment))
+		if err != nil {
+			p.logger.Warnw("failed getting token for tenant", "tenant", tenantID, "error", err.Error())
+			continue
+		}
+		credentials = append(credentials, tenantCredential{tenantID: tenantID, credential: credential})
+	}
+
+	return credentials
+}
+
+// credentialForSubscription returns the credential for the tenant of the subscription. The
+// subscriptions are listed to find the tenant if they haven't been already.
+func (p *aksClusterProvider) credentialForSubscription(ctx context.Context, subscriptionID string) (azcore.TokenCredential, error) {
+	if !p.isMultiTenant() {
+		return p.credential, nil
+	}
+	if p.subscriptionCredentials == nil {
+		if _, err := p.listSubscriptions(ctx); err != nil {
+			return nil, fmt.Errorf("getting subscriptions: %w", err)
+		}
+	}
+	if credential, ok := p.subscriptionCredentials[subscriptionID]; ok {
+		return credential, nil
+	}
+
+	return p.credential, nil
+}
+
+// containerClient returns a managed clusters client for the subscription that uses the
+// credential of its tenant
+func (p *aksClusterProvider) containerClient(ctx context.Context, subscriptionID string) (*armcontainerservice.ManagedClustersClient, error) {
+	credential, err := p.credentialForSubscription(ctx, subscriptionID)
+	if err != nil {
+		return nil, err
+	}
+	client, err := azclient.NewContainerClient(p.environment, subscriptionID, credential, p.throttle)
+	if err != nil {
+		return nil, fmt.Errorf("creating container client for subscription %s: %w", subscriptionID, err)
+	}
+
+	return client, nil
+}
+
+// tenantForSubscription returns the tenant of the subscription if its known, otherwise
+// the tenant the user authenticated to
+func (p *aksClusterProvider) tenantForSubscription(subscriptionID string) string {
+	if tenantID, ok := p.subscriptionTenants[subscriptionID]; ok && tenantID != "" {
+		return tenantID
+	}
+
+	return p.config.TenantID
+}
@@ -16,6 +16,8 @@ limitations under the License.
 
 package azure
 
+import "github.com/fidelity/kconnect/pkg/azure/cloud"
+
 // Environment is a type that represents an Azure environment
 type Environment string
 
@@ -30,6 +32,29 @@ var (
 	EnvironmentStackCloud = Environment("stack")
 )
 
+// cloudFromEnvironment maps the deprecated environment to the name of the cloud
+func cloudFromEnvironment(env Environment) cloud.Name {
+	switch env {
+	case EnvironmentChinaCloud:
+		return cloud.AzureChina
+	case EnvironmentUSGovCloud:
+		return cloud.AzureUSGovernment
+	case EnvironmentStackCloud:
+		return cloud.AzureStack
+	default:
+		return cloud.AzurePublic
+	}
+}
+
+func environmentValues() []string {
+	return []string{
+		string(EnvironmentPublicCloud),
+		string(EnvironmentChinaCloud),
+		string(EnvironmentUSGovCloud),
+		string(EnvironmentStackCloud),
+	}
+}
+
 // LoginType is a type that denotes the type of user login
 type LoginType string
 
@@ -44,4 +69,43 @@ var (
 	LoginTypeManagedServiceIdentity = LoginType("msi")
 	// LoginTypeToken is for an embedded token login type
 	LoginTypeToken = LoginType("token")
+	// LoginTypeAzureCLI is for using the token of the az CLI, which shares its token cache
+	LoginTypeAzureCLI = LoginType("azurecli")
+	// LoginTypeWorkloadIdentity is for using the federated token of Azure workload identity to login
+	LoginTypeWorkloadIdentity = LoginType("workloadidentity")
 )
+
+func loginTypeValues() []string {
+	return []string{
+		string(LoginTypeDeviceCode),
+		string(LoginTypeServicePrincipal),
+		string(LoginTypeResourceOwnerPassword),
+		string(LoginTypeManagedServiceIdentity),
+		string(LoginTypeToken),
+		string(LoginTypeAzureCLI),
+		string(LoginTypeWorkloadIdentity),
+	}
+}
+
+// PrivateAccess is a type that denotes how a private cluster is reached
+type PrivateAccess string
+
+var (
+	// PrivateAccessFQDN uses the private FQDN of the cluster, which needs access to the virtual network
+	PrivateAccessFQDN = PrivateAccess("private-fqdn")
+	// PrivateAccessSSHTunnel uses a local SSH tunnel through a jump host
+	PrivateAccessSSHTunnel = PrivateAccess("ssh-tunnel")
+	// PrivateAccessBastion uses a local SSH tunnel through an Azure Bastion tunnel to a VM
+	PrivateAccessBastion = PrivateAccess("bastion")
+	// PrivateAccessCommandInvoke uses az aks command invoke to run commands in the cluster
+	PrivateAccessCommandInvoke = PrivateAccess("command-invoke")
+)
+
+func privateAccessValues() []string {
+	return []string{
+		string(PrivateAccessFQDN),
+		string(PrivateAccessSSHTunnel),
+		string(PrivateAccessBastion),
+		string(PrivateAccessCommandInvoke),
+	}
+}
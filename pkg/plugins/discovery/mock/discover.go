@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"k8s.io/client-go/tools/clientcmd/api"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+var (
+	ErrMockFailure     = errors.New("mock failure")
+	ErrClusterNotFound = kerrors.WithCode(kerrors.CodeClusterNotFound, errors.New("mock cluster not found"))
+)
+
+// Discover returns a cluster for each of the configured names
+func (p *mockClusterProvider) Discover(ctx context.Context, input *discovery.DiscoverInput) (*discovery.DiscoverOutput, error) {
+	if err := p.setup(input.ConfigSet, input.Identity); err != nil {
+		return nil, fmt.Errorf("setting up mock provider: %w", err)
+	}
+	p.logger.Info("discovering mock clusters")
+
+	if err := p.simulate(ctx, OperationDiscover); err != nil {
+		return nil, err
+	}
+
+	discoverOutput := &discovery.DiscoverOutput{
+		DiscoveryProvider: ProviderName,
+		IdentityProvider:  input.Identity.IdentityProviderName(),
+		Clusters:          make(map[string]*discovery.Cluster),
+	}
+	for _, name := range p.config.Clusters {
+		cluster := newCluster(name)
+		discoverOutput.Clusters[name] = cluster
+		input.ClustersFound(cluster)
+	}
+
+	return discoverOutput, nil
+}
+
+// GetCluster returns the mock cluster with the id if it's one of the configured names
+func (p *mockClusterProvider) GetCluster(ctx context.Context, input *discovery.GetClusterInput) (*discovery.GetClusterOutput, error) {
+	if err := p.setup(input.ConfigSet, input.Identity); err != nil {
+		return nil, fmt.Errorf("setting up mock provider: %w", err)
+	}
+	p.logger.Infow("getting mock cluster", "id", input.ClusterID)
+
+	if err := p.simulate(ctx, OperationGetCluster); err != nil {
+		return nil, err
+	}
+
+	for _, name := range p.config.Clusters {
+		if name == input.ClusterID {
+			return &discovery.GetClusterOutput{
+				Cluster: newCluster(name),
+			}, nil
+		}
+	}
+
+	return nil, fmt.Errorf("getting cluster %s: %w", input.ClusterID, ErrClusterNotFound)
+}
+
+// GetConfig returns a kubeconfig for the mock cluster that uses the mock token
+func (p *mockClusterProvider) GetConfig(ctx context.Context, input *discovery.GetConfigInput) (*discovery.GetConfigOutput, error) {
+	p.logger.Debug("getting mock cluster config")
+
+	if err := p.simulate(ctx, OperationGetConfig); err != nil {
+		return nil, err
+	}
+
+	contextName := fmt.Sprintf("mock-%s", input.Cluster.Name)
+	userName := fmt.Sprintf("mock-%s-user", input.Cluster.Name)
+
+	cfg := api.NewConfig()
+	cluster := api.NewCluster()
+	cluster.Server = *input.Cluster.ControlPlaneEndpoint
+	cluster.InsecureSkipTLSVerify = true
+	cluster.ProxyURL = input.ProxyURL
+	cfg.Clusters[input.Cluster.Name] = cluster
+
+	authInfo := api.NewAuthInfo()
+	authInfo.Token = p.token
+	cfg.AuthInfos[userName] = authInfo
+
+	kubeContext := api.NewContext()
+	kubeContext.Cluster = input.Cluster.Name
+	kubeContext.AuthInfo = userName
+	if input.Namespace != nil && *input.Namespace != "" {
+		p.logger.Debugw("setting kubernetes namespace", "namespace", *input.Namespace)
+		kubeContext.Namespace = *input.Namespace
+	}
+	cfg.Contexts[contextName] = kubeContext
+	cfg.CurrentContext = contextName
+
+	return &discovery.GetConfigOutput{
+		KubeConfig:  cfg,
+		ContextName: &contextName,
+	}, nil
+}
+
+// Validate will check that the required config items have values
+func (p *mockClusterProvider) Validate(cfg config.ConfigurationSet) error {
+	return config.ValidateRequired(cfg)
+}
+
+// Resolve does nothing as the mock config items all have defaults
+func (p *mockClusterProvider) Resolve(cfg config.ConfigurationSet, identity identity.Identity) error {
+	return nil
+}
+
+func newCluster(name string) *discovery.Cluster {
+	endpoint := fmt.Sprintf("https://%s.mock.kconnect.local", name)
+
+	return &discovery.Cluster{
+		ID:                   name,
+		Name:                 name,
+		ControlPlaneEndpoint: &endpoint,
+	}
+}
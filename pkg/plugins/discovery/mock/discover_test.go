@@ -0,0 +1,76 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/plugins/discovery/mock"
+	"github.com/fidelity/kconnect/pkg/provider"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+func TestDiscoverAndGetConfig(t *testing.T) {
+	g := NewWithT(t)
+
+	cs, err := mock.ConfigurationItems("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cs.SetValue("mock-clusters", []string{"alpha", "beta"})).To(Succeed())
+
+	p, err := mock.New(&provider.PluginCreationInput{Logger: zap.NewNop().Sugar()})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	id := identity.NewTokenIdentity("bob", "abc", "mock")
+	out, err := p.Discover(context.Background(), &discovery.DiscoverInput{ConfigSet: cs, Identity: id})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(out.Clusters).To(HaveLen(2))
+	g.Expect(out.Clusters).To(HaveKey("alpha"))
+
+	namespace := "team"
+	cfgOut, err := p.GetConfig(context.Background(), &discovery.GetConfigInput{Cluster: out.Clusters["beta"], Namespace: &namespace, Identity: id})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*cfgOut.ContextName).To(Equal("mock-beta"))
+	g.Expect(cfgOut.KubeConfig.Clusters["beta"].Server).To(Equal("https://beta.mock.kconnect.local"))
+	g.Expect(cfgOut.KubeConfig.AuthInfos["mock-beta-user"].Token).To(Equal("abc"))
+	g.Expect(cfgOut.KubeConfig.Contexts["mock-beta"].Namespace).To(Equal("team"))
+}
+
+func TestInjectedFailure(t *testing.T) {
+	g := NewWithT(t)
+
+	cs, err := mock.ConfigurationItems("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cs.SetValue("mock-fail", mock.OperationDiscover)).To(Succeed())
+
+	p, err := mock.New(&provider.PluginCreationInput{Logger: zap.NewNop().Sugar()})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = p.Discover(context.Background(), &discovery.DiscoverInput{ConfigSet: cs, Identity: identity.NewTokenIdentity("bob", "abc", "mock")})
+	g.Expect(errors.Is(err, mock.ErrMockFailure)).To(BeTrue())
+
+	cs, err = mock.ConfigurationItems("")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, err = p.GetCluster(context.Background(), &discovery.GetClusterInput{ClusterID: "missing", ConfigSet: cs, Identity: identity.NewTokenIdentity("bob", "abc", "mock")})
+	g.Expect(errors.Is(err, mock.ErrClusterNotFound)).To(BeTrue())
+}
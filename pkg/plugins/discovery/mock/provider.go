@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/provider"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+const (
+	ProviderName = "mock"
+	UsageExample = `  # Discover the default mock clusters
+	{{.CommandPath}} use mock --mock-plugins
+
+	# Discover your own list of mock clusters
+	{{.CommandPath}} use mock --mock-plugins --mock-clusters dev,prod
+
+	# Reproduce a slow provider that fails getting the kubeconfig
+	{{.CommandPath}} use mock --mock-plugins --mock-latency 5s --mock-fail get-config
+  `
+
+	clustersConfigItem = "mock-clusters"
+	latencyConfigItem  = "mock-latency"
+	failConfigItem     = "mock-fail"
+
+	// OperationDiscover is the discovery of the clusters
+	OperationDiscover = "discover"
+	// OperationGetCluster is getting the details of a single cluster
+	OperationGetCluster = "get-cluster"
+	// OperationGetConfig is getting the kubeconfig of the selected cluster
+	OperationGetConfig = "get-config"
+)
+
+// Register will register the mock discovery plugin. It isn't registered by default so
+// it's only available when the hidden --mock-plugins flag is set.
+func Register() error {
+	if err := registry.RegisterDiscoveryPlugin(&registry.DiscoveryPluginRegistration{
+		PluginRegistration: registry.PluginRegistration{
+			Name:                   ProviderName,
+			UsageExample:           UsageExample,
+			ConfigurationItemsFunc: ConfigurationItems,
+			Capabilities:           []registry.Capability{registry.CapabilitySupportsRefresh},
+		},
+		CreateFunc:                 New,
+		SupportedIdentityProviders: []string{"mock", "static-token"},
+	}); err != nil {
+		return fmt.Errorf("registering mock discovery plugin: %w", err)
+	}
+
+	return nil
+}
+
+// New will create a new mock discovery plugin
+func New(input *provider.PluginCreationInput) (discovery.Provider, error) {
+	return &mockClusterProvider{
+		logger: input.Logger,
+	}, nil
+}
+
+type mockClusterProviderConfig struct {
+	Clusters []string      `json:"mock-clusters"`
+	Latency  time.Duration `json:"mock-latency"`
+	Fail     string        `json:"mock-fail"`
+}
+
+type mockClusterProvider struct {
+	config *mockClusterProviderConfig
+	token  string
+
+	logger *zap.SugaredLogger
+}
+
+func (p *mockClusterProvider) Name() string {
+	return ProviderName
+}
+
+func (p *mockClusterProvider) setup(cs config.ConfigurationSet, userID identity.Identity) error {
+	cfg := &mockClusterProviderConfig{}
+	if err := config.Unmarshall(cs, cfg); err != nil {
+		return fmt.Errorf("unmarshalling config items into mockClusterProviderConfig: %w", err)
+	}
+	p.config = cfg
+
+	id, ok := userID.(*identity.TokenIdentity)
+	if !ok {
+		return identity.ErrNotTokenIdentity
+	}
+	p.token = id.Token()
+
+	return nil
+}
+
+// simulate will wait for the configured latency and then return an error if the
+// operation is the one that's configured to fail
+func (p *mockClusterProvider) simulate(ctx context.Context, operation string) error {
+	if p.config.Latency > 0 {
+		p.logger.Debugw("waiting before responding", "operation", operation, "latency", p.config.Latency.String())
+		select {
+		case <-time.After(p.config.Latency):
+		case <-ctx.Done():
+			return ctx.Err()
+		}
+	}
+
+	if p.config.Fail == operation {
+		return fmt.Errorf("mock %s: %w", operation, ErrMockFailure)
+	}
+
+	return nil
+}
+
+func (p *mockClusterProvider) ListPreReqs() []provider.PreReq {
+	return []provider.PreReq{}
+}
+
+func (p *mockClusterProvider) CheckPreReqs() error {
+	return nil
+}
+
+func ConfigurationItems(scopeTo string) (config.ConfigurationSet, error) {
+	cs := config.NewConfigurationSet()
+
+	cs.StringSlice(clustersConfigItem, []string{"dev", "test", "prod"}, "Comma separated names of the clusters the mock discovers")                                       //nolint:errcheck
+	cs.Duration(latencyConfigItem, 0, "How long each call to the mock waits before responding, to simulate a slow provider")                                              //nolint:errcheck
+	cs.Enum(failConfigItem, "", []string{OperationDiscover, OperationGetCluster, OperationGetConfig}, "An operation of the mock that fails, e.g. discover or get-config") //nolint:errcheck
+
+	return cs, nil
+}
@@ -26,7 +26,6 @@ import (
 	"k8s.io/client-go/tools/clientcmd/api"
 
 	"github.com/fidelity/kconnect/pkg/defaults"
-	khttp "github.com/fidelity/kconnect/pkg/http"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 	"github.com/fidelity/kconnect/pkg/rancher"
 )
@@ -67,9 +66,8 @@ func (p *rancherClusterProvider) getClusterDetails(clusterID string) (*clusterDe
 	}
 
 	headers := defaults.Headers(defaults.WithJSON(), defaults.WithBearerAuth(p.token))
-	httpClient := khttp.NewHTTPClient()
 
-	resp, err := httpClient.Get(resolver.Cluster(clusterID), headers)
+	resp, err := p.httpClient.Get(resolver.Cluster(clusterID), headers)
 	if err != nil {
 		return nil, fmt.Errorf("getting cluster %s using api: %w", clusterID, err)
 	}
@@ -93,9 +91,8 @@ func (p *rancherClusterProvider) getKubeconfig(clusterDetail *clusterDetails) (*
 	}
 
 	headers := defaults.Headers(defaults.WithJSON(), defaults.WithBearerAuth(p.token))
-	httpClient := khttp.NewHTTPClient()
 
-	resp, err := httpClient.Post(actionURL, "{}", headers)
+	resp, err := p.httpClient.Post(actionURL, "{}", headers)
 	if err != nil {
 		return nil, fmt.Errorf("getting cluster %s kubeconfig using api: %w", clusterDetail.ID, err)
 	}
@@ -23,7 +23,6 @@ import (
 	"net/http"
 
 	"github.com/fidelity/kconnect/pkg/defaults"
-	khttp "github.com/fidelity/kconnect/pkg/http"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 	"github.com/fidelity/kconnect/pkg/provider/identity"
 	"github.com/fidelity/kconnect/pkg/rancher"
@@ -69,9 +68,8 @@ func (p *rancherClusterProvider) listClusters() ([]*discovery.Cluster, error) {
 	}
 
 	headers := defaults.Headers(defaults.WithJSON(), defaults.WithBearerAuth(p.token))
-	httpClient := khttp.NewHTTPClient()
 
-	resp, err := httpClient.Get(resolver.ClustersList(), headers)
+	resp, err := p.httpClient.Get(resolver.ClustersList(), headers)
 	if err != nil {
 		return nil, fmt.Errorf("getting clusters using api: %w", err)
 	}
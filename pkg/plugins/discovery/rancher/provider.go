@@ -36,6 +36,9 @@ const (
 	UsageExample = `  # Discover Rancher clusters using Active Directory
 	{{.CommandPath}} use rancher --idp-protocol rancher-ad
 
+	# Discover Rancher clusters logging in with GitHub using the browser
+	{{.CommandPath}} use rancher --idp-protocol rancher-ad --rancher-auth-provider github
+
 	# Discover clusters via Rancher using a API key
 	{{.CommandPath}} use rancher --idp-protocol static-token --token ABCDEF
   `
@@ -47,6 +50,7 @@ func init() {
 			Name:                   ProviderName,
 			UsageExample:           UsageExample,
 			ConfigurationItemsFunc: ConfigurationItems,
+			Capabilities:           []registry.Capability{registry.CapabilitySupportsRefresh},
 		},
 		CreateFunc:                 New,
 		SupportedIdentityProviders: []string{"static-token", "rancher-ad"},
@@ -102,8 +106,8 @@ func (p *rancherClusterProvider) setup(cs config.ConfigurationSet, userID identi
 	return nil
 }
 
-func (p *rancherClusterProvider) ListPreReqs() []*provider.PreReq {
-	return []*provider.PreReq{}
+func (p *rancherClusterProvider) ListPreReqs() []provider.PreReq {
+	return []provider.PreReq{}
 }
 
 func (p *rancherClusterProvider) CheckPreReqs() error {
@@ -20,25 +20,12 @@ import (
 	"fmt"
 
 	"github.com/fidelity/kconnect/pkg/config"
-	kerrors "github.com/fidelity/kconnect/pkg/errors"
 	"github.com/fidelity/kconnect/pkg/provider/identity"
 	rshared "github.com/fidelity/kconnect/pkg/rancher"
 )
 
 func (p *rancherClusterProvider) Validate(cfg config.ConfigurationSet) error {
-	errsValidation := &kerrors.ValidationFailed{}
-
-	for _, item := range cfg.GetAll() {
-		if item.Required && !cfg.ExistsWithValue(item.Name) {
-			errsValidation.AddFailure(fmt.Sprintf("%s is required", item.Name))
-		}
-	}
-
-	if len(errsValidation.Failures()) > 0 {
-		return errsValidation
-	}
-
-	return nil
+	return config.ValidateRequired(cfg)
 }
 
 // Resolve will resolve the values for the AWS specific flags that have no value. It will
@@ -0,0 +1,151 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+// describeCache holds the descriptions of the external plugins so that a plugin is
+// only run to describe it when its executable changes. An empty path disables the cache.
+type describeCache struct {
+	path    string
+	entries map[string]*cacheEntry
+	changed bool
+}
+
+// cacheEntry is the description of a plugin executable. It's used as long as the
+// modification time and hash of the executable are unchanged.
+type cacheEntry struct {
+	ModTime   time.Time    `json:"modTime"`
+	Hash      string       `json:"hash"`
+	Identity  *Description `json:"identity,omitempty"`
+	Discovery *Description `json:"discovery,omitempty"`
+	MFA       *Description `json:"mfa,omitempty"`
+}
+
+func loadDescribeCache(path string) *describeCache {
+	cache := &describeCache{
+		path:    path,
+		entries: map[string]*cacheEntry{},
+	}
+	if path == "" {
+		return cache
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if !os.IsNotExist(err) {
+			zap.S().Debugw("failed to read plugins cache", "path", path, "error", err.Error())
+		}
+		return cache
+	}
+	if err := json.Unmarshal(data, &cache.entries); err != nil {
+		zap.S().Debugw("ignoring invalid plugins cache", "path", path, "error", err.Error())
+		cache.entries = map[string]*cacheEntry{}
+	}
+
+	return cache
+}
+
+// get returns the cached description of the plugin if its executable hasn't changed
+func (c *describeCache) get(plugin *pluginExecutable) *cacheEntry {
+	entry, ok := c.entries[plugin.path]
+	if !ok {
+		return nil
+	}
+
+	modTime, hash, err := fingerprint(plugin.path)
+	if err != nil || !entry.ModTime.Equal(modTime) || entry.Hash != hash {
+		return nil
+	}
+
+	return entry
+}
+
+func (c *describeCache) set(plugin *pluginExecutable, entry *cacheEntry) {
+	modTime, hash, err := fingerprint(plugin.path)
+	if err != nil {
+		zap.S().Debugw("not caching plugin description", "path", plugin.path, "error", err.Error())
+		return
+	}
+	entry.ModTime = modTime
+	entry.Hash = hash
+
+	c.entries[plugin.path] = entry
+	c.changed = true
+}
+
+// save writes the cache, without the plugins that are no longer installed
+func (c *describeCache) save(plugins []*pluginExecutable) error {
+	if c.path == "" {
+		return nil
+	}
+
+	installed := map[string]*cacheEntry{}
+	for _, plugin := range plugins {
+		if entry, ok := c.entries[plugin.path]; ok {
+			installed[plugin.path] = entry
+		}
+	}
+	if !c.changed && len(installed) == len(c.entries) {
+		return nil
+	}
+
+	data, err := json.Marshal(installed)
+	if err != nil {
+		return fmt.Errorf("marshalling plugins cache: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(c.path), os.ModePerm); err != nil {
+		return fmt.Errorf("creating plugins cache directory: %w", err)
+	}
+	if err := os.WriteFile(c.path, data, 0600); err != nil {
+		return fmt.Errorf("writing plugins cache %s: %w", c.path, err)
+	}
+
+	return nil
+}
+
+// fingerprint returns the modification time and SHA-256 hash of the executable
+func fingerprint(path string) (time.Time, string, error) {
+	info, err := os.Stat(path)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("getting plugin file info: %w", err)
+	}
+
+	file, err := os.Open(path)
+	if err != nil {
+		return time.Time{}, "", fmt.Errorf("opening plugin: %w", err)
+	}
+	defer file.Close()
+
+	hash := sha256.New()
+	if _, err := io.Copy(hash, file); err != nil {
+		return time.Time{}, "", fmt.Errorf("hashing plugin: %w", err)
+	}
+
+	return info.ModTime().UTC(), hex.EncodeToString(hash.Sum(nil)), nil
+}
@@ -0,0 +1,146 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"fmt"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/prompt"
+)
+
+// configurationItemsFunc returns a function that creates the configuration items described by a plugin
+func configurationItemsFunc(items []*ConfigurationItem) func(scopeTo string) (config.ConfigurationSet, error) {
+	return func(scopeTo string) (config.ConfigurationSet, error) {
+		cs := config.NewConfigurationSet()
+
+		for _, item := range items {
+			if err := addConfigurationItem(cs, item); err != nil {
+				return nil, fmt.Errorf("adding configuration item %s: %w", item.Name, err)
+			}
+		}
+
+		return cs, nil
+	}
+}
+
+func addConfigurationItem(cs config.ConfigurationSet, pluginItem *ConfigurationItem) error {
+	var item *config.Item
+	var err error
+
+	switch config.ItemType(pluginItem.Type) {
+	case config.ItemTypeString, "":
+		item, err = cs.String(pluginItem.Name, "", pluginItem.Description)
+	case config.ItemTypeInt:
+		item, err = cs.Int(pluginItem.Name, 0, pluginItem.Description)
+	case config.ItemTypeBool:
+		item, err = cs.Bool(pluginItem.Name, false, pluginItem.Description)
+	case config.ItemTypeDuration:
+		item, err = cs.Duration(pluginItem.Name, 0, pluginItem.Description)
+	case config.ItemTypeStringSlice:
+		item, err = cs.StringSlice(pluginItem.Name, []string{}, pluginItem.Description)
+	case config.ItemTypeEnum:
+		item, err = cs.Enum(pluginItem.Name, "", pluginItem.AllowedValues, pluginItem.Description)
+	case config.ItemTypeStringMap:
+		item, err = cs.StringMap(pluginItem.Name, map[string]string{}, pluginItem.Description)
+	default:
+		return fmt.Errorf("type %s: %w", pluginItem.Type, config.ErrUnknownItemType)
+	}
+	if err != nil {
+		return err
+	}
+
+	if pluginItem.Default != "" {
+		defaultValue, err := config.ParseItemValue(item, pluginItem.Default)
+		if err != nil {
+			return fmt.Errorf("parsing default value: %w", err)
+		}
+		item.DefaultValue = defaultValue
+	}
+	item.Shorthand = pluginItem.Shorthand
+	item.Required = pluginItem.Required
+	item.Sensitive = pluginItem.Sensitive
+	item.Hidden = pluginItem.Hidden
+	if len(pluginItem.AllowedValues) > 0 {
+		item.AllowedValues = pluginItem.AllowedValues
+	}
+
+	return nil
+}
+
+// configValues returns the values of the items in the configuration set as strings
+func configValues(cs config.ConfigurationSet) map[string]string {
+	values := map[string]string{}
+	if cs == nil {
+		return values
+	}
+
+	for _, item := range cs.GetAll() {
+		if item.HasValue() {
+			values[item.Name] = item.ValueString()
+		}
+	}
+
+	return values
+}
+
+// resolveRequired will ask the user for the values of the required items in the
+// plugins configuration that don't have a value
+func resolveRequired(cs config.ConfigurationSet, items []*ConfigurationItem, interactive bool) error {
+	if !interactive {
+		return nil
+	}
+
+	for _, item := range items {
+		if !item.Required || cs.ExistsWithValue(item.Name) {
+			continue
+		}
+
+		message := item.Description
+		if message == "" {
+			message = fmt.Sprintf("Enter %s", item.Name)
+		}
+
+		var err error
+		if item.Sensitive {
+			err = prompt.InputSensitiveAndSet(cs, item.Name, message, true)
+		} else {
+			err = prompt.InputAndSet(cs, item.Name, message, true)
+		}
+		if err != nil {
+			return fmt.Errorf("resolving %s: %w", item.Name, err)
+		}
+	}
+
+	return nil
+}
+
+// applyResolved sets the values that a plugin resolved in the configuration set
+func applyResolved(cs config.ConfigurationSet, values map[string]string) error {
+	for name, value := range values {
+		item := cs.Get(name)
+		if item == nil || item.HasValue() {
+			continue
+		}
+		if err := config.SetItemValue(item, value); err != nil {
+			return fmt.Errorf("setting resolved value for %s: %w", name, err)
+		}
+		item.Source = config.ItemSourceResolved
+	}
+
+	return nil
+}
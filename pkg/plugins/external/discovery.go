@@ -0,0 +1,201 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"go.uber.org/zap"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/provider"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+var (
+	ErrNoCluster    = errors.New("external plugin didn't return a cluster")
+	ErrNoKubeconfig = errors.New("external plugin didn't return a kubeconfig")
+)
+
+func newDiscoveryProvider(exec *executor, desc *Description) discovery.ProviderCreatorFun {
+	return func(input *provider.PluginCreationInput) (discovery.Provider, error) {
+		return &externalDiscoveryProvider{
+			exec:        exec,
+			description: desc,
+			logger:      input.Logger,
+			interactive: input.IsInteractice,
+		}, nil
+	}
+}
+
+type externalDiscoveryProvider struct {
+	exec        *executor
+	description *Description
+	logger      *zap.SugaredLogger
+	interactive bool
+}
+
+func (p *externalDiscoveryProvider) Name() string {
+	return p.description.Name
+}
+
+// ListPreReqs returns no pre-requisites as they are checked by the external plugin
+func (p *externalDiscoveryProvider) ListPreReqs() []provider.PreReq {
+	return []provider.PreReq{}
+}
+
+// CheckPreReqs will ask the external plugin to check its pre-requisites
+func (p *externalDiscoveryProvider) CheckPreReqs() error {
+	_, err := p.exec.call(context.Background(), &Request{
+		Method:      MethodCheckPreReqs,
+		Interactive: p.interactive,
+	})
+
+	return err
+}
+
+// Validate will ask the external plugin to validate the configuration
+func (p *externalDiscoveryProvider) Validate(cs config.ConfigurationSet) error {
+	_, err := p.exec.call(context.Background(), &Request{
+		Method:      MethodValidate,
+		Interactive: p.interactive,
+		Config:      configValues(cs),
+	})
+
+	return err
+}
+
+// Resolve will ask the user for any required values that are missing and then
+// ask the external plugin to resolve the rest of the configuration
+func (p *externalDiscoveryProvider) Resolve(cs config.ConfigurationSet, userID identity.Identity) error {
+	if err := resolveRequired(cs, p.description.ConfigurationItems, p.interactive); err != nil {
+		return fmt.Errorf("resolving config: %w", err)
+	}
+
+	resp, err := p.exec.call(context.Background(), &Request{
+		Method:      MethodResolve,
+		Interactive: p.interactive,
+		Config:      configValues(cs),
+		Identity:    toProtocolIdentity(userID),
+	})
+	if err != nil {
+		return err
+	}
+
+	return applyResolved(cs, resp.Config)
+}
+
+// Discover will ask the external plugin to discover the clusters
+func (p *externalDiscoveryProvider) Discover(ctx context.Context, input *discovery.DiscoverInput) (*discovery.DiscoverOutput, error) {
+	p.logger.Infof("discovering clusters using external plugin %s", p.description.Name)
+
+	resp, err := p.exec.call(ctx, &Request{
+		Method:      MethodDiscover,
+		Interactive: p.interactive,
+		Config:      configValues(input.ConfigSet),
+		Identity:    toProtocolIdentity(input.Identity),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	discoverOutput := &discovery.DiscoverOutput{
+		DiscoveryProvider: p.description.Name,
+		IdentityProvider:  input.Identity.IdentityProviderName(),
+		Clusters:          make(map[string]*discovery.Cluster),
+	}
+	for _, cluster := range resp.Clusters {
+		discoverOutput.Clusters[cluster.ID] = toDiscoveryCluster(cluster)
+	}
+
+	return discoverOutput, nil
+}
+
+// GetCluster will ask the external plugin for the details of a cluster
+func (p *externalDiscoveryProvider) GetCluster(ctx context.Context, input *discovery.GetClusterInput) (*discovery.GetClusterOutput, error) {
+	resp, err := p.exec.call(ctx, &Request{
+		Method:      MethodGetCluster,
+		Interactive: p.interactive,
+		Config:      configValues(input.ConfigSet),
+		Identity:    toProtocolIdentity(input.Identity),
+		ClusterID:   input.ClusterID,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Cluster == nil {
+		return nil, ErrNoCluster
+	}
+
+	return &discovery.GetClusterOutput{
+		Cluster: toDiscoveryCluster(resp.Cluster),
+	}, nil
+}
+
+// GetConfig will ask the external plugin to generate the kubeconfig for a cluster
+func (p *externalDiscoveryProvider) GetConfig(ctx context.Context, input *discovery.GetConfigInput) (*discovery.GetConfigOutput, error) {
+	req := &Request{
+		Method:      MethodGetConfig,
+		Interactive: p.interactive,
+		Identity:    toProtocolIdentity(input.Identity),
+		Cluster: &Cluster{
+			ID:                       input.Cluster.ID,
+			Name:                     input.Cluster.Name,
+			ControlPlaneEndpoint:     input.Cluster.ControlPlaneEndpoint,
+			CertificateAuthorityData: input.Cluster.CertificateAuthorityData,
+		},
+	}
+	if input.Namespace != nil {
+		req.Namespace = *input.Namespace
+	}
+
+	resp, err := p.exec.call(ctx, req)
+	if err != nil {
+		return nil, err
+	}
+	if resp.Kubeconfig == "" {
+		return nil, ErrNoKubeconfig
+	}
+
+	kubeConfig, err := clientcmd.Load([]byte(resp.Kubeconfig))
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig from %s: %w", p.description.Name, err)
+	}
+
+	contextName := resp.ContextName
+	if contextName == "" {
+		contextName = kubeConfig.CurrentContext
+	}
+
+	return &discovery.GetConfigOutput{
+		KubeConfig:  kubeConfig,
+		ContextName: &contextName,
+	}, nil
+}
+
+func toDiscoveryCluster(cluster *Cluster) *discovery.Cluster {
+	return &discovery.Cluster{
+		ID:                       cluster.ID,
+		Name:                     cluster.Name,
+		ControlPlaneEndpoint:     cluster.ControlPlaneEndpoint,
+		CertificateAuthorityData: cluster.CertificateAuthorityData,
+	}
+}
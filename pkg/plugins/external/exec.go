@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+
+	"go.uber.org/zap"
+)
+
+var (
+	ErrPluginFailed        = errors.New("external plugin failed")
+	ErrUnsupportedProtocol = errors.New("external plugin uses an unsupported protocol version")
+)
+
+// executor runs an external plugin executable for each request
+type executor struct {
+	path string
+}
+
+func (e *executor) call(ctx context.Context, req *Request) (*Response, error) {
+	req.APIVersion = ProtocolVersion
+	reqData, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling %s request: %w", req.Method, err)
+	}
+
+	zap.S().Debugw("calling external plugin", "path", e.path, "method", req.Method)
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, e.path) //nolint: gosec
+	cmd.Stdin = bytes.NewReader(reqData)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	runErr := cmd.Run()
+
+	resp := &Response{}
+	if stdout.Len() > 0 {
+		if err := json.Unmarshal(stdout.Bytes(), resp); err != nil {
+			return nil, fmt.Errorf("parsing %s response from %s: %w", req.Method, e.path, err)
+		}
+	}
+
+	if resp.Error != "" {
+		return nil, fmt.Errorf("%s %s: %s: %w", e.path, req.Method, resp.Error, ErrPluginFailed)
+	}
+	if runErr != nil {
+		return nil, fmt.Errorf("running %s %s: %w", e.path, req.Method, runErr)
+	}
+	if resp.APIVersion != ProtocolVersion {
+		return nil, fmt.Errorf("%s responded with %q, expected %q: %w", e.path, resp.APIVersion, ProtocolVersion, ErrUnsupportedProtocol)
+	}
+
+	return resp, nil
+}
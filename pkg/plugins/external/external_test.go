@@ -0,0 +1,262 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external_test
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/plugins/external"
+	"github.com/fidelity/kconnect/pkg/provider"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+const (
+	fakePluginEnv  = "KCONNECT_TEST_FAKE_PLUGIN"
+	describeLogEnv = "KCONNECT_TEST_DESCRIBE_LOG"
+)
+
+const fakeKubeconfig = `apiVersion: v1
+kind: Config
+clusters:
+- name: cluster1
+  cluster:
+    server: https://cluster1.example.com
+contexts:
+- name: cluster1
+  context:
+    cluster: cluster1
+    user: bob
+current-context: cluster1
+users:
+- name: bob
+  user:
+    token: abc
+`
+
+// TestMain lets the test binary act as an external plugin when it's run
+// through one of the links created by the tests
+func TestMain(m *testing.M) {
+	if os.Getenv(fakePluginEnv) != "" {
+		runFakePlugin()
+		os.Exit(0)
+	}
+
+	os.Exit(m.Run())
+}
+
+func runFakePlugin() {
+	req := &external.Request{}
+	if err := json.NewDecoder(os.Stdin).Decode(req); err != nil {
+		os.Exit(1)
+	}
+
+	resp := &external.Response{APIVersion: external.ProtocolVersion}
+	name := filepath.Base(os.Args[0])
+
+	if logPath := os.Getenv(describeLogEnv); logPath != "" && req.Method == external.MethodDescribe {
+		logFile, err := os.OpenFile(logPath, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0600)
+		if err != nil {
+			os.Exit(1)
+		}
+		fmt.Fprintln(logFile, name)
+		logFile.Close()
+	}
+
+	switch {
+	case strings.HasSuffix(name, "oldversion"):
+		resp.APIVersion = "kconnect.fidelity.github.com/plugin/v0"
+	case req.Method == external.MethodDescribe && strings.HasPrefix(name, external.MFAPluginPrefix):
+		resp.Description = &external.Description{MFAMethods: []string{"webauthn"}}
+	case req.Method == external.MethodHandleMFA:
+		resp.MFAResponse = &identity.MFAResponse{
+			WebAuthn: &identity.WebAuthnAssertion{Signature: append([]byte("signed-"), req.MFAChallenge.WebAuthn.Challenge...)},
+		}
+	case req.Method == external.MethodDescribe && strings.HasPrefix(name, external.IdentityPluginPrefix):
+		resp.Description = &external.Description{
+			ConfigurationItems: []*external.ConfigurationItem{
+				{Name: "fake-token", Type: "string", Required: true, Sensitive: true},
+			},
+		}
+	case req.Method == external.MethodDescribe:
+		resp.Description = &external.Description{
+			SupportedIdentityProviders: []string{"fake"},
+			ConfigurationItems: []*external.ConfigurationItem{
+				{Name: "fake-region", Type: "enum", AllowedValues: []string{"east", "west"}, Default: "east"},
+			},
+		}
+	case req.Method == external.MethodAuthenticate:
+		resp.Identity = &external.Identity{Type: "token", Name: "bob", Token: req.Config["fake-token"]}
+	case req.Method == external.MethodDiscover:
+		if req.Identity == nil || req.Identity.Token != "abc" {
+			resp.Error = "not authorized"
+			break
+		}
+		resp.Clusters = []*external.Cluster{{ID: "cluster1", Name: req.Config["fake-region"] + "-cluster1"}}
+	case req.Method == external.MethodGetConfig:
+		resp.Kubeconfig = fakeKubeconfig
+	}
+
+	if err := json.NewEncoder(os.Stdout).Encode(resp); err != nil {
+		os.Exit(1)
+	}
+}
+
+func TestRegisterPluginsFromPath(t *testing.T) {
+	g := NewWithT(t)
+
+	testBinary, err := os.Executable()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	dir := t.TempDir()
+	for _, name := range []string{"kconnect-identity-fake", "kconnect-discovery-fake", "kconnect-discovery-oldversion", "kconnect-mfa-fake"} {
+		g.Expect(os.Symlink(testBinary, filepath.Join(dir, name))).To(Succeed())
+	}
+	t.Setenv(fakePluginEnv, "true")
+
+	external.RegisterPluginsFromPath(dir, "")
+
+	_, err = registry.GetDiscoveryProviderRegistration("oldversion")
+	g.Expect(err).To(MatchError(registry.ErrPluginNotFound))
+
+	discoReg, err := registry.GetDiscoveryProviderRegistration("fake")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(discoReg.SupportedIdentityProviders).To(Equal([]string{"fake"}))
+
+	cs, err := discoReg.ConfigurationItemsFunc("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cs.Get("fake-region").DefaultValue).To(Equal("east"))
+
+	idReg, err := registry.GetIdentityProviderRegistration("fake")
+	g.Expect(err).NotTo(HaveOccurred())
+	idCfg, err := idReg.ConfigurationItemsFunc("fake")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cs.AddSet(idCfg)).To(Succeed())
+	g.Expect(cs.Get("fake-token").Sensitive).To(BeTrue())
+	g.Expect(cs.SetValue("fake-token", "abc")).To(Succeed())
+	g.Expect(cs.SetValue("fake-region", "west")).To(Succeed())
+
+	input := &provider.PluginCreationInput{Logger: zap.S()}
+	ctx := context.Background()
+
+	idProvider, err := registry.GetIdentityProvider("fake", input)
+	g.Expect(err).NotTo(HaveOccurred())
+	authOutput, err := idProvider.Authenticate(ctx, &identity.AuthenticateInput{ConfigSet: cs})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(authOutput.Identity.Name()).To(Equal("bob"))
+	g.Expect(authOutput.Identity.IdentityProviderName()).To(Equal("fake"))
+
+	discoProvider, err := registry.GetDiscoveryProvider("fake", input)
+	g.Expect(err).NotTo(HaveOccurred())
+	discoverOutput, err := discoProvider.Discover(ctx, &discovery.DiscoverInput{ConfigSet: cs, Identity: authOutput.Identity})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(discoverOutput.Clusters).To(HaveKey("cluster1"))
+	g.Expect(discoverOutput.Clusters["cluster1"].Name).To(Equal("west-cluster1"))
+
+	_, err = discoProvider.Discover(ctx, &discovery.DiscoverInput{
+		ConfigSet: config.NewConfigurationSet(),
+		Identity:  identity.NewTokenIdentity("bob", "wrong", "fake"),
+	})
+	g.Expect(err).To(MatchError(ContainSubstring("not authorized")))
+
+	configOutput, err := discoProvider.GetConfig(ctx, &discovery.GetConfigInput{
+		Cluster:  discoverOutput.Clusters["cluster1"],
+		Identity: authOutput.Identity,
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(*configOutput.ContextName).To(Equal("cluster1"))
+	g.Expect(configOutput.KubeConfig.Clusters).To(HaveKey("cluster1"))
+
+	mfaResponse, err := identity.HandleMFA(ctx, &identity.MFAChallenge{
+		Method:   identity.MFAMethodWebAuthn,
+		Provider: "fake",
+		WebAuthn: &identity.WebAuthnChallenge{RelyingPartyID: "example.com", Challenge: []byte("abc")},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(mfaResponse.WebAuthn.Signature).To(Equal([]byte("signed-abc")))
+}
+
+func TestRegisterPluginsCache(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	pluginPath := filepath.Join(dir, "kconnect-identity-cached")
+	copyTestBinary(t, pluginPath)
+	cachePath := filepath.Join(t.TempDir(), "plugins.json")
+	describeLog := filepath.Join(t.TempDir(), "describe.log")
+	t.Setenv(fakePluginEnv, "true")
+	t.Setenv(describeLogEnv, describeLog)
+
+	describeCount := func() int {
+		data, err := os.ReadFile(describeLog)
+		if os.IsNotExist(err) {
+			return 0
+		}
+		g.Expect(err).NotTo(HaveOccurred())
+		return strings.Count(string(data), "\n")
+	}
+
+	external.RegisterPluginsFromPath(dir, cachePath)
+	g.Expect(describeCount()).To(Equal(1))
+	g.Expect(cachePath).To(BeAnExistingFile())
+
+	idReg, err := registry.GetIdentityProviderRegistration("cached")
+	g.Expect(err).NotTo(HaveOccurred())
+	cs, err := idReg.ConfigurationItemsFunc("")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cs.Get("fake-token")).NotTo(BeNil())
+
+	// The cached description is used while the plugin is unchanged
+	external.RegisterPluginsFromPath(dir, cachePath)
+	g.Expect(describeCount()).To(Equal(1))
+
+	modTime := time.Now().Add(time.Hour)
+	g.Expect(os.Chtimes(pluginPath, modTime, modTime)).To(Succeed())
+	external.RegisterPluginsFromPath(dir, cachePath)
+	g.Expect(describeCount()).To(Equal(2))
+}
+
+func copyTestBinary(t *testing.T, path string) {
+	g := NewWithT(t)
+
+	testBinary, err := os.Executable()
+	g.Expect(err).NotTo(HaveOccurred())
+
+	src, err := os.Open(testBinary)
+	g.Expect(err).NotTo(HaveOccurred())
+	defer src.Close()
+
+	dst, err := os.OpenFile(path, os.O_CREATE|os.O_WRONLY, 0700) //nolint: gosec
+	g.Expect(err).NotTo(HaveOccurred())
+	defer dst.Close()
+
+	_, err = io.Copy(dst, src)
+	g.Expect(err).NotTo(HaveOccurred())
+}
@@ -0,0 +1,473 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"github.com/hashicorp/go-hclog"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"k8s.io/client-go/tools/clientcmd"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/plugin/pluginpb"
+	"github.com/fidelity/kconnect/pkg/plugin/sdk"
+	"github.com/fidelity/kconnect/pkg/provider"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+var ErrNoPluginTypes = errors.New("plugin doesn't implement a discovery or identity plugin")
+
+// describeGRPCPlugin will start a long-running plugin to describe the discovery and
+// identity plugins that it implements. The plugin is stopped afterwards, it's started
+// again when it's used.
+func describeGRPCPlugin(plugin *pluginExecutable) (*cacheEntry, error) {
+	client, err := sdk.NewClient(plugin.path, newPluginLogger(plugin.name))
+	if err != nil {
+		return nil, err
+	}
+	defer client.Close()
+
+	ctx, cancel := context.WithTimeout(context.Background(), describeTimeout)
+	defer cancel()
+
+	entry := &cacheEntry{}
+
+	idPlugin, err := client.Identity()
+	if err != nil {
+		return nil, err
+	}
+	idDesc, err := idPlugin.Describe(ctx, &pluginpb.DescribeRequest{})
+	switch {
+	case errors.Is(err, sdk.ErrNotImplemented):
+	case err != nil:
+		return nil, fmt.Errorf("describing identity plugin: %w", err)
+	default:
+		entry.Identity = fromPBDescription(idDesc)
+	}
+
+	discoPlugin, err := client.Discovery()
+	if err != nil {
+		return nil, err
+	}
+	discoDesc, err := discoPlugin.Describe(ctx, &pluginpb.DescribeRequest{})
+	switch {
+	case errors.Is(err, sdk.ErrNotImplemented):
+	case err != nil:
+		return nil, fmt.Errorf("describing discovery plugin: %w", err)
+	default:
+		entry.Discovery = fromPBDescription(discoDesc)
+	}
+
+	if entry.Identity == nil && entry.Discovery == nil {
+		return nil, ErrNoPluginTypes
+	}
+
+	return entry, nil
+}
+
+func newGRPCRegistrations(plugin *pluginExecutable, entry *cacheEntry) ([]*registration, error) {
+	client := &grpcClient{plugin: plugin}
+	registrations := []*registration{}
+
+	if entry.Identity != nil {
+		pluginRegistration, err := newPluginRegistration(plugin, entry.Identity)
+		if err != nil {
+			return nil, err
+		}
+		registrations = append(registrations, &registration{
+			path: plugin.path,
+			identity: &registry.IdentityPluginRegistration{
+				PluginRegistration: *pluginRegistration,
+				CreateFunc:         newGRPCIdentityProvider(client, entry.Identity),
+			},
+		})
+	}
+
+	if entry.Discovery != nil {
+		pluginRegistration, err := newPluginRegistration(plugin, entry.Discovery)
+		if err != nil {
+			return nil, err
+		}
+		registrations = append(registrations, &registration{
+			path: plugin.path,
+			discovery: &registry.DiscoveryPluginRegistration{
+				PluginRegistration:         *pluginRegistration,
+				SupportedIdentityProviders: entry.Discovery.SupportedIdentityProviders,
+				CreateFunc:                 newGRPCDiscoveryProvider(client, entry.Discovery),
+			},
+		})
+	}
+
+	if len(registrations) == 0 {
+		return nil, ErrNoPluginTypes
+	}
+
+	return registrations, nil
+}
+
+// grpcClient starts the long-running plugin the first time one of its discovery
+// or identity plugins is created. It's kept running until Cleanup is called.
+type grpcClient struct {
+	plugin *pluginExecutable
+
+	once   sync.Once
+	client *sdk.Client
+	err    error
+}
+
+func (c *grpcClient) start() (*sdk.Client, error) {
+	c.once.Do(func() {
+		c.client, c.err = sdk.NewClient(c.plugin.path, newPluginLogger(c.plugin.name))
+	})
+
+	return c.client, c.err
+}
+
+func newPluginLogger(name string) hclog.Logger {
+	level := hclog.Warn
+	if zap.S().Desugar().Core().Enabled(zapcore.DebugLevel) {
+		level = hclog.Debug
+	}
+
+	return hclog.New(&hclog.LoggerOptions{
+		Name:  fmt.Sprintf("plugin.%s", name),
+		Level: level,
+	})
+}
+
+func newGRPCIdentityProvider(client *grpcClient, desc *Description) identity.ProviderCreatorFun {
+	return func(input *provider.PluginCreationInput) (identity.Provider, error) {
+		pluginClient, err := client.start()
+		if err != nil {
+			return nil, fmt.Errorf("starting plugin %s: %w", desc.Name, err)
+		}
+		idPlugin, err := pluginClient.Identity()
+		if err != nil {
+			return nil, fmt.Errorf("getting identity plugin %s: %w", desc.Name, err)
+		}
+
+		scopedTo := ""
+		if input.ScopedTo != nil {
+			scopedTo = *input.ScopedTo
+		}
+
+		return &grpcIdentityProvider{
+			plugin:      idPlugin,
+			description: desc,
+			logger:      input.Logger,
+			interactive: input.IsInteractice,
+			scopedTo:    scopedTo,
+		}, nil
+	}
+}
+
+type grpcIdentityProvider struct {
+	plugin      sdk.IdentityPlugin
+	description *Description
+	logger      *zap.SugaredLogger
+	interactive bool
+	scopedTo    string
+}
+
+func (p *grpcIdentityProvider) Name() string {
+	return p.description.Name
+}
+
+// Authenticate will ask the plugin to authenticate the user
+func (p *grpcIdentityProvider) Authenticate(ctx context.Context, input *identity.AuthenticateInput) (*identity.AuthenticateOutput, error) {
+	p.logger.Infof("using plugin %s for authentication", p.description.Name)
+
+	if err := resolveRequired(input.ConfigSet, p.description.ConfigurationItems, p.interactive); err != nil {
+		return nil, fmt.Errorf("resolving config: %w", err)
+	}
+
+	id, err := p.plugin.Authenticate(ctx, &pluginpb.AuthenticateRequest{
+		Config:      configValues(input.ConfigSet),
+		ScopedTo:    p.scopedTo,
+		Interactive: p.interactive,
+	}, p.progress)
+	if err != nil {
+		return nil, fmt.Errorf("authenticating using %s: %w", p.description.Name, err)
+	}
+	if id == nil {
+		return nil, ErrNoIdentity
+	}
+
+	protocolID := fromPBIdentity(id)
+	if protocolID.Provider == "" {
+		protocolID.Provider = p.description.Name
+	}
+
+	return &identity.AuthenticateOutput{
+		Identity: &identityAdapter{identity: protocolID},
+	}, nil
+}
+
+// RestoreIdentity creates the identity returned by the plugin from its JSON
+func (p *grpcIdentityProvider) RestoreIdentity(data []byte) (identity.Identity, error) {
+	return restoreIdentity(data)
+}
+
+func (p *grpcIdentityProvider) progress(message string) {
+	p.logger.Info(message)
+}
+
+func newGRPCDiscoveryProvider(client *grpcClient, desc *Description) discovery.ProviderCreatorFun {
+	return func(input *provider.PluginCreationInput) (discovery.Provider, error) {
+		pluginClient, err := client.start()
+		if err != nil {
+			return nil, fmt.Errorf("starting plugin %s: %w", desc.Name, err)
+		}
+		discoPlugin, err := pluginClient.Discovery()
+		if err != nil {
+			return nil, fmt.Errorf("getting discovery plugin %s: %w", desc.Name, err)
+		}
+
+		return &grpcDiscoveryProvider{
+			plugin:      discoPlugin,
+			description: desc,
+			logger:      input.Logger,
+			interactive: input.IsInteractice,
+		}, nil
+	}
+}
+
+type grpcDiscoveryProvider struct {
+	plugin      sdk.DiscoveryPlugin
+	description *Description
+	logger      *zap.SugaredLogger
+	interactive bool
+}
+
+func (p *grpcDiscoveryProvider) Name() string {
+	return p.description.Name
+}
+
+// ListPreReqs returns no pre-requisites as they are checked by the plugin
+func (p *grpcDiscoveryProvider) ListPreReqs() []provider.PreReq {
+	return []provider.PreReq{}
+}
+
+// CheckPreReqs will ask the plugin to check its pre-requisites
+func (p *grpcDiscoveryProvider) CheckPreReqs() error {
+	return p.plugin.CheckPreReqs(context.Background(), &pluginpb.CheckPreReqsRequest{})
+}
+
+// Validate will ask the plugin to validate the configuration
+func (p *grpcDiscoveryProvider) Validate(cs config.ConfigurationSet) error {
+	return p.plugin.Validate(context.Background(), &pluginpb.ValidateRequest{
+		Config: configValues(cs),
+	})
+}
+
+// Resolve will ask the user for any required values that are missing and then
+// ask the plugin to resolve the rest of the configuration
+func (p *grpcDiscoveryProvider) Resolve(cs config.ConfigurationSet, userID identity.Identity) error {
+	if err := resolveRequired(cs, p.description.ConfigurationItems, p.interactive); err != nil {
+		return fmt.Errorf("resolving config: %w", err)
+	}
+
+	resp, err := p.plugin.Resolve(context.Background(), &pluginpb.ResolveRequest{
+		Config:      configValues(cs),
+		Identity:    toPBIdentity(userID),
+		Interactive: p.interactive,
+	})
+	if err != nil {
+		return fmt.Errorf("resolving config using %s: %w", p.description.Name, err)
+	}
+
+	return applyResolved(cs, resp.Config)
+}
+
+// Discover will ask the plugin to discover the clusters
+func (p *grpcDiscoveryProvider) Discover(ctx context.Context, input *discovery.DiscoverInput) (*discovery.DiscoverOutput, error) {
+	p.logger.Infof("discovering clusters using plugin %s", p.description.Name)
+
+	result, err := p.plugin.Discover(ctx, &pluginpb.DiscoverRequest{
+		Config:   configValues(input.ConfigSet),
+		Identity: toPBIdentity(input.Identity),
+	}, p.progress)
+	if err != nil {
+		return nil, fmt.Errorf("discovering clusters using %s: %w", p.description.Name, err)
+	}
+
+	discoverOutput := &discovery.DiscoverOutput{
+		DiscoveryProvider: p.description.Name,
+		IdentityProvider:  input.Identity.IdentityProviderName(),
+		Clusters:          make(map[string]*discovery.Cluster),
+	}
+	for _, cluster := range result.Clusters {
+		discoverOutput.Clusters[cluster.Id] = fromPBCluster(cluster)
+	}
+
+	return discoverOutput, nil
+}
+
+// GetCluster will ask the plugin for the details of a cluster
+func (p *grpcDiscoveryProvider) GetCluster(ctx context.Context, input *discovery.GetClusterInput) (*discovery.GetClusterOutput, error) {
+	resp, err := p.plugin.GetCluster(ctx, &pluginpb.GetClusterRequest{
+		Config:    configValues(input.ConfigSet),
+		Identity:  toPBIdentity(input.Identity),
+		ClusterId: input.ClusterID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("getting cluster using %s: %w", p.description.Name, err)
+	}
+	if resp.Cluster == nil {
+		return nil, ErrNoCluster
+	}
+
+	return &discovery.GetClusterOutput{
+		Cluster: fromPBCluster(resp.Cluster),
+	}, nil
+}
+
+// GetConfig will ask the plugin to generate the kubeconfig for a cluster
+func (p *grpcDiscoveryProvider) GetConfig(ctx context.Context, input *discovery.GetConfigInput) (*discovery.GetConfigOutput, error) {
+	req := &pluginpb.GetConfigRequest{
+		Cluster:  toPBCluster(input.Cluster),
+		Identity: toPBIdentity(input.Identity),
+	}
+	if input.Namespace != nil {
+		req.Namespace = *input.Namespace
+	}
+
+	resp, err := p.plugin.GetConfig(ctx, req)
+	if err != nil {
+		return nil, fmt.Errorf("getting kubeconfig using %s: %w", p.description.Name, err)
+	}
+	if len(resp.Kubeconfig) == 0 {
+		return nil, ErrNoKubeconfig
+	}
+
+	kubeConfig, err := clientcmd.Load(resp.Kubeconfig)
+	if err != nil {
+		return nil, fmt.Errorf("loading kubeconfig from %s: %w", p.description.Name, err)
+	}
+
+	contextName := resp.ContextName
+	if contextName == "" {
+		contextName = kubeConfig.CurrentContext
+	}
+
+	return &discovery.GetConfigOutput{
+		KubeConfig:  kubeConfig,
+		ContextName: &contextName,
+	}, nil
+}
+
+func (p *grpcDiscoveryProvider) progress(message string) {
+	p.logger.Info(message)
+}
+
+func fromPBDescription(desc *pluginpb.Description) *Description {
+	converted := &Description{
+		Name:                       desc.Name,
+		UsageExample:               desc.UsageExample,
+		SupportedIdentityProviders: desc.SupportedIdentityProviders,
+		Capabilities:               desc.Capabilities,
+	}
+	for _, item := range desc.ConfigurationItems {
+		converted.ConfigurationItems = append(converted.ConfigurationItems, &ConfigurationItem{
+			Name:          item.Name,
+			Type:          item.Type,
+			Shorthand:     item.Shorthand,
+			Description:   item.Description,
+			Default:       item.Default,
+			Required:      item.Required,
+			Sensitive:     item.Sensitive,
+			Hidden:        item.Hidden,
+			AllowedValues: item.AllowedValues,
+		})
+	}
+
+	return converted
+}
+
+func fromPBIdentity(id *pluginpb.Identity) *Identity {
+	converted := &Identity{
+		Type:     id.Type,
+		Name:     id.Name,
+		Provider: id.Provider,
+		Token:    id.Token,
+		Data:     id.Data,
+	}
+	if id.ExpiresAt != 0 {
+		expiresAt := time.Unix(id.ExpiresAt, 0)
+		converted.ExpiresAt = &expiresAt
+	}
+
+	return converted
+}
+
+func toPBIdentity(id identity.Identity) *pluginpb.Identity {
+	protocolID := toProtocolIdentity(id)
+	if protocolID == nil {
+		return nil
+	}
+
+	converted := &pluginpb.Identity{
+		Type:     protocolID.Type,
+		Name:     protocolID.Name,
+		Provider: protocolID.Provider,
+		Token:    protocolID.Token,
+		Data:     protocolID.Data,
+	}
+	if protocolID.ExpiresAt != nil {
+		converted.ExpiresAt = protocolID.ExpiresAt.Unix()
+	}
+
+	return converted
+}
+
+func fromPBCluster(cluster *pluginpb.Cluster) *discovery.Cluster {
+	converted := &discovery.Cluster{
+		ID:   cluster.Id,
+		Name: cluster.Name,
+	}
+	if cluster.Endpoint != "" {
+		converted.ControlPlaneEndpoint = &cluster.Endpoint
+	}
+	if cluster.Ca != "" {
+		converted.CertificateAuthorityData = &cluster.Ca
+	}
+
+	return converted
+}
+
+func toPBCluster(cluster *discovery.Cluster) *pluginpb.Cluster {
+	converted := &pluginpb.Cluster{
+		Id:   cluster.ID,
+		Name: cluster.Name,
+	}
+	if cluster.ControlPlaneEndpoint != nil {
+		converted.Endpoint = *cluster.ControlPlaneEndpoint
+	}
+	if cluster.CertificateAuthorityData != nil {
+		converted.Ca = *cluster.CertificateAuthorityData
+	}
+
+	return converted
+}
@@ -0,0 +1,174 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/provider"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+var ErrNoIdentity = errors.New("external plugin didn't return an identity")
+
+func newIdentityProvider(exec *executor, desc *Description) identity.ProviderCreatorFun {
+	return func(input *provider.PluginCreationInput) (identity.Provider, error) {
+		scopedTo := ""
+		if input.ScopedTo != nil {
+			scopedTo = *input.ScopedTo
+		}
+
+		return &externalIdentityProvider{
+			exec:        exec,
+			description: desc,
+			logger:      input.Logger,
+			interactive: input.IsInteractice,
+			scopedTo:    scopedTo,
+		}, nil
+	}
+}
+
+type externalIdentityProvider struct {
+	exec        *executor
+	description *Description
+	logger      *zap.SugaredLogger
+	interactive bool
+	scopedTo    string
+}
+
+func (p *externalIdentityProvider) Name() string {
+	return p.description.Name
+}
+
+// Authenticate will ask the external plugin to authenticate the user
+func (p *externalIdentityProvider) Authenticate(ctx context.Context, input *identity.AuthenticateInput) (*identity.AuthenticateOutput, error) {
+	p.logger.Infof("using external plugin %s for authentication", p.description.Name)
+
+	if err := resolveRequired(input.ConfigSet, p.description.ConfigurationItems, p.interactive); err != nil {
+		return nil, fmt.Errorf("resolving config: %w", err)
+	}
+
+	resp, err := p.exec.call(ctx, &Request{
+		Method:      MethodAuthenticate,
+		ScopedTo:    p.scopedTo,
+		Interactive: p.interactive,
+		Config:      configValues(input.ConfigSet),
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.Identity == nil {
+		return nil, ErrNoIdentity
+	}
+	if resp.Identity.Provider == "" {
+		resp.Identity.Provider = p.description.Name
+	}
+
+	return &identity.AuthenticateOutput{
+		Identity: &identityAdapter{identity: resp.Identity},
+	}, nil
+}
+
+// RestoreIdentity creates the identity returned by the external plugin from its JSON
+func (p *externalIdentityProvider) RestoreIdentity(data []byte) (identity.Identity, error) {
+	return restoreIdentity(data)
+}
+
+// toProtocolIdentity converts an identity so that it can be sent to an external plugin.
+// Only the details of token and external identities can be sent.
+func toProtocolIdentity(id identity.Identity) *Identity {
+	if id == nil {
+		return nil
+	}
+
+	switch typedID := id.(type) {
+	case *identityAdapter:
+		return typedID.identity
+	case *identity.TokenIdentity:
+		return &Identity{
+			Type:     typedID.Type(),
+			Name:     typedID.Name(),
+			Provider: typedID.IdentityProviderName(),
+			Token:    typedID.Token(),
+		}
+	default:
+		return &Identity{
+			Type:     id.Type(),
+			Name:     id.Name(),
+			Provider: id.IdentityProviderName(),
+		}
+	}
+}
+
+// restoreIdentity creates the identity returned by a plugin from its JSON
+func restoreIdentity(data []byte) (identity.Identity, error) {
+	id := &Identity{}
+	if err := json.Unmarshal(data, id); err != nil {
+		return nil, fmt.Errorf("unmarshalling identity: %w", err)
+	}
+
+	return &identityAdapter{identity: id}, nil
+}
+
+// identityAdapter adapts an identity returned by an external plugin to identity.Identity
+type identityAdapter struct {
+	identity *Identity
+}
+
+func (a *identityAdapter) Type() string {
+	return a.identity.Type
+}
+
+func (a *identityAdapter) Name() string {
+	return a.identity.Name
+}
+
+func (a *identityAdapter) IsExpired() bool {
+	if a.identity.ExpiresAt == nil {
+		return false
+	}
+
+	return time.Now().After(*a.identity.ExpiresAt)
+}
+
+// ExpiresAt returns when the identity expires, if the plugin returned the expiry
+func (a *identityAdapter) ExpiresAt() time.Time {
+	if a.identity.ExpiresAt == nil {
+		return time.Time{}
+	}
+
+	return *a.identity.ExpiresAt
+}
+
+func (a *identityAdapter) IdentityProviderName() string {
+	return a.identity.Provider
+}
+
+// Token returns the token of the identity, if the plugin returned one
+func (a *identityAdapter) Token() string {
+	return a.identity.Token
+}
+
+func (a *identityAdapter) MarshalJSON() ([]byte, error) {
+	return json.Marshal(a.identity)
+}
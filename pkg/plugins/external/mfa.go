@@ -0,0 +1,87 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+var (
+	ErrNoMFAMethods  = errors.New("external mfa plugin doesn't handle any mfa methods")
+	ErrNoMFAResponse = errors.New("external plugin didn't return a mfa response")
+)
+
+func newMFARegistrations(plugin *pluginExecutable, desc *Description) ([]*registration, error) {
+	if desc == nil {
+		return nil, ErrNoPluginTypes
+	}
+	if desc.Name == "" {
+		desc.Name = plugin.name
+	}
+	if desc.Name != plugin.name {
+		return nil, fmt.Errorf("plugin %s: %w", desc.Name, ErrNameMismatch)
+	}
+	if len(desc.MFAMethods) == 0 {
+		return nil, fmt.Errorf("plugin %s: %w", desc.Name, ErrNoMFAMethods)
+	}
+
+	methods := map[identity.MFAMethod]bool{}
+	for _, method := range desc.MFAMethods {
+		methods[identity.MFAMethod(method)] = true
+	}
+
+	return []*registration{{
+		path: plugin.path,
+		mfa: &externalMFAHandler{
+			name:    desc.Name,
+			exec:    &executor{path: plugin.path},
+			methods: methods,
+		},
+	}}, nil
+}
+
+// externalMFAHandler asks an external plugin to complete MFA challenges
+type externalMFAHandler struct {
+	name    string
+	exec    *executor
+	methods map[identity.MFAMethod]bool
+}
+
+func (h *externalMFAHandler) Supports(method identity.MFAMethod) bool {
+	return h.methods[method]
+}
+
+// HandleMFA will ask the external plugin to complete the challenge
+func (h *externalMFAHandler) HandleMFA(ctx context.Context, challenge *identity.MFAChallenge) (*identity.MFAResponse, error) {
+	resp, err := h.exec.call(ctx, &Request{
+		Method:       MethodHandleMFA,
+		Interactive:  challenge.Interactive,
+		MFAChallenge: challenge,
+	})
+	if err != nil {
+		return nil, err
+	}
+	if resp.MFAResponse == nil {
+		return nil, ErrNoMFAResponse
+	}
+
+	return resp.MFAResponse, nil
+}
@@ -0,0 +1,142 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"time"
+
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+const (
+	// ProtocolVersion is the version of the protocol used to talk to external plugins
+	ProtocolVersion = "kconnect.fidelity.github.com/plugin/v1"
+
+	// DiscoveryPluginPrefix is the prefix of the name of an executable that is a discovery plugin
+	DiscoveryPluginPrefix = "kconnect-discovery-"
+	// IdentityPluginPrefix is the prefix of the name of an executable that is an identity plugin
+	IdentityPluginPrefix = "kconnect-identity-"
+	// MFAPluginPrefix is the prefix of the name of an executable that is a MFA handler plugin
+	MFAPluginPrefix = "kconnect-mfa-"
+)
+
+// Method is the operation that a plugin is being asked to perform
+type Method string
+
+var (
+	// MethodDescribe asks the plugin to describe itself and its configuration items
+	MethodDescribe = Method("describe")
+	// MethodCheckPreReqs asks the plugin to check its pre-requisites
+	MethodCheckPreReqs = Method("checkPreReqs")
+	// MethodValidate asks the plugin to validate the configuration
+	MethodValidate = Method("validate")
+	// MethodResolve asks the plugin to resolve any configuration values it can
+	MethodResolve = Method("resolve")
+	// MethodAuthenticate asks an identity plugin to authenticate the user
+	MethodAuthenticate = Method("authenticate")
+	// MethodDiscover asks a discovery plugin to discover the clusters
+	MethodDiscover = Method("discover")
+	// MethodGetCluster asks a discovery plugin for the details of a single cluster
+	MethodGetCluster = Method("getCluster")
+	// MethodGetConfig asks a discovery plugin to generate the kubeconfig for a cluster
+	MethodGetConfig = Method("getConfig")
+	// MethodHandleMFA asks a MFA plugin to complete a MFA challenge
+	MethodHandleMFA = Method("handleMFA")
+)
+
+// Request is written as JSON to the stdin of the plugin
+type Request struct {
+	APIVersion  string            `json:"apiVersion"`
+	Method      Method            `json:"method"`
+	ScopedTo    string            `json:"scopedTo,omitempty"`
+	Interactive bool              `json:"interactive"`
+	Config      map[string]string `json:"config,omitempty"`
+	Identity    *Identity         `json:"identity,omitempty"`
+	ClusterID   string            `json:"clusterId,omitempty"`
+	Cluster     *Cluster          `json:"cluster,omitempty"`
+	Namespace   string            `json:"namespace,omitempty"`
+	// MFAChallenge is sent with handleMFA
+	MFAChallenge *identity.MFAChallenge `json:"mfaChallenge,omitempty"`
+}
+
+// Response is read as JSON from the stdout of the plugin. The plugin can write
+// any log or user facing messages to stderr.
+type Response struct {
+	APIVersion string `json:"apiVersion"`
+	// Error is set if the method failed
+	Error string `json:"error,omitempty"`
+
+	// Description is returned for describe
+	Description *Description `json:"description,omitempty"`
+	// Config is returned for resolve and contains the values that were resolved
+	Config map[string]string `json:"config,omitempty"`
+	// Identity is returned for authenticate
+	Identity *Identity `json:"identity,omitempty"`
+	// Clusters is returned for discover
+	Clusters []*Cluster `json:"clusters,omitempty"`
+	// Cluster is returned for getCluster
+	Cluster *Cluster `json:"cluster,omitempty"`
+	// Kubeconfig is returned for getConfig and is a kubeconfig in yaml or json
+	Kubeconfig string `json:"kubeconfig,omitempty"`
+	// ContextName is returned for getConfig and is the name of the context to use
+	ContextName string `json:"contextName,omitempty"`
+	// MFAResponse is returned for handleMFA
+	MFAResponse *identity.MFAResponse `json:"mfaResponse,omitempty"`
+}
+
+// Description describes a plugin
+type Description struct {
+	Name                       string               `json:"name"`
+	UsageExample               string               `json:"usageExample,omitempty"`
+	ConfigurationItems         []*ConfigurationItem `json:"configurationItems,omitempty"`
+	SupportedIdentityProviders []string             `json:"supportedIdentityProviders,omitempty"`
+	// Capabilities are the optional behaviours the plugin supports, e.g. supports-filtering
+	Capabilities []string `json:"capabilities,omitempty"`
+	// MFAMethods are the MFA methods a MFA plugin handles, e.g. totp, push or webauthn
+	MFAMethods []string `json:"mfaMethods,omitempty"`
+}
+
+// ConfigurationItem describes a configuration item of a plugin
+type ConfigurationItem struct {
+	Name          string   `json:"name"`
+	Type          string   `json:"type"`
+	Shorthand     string   `json:"shorthand,omitempty"`
+	Description   string   `json:"description,omitempty"`
+	Default       string   `json:"default,omitempty"`
+	Required      bool     `json:"required,omitempty"`
+	Sensitive     bool     `json:"sensitive,omitempty"`
+	Hidden        bool     `json:"hidden,omitempty"`
+	AllowedValues []string `json:"allowedValues,omitempty"`
+}
+
+// Identity is the identity of a user that has authenticated
+type Identity struct {
+	Type      string            `json:"type"`
+	Name      string            `json:"name"`
+	Provider  string            `json:"provider"`
+	Token     string            `json:"token,omitempty"`
+	ExpiresAt *time.Time        `json:"expiresAt,omitempty"`
+	Data      map[string]string `json:"data,omitempty"`
+}
+
+// Cluster is a cluster that has been discovered
+type Cluster struct {
+	ID                       string  `json:"id"`
+	Name                     string  `json:"name"`
+	ControlPlaneEndpoint     *string `json:"endpoint,omitempty"`
+	CertificateAuthorityData *string `json:"ca,omitempty"`
+}
@@ -0,0 +1,318 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package external
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+	"github.com/fidelity/kconnect/pkg/plugin/sdk"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+const describeTimeout = 10 * time.Second
+
+var ErrNameMismatch = errors.New("external plugin name doesn't match its executable name")
+
+// RegisterPlugins will find the external plugins on the PATH and register them. A plugin
+// that can't be described or that has the same name as an existing plugin is skipped
+// with a warning so that a broken plugin doesn't stop kconnect from working.
+func RegisterPlugins() {
+	RegisterPluginsFromPath(os.Getenv("PATH"), defaults.PluginsCachePath())
+}
+
+// RegisterPluginsFromPath will find the external plugins in the supplied list of
+// directories and register them. When the same plugin is in more than one of the
+// directories the first is used. The descriptions of the plugins are cached in the
+// cache file, so a plugin is only run to describe it when it changes, and gRPC plugins
+// are only started when they're used. An empty cache path disables the cache.
+func RegisterPluginsFromPath(path, cachePath string) {
+	cache := loadDescribeCache(cachePath)
+	plugins := findPlugins(path)
+
+	registrations := []*registration{}
+	for _, plugin := range plugins {
+		entry := cache.get(plugin)
+		if entry == nil {
+			var err error
+			if entry, err = describePlugin(plugin); err != nil {
+				zap.S().Warnw("skipping external plugin", "path", plugin.path, "error", err.Error())
+				continue
+			}
+			cache.set(plugin, entry)
+		}
+
+		pluginRegistrations, err := newRegistrations(plugin, entry)
+		if err != nil {
+			zap.S().Warnw("skipping external plugin", "path", plugin.path, "error", err.Error())
+			continue
+		}
+		registrations = append(registrations, pluginRegistrations...)
+	}
+	if err := cache.save(plugins); err != nil {
+		zap.S().Debugw("failed to save plugins cache", "error", err.Error())
+	}
+
+	// Identity plugins are registered first so that discovery plugins can use them
+	for _, reg := range registrations {
+		if reg.identity != nil || reg.mfa != nil {
+			reg.register()
+		}
+	}
+	for _, reg := range registrations {
+		if reg.discovery != nil {
+			reg.register()
+		}
+	}
+}
+
+// Cleanup will stop any long-running plugins that have been started
+func Cleanup() {
+	sdk.CleanupClients()
+}
+
+type pluginKind string
+
+var (
+	kindDiscovery = pluginKind(DiscoveryPluginPrefix)
+	kindIdentity  = pluginKind(IdentityPluginPrefix)
+	kindMFA       = pluginKind(MFAPluginPrefix)
+	kindGRPC      = pluginKind(sdk.PluginPrefix)
+)
+
+type pluginExecutable struct {
+	name string
+	path string
+	kind pluginKind
+}
+
+// registration is a discovery, identity or MFA plugin to register from an external plugin
+type registration struct {
+	path      string
+	identity  *registry.IdentityPluginRegistration
+	discovery *registry.DiscoveryPluginRegistration
+	mfa       *externalMFAHandler
+}
+
+func (r *registration) register() {
+	var name string
+	var err error
+	switch {
+	case r.mfa != nil:
+		name = r.mfa.name
+		identity.RegisterMFAHandler(name, r.mfa)
+	case r.discovery != nil:
+		name = r.discovery.Name
+		err = registerDiscovery(r.discovery)
+	default:
+		name = r.identity.Name
+		err = registry.RegisterIdentityPlugin(r.identity)
+	}
+	if err != nil {
+		zap.S().Warnw("skipping external plugin", "path", r.path, "name", name, "error", err.Error())
+		return
+	}
+
+	zap.S().Debugw("registered external plugin", "name", name, "path", r.path)
+}
+
+func registerDiscovery(reg *registry.DiscoveryPluginRegistration) error {
+	for _, idProviderName := range reg.SupportedIdentityProviders {
+		if _, err := registry.GetIdentityProviderRegistration(idProviderName); err != nil {
+			return fmt.Errorf("plugin %s identity provider %s: %w", reg.Name, idProviderName, err)
+		}
+	}
+
+	return registry.RegisterDiscoveryPlugin(reg)
+}
+
+func findPlugins(path string) []*pluginExecutable {
+	found := map[string]bool{}
+	plugins := []*pluginExecutable{}
+
+	for _, dir := range filepath.SplitList(path) {
+		if dir == "" {
+			continue
+		}
+		entries, err := os.ReadDir(dir)
+		if err != nil {
+			continue
+		}
+
+		for _, entry := range entries {
+			plugin := parsePluginExecutable(dir, entry)
+			if plugin == nil {
+				continue
+			}
+			key := string(plugin.kind) + plugin.name
+			if found[key] {
+				continue
+			}
+			found[key] = true
+			plugins = append(plugins, plugin)
+		}
+	}
+
+	return plugins
+}
+
+func parsePluginExecutable(dir string, entry os.DirEntry) *pluginExecutable {
+	if entry.IsDir() {
+		return nil
+	}
+
+	fileName := entry.Name()
+	name := fileName
+	if runtime.GOOS == "windows" {
+		name = strings.TrimSuffix(name, ".exe")
+	}
+
+	plugin := &pluginExecutable{
+		path: filepath.Join(dir, fileName),
+	}
+	for _, kind := range []pluginKind{kindDiscovery, kindIdentity, kindMFA, kindGRPC} {
+		if strings.HasPrefix(name, string(kind)) {
+			plugin.name = strings.TrimPrefix(name, string(kind))
+			plugin.kind = kind
+		}
+	}
+	if plugin.name == "" {
+		return nil
+	}
+
+	info, err := entry.Info()
+	if err != nil {
+		return nil
+	}
+	if runtime.GOOS != "windows" && info.Mode()&0111 == 0 {
+		return nil
+	}
+
+	return plugin
+}
+
+// describePlugin runs the plugin to get the descriptions of what it implements
+func describePlugin(plugin *pluginExecutable) (*cacheEntry, error) {
+	if plugin.kind == kindGRPC {
+		return describeGRPCPlugin(plugin)
+	}
+
+	exec := &executor{path: plugin.path}
+
+	ctx, cancel := context.WithTimeout(context.Background(), describeTimeout)
+	defer cancel()
+
+	resp, err := exec.call(ctx, &Request{Method: MethodDescribe})
+	if err != nil {
+		return nil, fmt.Errorf("describing plugin: %w", err)
+	}
+	desc := resp.Description
+	if desc == nil {
+		desc = &Description{}
+	}
+
+	switch plugin.kind {
+	case kindDiscovery:
+		return &cacheEntry{Discovery: desc}, nil
+	case kindMFA:
+		return &cacheEntry{MFA: desc}, nil
+	default:
+		return &cacheEntry{Identity: desc}, nil
+	}
+}
+
+// newRegistrations creates the registrations for the plugins described by the entry
+func newRegistrations(plugin *pluginExecutable, entry *cacheEntry) ([]*registration, error) {
+	switch plugin.kind {
+	case kindGRPC:
+		return newGRPCRegistrations(plugin, entry)
+	case kindMFA:
+		return newMFARegistrations(plugin, entry.MFA)
+	default:
+		return newExecRegistrations(plugin, entry)
+	}
+}
+
+func newExecRegistrations(plugin *pluginExecutable, entry *cacheEntry) ([]*registration, error) {
+	exec := &executor{path: plugin.path}
+
+	desc := entry.Identity
+	if plugin.kind == kindDiscovery {
+		desc = entry.Discovery
+	}
+	if desc == nil {
+		return nil, ErrNoPluginTypes
+	}
+
+	pluginRegistration, err := newPluginRegistration(plugin, desc)
+	if err != nil {
+		return nil, err
+	}
+
+	reg := &registration{path: plugin.path}
+	if plugin.kind == kindDiscovery {
+		reg.discovery = &registry.DiscoveryPluginRegistration{
+			PluginRegistration:         *pluginRegistration,
+			SupportedIdentityProviders: desc.SupportedIdentityProviders,
+			CreateFunc:                 newDiscoveryProvider(exec, desc),
+		}
+	} else {
+		reg.identity = &registry.IdentityPluginRegistration{
+			PluginRegistration: *pluginRegistration,
+			CreateFunc:         newIdentityProvider(exec, desc),
+		}
+	}
+
+	return []*registration{reg}, nil
+}
+
+func newPluginRegistration(plugin *pluginExecutable, desc *Description) (*registry.PluginRegistration, error) {
+	if desc.Name == "" {
+		desc.Name = plugin.name
+	}
+	if desc.Name != plugin.name {
+		return nil, fmt.Errorf("plugin %s: %w", desc.Name, ErrNameMismatch)
+	}
+
+	// Check the configuration items are valid upfront
+	if _, err := configurationItemsFunc(desc.ConfigurationItems)(""); err != nil {
+		return nil, fmt.Errorf("plugin %s configuration items: %w", desc.Name, err)
+	}
+
+	capabilities := []registry.Capability{}
+	for _, capability := range desc.Capabilities {
+		capabilities = append(capabilities, registry.Capability(capability))
+	}
+
+	return &registry.PluginRegistration{
+		Name:                   desc.Name,
+		UsageExample:           desc.UsageExample,
+		ConfigurationItemsFunc: configurationItemsFunc(desc.ConfigurationItems),
+		Capabilities:           capabilities,
+		Path:                   plugin.path,
+	}, nil
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ambient
+
+import (
+	"context"
+	"net/http"
+	"os"
+	"time"
+)
+
+const (
+	imdsTokenURL = "http://169.254.169.254/latest/api/token"
+	imdsTimeout  = 250 * time.Millisecond
+)
+
+// explicitCredentialEnvVars mean the user has chosen credentials and so the ambient
+// credentials shouldn't be used
+var explicitCredentialEnvVars = []string{
+	"AWS_PROFILE",
+	"AWS_ACCESS_KEY_ID",
+}
+
+// Detect will check if kconnect is running on AWS compute with ambient credentials. This
+// checks for a web identity token (IRSA) and then the instance metadata service (IMDS).
+func Detect(scopeTo string) (string, bool) {
+	for _, envVar := range explicitCredentialEnvVars {
+		if os.Getenv(envVar) != "" {
+			return "", false
+		}
+	}
+
+	if os.Getenv(webIdentityTokenFileEnvVar) != "" {
+		return webIdentityTokenFileEnvVar + " is set", true
+	}
+
+	if hasIMDS() {
+		return "running on EC2 with instance metadata available", true
+	}
+
+	return "", false
+}
+
+func hasIMDS() bool {
+	ctx, cancel := context.WithTimeout(context.Background(), imdsTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPut, imdsTokenURL, nil)
+	if err != nil {
+		return false
+	}
+	req.Header.Set("X-aws-ec2-metadata-token-ttl-seconds", "60")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return false
+	}
+	defer resp.Body.Close()
+
+	return resp.StatusCode == http.StatusOK
+}
@@ -0,0 +1,190 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package ambient
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/ec2rolecreds"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/aws/aws-sdk-go-v2/feature/ec2/imds"
+	"go.uber.org/zap"
+
+	kaws "github.com/fidelity/kconnect/pkg/aws"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/provider"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+const (
+	ProviderName = "aws-ambient"
+
+	webIdentityTokenFileEnvVar = "AWS_WEB_IDENTITY_TOKEN_FILE"
+	roleARNEnvVar              = "AWS_ROLE_ARN"
+	roleSessionNameEnvVar      = "AWS_ROLE_SESSION_NAME"
+	defaultRoleSessionName     = "kconnect"
+)
+
+var (
+	ErrRoleARNRequired = errors.New("AWS_ROLE_ARN is required when using a web identity token")
+)
+
+func init() {
+	if err := registry.RegisterIdentityPlugin(&registry.IdentityPluginRegistration{
+		PluginRegistration: registry.PluginRegistration{
+			Name:                   ProviderName,
+			UsageExample:           "",
+			ConfigurationItemsFunc: ConfigurationItems,
+		},
+		CreateFunc: New,
+		DetectFunc: Detect,
+	}); err != nil {
+		zap.S().Fatalw("Failed to register AWS ambient identity plugin", "error", err)
+	}
+}
+
+// New will create a new AWS ambient identity provider. This uses the credentials of
+// the AWS compute that kconnect is running on, either a web identity token (IRSA)
+// or the EC2 instance profile.
+func New(input *provider.PluginCreationInput) (identity.Provider, error) {
+	return &ambientIdentityProvider{
+		logger: input.Logger,
+	}, nil
+}
+
+type ambientIdentityProvider struct {
+	logger *zap.SugaredLogger
+}
+
+type providerConfig struct {
+	Region    string `json:"region"`
+	Partition string `json:"partition"`
+	kaws.Endpoints
+}
+
+func (p *ambientIdentityProvider) Name() string {
+	return ProviderName
+}
+
+// Authenticate will get the ambient credentials and return details of the identity
+func (p *ambientIdentityProvider) Authenticate(ctx context.Context, input *identity.AuthenticateInput) (*identity.AuthenticateOutput, error) {
+	p.logger.Info("using ambient aws credentials for authentication")
+
+	cfg := &providerConfig{}
+	if err := config.Unmarshall(input.ConfigSet, cfg); err != nil {
+		return nil, fmt.Errorf("unmarshalling config into providerConfig: %w", err)
+	}
+
+	region, err := p.resolveRegion(ctx, kaws.PrimaryRegion(cfg.Region))
+	if err != nil {
+		return nil, err
+	}
+	if err := kaws.ValidateRegion(cfg.Partition, region); err != nil {
+		return nil, fmt.Errorf("validating region: %w", err)
+	}
+
+	awsCfg, err := kaws.NewConfig(ctx, region, "", "", "", "", &cfg.Endpoints)
+	if err != nil {
+		return nil, fmt.Errorf("creating aws config: %w", err)
+	}
+
+	creds, err := p.ambientCredentials(awsCfg)
+	if err != nil {
+		return nil, err
+	}
+	value, err := creds.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting ambient credentials: %w", err)
+	}
+	p.logger.Debugw("found ambient aws credentials", "provider", value.Source)
+
+	id := &kaws.Identity{
+		AWSAccessKey:    value.AccessKeyID,
+		AWSSecretKey:    value.SecretAccessKey,
+		AWSSessionToken: value.SessionToken,
+		Region:          region,
+		IDProviderName:  ProviderName,
+	}
+	if value.CanExpire {
+		id.Expires = value.Expires
+	}
+
+	return &identity.AuthenticateOutput{
+		Identity: id,
+	}, nil
+}
+
+// ambientCredentials returns the web identity credentials if a token has been supplied
+// (e.g. by IRSA) and otherwise the EC2 instance profile credentials
+func (p *ambientIdentityProvider) ambientCredentials(awsCfg aws.Config) (aws.CredentialsProvider, error) {
+	if tokenFile := os.Getenv(webIdentityTokenFileEnvVar); tokenFile != "" {
+		roleARN := os.Getenv(roleARNEnvVar)
+		if roleARN == "" {
+			return nil, ErrRoleARNRequired
+		}
+		sessionName := os.Getenv(roleSessionNameEnvVar)
+		if sessionName == "" {
+			sessionName = defaultRoleSessionName
+		}
+		p.logger.Debugw("using web identity token", "role", roleARN, "token-file", tokenFile)
+
+		return stscreds.NewWebIdentityRoleProvider(kaws.NewSTSClient(awsCfg), roleARN, stscreds.IdentityTokenFile(tokenFile), func(o *stscreds.WebIdentityRoleOptions) {
+			o.RoleSessionName = sessionName
+		}), nil
+	}
+
+	p.logger.Debug("using ec2 instance profile")
+	return ec2rolecreds.New(), nil
+}
+
+// resolveRegion will use the supplied region, then the region from the environment
+// and finally the region of the EC2 instance
+func (p *ambientIdentityProvider) resolveRegion(ctx context.Context, region string) (string, error) {
+	if region != "" {
+		return region, nil
+	}
+	for _, envVar := range []string{"AWS_REGION", "AWS_DEFAULT_REGION"} {
+		if envRegion := os.Getenv(envVar); envRegion != "" {
+			return envRegion, nil
+		}
+	}
+
+	output, err := imds.New(imds.Options{}).GetRegion(ctx, &imds.GetRegionInput{})
+	if err != nil {
+		return "", fmt.Errorf("getting region from instance metadata: %w", kaws.ErrNoRegion)
+	}
+	p.logger.Debugw("using region of the ec2 instance", "region", output.Region)
+
+	return output.Region, nil
+}
+
+// ConfigurationItems will return the configuration items for the intentity plugin based
+// of the cluster provider that its being used in conjunction with
+func ConfigurationItems(scopeTo string) (config.ConfigurationSet, error) {
+	cs := config.NewConfigurationSet()
+
+	kaws.AddPartitionConfig(cs)
+	kaws.AddEndpointConfig(cs)
+	cs.String(kaws.RegionConfigItem, "", "AWS region to connect to. Defaults to the region of the environment or EC2 instance") //nolint: errcheck
+
+	return cs, nil
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	awsgo "github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/credentials/stscreds"
+	"github.com/versent/saml2aws/pkg/awsconfig"
+
+	kaws "github.com/fidelity/kconnect/pkg/aws"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+const roleSessionName = "kconnect"
+
+// assumeRole will assume the role using the credentials from the initial authentication.
+// The credentials for the role are saved to a kconnect profile so that they are used
+// by the kubeconfig.
+func (p *iamIdentityProvider) assumeRole(ctx context.Context, awsCfg awsgo.Config, cfg *providerConfig, region string) (*kaws.Identity, error) {
+	p.logger.Debugw("assuming aws role", "role", cfg.AssumeRoleARN, "mfa", cfg.MFASerial != "")
+
+	roleCreds := stscreds.NewAssumeRoleProvider(kaws.NewSTSClient(awsCfg), cfg.AssumeRoleARN, func(options *stscreds.AssumeRoleOptions) {
+		options.RoleSessionName = roleSessionName
+		if cfg.ExternalID != "" {
+			options.ExternalID = awsgo.String(cfg.ExternalID)
+		}
+		if cfg.MFASerial != "" {
+			options.SerialNumber = awsgo.String(cfg.MFASerial)
+			options.TokenProvider = p.mfaTokenProvider(ctx, cfg.MFASerial, cfg.MFAToken)
+		}
+	})
+	creds, err := roleCreds.Retrieve(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("getting role credentials: %w", err)
+	}
+
+	identifier, err := kaws.CreateIDFromCreds(&awsconfig.AWSCredentials{PrincipalARN: cfg.AssumeRoleARN})
+	if err != nil {
+		return nil, fmt.Errorf("creating identifier for role: %w", err)
+	}
+	profileName := fmt.Sprintf("kconnect-%s", identifier)
+
+	id := &kaws.Identity{
+		ProfileName:     profileName,
+		AWSAccessKey:    creds.AccessKeyID,
+		AWSSecretKey:    creds.SecretAccessKey,
+		AWSSessionToken: creds.SessionToken,
+		PrincipalARN:    cfg.AssumeRoleARN,
+		Expires:         creds.Expires,
+		Region:          region,
+		IDProviderName:  ProviderName,
+	}
+
+	store, err := kaws.NewIdentityStore(profileName, ProviderName)
+	if err != nil {
+		return nil, fmt.Errorf("creating identity store: %w", err)
+	}
+	if err := store.Save(id); err != nil {
+		return nil, fmt.Errorf("saving role credentials to profile %s: %w", profileName, err)
+	}
+	p.logger.Debugw("saved role credentials", "profile", profileName)
+
+	return id, nil
+}
+
+// mfaTokenProvider returns the supplied token or gets the token from the MFA handlers
+func (p *iamIdentityProvider) mfaTokenProvider(ctx context.Context, serial, token string) func() (string, error) {
+	return func() (string, error) {
+		if token != "" {
+			return token, nil
+		}
+
+		resp, err := identity.HandleMFA(ctx, &identity.MFAChallenge{
+			Method:      identity.MFAMethodTOTP,
+			Provider:    ProviderName,
+			Device:      serial,
+			Interactive: p.interactive,
+		})
+		if errors.Is(err, identity.ErrMFAInputRequired) {
+			return "", ErrMFATokenRequired
+		}
+		if err != nil {
+			return "", err
+		}
+
+		return resp.Code, nil
+	}
+}
@@ -0,0 +1,59 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package iam
+
+import (
+	"os"
+	"path/filepath"
+)
+
+var credentialEnvVars = []string{
+	"AWS_PROFILE",
+	"AWS_ACCESS_KEY_ID",
+	"AWS_CONTAINER_CREDENTIALS_RELATIVE_URI",
+	"AWS_CONTAINER_CREDENTIALS_FULL_URI",
+}
+
+// Detect will check if there are AWS credentials available in the environment. This
+// checks the environment variables and then the AWS SSO cache. Ambient credentials
+// of AWS compute are detected by the aws-ambient provider.
+func Detect(scopeTo string) (string, bool) {
+	for _, envVar := range credentialEnvVars {
+		if os.Getenv(envVar) != "" {
+			return envVar + " is set", true
+		}
+	}
+
+	if hasSSOCache() {
+		return "found cached AWS SSO credentials", true
+	}
+
+	return "", false
+}
+
+func hasSSOCache() bool {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return false
+	}
+	matches, err := filepath.Glob(filepath.Join(home, ".aws", "sso", "cache", "*.json"))
+	if err != nil {
+		return false
+	}
+
+	return len(matches) > 0
+}
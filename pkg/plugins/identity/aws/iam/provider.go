@@ -22,6 +22,7 @@ import (
 
 	kaws "github.com/fidelity/kconnect/pkg/aws"
 	"github.com/fidelity/kconnect/pkg/config"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
 	"github.com/fidelity/kconnect/pkg/provider"
 	"github.com/fidelity/kconnect/pkg/provider/identity"
 	"github.com/fidelity/kconnect/pkg/provider/registry"
@@ -35,6 +36,8 @@ var (
 	ErrProfileWithAccessKey    = errors.New("cannot use profile with access-key")
 	ErrProfileWithSecretKey    = errors.New("cannot use profile with secret-key")
 	ErrAccessAndSecretRequired = errors.New("access-key and secret-key are both required")
+	ErrMFAWithoutRole          = errors.New("mfa-serial and external-id can only be used with assume-role-arn")
+	ErrMFATokenRequired        = kerrors.WithCode(kerrors.CodeMFARequired, errors.New("mfa-token is required when running non-interactively"))
 )
 
 func init() {
@@ -44,7 +47,9 @@ func init() {
 			UsageExample:           "",
 			ConfigurationItemsFunc: ConfigurationItems,
 		},
-		CreateFunc: New,
+		CreateFunc:           New,
+		DetectFunc:           Detect,
+		IdentitiesConfigItem: kaws.AssumeRoleConfigItem,
 	}); err != nil {
 		zap.S().Fatalw("Failed to register AWS IAM identity plugin", "error", err)
 	}
@@ -64,12 +69,19 @@ type iamIdentityProvider struct {
 }
 
 type providerConfig struct {
+	AWSProfile   string `json:"aws-profile"`
 	Profile      string `json:"profile"`
 	AccessKey    string `json:"access-key"`
 	SecretKey    string `json:"secret-key"`
 	SessionToken string `json:"session-token"`
 	Region       string `json:"region"`
 	Partition    string `json:"partition"`
+	kaws.Endpoints
+
+	AssumeRoleARN string `json:"assume-role-arn"`
+	ExternalID    string `json:"external-id"`
+	MFASerial     string `json:"mfa-serial"`
+	MFAToken      string `json:"mfa-token"`
 }
 
 func (p *iamIdentityProvider) Name() string {
@@ -85,27 +97,54 @@ func (p *iamIdentityProvider) Authenticate(ctx context.Context, input *identity.
 		return nil, fmt.Errorf("unmarshalling config into providerConfig: %w", err)
 	}
 
+	if cfg.AWSProfile != "" {
+		cfg.Profile = cfg.AWSProfile
+	}
 	if err := p.validateConfig(cfg); err != nil {
 		return nil, err
 	}
 
-	sess, err := kaws.NewSession(cfg.Region, cfg.Profile, cfg.AccessKey, cfg.SecretKey, cfg.SessionToken)
+	// Without a region the config uses the default region of the partition so
+	// that the cluster provider can ask which regions to use
+	region := kaws.PrimaryRegion(cfg.Region)
+	awsCfg, err := kaws.NewConfig(ctx, region, cfg.Profile, cfg.AccessKey, cfg.SecretKey, cfg.SessionToken, &cfg.Endpoints)
+	if errors.Is(err, kaws.ErrNoRegion) {
+		p.logger.Debugw("no region supplied, using default region of the partition", "partition", cfg.Partition)
+		awsCfg, err = kaws.NewConfig(ctx, kaws.PartitionDefaultRegion(cfg.Partition), cfg.Profile, cfg.AccessKey, cfg.SecretKey, cfg.SessionToken, &cfg.Endpoints)
+		if err == nil {
+			region = ""
+		}
+	} else if err == nil {
+		region = awsCfg.Region
+	}
 	if err != nil {
-		return nil, fmt.Errorf("creating aws session: %w", err)
+		return nil, fmt.Errorf("creating aws config: %w", err)
 	}
 
-	creds, err := sess.Config.Credentials.Get()
+	creds, err := awsCfg.Credentials.Retrieve(ctx)
 	if err != nil {
 		return nil, fmt.Errorf("getting credentials: %w", err)
 	}
-	p.logger.Debugw("found aws iam credentials", "provider", creds.ProviderName)
+	p.logger.Debugw("found aws iam credentials", "provider", creds.Source)
+
+	if cfg.AssumeRoleARN != "" {
+		id, err := p.assumeRole(ctx, awsCfg, cfg, region)
+		if err != nil {
+			return nil, fmt.Errorf("assuming role %s: %w", cfg.AssumeRoleARN, err)
+		}
+
+		return &identity.AuthenticateOutput{
+			Identity: id,
+		}, nil
+	}
 
 	id := &kaws.Identity{
 		ProfileName:     cfg.Profile,
 		AWSAccessKey:    creds.AccessKeyID,
 		AWSSecretKey:    creds.SecretAccessKey,
 		AWSSessionToken: creds.SessionToken,
-		Region:          cfg.Region,
+		Region:          region,
+		IDProviderName:  ProviderName,
 	}
 
 	return &identity.AuthenticateOutput{
@@ -113,6 +152,11 @@ func (p *iamIdentityProvider) Authenticate(ctx context.Context, input *identity.
 	}, nil
 }
 
+// RestoreIdentity creates the AWS identity from its JSON
+func (p *iamIdentityProvider) RestoreIdentity(data []byte) (identity.Identity, error) {
+	return kaws.RestoreIdentity(data)
+}
+
 func (p *iamIdentityProvider) validateConfig(cfg *providerConfig) error {
 	if cfg.Profile != "" && cfg.AccessKey != "" {
 		return ErrProfileWithAccessKey
@@ -126,6 +170,12 @@ func (p *iamIdentityProvider) validateConfig(cfg *providerConfig) error {
 	if cfg.AccessKey == "" && cfg.SecretKey != "" {
 		return ErrAccessAndSecretRequired
 	}
+	if cfg.AssumeRoleARN == "" && (cfg.MFASerial != "" || cfg.ExternalID != "") {
+		return ErrMFAWithoutRole
+	}
+	if err := kaws.ValidateRegion(cfg.Partition, cfg.Region); err != nil {
+		return fmt.Errorf("validating region: %w", err)
+	}
 
 	return nil
 }
@@ -138,6 +188,8 @@ func ConfigurationItems(scopeTo string) (config.ConfigurationSet, error) {
 	kaws.AddRegionConfig(cs)
 	kaws.AddPartitionConfig(cs)
 	kaws.AddIAMConfigs(cs)
+	kaws.AddEndpointConfig(cs)
+	kaws.AddAssumeRoleConfig(cs)
 
 	return cs, nil
 }
@@ -35,7 +35,9 @@ func (p *aadIdentityProvider) resolveConfig(cfg config.ConfigurationSet) error {
 	if err := prompt.InputAndSet(cfg, defaults.UsernameConfigItem, "Username:", true); err != nil {
 		return fmt.Errorf("resolving %s: %w", defaults.UsernameConfigItem, err)
 	}
-	if err := prompt.InputSensitiveAndSet(cfg, defaults.PasswordConfigItem, "Password:", true); err != nil {
+	if p.passwordCached(cfg) {
+		p.logger.Debug("skipping password as there is a cached refresh token")
+	} else if err := prompt.InputSensitiveAndSet(cfg, defaults.PasswordConfigItem, "Password:", true); err != nil {
 		return fmt.Errorf("resolving %s: %w", defaults.PasswordConfigItem, err)
 	}
 	if err := prompt.InputAndSet(cfg, azure.TenantIDConfigItem, "Enter the Azure tenant ID", true); err != nil {
@@ -51,6 +53,21 @@ func (p *aadIdentityProvider) resolveConfig(cfg config.ConfigurationSet) error {
 	return nil
 }
 
+// passwordCached returns true if the password isn't needed because the token cache has
+// a refresh token for the user
+func (p *aadIdentityProvider) passwordCached(cfg config.ConfigurationSet) bool {
+	if cfg.ExistsWithValue(defaults.PasswordConfigItem) || !cfg.ExistsWithValue(azure.TokenCacheConfigItem) {
+		return false
+	}
+
+	return hasCachedRefreshToken(
+		cfg.ValueString(azure.TokenCacheConfigItem),
+		cfg.ValueString(defaults.UsernameConfigItem),
+		identity.AADHost(cfg.ValueString(azure.AADHostConfigItem)),
+		cfg.ValueString(azure.ClientIDConfigItem),
+	)
+}
+
 func aadHostOptions() (map[string]string, error) {
 	return map[string]string{
 		"Worldwide (recommended)": string(identity.AADHostWorldwide),
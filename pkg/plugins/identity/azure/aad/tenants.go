@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package aad
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	azclient "github.com/fidelity/kconnect/pkg/azure/client"
+	"github.com/fidelity/kconnect/pkg/azure/cloud"
+	"github.com/fidelity/kconnect/pkg/azure/identity"
+)
+
+// listTenants returns the ids of the tenants the user can access other than the tenant
+// they authenticated to. Subscriptions delegated to the tenant using Azure Lighthouse
+// are already visible from it, the other tenants are where the user is a guest.
+func (p *aadIdentityProvider) listTenants(ctx context.Context, cfg *aadConfig, id *identity.ActiveDirectoryIdentity) ([]string, error) {
+	env, err := cfg.Config.Environment()
+	if err != nil {
+		return nil, fmt.Errorf("getting azure environment %s: %w", cfg.Name, err)
+	}
+
+	client, err := azclient.NewTenantsClient(env, id.TokenCredential(cloud.ResourceManagerAudience(env)))
+	if err != nil {
+		return nil, fmt.Errorf("creating tenants client: %w", err)
+	}
+
+	tenants := []string{}
+	pager := client.NewListPager(nil)
+	for pager.More() {
+		page, err := pager.NextPage(ctx)
+		if err != nil {
+			return nil, fmt.Errorf("getting tenant list: %w", err)
+		}
+		for _, tenant := range page.Value {
+			if tenant.TenantID != nil && !strings.EqualFold(*tenant.TenantID, cfg.TenantID) {
+				tenants = append(tenants, *tenant.TenantID)
+			}
+		}
+	}
+	p.logger.Debugw("found additional tenants", "tenants", tenants)
+
+	return tenants, nil
+}
+
+func splitTenants(tenants string) []string {
+	tenantIDs := []string{}
+	for _, tenant := range strings.Split(tenants, ",") {
+		tenant = strings.TrimSpace(tenant)
+		if tenant != "" {
+			tenantIDs = append(tenantIDs, tenant)
+		}
+	}
+
+	return tenantIDs
+}
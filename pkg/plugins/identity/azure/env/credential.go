@@ -0,0 +1,169 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"os"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	"golang.org/x/text/encoding/unicode"
+	"golang.org/x/text/transform"
+)
+
+// CredentialType is the type of credential used to authenticate
+type CredentialType string
+
+var (
+	// CredentialTypeAuto uses workload identity if its token file is set, a service principal or
+	// user if their environment variables are set and otherwise a managed identity
+	CredentialTypeAuto = CredentialType("auto")
+	// CredentialTypeEnvironment uses the service principal or user set by the environment variables
+	CredentialTypeEnvironment = CredentialType("environment")
+	// CredentialTypeManagedIdentity uses the managed identity of the VM, App Service or container
+	CredentialTypeManagedIdentity = CredentialType("managed-identity")
+	// CredentialTypeWorkloadIdentity uses the federated token of Azure workload identity on Kubernetes
+	CredentialTypeWorkloadIdentity = CredentialType("workload-identity")
+)
+
+var (
+	ErrAuthFileRequired    = errors.New("the AZURE_AUTH_LOCATION environment variable must be set to use file based authorization")
+	ErrAuthFileCredentials = errors.New("the auth file must contain the client id and either a client secret or certificate")
+	ErrUnknownCredential   = errors.New("unknown credential type")
+)
+
+const (
+	authLocationEnvVar       = "AZURE_AUTH_LOCATION"
+	clientIDEnvVar           = "AZURE_CLIENT_ID"
+	federatedTokenFileEnvVar = "AZURE_FEDERATED_TOKEN_FILE"
+	identityEndpointEnvVar   = "IDENTITY_ENDPOINT"
+	msiEndpointEnvVar        = "MSI_ENDPOINT"
+	clientSecretEnvVar       = "AZURE_CLIENT_SECRET"
+	clientCertificateEnvVar  = "AZURE_CLIENT_CERTIFICATE_PATH"
+	usernameEnvVar           = "AZURE_USERNAME"
+	environmentNameEnvVar    = "AZURE_ENVIRONMENT"
+)
+
+func credentialTypeValues() []string {
+	return []string{
+		string(CredentialTypeAuto),
+		string(CredentialTypeEnvironment),
+		string(CredentialTypeManagedIdentity),
+		string(CredentialTypeWorkloadIdentity),
+	}
+}
+
+// resolveCredentialType returns the type of credential to use when the type is auto
+func resolveCredentialType(credentialType CredentialType) CredentialType {
+	if credentialType != CredentialTypeAuto && credentialType != "" {
+		return credentialType
+	}
+
+	switch {
+	case os.Getenv(federatedTokenFileEnvVar) != "":
+		return CredentialTypeWorkloadIdentity
+	case os.Getenv(clientSecretEnvVar) != "", os.Getenv(clientCertificateEnvVar) != "", os.Getenv(usernameEnvVar) != "":
+		return CredentialTypeEnvironment
+	default:
+		return CredentialTypeManagedIdentity
+	}
+}
+
+// authFile is the file created by az ad sp create-for-rbac --sdk-auth
+type authFile struct {
+	ClientID                  string `json:"clientId"`
+	ClientSecret              string `json:"clientSecret"`
+	ClientCertificate         string `json:"clientCertificate"`
+	ClientCertificatePassword string `json:"clientCertificatePassword"`
+	TenantID                  string `json:"tenantId"`
+	ActiveDirectoryEndpoint   string `json:"activeDirectoryEndpointUrl"`
+}
+
+// credentialFromFile creates a credential for the service principal in the file set by
+// AZURE_AUTH_LOCATION. The Azure AD endpoint in the file is used if there is one.
+func credentialFromFile(opts azcore.ClientOptions) (azcore.TokenCredential, error) {
+	path := os.Getenv(authLocationEnvVar)
+	if path == "" {
+		return nil, ErrAuthFileRequired
+	}
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading auth file %s: %w", path, err)
+	}
+	// The file is UTF-16 encoded if it was written by PowerShell
+	data, _, err = transform.Bytes(unicode.BOMOverride(unicode.UTF8.NewDecoder()), data)
+	if err != nil {
+		return nil, fmt.Errorf("decoding auth file %s: %w", path, err)
+	}
+
+	file := &authFile{}
+	if err := json.Unmarshal(data, file); err != nil {
+		return nil, fmt.Errorf("unmarshalling auth file %s: %w", path, err)
+	}
+	if file.ActiveDirectoryEndpoint != "" {
+		opts.Cloud.ActiveDirectoryAuthorityHost = file.ActiveDirectoryEndpoint
+	}
+
+	switch {
+	case file.ClientID != "" && file.ClientSecret != "":
+		return azidentity.NewClientSecretCredential(file.TenantID, file.ClientID, file.ClientSecret, &azidentity.ClientSecretCredentialOptions{ClientOptions: opts})
+	case file.ClientID != "" && file.ClientCertificate != "":
+		certData, err := ioutil.ReadFile(file.ClientCertificate)
+		if err != nil {
+			return nil, fmt.Errorf("reading client certificate %s: %w", file.ClientCertificate, err)
+		}
+		certs, key, err := azidentity.ParseCertificates(certData, []byte(file.ClientCertificatePassword))
+		if err != nil {
+			return nil, fmt.Errorf("parsing client certificate %s: %w", file.ClientCertificate, err)
+		}
+		return azidentity.NewClientCertificateCredential(file.TenantID, file.ClientID, certs, key, &azidentity.ClientCertificateCredentialOptions{ClientOptions: opts})
+	default:
+		return nil, ErrAuthFileCredentials
+	}
+}
+
+// credentialFromEnvironment creates a credential of the type from the environment variables
+func (p *envIdentityProvider) credentialFromEnvironment(cfg *providerConfig, opts azcore.ClientOptions) (azcore.TokenCredential, error) {
+	credentialType := resolveCredentialType(cfg.CredentialType)
+	p.logger.Debugw("creating azure credential", "type", credentialType)
+
+	switch credentialType {
+	case CredentialTypeEnvironment:
+		return azidentity.NewEnvironmentCredential(&azidentity.EnvironmentCredentialOptions{ClientOptions: opts})
+	case CredentialTypeWorkloadIdentity:
+		return azidentity.NewWorkloadIdentityCredential(&azidentity.WorkloadIdentityCredentialOptions{ClientOptions: opts})
+	case CredentialTypeManagedIdentity:
+		miOpts := &azidentity.ManagedIdentityCredentialOptions{ClientOptions: opts}
+		clientID := cfg.ManagedIdentityClientID
+		if clientID == "" {
+			clientID = os.Getenv(clientIDEnvVar)
+		}
+		if clientID != "" {
+			p.logger.Debugw("using user-assigned managed identity", "client-id", clientID)
+			miOpts.ID = azidentity.ClientID(clientID)
+		} else {
+			p.logger.Debug("using the system-assigned managed identity")
+		}
+		return azidentity.NewManagedIdentityCredential(miOpts)
+	default:
+		return nil, fmt.Errorf("creating credential %s: %w", credentialType, ErrUnknownCredential)
+	}
+}
@@ -0,0 +1,132 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package env
+
+import (
+	"errors"
+	"io/ioutil"
+	"path/filepath"
+	"testing"
+	"unicode/utf16"
+
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
+	"github.com/Azure/azure-sdk-for-go/sdk/azidentity"
+	. "github.com/onsi/gomega"
+)
+
+func TestResolveCredentialType(t *testing.T) {
+	testCases := []struct {
+		name     string
+		selected CredentialType
+		env      map[string]string
+		expected CredentialType
+	}{
+		{
+			name:     "chosen type is used",
+			selected: CredentialTypeManagedIdentity,
+			env:      map[string]string{federatedTokenFileEnvVar: "/var/run/token"},
+			expected: CredentialTypeManagedIdentity,
+		},
+		{
+			name:     "workload identity token file",
+			selected: CredentialTypeAuto,
+			env:      map[string]string{federatedTokenFileEnvVar: "/var/run/token", clientSecretEnvVar: "secret"},
+			expected: CredentialTypeWorkloadIdentity,
+		},
+		{
+			name:     "client secret",
+			selected: CredentialTypeAuto,
+			env:      map[string]string{clientSecretEnvVar: "secret"},
+			expected: CredentialTypeEnvironment,
+		},
+		{
+			name:     "managed identity when nothing is set",
+			selected: "",
+			expected: CredentialTypeManagedIdentity,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			for _, name := range []string{federatedTokenFileEnvVar, clientSecretEnvVar, clientCertificateEnvVar, usernameEnvVar} {
+				t.Setenv(name, tc.env[name]) //nolint:scopelint
+			}
+
+			g.Expect(resolveCredentialType(tc.selected)).To(Equal(tc.expected)) //nolint:scopelint
+		})
+	}
+}
+
+func TestCredentialFromFile(t *testing.T) {
+	secretFile := `{"clientId": "76849", "clientSecret": "supersecret", "tenantId": "123455", "activeDirectoryEndpointUrl": "https://login.microsoftonline.com"}`
+
+	testCases := []struct {
+		name      string
+		content   []byte
+		expectErr error
+	}{
+		{
+			name:    "client secret",
+			content: []byte(secretFile),
+		},
+		{
+			name:    "utf-16 written by powershell",
+			content: utf16File(secretFile),
+		},
+		{
+			name:      "no credentials",
+			content:   []byte(`{"clientId": "76849", "tenantId": "123455"}`),
+			expectErr: ErrAuthFileCredentials,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+			path := filepath.Join(t.TempDir(), "auth.json")
+			g.Expect(ioutil.WriteFile(path, tc.content, 0600)).To(Succeed()) //nolint:scopelint
+			t.Setenv(authLocationEnvVar, path)
+
+			credential, err := credentialFromFile(azcore.ClientOptions{})
+			if tc.expectErr != nil { //nolint:scopelint
+				g.Expect(errors.Is(err, tc.expectErr)).To(BeTrue()) //nolint:scopelint
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(credential).To(BeAssignableToTypeOf(&azidentity.ClientSecretCredential{}))
+		})
+	}
+}
+
+func TestCredentialFromFileNotSet(t *testing.T) {
+	g := NewWithT(t)
+	t.Setenv(authLocationEnvVar, "")
+
+	_, err := credentialFromFile(azcore.ClientOptions{})
+	g.Expect(errors.Is(err, ErrAuthFileRequired)).To(BeTrue())
+}
+
+// utf16File encodes the content as little endian UTF-16 with a byte order mark
+func utf16File(content string) []byte {
+	data := []byte{0xff, 0xfe}
+	for _, unit := range utf16.Encode([]rune(content)) {
+		data = append(data, byte(unit), byte(unit>>8))
+	}
+
+	return data
+}
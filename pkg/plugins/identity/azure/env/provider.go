@@ -19,12 +19,14 @@ package env
 import (
 	"context"
 	"fmt"
+	"os"
 
 	"go.uber.org/zap"
 
-	"github.com/Azure/go-autorest/autorest"
-	"github.com/Azure/go-autorest/autorest/azure/auth"
+	"github.com/Azure/azure-sdk-for-go/sdk/azcore"
 
+	azclient "github.com/fidelity/kconnect/pkg/azure/client"
+	"github.com/fidelity/kconnect/pkg/azure/cloud"
 	"github.com/fidelity/kconnect/pkg/azure/identity"
 	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/provider"
@@ -44,6 +46,7 @@ func init() {
 			ConfigurationItemsFunc: ConfigurationItems,
 		},
 		CreateFunc: New,
+		DetectFunc: Detect,
 	}); err != nil {
 		zap.S().Fatalw("Failed to register Azure environment identity plugin", "error", err)
 	}
@@ -63,7 +66,10 @@ type envIdentityProvider struct {
 }
 
 type providerConfig struct {
-	UseFile bool `json:"use-file"`
+	UseFile                 bool           `json:"use-file"`
+	CredentialType          CredentialType `json:"credential-type"`
+	ManagedIdentityClientID string         `json:"managed-identity-client-id"`
+	cloud.Config
 }
 
 func (p *envIdentityProvider) Name() string {
@@ -79,30 +85,69 @@ func (p *envIdentityProvider) Authenticate(ctx context.Context, input *provid.Au
 		return nil, fmt.Errorf("unmarshalling config into providerConfig: %w", err)
 	}
 
-	var authorizer autorest.Authorizer
-	var err error
+	env, err := p.environment(&cfg.Config)
+	if err != nil {
+		return nil, fmt.Errorf("getting azure environment %s: %w", cfg.Name, err)
+	}
+
+	opts := azcore.ClientOptions{
+		Cloud:     cloud.ClientConfiguration(env),
+		Transport: azclient.NewTransport(),
+	}
+
+	var credential azcore.TokenCredential
 	if cfg.UseFile {
-		authorizer, err = auth.NewAuthorizerFromFile("")
+		credential, err = credentialFromFile(opts)
 	} else {
-		authorizer, err = auth.NewAuthorizerFromEnvironment()
+		credential, err = p.credentialFromEnvironment(cfg, opts)
 	}
-
 	if err != nil {
-		return nil, fmt.Errorf("getting authorizer: %w", err)
+		return nil, fmt.Errorf("getting credential: %w", err)
 	}
 
-	id := identity.NewAuthorizerIdentity("", ProviderName, authorizer)
+	id := identity.NewCredentialIdentity("", ProviderName, credential)
 
 	return &provid.AuthenticateOutput{
 		Identity: id,
 	}, nil
 }
 
+// environment returns the endpoints of the cloud. AZURE_ENVIRONMENT is used if a cloud
+// hasn't been chosen.
+func (p *envIdentityProvider) environment(cloudCfg *cloud.Config) (cloud.Environment, error) {
+	if name := os.Getenv(environmentNameEnvVar); name != "" && cloudCfg.IsDefault() {
+		return cloud.EnvironmentFromName(name)
+	}
+
+	return cloudCfg.Environment()
+}
+
+// Detect will check if the azure environment variables used for authentication have been set
+func Detect(scopeTo string) (string, bool) {
+	if os.Getenv(authLocationEnvVar) != "" {
+		return "AZURE_AUTH_LOCATION is set", true
+	}
+	if os.Getenv(federatedTokenFileEnvVar) != "" {
+		return "AZURE_FEDERATED_TOKEN_FILE is set for workload identity", true
+	}
+	if os.Getenv("AZURE_TENANT_ID") != "" && os.Getenv(clientIDEnvVar) != "" {
+		return "AZURE_TENANT_ID and AZURE_CLIENT_ID are set", true
+	}
+	if os.Getenv(identityEndpointEnvVar) != "" || os.Getenv(msiEndpointEnvVar) != "" {
+		return "a managed identity endpoint is set", true
+	}
+
+	return "", false
+}
+
 // ConfigurationItems will return the configuration items for the intentity plugin based
 // of the cluster provider that its being used in conjunction with
 func ConfigurationItems(scopeTo string) (config.ConfigurationSet, error) {
 	cs := config.NewConfigurationSet()
-	cs.Bool("use-file", false, "Use file based authorization") //nolint:errcheck
+	cs.Bool("use-file", false, "Use file based authorization")                                                                                                                                                    //nolint:errcheck
+	cs.Enum("credential-type", string(CredentialTypeAuto), credentialTypeValues(), "The credential to authenticate with. auto uses workload identity, then a service principal or user, then a managed identity") //nolint:errcheck
+	cs.String("managed-identity-client-id", "", "The client id of a user-assigned managed identity. Defaults to AZURE_CLIENT_ID, the system-assigned identity is used if neither is set")                         //nolint:errcheck
+	cloud.AddConfig(cs)
 
 	return cs, nil
 }
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package mock
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/provider"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+const (
+	ProviderName = "mock"
+
+	usernameConfigItem    = "mock-username"
+	authOutcomeConfigItem = "mock-auth-outcome"
+	authLatencyConfigItem = "mock-auth-latency"
+	passwordConfigItem    = "mock-password"
+
+	// Password is the only password the mock accepts with the invalid-credentials outcome
+	Password = "mock-password"
+
+	// OutcomeSuccess means authentication succeeds
+	OutcomeSuccess = "success"
+	// OutcomeDenied means authentication fails as the credentials are wrong
+	OutcomeDenied = "denied"
+	// OutcomeMFARequired means authentication fails as MFA is required
+	OutcomeMFARequired = "mfa-required"
+	// OutcomeError means authentication fails with an unexpected error
+	OutcomeError = "error"
+	// OutcomeInvalidCredentials means authentication fails until the mock password is entered
+	OutcomeInvalidCredentials = "invalid-credentials"
+	// OutcomeMFADenied means authentication fails as the MFA challenge is denied
+	OutcomeMFADenied = "mfa-denied"
+	// OutcomeAccountLocked means authentication fails as the account is locked
+	OutcomeAccountLocked = "account-locked"
+)
+
+var (
+	ErrAccessDenied  = kerrors.WithCode(kerrors.CodeAuthFailed, errors.New("mock access denied"))
+	ErrMFARequired   = kerrors.WithCode(kerrors.CodeMFARequired, errors.New("mock mfa required"))
+	ErrUnexpected    = errors.New("mock unexpected error")
+	ErrWrongPassword = errors.New("mock wrong password")
+	ErrMFADenied     = kerrors.WithCode(kerrors.CodeMFADenied, errors.New("mock mfa denied"))
+	ErrLocked        = identity.NewCredentialError(kerrors.CodeAccountLocked, errors.New("mock account locked"), passwordConfigItem)
+)
+
+// Register will register the mock identity plugin. It isn't registered by default so
+// it's only available when the hidden --mock-plugins flag is set.
+func Register() error {
+	if err := registry.RegisterIdentityPlugin(&registry.IdentityPluginRegistration{
+		PluginRegistration: registry.PluginRegistration{
+			Name:                   ProviderName,
+			UsageExample:           "",
+			ConfigurationItemsFunc: ConfigurationItems,
+		},
+		CreateFunc:           New,
+		IdentitiesConfigItem: usernameConfigItem,
+	}); err != nil {
+		return fmt.Errorf("registering mock identity plugin: %w", err)
+	}
+
+	return nil
+}
+
+// New will create a new mock identity provider
+func New(input *provider.PluginCreationInput) (identity.Provider, error) {
+	return &mockIdentityProvider{
+		logger: input.Logger,
+	}, nil
+}
+
+type mockIdentityProvider struct {
+	logger *zap.SugaredLogger
+}
+
+type providerConfig struct {
+	Username    string        `json:"mock-username"`
+	AuthOutcome string        `json:"mock-auth-outcome"`
+	AuthLatency time.Duration `json:"mock-auth-latency"`
+	Password    string        `json:"mock-password"`
+}
+
+func (p *mockIdentityProvider) Name() string {
+	return ProviderName
+}
+
+// Authenticate will return a token identity for the mock user or fail with the
+// configured outcome, after waiting for the configured latency.
+func (p *mockIdentityProvider) Authenticate(ctx context.Context, input *identity.AuthenticateInput) (*identity.AuthenticateOutput, error) {
+	p.logger.Info("using mock authentication")
+
+	cfg := &providerConfig{}
+	if err := config.Unmarshall(input.ConfigSet, cfg); err != nil {
+		return nil, fmt.Errorf("unmarshalling config into providerConfig: %w", err)
+	}
+
+	if cfg.AuthLatency > 0 {
+		p.logger.Debugw("waiting before authenticating", "latency", cfg.AuthLatency.String())
+		select {
+		case <-time.After(cfg.AuthLatency):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+
+	switch cfg.AuthOutcome {
+	case OutcomeDenied:
+		return nil, ErrAccessDenied
+	case OutcomeMFARequired:
+		return nil, ErrMFARequired
+	case OutcomeError:
+		return nil, ErrUnexpected
+	case OutcomeInvalidCredentials:
+		if cfg.Password != Password {
+			return nil, identity.NewCredentialError(kerrors.CodeInvalidCredentials, ErrWrongPassword, passwordConfigItem)
+		}
+	case OutcomeMFADenied:
+		return nil, ErrMFADenied
+	case OutcomeAccountLocked:
+		return nil, ErrLocked
+	}
+
+	return &identity.AuthenticateOutput{
+		Identity: identity.NewTokenIdentity(cfg.Username, "mock-token-"+cfg.Username, ProviderName),
+	}, nil
+}
+
+// ConfigurationItems will return the configuration items for the mock identity plugin
+func ConfigurationItems(scopeTo string) (config.ConfigurationSet, error) {
+	cs := config.NewConfigurationSet()
+
+	cs.String("idp-protocol", "", "The idp protocol to use (e.g. saml). Each protocol has its own flags.")                                                                                                                                //nolint:errcheck
+	cs.String(usernameConfigItem, "mock-user", "The name of the user the mock authenticates as")                                                                                                                                          //nolint:errcheck
+	cs.Enum(authOutcomeConfigItem, OutcomeSuccess, []string{OutcomeSuccess, OutcomeDenied, OutcomeMFARequired, OutcomeError, OutcomeInvalidCredentials, OutcomeMFADenied, OutcomeAccountLocked}, "The result of the mock authentication") //nolint:errcheck
+	cs.String(passwordConfigItem, "", "The password of the mock user, only checked with the invalid-credentials outcome")                                                                                                                 //nolint:errcheck
+	cs.SetSensitive(passwordConfigItem)                                                                                                                                                                                                   //nolint:errcheck
+	cs.Duration(authLatencyConfigItem, 0, "How long the mock waits before authenticating, to simulate a slow identity provider")                                                                                                          //nolint:errcheck
+
+	return cs, nil
+}
@@ -0,0 +1,128 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oauth
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+	khttp "github.com/fidelity/kconnect/pkg/http"
+)
+
+const (
+	oauthMetadataPath  = "/.well-known/oauth-authorization-server"
+	tokenRequestPath   = "/oauth/token/request"
+	challengingClient  = "openshift-challenging-client"
+	csrfTokenHeader    = "X-CSRF-Token"
+	locationHeader     = "Location"
+	accessTokenParam   = "access_token"
+	errorResponseParam = "error"
+)
+
+type oauthMetadata struct {
+	Issuer                string `json:"issuer"`
+	AuthorizationEndpoint string `json:"authorization_endpoint"`
+	TokenEndpoint         string `json:"token_endpoint"`
+}
+
+// discoverOAuthServer gets the details of the OAuth server from the OpenShift API server
+func (p *oauthIdentityProvider) discoverOAuthServer(apiServer string) (*oauthMetadata, error) {
+	metadataURL := strings.TrimSuffix(apiServer, "/") + oauthMetadataPath
+
+	resp, err := p.httpClient.Get(metadataURL, defaults.Headers(defaults.WithAcceptJSON()))
+	if err != nil {
+		return nil, fmt.Errorf("getting openshift oauth metadata: %w", err)
+	}
+	if resp.ResponseCode() != http.StatusOK {
+		return nil, fmt.Errorf("getting openshift oauth metadata, status code %d: %w", resp.ResponseCode(), ErrNoOAuthServer)
+	}
+
+	metadata := &oauthMetadata{}
+	if err := json.Unmarshal([]byte(resp.Body()), metadata); err != nil {
+		return nil, fmt.Errorf("unmarshalling openshift oauth metadata: %w", err)
+	}
+	if metadata.AuthorizationEndpoint == "" {
+		return nil, ErrNoOAuthServer
+	}
+
+	return metadata, nil
+}
+
+// challengeLogin requests a token using the username and password as basic credentials
+// in the same way as oc login. The token is in the fragment of the redirect location.
+func (p *oauthIdentityProvider) challengeLogin(cfg *oauthConfig) (string, error) {
+	metadata, err := p.discoverOAuthServer(cfg.APIServer)
+	if err != nil {
+		return "", err
+	}
+
+	authorizeURL, err := url.Parse(metadata.AuthorizationEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("parsing openshift authorization endpoint: %w", err)
+	}
+	query := authorizeURL.Query()
+	query.Set("response_type", "token")
+	query.Set("client_id", challengingClient)
+	authorizeURL.RawQuery = query.Encode()
+
+	headers := defaults.Headers(defaults.WithNoCache())
+	headers[csrfTokenHeader] = "1"
+	khttp.SetBasicAuthHeaders(headers, cfg.Username, cfg.Password)
+
+	p.logger.Debugw("requesting openshift token", "url", metadata.AuthorizationEndpoint)
+	resp, err := p.httpClient.Get(authorizeURL.String(), headers)
+	if err != nil {
+		return "", fmt.Errorf("requesting openshift token: %w", err)
+	}
+	if resp.ResponseCode() != http.StatusFound {
+		return "", fmt.Errorf("requesting openshift token, status code %d: %w", resp.ResponseCode(), ErrAuthenticationFailed)
+	}
+
+	return tokenFromLocation(resp.Headers()[locationHeader])
+}
+
+// tokenFromLocation returns the access token from the fragment of the redirect location
+func tokenFromLocation(location string) (string, error) {
+	locationURL, err := url.Parse(location)
+	if err != nil {
+		return "", fmt.Errorf("parsing openshift oauth redirect: %w", err)
+	}
+
+	params, err := url.ParseQuery(locationURL.Fragment)
+	if err != nil {
+		return "", fmt.Errorf("parsing openshift oauth redirect fragment: %w", err)
+	}
+	if oauthErr := params.Get(errorResponseParam); oauthErr != "" {
+		return "", fmt.Errorf("openshift oauth error %s: %w", oauthErr, ErrAuthenticationFailed)
+	}
+
+	token := params.Get(accessTokenParam)
+	if token == "" {
+		return "", ErrNoAccessToken
+	}
+
+	return token, nil
+}
+
+// tokenRequestURL returns the URL to request a token in the browser
+func tokenRequestURL(metadata *oauthMetadata) string {
+	return strings.TrimSuffix(metadata.Issuer, "/") + tokenRequestPath
+}
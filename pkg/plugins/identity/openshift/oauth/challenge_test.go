@@ -0,0 +1,140 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oauth
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	khttp "github.com/fidelity/kconnect/pkg/http"
+)
+
+func TestChallengeLogin(t *testing.T) {
+	testCases := []struct {
+		name        string
+		username    string
+		password    string
+		expectToken string
+		expectError bool
+	}{
+		{
+			name:        "valid credentials",
+			username:    "bob",
+			password:    "secret",
+			expectToken: "sha256~abc",
+		},
+		{
+			name:        "invalid credentials",
+			username:    "bob",
+			password:    "wrong",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			server := newFakeOAuthServer()
+			defer server.Close()
+
+			p := &oauthIdentityProvider{
+				logger:     zap.S(),
+				httpClient: khttp.NewHTTPClient(khttp.WithoutRedirects()),
+			}
+			token, err := p.challengeLogin(&oauthConfig{ //nolint:scopelint
+				APIServer: server.URL,
+				Username:  tc.username, //nolint:scopelint
+				Password:  tc.password, //nolint:scopelint
+			})
+
+			if tc.expectError { //nolint:scopelint
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(token).To(Equal(tc.expectToken)) //nolint:scopelint
+		})
+	}
+}
+
+func TestTokenFromLocation(t *testing.T) {
+	testCases := []struct {
+		name        string
+		location    string
+		expectToken string
+		expectError bool
+	}{
+		{
+			name:        "token in fragment",
+			location:    "https://oauth.example.com/oauth/token/implicit#access_token=sha256~abc&expires_in=86400&token_type=Bearer",
+			expectToken: "sha256~abc",
+		},
+		{
+			name:        "oauth error",
+			location:    "https://oauth.example.com/oauth/token/implicit#error=access_denied",
+			expectError: true,
+		},
+		{
+			name:        "no token",
+			location:    "https://oauth.example.com/oauth/token/implicit",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			token, err := tokenFromLocation(tc.location) //nolint:scopelint
+			if tc.expectError {                          //nolint:scopelint
+				g.Expect(err).To(HaveOccurred())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(token).To(Equal(tc.expectToken)) //nolint:scopelint
+		})
+	}
+}
+
+func newFakeOAuthServer() *httptest.Server {
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+
+	mux.HandleFunc(oauthMetadataPath, func(w http.ResponseWriter, r *http.Request) {
+		fmt.Fprintf(w, `{"issuer":"%[1]s","authorization_endpoint":"%[1]s/oauth/authorize","token_endpoint":"%[1]s/oauth/token"}`, server.URL)
+	})
+	mux.HandleFunc("/oauth/authorize", func(w http.ResponseWriter, r *http.Request) {
+		username, password, ok := r.BasicAuth()
+		if !ok || username != "bob" || password != "secret" || r.Header.Get(csrfTokenHeader) == "" {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		if r.URL.Query().Get("client_id") != challengingClient {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		http.Redirect(w, r, server.URL+"/oauth/token/implicit#access_token=sha256~abc&token_type=Bearer", http.StatusFound)
+	})
+
+	return server
+}
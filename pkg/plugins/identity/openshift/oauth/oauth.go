@@ -0,0 +1,178 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oauth
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/go-playground/validator/v10"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/provider"
+	"github.com/fidelity/kconnect/pkg/provider/common"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+const (
+	ProviderName = "openshift-oauth"
+
+	APIServerConfigItem = "openshift-api-server"
+	LoginConfigItem     = "openshift-login"
+	TokenConfigItem     = "openshift-token"
+)
+
+var (
+	ErrAddingCommonCfg      = errors.New("adding common identity config")
+	ErrNoOAuthServer        = errors.New("no oauth server found for the openshift api server")
+	ErrAuthenticationFailed = errors.New("failed to authenticate with the openshift oauth server")
+	ErrNoAccessToken        = errors.New("no access token in the openshift oauth response")
+	ErrPasswordRequired     = errors.New("username and password are required for the challenge login")
+)
+
+// LoginType is a type that represents how to get a token from the OpenShift OAuth server
+type LoginType string
+
+var (
+	// LoginTypeChallenge requests a token using the username and password as basic credentials
+	LoginTypeChallenge = LoginType("challenge")
+	// LoginTypeBrowser displays a URL to request a token in the browser and asks for the token
+	LoginTypeBrowser = LoginType("browser")
+)
+
+func loginTypeValues() []string {
+	return []string{
+		string(LoginTypeChallenge),
+		string(LoginTypeBrowser),
+	}
+}
+
+func init() {
+	if err := registry.RegisterIdentityPlugin(&registry.IdentityPluginRegistration{
+		PluginRegistration: registry.PluginRegistration{
+			Name:                   ProviderName,
+			UsageExample:           "",
+			ConfigurationItemsFunc: ConfigurationItems,
+		},
+		CreateFunc: New,
+	}); err != nil {
+		zap.S().Fatalw("Failed to register OpenShift OAuth identity plugin", "error", err)
+	}
+}
+
+// New will create a new OpenShift OAuth identity provider
+func New(input *provider.PluginCreationInput) (identity.Provider, error) {
+	return &oauthIdentityProvider{
+		logger:      input.Logger,
+		interactive: input.IsInteractice,
+		// The token is returned in the location of a redirect so they mustn't be followed
+		httpClient: khttp.NewHTTPClient(append(input.HTTPClientOptions, khttp.WithoutRedirects())...),
+	}, nil
+}
+
+type oauthIdentityProvider struct {
+	interactive bool
+	logger      *zap.SugaredLogger
+	httpClient  khttp.Client
+}
+
+type oauthConfig struct {
+	Username  string    `json:"username"`
+	Password  string    `json:"password"`
+	APIServer string    `json:"openshift-api-server" validate:"required"`
+	Login     LoginType `json:"openshift-login" validate:"required"`
+	Token     string    `json:"openshift-token"`
+}
+
+func (p *oauthIdentityProvider) Name() string {
+	return ProviderName
+}
+
+// Authenticate will get a token for the user from the OpenShift OAuth server
+func (p *oauthIdentityProvider) Authenticate(ctx context.Context, input *identity.AuthenticateInput) (*identity.AuthenticateOutput, error) {
+	p.logger.Info("authenticating user with openshift oauth")
+
+	if err := p.resolveConfig(input.ConfigSet); err != nil {
+		return nil, fmt.Errorf("resolving config: %w", err)
+	}
+
+	cfg := &oauthConfig{}
+	if err := config.Unmarshall(input.ConfigSet, cfg); err != nil {
+		return nil, fmt.Errorf("unmarshalling config into oauthConfig: %w", err)
+	}
+
+	if err := p.validateConfig(cfg); err != nil {
+		return nil, err
+	}
+
+	token := cfg.Token
+	if token == "" {
+		if cfg.Login != LoginTypeChallenge || cfg.Username == "" || cfg.Password == "" {
+			return nil, ErrPasswordRequired
+		}
+
+		var err error
+		token, err = p.challengeLogin(cfg)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	name := cfg.Username
+	if name == "" {
+		name = ProviderName
+	}
+
+	return &identity.AuthenticateOutput{
+		Identity: identity.NewTokenIdentity(name, token, ProviderName),
+	}, nil
+}
+
+// RestoreIdentity creates the token identity from its JSON
+func (p *oauthIdentityProvider) RestoreIdentity(data []byte) (identity.Identity, error) {
+	return identity.RestoreTokenIdentity(data)
+}
+
+func (p *oauthIdentityProvider) validateConfig(cfg *oauthConfig) error {
+	validate := validator.New()
+	if err := validate.Struct(cfg); err != nil {
+		return fmt.Errorf("validating openshift oauth config: %w", err)
+	}
+	return nil
+}
+
+// ConfigurationItems will return the configuration items for the intentity plugin based
+// of the cluster provider that its being used in conjunction with
+func ConfigurationItems(scopeTo string) (config.ConfigurationSet, error) {
+	cs := config.NewConfigurationSet()
+
+	if err := common.AddCommonIdentityConfig(cs); err != nil {
+		return nil, ErrAddingCommonCfg
+	}
+
+	cs.String(APIServerConfigItem, "", "The OpenShift API server, which is used to discover the OAuth server")                                                  //nolint: errcheck
+	cs.Enum(LoginConfigItem, string(LoginTypeChallenge), loginTypeValues(), "How to get the token, either challenge with the username and password or browser") //nolint: errcheck
+	cs.String(TokenConfigItem, "", "An OpenShift token to use instead of requesting one from the OAuth server")                                                 //nolint: errcheck
+	cs.SetRequired(APIServerConfigItem)                                                                                                                         //nolint: errcheck
+	cs.SetSensitive(TokenConfigItem)                                                                                                                            //nolint: errcheck
+
+	return cs, nil
+}
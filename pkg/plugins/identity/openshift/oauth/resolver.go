@@ -0,0 +1,73 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package oauth
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/defaults"
+	"github.com/fidelity/kconnect/pkg/prompt"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+func (p *oauthIdentityProvider) resolveConfig(cfg config.ConfigurationSet) error {
+	if !p.interactive {
+		p.logger.Debug("skipping configuration resolution as runnning non-interactive")
+		return nil
+	}
+
+	if err := prompt.InputAndSet(cfg, APIServerConfigItem, "Enter the OpenShift API server", true); err != nil {
+		return fmt.Errorf("resolving %s: %w", APIServerConfigItem, err)
+	}
+	if cfg.ExistsWithValue(TokenConfigItem) {
+		return nil
+	}
+
+	if cfg.ValueString(LoginConfigItem) == string(LoginTypeBrowser) {
+		return p.resolveBrowserToken(cfg)
+	}
+
+	if err := prompt.InputAndSet(cfg, defaults.UsernameConfigItem, "Username:", true); err != nil {
+		return fmt.Errorf("resolving %s: %w", defaults.UsernameConfigItem, err)
+	}
+	if err := prompt.InputSensitiveAndSet(cfg, defaults.PasswordConfigItem, "Password:", true); err != nil {
+		return fmt.Errorf("resolving %s: %w", defaults.PasswordConfigItem, err)
+	}
+
+	return nil
+}
+
+// resolveBrowserToken displays the URL to request a token in the browser and asks the
+// user to enter the token that is displayed
+func (p *oauthIdentityProvider) resolveBrowserToken(cfg config.ConfigurationSet) error {
+	metadata, err := p.discoverOAuthServer(cfg.ValueString(APIServerConfigItem))
+	if err != nil {
+		return err
+	}
+
+	fmt.Fprintf(os.Stderr, "%s\n%s\n", utils.Warning("Open the following URL in your browser to request an OpenShift token:"), tokenRequestURL(metadata))
+	if err := utils.OpenBrowser(tokenRequestURL(metadata)); err != nil {
+		p.logger.Debugw("failed opening the token request url in the browser", "error", err.Error())
+	}
+	if err := prompt.InputSensitiveAndSet(cfg, TokenConfigItem, "Token:", true); err != nil {
+		return fmt.Errorf("resolving %s: %w", TokenConfigItem, err)
+	}
+
+	return nil
+}
@@ -18,9 +18,11 @@ package discovery
 
 import (
 	// Initialize the identity plugins
+	_ "github.com/fidelity/kconnect/pkg/plugins/identity/aws/ambient"
 	_ "github.com/fidelity/kconnect/pkg/plugins/identity/aws/iam"
 	_ "github.com/fidelity/kconnect/pkg/plugins/identity/azure/aad"
 	_ "github.com/fidelity/kconnect/pkg/plugins/identity/azure/env"
+	_ "github.com/fidelity/kconnect/pkg/plugins/identity/openshift/oauth"
 	_ "github.com/fidelity/kconnect/pkg/plugins/identity/rancher/activedirectory"
 	_ "github.com/fidelity/kconnect/pkg/plugins/identity/saml"
 	_ "github.com/fidelity/kconnect/pkg/plugins/identity/static/token"
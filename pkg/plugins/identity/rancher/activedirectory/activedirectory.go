@@ -22,12 +22,14 @@ import (
 	"errors"
 	"fmt"
 	"net/http"
+	"time"
 
 	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 
 	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/defaults"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
 	khttp "github.com/fidelity/kconnect/pkg/http"
 	"github.com/fidelity/kconnect/pkg/provider"
 	"github.com/fidelity/kconnect/pkg/provider/common"
@@ -38,12 +40,17 @@ import (
 
 const (
 	ProviderName = "rancher-ad"
+
+	tokenDescription = "kconnect"
 )
 
 var (
 	ErrAddingCommonCfg      = errors.New("adding common identity config")
 	ErrNoExpiryTime         = errors.New("token has no expiry time")
-	ErrAuthenticationFailed = errors.New("failed to authenticate using active directory")
+	ErrAuthenticationFailed = errors.New("failed to authenticate with rancher")
+	ErrInvalidCredentials   = errors.New("rancher rejected the username or password")
+	ErrPasswordRequired     = errors.New("password is required as there is no stored rancher token")
+	ErrBrowserLoginTimeout  = errors.New("timed out waiting for the browser login to complete")
 )
 
 func init() {
@@ -81,6 +88,8 @@ type radIdentityProvider struct {
 type radConfig struct {
 	common.IdentityProviderConfig
 	rancher.CommonConfig
+	AuthProvider rancher.AuthProvider `json:"rancher-auth-provider"`
+	TokenTTL     time.Duration        `json:"token-ttl"`
 }
 
 func (p *radIdentityProvider) Name() string {
@@ -110,26 +119,106 @@ func (p *radIdentityProvider) Authenticate(ctx context.Context, input *identity.
 		return nil, fmt.Errorf("vreating endpoint resolver: %w", err)
 	}
 
+	tokenStore := rancher.NewTokenStore()
+	storedToken, err := p.reusableToken(tokenStore, resolver, cfg)
+	if err != nil {
+		return nil, err
+	}
+	if storedToken != nil {
+		p.logger.Debug("reusing stored rancher token")
+		id := identity.NewTokenIdentity(storedToken.UserID, storedToken.Token, ProviderName)
+		id.SetExpiresAt(storedToken.ExpiresAt)
+
+		return &identity.AuthenticateOutput{
+			Identity: id,
+		}, nil
+	}
+
+	if cfg.AuthProvider.UsesPassword() {
+		storedToken, err = p.passwordLogin(resolver, cfg)
+	} else {
+		storedToken, err = p.browserLogin(ctx, resolver, cfg)
+	}
+	if err != nil {
+		return nil, err
+	}
+	if err := tokenStore.Save(cfg.APIEndpoint, cfg.AuthProvider, cfg.Username, storedToken); err != nil {
+		p.logger.Warnw("failed storing rancher token", "error", err.Error())
+	}
+
+	id := identity.NewTokenIdentity(storedToken.UserID, storedToken.Token, ProviderName)
+	id.SetExpiresAt(storedToken.ExpiresAt)
+
+	return &identity.AuthenticateOutput{
+		Identity: id,
+	}, nil
+}
+
+// RestoreIdentity creates the token identity from its JSON
+func (p *radIdentityProvider) RestoreIdentity(data []byte) (identity.Identity, error) {
+	return identity.RestoreTokenIdentity(data)
+}
+
+// reusableToken returns the stored token for the user if it isn't near expiry and
+// the Rancher API still accepts it
+func (p *radIdentityProvider) reusableToken(tokenStore *rancher.TokenStore, resolver rancher.EndpointsResolver, cfg *radConfig) (*rancher.StoredToken, error) {
+	storedToken, err := tokenStore.Get(cfg.APIEndpoint, cfg.AuthProvider, cfg.Username)
+	if err != nil {
+		p.logger.Warnw("failed reading stored rancher token", "error", err.Error())
+		return nil, nil
+	}
+	if storedToken == nil {
+		return nil, nil
+	}
+
+	valid, err := rancher.IsTokenValid(p.httpClient, resolver, storedToken.Token)
+	if err != nil {
+		return nil, fmt.Errorf("checking stored rancher token: %w", err)
+	}
+	if !valid {
+		p.logger.Debug("stored rancher token is no longer valid")
+		if err := tokenStore.Delete(cfg.APIEndpoint, cfg.AuthProvider, cfg.Username); err != nil {
+			p.logger.Warnw("failed deleting stored rancher token", "error", err.Error())
+		}
+		return nil, nil
+	}
+
+	return storedToken, nil
+}
+
+// passwordLogin creates a new token for the user with the configured time to live using
+// the login API of the auth provider
+func (p *radIdentityProvider) passwordLogin(resolver rancher.EndpointsResolver, cfg *radConfig) (*rancher.StoredToken, error) {
+	if cfg.Password == "" {
+		return nil, ErrPasswordRequired
+	}
+
 	loginRequest := &loginRequest{
 		Type:        "token",
-		Description: "automation",
+		Description: tokenDescription,
 		Username:    cfg.Username,
 		Password:    cfg.Password,
+		TTL:         cfg.TokenTTL.Milliseconds(),
 	}
 
 	data, err := json.Marshal(loginRequest)
 	if err != nil {
-		return nil, fmt.Errorf("marshalling ad request: %w", err)
+		return nil, fmt.Errorf("marshalling login request: %w", err)
 	}
 
 	headers := defaults.Headers(defaults.WithNoCache(), defaults.WithContentTypeJSON())
 
-	resp, err := p.httpClient.Post(resolver.ActiveDirectoryAuth(), string(data), headers)
+	resp, err := p.httpClient.Post(resolver.PasswordAuth(cfg.AuthProvider), string(data), headers)
 	if err != nil {
-		return nil, fmt.Errorf("performing AD auth: %w", err)
+		return nil, fmt.Errorf("performing %s auth: %w", cfg.AuthProvider, err)
 	}
 
-	if resp.ResponseCode() != http.StatusCreated {
+	switch resp.ResponseCode() {
+	case http.StatusCreated:
+	case http.StatusUnauthorized:
+		// Rancher doesn't say which credential is wrong, the password is the likeliest
+		return nil, identity.NewCredentialError(kerrors.CodeInvalidCredentials, ErrInvalidCredentials, "password")
+	default:
 		return nil, ErrAuthenticationFailed
 	}
 
@@ -138,16 +227,22 @@ func (p *radIdentityProvider) Authenticate(ctx context.Context, input *identity.
 		return nil, fmt.Errorf("unmarshalling login response: %w", err)
 	}
 
-	id := identity.NewTokenIdentity(loginResponse.UserID, loginResponse.Token, ProviderName)
-
-	return &identity.AuthenticateOutput{
-		Identity: id,
+	return &rancher.StoredToken{
+		Token:     loginResponse.Token,
+		UserID:    loginResponse.UserID,
+		ExpiresAt: loginResponse.expiresAt(loginRequest.TTL),
 	}, nil
 }
 
 func (p *radIdentityProvider) validateConfig(cfg *radConfig) error {
 	validate := validator.New()
-	if err := validate.Struct(cfg); err != nil {
+	// The password isn't needed if there is a stored token and the username isn't
+	// needed when logging in with the browser
+	excluded := []string{"IdentityProviderConfig.Password"}
+	if !cfg.AuthProvider.UsesPassword() {
+		excluded = append(excluded, "IdentityProviderConfig.Username")
+	}
+	if err := validate.StructExcept(cfg, excluded...); err != nil {
 		return fmt.Errorf("validating aad config: %w", err)
 	}
 	return nil
@@ -164,6 +259,8 @@ func ConfigurationItems(scopeTo string) (config.ConfigurationSet, error) {
 	if err := rancher.AddCommonConfig(cs); err != nil {
 		return nil, ErrAddingCommonCfg
 	}
+	cs.Enum(rancher.AuthProviderConfigName, string(rancher.AuthProviderActiveDirectory), rancher.AuthProviderValues(), "The Rancher auth provider to log in with. The local and activedirectory providers use the username and password, the others log in using the browser") //nolint: errcheck
+	cs.Duration(rancher.TokenTTLConfigName, rancher.DefaultTokenTTL, "How long the Rancher token created by kconnect is valid for. The token is reused until it's near expiry")                                                                                                //nolint: errcheck
 
 	return cs, nil
 }
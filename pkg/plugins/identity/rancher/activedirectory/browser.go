@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package activedirectory
+
+import (
+	"context"
+	"crypto"
+	"crypto/rand"
+	"crypto/rsa"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"time"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/rancher"
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	browserLoginKeySize      = 2048
+	browserLoginPollInterval = 5 * time.Second
+	browserLoginTimeout      = 5 * time.Minute
+)
+
+// browserLogin logs in with an auth provider that needs the browser, e.g. GitHub, SAML or
+// OIDC. The user completes the login in the Rancher dashboard, which encrypts the new token
+// with our public key and makes it available to poll for.
+func (p *radIdentityProvider) browserLogin(ctx context.Context, resolver rancher.EndpointsResolver, cfg *radConfig) (*rancher.StoredToken, error) {
+	privateKey, err := rsa.GenerateKey(rand.Reader, browserLoginKeySize)
+	if err != nil {
+		return nil, fmt.Errorf("generating browser login key: %w", err)
+	}
+	publicKeyData, err := json.Marshal(privateKey.PublicKey)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling browser login public key: %w", err)
+	}
+	requestID, err := newRequestID()
+	if err != nil {
+		return nil, err
+	}
+
+	loginURL := resolver.DashboardLogin(requestID, base64.StdEncoding.EncodeToString(publicKeyData))
+	fmt.Fprintf(os.Stderr, "%s\n%s\n", utils.Warning(fmt.Sprintf("Open the following URL in your browser to log in to Rancher using %s:", cfg.AuthProvider)), loginURL)
+	if err := utils.OpenBrowser(loginURL); err != nil {
+		p.logger.Debugw("failed opening the login url in the browser", "error", err.Error())
+	}
+
+	token, err := p.pollAuthToken(ctx, resolver.AuthToken(requestID))
+	if err != nil {
+		return nil, err
+	}
+
+	encrypted, err := base64.StdEncoding.DecodeString(token.Token)
+	if err != nil {
+		return nil, fmt.Errorf("decoding browser login token: %w", err)
+	}
+	decrypted, err := privateKey.Decrypt(nil, encrypted, &rsa.OAEPOptions{Hash: crypto.SHA256})
+	if err != nil {
+		return nil, fmt.Errorf("decrypting browser login token: %w", err)
+	}
+
+	if _, err := p.httpClient.Do(&khttp.ClientRequest{
+		URL:     resolver.AuthToken(requestID),
+		Method:  http.MethodDelete,
+		Headers: defaults.Headers(defaults.WithJSON()),
+	}); err != nil {
+		p.logger.Warnw("failed deleting browser login request", "error", err.Error())
+	}
+
+	return &rancher.StoredToken{
+		Token:     string(decrypted),
+		UserID:    token.UserID,
+		ExpiresAt: token.expiresAt(0),
+	}, nil
+}
+
+// pollAuthToken polls for the token of the browser login request until the user has
+// completed the login or it times out
+func (p *radIdentityProvider) pollAuthToken(ctx context.Context, tokenURL string) (*loginResponse, error) {
+	ctx, cancel := context.WithTimeout(ctx, browserLoginTimeout)
+	defer cancel()
+
+	headers := defaults.Headers(defaults.WithNoCache(), defaults.WithJSON())
+	ticker := time.NewTicker(browserLoginPollInterval)
+	defer ticker.Stop()
+
+	for {
+		select {
+		case <-ctx.Done():
+			return nil, fmt.Errorf("waiting for browser login: %w", ErrBrowserLoginTimeout)
+		case <-ticker.C:
+		}
+
+		resp, err := p.httpClient.Get(tokenURL, headers)
+		if err != nil {
+			return nil, fmt.Errorf("polling for browser login token: %w", err)
+		}
+		if resp.ResponseCode() == http.StatusNotFound {
+			p.logger.Debug("browser login not completed yet")
+			continue
+		}
+		if resp.ResponseCode() != http.StatusOK {
+			return nil, ErrAuthenticationFailed
+		}
+
+		token := &loginResponse{}
+		if err := json.Unmarshal([]byte(resp.Body()), token); err != nil {
+			return nil, fmt.Errorf("unmarshalling browser login token: %w", err)
+		}
+		if token.Token == "" {
+			continue
+		}
+
+		return token, nil
+	}
+}
+
+func newRequestID() (string, error) {
+	data := make([]byte, 16)
+	if _, err := rand.Read(data); err != nil {
+		return "", fmt.Errorf("generating browser login request id: %w", err)
+	}
+
+	return hex.EncodeToString(data), nil
+}
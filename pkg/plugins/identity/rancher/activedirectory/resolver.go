@@ -31,15 +31,48 @@ func (p *radIdentityProvider) resolveConfig(cfg config.ConfigurationSet) error {
 		return nil
 	}
 
+	if err := rshared.ResolveCommon(cfg); err != nil {
+		return fmt.Errorf("resolving common Rancher config: %w", err)
+	}
+	if !authProvider(cfg).UsesPassword() {
+		p.logger.Debug("skipping username and password as logging in using the browser")
+		return nil
+	}
 	if err := prompt.InputAndSet(cfg, defaults.UsernameConfigItem, "Username:", true); err != nil {
 		return fmt.Errorf("resolving %s: %w", defaults.UsernameConfigItem, err)
 	}
+	if p.hasStoredToken(cfg) {
+		p.logger.Debug("skipping password as there is a stored rancher token")
+		return nil
+	}
 	if err := prompt.InputSensitiveAndSet(cfg, defaults.PasswordConfigItem, "Password:", true); err != nil {
 		return fmt.Errorf("resolving %s: %w", defaults.PasswordConfigItem, err)
 	}
-	if err := rshared.ResolveCommon(cfg); err != nil {
-		return fmt.Errorf("resolving common Rancher config: %w", err)
-	}
 
 	return nil
 }
+
+// hasStoredToken returns true if there is a token stored for the user that isn't near expiry
+func (p *radIdentityProvider) hasStoredToken(cfg config.ConfigurationSet) bool {
+	if !cfg.ExistsWithValue(defaults.UsernameConfigItem) || !cfg.ExistsWithValue(rshared.APIEndpointConfigName) {
+		return false
+	}
+	username := cfg.Get(defaults.UsernameConfigItem).Value.(string)
+	apiEndpoint := cfg.Get(rshared.APIEndpointConfigName).Value.(string)
+
+	storedToken, err := rshared.NewTokenStore().Get(apiEndpoint, authProvider(cfg), username)
+	if err != nil {
+		p.logger.Warnw("failed reading stored rancher token", "error", err.Error())
+		return false
+	}
+
+	return storedToken != nil
+}
+
+func authProvider(cfg config.ConfigurationSet) rshared.AuthProvider {
+	if !cfg.ExistsWithValue(rshared.AuthProviderConfigName) {
+		return rshared.AuthProviderActiveDirectory
+	}
+
+	return rshared.AuthProvider(cfg.Get(rshared.AuthProviderConfigName).Value.(string))
+}
@@ -16,19 +16,43 @@ limitations under the License.
 
 package activedirectory
 
-import "encoding/json"
+import (
+	"encoding/json"
+	"time"
+)
 
 type loginRequest struct {
 	Type        string `json:"type"`
 	Description string `json:"description"`
 	Username    string `json:"username"`
 	Password    string `json:"password"`
+	TTL         int64  `json:"ttl,omitempty"`
 }
 
 type loginResponse struct { //TODO: add additional fields
-	Type   string      `json:"type"`
-	Name   string      `json:"name"`
-	Token  string      `json:"token"`
-	UserID string      `json:"userId"`
-	TTL    json.Number `json:"ttl"`
+	Type      string      `json:"type"`
+	Name      string      `json:"name"`
+	Token     string      `json:"token"`
+	UserID    string      `json:"userId"`
+	TTL       json.Number `json:"ttl"`
+	ExpiresAt string      `json:"expiresAt"`
+}
+
+// expiresAt returns when the token expires. Rancher can shorten the requested ttl so
+// the expiry in the response is used if there is one. A zero time means the token
+// doesn't expire.
+func (r *loginResponse) expiresAt(requestedTTL int64) time.Time {
+	if expires, err := time.Parse(time.RFC3339, r.ExpiresAt); err == nil {
+		return expires
+	}
+
+	ttl, err := r.TTL.Int64()
+	if err != nil {
+		ttl = requestedTTL
+	}
+	if ttl <= 0 {
+		return time.Time{}
+	}
+
+	return time.Now().Add(time.Duration(ttl) * time.Millisecond)
 }
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assertion
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+)
+
+const notOnOrAfterAttr = "NotOnOrAfter"
+
+var ErrNoExpiry = errors.New("saml assertion has no expiry")
+
+// Expiry returns when the base64 encoded SAML response can no longer be used, which is
+// the earliest NotOnOrAfter of its conditions and subject confirmations
+func Expiry(samlResponse string) (time.Time, error) {
+	data, err := base64.StdEncoding.DecodeString(samlResponse)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("decoding saml response: %w", err)
+	}
+
+	var expiry time.Time
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	for {
+		token, err := decoder.Token()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return time.Time{}, fmt.Errorf("parsing saml response: %w", err)
+		}
+
+		start, ok := token.(xml.StartElement)
+		if !ok {
+			continue
+		}
+		for _, attr := range start.Attr {
+			if attr.Name.Local != notOnOrAfterAttr {
+				continue
+			}
+			notOnOrAfter, err := time.Parse(time.RFC3339, attr.Value)
+			if err != nil {
+				return time.Time{}, fmt.Errorf("parsing %s of %s: %w", notOnOrAfterAttr, start.Name.Local, err)
+			}
+			if expiry.IsZero() || notOnOrAfter.Before(expiry) {
+				expiry = notOnOrAfter
+			}
+		}
+	}
+
+	if expiry.IsZero() {
+		return time.Time{}, ErrNoExpiry
+	}
+
+	return expiry, nil
+}
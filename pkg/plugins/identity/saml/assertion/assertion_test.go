@@ -0,0 +1,53 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package assertion_test
+
+import (
+	"encoding/base64"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/plugins/identity/saml/assertion"
+)
+
+func TestExpiry(t *testing.T) {
+	g := NewWithT(t)
+
+	response := base64.StdEncoding.EncodeToString([]byte(`<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol" xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion">
+  <saml:Assertion>
+    <saml:Subject>
+      <saml:SubjectConfirmation Method="urn:oasis:names:tc:SAML:2.0:cm:bearer">
+        <saml:SubjectConfirmationData NotOnOrAfter="2030-01-02T03:09:05Z" Recipient="https://signin.aws.amazon.com/saml"/>
+      </saml:SubjectConfirmation>
+    </saml:Subject>
+    <saml:Conditions NotBefore="2030-01-02T03:04:05Z" NotOnOrAfter="2030-01-02T04:04:05.123Z"/>
+  </saml:Assertion>
+</samlp:Response>`))
+
+	expiry, err := assertion.Expiry(response)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(expiry.Equal(time.Date(2030, 1, 2, 3, 9, 5, 0, time.UTC))).To(BeTrue())
+
+	_, err = assertion.Expiry(base64.StdEncoding.EncodeToString([]byte(`<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol"/>`)))
+	g.Expect(errors.Is(err, assertion.ErrNoExpiry)).To(BeTrue())
+
+	_, err = assertion.Expiry("not base64!")
+	g.Expect(err).To(HaveOccurred())
+}
@@ -0,0 +1,75 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saml
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/plugins/identity/saml/metadata"
+	"github.com/fidelity/kconnect/pkg/plugins/identity/saml/sp"
+)
+
+// applyMetadata sets the idp-endpoint and idp-provider from the SAML metadata of the
+// identity provider, if there's a metadata url. Values that have been set, e.g. using
+// the flags, aren't changed.
+func (p *samlIdentityProvider) applyMetadata(cs config.ConfigurationSet) error {
+	metadataURL := cs.ValueString(sp.MetadataURLConfigItem)
+	if metadataURL == "" {
+		return nil
+	}
+	p.logger.Debugw("configuring from saml metadata", "url", metadataURL)
+
+	client := p.httpClient
+	if client == nil {
+		client = khttp.NewHTTPClient()
+	}
+	md, err := metadata.Fetch(client, metadataURL)
+	if err != nil {
+		return fmt.Errorf("getting idp metadata: %w", err)
+	}
+
+	for _, cert := range md.Certificates {
+		if time.Now().After(cert.NotAfter) {
+			p.logger.Warnw("idp signing certificate in the metadata has expired", "subject", cert.Subject.String(), "expired", cert.NotAfter)
+		}
+	}
+
+	if !cs.ExistsWithValue("idp-endpoint") {
+		endpoint, err := md.SSOEndpoint()
+		if err != nil {
+			return fmt.Errorf("getting idp endpoint from metadata: %w", err)
+		}
+		if err := cs.SetValue("idp-endpoint", endpoint); err != nil {
+			return fmt.Errorf("setting idp-endpoint: %w", err)
+		}
+		p.logger.Debugw("idp endpoint set from metadata", "endpoint", endpoint)
+	}
+
+	if !cs.ExistsWithValue("idp-provider") {
+		if idpProvider := md.IdpProvider(); idpProvider != "" {
+			if err := cs.SetValue("idp-provider", idpProvider); err != nil {
+				return fmt.Errorf("setting idp-provider: %w", err)
+			}
+			p.logger.Debugw("idp provider set from metadata", "provider", idpProvider)
+		}
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saml
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"os"
+	"strconv"
+	"time"
+
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	acsPath              = "/saml/acs"
+	browserLoginTimeout  = 5 * time.Minute
+	acsReadHeaderTimeout = 10 * time.Second
+	samlResponseField    = "SAMLResponse"
+)
+
+var ErrBrowserLoginTimeout = errors.New("timed out waiting for the browser login")
+
+// acsLoginComplete is shown in the browser when the SAML response has been received
+const acsLoginComplete = `<!DOCTYPE html>
+<html>
+<head><title>kconnect</title></head>
+<body><p>You have logged in to kconnect, you can close this window.</p></body>
+</html>
+`
+
+// browserAssertion gets the SAML assertion by opening the IdP in the system browser. The
+// user completes the login in the browser, which handles any MFA or conditional access
+// pages, and the IdP posts the SAML response to the assertion consumer service (ACS)
+// listening on localhost.
+func (p *samlIdentityProvider) browserAssertion(ctx context.Context) (string, error) {
+	address := net.JoinHostPort("127.0.0.1", strconv.Itoa(p.config.ACSPort))
+	listener, err := net.Listen("tcp", address)
+	if err != nil {
+		return "", fmt.Errorf("listening for the saml response on %s: %w", address, err)
+	}
+
+	assertions := make(chan string, 1)
+	server := &http.Server{
+		Handler:           acsHandler(assertions),
+		ReadHeaderTimeout: acsReadHeaderTimeout,
+	}
+	go func() {
+		if err := server.Serve(listener); err != nil && !errors.Is(err, http.ErrServerClosed) {
+			p.logger.Warnw("saml assertion consumer service stopped", "error", err.Error())
+		}
+	}()
+	defer server.Close()
+
+	p.logger.Debugw("waiting for the saml response", "acs", fmt.Sprintf("http://localhost:%d%s", p.config.ACSPort, acsPath))
+	fmt.Fprintf(os.Stderr, "%s\n%s\n", utils.Warning("Complete the login in your browser, if it doesn't open use the following URL:"), p.config.IdpEndpoint)
+	if err := utils.OpenBrowser(p.config.IdpEndpoint); err != nil {
+		p.logger.Debugw("failed opening the idp endpoint in the browser", "error", err.Error())
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, browserLoginTimeout)
+	defer cancel()
+
+	select {
+	case <-ctx.Done():
+		return "", ErrBrowserLoginTimeout
+	case assertion := <-assertions:
+		return assertion, nil
+	}
+}
+
+// acsHandler is the assertion consumer service that receives the SAML response posted
+// by the IdP and sends it to the channel
+func acsHandler(assertions chan<- string) http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc(acsPath, func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, http.StatusText(http.StatusMethodNotAllowed), http.StatusMethodNotAllowed)
+			return
+		}
+		if err := r.ParseForm(); err != nil {
+			http.Error(w, "invalid form", http.StatusBadRequest)
+			return
+		}
+		assertion := r.PostForm.Get(samlResponseField)
+		if assertion == "" {
+			http.Error(w, "no SAML response", http.StatusBadRequest)
+			return
+		}
+
+		select {
+		case assertions <- assertion:
+		default:
+			// A SAML response has already been received
+		}
+
+		w.Header().Set("Content-Type", "text/html; charset=utf-8")
+		fmt.Fprint(w, acsLoginComplete)
+	})
+
+	return mux
+}
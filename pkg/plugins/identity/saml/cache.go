@@ -0,0 +1,97 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saml
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/versent/saml2aws/pkg/cfg"
+
+	"github.com/fidelity/kconnect/pkg/plugins/identity/saml/assertion"
+	"github.com/fidelity/kconnect/pkg/secrets"
+)
+
+// assertionReuseMargin is how long before the SAML assertion expires that it's no longer
+// reused, so that there's time to use it with STS
+const assertionReuseMargin = 30 * time.Second
+
+// cachedAssertion returns the SAML assertion from the last sign in to the IdP, if it
+// hasn't expired. This allows the credentials for other roles or accounts to be got
+// from STS without signing in to the IdP again, e.g. with MFA.
+func (p *samlIdentityProvider) cachedAssertion() (string, bool) {
+	if !p.config.AssertionCache {
+		return "", false
+	}
+
+	var samlAssertion string
+	found, err := secrets.NewEncryptedCache(secrets.Default(), assertionReuseMargin).Get(p.assertionKey(), &samlAssertion)
+	if err != nil {
+		p.logger.Debugw("failed to get cached saml assertion", "error", err.Error())
+		return "", false
+	}
+	if !found || samlAssertion == "" {
+		return "", false
+	}
+	p.logger.Info("reusing the saml assertion from the last sign in to the idp")
+
+	return samlAssertion, true
+}
+
+// signIn gets a SAML assertion from the IdP and caches it until it expires
+func (p *samlIdentityProvider) signIn(ctx context.Context, account *cfg.IDPAccount) (string, error) {
+	samlAssertion, err := p.getAssertion(ctx, account)
+	if err != nil {
+		return "", err
+	}
+	if samlAssertion == "" {
+		return "", ErrNoSAMLAssertions
+	}
+
+	if p.config.AssertionCache {
+		if err := p.cacheAssertion(samlAssertion); err != nil {
+			p.logger.Debugw("failed to cache saml assertion", "error", err.Error())
+		}
+	}
+
+	return samlAssertion, nil
+}
+
+func (p *samlIdentityProvider) cacheAssertion(samlAssertion string) error {
+	expiry, err := assertion.Expiry(samlAssertion)
+	if err != nil {
+		return fmt.Errorf("getting saml assertion expiry: %w", err)
+	}
+	ttl := time.Until(expiry) - assertionReuseMargin
+	if ttl <= 0 {
+		return nil
+	}
+
+	return secrets.NewEncryptedCache(secrets.Default(), ttl).Set(p.assertionKey(), samlAssertion)
+}
+
+func (p *samlIdentityProvider) evictAssertion() {
+	if err := secrets.NewEncryptedCache(secrets.Default(), assertionReuseMargin).Delete(p.assertionKey()); err != nil {
+		p.logger.Debugw("failed to remove cached saml assertion", "error", err.Error())
+	}
+}
+
+// assertionKey is the cache key of the assertion for the IdP and user
+func (p *samlIdentityProvider) assertionKey() string {
+	return fmt.Sprintf("saml/assertions/%s/%s/%s", p.scopedToDiscovery, p.config.IdpEndpoint, p.config.Username)
+}
@@ -0,0 +1,70 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saml
+
+import (
+	"encoding/base64"
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+	"github.com/fidelity/kconnect/pkg/plugins/identity/saml/sp"
+	"github.com/fidelity/kconnect/pkg/secrets"
+)
+
+func TestAssertionCacheFileStore(t *testing.T) {
+	g := NewWithT(t)
+	t.Setenv("HOME", t.TempDir())
+	secrets.SetDefault(secrets.NewFileStore())
+	defer secrets.SetDefault(nil)
+
+	p := &samlIdentityProvider{
+		config: &sp.ProviderConfig{AssertionCache: true, IdpEndpoint: "https://idp.example.com"},
+		logger: zap.NewNop().Sugar(),
+	}
+	samlAssertion := base64.StdEncoding.EncodeToString([]byte(fmt.Sprintf(
+		`<samlp:Response xmlns:samlp="urn:oasis:names:tc:SAML:2.0:protocol"><saml:Conditions xmlns:saml="urn:oasis:names:tc:SAML:2.0:assertion" NotOnOrAfter="%s"/></samlp:Response>`,
+		time.Now().Add(time.Hour).UTC().Format(time.RFC3339))))
+
+	g.Expect(p.cacheAssertion(samlAssertion)).To(Succeed())
+	cached, found := p.cachedAssertion()
+	g.Expect(found).To(BeTrue())
+	g.Expect(cached).To(Equal(samlAssertion))
+
+	// The cache directory only has the encrypted assertion and the key isn't next to it
+	files, err := os.ReadDir(defaults.CacheDirectory())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(files).To(HaveLen(1))
+	data, err := os.ReadFile(filepath.Join(defaults.CacheDirectory(), files[0].Name()))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).NotTo(ContainSubstring(samlAssertion))
+
+	p.evictAssertion()
+	_, found = p.cachedAssertion()
+	g.Expect(found).To(BeFalse())
+
+	p.config.AssertionCache = false
+	g.Expect(p.cacheAssertion(samlAssertion)).To(Succeed())
+	_, found = p.cachedAssertion()
+	g.Expect(found).To(BeFalse())
+}
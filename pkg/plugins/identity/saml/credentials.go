@@ -0,0 +1,80 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saml
+
+import (
+	"errors"
+	"strings"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+const passwordConfigItem = "password"
+
+// credentialRejections are the messages in the errors of the saml2aws providers, and
+// the pages of the IdPs, when the credentials or the MFA challenge are rejected
+var credentialRejections = []struct {
+	code     kerrors.Code
+	messages []string
+}{
+	{
+		code:     kerrors.CodeAccountLocked,
+		messages: []string{"locked", "account is disabled"},
+	},
+	{
+		code:     kerrors.CodeMFADenied,
+		messages: []string{"mfa rejected", "did not accept mfa", "mfa denied", "push rejected", "verification rejected"},
+	},
+	{
+		code: kerrors.CodeInvalidCredentials,
+		messages: []string{
+			"incorrect user id or password",
+			"incorrect username or password",
+			"invalid username or password",
+			"invalid credentials",
+			"authentication failed",
+			"login failed",
+		},
+	},
+}
+
+// credentialError returns the error from saml2aws as a credential error if the IdP
+// rejected the password, the account is locked or the MFA challenge was denied. Only a
+// rejected password can be entered again.
+func credentialError(err error) error {
+	var credErr *identity.CredentialError
+	if err == nil || errors.As(err, &credErr) {
+		return err
+	}
+
+	message := strings.ToLower(err.Error())
+	for _, rejection := range credentialRejections {
+		for _, m := range rejection.messages {
+			if !strings.Contains(message, m) {
+				continue
+			}
+			if rejection.code == kerrors.CodeMFADenied {
+				return identity.NewCredentialError(rejection.code, err)
+			}
+
+			return identity.NewCredentialError(rejection.code, err, passwordConfigItem)
+		}
+	}
+
+	return err
+}
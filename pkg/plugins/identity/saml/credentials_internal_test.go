@@ -0,0 +1,69 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saml
+
+import (
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+func TestCredentialError(t *testing.T) {
+	testCases := []struct {
+		name     string
+		err      error
+		code     kerrors.Code
+		rejected []string
+	}{
+		{
+			name:     "bad password",
+			err:      errors.New("Login failed: Incorrect user ID or password"),
+			code:     kerrors.CodeInvalidCredentials,
+			rejected: []string{passwordConfigItem},
+		},
+		{
+			name: "mfa denied",
+			err:  errors.New("error verifying MFA: MFA rejected by user"),
+			code: kerrors.CodeMFADenied,
+		},
+		{
+			name: "locked account",
+			err:  errors.New("Your account is locked, contact your administrator"),
+			code: kerrors.CodeAccountLocked,
+		},
+		{
+			name: "other error",
+			err:  errors.New("failed to build document from response"),
+			code: kerrors.CodeUnknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := credentialError(tc.err)
+			g.Expect(errors.Is(err, tc.err)).To(BeTrue())
+			g.Expect(kerrors.CodeOf(err)).To(Equal(tc.code))
+			g.Expect(identity.RejectedItems(err)).To(Equal(tc.rejected))
+		})
+	}
+}
@@ -0,0 +1,209 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata
+
+import (
+	"crypto/x509"
+	"encoding/base64"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+	khttp "github.com/fidelity/kconnect/pkg/http"
+)
+
+const (
+	// BindingHTTPRedirect is the SAML binding where the request is sent in the query string
+	BindingHTTPRedirect = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect"
+	// BindingHTTPPost is the SAML binding where the request is sent in a form post
+	BindingHTTPPost = "urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST"
+
+	signingUse = "signing"
+)
+
+var (
+	ErrNoIdPDescriptor = errors.New("metadata has no identity provider descriptor")
+	ErrNoSSOEndpoint   = errors.New("metadata has no single sign-on endpoint with a supported binding")
+	ErrFetchFailed     = errors.New("fetching metadata failed")
+)
+
+// Metadata is the details of a SAML identity provider from its metadata document
+type Metadata struct {
+	// EntityID is the unique name of the identity provider
+	EntityID string
+	// SSOEndpoints are the single sign-on endpoints by binding
+	SSOEndpoints map[string]string
+	// Certificates are the certificates the identity provider signs with
+	Certificates []*x509.Certificate
+}
+
+type entityDescriptor struct {
+	XMLName           xml.Name
+	EntityID          string             `xml:"entityID,attr"`
+	IDPSSODescriptors []idpSSODescriptor `xml:"IDPSSODescriptor"`
+	EntityDescriptors []entityDescriptor `xml:"EntityDescriptor"`
+}
+
+type idpSSODescriptor struct {
+	KeyDescriptors      []keyDescriptor `xml:"KeyDescriptor"`
+	SingleSignOnService []endpoint      `xml:"SingleSignOnService"`
+}
+
+type keyDescriptor struct {
+	Use          string   `xml:"use,attr"`
+	Certificates []string `xml:"KeyInfo>X509Data>X509Certificate"`
+}
+
+type endpoint struct {
+	Binding  string `xml:"Binding,attr"`
+	Location string `xml:"Location,attr"`
+}
+
+// Fetch will get and parse the metadata document of the identity provider
+func Fetch(client khttp.Client, metadataURL string) (*Metadata, error) {
+	resp, err := client.Get(metadataURL, defaults.Headers())
+	if err != nil {
+		return nil, fmt.Errorf("getting saml metadata from %s: %w", metadataURL, err)
+	}
+	if resp.ResponseCode() != http.StatusOK {
+		return nil, fmt.Errorf("getting saml metadata from %s, status code %d: %w", metadataURL, resp.ResponseCode(), ErrFetchFailed)
+	}
+
+	return Parse([]byte(resp.Body()))
+}
+
+// Parse will parse a SAML metadata document. The document can be an EntityDescriptor or
+// an EntitiesDescriptor, in which case the first identity provider is used.
+func Parse(data []byte) (*Metadata, error) {
+	root := &entityDescriptor{}
+	if err := xml.Unmarshal(data, root); err != nil {
+		return nil, fmt.Errorf("unmarshalling saml metadata: %w", err)
+	}
+
+	entity := findIdP(root)
+	if entity == nil {
+		return nil, ErrNoIdPDescriptor
+	}
+
+	metadata := &Metadata{
+		EntityID:     entity.EntityID,
+		SSOEndpoints: map[string]string{},
+	}
+	for _, descriptor := range entity.IDPSSODescriptors {
+		for _, sso := range descriptor.SingleSignOnService {
+			if _, ok := metadata.SSOEndpoints[sso.Binding]; !ok {
+				metadata.SSOEndpoints[sso.Binding] = sso.Location
+			}
+		}
+		for _, key := range descriptor.KeyDescriptors {
+			// A key without a use is used for both signing and encryption
+			if key.Use != "" && key.Use != signingUse {
+				continue
+			}
+			for _, encoded := range key.Certificates {
+				cert, err := parseCertificate(encoded)
+				if err != nil {
+					return nil, err
+				}
+				metadata.Certificates = append(metadata.Certificates, cert)
+			}
+		}
+	}
+
+	return metadata, nil
+}
+
+// SSOEndpoint returns the single sign-on endpoint to log in with. The redirect binding
+// is preferred as it can be opened in the browser.
+func (m *Metadata) SSOEndpoint() (string, error) {
+	for _, binding := range []string{BindingHTTPRedirect, BindingHTTPPost} {
+		if location, ok := m.SSOEndpoints[binding]; ok && location != "" {
+			return location, nil
+		}
+	}
+
+	return "", ErrNoSSOEndpoint
+}
+
+// IdpProvider returns the name of the saml2aws provider for the identity provider based
+// on its single sign-on endpoint. An empty name is returned if it isn't recognised.
+func (m *Metadata) IdpProvider() string {
+	location, err := m.SSOEndpoint()
+	if err != nil {
+		return ""
+	}
+	endpointURL, err := url.Parse(location)
+	if err != nil {
+		return ""
+	}
+	host := strings.ToLower(endpointURL.Hostname())
+	endpointPath := strings.ToLower(endpointURL.Path)
+
+	switch {
+	case host == "login.microsoftonline.com" || host == "sts.windows.net":
+		return "AzureAD"
+	case strings.HasSuffix(host, ".okta.com") || strings.HasSuffix(host, ".oktapreview.com"):
+		return "Okta"
+	case strings.HasSuffix(host, ".onelogin.com"):
+		return "OneLogin"
+	case host == "accounts.google.com":
+		return "GoogleApps"
+	case strings.HasSuffix(host, ".jumpcloud.com"):
+		return "JumpCloud"
+	case strings.HasPrefix(endpointPath, "/adfs/"):
+		return "ADFS"
+	case strings.Contains(endpointPath, "/realms/"):
+		return "KeyCloak"
+	case strings.HasPrefix(endpointPath, "/idp/profile/saml2/"):
+		return "Shibboleth"
+	case strings.HasSuffix(host, ".pingone.com") || strings.HasPrefix(endpointPath, "/idp/sso.saml2"):
+		return "Ping"
+	default:
+		return ""
+	}
+}
+
+// findIdP returns the first entity that is an identity provider
+func findIdP(entity *entityDescriptor) *entityDescriptor {
+	if len(entity.IDPSSODescriptors) > 0 {
+		return entity
+	}
+	for i := range entity.EntityDescriptors {
+		if found := findIdP(&entity.EntityDescriptors[i]); found != nil {
+			return found
+		}
+	}
+
+	return nil
+}
+
+func parseCertificate(encoded string) (*x509.Certificate, error) {
+	data, err := base64.StdEncoding.DecodeString(strings.Join(strings.Fields(encoded), ""))
+	if err != nil {
+		return nil, fmt.Errorf("decoding saml metadata certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing saml metadata certificate: %w", err)
+	}
+
+	return cert, nil
+}
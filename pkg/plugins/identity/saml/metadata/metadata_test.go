@@ -0,0 +1,154 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package metadata_test
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/plugins/identity/saml/metadata"
+)
+
+const idpMetadata = `<?xml version="1.0" encoding="UTF-8"?>
+<md:EntityDescriptor xmlns:md="urn:oasis:names:tc:SAML:2.0:metadata" entityID="https://sts.windows.net/tenant/">
+  <md:IDPSSODescriptor protocolSupportEnumeration="urn:oasis:names:tc:SAML:2.0:protocol">
+    <md:KeyDescriptor use="signing">
+      <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+        <ds:X509Data>
+          <ds:X509Certificate>%s</ds:X509Certificate>
+        </ds:X509Data>
+      </ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:KeyDescriptor use="encryption">
+      <ds:KeyInfo xmlns:ds="http://www.w3.org/2000/09/xmldsig#">
+        <ds:X509Data>
+          <ds:X509Certificate>not a certificate</ds:X509Certificate>
+        </ds:X509Data>
+      </ds:KeyInfo>
+    </md:KeyDescriptor>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-POST" Location="https://login.microsoftonline.com/tenant/saml2/post"/>
+    <md:SingleSignOnService Binding="urn:oasis:names:tc:SAML:2.0:bindings:HTTP-Redirect" Location="https://login.microsoftonline.com/tenant/saml2"/>
+  </md:IDPSSODescriptor>
+</md:EntityDescriptor>`
+
+const spMetadata = `<EntitiesDescriptor xmlns="urn:oasis:names:tc:SAML:2.0:metadata">
+  <EntityDescriptor entityID="urn:amazon:webservices">
+    <SPSSODescriptor/>
+  </EntityDescriptor>
+</EntitiesDescriptor>`
+
+func TestParse(t *testing.T) {
+	g := NewWithT(t)
+
+	cert := newCertificate(t)
+	md, err := metadata.Parse([]byte(fmt.Sprintf(idpMetadata, base64.StdEncoding.EncodeToString(cert.Raw))))
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(md.EntityID).To(Equal("https://sts.windows.net/tenant/"))
+	g.Expect(md.SSOEndpoints).To(HaveKeyWithValue(metadata.BindingHTTPPost, "https://login.microsoftonline.com/tenant/saml2/post"))
+	g.Expect(md.Certificates).To(HaveLen(1))
+	g.Expect(md.Certificates[0].Subject.CommonName).To(Equal("idp.example.com"))
+
+	endpoint, err := md.SSOEndpoint()
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(endpoint).To(Equal("https://login.microsoftonline.com/tenant/saml2"))
+	g.Expect(md.IdpProvider()).To(Equal("AzureAD"))
+
+	_, err = metadata.Parse([]byte(spMetadata))
+	g.Expect(errors.Is(err, metadata.ErrNoIdPDescriptor)).To(BeTrue())
+}
+
+func TestIdpProvider(t *testing.T) {
+	testCases := []struct {
+		endpoint string
+		expect   string
+	}{
+		{endpoint: "https://example.okta.com/app/amazon_aws/abc/sso/saml", expect: "Okta"},
+		{endpoint: "https://adfs.example.com/adfs/ls/", expect: "ADFS"},
+		{endpoint: "https://sso.example.com/auth/realms/corp/protocol/saml", expect: "KeyCloak"},
+		{endpoint: "https://idp.example.com/idp/profile/SAML2/Redirect/SSO", expect: "Shibboleth"},
+		{endpoint: "https://idp.example.com/login", expect: ""},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.expect, func(t *testing.T) {
+			g := NewWithT(t)
+
+			md := &metadata.Metadata{SSOEndpoints: map[string]string{metadata.BindingHTTPRedirect: tc.endpoint}} //nolint:scopelint
+			g.Expect(md.IdpProvider()).To(Equal(tc.expect))                                                      //nolint:scopelint
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	g := NewWithT(t)
+
+	cert := newCertificate(t)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/FederationMetadata.xml" {
+			http.NotFound(w, r)
+			return
+		}
+		fmt.Fprintf(w, idpMetadata, base64.StdEncoding.EncodeToString(cert.Raw))
+	}))
+	defer server.Close()
+
+	client := khttp.NewHTTPClient()
+	md, err := metadata.Fetch(client, server.URL+"/FederationMetadata.xml")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(md.EntityID).To(Equal("https://sts.windows.net/tenant/"))
+
+	_, err = metadata.Fetch(client, server.URL+"/missing.xml")
+	g.Expect(errors.Is(err, metadata.ErrFetchFailed)).To(BeTrue())
+}
+
+func newCertificate(t *testing.T) *x509.Certificate {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(1),
+		Subject:      pkix.Name{CommonName: "idp.example.com"},
+		NotBefore:    time.Now(),
+		NotAfter:     time.Now().Add(time.Hour),
+	}
+	data, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(data)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return cert
+}
@@ -0,0 +1,160 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saml
+
+import (
+	"context"
+	"strings"
+	"sync"
+
+	"github.com/versent/saml2aws/pkg/prompter"
+
+	"github.com/fidelity/kconnect/pkg/prompt"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+// mfaCodePrompts are the parts of the saml2aws prompts that ask for an MFA code
+var mfaCodePrompts = []string{"code", "passcode", "token", "pin", "yubikey"}
+
+// mfaPrompter is the saml2aws prompter while signing in with the form flow. The MFA codes
+// that the IdP asks for are got from the MFA handlers, so a registered handler, e.g. an
+// external plugin, can supply them. The other prompts, e.g. choosing the MFA option, are
+// kconnect prompts. The saml2aws prompts can't return an error, so the first error is
+// kept and returned by Err once the sign in has finished.
+type mfaPrompter struct {
+	ctx         context.Context
+	interactive bool
+
+	mu  sync.Mutex
+	err error
+}
+
+func newMFAPrompter(ctx context.Context, interactive bool) *mfaPrompter {
+	return &mfaPrompter{ctx: ctx, interactive: interactive}
+}
+
+// Err returns the first error from the prompts
+func (m *mfaPrompter) Err() error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	return m.err
+}
+
+func (m *mfaPrompter) setErr(err error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if m.err == nil {
+		m.err = err
+	}
+}
+
+// mfaCode gets the one-time code from the MFA handlers
+func (m *mfaPrompter) mfaCode(message string) string {
+	resp, err := identity.HandleMFA(m.ctx, &identity.MFAChallenge{
+		Method:      identity.MFAMethodTOTP,
+		Provider:    ProviderName,
+		Message:     message,
+		Interactive: m.interactive,
+	})
+	if err != nil {
+		m.setErr(err)
+		return ""
+	}
+
+	return resp.Code
+}
+
+// RequestSecurityCode asks for an MFA code in the format of the pattern, e.g. 000000
+func (m *mfaPrompter) RequestSecurityCode(pattern string) string {
+	return m.mfaCode("")
+}
+
+// ChooseWithDefault asks the user to choose an option, e.g. the MFA method
+func (m *mfaPrompter) ChooseWithDefault(message string, defaultValue string, options []string) (string, error) {
+	if !m.interactive {
+		return defaultValue, nil
+	}
+
+	return prompt.Choose("mfa-option", message, true, prompt.OptionsFromStringSlice(options))
+}
+
+// Choose asks the user to choose an option, e.g. the MFA method, and returns its index
+func (m *mfaPrompter) Choose(message string, options []string) int {
+	if !m.interactive {
+		m.setErr(identity.ErrMFAInputRequired)
+		return 0
+	}
+
+	selected, err := prompt.Choose("mfa-option", message, true, prompt.OptionsFromStringSlice(options))
+	if err != nil {
+		m.setErr(err)
+		return 0
+	}
+	for i, option := range options {
+		if option == selected {
+			return i
+		}
+	}
+
+	return 0
+}
+
+// StringRequired asks for a value, the MFA codes are got from the MFA handlers
+func (m *mfaPrompter) StringRequired(message string) string {
+	return m.String(message, "")
+}
+
+// String asks for a value, the MFA codes are got from the MFA handlers
+func (m *mfaPrompter) String(message string, defaultValue string) string {
+	if isMFACodePrompt(message) {
+		return m.mfaCode(message)
+	}
+	if !m.interactive {
+		return defaultValue
+	}
+
+	value, err := prompt.Input("saml-input", message, defaultValue == "")
+	if err != nil {
+		m.setErr(err)
+		return ""
+	}
+	if value == "" {
+		return defaultValue
+	}
+
+	return value
+}
+
+// Password asks for a secret, e.g. the OTP of a YubiKey, which is an MFA code
+func (m *mfaPrompter) Password(message string) string {
+	return m.mfaCode(message)
+}
+
+func isMFACodePrompt(message string) bool {
+	message = strings.ToLower(message)
+	for _, part := range mfaCodePrompts {
+		if strings.Contains(message, part) {
+			return true
+		}
+	}
+
+	return false
+}
+
+var _ prompter.Prompter = &mfaPrompter{}
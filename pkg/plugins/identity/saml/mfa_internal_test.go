@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saml
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/prompt"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+func TestMFAPrompter(t *testing.T) {
+	g := NewWithT(t)
+
+	out := &bytes.Buffer{}
+	prompt.SetBackend(prompt.NewJSONBackend(strings.NewReader(`{"value":"TOTP MFA authentication"}
+{"value":"123456"}
+{"value":"654321"}
+{"value":"abc"}
+`), out))
+	defer func() {
+		backend, _ := prompt.NewBackend(prompt.BackendTerminal, nil, nil)
+		prompt.SetBackend(backend)
+	}()
+
+	mfa := newMFAPrompter(context.Background(), true)
+	g.Expect(mfa.Choose("Select which MFA option to use", []string{"PUSH MFA authentication", "TOTP MFA authentication"})).To(Equal(1))
+	g.Expect(mfa.RequestSecurityCode("000000")).To(Equal("123456"))
+	g.Expect(mfa.StringRequired("Enter verification code")).To(Equal("654321"))
+	g.Expect(mfa.String("Captcha", "")).To(Equal("abc"))
+	g.Expect(mfa.Err()).NotTo(HaveOccurred())
+
+	// The codes are asked for by the prompt MFA handler
+	g.Expect(out.String()).To(ContainSubstring(`"name":"mfa-option"`))
+	g.Expect(strings.Count(out.String(), `"name":"mfa-token"`)).To(Equal(2))
+	g.Expect(out.String()).To(ContainSubstring(`"name":"saml-input","message":"Captcha"`))
+}
+
+func TestMFAPrompterNonInteractive(t *testing.T) {
+	g := NewWithT(t)
+
+	mfa := newMFAPrompter(context.Background(), false)
+	g.Expect(mfa.RequestSecurityCode("000000")).To(BeEmpty())
+	g.Expect(mfa.String("Enter passcode", "")).To(BeEmpty())
+	g.Expect(errors.Is(mfa.Err(), identity.ErrMFAInputRequired)).To(BeTrue())
+}
@@ -0,0 +1,229 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saml
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+const (
+	oktaProvider       = "Okta"
+	oktaAuthnPath      = "/api/v1/authn"
+	oktaFactorWebAuthn = "webauthn"
+
+	oktaStatusSuccess      = "SUCCESS"
+	oktaStatusMFARequired  = "MFA_REQUIRED"
+	oktaStatusMFAChallenge = "MFA_CHALLENGE"
+	oktaStatusLockedOut    = "LOCKED_OUT"
+)
+
+var (
+	ErrOktaAuthentication     = errors.New("okta authentication failed")
+	ErrOktaLockedOut          = errors.New("okta account is locked out")
+	ErrOktaUnexpectedResponse = errors.New("unexpected okta authentication response")
+)
+
+// oktaTransaction is the state of an Okta authentication transaction
+type oktaTransaction struct {
+	StateToken string `json:"stateToken"`
+	Status     string `json:"status"`
+	Embedded   struct {
+		Factors []oktaFactor `json:"factors"`
+		Factor  oktaFactor   `json:"factor"`
+	} `json:"_embedded"`
+	Links struct {
+		Next oktaLink `json:"next"`
+	} `json:"_links"`
+}
+
+type oktaFactor struct {
+	FactorType string `json:"factorType"`
+	Profile    struct {
+		CredentialID string `json:"credentialId"`
+	} `json:"profile"`
+	Embedded struct {
+		Challenge struct {
+			Challenge        string `json:"challenge"`
+			UserVerification string `json:"userVerification"`
+		} `json:"challenge"`
+	} `json:"_embedded"`
+	Links struct {
+		Verify oktaLink `json:"verify"`
+	} `json:"_links"`
+}
+
+type oktaLink struct {
+	Href string `json:"href"`
+}
+
+// oktaWebAuthn signs in to Okta with the authentication API when a WebAuthn factor is
+// required and completes the challenge with the MFA handlers, as saml2aws can only sign
+// it with a U2F key itself. The state token of the transaction is returned so that
+// saml2aws resumes it to get the assertion, it's empty if Okta doesn't require MFA.
+func (p *samlIdentityProvider) oktaWebAuthn(ctx context.Context) (string, error) {
+	idpURL, err := url.Parse(p.config.IdpEndpoint)
+	if err != nil {
+		return "", fmt.Errorf("parsing idp endpoint: %w", err)
+	}
+	org := &url.URL{Scheme: idpURL.Scheme, Host: idpURL.Host}
+
+	txn, err := p.oktaPost(ctx, org.String()+oktaAuthnPath, map[string]string{
+		"username": p.config.Username,
+		"password": p.config.Password,
+	})
+	if errors.Is(err, ErrOktaAuthentication) {
+		return "", identity.NewCredentialError(kerrors.CodeInvalidCredentials, err, passwordConfigItem)
+	}
+	if err != nil {
+		return "", err
+	}
+	if txn.Status == oktaStatusLockedOut {
+		return "", identity.NewCredentialError(kerrors.CodeAccountLocked, ErrOktaLockedOut, passwordConfigItem)
+	}
+	if txn.Status != oktaStatusMFARequired {
+		return txn.StateToken, nil
+	}
+
+	var factor *oktaFactor
+	for i := range txn.Embedded.Factors {
+		if txn.Embedded.Factors[i].FactorType == oktaFactorWebAuthn {
+			factor = &txn.Embedded.Factors[i]
+			break
+		}
+	}
+	if factor == nil {
+		return txn.StateToken, nil
+	}
+
+	challengeTxn, err := p.oktaPost(ctx, factor.Links.Verify.Href, map[string]string{"stateToken": txn.StateToken})
+	if err != nil {
+		return "", err
+	}
+	if challengeTxn.Status != oktaStatusMFAChallenge {
+		return "", fmt.Errorf("verifying webauthn factor, status %s: %w", challengeTxn.Status, ErrOktaUnexpectedResponse)
+	}
+	challenge, err := oktaWebAuthnChallenge(org, &challengeTxn.Embedded.Factor)
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := identity.HandleMFA(ctx, &identity.MFAChallenge{
+		Method:      identity.MFAMethodWebAuthn,
+		Provider:    ProviderName,
+		Interactive: p.interactive,
+		WebAuthn:    challenge,
+	})
+	if errors.Is(err, identity.ErrMFAMethodUnsupported) {
+		p.logger.Debug("no mfa handler for the okta webauthn factor, leaving it to saml2aws")
+		return txn.StateToken, nil
+	}
+	if err != nil {
+		return "", err
+	}
+	if resp.WebAuthn == nil {
+		return "", identity.ErrNoWebAuthnChallenge
+	}
+
+	verifiedTxn, err := p.oktaPost(ctx, challengeTxn.Links.Next.Href, map[string]string{
+		"stateToken":        txn.StateToken,
+		"clientData":        base64.RawURLEncoding.EncodeToString(resp.WebAuthn.ClientDataJSON),
+		"authenticatorData": base64.RawURLEncoding.EncodeToString(resp.WebAuthn.AuthenticatorData),
+		"signatureData":     base64.RawURLEncoding.EncodeToString(resp.WebAuthn.Signature),
+	})
+	if errors.Is(err, ErrOktaAuthentication) {
+		return "", identity.NewCredentialError(kerrors.CodeMFADenied, err)
+	}
+	if err != nil {
+		return "", err
+	}
+	if verifiedTxn.Status != oktaStatusSuccess {
+		return "", fmt.Errorf("verifying webauthn challenge, status %s: %w", verifiedTxn.Status, ErrOktaUnexpectedResponse)
+	}
+
+	return txn.StateToken, nil
+}
+
+// oktaWebAuthnChallenge creates the challenge to sign from the WebAuthn factor. The
+// relying party is the Okta org.
+func oktaWebAuthnChallenge(org *url.URL, factor *oktaFactor) (*identity.WebAuthnChallenge, error) {
+	challenge, err := decodeBase64URL(factor.Embedded.Challenge.Challenge)
+	if err != nil {
+		return nil, fmt.Errorf("decoding webauthn challenge: %w", err)
+	}
+	credentialID, err := decodeBase64URL(factor.Profile.CredentialID)
+	if err != nil {
+		return nil, fmt.Errorf("decoding webauthn credential id: %w", err)
+	}
+
+	return &identity.WebAuthnChallenge{
+		RelyingPartyID:   org.Hostname(),
+		Challenge:        challenge,
+		CredentialIDs:    [][]byte{credentialID},
+		Origin:           org.String(),
+		UserVerification: factor.Embedded.Challenge.UserVerification,
+	}, nil
+}
+
+func (p *samlIdentityProvider) oktaPost(ctx context.Context, endpoint string, body map[string]string) (*oktaTransaction, error) {
+	data, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling okta request: %w", err)
+	}
+	reqBody := string(data)
+
+	resp, err := p.httpClient.Do(&khttp.ClientRequest{
+		URL:     endpoint,
+		Method:  http.MethodPost,
+		Body:    &reqBody,
+		Headers: defaults.Headers(defaults.WithJSON()),
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("posting to okta: %w", err)
+	}
+	switch resp.ResponseCode() {
+	case http.StatusOK:
+	case http.StatusUnauthorized, http.StatusForbidden:
+		return nil, fmt.Errorf("okta status code %d: %w", resp.ResponseCode(), ErrOktaAuthentication)
+	default:
+		return nil, fmt.Errorf("okta status code %d: %w", resp.ResponseCode(), ErrOktaUnexpectedResponse)
+	}
+
+	txn := &oktaTransaction{}
+	if err := json.Unmarshal([]byte(resp.Body()), txn); err != nil {
+		return nil, fmt.Errorf("unmarshalling okta response: %w", err)
+	}
+
+	return txn, nil
+}
+
+// decodeBase64URL decodes the base64url values of Okta, which may or may not be padded
+func decodeBase64URL(value string) ([]byte, error) {
+	return base64.RawURLEncoding.DecodeString(strings.TrimRight(value, "="))
+}
@@ -0,0 +1,141 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package saml
+
+import (
+	"context"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/plugins/identity/saml/sp"
+	"github.com/fidelity/kconnect/pkg/provider/common"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+// testWebAuthnHandler signs webauthn challenges in place of a security key
+type testWebAuthnHandler struct {
+	challenge *identity.MFAChallenge
+}
+
+func (h *testWebAuthnHandler) Supports(method identity.MFAMethod) bool {
+	return method == identity.MFAMethodWebAuthn
+}
+
+func (h *testWebAuthnHandler) HandleMFA(ctx context.Context, challenge *identity.MFAChallenge) (*identity.MFAResponse, error) {
+	h.challenge = challenge
+
+	return &identity.MFAResponse{
+		WebAuthn: &identity.WebAuthnAssertion{
+			CredentialID:      challenge.WebAuthn.CredentialIDs[0],
+			AuthenticatorData: []byte("authdata"),
+			ClientDataJSON:    []byte(`{"type":"webauthn.get"}`),
+			Signature:         []byte("signature"),
+		},
+	}, nil
+}
+
+func TestOktaWebAuthn(t *testing.T) {
+	g := NewWithT(t)
+
+	handler := &testWebAuthnHandler{}
+	identity.RegisterMFAHandler("test-webauthn", handler)
+
+	var verified map[string]string
+	mux := http.NewServeMux()
+	server := httptest.NewServer(mux)
+	defer server.Close()
+
+	mux.HandleFunc("/api/v1/authn", func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]string{}
+		g.Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+		switch body["password"] {
+		case "secret":
+		case "locked":
+			fmt.Fprint(w, `{"status":"LOCKED_OUT"}`)
+			return
+		default:
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		fmt.Fprintf(w, `{"stateToken":"state1","status":"MFA_REQUIRED","_embedded":{"factors":[
+			{"factorType":"token:software:totp","_links":{"verify":{"href":"%[1]s/api/v1/authn/factors/totp/verify"}}},
+			{"factorType":"webauthn","_links":{"verify":{"href":"%[1]s/api/v1/authn/factors/fwf1/verify"}}}]}}`, server.URL)
+	})
+	mux.HandleFunc("/api/v1/authn/factors/fwf1/verify", func(w http.ResponseWriter, r *http.Request) {
+		body := map[string]string{}
+		g.Expect(json.NewDecoder(r.Body).Decode(&body)).To(Succeed())
+		if body["signatureData"] == "" {
+			g.Expect(body["stateToken"]).To(Equal("state1"))
+			fmt.Fprintf(w, `{"stateToken":"state1","status":"MFA_CHALLENGE","_embedded":{"factor":{"factorType":"webauthn",
+				"profile":{"credentialId":"Y3JlZDE"},"_embedded":{"challenge":{"challenge":"Y2hhbGxlbmdl","userVerification":"preferred"}}}},
+				"_links":{"next":{"href":"%s/api/v1/authn/factors/fwf1/verify?rememberDevice=false"}}}`, server.URL)
+			return
+		}
+		verified = body
+		fmt.Fprint(w, `{"status":"SUCCESS","sessionToken":"session1"}`)
+	})
+
+	serverURL, err := url.Parse(server.URL)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	p := &samlIdentityProvider{
+		config: &sp.ProviderConfig{
+			IdentityProviderConfig: common.IdentityProviderConfig{Username: "bob", Password: "secret"},
+			IdpEndpoint:            server.URL + "/home/amazon_aws/0oa1/272",
+		},
+		interactive: true,
+		logger:      zap.NewNop().Sugar(),
+		httpClient:  khttp.NewHTTPClient(),
+	}
+
+	stateToken, err := p.oktaWebAuthn(context.Background())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(stateToken).To(Equal("state1"))
+
+	g.Expect(handler.challenge).NotTo(BeNil())
+	g.Expect(handler.challenge.Provider).To(Equal(ProviderName))
+	g.Expect(handler.challenge.WebAuthn.RelyingPartyID).To(Equal(serverURL.Hostname()))
+	g.Expect(handler.challenge.WebAuthn.Origin).To(Equal(server.URL))
+	g.Expect(handler.challenge.WebAuthn.Challenge).To(Equal([]byte("challenge")))
+	g.Expect(handler.challenge.WebAuthn.CredentialIDs).To(Equal([][]byte{[]byte("cred1")}))
+
+	g.Expect(verified).To(HaveKeyWithValue("stateToken", "state1"))
+	g.Expect(verified).To(HaveKeyWithValue("signatureData", base64.RawURLEncoding.EncodeToString([]byte("signature"))))
+	g.Expect(verified).To(HaveKeyWithValue("authenticatorData", base64.RawURLEncoding.EncodeToString([]byte("authdata"))))
+	g.Expect(verified).To(HaveKeyWithValue("clientData", base64.RawURLEncoding.EncodeToString([]byte(`{"type":"webauthn.get"}`))))
+
+	p.config.Password = "wrong"
+	_, err = p.oktaWebAuthn(context.Background())
+	g.Expect(err).To(MatchError(ErrOktaAuthentication))
+	g.Expect(kerrors.CodeOf(err)).To(Equal(kerrors.CodeInvalidCredentials))
+	g.Expect(identity.RejectedItems(err)).To(Equal([]string{passwordConfigItem}))
+
+	p.config.Password = "locked"
+	_, err = p.oktaWebAuthn(context.Background())
+	g.Expect(kerrors.CodeOf(err)).To(Equal(kerrors.CodeAccountLocked))
+	g.Expect(identity.RejectedItems(err)).To(BeEmpty())
+}
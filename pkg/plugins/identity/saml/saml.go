@@ -20,15 +20,18 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strings"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/versent/saml2aws"
 	"github.com/versent/saml2aws/pkg/cfg"
 	"github.com/versent/saml2aws/pkg/creds"
+	"github.com/versent/saml2aws/pkg/prompter"
 	"go.uber.org/zap"
 
 	kaws "github.com/fidelity/kconnect/pkg/aws"
 	"github.com/fidelity/kconnect/pkg/config"
+	khttp "github.com/fidelity/kconnect/pkg/http"
 	"github.com/fidelity/kconnect/pkg/plugins/identity/saml/sp"
 	"github.com/fidelity/kconnect/pkg/plugins/identity/saml/sp/aws"
 	"github.com/fidelity/kconnect/pkg/provider"
@@ -46,6 +49,7 @@ var (
 const (
 	ProviderName   = "saml"
 	defaultSession = 3600
+	defaultACSPort = 35001
 )
 
 var (
@@ -59,7 +63,8 @@ func init() {
 			UsageExample:           "",
 			ConfigurationItemsFunc: ConfigurationItems,
 		},
-		CreateFunc: New,
+		CreateFunc:           New,
+		IdentitiesConfigItem: "role-arn",
 	}); err != nil {
 		zap.S().Fatalw("Failed to register SAML identity plugin", "error", err)
 	}
@@ -73,6 +78,7 @@ func New(input *provider.PluginCreationInput) (identity.Provider, error) {
 
 	return &samlIdentityProvider{
 		logger:            input.Logger,
+		httpClient:        input.HTTPClient,
 		interactive:       input.IsInteractice,
 		itemSelector:      input.ItemSelector,
 		scopedToDiscovery: *input.ScopedTo,
@@ -87,6 +93,7 @@ type samlIdentityProvider struct {
 	itemSelector provider.SelectItemFunc
 	interactive  bool
 	logger       *zap.SugaredLogger
+	httpClient   khttp.Client
 }
 
 // Name returns the name of the plugin
@@ -104,6 +111,9 @@ func (p *samlIdentityProvider) Authenticate(ctx context.Context, input *identity
 	}
 	p.serviceProvider = sp
 
+	if err := p.applyMetadata(input.ConfigSet); err != nil {
+		return nil, err
+	}
 	if err := p.resolveConfig(input.ConfigSet); err != nil {
 		return nil, err
 	}
@@ -120,32 +130,23 @@ func (p *samlIdentityProvider) Authenticate(ctx context.Context, input *identity
 		return nil, ErrCreatingAccount
 	}
 
-	err = account.Validate()
-	if err != nil {
-		return nil, fmt.Errorf("validating saml: %w", err)
-	}
-
-	client, err := saml2aws.NewSAMLClient(account)
-	if err != nil {
-		return nil, fmt.Errorf("creating saml client: %w", err)
-	}
-
-	loginDetails := &creds.LoginDetails{
-		Username: p.config.Username,
-		Password: p.config.Password,
-		URL:      p.config.IdpEndpoint,
-	}
-
-	samlAssertion, err := client.Authenticate(loginDetails)
-	if err != nil {
-		return nil, fmt.Errorf("authenticating: %w", err)
-	}
-
-	if samlAssertion == "" {
-		return nil, ErrNoSAMLAssertions
+	samlAssertion, cached := p.cachedAssertion()
+	if !cached {
+		if samlAssertion, err = p.signIn(ctx, account); err != nil {
+			return nil, err
+		}
 	}
 
 	userID, err := p.serviceProvider.ProcessAssertions(account, samlAssertion, input.ConfigSet)
+	if err != nil && cached {
+		// STS may not accept the assertion again, so sign in to the IdP for a new one
+		p.logger.Debugw("cached saml assertion not accepted, signing in again", "error", err.Error())
+		p.evictAssertion()
+		if samlAssertion, err = p.signIn(ctx, account); err != nil {
+			return nil, err
+		}
+		userID, err = p.serviceProvider.ProcessAssertions(account, samlAssertion, input.ConfigSet)
+	}
 	if err != nil {
 		return nil, fmt.Errorf("processing assertions for: %s: %w", p.scopedToDiscovery, err)
 	}
@@ -165,6 +166,57 @@ func (p *samlIdentityProvider) Authenticate(ctx context.Context, input *identity
 	}, nil
 }
 
+// getAssertion gets the SAML assertion from the IdP using the configured SAML flow
+func (p *samlIdentityProvider) getAssertion(ctx context.Context, account *cfg.IDPAccount) (string, error) {
+	if p.config.SAMLFlow == sp.SAMLFlowBrowser {
+		return p.browserAssertion(ctx)
+	}
+
+	return p.formAssertion(ctx, account)
+}
+
+// formAssertion gets the SAML assertion by submitting the username and password to the
+// login form of the IdP. The MFA challenges of the IdP are completed by the MFA handlers.
+func (p *samlIdentityProvider) formAssertion(ctx context.Context, account *cfg.IDPAccount) (string, error) {
+	if err := account.Validate(); err != nil {
+		return "", fmt.Errorf("validating saml: %w", err)
+	}
+
+	client, err := saml2aws.NewSAMLClient(account)
+	if err != nil {
+		return "", fmt.Errorf("creating saml client: %w", err)
+	}
+
+	loginDetails := &creds.LoginDetails{
+		Username: p.config.Username,
+		Password: p.config.Password,
+		URL:      p.config.IdpEndpoint,
+	}
+	if strings.EqualFold(account.Provider, oktaProvider) {
+		if loginDetails.StateToken, err = p.oktaWebAuthn(ctx); err != nil {
+			return "", fmt.Errorf("authenticating: %w", err)
+		}
+	}
+
+	mfa := newMFAPrompter(ctx, p.interactive)
+	prompter.SetPrompter(mfa)
+	samlAssertion, err := client.Authenticate(loginDetails)
+	if mfaErr := mfa.Err(); mfaErr != nil {
+		return "", fmt.Errorf("authenticating: %w", mfaErr)
+	}
+	if err != nil {
+		return "", fmt.Errorf("authenticating: %w", credentialError(err))
+	}
+
+	return samlAssertion, nil
+}
+
+// RestoreIdentity creates the identity from its JSON. The only service provider is
+// AWS so the identity is an AWS identity.
+func (p *samlIdentityProvider) RestoreIdentity(data []byte) (identity.Identity, error) {
+	return kaws.RestoreIdentity(data)
+}
+
 func (p *samlIdentityProvider) bindAndValidateConfig(cs config.ConfigurationSet) error {
 	spConfig := &sp.ProviderConfig{}
 
@@ -173,7 +225,13 @@ func (p *samlIdentityProvider) bindAndValidateConfig(cs config.ConfigurationSet)
 	}
 
 	validate := validator.New()
-	if err := validate.Struct(spConfig); err != nil {
+	var err error
+	if spConfig.SAMLFlow == sp.SAMLFlowBrowser {
+		err = validate.StructExcept(spConfig, sp.BrowserFlowExceptions("")...)
+	} else {
+		err = validate.Struct(spConfig)
+	}
+	if err != nil {
 		return fmt.Errorf("validating config struct: %w", err)
 	}
 
@@ -248,7 +306,16 @@ func ConfigurationItems(scopedToDiscovery string) (config.ConfigurationSet, erro
 	cs.String("idp-endpoint", "", "identity provider endpoint provided by your IT team") //nolint: errcheck
 	cs.String("idp-provider", "", "the name of the idp provider")                        //nolint: errcheck
 	cs.SetRequired("idp-endpoint")                                                       //nolint: errcheck
-	cs.SetRequired("idp-provider")                                                       //nolint: errcheck
+
+	cs.String(sp.MetadataURLConfigItem, "", "URL of the SAML metadata of the identity provider, the idp-endpoint and idp-provider are derived from it if they aren't set") //nolint: errcheck
+
+	cs.Enum(sp.SAMLFlowConfigItem, sp.SAMLFlowForm, []string{sp.SAMLFlowForm, sp.SAMLFlowBrowser}, "How to log in to the IdP, form submits the username and password to the IdP's login page and browser completes the login in the system browser, e.g. for MFA or conditional access pages") //nolint: errcheck
+	cs.Int(sp.ACSPortConfigItem, defaultACSPort, "The localhost port the IdP posts the SAML response to with the browser flow. The IdP must allow http://localhost:<port>/saml/acs as an assertion consumer service URL")                                                                      //nolint: errcheck
+	// The idp provider is only used to submit the login form
+	cs.SetRequiredWhen("idp-provider", config.Condition{Name: sp.SAMLFlowConfigItem, Value: sp.SAMLFlowForm})                                                                                                //nolint: errcheck
+	cs.SetHistoryIgnore(sp.ACSPortConfigItem)                                                                                                                                                                //nolint: errcheck
+	cs.Bool(sp.AssertionCacheConfigItem, true, "Keep the SAML assertion from the IdP, encrypted, until it expires and reuse it to get the credentials for other roles or accounts without signing in again") //nolint: errcheck
+	cs.SetHistoryIgnore(sp.AssertionCacheConfigItem)                                                                                                                                                         //nolint: errcheck
 
 	// get the service provider flags
 	sp, err := createServiceProvider(scopedToDiscovery, nil)
@@ -17,6 +17,7 @@ limitations under the License.
 package aws
 
 import (
+	"context"
 	"encoding/base64"
 	"errors"
 	"fmt"
@@ -26,9 +27,8 @@ import (
 	"github.com/go-playground/validator/v10"
 	"go.uber.org/zap"
 
-	"github.com/aws/aws-sdk-go/aws"
-	"github.com/aws/aws-sdk-go/aws/session"
-	"github.com/aws/aws-sdk-go/service/sts"
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/sts"
 
 	"github.com/versent/saml2aws"
 	"github.com/versent/saml2aws/pkg/awsconfig"
@@ -43,6 +43,10 @@ import (
 
 const (
 	responseTag = "Response"
+
+	// RolePrincipalConfigItem is the name of the config item for the ARN of the SAML provider
+	// in AWS that the role is assumed with. It's recorded in the history with the role ARN.
+	RolePrincipalConfigItem = "role-principal-arn"
 )
 
 var (
@@ -50,6 +54,7 @@ var (
 	ErrNoRolesFound           = errors.New("no aws roles found")
 	ErrNotAccounts            = errors.New("no accounts available")
 	ErrMissingResponseElement = errors.New("missing response element")
+	ErrRoleNotInAssertion     = errors.New("role not found in saml assertion")
 )
 
 type awsProviderConfig struct {
@@ -79,7 +84,7 @@ func (p *ServiceProvider) PopulateAccount(account *cfg.IDPAccount, cfg config.Co
 	if regionCfg == nil || regionCfg.Value.(string) == "" {
 		return ErrNoRegion
 	}
-	account.Region = regionCfg.Value.(string)
+	account.Region = kaws.PrimaryRegion(regionCfg.Value.(string))
 
 	roleCfg := cfg.Get("role-arn")
 	if roleCfg != nil || roleCfg.Value.(string) != "" {
@@ -115,14 +120,32 @@ func (p *ServiceProvider) ProcessAssertions(account *cfg.IDPAccount, samlAsserti
 		roleFilter = item.Value.(string)
 	}
 
-	role, err := p.resolveRole(awsRoles, samlAssertions, account, roleFilter)
-	if err != nil {
-		return nil, fmt.Errorf("resolving aws role: %w", err)
+	var role *saml2aws.AWSRole
+	if account.RoleARN != "" {
+		role = p.locateRole(awsRoles, account.RoleARN, cfg.ValueString(RolePrincipalConfigItem))
+		if role == nil {
+			roleCfg := cfg.Get("role-arn")
+			if roleCfg == nil || roleCfg.Source != config.ItemSourceHistory {
+				return nil, fmt.Errorf("locating role %s: %w", account.RoleARN, ErrRoleNotInAssertion)
+			}
+			// The role from the history may have been removed, so a role is selected again
+			p.logger.Warnw("role from the history not found in saml assertion, select a role", "role", account.RoleARN)
+			account.RoleARN = ""
+		}
+	}
+
+	if role == nil {
+		if role, err = p.resolveRole(awsRoles, samlAssertions, roleFilter); err != nil {
+			return nil, fmt.Errorf("resolving aws role: %w", err)
+		}
 	}
 
 	if err := cfg.SetValue("role-arn", role.RoleARN); err != nil {
 		return nil, fmt.Errorf("setting role-arn config value: %w", err)
 	}
+	if err := cfg.SetValue(RolePrincipalConfigItem, role.PrincipalARN); err != nil {
+		return nil, fmt.Errorf("setting %s config value: %w", RolePrincipalConfigItem, err)
+	}
 	p.logger.Debugw("role selected", "role", role.RoleARN)
 
 	awsCreds, err := p.loginToStsUsingRole(account, role, samlAssertions)
@@ -161,11 +184,8 @@ func (p *ServiceProvider) setProfileName(profileName string, cfg config.Configur
 	return nil
 }
 
-func (p *ServiceProvider) resolveRole(awsRoles []*saml2aws.AWSRole, samlAssertion string, account *cfg.IDPAccount, roleFilter string) (*saml2aws.AWSRole, error) {
+func (p *ServiceProvider) resolveRole(awsRoles []*saml2aws.AWSRole, samlAssertion string, roleFilter string) (*saml2aws.AWSRole, error) {
 	if len(awsRoles) == 1 {
-		if account.RoleARN != "" {
-			return saml2aws.LocateRole(awsRoles, account.RoleARN)
-		}
 		return awsRoles[0], nil
 	} else if len(awsRoles) == 0 {
 		return nil, ErrNoRolesFound
@@ -197,10 +217,6 @@ func (p *ServiceProvider) resolveRole(awsRoles []*saml2aws.AWSRole, samlAssertio
 
 	awsAccounts = p.filterAccounts(awsAccounts, roleFilter)
 
-	if account.RoleARN != "" {
-		return saml2aws.LocateRole(awsRoles, account.RoleARN)
-	}
-
 	role, err := p.getRoleFromPrompt(awsAccounts, roleFilter)
 	if err != nil {
 		return nil, fmt.Errorf("getting role: %w", err)
@@ -209,6 +225,21 @@ func (p *ServiceProvider) resolveRole(awsRoles []*saml2aws.AWSRole, samlAssertio
 	return role, nil
 }
 
+// locateRole finds the role in the roles from the assertion. The principal is only
+// matched if one is supplied.
+func (p *ServiceProvider) locateRole(awsRoles []*saml2aws.AWSRole, roleARN, principalARN string) *saml2aws.AWSRole {
+	for _, role := range awsRoles {
+		if role.RoleARN != roleARN {
+			continue
+		}
+		if principalARN == "" || role.PrincipalARN == principalARN {
+			return role
+		}
+	}
+
+	return nil
+}
+
 func (p *ServiceProvider) filterAccounts(accounts []*saml2aws.AWSAccount, roleFilter string) []*saml2aws.AWSAccount {
 	if roleFilter == "" {
 		return accounts
@@ -259,36 +290,35 @@ func (p *ServiceProvider) getRoleFromPrompt(accounts []*saml2aws.AWSAccount, rol
 }
 
 func (p *ServiceProvider) loginToStsUsingRole(account *cfg.IDPAccount, role *saml2aws.AWSRole, samlAssertion string) (*awsconfig.AWSCredentials, error) {
-	sess, err := session.NewSession(&aws.Config{
-		Region: &account.Region,
-	})
+	ctx := context.Background()
+	awsCfg, err := kaws.NewConfig(ctx, account.Region, "", "", "", "", nil)
 	if err != nil {
-		return nil, fmt.Errorf("creating aws session: %w", err)
+		return nil, fmt.Errorf("creating aws config: %w", err)
 	}
 
-	svc := sts.New(sess)
+	svc := kaws.NewSTSClient(awsCfg)
 
 	params := &sts.AssumeRoleWithSAMLInput{
 		PrincipalArn:    aws.String(role.PrincipalARN),
 		RoleArn:         aws.String(role.RoleARN),
 		SAMLAssertion:   aws.String(samlAssertion),
-		DurationSeconds: aws.Int64(int64(account.SessionDuration)),
+		DurationSeconds: aws.Int32(int32(account.SessionDuration)),
 	}
 
 	p.logger.Info("requesting AWS credentials using SAML")
 
-	resp, err := svc.AssumeRoleWithSAML(params)
+	resp, err := svc.AssumeRoleWithSAML(ctx, params)
 	if err != nil {
 		return nil, fmt.Errorf("retrieving STS credentials using SAML: %w", err)
 	}
 
 	return &awsconfig.AWSCredentials{
-		AWSAccessKey:     aws.StringValue(resp.Credentials.AccessKeyId),
-		AWSSecretKey:     aws.StringValue(resp.Credentials.SecretAccessKey),
-		AWSSessionToken:  aws.StringValue(resp.Credentials.SessionToken),
-		AWSSecurityToken: aws.StringValue(resp.Credentials.SessionToken),
-		PrincipalARN:     aws.StringValue(resp.AssumedRoleUser.Arn),
-		Expires:          resp.Credentials.Expiration.Local(),
+		AWSAccessKey:     aws.ToString(resp.Credentials.AccessKeyId),
+		AWSSecretKey:     aws.ToString(resp.Credentials.SecretAccessKey),
+		AWSSessionToken:  aws.ToString(resp.Credentials.SessionToken),
+		AWSSecurityToken: aws.ToString(resp.Credentials.SessionToken),
+		PrincipalARN:     aws.ToString(resp.AssumedRoleUser.Arn),
+		Expires:          aws.ToTime(resp.Credentials.Expiration).Local(),
 		Region:           account.Region,
 	}, nil
 }
@@ -331,7 +361,13 @@ func (p *ServiceProvider) Validate(configItems config.ConfigurationSet) error {
 	}
 
 	validate := validator.New()
-	if err := validate.Struct(cfg); err != nil {
+	var err error
+	if cfg.SAMLFlow == sp.SAMLFlowBrowser {
+		err = validate.StructExcept(cfg, sp.BrowserFlowExceptions("ProviderConfig")...)
+	} else {
+		err = validate.Struct(cfg)
+	}
+	if err != nil {
 		return fmt.Errorf("validating config struct: %w", err)
 	}
 
@@ -340,6 +376,8 @@ func (p *ServiceProvider) Validate(configItems config.ConfigurationSet) error {
 
 func (p *ServiceProvider) ConfigurationItems() config.ConfigurationSet {
 	cs := kaws.SharedConfig()
+	cs.String(RolePrincipalConfigItem, "", "ARN of the SAML provider in AWS to assume the role with") //nolint: errcheck
+	cs.SetHidden(RolePrincipalConfigItem)                                                             //nolint: errcheck
 
 	return cs
 }
@@ -22,6 +22,7 @@ import (
 	kaws "github.com/fidelity/kconnect/pkg/aws"
 	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/defaults"
+	"github.com/fidelity/kconnect/pkg/plugins/identity/saml/sp"
 	"github.com/fidelity/kconnect/pkg/prompt"
 )
 
@@ -30,9 +31,13 @@ import (
 func (p *ServiceProvider) ResolveConfiguration(cfg config.ConfigurationSet) error {
 	p.logger.Debug("resolving AWS identity configuration items")
 
-	// NOTE: resolution is only needed for required fields
-	if err := p.resolveIdpProvider("idp-provider", cfg); err != nil {
-		return fmt.Errorf("resolving idp-provider: %w", err)
+	// NOTE: resolution is only needed for required fields. The idp provider, username and
+	// password aren't needed when the login is completed in the browser.
+	browserFlow := cfg.ValueString(sp.SAMLFlowConfigItem) == sp.SAMLFlowBrowser
+	if !browserFlow {
+		if err := p.resolveIdpProvider("idp-provider", cfg); err != nil {
+			return fmt.Errorf("resolving idp-provider: %w", err)
+		}
 	}
 	if err := p.resolveIdpEndpoint("idp-endpoint", cfg); err != nil {
 		return fmt.Errorf("resolving idp-endpoint: %w", err)
@@ -44,6 +49,9 @@ func (p *ServiceProvider) ResolveConfiguration(cfg config.ConfigurationSet) erro
 	if err := kaws.ResolveRegion(cfg); err != nil {
 		return fmt.Errorf("resolving region: %w", err)
 	}
+	if browserFlow {
+		return nil
+	}
 	if err := prompt.InputAndSet(cfg, defaults.UsernameConfigItem, "Username:", true); err != nil {
 		return fmt.Errorf("resolving %s: %w", defaults.UsernameConfigItem, err)
 	}
@@ -24,10 +24,49 @@ import (
 	"github.com/fidelity/kconnect/pkg/provider/identity"
 )
 
+const (
+	// SAMLFlowConfigItem is the name of the config item for how the SAML response is got from the IdP
+	SAMLFlowConfigItem = "saml-flow"
+	// ACSPortConfigItem is the name of the config item for the port the SAML response is posted to
+	ACSPortConfigItem = "saml-acs-port"
+	// MetadataURLConfigItem is the name of the config item for the URL of the IdP's SAML metadata
+	MetadataURLConfigItem = "idp-metadata-url"
+	// AssertionCacheConfigItem is the name of the config item for reusing the SAML assertion
+	// until it expires
+	AssertionCacheConfigItem = "saml-assertion-cache"
+
+	// SAMLFlowForm logs in by submitting the username and password to the login form of the IdP
+	SAMLFlowForm = "form"
+	// SAMLFlowBrowser logs in using the system browser, the IdP posts the SAML response to
+	// an assertion consumer service listening on localhost
+	SAMLFlowBrowser = "browser"
+)
+
 type ProviderConfig struct {
 	common.IdentityProviderConfig
 	IdpEndpoint string `json:"idp-endpoint" validate:"required"`
 	IdpProvider string `json:"idp-provider" validate:"required"`
+	SAMLFlow    string `json:"saml-flow"`
+	ACSPort     int    `json:"saml-acs-port"`
+	// AssertionCache reuses the SAML assertion until it expires
+	AssertionCache bool `json:"saml-assertion-cache"`
+}
+
+// BrowserFlowExceptions returns the fields of the ProviderConfig that aren't needed, and
+// so aren't validated, when the login is completed in the browser. The namespace is the
+// path to the ProviderConfig in the struct that's validated, if it's embedded.
+func BrowserFlowExceptions(namespace string) []string {
+	fields := []string{"IdentityProviderConfig.Username", "IdentityProviderConfig.Password", "IdpProvider"}
+	if namespace == "" {
+		return fields
+	}
+
+	exceptions := make([]string, len(fields))
+	for i, field := range fields {
+		exceptions[i] = namespace + "." + field
+	}
+
+	return exceptions
 }
 
 type ServiceProvider interface {
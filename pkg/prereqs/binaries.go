@@ -0,0 +1,133 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prereqs
+
+import "fmt"
+
+const (
+	// maxBinarySize is the largest binary that will be extracted from an archive
+	maxBinarySize = 200 * 1024 * 1024
+
+	awsIAMAuthenticatorName       = "aws-iam-authenticator"
+	awsIAMAuthenticatorVersion    = "0.5.2"
+	awsIAMAuthenticatorMinVersion = "v0.5.0"
+
+	kubeloginName       = "kubelogin"
+	kubeloginVersion    = "0.0.8"
+	kubeloginMinVersion = "v0.0.8"
+
+	kubectlMinVersion = "v1.17.0"
+)
+
+// The SHA-256 of each download is pinned, rather than read from a checksums file in the
+// same release, so that a release that has been tampered with isn't installed. They must
+// be updated from the published checksums along with the versions.
+const (
+	awsIAMAuthenticatorLinuxAMD64SHA256   = ""
+	awsIAMAuthenticatorLinuxARM64SHA256   = ""
+	awsIAMAuthenticatorDarwinAMD64SHA256  = ""
+	awsIAMAuthenticatorWindowsAMD64SHA256 = ""
+
+	kubeloginLinuxAMD64SHA256   = ""
+	kubeloginDarwinAMD64SHA256  = ""
+	kubeloginWindowsAMD64SHA256 = ""
+)
+
+// AWSIAMAuthenticator is the pre-requisite for aws-iam-authenticator which is
+// used by the kubeconfig for EKS clusters
+func AWSIAMAuthenticator() *BinaryPreReq {
+	baseURL := fmt.Sprintf("https://github.com/kubernetes-sigs/aws-iam-authenticator/releases/download/v%s", awsIAMAuthenticatorVersion)
+	binary := func(platform, sha256 string) *download {
+		return &download{
+			url:    fmt.Sprintf("%s/aws-iam-authenticator_%s_%s", baseURL, awsIAMAuthenticatorVersion, platform),
+			sha256: sha256,
+		}
+	}
+
+	return &BinaryPreReq{
+		name:        awsIAMAuthenticatorName,
+		minVersion:  awsIAMAuthenticatorMinVersion,
+		versionArgs: []string{"version"},
+		help:        "install aws-iam-authenticator (https://github.com/kubernetes-sigs/aws-iam-authenticator) or use --install-prereqs",
+		install: &installSpec{
+			version: awsIAMAuthenticatorVersion,
+			downloads: map[string]*download{
+				"linux/amd64":   binary("linux_amd64", awsIAMAuthenticatorLinuxAMD64SHA256),
+				"linux/arm64":   binary("linux_arm64", awsIAMAuthenticatorLinuxARM64SHA256),
+				"darwin/amd64":  binary("darwin_amd64", awsIAMAuthenticatorDarwinAMD64SHA256),
+				"windows/amd64": binary("windows_amd64.exe", awsIAMAuthenticatorWindowsAMD64SHA256),
+			},
+		},
+	}
+}
+
+// Kubelogin is the pre-requisite for kubelogin which is used by the kubeconfig
+// for AKS clusters that use Azure AD
+func Kubelogin() *BinaryPreReq {
+	baseURL := fmt.Sprintf("https://github.com/Azure/kubelogin/releases/download/v%s", kubeloginVersion)
+	archive := func(platform, archivePath, sha256 string) *download {
+		return &download{
+			url:         fmt.Sprintf("%s/kubelogin-%s.zip", baseURL, platform),
+			sha256:      sha256,
+			archivePath: archivePath,
+		}
+	}
+
+	return &BinaryPreReq{
+		name:        kubeloginName,
+		minVersion:  kubeloginMinVersion,
+		versionArgs: []string{"--version"},
+		help:        "install kubelogin (https://github.com/Azure/kubelogin) or use --install-prereqs",
+		install: &installSpec{
+			version: kubeloginVersion,
+			downloads: map[string]*download{
+				"linux/amd64":   archive("linux-amd64", "bin/linux_amd64/kubelogin", kubeloginLinuxAMD64SHA256),
+				"darwin/amd64":  archive("darwin-amd64", "bin/darwin_amd64/kubelogin", kubeloginDarwinAMD64SHA256),
+				"windows/amd64": archive("win-amd64", "bin/windows_amd64/kubelogin.exe", kubeloginWindowsAMD64SHA256),
+			},
+		},
+	}
+}
+
+// Kubectl is the pre-requisite for kubectl which uses the generated kubeconfig
+func Kubectl() *BinaryPreReq {
+	return &BinaryPreReq{
+		name:        "kubectl",
+		minVersion:  kubectlMinVersion,
+		versionArgs: []string{"version", "--client"},
+		help:        "install kubectl (https://kubernetes.io/docs/tasks/tools/)",
+	}
+}
+
+// AWSCLI is the pre-requisite for the AWS CLI which can be used by the kubeconfig for
+// EKS clusters instead of aws-iam-authenticator
+func AWSCLI() *BinaryPreReq {
+	return &BinaryPreReq{
+		name:        "aws",
+		versionArgs: []string{"--version"},
+		help:        "install the AWS CLI (https://aws.amazon.com/cli/)",
+	}
+}
+
+// AzureCLI is the pre-requisite for the Azure CLI which is used by the azurecli login type
+func AzureCLI() *BinaryPreReq {
+	return &BinaryPreReq{
+		name:        "az",
+		versionArgs: []string{"version"},
+		help:        "install the Azure CLI (https://docs.microsoft.com/cli/azure/install-azure-cli)",
+	}
+}
@@ -0,0 +1,199 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prereqs
+
+import (
+	"archive/tar"
+	"archive/zip"
+	"bufio"
+	"bytes"
+	"compress/gzip"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+
+	khttp "github.com/fidelity/kconnect/pkg/http"
+)
+
+var (
+	ErrUnsupportedPlatform = errors.New("no download available for platform")
+	ErrChecksumNotFound    = errors.New("checksum not found")
+	ErrChecksumMismatch    = errors.New("checksum of download doesn't match")
+	ErrDownloadFailed      = errors.New("download failed")
+	ErrFileNotInArchive    = errors.New("file not found in archive")
+)
+
+// installSpec describes where to download a binary from for each platform
+type installSpec struct {
+	version   string
+	downloads map[string]*download
+}
+
+// download is the location of a binary for a platform and its pinned SHA-256. If
+// archivePath is set the download is a zip archive containing the binary at that path.
+type download struct {
+	url         string
+	sha256      string
+	archivePath string
+}
+
+func (s *installSpec) install(ctx context.Context, name, dir string) (string, error) {
+	platform := runtime.GOOS + "/" + runtime.GOARCH
+	dl, ok := s.downloads[platform]
+	if !ok {
+		return "", fmt.Errorf("installing %s %s on %s: %w", name, s.version, platform, ErrUnsupportedPlatform)
+	}
+
+	if dl.sha256 == "" {
+		return "", fmt.Errorf("installing %s %s on %s, no pinned checksum: %w", name, s.version, platform, ErrChecksumNotFound)
+	}
+	data, err := Fetch(ctx, dl.url)
+	if err != nil {
+		return "", err
+	}
+	if err := verifySHA256(data, dl.sha256); err != nil {
+		return "", fmt.Errorf("verifying %s: %w", dl.url, err)
+	}
+
+	if dl.archivePath != "" {
+		data, err = ExtractFromZip(data, dl.archivePath)
+		if err != nil {
+			return "", fmt.Errorf("extracting %s: %w", name, err)
+		}
+	}
+
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return "", fmt.Errorf("creating bin directory %s: %w", dir, err)
+	}
+	binPath := filepath.Join(dir, binaryFileName(name))
+	if err := ioutil.WriteFile(binPath, data, 0755); err != nil { //nolint: gosec
+		return "", fmt.Errorf("writing %s: %w", binPath, err)
+	}
+
+	return binPath, nil
+}
+
+// Fetch downloads the content of the url with the kconnect http client, so the
+// downloads are traced, recorded and refused when offline like other requests
+func Fetch(ctx context.Context, url string) ([]byte, error) {
+	resp, err := khttp.NewHTTPClient().Do(&khttp.ClientRequest{
+		URL:     url,
+		Method:  http.MethodGet,
+		Context: ctx,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("downloading %s: %w", url, err)
+	}
+	if resp.ResponseCode() != http.StatusOK {
+		return nil, fmt.Errorf("downloading %s, status %d: %w", url, resp.ResponseCode(), ErrDownloadFailed)
+	}
+
+	return []byte(resp.Body()), nil
+}
+
+// VerifyChecksum checks the sha256 of the data against a checksums file. The file
+// can contain just the checksum or lines of "<checksum>  <file name>".
+func VerifyChecksum(data, checksums []byte, fileName string) error {
+	expected := ""
+	scanner := bufio.NewScanner(bytes.NewReader(checksums))
+	for scanner.Scan() {
+		fields := strings.Fields(scanner.Text())
+		if len(fields) == 1 {
+			expected = fields[0]
+			break
+		}
+		if len(fields) == 2 && strings.TrimPrefix(fields[1], "*") == fileName {
+			expected = fields[0]
+			break
+		}
+	}
+	if expected == "" {
+		return fmt.Errorf("checksum for %s: %w", fileName, ErrChecksumNotFound)
+	}
+
+	return verifySHA256(data, expected)
+}
+
+// verifySHA256 checks the sha256 of the data against the expected hex encoded checksum
+func verifySHA256(data []byte, expected string) error {
+	sum := sha256.Sum256(data)
+	actual := hex.EncodeToString(sum[:])
+	if !strings.EqualFold(actual, expected) {
+		return fmt.Errorf("expected %s got %s: %w", expected, actual, ErrChecksumMismatch)
+	}
+
+	return nil
+}
+
+// ExtractFromZip returns the content of the file at the path in the zip archive
+func ExtractFromZip(data []byte, filePath string) ([]byte, error) {
+	reader, err := zip.NewReader(bytes.NewReader(data), int64(len(data)))
+	if err != nil {
+		return nil, fmt.Errorf("reading zip: %w", err)
+	}
+
+	for _, file := range reader.File {
+		if file.Name != filePath {
+			continue
+		}
+		fileReader, err := file.Open()
+		if err != nil {
+			return nil, fmt.Errorf("opening %s: %w", filePath, err)
+		}
+		defer fileReader.Close()
+
+		return ioutil.ReadAll(io.LimitReader(fileReader, maxBinarySize))
+	}
+
+	return nil, fmt.Errorf("%s: %w", filePath, ErrFileNotInArchive)
+}
+
+// ExtractFromTarGz returns the content of the file at the path in the gzipped tar archive
+func ExtractFromTarGz(data []byte, filePath string) ([]byte, error) {
+	gzipReader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, fmt.Errorf("reading gzip: %w", err)
+	}
+	defer gzipReader.Close()
+
+	tarReader := tar.NewReader(gzipReader)
+	for {
+		header, err := tarReader.Next()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("reading tar: %w", err)
+		}
+		if header.Name != filePath {
+			continue
+		}
+
+		return ioutil.ReadAll(io.LimitReader(tarReader, maxBinarySize))
+	}
+
+	return nil, fmt.Errorf("%s: %w", filePath, ErrFileNotInArchive)
+}
@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prereqs
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+
+	"go.uber.org/zap"
+	"golang.org/x/mod/semver"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/provider"
+)
+
+var (
+	ErrBinaryNotFound  = kerrors.WithCode(kerrors.CodePrereqMissing, errors.New("binary not found"))
+	ErrVersionTooLow   = kerrors.WithCode(kerrors.CodePrereqMissing, errors.New("binary version is lower than the minimum required"))
+	ErrVersionNotFound = errors.New("unable to determine binary version")
+	ErrNotInstallable  = errors.New("pre-requisite can't be installed")
+	ErrPreReqsNotMet   = kerrors.WithCode(kerrors.CodePrereqMissing, errors.New("pre-requisites not met"))
+
+	versionRegex = regexp.MustCompile(`v?(\d+\.\d+\.\d+)`)
+)
+
+// BinaryPreReq is a pre-requisite that an executable exists with a minimum version. The
+// executable is looked for on the PATH and then in the kconnect bin directory.
+type BinaryPreReq struct {
+	name        string
+	minVersion  string
+	versionArgs []string
+	help        string
+	install     *installSpec
+}
+
+var _ provider.InstallablePreReq = (*BinaryPreReq)(nil)
+
+// Name returns the name of the binary
+func (b *BinaryPreReq) Name() string {
+	return b.name
+}
+
+// Help returns details of how to install the binary
+func (b *BinaryPreReq) Help() string {
+	return b.help
+}
+
+// Check will check the binary can be found and that its version is at least the minimum version
+func (b *BinaryPreReq) Check() error {
+	path, err := b.Path()
+	if err != nil {
+		return err
+	}
+	if b.minVersion == "" {
+		return nil
+	}
+
+	version, err := b.version(path)
+	if err != nil {
+		return err
+	}
+	if semver.Compare(version, b.minVersion) < 0 {
+		return fmt.Errorf("%s version %s, minimum version %s: %w", b.name, version, b.minVersion, ErrVersionTooLow)
+	}
+
+	return nil
+}
+
+// Path returns the path to the binary
+func (b *BinaryPreReq) Path() (string, error) {
+	if path, err := exec.LookPath(b.name); err == nil {
+		return path, nil
+	}
+	if path := installedPath(b.name); path != "" {
+		return path, nil
+	}
+
+	return "", fmt.Errorf("finding %s: %w", b.name, ErrBinaryNotFound)
+}
+
+// Install will download the binary into the kconnect bin directory. The download is
+// verified using the checksum published with the release.
+func (b *BinaryPreReq) Install(ctx context.Context) (string, error) {
+	if b.install == nil {
+		return "", fmt.Errorf("installing %s: %w", b.name, ErrNotInstallable)
+	}
+
+	return b.install.install(ctx, b.name, defaults.BinDirectory())
+}
+
+// Version returns the version of the binary
+func (b *BinaryPreReq) Version() (string, error) {
+	path, err := b.Path()
+	if err != nil {
+		return "", err
+	}
+
+	return b.version(path)
+}
+
+// MinVersion returns the minimum version of the binary that is required
+func (b *BinaryPreReq) MinVersion() string {
+	return b.minVersion
+}
+
+func (b *BinaryPreReq) version(path string) (string, error) {
+	output, err := exec.Command(path, b.versionArgs...).CombinedOutput() //nolint: gosec
+	if err != nil {
+		return "", fmt.Errorf("getting %s version: %w", b.name, err)
+	}
+
+	matches := versionRegex.FindStringSubmatch(string(output))
+	if len(matches) == 0 {
+		return "", fmt.Errorf("getting %s version: %w", b.name, ErrVersionNotFound)
+	}
+
+	return "v" + matches[1], nil
+}
+
+// Check will check all the pre-requisites and return an error
+// that contains the help for each one that isn't met
+func Check(prereqs []provider.PreReq) error {
+	failed := []string{}
+	for _, prereq := range prereqs {
+		if err := prereq.Check(); err != nil {
+			failed = append(failed, fmt.Sprintf("%s: %s", err.Error(), prereq.Help()))
+		}
+	}
+	if len(failed) > 0 {
+		return fmt.Errorf("%w:\n%s", ErrPreReqsNotMet, strings.Join(failed, "\n"))
+	}
+
+	return nil
+}
+
+// Install will install each pre-requisite that isn't met and can be installed
+func Install(ctx context.Context, prereqs []provider.PreReq) error {
+	for _, prereq := range prereqs {
+		if err := prereq.Check(); err == nil {
+			continue
+		}
+		installable, ok := prereq.(provider.InstallablePreReq)
+		if !ok {
+			zap.S().Warnw("pre-requisite can't be installed by kconnect", "name", prereq.Name(), "help", prereq.Help())
+			continue
+		}
+
+		zap.S().Infow("installing pre-requisite", "name", prereq.Name())
+		path, err := installable.Install(ctx)
+		if err != nil {
+			return fmt.Errorf("installing %s: %w", prereq.Name(), err)
+		}
+		zap.S().Infow("installed pre-requisite", "name", prereq.Name(), "path", path)
+	}
+
+	return nil
+}
+
+// ResolveCommand returns the path to a binary installed by kconnect if the command
+// can't be found on the PATH. Otherwise the command is returned unchanged.
+func ResolveCommand(command string) string {
+	if _, err := exec.LookPath(command); err == nil {
+		return command
+	}
+	if path := installedPath(command); path != "" {
+		return path
+	}
+
+	return command
+}
+
+func installedPath(name string) string {
+	path := filepath.Join(defaults.BinDirectory(), binaryFileName(name))
+	info, err := os.Stat(path)
+	if err != nil || info.IsDir() {
+		return ""
+	}
+
+	return path
+}
+
+func binaryFileName(name string) string {
+	if runtime.GOOS == "windows" {
+		return name + ".exe"
+	}
+
+	return name
+}
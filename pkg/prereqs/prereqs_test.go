@@ -0,0 +1,210 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prereqs
+
+import (
+	"archive/zip"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+)
+
+func TestVerifyChecksum(t *testing.T) {
+	data := []byte("binary")
+	sum := sha256.Sum256(data)
+	checksum := hex.EncodeToString(sum[:])
+
+	testCases := []struct {
+		name      string
+		checksums string
+		expectErr error
+	}{
+		{
+			name:      "checksum only",
+			checksums: checksum + "\n",
+		},
+		{
+			name:      "checksums file",
+			checksums: fmt.Sprintf("abcdef  other_file\n%s  tool_linux_amd64\n", checksum),
+		},
+		{
+			name:      "binary mode checksums file",
+			checksums: fmt.Sprintf("%s *tool_linux_amd64\n", checksum),
+		},
+		{
+			name:      "file not in checksums",
+			checksums: fmt.Sprintf("%s  other_file\n", checksum),
+			expectErr: ErrChecksumNotFound,
+		},
+		{
+			name:      "checksum mismatch",
+			checksums: "abcdef  tool_linux_amd64\n",
+			expectErr: ErrChecksumMismatch,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := VerifyChecksum(data, []byte(tc.checksums), "tool_linux_amd64") //nolint:scopelint
+			if tc.expectErr == nil {                                              //nolint:scopelint
+				g.Expect(err).NotTo(HaveOccurred())
+			} else {
+				g.Expect(errors.Is(err, tc.expectErr)).To(BeTrue()) //nolint:scopelint
+			}
+		})
+	}
+}
+
+func TestInstall(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("uses a shell script as the binary")
+	}
+	g := NewWithT(t)
+	t.Setenv("HOME", t.TempDir())
+	t.Setenv("PATH", t.TempDir())
+
+	script := []byte("#!/bin/sh\necho \"tool version v1.2.3\"\n")
+	archive := createZip(t, "bin/tool", script)
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/tool.zip":
+			w.Write(archive) //nolint:errcheck
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	prereq := &BinaryPreReq{
+		name:        "tool",
+		minVersion:  "v1.0.0",
+		versionArgs: []string{"--version"},
+		install: &installSpec{
+			version: "1.2.3",
+			downloads: map[string]*download{
+				runtime.GOOS + "/" + runtime.GOARCH: {
+					url:         server.URL + "/tool.zip",
+					sha256:      sha256Hex(archive),
+					archivePath: "bin/tool",
+				},
+			},
+		},
+	}
+	g.Expect(errors.Is(prereq.Check(), ErrBinaryNotFound)).To(BeTrue())
+	g.Expect(ResolveCommand("tool")).To(Equal("tool"))
+
+	path, err := prereq.Install(context.TODO())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(path).To(Equal(filepath.Join(defaults.BinDirectory(), "tool")))
+
+	installed, err := ioutil.ReadFile(path)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(installed).To(Equal(script))
+
+	g.Expect(prereq.Check()).To(Succeed())
+	g.Expect(ResolveCommand("tool")).To(Equal(path))
+
+	prereq.minVersion = "v2.0.0"
+	g.Expect(errors.Is(prereq.Check(), ErrVersionTooLow)).To(BeTrue())
+}
+
+func TestInstallChecksumMismatch(t *testing.T) {
+	g := NewWithT(t)
+	t.Setenv("HOME", t.TempDir())
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte("tampered")) //nolint:errcheck
+	}))
+	defer server.Close()
+
+	prereq := &BinaryPreReq{
+		name: "tool",
+		install: &installSpec{
+			downloads: map[string]*download{
+				runtime.GOOS + "/" + runtime.GOARCH: {
+					url:    server.URL + "/tool",
+					sha256: sha256Hex([]byte("original")),
+				},
+			},
+		},
+	}
+
+	_, err := prereq.Install(context.TODO())
+	g.Expect(errors.Is(err, ErrChecksumMismatch)).To(BeTrue())
+	_, err = os.Stat(filepath.Join(defaults.BinDirectory(), binaryFileName("tool")))
+	g.Expect(os.IsNotExist(err)).To(BeTrue())
+}
+
+func TestInstallNotPinned(t *testing.T) {
+	g := NewWithT(t)
+	t.Setenv("HOME", t.TempDir())
+
+	calls := 0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	prereq := &BinaryPreReq{
+		name: "tool",
+		install: &installSpec{
+			downloads: map[string]*download{
+				runtime.GOOS + "/" + runtime.GOARCH: {url: server.URL + "/tool"},
+			},
+		},
+	}
+
+	_, err := prereq.Install(context.TODO())
+	g.Expect(errors.Is(err, ErrChecksumNotFound)).To(BeTrue())
+	g.Expect(calls).To(Equal(0))
+}
+
+func createZip(t *testing.T, name string, data []byte) []byte {
+	g := NewWithT(t)
+
+	buf := &bytes.Buffer{}
+	writer := zip.NewWriter(buf)
+	fileWriter, err := writer.Create(name)
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = fileWriter.Write(data)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(writer.Close()).To(Succeed())
+
+	return buf.Bytes()
+}
+
+func sha256Hex(data []byte) string {
+	sum := sha256.Sum256(data)
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prompt
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
+
+var ErrNoAnswer = kerrors.WithCode(kerrors.CodeInputRequired, errors.New("no answer for prompt"))
+
+// NewAnswersBackend creates a backend that answers each prompt using the value
+// for the prompts name. A prompt without an answer is an error so that a
+// missing answer doesn't block waiting for input. A multi-select prompt is
+// answered with the options separated by commas.
+func NewAnswersBackend(answers map[string]string) Backend {
+	return &answersBackend{
+		answers: answers,
+	}
+}
+
+type answersBackend struct {
+	answers map[string]string
+}
+
+func (a *answersBackend) Input(name, message string, required bool) (string, error) {
+	return a.answer(name, required)
+}
+
+func (a *answersBackend) Password(name, message string, required bool) (string, error) {
+	return a.answer(name, required)
+}
+
+func (a *answersBackend) Select(name, message string, required bool, options []string) (string, error) {
+	value, err := a.answer(name, required)
+	if err != nil {
+		return "", err
+	}
+	if contains(options, value) {
+		return value, nil
+	}
+
+	return "", fmt.Errorf("answer %s for %s: %w", value, name, ErrInvalidOption)
+}
+
+func (a *answersBackend) MultiSelect(name, message string, required bool, options []string) ([]string, error) {
+	value, err := a.answer(name, required)
+	if err != nil {
+		return nil, err
+	}
+	if value == "" {
+		return []string{}, nil
+	}
+
+	selected := []string{}
+	for _, answer := range strings.Split(value, ",") {
+		answer = strings.TrimSpace(answer)
+		if !contains(options, answer) {
+			return nil, fmt.Errorf("answer %s for %s: %w", answer, name, ErrInvalidOption)
+		}
+		selected = append(selected, answer)
+	}
+
+	return selected, nil
+}
+
+func (a *answersBackend) Confirm(name, message string, required bool) (bool, error) {
+	value, err := a.answer(name, required)
+	if err != nil {
+		return false, err
+	}
+
+	confirmed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("parsing answer for %s: %w", name, err)
+	}
+
+	return confirmed, nil
+}
+
+func (a *answersBackend) answer(name string, required bool) (string, error) {
+	value, ok := a.answers[name]
+	if !ok {
+		return "", fmt.Errorf("answering %s: %w", name, ErrNoAnswer)
+	}
+	if required && value == "" {
+		return "", fmt.Errorf("answering %s: %w", name, ErrRequired)
+	}
+
+	return value, nil
+}
+
+func contains(options []string, value string) bool {
+	for _, option := range options {
+		if option == value {
+			return true
+		}
+	}
+
+	return false
+}
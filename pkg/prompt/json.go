@@ -0,0 +1,203 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prompt
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"sync"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
+
+var ErrPromptCancelled = kerrors.WithCode(kerrors.CodeCanceled, errors.New("prompt cancelled"))
+
+// PromptType is the type of a prompt sent by the JSON backend
+type PromptType string
+
+var (
+	PromptTypeInput       = PromptType("input")
+	PromptTypePassword    = PromptType("password")
+	PromptTypeSelect      = PromptType("select")
+	PromptTypeMultiSelect = PromptType("multiselect")
+	PromptTypeConfirm     = PromptType("confirm")
+)
+
+// Request is a prompt written by the JSON backend. Each request is written
+// as a single line of JSON.
+type Request struct {
+	Type     PromptType `json:"type"`
+	Name     string     `json:"name"`
+	Message  string     `json:"message"`
+	Required bool       `json:"required,omitempty"`
+	Options  []string   `json:"options,omitempty"`
+}
+
+// Response is the answer to a prompt read by the JSON backend. Each response
+// must be a single line of JSON. A confirm prompt is answered with a value of
+// true or false and a multi-select prompt is answered with the values.
+type Response struct {
+	Value     string   `json:"value"`
+	Values    []string `json:"values,omitempty"`
+	Cancelled bool     `json:"cancelled,omitempty"`
+}
+
+// NewJSONBackend creates a backend that writes each prompt as a JSON request and
+// reads the JSON response. This allows kconnect to be driven by another program,
+// such as a GUI, over stdin and stdout.
+func NewJSONBackend(in io.Reader, out io.Writer) Backend {
+	return &jsonBackend{
+		in:  bufio.NewReader(in),
+		out: out,
+	}
+}
+
+type jsonBackend struct {
+	in   *bufio.Reader
+	out  io.Writer
+	lock sync.Mutex
+}
+
+func (j *jsonBackend) Input(name, message string, required bool) (string, error) {
+	return j.ask(&Request{
+		Type:     PromptTypeInput,
+		Name:     name,
+		Message:  message,
+		Required: required,
+	})
+}
+
+func (j *jsonBackend) Password(name, message string, required bool) (string, error) {
+	return j.ask(&Request{
+		Type:     PromptTypePassword,
+		Name:     name,
+		Message:  message,
+		Required: required,
+	})
+}
+
+func (j *jsonBackend) Select(name, message string, required bool, options []string) (string, error) {
+	value, err := j.ask(&Request{
+		Type:     PromptTypeSelect,
+		Name:     name,
+		Message:  message,
+		Required: required,
+		Options:  options,
+	})
+	if err != nil {
+		return "", err
+	}
+	if value == "" || contains(options, value) {
+		return value, nil
+	}
+
+	return "", fmt.Errorf("selecting %s for %s: %w", value, name, ErrInvalidOption)
+}
+
+func (j *jsonBackend) MultiSelect(name, message string, required bool, options []string) ([]string, error) {
+	req := &Request{
+		Type:     PromptTypeMultiSelect,
+		Name:     name,
+		Message:  message,
+		Required: required,
+		Options:  options,
+	}
+	resp, err := j.send(req)
+	if err != nil {
+		return nil, err
+	}
+	if required && len(resp.Values) == 0 {
+		return nil, fmt.Errorf("prompt %s: %w", name, ErrRequired)
+	}
+
+	for _, value := range resp.Values {
+		if !contains(options, value) {
+			return nil, fmt.Errorf("selecting %s for %s: %w", value, name, ErrInvalidOption)
+		}
+	}
+	if resp.Values == nil {
+		return []string{}, nil
+	}
+
+	return resp.Values, nil
+}
+
+func (j *jsonBackend) Confirm(name, message string, required bool) (bool, error) {
+	value, err := j.ask(&Request{
+		Type:     PromptTypeConfirm,
+		Name:     name,
+		Message:  message,
+		Required: required,
+	})
+	if err != nil {
+		return false, err
+	}
+	if value == "" {
+		return false, nil
+	}
+
+	confirmed, err := strconv.ParseBool(value)
+	if err != nil {
+		return false, fmt.Errorf("parsing confirmation for %s: %w", name, err)
+	}
+
+	return confirmed, nil
+}
+
+func (j *jsonBackend) ask(req *Request) (string, error) {
+	resp, err := j.send(req)
+	if err != nil {
+		return "", err
+	}
+	if req.Required && resp.Value == "" {
+		return "", fmt.Errorf("prompt %s: %w", req.Name, ErrRequired)
+	}
+
+	return resp.Value, nil
+}
+
+func (j *jsonBackend) send(req *Request) (*Response, error) {
+	j.lock.Lock()
+	defer j.lock.Unlock()
+
+	data, err := json.Marshal(req)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling prompt request: %w", err)
+	}
+	if _, err := fmt.Fprintln(j.out, string(data)); err != nil {
+		return nil, fmt.Errorf("writing prompt request: %w", err)
+	}
+
+	line, err := j.in.ReadBytes('\n')
+	if err != nil && !(errors.Is(err, io.EOF) && len(line) > 0) {
+		return nil, fmt.Errorf("reading prompt response: %w", err)
+	}
+	resp := &Response{}
+	if err := json.Unmarshal(line, resp); err != nil {
+		return nil, fmt.Errorf("unmarshalling prompt response: %w", err)
+	}
+
+	if resp.Cancelled {
+		return nil, fmt.Errorf("prompt %s: %w", req.Name, ErrPromptCancelled)
+	}
+
+	return resp, nil
+}
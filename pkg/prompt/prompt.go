@@ -19,35 +19,87 @@ package prompt
 import (
 	"errors"
 	"fmt"
-	"os"
+	"io"
 	"sort"
 	"strings"
+	"sync"
 
-	"github.com/AlecAivazis/survey/v2"
-	"github.com/AlecAivazis/survey/v2/terminal"
 	"go.uber.org/zap"
 
 	"github.com/fidelity/kconnect/pkg/config"
-	"github.com/fidelity/kconnect/pkg/utils"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/logging"
 )
 
-// Input will ask the user to enter a value
-func Input(name, message string, required bool) (string, error) {
-	enteredValue := ""
-	prompt := &survey.Input{
-		Message: message,
-	}
-	opts := []survey.AskOpt{}
+const (
+	// BackendTerminal is the name of the default backend that prompts in the terminal
+	BackendTerminal = "terminal"
+	// BackendJSON is the name of the backend that uses JSON requests and responses
+	BackendJSON = "json"
+)
+
+var (
+	ErrRequired       = kerrors.WithCode(kerrors.CodeInputRequired, errors.New("a value is required"))
+	ErrInvalidOption  = kerrors.WithCode(kerrors.CodeInputRequired, errors.New("value is not one of the options"))
+	ErrUnknownBackend = errors.New("unknown prompt backend")
+
+	backend     Backend = &surveyBackend{}
+	backendLock sync.Mutex
+)
+
+// Backend is the frontend used to ask the user for values. The default backend
+// uses an interactive terminal but alternatives can be set using SetBackend.
+type Backend interface {
+	// Input asks for a value to be entered
+	Input(name, message string, required bool) (string, error)
+	// Password asks for a value to be entered without showing it
+	Password(name, message string, required bool) (string, error)
+	// Select asks for one of the options to be selected
+	Select(name, message string, required bool, options []string) (string, error)
+	// MultiSelect asks for any number of the options to be selected
+	MultiSelect(name, message string, required bool, options []string) ([]string, error)
+	// Confirm asks for a yes or no answer
+	Confirm(name, message string, required bool) (bool, error)
+}
 
-	if required {
-		opts = append(opts, survey.WithValidator(survey.Required))
+// SetBackend sets the backend used for all prompts
+func SetBackend(b Backend) {
+	backendLock.Lock()
+	defer backendLock.Unlock()
+
+	backend = b
+}
+
+// NewBackend creates the named backend. The JSON backend uses the supplied reader
+// and writer for the requests and responses.
+func NewBackend(name string, in io.Reader, out io.Writer) (Backend, error) {
+	switch name {
+	case "", BackendTerminal:
+		return &surveyBackend{}, nil
+	case BackendJSON:
+		return NewJSONBackend(in, out), nil
+	default:
+		return nil, fmt.Errorf("creating prompt backend %s: %w", name, ErrUnknownBackend)
 	}
+}
 
-	if err := survey.AskOne(prompt, &enteredValue, opts...); err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			zap.S().Info("Received interrupt, exiting..")
-			os.Exit(0)
-		}
+// IsTerminal returns true if the prompts are shown in an interactive terminal
+func IsTerminal() bool {
+	_, ok := getBackend().(*surveyBackend)
+	return ok
+}
+
+func getBackend() Backend {
+	backendLock.Lock()
+	defer backendLock.Unlock()
+
+	return backend
+}
+
+// Input will ask the user to enter a value
+func Input(name, message string, required bool) (string, error) {
+	enteredValue, err := getBackend().Input(name, message, required)
+	if err != nil {
 		return "", fmt.Errorf("asking for %s name: %w", name, err)
 	}
 
@@ -60,55 +112,87 @@ func InputAndSet(cfg config.ConfigurationSet, name, message string, required boo
 		return nil
 	}
 
+	item := cfg.Get(name)
+	if item == nil {
+		return fmt.Errorf("getting config item %s: %w", name, config.ErrConfigNotFound)
+	}
+
 	var enteredValue string
 	var err error
 
-	if cfg.ValueIsList(name) {
+	switch {
+	case item.Sensitive:
+		enteredValue, err = getBackend().Password(name, message, required)
+		logging.Redact(enteredValue)
+	case item.ValuesResolver != nil:
+		enteredValue, err = chooseResolved(name, message, required, item.ValuesResolver)
+	case cfg.ValueIsList(name):
 		enteredValue, err = Choose(name, message, required, OptionsFromConfigList(cfg.ValueString(name)))
-	} else {
+	case item.Type == config.ItemTypeEnum:
+		enteredValue, err = Choose(name, message, required, OptionsFromStringSlice(item.AllowedValues))
+	default:
 		enteredValue, err = Input(name, message, required)
 	}
 	if err != nil {
 		return fmt.Errorf("asking for %s name: %w", name, err)
 	}
 
-	if err := cfg.SetValue(name, enteredValue); err != nil {
+	// Non-string items are entered in the same format as their flags
+	if err := config.SetItemValue(item, enteredValue); err != nil {
 		return fmt.Errorf("setting %s config: %w", name, err)
 	}
-	zap.S().Debugw("resolved config item", "name", name, "value", enteredValue)
+	item.Source = config.ItemSourcePrompt
+	zap.S().Debugw("resolved config item", "name", name, "value", item.DisplayValue())
 
 	return nil
 }
 
+// chooseResolved will ask the user to select one of the values from the resolver. The
+// user is asked to enter the value if the resolver doesn't find any.
+func chooseResolved(name, message string, required bool, resolver config.ValuesResolver) (string, error) {
+	options, err := resolver()
+	if err != nil {
+		return "", fmt.Errorf("resolving values for %s: %w", name, err)
+	}
+	if len(options) == 0 {
+		zap.S().Debugw("no values resolved for config item", "name", name)
+		return Input(name, message, required)
+	}
+
+	return Choose(name, message, required, OptionsFromMap(options))
+}
+
 // InputSensitiveAndSet will resolve and set a configuration item by asking the user to enter
-// a value but it won't show the value eneterd
+// a value but it won't show the value eneterd. The item is marked as sensitive so that its
+// value isn't kept in the history or shown.
 func InputSensitiveAndSet(cfg config.ConfigurationSet, name, message string, required bool) error {
 	if cfg.ExistsWithValue(name) {
 		return nil
 	}
-
-	enteredValue := ""
-	prompt := &survey.Password{
-		Message: message,
+	if err := cfg.SetSensitive(name); err != nil {
+		return fmt.Errorf("setting %s sensitive: %w", name, err)
 	}
-	opts := []survey.AskOpt{}
 
-	if required {
-		opts = append(opts, survey.WithValidator(survey.Required))
-	}
+	return InputAndSet(cfg, name, message, required)
+}
 
-	if err := survey.AskOne(prompt, &enteredValue, opts...); err != nil {
-		if errors.Is(err, terminal.InterruptErr) {
-			zap.S().Info("Received interrupt, exiting..")
-			os.Exit(0)
+// ResolveDependencies will ask the user to enter the values of the items that are required
+// because of the value of another item. The items are asked for in resolution order so that
+// the values they depend on are known first.
+func ResolveDependencies(cfg config.ConfigurationSet) error {
+	for _, item := range config.ResolutionOrder(cfg) {
+		if len(item.RequiredWhen) == 0 || item.HasValue() || !config.IsRequired(cfg, item) {
+			continue
 		}
-		return fmt.Errorf("asking for %s name: %w", name, err)
-	}
 
-	if err := cfg.SetValue(name, enteredValue); err != nil {
-		return fmt.Errorf("setting %s config: %w", name, err)
+		message := item.ResolutionPrompt
+		if message == "" {
+			message = fmt.Sprintf("Enter %s", item.Name)
+		}
+		if err := InputAndSet(cfg, item.Name, message, true); err != nil {
+			return fmt.Errorf("resolving %s: %w", item.Name, err)
+		}
 	}
-	zap.S().Debugw("resolved sensitive config item", "name", name)
 
 	return nil
 }
@@ -146,10 +230,14 @@ func OptionsFromConfigList(listName string) OptionsFunc {
 		if err != nil {
 			return nil, fmt.Errorf("getting app configuration: %w", err)
 		}
-		appCfg, err := appConfig.Get()
+		rawCfg, err := appConfig.Get()
 		if err != nil {
 			return nil, fmt.Errorf("reading app configuration: %w", err)
 		}
+		appCfg, err := config.Interpolate(rawCfg)
+		if err != nil {
+			return nil, fmt.Errorf("interpolating app configuration: %w", err)
+		}
 		list, ok := appCfg.Spec.Lists[listName]
 		if !ok {
 			return nil, fmt.Errorf("getting list %s: %w", listName, err)
@@ -177,7 +265,8 @@ func ChooseAndSet(cfg config.ConfigurationSet, name, message string, required bo
 	if err := cfg.SetValue(name, selected); err != nil {
 		return fmt.Errorf("setting %s config: %w", name, err)
 	}
-	zap.S().Debugw("resolved config item", "name", name, "value", selected)
+	cfg.SetSource(name, config.ItemSourcePrompt) //nolint: errcheck
+	zap.S().Debugw("resolved config item", "name", name, "value", cfg.Get(name).DisplayValue())
 
 	return nil
 }
@@ -201,22 +290,8 @@ func Choose(name, message string, required bool, optionsFn OptionsFunc) (string,
 		// If there is only 1 item we auto select
 		selectedOptionDisplay = displayOptions[0]
 	} else {
-		prompt := &survey.Select{
-			Message: message,
-			Options: displayOptions,
-			Filter:  utils.SurveyFilter,
-		}
-
-		opts := []survey.AskOpt{}
-		if required {
-			opts = append(opts, survey.WithValidator(survey.Required))
-		}
-
-		if err := survey.AskOne(prompt, &selectedOptionDisplay, opts...); err != nil {
-			if errors.Is(err, terminal.InterruptErr) {
-				zap.S().Info("Received interrupt, exiting..")
-				os.Exit(0)
-			}
+		selectedOptionDisplay, err = getBackend().Select(name, message, required, displayOptions)
+		if err != nil {
 			return "", fmt.Errorf("asking for %s: %w", name, err)
 		}
 	}
@@ -225,19 +300,43 @@ func Choose(name, message string, required bool, optionsFn OptionsFunc) (string,
 	return selectedValue, nil
 }
 
-// Input will ask the user to enter a value
-func Confirm(name, message string, required bool) (bool, error) {
-	confirmedValue := false
-	prompt := &survey.Confirm{
-		Message: message,
+// ChooseMany will ask the user to select any number of the options. The values of the
+// selected options are returned in the order of the options.
+func ChooseMany(name, message string, required bool, optionsFn OptionsFunc) ([]string, error) {
+	options, err := optionsFn()
+	if err != nil {
+		return nil, err
+	}
+
+	displayOptions := []string{}
+	for k := range options {
+		displayOptions = append(displayOptions, k)
+	}
+	sort.Strings(displayOptions)
+
+	selectedDisplay, err := getBackend().MultiSelect(name, message, required, displayOptions)
+	if err != nil {
+		return nil, fmt.Errorf("asking for %s: %w", name, err)
+	}
+	selected := map[string]bool{}
+	for _, display := range selectedDisplay {
+		selected[display] = true
 	}
-	opts := []survey.AskOpt{}
 
-	if required {
-		opts = append(opts, survey.WithValidator(survey.Required))
+	values := []string{}
+	for _, display := range displayOptions {
+		if selected[display] {
+			values = append(values, options[display])
+		}
 	}
 
-	if err := survey.AskOne(prompt, &confirmedValue, opts...); err != nil {
+	return values, nil
+}
+
+// Confirm will ask the user to answer yes or no
+func Confirm(name, message string, required bool) (bool, error) {
+	confirmedValue, err := getBackend().Confirm(name, message, required)
+	if err != nil {
 		return confirmedValue, fmt.Errorf("asking for %s name: %w", name, err)
 	}
 
@@ -0,0 +1,242 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prompt_test
+
+import (
+	"bytes"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/prompt"
+)
+
+func TestJSONBackend(t *testing.T) {
+	g := NewWithT(t)
+
+	in := strings.NewReader(`{"value":"bob"}
+{"value":"eu-west-1"}
+{"value":"true"}
+{"cancelled":true}
+`)
+	out := &bytes.Buffer{}
+	prompt.SetBackend(prompt.NewJSONBackend(in, out))
+	defer prompt.SetBackend(mustBackend(t, prompt.BackendTerminal))
+
+	cs := config.NewConfigurationSet()
+	_, err := cs.String("username", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(prompt.InputAndSet(cs, "username", "Enter username", true)).To(Succeed())
+	g.Expect(cs.ValueString("username")).To(Equal("bob"))
+	g.Expect(cs.Get("username").Source).To(Equal(config.ItemSourcePrompt))
+
+	region, err := prompt.Choose("region", "Select region", true, prompt.OptionsFromStringSlice([]string{"eu-west-1", "us-east-1"}))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(region).To(Equal("eu-west-1"))
+
+	confirmed, err := prompt.Confirm("overwrite", "Overwrite?", false)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(confirmed).To(BeTrue())
+
+	_, err = prompt.Input("password", "Enter password", true)
+	g.Expect(errors.Is(err, prompt.ErrPromptCancelled)).To(BeTrue())
+
+	g.Expect(out.String()).To(Equal(`{"type":"input","name":"username","message":"Enter username","required":true}
+{"type":"select","name":"region","message":"Select region","required":true,"options":["eu-west-1","us-east-1"]}
+{"type":"confirm","name":"overwrite","message":"Overwrite?"}
+{"type":"input","name":"password","message":"Enter password","required":true}
+`))
+}
+
+func TestInputAndSetSensitive(t *testing.T) {
+	g := NewWithT(t)
+
+	out := &bytes.Buffer{}
+	prompt.SetBackend(prompt.NewJSONBackend(strings.NewReader(`{"value":"s3cr3t"}
+{"value":"t0k3n"}
+`), out))
+	defer prompt.SetBackend(mustBackend(t, prompt.BackendTerminal))
+
+	cs := config.NewConfigurationSet()
+	_, err := cs.String("password", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cs.SetSensitive("password")).To(Succeed())
+	_, err = cs.String("token", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+
+	g.Expect(prompt.InputAndSet(cs, "password", "Password:", true)).To(Succeed())
+	g.Expect(cs.ValueString("password")).To(Equal("s3cr3t"))
+	g.Expect(prompt.InputSensitiveAndSet(cs, "token", "Token:", true)).To(Succeed())
+	g.Expect(cs.ValueString("token")).To(Equal("t0k3n"))
+	g.Expect(cs.Get("token").Sensitive).To(BeTrue())
+
+	g.Expect(out.String()).To(Equal(`{"type":"password","name":"password","message":"Password:","required":true}
+{"type":"password","name":"token","message":"Token:","required":true}
+`))
+}
+
+func TestInputAndSetValuesResolver(t *testing.T) {
+	g := NewWithT(t)
+
+	out := &bytes.Buffer{}
+	prompt.SetBackend(prompt.NewJSONBackend(strings.NewReader(`{"value":"prod (eu-west-2)"}
+{"value":"rg-dev"}
+`), out))
+	defer prompt.SetBackend(mustBackend(t, prompt.BackendTerminal))
+
+	cs := config.NewConfigurationSet()
+	_, err := cs.String("subscription-id", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	_, err = cs.String("resource-group", "", "")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cs.SetValuesResolver("subscription-id", func() (map[string]string, error) {
+		return map[string]string{"dev (eu-west-1)": "0001", "prod (eu-west-2)": "0002"}, nil
+	})).To(Succeed())
+	g.Expect(cs.SetValuesResolver("resource-group", func() (map[string]string, error) {
+		return nil, nil
+	})).To(Succeed())
+
+	g.Expect(prompt.InputAndSet(cs, "subscription-id", "Choose subscription", true)).To(Succeed())
+	g.Expect(cs.ValueString("subscription-id")).To(Equal("0002"))
+	g.Expect(prompt.InputAndSet(cs, "resource-group", "Enter resource group", true)).To(Succeed())
+	g.Expect(cs.ValueString("resource-group")).To(Equal("rg-dev"))
+
+	g.Expect(out.String()).To(Equal(`{"type":"select","name":"subscription-id","message":"Choose subscription","required":true,"options":["dev (eu-west-1)","prod (eu-west-2)"]}
+{"type":"input","name":"resource-group","message":"Enter resource group","required":true}
+`))
+}
+
+func TestJSONBackendInvalidOption(t *testing.T) {
+	g := NewWithT(t)
+
+	backend := prompt.NewJSONBackend(strings.NewReader(`{"value":"ap-south-1"}`), &bytes.Buffer{})
+
+	_, err := backend.Select("region", "Select region", true, []string{"eu-west-1", "us-east-1"})
+	g.Expect(errors.Is(err, prompt.ErrInvalidOption)).To(BeTrue())
+}
+
+func TestAnswersBackend(t *testing.T) {
+	testCases := []struct {
+		name      string
+		answers   map[string]string
+		options   []string
+		expect    string
+		expectErr error
+	}{
+		{
+			name:    "input answered",
+			answers: map[string]string{"username": "bob"},
+			expect:  "bob",
+		},
+		{
+			name:      "no answer",
+			answers:   map[string]string{},
+			expectErr: prompt.ErrNoAnswer,
+		},
+		{
+			name:      "empty required answer",
+			answers:   map[string]string{"username": ""},
+			expectErr: prompt.ErrRequired,
+		},
+		{
+			name:    "option answered",
+			answers: map[string]string{"username": "bob"},
+			options: []string{"alice", "bob"},
+			expect:  "bob",
+		},
+		{
+			name:      "answer not an option",
+			answers:   map[string]string{"username": "eve"},
+			options:   []string{"alice", "bob"},
+			expectErr: prompt.ErrInvalidOption,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			backend := prompt.NewAnswersBackend(tc.answers) //nolint:scopelint
+
+			var actual string
+			var err error
+			if tc.options == nil { //nolint:scopelint
+				actual, err = backend.Input("username", "Enter username", true)
+			} else {
+				actual, err = backend.Select("username", "Select username", true, tc.options) //nolint:scopelint
+			}
+
+			if tc.expectErr != nil { //nolint:scopelint
+				g.Expect(errors.Is(err, tc.expectErr)).To(BeTrue()) //nolint:scopelint
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual).To(Equal(tc.expect)) //nolint:scopelint
+		})
+	}
+}
+
+func TestMultiSelect(t *testing.T) {
+	g := NewWithT(t)
+
+	options := []string{"eu-west-1", "us-east-1", "us-west-2"}
+
+	jsonBackend := prompt.NewJSONBackend(strings.NewReader(`{"values":["us-west-2","eu-west-1"]}
+{"values":["ap-south-1"]}
+`), &bytes.Buffer{})
+	selected, err := jsonBackend.MultiSelect("region", "Select regions", true, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(selected).To(Equal([]string{"us-west-2", "eu-west-1"}))
+	_, err = jsonBackend.MultiSelect("region", "Select regions", true, options)
+	g.Expect(errors.Is(err, prompt.ErrInvalidOption)).To(BeTrue())
+
+	answersBackend := prompt.NewAnswersBackend(map[string]string{"region": "eu-west-1, us-east-1", "filtered": "ap-south-1"})
+	selected, err = answersBackend.MultiSelect("region", "Select regions", true, options)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(selected).To(Equal([]string{"eu-west-1", "us-east-1"}))
+	_, err = answersBackend.MultiSelect("filtered", "Select regions", true, options)
+	g.Expect(errors.Is(err, prompt.ErrInvalidOption)).To(BeTrue())
+
+	prompt.SetBackend(prompt.NewAnswersBackend(map[string]string{"region": "us-east-1 (N. Virginia),eu-west-1 (Ireland)"}))
+	defer prompt.SetBackend(mustBackend(t, prompt.BackendTerminal))
+	values, err := prompt.ChooseMany("region", "Select regions", true, prompt.OptionsFromMap(map[string]string{
+		"eu-west-1 (Ireland)":     "eu-west-1",
+		"us-east-1 (N. Virginia)": "us-east-1",
+		"us-west-2 (Oregon)":      "us-west-2",
+	}))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(values).To(Equal([]string{"eu-west-1", "us-east-1"}))
+}
+
+func TestNewBackendUnknown(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := prompt.NewBackend("gui", nil, nil)
+	g.Expect(errors.Is(err, prompt.ErrUnknownBackend)).To(BeTrue())
+}
+
+func mustBackend(t *testing.T, name string) prompt.Backend {
+	backend, err := prompt.NewBackend(name, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return backend
+}
@@ -0,0 +1,118 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package prompt
+
+import (
+	"errors"
+	"os"
+
+	"github.com/AlecAivazis/survey/v2"
+	"github.com/AlecAivazis/survey/v2/terminal"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+// surveyBackend is the default backend and prompts the user in the terminal
+type surveyBackend struct{}
+
+func (s *surveyBackend) Input(name, message string, required bool) (string, error) {
+	enteredValue := ""
+	prompt := &survey.Input{
+		Message: message,
+	}
+
+	if err := survey.AskOne(prompt, &enteredValue, askOpts(required)...); err != nil {
+		return "", handleInterrupt(err)
+	}
+
+	return enteredValue, nil
+}
+
+func (s *surveyBackend) Password(name, message string, required bool) (string, error) {
+	enteredValue := ""
+	prompt := &survey.Password{
+		Message: message,
+	}
+
+	if err := survey.AskOne(prompt, &enteredValue, askOpts(required)...); err != nil {
+		return "", handleInterrupt(err)
+	}
+
+	return enteredValue, nil
+}
+
+func (s *surveyBackend) Select(name, message string, required bool, options []string) (string, error) {
+	selected := ""
+	prompt := &survey.Select{
+		Message: message,
+		Options: options,
+		Filter:  utils.SurveyFilter,
+	}
+
+	if err := survey.AskOne(prompt, &selected, askOpts(required)...); err != nil {
+		return "", handleInterrupt(err)
+	}
+
+	return selected, nil
+}
+
+func (s *surveyBackend) MultiSelect(name, message string, required bool, options []string) ([]string, error) {
+	selected := []string{}
+	prompt := &survey.MultiSelect{
+		Message: message,
+		Options: options,
+		Filter:  utils.SurveyFilter,
+	}
+
+	if err := survey.AskOne(prompt, &selected, askOpts(required)...); err != nil {
+		return nil, handleInterrupt(err)
+	}
+
+	return selected, nil
+}
+
+func (s *surveyBackend) Confirm(name, message string, required bool) (bool, error) {
+	confirmedValue := false
+	prompt := &survey.Confirm{
+		Message: message,
+	}
+
+	if err := survey.AskOne(prompt, &confirmedValue, askOpts(required)...); err != nil {
+		return confirmedValue, err
+	}
+
+	return confirmedValue, nil
+}
+
+func askOpts(required bool) []survey.AskOpt {
+	opts := []survey.AskOpt{}
+	if required {
+		opts = append(opts, survey.WithValidator(survey.Required))
+	}
+
+	return opts
+}
+
+func handleInterrupt(err error) error {
+	if errors.Is(err, terminal.InterruptErr) {
+		zap.S().Info("Received interrupt, exiting..")
+		os.Exit(0)
+	}
+
+	return err
+}
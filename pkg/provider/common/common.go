@@ -42,7 +42,7 @@ type IdentityProviderConfig struct {
 }
 
 func AddCommonClusterConfig(cs config.ConfigurationSet) error {
-	if _, err := cs.String("cluster-id", "", "Id of the cluster to use."); err != nil {
+	if _, err := cs.String("cluster-id", "", "Id of the cluster to use, or - to read it from stdin."); err != nil {
 		return fmt.Errorf("adding cluster-id setting: %w", err)
 	}
 	if _, err := cs.String("alias", "", "Friendly name to give to give the connection"); err != nil {
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/cache"
+)
+
+const clusterIDConfigItem = "cluster-id"
+
+type refreshKey struct{}
+
+// WithRefresh returns a context that makes the cache middleware discover the clusters
+// again instead of reading them from the cache, e.g. when the cached details of a
+// cluster are found to be stale. The clusters that are discovered are still cached.
+func WithRefresh(ctx context.Context) context.Context {
+	return context.WithValue(ctx, refreshKey{}, true)
+}
+
+func refreshRequested(ctx context.Context) bool {
+	refresh, _ := ctx.Value(refreshKey{}).(bool)
+	return refresh
+}
+
+// CacheMiddleware will return the discovered clusters from the cache if the same
+// identity discovered them with the same configuration within the ttl of the cache.
+// The scope is added to the key, e.g. the cluster filter. If refresh is true the
+// cache isn't read but the clusters that are discovered are still cached. Calls to
+// get a single cluster or its config aren't cached.
+func CacheMiddleware(c cache.Cache, scope string, refresh bool, logger *zap.SugaredLogger) Middleware {
+	return func(next Provider) Provider {
+		return &cacheProvider{Provider: next, cache: c, scope: scope, refresh: refresh, logger: logger}
+	}
+}
+
+type cacheProvider struct {
+	Provider
+	cache   cache.Cache
+	scope   string
+	refresh bool
+	logger  *zap.SugaredLogger
+}
+
+func (c *cacheProvider) Discover(ctx context.Context, input *DiscoverInput) (*DiscoverOutput, error) {
+	key := c.key(input)
+
+	if !c.refresh && !refreshRequested(ctx) {
+		cached := &DiscoverOutput{}
+		found, err := c.cache.Get(key, cached)
+		if err != nil {
+			c.logger.Warnw("failed reading discovered clusters from cache", "provider", c.Name(), "error", err.Error())
+		}
+		if found {
+			c.logger.Debugw("using cached discovered clusters", "provider", c.Name(), "count", len(cached.Clusters))
+			clusters := []*Cluster{}
+			for _, cluster := range cached.Clusters {
+				clusters = append(clusters, cluster)
+			}
+			input.ClustersFound(clusters...)
+			return cached, nil
+		}
+	}
+
+	output, err := c.Provider.Discover(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := c.cache.Set(key, output); err != nil {
+		c.logger.Warnw("failed caching discovered clusters", "provider", c.Name(), "error", err.Error())
+	}
+
+	return output, nil
+}
+
+// key identifies the discovery using the provider, the identity and the values of the
+// config items. Sensitive items and items that aren't kept in the history, as they
+// don't change which clusters are discovered, aren't used. Neither is the cluster id
+// so that a cluster can be found in the clusters cached when choosing from them.
+func (c *cacheProvider) key(input *DiscoverInput) string {
+	parts := []string{"discovery", c.Name(), c.scope}
+	if input.Identity != nil {
+		parts = append(parts, input.Identity.IdentityProviderName(), input.Identity.Name())
+	}
+
+	if input.ConfigSet != nil {
+		values := []string{}
+		for _, item := range input.ConfigSet.GetAll() {
+			if item.Sensitive || item.HistoryIgnore || !item.HasValue() || item.Name == clusterIDConfigItem {
+				continue
+			}
+			values = append(values, fmt.Sprintf("%s=%s", item.Name, item.ValueString()))
+		}
+		sort.Strings(values)
+		parts = append(parts, values...)
+	}
+
+	return strings.Join(parts, "|")
+}
@@ -34,10 +34,48 @@ type Provider interface {
 
 type ProviderCreatorFun func(input *provider.PluginCreationInput) (Provider, error)
 
+// DashboardResolver is an optional interface for providers whose
+// clusters have a managed dashboard, such as a cloud console
+type DashboardResolver interface {
+	// DashboardURL returns the URL of the managed dashboard for the cluster
+	// with the provided provider specific cluster id
+	DashboardURL(cs config.ConfigurationSet, clusterID string) (string, error)
+}
+
 // DiscoverInput is the input to Discover
 type DiscoverInput struct {
 	ConfigSet config.ConfigurationSet
 	Identity  identity.Identity
+	// OnClusters is optional and is called with the clusters as they are found by
+	// providers that discover in batches, e.g. a region at a time. The output of
+	// Discover still has all the clusters.
+	OnClusters func(clusters []*Cluster)
+}
+
+// ClustersFound will pass the clusters to the OnClusters callback, if there is one
+func (i *DiscoverInput) ClustersFound(clusters ...*Cluster) {
+	if i.OnClusters != nil && len(clusters) > 0 {
+		i.OnClusters(clusters)
+	}
+}
+
+// withOnClusters returns a copy of the input that calls fn with the clusters that are
+// found before they're passed to the original callback. The input is returned as is
+// if there's no callback.
+func (i *DiscoverInput) withOnClusters(fn func(clusters []*Cluster) []*Cluster) *DiscoverInput {
+	if i.OnClusters == nil {
+		return i
+	}
+
+	next := i.OnClusters
+	wrapped := *i
+	wrapped.OnClusters = func(clusters []*Cluster) {
+		if clusters = fn(clusters); len(clusters) > 0 {
+			next(clusters)
+		}
+	}
+
+	return &wrapped
 }
 
 // DiscoverOutput holds details of the output of the Discover
@@ -64,6 +102,8 @@ type GetConfigInput struct {
 	Cluster   *Cluster
 	Namespace *string
 	Identity  identity.Identity
+	// ProxyURL is the proxy that connections to the cluster go through, e.g. a SSH tunnel
+	ProxyURL string
 }
 
 type GetConfigOutput struct {
@@ -77,4 +117,12 @@ type Cluster struct {
 	Name                     string  `yaml:"name"`
 	ControlPlaneEndpoint     *string `yaml:"endpoint"`
 	CertificateAuthorityData *string `yaml:"ca"`
+	// Account is the account or subscription the cluster belongs to, e.g. the AWS account ID
+	Account string `yaml:"account,omitempty"`
+	// Region is the region or location the cluster is in
+	Region string `yaml:"region,omitempty"`
+	// Tags are the tags of the cluster in the provider
+	Tags map[string]string `yaml:"tags,omitempty"`
+	// Annotations are additional details about the cluster added by enrichers
+	Annotations map[string]string `yaml:"annotations,omitempty"`
 }
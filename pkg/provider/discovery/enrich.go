@@ -0,0 +1,123 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"fmt"
+	"sort"
+	"strings"
+
+	"go.uber.org/zap"
+)
+
+// ColumnTags is the column that shows the tags of the clusters when selecting a cluster
+const ColumnTags = "tags"
+
+// Enricher adds additional details to a cluster after it has been discovered and
+// before it's selected. For example the version of Kubernetes or the owner of the
+// cluster from a CMDB. The details are stored as annotations on the cluster.
+type Enricher interface {
+	// Name is the unique name of the enricher
+	Name() string
+
+	// Columns are the annotation keys to show when selecting a cluster
+	Columns() []string
+
+	// Enrich will add annotations to the cluster
+	Enrich(ctx context.Context, cluster *Cluster) error
+}
+
+// SetAnnotation will set an annotation on the cluster
+func (c *Cluster) SetAnnotation(key, value string) {
+	if c.Annotations == nil {
+		c.Annotations = make(map[string]string)
+	}
+	c.Annotations[key] = value
+}
+
+// ColumnValue returns the value to show in the column when selecting the cluster. The column
+// is the key of an annotation or ColumnTags.
+func (c *Cluster) ColumnValue(column string) string {
+	if column != ColumnTags {
+		return c.Annotations[column]
+	}
+
+	tags := make([]string, 0, len(c.Tags))
+	for key, value := range c.Tags {
+		tags = append(tags, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(tags)
+
+	return strings.Join(tags, ",")
+}
+
+// EnrichMiddleware will run the enrichers against each cluster returned by the provider.
+// An enricher that fails is logged and doesn't stop the cluster being used.
+func EnrichMiddleware(logger *zap.SugaredLogger, enrichers ...Enricher) Middleware {
+	return func(next Provider) Provider {
+		return &enrichProvider{Provider: next, enrichers: enrichers, logger: logger}
+	}
+}
+
+type enrichProvider struct {
+	Provider
+	enrichers []Enricher
+	logger    *zap.SugaredLogger
+}
+
+func (e *enrichProvider) Discover(ctx context.Context, input *DiscoverInput) (*DiscoverOutput, error) {
+	found := &foundClusters{}
+	input = input.withOnClusters(func(clusters []*Cluster) []*Cluster {
+		for _, cluster := range found.add(clusters) {
+			e.enrich(ctx, cluster)
+		}
+		return clusters
+	})
+
+	output, err := e.Provider.Discover(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	for _, cluster := range output.Clusters {
+		if !found.contains(cluster) {
+			e.enrich(ctx, cluster)
+		}
+	}
+
+	return output, nil
+}
+
+func (e *enrichProvider) GetCluster(ctx context.Context, input *GetClusterInput) (*GetClusterOutput, error) {
+	output, err := e.Provider.GetCluster(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if output.Cluster != nil {
+		e.enrich(ctx, output.Cluster)
+	}
+
+	return output, nil
+}
+
+func (e *enrichProvider) enrich(ctx context.Context, cluster *Cluster) {
+	for _, enricher := range e.enrichers {
+		if err := enricher.Enrich(ctx, cluster); err != nil {
+			e.logger.Warnw("enriching cluster failed", "enricher", enricher.Name(), "cluster", cluster.ID, "error", err.Error())
+		}
+	}
+}
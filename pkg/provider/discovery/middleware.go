@@ -0,0 +1,380 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"regexp"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/telemetry"
+)
+
+// Middleware wraps a discovery provider to add behaviour that is common to all the
+// providers, such as logging or filtering, without changing the provider itself.
+type Middleware func(next Provider) Provider
+
+// Chain will wrap the provider with the middleware. The first middleware is the
+// outermost and so is called first.
+func Chain(p Provider, middleware ...Middleware) Provider {
+	for i := len(middleware) - 1; i >= 0; i-- {
+		p = middleware[i](p)
+	}
+
+	return p
+}
+
+// LoggingMiddleware will log each call to the provider and any error returned
+func LoggingMiddleware(logger *zap.SugaredLogger) Middleware {
+	return func(next Provider) Provider {
+		return &loggingProvider{Provider: next, logger: logger}
+	}
+}
+
+type loggingProvider struct {
+	Provider
+	logger *zap.SugaredLogger
+}
+
+func (l *loggingProvider) Discover(ctx context.Context, input *DiscoverInput) (*DiscoverOutput, error) {
+	l.logger.Debugw("discovering clusters", "provider", l.Name())
+	output, err := l.Provider.Discover(ctx, input)
+	if err != nil {
+		l.logger.Debugw("discovering clusters failed", "provider", l.Name(), "error", err.Error())
+		return nil, err
+	}
+	l.logger.Debugw("discovered clusters", "provider", l.Name(), "count", len(output.Clusters))
+
+	return output, nil
+}
+
+func (l *loggingProvider) GetCluster(ctx context.Context, input *GetClusterInput) (*GetClusterOutput, error) {
+	l.logger.Debugw("getting cluster", "provider", l.Name(), "id", input.ClusterID)
+	output, err := l.Provider.GetCluster(ctx, input)
+	if err != nil {
+		l.logger.Debugw("getting cluster failed", "provider", l.Name(), "id", input.ClusterID, "error", err.Error())
+	}
+
+	return output, err
+}
+
+func (l *loggingProvider) GetConfig(ctx context.Context, input *GetConfigInput) (*GetConfigOutput, error) {
+	l.logger.Debugw("getting cluster config", "provider", l.Name(), "id", input.Cluster.ID)
+	output, err := l.Provider.GetConfig(ctx, input)
+	if err != nil {
+		l.logger.Debugw("getting cluster config failed", "provider", l.Name(), "id", input.Cluster.ID, "error", err.Error())
+	}
+
+	return output, err
+}
+
+// TimingMiddleware will log how long each call to the provider takes
+func TimingMiddleware(logger *zap.SugaredLogger) Middleware {
+	return func(next Provider) Provider {
+		return &timingProvider{Provider: next, logger: logger}
+	}
+}
+
+type timingProvider struct {
+	Provider
+	logger *zap.SugaredLogger
+}
+
+func (t *timingProvider) Discover(ctx context.Context, input *DiscoverInput) (*DiscoverOutput, error) {
+	defer t.logDuration("Discover", time.Now())
+	return t.Provider.Discover(ctx, input)
+}
+
+func (t *timingProvider) GetCluster(ctx context.Context, input *GetClusterInput) (*GetClusterOutput, error) {
+	defer t.logDuration("GetCluster", time.Now())
+	return t.Provider.GetCluster(ctx, input)
+}
+
+func (t *timingProvider) GetConfig(ctx context.Context, input *GetConfigInput) (*GetConfigOutput, error) {
+	defer t.logDuration("GetConfig", time.Now())
+	return t.Provider.GetConfig(ctx, input)
+}
+
+func (t *timingProvider) logDuration(method string, start time.Time) {
+	t.logger.Debugw("discovery provider call", "provider", t.Name(), "method", method, "duration", time.Since(start).String())
+}
+
+// TracingMiddleware will record a span for each call to the provider
+func TracingMiddleware() Middleware {
+	return func(next Provider) Provider {
+		return &tracingProvider{Provider: next}
+	}
+}
+
+type tracingProvider struct {
+	Provider
+}
+
+func (t *tracingProvider) Discover(ctx context.Context, input *DiscoverInput) (*DiscoverOutput, error) {
+	ctx, span := telemetry.Start(ctx, "discovery.discover", "provider", t.Name())
+	defer span.Finish()
+
+	output, err := t.Provider.Discover(ctx, input)
+	span.RecordError(err)
+	if err == nil {
+		span.SetAttributes("clusters", strconv.Itoa(len(output.Clusters)))
+	}
+
+	return output, err
+}
+
+func (t *tracingProvider) GetCluster(ctx context.Context, input *GetClusterInput) (*GetClusterOutput, error) {
+	ctx, span := telemetry.Start(ctx, "discovery.get-cluster", "provider", t.Name(), "cluster-id", input.ClusterID)
+	defer span.Finish()
+
+	output, err := t.Provider.GetCluster(ctx, input)
+	span.RecordError(err)
+
+	return output, err
+}
+
+func (t *tracingProvider) GetConfig(ctx context.Context, input *GetConfigInput) (*GetConfigOutput, error) {
+	ctx, span := telemetry.Start(ctx, "discovery.get-config", "provider", t.Name(), "cluster-id", input.Cluster.ID)
+	defer span.Finish()
+
+	output, err := t.Provider.GetConfig(ctx, input)
+	span.RecordError(err)
+
+	return output, err
+}
+
+// ClusterFilterFunc returns true if a discovered cluster should be kept
+type ClusterFilterFunc func(cluster *Cluster) bool
+
+// NameFilter returns a filter that matches clusters with a name that matches
+// one of the comma separated filters. The filters support wildcards (*) and an
+// empty filter matches all clusters
+func NameFilter(filter string) ClusterFilterFunc {
+	patterns := []*regexp.Regexp{}
+	for _, part := range strings.Split(filter, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		patterns = append(patterns, wildcardPattern(part))
+	}
+
+	return func(cluster *Cluster) bool {
+		if len(patterns) == 0 {
+			return true
+		}
+		for _, pattern := range patterns {
+			if pattern.MatchString(cluster.Name) {
+				return true
+			}
+		}
+
+		return false
+	}
+}
+
+// wildcardPattern returns a regular expression that matches the whole of a value
+// with the pattern, where * matches any characters
+func wildcardPattern(pattern string) *regexp.Regexp {
+	quoted := regexp.QuoteMeta(pattern)
+
+	return regexp.MustCompile("^" + strings.ReplaceAll(quoted, `\*`, ".*") + "$")
+}
+
+// FilterMiddleware will remove the discovered clusters that don't match the filter
+func FilterMiddleware(filter ClusterFilterFunc) Middleware {
+	return func(next Provider) Provider {
+		return &filterProvider{Provider: next, filter: filter}
+	}
+}
+
+type filterProvider struct {
+	Provider
+	filter ClusterFilterFunc
+}
+
+func (f *filterProvider) Discover(ctx context.Context, input *DiscoverInput) (*DiscoverOutput, error) {
+	input = input.withOnClusters(func(clusters []*Cluster) []*Cluster {
+		filtered := []*Cluster{}
+		for _, cluster := range clusters {
+			if f.filter(cluster) {
+				filtered = append(filtered, cluster)
+			}
+		}
+		return filtered
+	})
+
+	output, err := f.Provider.Discover(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make(map[string]*Cluster)
+	for id, cluster := range output.Clusters {
+		if f.filter(cluster) {
+			filtered[id] = cluster
+		}
+	}
+	output.Clusters = filtered
+
+	return output, nil
+}
+
+// ClusterTransformFunc changes the details of a cluster returned by a provider
+type ClusterTransformFunc func(cluster *Cluster)
+
+// TransformMiddleware will apply the transform to each cluster returned by the provider
+func TransformMiddleware(transform ClusterTransformFunc) Middleware {
+	return func(next Provider) Provider {
+		return &transformProvider{Provider: next, transform: transform}
+	}
+}
+
+type transformProvider struct {
+	Provider
+	transform ClusterTransformFunc
+}
+
+func (t *transformProvider) Discover(ctx context.Context, input *DiscoverInput) (*DiscoverOutput, error) {
+	found := &foundClusters{}
+	input = input.withOnClusters(func(clusters []*Cluster) []*Cluster {
+		for _, cluster := range found.add(clusters) {
+			t.transform(cluster)
+		}
+		return clusters
+	})
+
+	output, err := t.Provider.Discover(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	for _, cluster := range output.Clusters {
+		if !found.contains(cluster) {
+			t.transform(cluster)
+		}
+	}
+
+	return output, nil
+}
+
+func (t *transformProvider) GetCluster(ctx context.Context, input *GetClusterInput) (*GetClusterOutput, error) {
+	output, err := t.Provider.GetCluster(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if output.Cluster != nil {
+		t.transform(output.Cluster)
+	}
+
+	return output, nil
+}
+
+// CachingMiddleware will cache the result of discovering the clusters so that the
+// provider is only called once. Getting a cluster that has already been discovered
+// is also served from the cache.
+func CachingMiddleware() Middleware {
+	return func(next Provider) Provider {
+		return &cachingProvider{Provider: next}
+	}
+}
+
+type cachingProvider struct {
+	Provider
+	lock       sync.Mutex
+	discovered *DiscoverOutput
+}
+
+func (c *cachingProvider) Discover(ctx context.Context, input *DiscoverInput) (*DiscoverOutput, error) {
+	c.lock.Lock()
+	defer c.lock.Unlock()
+
+	if c.discovered != nil {
+		return copyDiscoverOutput(c.discovered), nil
+	}
+
+	output, err := c.Provider.Discover(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	c.discovered = copyDiscoverOutput(output)
+
+	return output, nil
+}
+
+func (c *cachingProvider) GetCluster(ctx context.Context, input *GetClusterInput) (*GetClusterOutput, error) {
+	c.lock.Lock()
+	if c.discovered != nil {
+		if cluster, found := c.discovered.Clusters[input.ClusterID]; found {
+			c.lock.Unlock()
+			return &GetClusterOutput{Cluster: cluster}, nil
+		}
+	}
+	c.lock.Unlock()
+
+	return c.Provider.GetCluster(ctx, input)
+}
+
+func copyDiscoverOutput(output *DiscoverOutput) *DiscoverOutput {
+	clusters := make(map[string]*Cluster, len(output.Clusters))
+	for id, cluster := range output.Clusters {
+		clusters[id] = cluster
+	}
+
+	return &DiscoverOutput{
+		DiscoveryProvider: output.DiscoveryProvider,
+		IdentityProvider:  output.IdentityProvider,
+		Clusters:          clusters,
+	}
+}
+
+// foundClusters keeps track of the clusters that have already been passed to the
+// OnClusters callback, so that they're only changed once
+type foundClusters struct {
+	lock     sync.Mutex
+	clusters map[*Cluster]bool
+}
+
+// add returns the clusters that haven't been found before
+func (f *foundClusters) add(clusters []*Cluster) []*Cluster {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+	if f.clusters == nil {
+		f.clusters = make(map[*Cluster]bool)
+	}
+
+	added := []*Cluster{}
+	for _, cluster := range clusters {
+		if !f.clusters[cluster] {
+			f.clusters[cluster] = true
+			added = append(added, cluster)
+		}
+	}
+
+	return added
+}
+
+func (f *foundClusters) contains(cluster *Cluster) bool {
+	f.lock.Lock()
+	defer f.lock.Unlock()
+
+	return f.clusters[cluster]
+}
@@ -0,0 +1,305 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/cache"
+	"github.com/fidelity/kconnect/pkg/config"
+	"github.com/fidelity/kconnect/pkg/provider"
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+func TestChainOrder(t *testing.T) {
+	g := NewWithT(t)
+
+	calls := []string{}
+	record := func(name string) discovery.Middleware {
+		return discovery.TransformMiddleware(func(cluster *discovery.Cluster) {
+			calls = append(calls, name)
+		})
+	}
+
+	p := discovery.Chain(newFakeProvider(), record("outer"), record("inner"))
+	_, err := p.GetCluster(context.TODO(), &discovery.GetClusterInput{ClusterID: "1"})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	// The inner middleware sees the result first
+	g.Expect(calls).To(Equal([]string{"inner", "outer"}))
+}
+
+func TestFilterMiddleware(t *testing.T) {
+	g := NewWithT(t)
+
+	p := discovery.Chain(newFakeProvider(), discovery.FilterMiddleware(func(cluster *discovery.Cluster) bool {
+		return strings.HasPrefix(cluster.Name, "dev")
+	}))
+
+	output, err := p.Discover(context.TODO(), &discovery.DiscoverInput{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(output.Clusters).To(HaveLen(1))
+	g.Expect(output.Clusters).To(HaveKey("1"))
+}
+
+func TestNameFilter(t *testing.T) {
+	testCases := []struct {
+		filter string
+		name   string
+		expect bool
+	}{
+		{filter: "", name: "dev-1", expect: true},
+		{filter: "dev-1", name: "dev-1", expect: true},
+		{filter: "dev-*", name: "dev-1", expect: true},
+		{filter: "dev-*", name: "prod-1", expect: false},
+		{filter: "prod-*, dev-*", name: "dev-1", expect: true},
+		{filter: "dev.1", name: "dev-1", expect: false},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.filter+"/"+tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			filter := discovery.NameFilter(tc.filter)                                //nolint:scopelint
+			g.Expect(filter(&discovery.Cluster{Name: tc.name})).To(Equal(tc.expect)) //nolint:scopelint
+		})
+	}
+}
+
+func TestTransformMiddleware(t *testing.T) {
+	g := NewWithT(t)
+
+	p := discovery.Chain(newFakeProvider(), discovery.TransformMiddleware(func(cluster *discovery.Cluster) {
+		cluster.Name = strings.ToUpper(cluster.Name)
+	}))
+
+	output, err := p.Discover(context.TODO(), &discovery.DiscoverInput{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(output.Clusters["1"].Name).To(Equal("DEV-1"))
+	g.Expect(output.Clusters["2"].Name).To(Equal("PROD-1"))
+}
+
+func TestCachingMiddleware(t *testing.T) {
+	g := NewWithT(t)
+
+	fake := newFakeProvider()
+	p := discovery.Chain(fake, discovery.LoggingMiddleware(zap.S()), discovery.TimingMiddleware(zap.S()), discovery.CachingMiddleware())
+
+	for i := 0; i < 2; i++ {
+		output, err := p.Discover(context.TODO(), &discovery.DiscoverInput{})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(output.Clusters).To(HaveLen(2))
+	}
+	cluster, err := p.GetCluster(context.TODO(), &discovery.GetClusterInput{ClusterID: "2"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Cluster.Name).To(Equal("prod-1"))
+
+	g.Expect(fake.discoverCalls).To(Equal(1))
+	g.Expect(fake.getClusterCalls).To(Equal(0))
+	g.Expect(p.Name()).To(Equal("fake"))
+}
+
+func TestEnrichMiddleware(t *testing.T) {
+	g := NewWithT(t)
+
+	p := discovery.Chain(newFakeProvider(), discovery.EnrichMiddleware(zap.S(),
+		&fakeEnricher{name: "failing", err: errors.New("enricher failed")},
+		&fakeEnricher{name: "version", key: "version", value: "v1.21"},
+	))
+
+	output, err := p.Discover(context.TODO(), &discovery.DiscoverInput{})
+	g.Expect(err).NotTo(HaveOccurred())
+	for _, cluster := range output.Clusters {
+		g.Expect(cluster.Annotations).To(HaveKeyWithValue("version", "v1.21"))
+	}
+
+	cluster, err := p.GetCluster(context.TODO(), &discovery.GetClusterInput{ClusterID: "1"})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cluster.Cluster.Annotations).To(HaveKeyWithValue("version", "v1.21"))
+}
+
+func TestStreamedClusters(t *testing.T) {
+	g := NewWithT(t)
+
+	transforms := 0
+	p := discovery.Chain(newFakeProvider(),
+		discovery.FilterMiddleware(func(cluster *discovery.Cluster) bool {
+			return strings.HasPrefix(cluster.Name, "DEV")
+		}),
+		discovery.TransformMiddleware(func(cluster *discovery.Cluster) {
+			transforms++
+			cluster.Name = strings.ToUpper(cluster.Name)
+		}),
+	)
+
+	found := []string{}
+	output, err := p.Discover(context.TODO(), &discovery.DiscoverInput{
+		OnClusters: func(clusters []*discovery.Cluster) {
+			for _, cluster := range clusters {
+				found = append(found, cluster.Name)
+			}
+		},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(Equal([]string{"DEV-1"}))
+	g.Expect(output.Clusters).To(HaveLen(1))
+
+	// The clusters passed to the callback aren't transformed again
+	g.Expect(transforms).To(Equal(2))
+}
+
+type fakeEnricher struct {
+	name  string
+	key   string
+	value string
+	err   error
+}
+
+func (f *fakeEnricher) Name() string {
+	return f.name
+}
+
+func (f *fakeEnricher) Columns() []string {
+	return []string{f.key}
+}
+
+func (f *fakeEnricher) Enrich(ctx context.Context, cluster *discovery.Cluster) error {
+	if f.err != nil {
+		return f.err
+	}
+	cluster.SetAnnotation(f.key, f.value)
+	return nil
+}
+
+type fakeProvider struct {
+	discoverCalls   int
+	getClusterCalls int
+	// describedTags are set on the cluster by GetConfig, like a provider that lists
+	// the clusters without their details
+	describedTags map[string]string
+}
+
+func newFakeProvider() *fakeProvider {
+	return &fakeProvider{}
+}
+
+func (f *fakeProvider) clusters() map[string]*discovery.Cluster {
+	return map[string]*discovery.Cluster{
+		"1": {ID: "1", Name: "dev-1"},
+		"2": {ID: "2", Name: "prod-1"},
+	}
+}
+
+func (f *fakeProvider) Name() string {
+	return "fake"
+}
+
+func (f *fakeProvider) ListPreReqs() []provider.PreReq {
+	return []provider.PreReq{}
+}
+
+func (f *fakeProvider) CheckPreReqs() error {
+	return nil
+}
+
+func (f *fakeProvider) Validate(cfg config.ConfigurationSet) error {
+	return nil
+}
+
+func (f *fakeProvider) Resolve(cfg config.ConfigurationSet, identity identity.Identity) error {
+	return nil
+}
+
+func (f *fakeProvider) Discover(ctx context.Context, input *discovery.DiscoverInput) (*discovery.DiscoverOutput, error) {
+	f.discoverCalls++
+	clusters := f.clusters()
+	input.ClustersFound(clusters["1"])
+	input.ClustersFound(clusters["2"])
+
+	return &discovery.DiscoverOutput{
+		DiscoveryProvider: "fake",
+		Clusters:          clusters,
+	}, nil
+}
+
+func (f *fakeProvider) GetCluster(ctx context.Context, input *discovery.GetClusterInput) (*discovery.GetClusterOutput, error) {
+	f.getClusterCalls++
+	return &discovery.GetClusterOutput{
+		Cluster: f.clusters()[input.ClusterID],
+	}, nil
+}
+
+func (f *fakeProvider) GetConfig(ctx context.Context, input *discovery.GetConfigInput) (*discovery.GetConfigOutput, error) {
+	if f.describedTags != nil {
+		input.Cluster.Tags = f.describedTags
+	}
+	return &discovery.GetConfigOutput{}, nil
+}
+
+func TestCacheMiddleware(t *testing.T) {
+	g := NewWithT(t)
+
+	dir := t.TempDir()
+	c := cache.New(dir, time.Hour)
+	fake := newFakeProvider()
+	id := identity.NewTokenIdentity("bob", "abc", "fake-idp")
+
+	p := discovery.Chain(fake, discovery.CacheMiddleware(c, "dev*", false, zap.NewNop().Sugar()))
+	for i := 0; i < 2; i++ {
+		output, err := p.Discover(context.TODO(), &discovery.DiscoverInput{Identity: id})
+		g.Expect(err).NotTo(HaveOccurred())
+		g.Expect(output.Clusters).To(HaveLen(2))
+	}
+	g.Expect(fake.discoverCalls).To(Equal(1))
+
+	// A different scope isn't cached
+	p = discovery.Chain(fake, discovery.CacheMiddleware(c, "prod*", false, zap.NewNop().Sugar()))
+	_, err := p.Discover(context.TODO(), &discovery.DiscoverInput{Identity: id})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(fake.discoverCalls).To(Equal(2))
+
+	// Refreshing ignores the cached clusters
+	p = discovery.Chain(fake, discovery.CacheMiddleware(c, "dev*", true, zap.NewNop().Sugar()))
+	_, err = p.Discover(context.TODO(), &discovery.DiscoverInput{Identity: id})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(fake.discoverCalls).To(Equal(3))
+
+	// So does refreshing with the context
+	p = discovery.Chain(fake, discovery.CacheMiddleware(c, "dev*", false, zap.NewNop().Sugar()))
+	_, err = p.Discover(discovery.WithRefresh(context.TODO()), &discovery.DiscoverInput{Identity: id})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(fake.discoverCalls).To(Equal(4))
+
+	// The cluster id doesn't change the key and a read only cache returns the clusters
+	// after they've expired
+	cs := config.NewConfigurationSet()
+	cs.String("cluster-id", "", "") //nolint: errcheck
+	g.Expect(cs.SetValue("cluster-id", "2")).To(Succeed())
+	p = discovery.Chain(fake, discovery.CacheMiddleware(cache.NewReadOnly(dir), "dev*", false, zap.NewNop().Sugar()))
+	output, err := p.Discover(context.TODO(), &discovery.DiscoverInput{Identity: id, ConfigSet: cs})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(output.Clusters).To(HaveKey("2"))
+	g.Expect(fake.discoverCalls).To(Equal(4))
+}
@@ -0,0 +1,136 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
+
+var ErrClusterDenied = kerrors.WithCode(kerrors.CodeClusterDenied, errors.New("cluster is denied by the cluster policy"))
+
+// ClusterPattern matches clusters by name and tags. The name and tag values support
+// wildcards (*) and a cluster must match all the fields that are set.
+type ClusterPattern struct {
+	Name string
+	Tags map[string]string
+}
+
+// Matches returns true if the cluster matches the pattern
+func (p *ClusterPattern) Matches(cluster *Cluster) bool {
+	if p.Name != "" && !wildcardPattern(p.Name).MatchString(cluster.Name) {
+		return false
+	}
+	for key, value := range p.Tags {
+		tagValue, ok := cluster.Tags[key]
+		if !ok || !wildcardPattern(value).MatchString(tagValue) {
+			return false
+		}
+	}
+
+	return true
+}
+
+// String returns the pattern in the form name[key=value,...]
+func (p *ClusterPattern) String() string {
+	name := p.Name
+	if name == "" {
+		name = "*"
+	}
+	if len(p.Tags) == 0 {
+		return name
+	}
+
+	tags := []string{}
+	for key, value := range p.Tags {
+		tags = append(tags, fmt.Sprintf("%s=%s", key, value))
+	}
+	sort.Strings(tags)
+
+	return fmt.Sprintf("%s[%s]", name, strings.Join(tags, ","))
+}
+
+// ClusterPolicy restricts the clusters that can be connected to. If there are allow
+// patterns a cluster must match one of them, and a cluster that matches any of the
+// deny patterns is denied.
+type ClusterPolicy struct {
+	Allow []ClusterPattern
+	Deny  []ClusterPattern
+}
+
+// Check returns ErrClusterDenied, with the reason, if the policy denies the cluster. A
+// nil policy allows all clusters.
+func (p *ClusterPolicy) Check(cluster *Cluster) error {
+	if p == nil {
+		return nil
+	}
+
+	for i := range p.Deny {
+		if p.Deny[i].Matches(cluster) {
+			return fmt.Errorf("%s matches deny pattern %s: %w", cluster.Name, p.Deny[i].String(), ErrClusterDenied)
+		}
+	}
+	if len(p.Allow) == 0 {
+		return nil
+	}
+	for i := range p.Allow {
+		if p.Allow[i].Matches(cluster) {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("%s doesn't match any allow pattern: %w", cluster.Name, ErrClusterDenied)
+}
+
+// Filter returns a filter that keeps the clusters allowed by the policy
+func (p *ClusterPolicy) Filter() ClusterFilterFunc {
+	return func(cluster *Cluster) bool {
+		return p.Check(cluster) == nil
+	}
+}
+
+// PolicyMiddleware will remove the discovered clusters that the policy denies and check
+// the policy again once the config of a cluster has been got. Providers that list the
+// clusters without their details, e.g. EKS with --lazy-describe, only fill in the tags
+// of the selected cluster when getting its config.
+func PolicyMiddleware(policy *ClusterPolicy) Middleware {
+	return func(next Provider) Provider {
+		return &policyProvider{Provider: FilterMiddleware(policy.Filter())(next), policy: policy}
+	}
+}
+
+type policyProvider struct {
+	Provider
+	policy *ClusterPolicy
+}
+
+func (p *policyProvider) GetConfig(ctx context.Context, input *GetConfigInput) (*GetConfigOutput, error) {
+	output, err := p.Provider.GetConfig(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+	if err := p.policy.Check(input.Cluster); err != nil {
+		return nil, err
+	}
+
+	return output, nil
+}
@@ -0,0 +1,122 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery_test
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+)
+
+func TestClusterPolicy(t *testing.T) {
+	devCluster := &discovery.Cluster{Name: "dev-1", Tags: map[string]string{"env": "dev"}}
+	prodCluster := &discovery.Cluster{Name: "prod-1", Tags: map[string]string{"env": "production", "team": "payments"}}
+	legacyCluster := &discovery.Cluster{Name: "prod-legacy", Tags: map[string]string{"env": "production"}}
+
+	testCases := []struct {
+		name    string
+		policy  *discovery.ClusterPolicy
+		cluster *discovery.Cluster
+		denied  bool
+	}{
+		{
+			name:    "no policy",
+			cluster: prodCluster,
+		},
+		{
+			name:    "denied by name",
+			policy:  &discovery.ClusterPolicy{Deny: []discovery.ClusterPattern{{Name: "*-legacy"}}},
+			cluster: legacyCluster,
+			denied:  true,
+		},
+		{
+			name:    "denied by tag",
+			policy:  &discovery.ClusterPolicy{Deny: []discovery.ClusterPattern{{Tags: map[string]string{"env": "prod*"}}}},
+			cluster: prodCluster,
+			denied:  true,
+		},
+		{
+			name:    "tag missing",
+			policy:  &discovery.ClusterPolicy{Deny: []discovery.ClusterPattern{{Tags: map[string]string{"team": "*"}}}},
+			cluster: devCluster,
+		},
+		{
+			name:    "allowed",
+			policy:  &discovery.ClusterPolicy{Allow: []discovery.ClusterPattern{{Name: "prod-*", Tags: map[string]string{"team": "payments"}}}},
+			cluster: prodCluster,
+		},
+		{
+			name:    "not allowed",
+			policy:  &discovery.ClusterPolicy{Allow: []discovery.ClusterPattern{{Name: "prod-*", Tags: map[string]string{"team": "payments"}}}},
+			cluster: legacyCluster,
+			denied:  true,
+		},
+		{
+			name: "deny overrides allow",
+			policy: &discovery.ClusterPolicy{
+				Allow: []discovery.ClusterPattern{{Name: "prod-*"}},
+				Deny:  []discovery.ClusterPattern{{Name: "prod-legacy"}},
+			},
+			cluster: legacyCluster,
+			denied:  true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			err := tc.policy.Check(tc.cluster) //nolint:scopelint
+			if tc.denied {                     //nolint:scopelint
+				g.Expect(errors.Is(err, discovery.ErrClusterDenied)).To(BeTrue())
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(tc.policy.Filter()(tc.cluster)).To(BeTrue()) //nolint:scopelint
+		})
+	}
+}
+
+func TestClusterPatternString(t *testing.T) {
+	g := NewWithT(t)
+
+	pattern := &discovery.ClusterPattern{Tags: map[string]string{"team": "payments", "env": "prod*"}}
+	g.Expect(pattern.String()).To(Equal("*[env=prod*,team=payments]"))
+}
+
+func TestPolicyMiddlewareLazyDescribe(t *testing.T) {
+	g := NewWithT(t)
+
+	fake := newFakeProvider()
+	fake.describedTags = map[string]string{"env": "production"}
+	policy := &discovery.ClusterPolicy{
+		Deny: []discovery.ClusterPattern{{Tags: map[string]string{"env": "prod*"}}},
+	}
+	p := discovery.Chain(fake, discovery.PolicyMiddleware(policy))
+
+	// The clusters are listed without their tags so the policy can't deny them yet
+	output, err := p.Discover(context.TODO(), &discovery.DiscoverInput{})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(output.Clusters).To(HaveLen(2))
+
+	_, err = p.GetConfig(context.TODO(), &discovery.GetConfigInput{Cluster: output.Clusters["2"]})
+	g.Expect(errors.Is(err, discovery.ErrClusterDenied)).To(BeTrue())
+}
@@ -0,0 +1,144 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery
+
+import (
+	"bufio"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
+
+// SelectionFromStdin is the cluster id that means the cluster is selected using stdin
+const SelectionFromStdin = "-"
+
+// maxSelectionSize limits how much is read when selecting a cluster from stdin
+const maxSelectionSize = 1024 * 1024
+
+var (
+	ErrNoSelection          = kerrors.WithCode(kerrors.CodeInputRequired, errors.New("no cluster selected"))
+	ErrAmbiguousSelection   = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("more than 1 cluster selected"))
+	ErrSelectionProvider    = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("selected cluster is for a different provider"))
+	ErrUnsupportedSelection = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("unsupported cluster selection document"))
+)
+
+// ReadSelection reads the id of the cluster to use. The input is either the plain cluster
+// id or a JSON document from the ls command, e.g. a discovered cluster, a list of
+// discovered clusters with 1 cluster or a history entry. If the document includes the
+// discovery provider it must match the supplied provider.
+func ReadSelection(r io.Reader, provider string) (string, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxSelectionSize))
+	if err != nil {
+		return "", fmt.Errorf("reading cluster selection: %w", err)
+	}
+	selection := strings.TrimSpace(string(data))
+	if selection == "" {
+		return "", ErrNoSelection
+	}
+
+	if strings.HasPrefix(selection, "{") || strings.HasPrefix(selection, "[") || strings.HasPrefix(selection, "\"") {
+		var doc interface{}
+		if err := json.Unmarshal([]byte(selection), &doc); err != nil {
+			return "", fmt.Errorf("parsing cluster selection: %w", err)
+		}
+		return selectionFromJSON(doc, provider)
+	}
+
+	lines := []string{}
+	scanner := bufio.NewScanner(strings.NewReader(selection))
+	for scanner.Scan() {
+		if line := strings.TrimSpace(scanner.Text()); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	if len(lines) > 1 {
+		return "", fmt.Errorf("reading cluster selection with %d lines: %w", len(lines), ErrAmbiguousSelection)
+	}
+
+	return lines[0], nil
+}
+
+func selectionFromJSON(doc interface{}, provider string) (string, error) {
+	switch val := doc.(type) {
+	case string:
+		if strings.TrimSpace(val) == "" {
+			return "", ErrNoSelection
+		}
+		return strings.TrimSpace(val), nil
+	case []interface{}:
+		return selectionFromList(val, provider)
+	case map[string]interface{}:
+		return selectionFromObject(val, provider)
+	default:
+		return "", ErrUnsupportedSelection
+	}
+}
+
+func selectionFromList(list []interface{}, provider string) (string, error) {
+	switch len(list) {
+	case 0:
+		return "", ErrNoSelection
+	case 1:
+		return selectionFromJSON(list[0], provider)
+	default:
+		return "", fmt.Errorf("reading cluster selection with %d items: %w", len(list), ErrAmbiguousSelection)
+	}
+}
+
+func selectionFromObject(obj map[string]interface{}, provider string) (string, error) {
+	if err := checkSelectionProvider(obj, provider); err != nil {
+		return "", err
+	}
+
+	// A list of discovered clusters or history entries
+	for _, listField := range []string{"clusters", "items"} {
+		if list, ok := obj[listField].([]interface{}); ok {
+			return selectionFromList(list, provider)
+		}
+	}
+
+	// A history entry
+	if spec, ok := obj["spec"].(map[string]interface{}); ok {
+		if err := checkSelectionProvider(spec, provider); err != nil {
+			return "", err
+		}
+		if id, ok := spec["providerID"].(string); ok && id != "" {
+			return id, nil
+		}
+		return "", ErrNoSelection
+	}
+
+	// A discovered cluster
+	if id, ok := obj["id"].(string); ok && id != "" {
+		return id, nil
+	}
+
+	return "", ErrUnsupportedSelection
+}
+
+func checkSelectionProvider(obj map[string]interface{}, provider string) error {
+	selected, ok := obj["provider"].(string)
+	if !ok || selected == "" || provider == "" || selected == provider {
+		return nil
+	}
+
+	return fmt.Errorf("cluster selection for %s can't be used with %s: %w", selected, provider, ErrSelectionProvider)
+}
@@ -0,0 +1,112 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package discovery_test
+
+import (
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+)
+
+func TestReadSelection(t *testing.T) {
+	testCases := []struct {
+		name      string
+		input     string
+		expect    string
+		expectErr error
+	}{
+		{
+			name:   "plain id",
+			input:  "  prod-cluster\n",
+			expect: "prod-cluster",
+		},
+		{
+			name:   "json string",
+			input:  `"arn:aws:eks:eu-west-1:123456789012:cluster/prod"`,
+			expect: "arn:aws:eks:eu-west-1:123456789012:cluster/prod",
+		},
+		{
+			name:   "discovered cluster",
+			input:  `{"id":"prod-id","name":"prod"}`,
+			expect: "prod-id",
+		},
+		{
+			name:   "discovered clusters with 1 cluster",
+			input:  `{"provider":"eks","clusters":[{"id":"prod-id","name":"prod"}]}`,
+			expect: "prod-id",
+		},
+		{
+			name:   "history entry",
+			input:  `{"metadata":{"name":"01F"},"spec":{"provider":"eks","providerID":"prod-id"}}`,
+			expect: "prod-id",
+		},
+		{
+			name:   "history entry list with 1 entry",
+			input:  `{"items":[{"spec":{"provider":"eks","providerID":"prod-id"}}]}`,
+			expect: "prod-id",
+		},
+		{
+			name:      "empty",
+			input:     "\n",
+			expectErr: discovery.ErrNoSelection,
+		},
+		{
+			name:      "multiple ids",
+			input:     "dev-cluster\nprod-cluster\n",
+			expectErr: discovery.ErrAmbiguousSelection,
+		},
+		{
+			name:      "multiple discovered clusters",
+			input:     `{"provider":"eks","clusters":[{"id":"dev-id"},{"id":"prod-id"}]}`,
+			expectErr: discovery.ErrAmbiguousSelection,
+		},
+		{
+			name:      "no discovered clusters",
+			input:     `{"provider":"eks","clusters":[]}`,
+			expectErr: discovery.ErrNoSelection,
+		},
+		{
+			name:      "different provider",
+			input:     `{"spec":{"provider":"aks","providerID":"prod-id"}}`,
+			expectErr: discovery.ErrSelectionProvider,
+		},
+		{
+			name:      "unsupported document",
+			input:     `{"cluster":"prod-id"}`,
+			expectErr: discovery.ErrUnsupportedSelection,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			actual, err := discovery.ReadSelection(strings.NewReader(tc.input), "eks") //nolint:scopelint
+
+			if tc.expectErr != nil { //nolint:scopelint
+				g.Expect(errors.Is(err, tc.expectErr)).To(BeTrue()) //nolint:scopelint
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(actual).To(Equal(tc.expect)) //nolint:scopelint
+		})
+	}
+}
@@ -0,0 +1,72 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"errors"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
+
+// CredentialError is returned by identity providers when the identity provider rejects
+// the credentials. The code tells apart why they were rejected, e.g. a wrong password
+// or a locked account, and Items are the config items that were rejected so that just
+// they can be entered again.
+type CredentialError struct {
+	Code  kerrors.Code
+	Items []string
+	Err   error
+}
+
+// NewCredentialError creates an error for credentials that were rejected with the code
+func NewCredentialError(code kerrors.Code, err error, items ...string) error {
+	return &CredentialError{
+		Code:  code,
+		Items: items,
+		Err:   err,
+	}
+}
+
+func (e *CredentialError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *CredentialError) Unwrap() error {
+	return e.Err
+}
+
+// ErrorCode returns the code for why the credentials were rejected
+func (e *CredentialError) ErrorCode() kerrors.Code {
+	return e.Code
+}
+
+// Retryable returns true if entering the rejected items again could succeed. A locked
+// account isn't retried as each attempt could keep it locked for longer.
+func (e *CredentialError) Retryable() bool {
+	return len(e.Items) > 0 && e.Code != kerrors.CodeAccountLocked
+}
+
+// RejectedItems returns the config items that were rejected if the error, or an error
+// it wraps, is a retryable CredentialError
+func RejectedItems(err error) []string {
+	var credErr *CredentialError
+	if !errors.As(err, &credErr) || !credErr.Retryable() {
+		return nil
+	}
+
+	return credErr.Items
+}
@@ -0,0 +1,71 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+func TestRejectedItems(t *testing.T) {
+	errRejected := errors.New("rejected")
+
+	testCases := []struct {
+		name         string
+		err          error
+		expectedCode kerrors.Code
+		expected     []string
+	}{
+		{
+			name:         "invalid password",
+			err:          fmt.Errorf("authenticating: %w", identity.NewCredentialError(kerrors.CodeInvalidCredentials, errRejected, "password")),
+			expectedCode: kerrors.CodeInvalidCredentials,
+			expected:     []string{"password"},
+		},
+		{
+			name:         "locked account isn't retried",
+			err:          identity.NewCredentialError(kerrors.CodeAccountLocked, errRejected, "password"),
+			expectedCode: kerrors.CodeAccountLocked,
+		},
+		{
+			name:         "mfa denied without items",
+			err:          identity.NewCredentialError(kerrors.CodeMFADenied, errRejected),
+			expectedCode: kerrors.CodeMFADenied,
+		},
+		{
+			name:         "not a credential error",
+			err:          errRejected,
+			expectedCode: kerrors.CodeUnknown,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) { //nolint:scopelint
+			g := NewWithT(t)
+
+			g.Expect(identity.RejectedItems(tc.err)).To(Equal(tc.expected)) //nolint:scopelint
+			g.Expect(kerrors.CodeOf(tc.err)).To(Equal(tc.expectedCode))     //nolint:scopelint
+			g.Expect(errors.Is(tc.err, errRejected)).To(BeTrue())           //nolint:scopelint
+		})
+	}
+}
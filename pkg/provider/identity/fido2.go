@@ -0,0 +1,233 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"strings"
+
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/pkg/utils"
+)
+
+const (
+	fido2TokenCommand  = "fido2-token"
+	fido2AssertCommand = "fido2-assert"
+
+	userVerificationRequired = "required"
+)
+
+var (
+	ErrNoSecurityKey       = errors.New("no fido2 security key found")
+	ErrNoCredentialIDs     = errors.New("the webauthn challenge has no credential ids")
+	ErrInvalidFIDO2Output  = errors.New("unexpected output from fido2-assert")
+	ErrNoWebAuthnChallenge = errors.New("the mfa challenge has no webauthn challenge")
+)
+
+// fido2MFAHandler signs WebAuthn challenges with a FIDO2 security key using the libfido2
+// command line tools. It's used when fido2-assert is on the PATH.
+type fido2MFAHandler struct{}
+
+func (h *fido2MFAHandler) Supports(method MFAMethod) bool {
+	if method != MFAMethodWebAuthn {
+		return false
+	}
+	_, err := exec.LookPath(fido2AssertCommand)
+
+	return err == nil
+}
+
+func (h *fido2MFAHandler) HandleMFA(ctx context.Context, challenge *MFAChallenge) (*MFAResponse, error) {
+	if !challenge.Interactive {
+		return nil, ErrMFAInputRequired
+	}
+	webAuthn := challenge.WebAuthn
+	if webAuthn == nil {
+		return nil, ErrNoWebAuthnChallenge
+	}
+	if len(webAuthn.CredentialIDs) == 0 {
+		return nil, ErrNoCredentialIDs
+	}
+
+	device, err := fido2Device(ctx)
+	if err != nil {
+		return nil, err
+	}
+	clientData, err := webAuthnClientData(webAuthn)
+	if err != nil {
+		return nil, err
+	}
+	clientDataHash := sha256.Sum256(clientData)
+
+	fmt.Fprintln(os.Stderr, utils.Warning("Touch your security key to continue"))
+
+	var assertErr error
+	for _, credentialID := range webAuthn.CredentialIDs {
+		assertion, err := fido2Assert(ctx, device, clientDataHash[:], credentialID, webAuthn)
+		if err != nil {
+			zap.S().Debugw("security key didn't sign the challenge", "device", device, "error", err.Error())
+			assertErr = err
+			continue
+		}
+		assertion.ClientDataJSON = clientData
+
+		return &MFAResponse{WebAuthn: assertion}, nil
+	}
+
+	return nil, fmt.Errorf("signing webauthn challenge with %s: %w", device, assertErr)
+}
+
+// fido2Device returns the first FIDO2 device listed by fido2-token
+func fido2Device(ctx context.Context) (string, error) {
+	out, err := exec.CommandContext(ctx, fido2TokenCommand, "-L").Output() //nolint: gosec
+	if err != nil {
+		return "", fmt.Errorf("listing fido2 devices: %w", err)
+	}
+
+	for _, line := range strings.Split(string(out), "\n") {
+		if device := strings.TrimSpace(strings.SplitN(line, ": ", 2)[0]); device != "" {
+			return device, nil
+		}
+	}
+
+	return "", ErrNoSecurityKey
+}
+
+// webAuthnClientData creates the client data that is signed for the challenge
+func webAuthnClientData(challenge *WebAuthnChallenge) ([]byte, error) {
+	origin := challenge.Origin
+	if origin == "" {
+		origin = "https://" + challenge.RelyingPartyID
+	}
+
+	clientData, err := json.Marshal(map[string]interface{}{
+		"type":        "webauthn.get",
+		"challenge":   base64.RawURLEncoding.EncodeToString(challenge.Challenge),
+		"origin":      origin,
+		"crossOrigin": false,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("marshalling webauthn client data: %w", err)
+	}
+
+	return clientData, nil
+}
+
+// fido2Assert asks the device to sign the client data hash using fido2-assert. The PIN
+// is asked for by fido2-assert when user verification is required.
+func fido2Assert(ctx context.Context, device string, clientDataHash, credentialID []byte, challenge *WebAuthnChallenge) (*WebAuthnAssertion, error) {
+	args := []string{"-G", "-p"}
+	if challenge.UserVerification == userVerificationRequired {
+		args = append(args, "-v")
+	}
+	args = append(args, device)
+
+	input := strings.Join([]string{
+		base64.StdEncoding.EncodeToString(clientDataHash),
+		challenge.RelyingPartyID,
+		base64.StdEncoding.EncodeToString(credentialID),
+	}, "\n") + "\n"
+
+	var stdout bytes.Buffer
+	cmd := exec.CommandContext(ctx, fido2AssertCommand, args...) //nolint: gosec
+	cmd.Stdin = strings.NewReader(input)
+	cmd.Stdout = &stdout
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("running %s: %w", fido2AssertCommand, err)
+	}
+
+	assertion, err := parseFIDO2Assertion(stdout.Bytes())
+	if err != nil {
+		return nil, err
+	}
+	assertion.CredentialID = credentialID
+
+	return assertion, nil
+}
+
+// parseFIDO2Assertion parses the output of fido2-assert, which is the client data hash,
+// relying party id, CBOR encoded authenticator data and signature on separate lines
+func parseFIDO2Assertion(out []byte) (*WebAuthnAssertion, error) {
+	lines := []string{}
+	scanner := bufio.NewScanner(bytes.NewReader(out))
+	for scanner.Scan() {
+		lines = append(lines, strings.TrimSpace(scanner.Text()))
+	}
+	if len(lines) < 4 { //nolint: gomnd
+		return nil, fmt.Errorf("expected at least 4 lines, got %d: %w", len(lines), ErrInvalidFIDO2Output)
+	}
+
+	authDataCBOR, err := base64.StdEncoding.DecodeString(lines[2])
+	if err != nil {
+		return nil, fmt.Errorf("decoding authenticator data: %w", err)
+	}
+	authData, err := cborByteString(authDataCBOR)
+	if err != nil {
+		return nil, fmt.Errorf("decoding authenticator data: %w", err)
+	}
+	signature, err := base64.StdEncoding.DecodeString(lines[3])
+	if err != nil {
+		return nil, fmt.Errorf("decoding signature: %w", err)
+	}
+
+	assertion := &WebAuthnAssertion{
+		AuthenticatorData: authData,
+		Signature:         signature,
+	}
+	if len(lines) > 4 && lines[4] != "" { //nolint: gomnd
+		if assertion.UserHandle, err = base64.StdEncoding.DecodeString(lines[4]); err != nil {
+			return nil, fmt.Errorf("decoding user id: %w", err)
+		}
+	}
+
+	return assertion, nil
+}
+
+// cborByteString returns the contents of a CBOR encoded byte string
+func cborByteString(data []byte) ([]byte, error) {
+	if len(data) == 0 || data[0]>>5 != 2 { //nolint: gomnd
+		return nil, fmt.Errorf("not a cbor byte string: %w", ErrInvalidFIDO2Output)
+	}
+
+	length := int(data[0] & 0x1f) //nolint: gomnd
+	header := 1
+	switch {
+	case length < 24: //nolint: gomnd
+	case length == 24 && len(data) > 1: //nolint: gomnd
+		length, header = int(data[1]), 2
+	case length == 25 && len(data) > 2: //nolint: gomnd
+		length, header = int(data[1])<<8|int(data[2]), 3
+	default:
+		return nil, fmt.Errorf("unsupported cbor byte string length: %w", ErrInvalidFIDO2Output)
+	}
+	if len(data) != header+length {
+		return nil, fmt.Errorf("cbor byte string length %d doesn't match data: %w", length, ErrInvalidFIDO2Output)
+	}
+
+	return data[header:], nil
+}
@@ -0,0 +1,66 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseFIDO2Assertion(t *testing.T) {
+	g := NewWithT(t)
+
+	authData := make([]byte, 37)
+	authData[32] = 0x01
+	authDataCBOR := append([]byte{0x58, byte(len(authData))}, authData...)
+
+	out := "aGFzaA==\nlogin.example.com\n" +
+		base64.StdEncoding.EncodeToString(authDataCBOR) + "\n" +
+		base64.StdEncoding.EncodeToString([]byte("signature")) + "\n"
+
+	assertion, err := parseFIDO2Assertion([]byte(out))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(assertion.AuthenticatorData).To(Equal(authData))
+	g.Expect(assertion.Signature).To(Equal([]byte("signature")))
+	g.Expect(assertion.UserHandle).To(BeNil())
+
+	_, err = parseFIDO2Assertion([]byte("aGFzaA==\nlogin.example.com\n"))
+	g.Expect(errors.Is(err, ErrInvalidFIDO2Output)).To(BeTrue())
+
+	_, err = parseFIDO2Assertion([]byte("aGFzaA==\nlogin.example.com\nAQ==\nc2ln\n"))
+	g.Expect(errors.Is(err, ErrInvalidFIDO2Output)).To(BeTrue())
+}
+
+func TestWebAuthnClientData(t *testing.T) {
+	g := NewWithT(t)
+
+	data, err := webAuthnClientData(&WebAuthnChallenge{RelyingPartyID: "login.example.com", Challenge: []byte{0xfb, 0xff}})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	clientData := map[string]interface{}{}
+	g.Expect(json.Unmarshal(data, &clientData)).To(Succeed())
+	g.Expect(clientData).To(Equal(map[string]interface{}{
+		"type":        "webauthn.get",
+		"challenge":   "-_8",
+		"origin":      "https://login.example.com",
+		"crossOrigin": false,
+	}))
+}
@@ -2,6 +2,7 @@ package identity
 
 import (
 	"context"
+	"time"
 
 	"github.com/fidelity/kconnect/pkg/config"
 	"github.com/fidelity/kconnect/pkg/provider"
@@ -18,6 +19,11 @@ type Provider interface {
 
 type ProviderCreatorFun func(input *provider.PluginCreationInput) (Provider, error)
 
+// DetectFunc inspects the environment to see if the identity provider is likely to be
+// usable when used with the supplied discovery provider. If it is then the reason it
+// was detected is returned (e.g. an environment variable has been set).
+type DetectFunc func(scopeTo string) (reason string, detected bool)
+
 type AuthenticateInput struct {
 	ConfigSet config.ConfigurationSet
 }
@@ -35,6 +41,21 @@ type Identity interface {
 	IdentityProviderName() string
 }
 
+// Expirer is an optional interface for identities that know when their credentials
+// expire, so that the expiry can be recorded in the history
+type Expirer interface {
+	// ExpiresAt returns when the credentials expire, a zero time means it isn't known
+	ExpiresAt() time.Time
+}
+
+// Restorer is an optional interface for identity providers whose identities can be
+// restored from their JSON, so that a failed use command can be resumed without
+// authenticating again
+type Restorer interface {
+	// RestoreIdentity creates the identity from its JSON
+	RestoreIdentity(data []byte) (Identity, error)
+}
+
 // Store represents an way to store and retrieve credentials
 type Store interface {
 	CredsExists() (bool, error)
@@ -0,0 +1,189 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+
+	"go.uber.org/zap"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	"github.com/fidelity/kconnect/pkg/prompt"
+)
+
+var (
+	ErrMFAMethodUnsupported = kerrors.WithCode(kerrors.CodeMFARequired, errors.New("no mfa handler supports the mfa method"))
+	ErrMFAInputRequired     = kerrors.WithCode(kerrors.CodeMFARequired, errors.New("mfa requires input but running non-interactively"))
+	ErrMFANotApproved       = kerrors.WithCode(kerrors.CodeMFADenied, errors.New("mfa push notification wasn't approved"))
+
+	mfaHandlers     = []namedMFAHandler{}
+	mfaHandlersLock sync.RWMutex
+)
+
+// MFAMethod is the method used to complete a multi-factor authentication challenge
+type MFAMethod string
+
+var (
+	// MFAMethodTOTP is a one-time code from an authenticator app or hardware token
+	MFAMethodTOTP = MFAMethod("totp")
+	// MFAMethodPush is a push notification that's approved on the user's device
+	MFAMethodPush = MFAMethod("push")
+	// MFAMethodWebAuthn is a WebAuthn challenge signed by a FIDO2 security key
+	MFAMethodWebAuthn = MFAMethod("webauthn")
+)
+
+// MFAChallenge is a multi-factor authentication challenge from an identity provider
+type MFAChallenge struct {
+	// Method is how the challenge is completed
+	Method MFAMethod `json:"method"`
+	// Provider is the name of the identity plugin that was challenged
+	Provider string `json:"provider"`
+	// Device identifies the MFA device, e.g. the serial number or ARN of an AWS MFA device
+	Device string `json:"device,omitempty"`
+	// Message is shown to the user, e.g. the number to select in the push notification
+	Message string `json:"message,omitempty"`
+	// Interactive is true if the user can be asked for input
+	Interactive bool `json:"interactive"`
+	// WebAuthn is the challenge to sign for the webauthn method
+	WebAuthn *WebAuthnChallenge `json:"webauthn,omitempty"`
+}
+
+// WebAuthnChallenge is the challenge to sign with a FIDO2 security key
+type WebAuthnChallenge struct {
+	RelyingPartyID string   `json:"rpId"`
+	Challenge      []byte   `json:"challenge"`
+	CredentialIDs  [][]byte `json:"credentialIds,omitempty"`
+	// Origin is the origin in the client data, it defaults to https://<rpId>
+	Origin string `json:"origin,omitempty"`
+	// UserVerification is the WebAuthn user verification requirement, e.g. required
+	UserVerification string `json:"userVerification,omitempty"`
+}
+
+// MFAResponse is the response to a multi-factor authentication challenge
+type MFAResponse struct {
+	// Code is the one-time code for the totp method
+	Code string `json:"code,omitempty"`
+	// Approved is true if the push notification was approved for the push method
+	Approved bool `json:"approved,omitempty"`
+	// WebAuthn is the signed assertion for the webauthn method
+	WebAuthn *WebAuthnAssertion `json:"webauthn,omitempty"`
+}
+
+// WebAuthnAssertion is a WebAuthn challenge signed by a FIDO2 security key
+type WebAuthnAssertion struct {
+	CredentialID      []byte `json:"credentialId"`
+	AuthenticatorData []byte `json:"authenticatorData"`
+	ClientDataJSON    []byte `json:"clientDataJson"`
+	Signature         []byte `json:"signature"`
+	UserHandle        []byte `json:"userHandle,omitempty"`
+}
+
+// MFAHandler completes multi-factor authentication challenges for identity plugins. The
+// default handlers ask the user in the terminal or use a FIDO2 security key, other handlers can be registered with
+// RegisterMFAHandler, e.g. by external plugins, to support other methods or devices.
+type MFAHandler interface {
+	// Supports returns true if the handler can complete challenges of the method
+	Supports(method MFAMethod) bool
+	// HandleMFA completes the challenge
+	HandleMFA(ctx context.Context, challenge *MFAChallenge) (*MFAResponse, error)
+}
+
+type namedMFAHandler struct {
+	name    string
+	handler MFAHandler
+}
+
+// RegisterMFAHandler will register a handler for MFA challenges. Registered handlers are
+// used in the order they were registered and before the default handlers.
+func RegisterMFAHandler(name string, handler MFAHandler) {
+	mfaHandlersLock.Lock()
+	defer mfaHandlersLock.Unlock()
+
+	mfaHandlers = append(mfaHandlers, namedMFAHandler{name: name, handler: handler})
+}
+
+// HandleMFA will complete the challenge using the first handler that supports its method.
+// Identity plugins call this when they're challenged for MFA.
+func HandleMFA(ctx context.Context, challenge *MFAChallenge) (*MFAResponse, error) {
+	mfaHandlersLock.RLock()
+	handlers := append([]namedMFAHandler{}, mfaHandlers...)
+	mfaHandlersLock.RUnlock()
+	handlers = append(handlers,
+		namedMFAHandler{name: "fido2", handler: &fido2MFAHandler{}},
+		namedMFAHandler{name: "prompt", handler: &promptMFAHandler{}},
+	)
+
+	for _, h := range handlers {
+		if !h.handler.Supports(challenge.Method) {
+			continue
+		}
+		zap.S().Debugw("handling mfa challenge", "handler", h.name, "method", challenge.Method, "provider", challenge.Provider)
+
+		resp, err := h.handler.HandleMFA(ctx, challenge)
+		if err != nil {
+			return nil, fmt.Errorf("handling %s mfa challenge with %s: %w", challenge.Method, h.name, err)
+		}
+
+		return resp, nil
+	}
+
+	return nil, fmt.Errorf("handling %s mfa challenge: %w", challenge.Method, ErrMFAMethodUnsupported)
+}
+
+// promptMFAHandler is the default handler, it asks the user for TOTP codes and to
+// approve push notifications
+type promptMFAHandler struct{}
+
+func (h *promptMFAHandler) Supports(method MFAMethod) bool {
+	return method == MFAMethodTOTP || method == MFAMethodPush
+}
+
+func (h *promptMFAHandler) HandleMFA(ctx context.Context, challenge *MFAChallenge) (*MFAResponse, error) {
+	if !challenge.Interactive {
+		return nil, ErrMFAInputRequired
+	}
+
+	if challenge.Method == MFAMethodPush {
+		message := "Approve the push notification on your device, then confirm"
+		if challenge.Message != "" {
+			message = fmt.Sprintf("%s (%s)", message, challenge.Message)
+		}
+		approved, err := prompt.Confirm("mfa-push", message, true)
+		if err != nil {
+			return nil, err
+		}
+		if !approved {
+			return nil, ErrMFANotApproved
+		}
+
+		return &MFAResponse{Approved: true}, nil
+	}
+
+	message := "Enter the MFA token code"
+	if challenge.Device != "" {
+		message = fmt.Sprintf("Enter the MFA token code for %s", challenge.Device)
+	}
+	code, err := prompt.Input("mfa-token", message, true)
+	if err != nil {
+		return nil, err
+	}
+
+	return &MFAResponse{Code: code}, nil
+}
@@ -0,0 +1,93 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/prompt"
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+type fakeMFAHandler struct {
+	method identity.MFAMethod
+	code   string
+}
+
+func (h *fakeMFAHandler) Supports(method identity.MFAMethod) bool {
+	return method == h.method
+}
+
+func (h *fakeMFAHandler) HandleMFA(ctx context.Context, challenge *identity.MFAChallenge) (*identity.MFAResponse, error) {
+	return &identity.MFAResponse{Code: h.code}, nil
+}
+
+func TestHandleMFAPrompt(t *testing.T) {
+	g := NewWithT(t)
+
+	out := &bytes.Buffer{}
+	prompt.SetBackend(prompt.NewJSONBackend(strings.NewReader(`{"value":"123456"}
+{"value":"true"}
+`), out))
+	defer prompt.SetBackend(mustBackend(t))
+	// Without fido2-assert on the PATH there's no handler for webauthn
+	t.Setenv("PATH", t.TempDir())
+	ctx := context.Background()
+
+	resp, err := identity.HandleMFA(ctx, &identity.MFAChallenge{Method: identity.MFAMethodTOTP, Device: "arn:aws:iam::123456789012:mfa/bob", Interactive: true})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Code).To(Equal("123456"))
+
+	resp, err = identity.HandleMFA(ctx, &identity.MFAChallenge{Method: identity.MFAMethodPush, Interactive: true})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Approved).To(BeTrue())
+
+	_, err = identity.HandleMFA(ctx, &identity.MFAChallenge{Method: identity.MFAMethodTOTP})
+	g.Expect(errors.Is(err, identity.ErrMFAInputRequired)).To(BeTrue())
+
+	_, err = identity.HandleMFA(ctx, &identity.MFAChallenge{Method: identity.MFAMethodWebAuthn, Interactive: true})
+	g.Expect(errors.Is(err, identity.ErrMFAMethodUnsupported)).To(BeTrue())
+
+	g.Expect(out.String()).To(Equal(`{"type":"input","name":"mfa-token","message":"Enter the MFA token code for arn:aws:iam::123456789012:mfa/bob","required":true}
+{"type":"confirm","name":"mfa-push","message":"Approve the push notification on your device, then confirm","required":true}
+`))
+}
+
+func TestHandleMFARegistered(t *testing.T) {
+	g := NewWithT(t)
+
+	identity.RegisterMFAHandler("fake", &fakeMFAHandler{method: identity.MFAMethodTOTP, code: "654321"})
+
+	resp, err := identity.HandleMFA(context.Background(), &identity.MFAChallenge{Method: identity.MFAMethodTOTP})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(resp.Code).To(Equal("654321"))
+}
+
+func mustBackend(t *testing.T) prompt.Backend {
+	backend, err := prompt.NewBackend(prompt.BackendTerminal, nil, nil)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	return backend
+}
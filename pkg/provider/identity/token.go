@@ -1,6 +1,11 @@
 package identity
 
-import "errors"
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
 
 var (
 	ErrNotTokenIdentity = errors.New("not a token identity")
@@ -10,6 +15,7 @@ type TokenIdentity struct {
 	token          string
 	name           string
 	idProviderName string
+	expiresAt      time.Time
 }
 
 func NewTokenIdentity(name, token, idProviderName string) *TokenIdentity {
@@ -29,8 +35,21 @@ func (t *TokenIdentity) Name() string {
 }
 
 func (t *TokenIdentity) IsExpired() bool {
-	// TODO: handle properly
-	return false
+	if t.expiresAt.IsZero() {
+		return false
+	}
+
+	return time.Now().After(t.expiresAt)
+}
+
+// ExpiresAt returns when the token expires, a zero time means it isn't known
+func (t *TokenIdentity) ExpiresAt() time.Time {
+	return t.expiresAt
+}
+
+// SetExpiresAt sets when the token expires
+func (t *TokenIdentity) SetExpiresAt(expiresAt time.Time) {
+	t.expiresAt = expiresAt
 }
 
 func (t *TokenIdentity) IdentityProviderName() string {
@@ -40,3 +59,43 @@ func (t *TokenIdentity) IdentityProviderName() string {
 func (t *TokenIdentity) Token() string {
 	return t.token
 }
+
+// tokenIdentityJSON is the JSON of a token identity
+type tokenIdentityJSON struct {
+	Name           string    `json:"name"`
+	Token          string    `json:"token"`
+	IDProviderName string    `json:"idProviderName"`
+	ExpiresAt      time.Time `json:"expiresAt,omitempty"`
+}
+
+func (t *TokenIdentity) MarshalJSON() ([]byte, error) {
+	return json.Marshal(&tokenIdentityJSON{
+		Name:           t.name,
+		Token:          t.token,
+		IDProviderName: t.idProviderName,
+		ExpiresAt:      t.expiresAt,
+	})
+}
+
+func (t *TokenIdentity) UnmarshalJSON(data []byte) error {
+	id := &tokenIdentityJSON{}
+	if err := json.Unmarshal(data, id); err != nil {
+		return err
+	}
+	t.name = id.Name
+	t.token = id.Token
+	t.idProviderName = id.IDProviderName
+	t.expiresAt = id.ExpiresAt
+
+	return nil
+}
+
+// RestoreTokenIdentity creates a token identity from its JSON
+func RestoreTokenIdentity(data []byte) (Identity, error) {
+	id := &TokenIdentity{}
+	if err := json.Unmarshal(data, id); err != nil {
+		return nil, fmt.Errorf("unmarshalling token identity: %w", err)
+	}
+
+	return id, nil
+}
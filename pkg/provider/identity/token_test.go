@@ -0,0 +1,49 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package identity_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/provider/identity"
+)
+
+func TestRestoreTokenIdentity(t *testing.T) {
+	g := NewWithT(t)
+
+	expiresAt := time.Now().Add(-time.Hour).UTC().Truncate(time.Second)
+	id := identity.NewTokenIdentity("user1", "token1", "openshift-oauth")
+	id.SetExpiresAt(expiresAt)
+
+	data, err := json.Marshal(id)
+	g.Expect(err).NotTo(HaveOccurred())
+
+	restored, err := identity.RestoreTokenIdentity(data)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(restored.Name()).To(Equal("user1"))
+	g.Expect(restored.IdentityProviderName()).To(Equal("openshift-oauth"))
+
+	tokenID, ok := restored.(*identity.TokenIdentity)
+	g.Expect(ok).To(BeTrue())
+	g.Expect(tokenID.Token()).To(Equal("token1"))
+	g.Expect(tokenID.ExpiresAt()).To(BeTemporally("==", expiresAt))
+	g.Expect(tokenID.IsExpired()).To(BeTrue())
+}
@@ -19,8 +19,10 @@ package registry
 import (
 	"errors"
 	"fmt"
+	"sort"
 	"sync"
 
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
 	"github.com/fidelity/kconnect/pkg/provider"
 	"github.com/fidelity/kconnect/pkg/provider/discovery"
 	"github.com/fidelity/kconnect/pkg/provider/identity"
@@ -28,29 +30,103 @@ import (
 
 var (
 	ErrDuplicatePlugin = errors.New("plugin already registered with same name")
-	ErrPluginNotFound  = errors.New("plugin not found")
+	ErrPluginNotFound  = kerrors.WithCode(kerrors.CodePluginUnavailable, errors.New("plugin not found"))
+	ErrPluginDisabled  = kerrors.WithCode(kerrors.CodePluginUnavailable, errors.New("plugin has been disabled by the app configuration"))
+
+	ErrUnsupportedAPIVersion = errors.New("plugin api version not supported")
+)
+
+const (
+	// PluginAPIVersion is the current version of the plugin api. Plugins that don't
+	// declare a version are assumed to use this version.
+	PluginAPIVersion = "v1"
+)
+
+// SupportedAPIVersions are the plugin api versions that can be registered
+var SupportedAPIVersions = []string{PluginAPIVersion}
+
+// Capability is an optional behaviour that a plugin declares it supports, this is
+// used by the app to adapt to the plugin
+type Capability string
+
+var (
+	// CapabilitySupportsFiltering means the discovery plugin applies the cluster-filter itself
+	CapabilitySupportsFiltering = Capability("supports-filtering")
+	// CapabilitySupportsPagination means the discovery plugin pages through all the
+	// clusters from the provider and not just the first page
+	CapabilitySupportsPagination = Capability("supports-pagination")
+	// CapabilitySupportsRefresh means the discovery plugin can get the latest details
+	// of a single cluster using its id
+	CapabilitySupportsRefresh = Capability("supports-refresh")
+	// CapabilityInteractiveRequired means the plugin can only be used interactively
+	CapabilityInteractiveRequired = Capability("interactive-required")
 )
 
 var (
 	pluginsLock      sync.Mutex
 	identityPlugins  = make(map[string]*IdentityPluginRegistration)
 	discoveryPlugins = make(map[string]*DiscoveryPluginRegistration)
+	enrichers        = make(map[string]discovery.Enricher)
+	disabledPlugins  = make(map[string]bool)
 )
 
 type PluginRegistration struct {
 	Name                   string
 	UsageExample           string
 	ConfigurationItemsFunc provider.ConfigurationItemsFunc
+	// APIVersion is the version of the plugin api the plugin implements
+	APIVersion string
+	// Capabilities are the optional behaviours the plugin supports
+	Capabilities []Capability
+	// Path is the location of the executable for an external plugin and is empty
+	// for a built-in plugin
+	Path string
+}
+
+// HasCapability returns true if the plugin declares that it supports the capability
+func (r *PluginRegistration) HasCapability(capability Capability) bool {
+	for _, declared := range r.Capabilities {
+		if declared == capability {
+			return true
+		}
+	}
+
+	return false
 }
+
+func (r *PluginRegistration) checkAPIVersion() error {
+	if r.APIVersion == "" {
+		r.APIVersion = PluginAPIVersion
+	}
+
+	for _, version := range SupportedAPIVersions {
+		if r.APIVersion == version {
+			return nil
+		}
+	}
+
+	return fmt.Errorf("registering plugin %s with api version %s: %w", r.Name, r.APIVersion, ErrUnsupportedAPIVersion)
+}
+
 type DiscoveryPluginRegistration struct {
 	PluginRegistration
 	SupportedIdentityProviders []string
 	CreateFunc                 discovery.ProviderCreatorFun
+	// Columns is optional and is the annotations of the discovered clusters to show
+	// when selecting a cluster, e.g. to tell apart clusters with the same name
+	Columns []string
 }
 
 type IdentityPluginRegistration struct {
 	PluginRegistration
 	CreateFunc identity.ProviderCreatorFun
+	// DetectFunc is optional and is used to select the identity provider when
+	// one hasn't been specified
+	DetectFunc identity.DetectFunc
+	// IdentitiesConfigItem is optional and is the config item that chooses one of
+	// the identities of the user, e.g. a role or tenant. Clusters can be discovered
+	// with several identities at once by setting it to each of the --identities.
+	IdentitiesConfigItem string
 }
 
 func RegisterIdentityPlugin(registration *IdentityPluginRegistration) error {
@@ -60,6 +136,9 @@ func RegisterIdentityPlugin(registration *IdentityPluginRegistration) error {
 	if _, found := identityPlugins[registration.Name]; found {
 		return ErrDuplicatePlugin
 	}
+	if err := registration.checkAPIVersion(); err != nil {
+		return err
+	}
 	identityPlugins[registration.Name] = registration
 
 	return nil
@@ -72,6 +151,9 @@ func RegisterDiscoveryPlugin(registration *DiscoveryPluginRegistration) error {
 	if _, found := discoveryPlugins[registration.Name]; found {
 		return ErrDuplicatePlugin
 	}
+	if err := registration.checkAPIVersion(); err != nil {
+		return err
+	}
 	discoveryPlugins[registration.Name] = registration
 
 	return nil
@@ -81,6 +163,9 @@ func GetDiscoveryProvider(name string, input *provider.PluginCreationInput) (dis
 	pluginsLock.Lock()
 	defer pluginsLock.Unlock()
 
+	if disabledPlugins[name] {
+		return nil, fmt.Errorf("getting cluster plugin %s: %w", name, ErrPluginDisabled)
+	}
 	if registration, found := discoveryPlugins[name]; found {
 		return registration.CreateFunc(input)
 	}
@@ -104,6 +189,9 @@ func GetIdentityProvider(name string, input *provider.PluginCreationInput) (iden
 	pluginsLock.Lock()
 	defer pluginsLock.Unlock()
 
+	if disabledPlugins[name] {
+		return nil, fmt.Errorf("getting identity plugin %s: %w", name, ErrPluginDisabled)
+	}
 	if registration, found := identityPlugins[name]; found {
 		return registration.CreateFunc(input)
 	}
@@ -148,3 +236,54 @@ func ListIdentityPluginRegistrations() []*IdentityPluginRegistration {
 
 	return plugins
 }
+
+// RegisterEnricher will register an enricher that adds details to the discovered clusters
+func RegisterEnricher(enricher discovery.Enricher) error {
+	pluginsLock.Lock()
+	defer pluginsLock.Unlock()
+
+	if _, found := enrichers[enricher.Name()]; found {
+		return ErrDuplicatePlugin
+	}
+	enrichers[enricher.Name()] = enricher
+
+	return nil
+}
+
+// ListEnrichers returns the enrichers that haven't been disabled, ordered by name
+func ListEnrichers() []discovery.Enricher {
+	pluginsLock.Lock()
+	defer pluginsLock.Unlock()
+
+	list := []discovery.Enricher{}
+	for name, enricher := range enrichers {
+		if disabledPlugins[name] {
+			continue
+		}
+		list = append(list, enricher)
+	}
+	sort.Slice(list, func(i, j int) bool {
+		return list[i].Name() < list[j].Name()
+	})
+
+	return list
+}
+
+// DisablePlugins will disable the discovery or identity plugins with the supplied names. A disabled
+// plugin is still registered but it can't be created.
+func DisablePlugins(names []string) {
+	pluginsLock.Lock()
+	defer pluginsLock.Unlock()
+
+	for _, name := range names {
+		disabledPlugins[name] = true
+	}
+}
+
+// IsPluginDisabled returns true if the plugin with the supplied name has been disabled
+func IsPluginDisabled(name string) bool {
+	pluginsLock.Lock()
+	defer pluginsLock.Unlock()
+
+	return disabledPlugins[name]
+}
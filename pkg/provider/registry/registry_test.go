@@ -0,0 +1,120 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package registry_test
+
+import (
+	"context"
+	"testing"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/provider/discovery"
+	"github.com/fidelity/kconnect/pkg/provider/registry"
+)
+
+func TestRegisterPluginAPIVersion(t *testing.T) {
+	testCases := []struct {
+		name          string
+		apiVersion    string
+		expectVersion string
+		expectErr     error
+	}{
+		{
+			name:          "no version",
+			apiVersion:    "",
+			expectVersion: registry.PluginAPIVersion,
+		},
+		{
+			name:          "current version",
+			apiVersion:    registry.PluginAPIVersion,
+			expectVersion: registry.PluginAPIVersion,
+		},
+		{
+			name:       "unsupported version",
+			apiVersion: "v99",
+			expectErr:  registry.ErrUnsupportedAPIVersion,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			registration := &registry.IdentityPluginRegistration{
+				PluginRegistration: registry.PluginRegistration{
+					Name:       "api-version-" + tc.name, //nolint:scopelint
+					APIVersion: tc.apiVersion,            //nolint:scopelint
+				},
+			}
+			err := registry.RegisterIdentityPlugin(registration)
+
+			if tc.expectErr != nil { //nolint:scopelint
+				g.Expect(err).To(MatchError(tc.expectErr)) //nolint:scopelint
+				_, err = registry.GetIdentityProviderRegistration(registration.Name)
+				g.Expect(err).To(MatchError(registry.ErrPluginNotFound))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(registration.APIVersion).To(Equal(tc.expectVersion)) //nolint:scopelint
+		})
+	}
+}
+
+func TestHasCapability(t *testing.T) {
+	g := NewWithT(t)
+
+	registration := &registry.PluginRegistration{
+		Name:         "capabilities",
+		Capabilities: []registry.Capability{registry.CapabilitySupportsRefresh},
+	}
+
+	g.Expect(registration.HasCapability(registry.CapabilitySupportsRefresh)).To(BeTrue())
+	g.Expect(registration.HasCapability(registry.CapabilitySupportsFiltering)).To(BeFalse())
+}
+
+func TestRegisterEnricher(t *testing.T) {
+	g := NewWithT(t)
+
+	g.Expect(registry.RegisterEnricher(&fakeEnricher{name: "enricher-b"})).To(Succeed())
+	g.Expect(registry.RegisterEnricher(&fakeEnricher{name: "enricher-a"})).To(Succeed())
+	g.Expect(registry.RegisterEnricher(&fakeEnricher{name: "enricher-c"})).To(Succeed())
+	g.Expect(registry.RegisterEnricher(&fakeEnricher{name: "enricher-a"})).To(MatchError(registry.ErrDuplicatePlugin))
+
+	registry.DisablePlugins([]string{"enricher-c"})
+
+	names := []string{}
+	for _, enricher := range registry.ListEnrichers() {
+		names = append(names, enricher.Name())
+	}
+	g.Expect(names).To(Equal([]string{"enricher-a", "enricher-b"}))
+}
+
+type fakeEnricher struct {
+	name string
+}
+
+func (f *fakeEnricher) Name() string {
+	return f.name
+}
+
+func (f *fakeEnricher) Columns() []string {
+	return []string{}
+}
+
+func (f *fakeEnricher) Enrich(ctx context.Context, cluster *discovery.Cluster) error {
+	return nil
+}
@@ -17,6 +17,8 @@ limitations under the License.
 package provider
 
 import (
+	"context"
+
 	"go.uber.org/zap"
 
 	"github.com/fidelity/kconnect/pkg/config"
@@ -32,7 +34,7 @@ type Plugin interface {
 // PluginPreReqs is an interface that providers have implement to
 // indicate that they have pre-requisites that they can check for
 type PluginPreReqs interface {
-	ListPreReqs() []*PreReq
+	ListPreReqs() []PreReq
 	CheckPreReqs() error
 }
 
@@ -43,6 +45,13 @@ type PreReq interface {
 	Check() error
 }
 
+// InstallablePreReq represents a pre-requisite that kconnect can install
+type InstallablePreReq interface {
+	PreReq
+	// Install will install the pre-requisite and return the path it was installed to
+	Install(ctx context.Context) (string, error)
+}
+
 // PluginCreationInput is the input to plugin Init
 type PluginCreationInput struct {
 	Logger        *zap.SugaredLogger
@@ -50,6 +59,9 @@ type PluginCreationInput struct {
 	ItemSelector  SelectItemFunc
 	ScopedTo      *string
 	HTTPClient    khttp.Client
+	// HTTPClientOptions are the options the HTTPClient was created with, e.g. the
+	// proxy, for plugins that need to create their own client
+	HTTPClientOptions []khttp.ClientOption
 }
 
 // ConfigurationItemsFunc is a function type that gets configuration items.
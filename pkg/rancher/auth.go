@@ -0,0 +1,50 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rancher
+
+// AuthProvider is a type that represents the Rancher auth provider to log in with
+type AuthProvider string
+
+var (
+	// AuthProviderLocal is for Rancher local users
+	AuthProviderLocal = AuthProvider("local")
+	// AuthProviderActiveDirectory is for users of Active Directory
+	AuthProviderActiveDirectory = AuthProvider("activedirectory")
+	// AuthProviderGitHub is for users that log in with GitHub
+	AuthProviderGitHub = AuthProvider("github")
+	// AuthProviderSAML is for users that log in with a SAML identity provider
+	AuthProviderSAML = AuthProvider("saml")
+	// AuthProviderOIDC is for users that log in with an OIDC identity provider
+	AuthProviderOIDC = AuthProvider("oidc")
+)
+
+// AuthProviderValues returns the names of the supported auth providers
+func AuthProviderValues() []string {
+	return []string{
+		string(AuthProviderLocal),
+		string(AuthProviderActiveDirectory),
+		string(AuthProviderGitHub),
+		string(AuthProviderSAML),
+		string(AuthProviderOIDC),
+	}
+}
+
+// UsesPassword returns true if the auth provider logs in using the username and password.
+// The other auth providers log in using the browser.
+func (a AuthProvider) UsesPassword() bool {
+	return a == AuthProviderLocal || a == AuthProviderActiveDirectory
+}
@@ -25,6 +25,8 @@ import (
 const (
 	// APIEndpointConfigName is the name of the config item for the Rancher API endpoint
 	APIEndpointConfigName = "api-endpoint"
+	// AuthProviderConfigName is the name of the config item for the Rancher auth provider to log in with
+	AuthProviderConfigName = "rancher-auth-provider"
 )
 
 // CommonConfig represents the common configuration for Rancher
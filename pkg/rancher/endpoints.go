@@ -18,19 +18,28 @@ package rancher
 
 import (
 	"fmt"
+	"net/url"
 	"strings"
 )
 
 const (
-	adAuthTemplate   = "%s-public/activeDirectoryProviders/activedirectory?action=login"
-	clustersTemplate = "%s/clusters"
-	clusterTemplate  = "%s/clusters/%s"
+	adAuthTemplate         = "%s-public/activeDirectoryProviders/activedirectory?action=login"
+	localAuthTemplate      = "%s-public/localProviders/local?action=login"
+	authTokenTemplate      = "%s-public/authTokens/%s"
+	dashboardLoginTemplate = "%s/dashboard/auth/login?requestId=%s&publicKey=%s&responseType=kubeconfig"
+	clustersTemplate       = "%s/clusters"
+	clusterTemplate        = "%s/clusters/%s"
+	tokenTemplate          = "%s/tokens/%s"
 )
 
 type EndpointsResolver interface {
 	ActiveDirectoryAuth() string
+	PasswordAuth(provider AuthProvider) string
+	AuthToken(requestID string) string
+	DashboardLogin(requestID, publicKey string) string
 	ClustersList() string
 	Cluster(clusterName string) string
+	Token(tokenName string) string
 }
 
 func NewStaticEndpointsResolver(apiEndpoint string) (EndpointsResolver, error) {
@@ -55,6 +64,27 @@ func (r *StaticEndpointsResolver) ActiveDirectoryAuth() string {
 	return fmt.Sprintf(adAuthTemplate, r.apiEndpoint)
 }
 
+// PasswordAuth returns the login endpoint of an auth provider that uses a username and password
+func (r *StaticEndpointsResolver) PasswordAuth(provider AuthProvider) string {
+	if provider == AuthProviderLocal {
+		return fmt.Sprintf(localAuthTemplate, r.apiEndpoint)
+	}
+
+	return r.ActiveDirectoryAuth()
+}
+
+// AuthToken returns the endpoint to poll for the token of a browser login request
+func (r *StaticEndpointsResolver) AuthToken(requestID string) string {
+	return fmt.Sprintf(authTokenTemplate, r.apiEndpoint, requestID)
+}
+
+// DashboardLogin returns the URL of the Rancher dashboard to complete a browser login request
+func (r *StaticEndpointsResolver) DashboardLogin(requestID, publicKey string) string {
+	serverURL := strings.TrimSuffix(r.apiEndpoint, "/v3")
+
+	return fmt.Sprintf(dashboardLoginTemplate, serverURL, requestID, url.QueryEscape(publicKey))
+}
+
 func (r *StaticEndpointsResolver) ClustersList() string {
 	return fmt.Sprintf(clustersTemplate, r.apiEndpoint)
 }
@@ -62,3 +92,7 @@ func (r *StaticEndpointsResolver) ClustersList() string {
 func (r *StaticEndpointsResolver) Cluster(clusterName string) string {
 	return fmt.Sprintf(clusterTemplate, r.apiEndpoint, clusterName)
 }
+
+func (r *StaticEndpointsResolver) Token(tokenName string) string {
+	return fmt.Sprintf(tokenTemplate, r.apiEndpoint, tokenName)
+}
@@ -19,5 +19,6 @@ package rancher
 import "errors"
 
 var (
-	ErrNoAPIEndpoint = errors.New("no rancher api endpoint")
+	ErrNoAPIEndpoint      = errors.New("no rancher api endpoint")
+	ErrUnexpectedResponse = errors.New("unexpected response from rancher api")
 )
@@ -0,0 +1,155 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package rancher
+
+import (
+	"fmt"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+	khttp "github.com/fidelity/kconnect/pkg/http"
+	"github.com/fidelity/kconnect/pkg/secrets"
+)
+
+const (
+	// TokenTTLConfigName is the name of the config item for the lifetime of the tokens created by kconnect
+	TokenTTLConfigName = "token-ttl"
+	// DefaultTokenTTL is the default lifetime of the tokens created by kconnect
+	DefaultTokenTTL = 12 * time.Hour
+
+	// tokenRenewBefore is how long before it expires that a stored token is replaced
+	tokenRenewBefore = 10 * time.Minute
+)
+
+// StoredToken is a Rancher token created by kconnect that is reused until it's near expiry
+type StoredToken struct {
+	Token     string    `json:"token"`
+	UserID    string    `json:"user-id"`
+	ExpiresAt time.Time `json:"expires-at"`
+}
+
+// Name returns the name of the Rancher token resource
+func (t *StoredToken) Name() string {
+	return TokenName(t.Token)
+}
+
+// NearExpiry returns true if the token should be replaced with a new one
+func (t *StoredToken) NearExpiry() bool {
+	return time.Until(t.ExpiresAt) <= tokenRenewBefore
+}
+
+// TokenStore stores the tokens created by kconnect for each Rancher endpoint, auth provider and user
+type TokenStore struct {
+	store secrets.Store
+}
+
+// NewTokenStore creates a token store that keeps the tokens in the secret store
+// chosen in the app config
+func NewTokenStore() *TokenStore {
+	return &TokenStore{
+		store: secrets.Default(),
+	}
+}
+
+// Get returns the stored token for the user or nil if there is no token or it's near expiry
+func (s *TokenStore) Get(apiEndpoint string, authProvider AuthProvider, username string) (*StoredToken, error) {
+	token := &StoredToken{}
+	found, err := secrets.NewCache(s.store, DefaultTokenTTL).Get(tokenKey(apiEndpoint, authProvider, username), token)
+	if err != nil {
+		return nil, fmt.Errorf("reading stored rancher token: %w", err)
+	}
+	if !found || token.NearExpiry() {
+		return nil, nil
+	}
+
+	return token, nil
+}
+
+// Save stores the token for the user until it's near expiry. Tokens that never expire
+// are not stored.
+func (s *TokenStore) Save(apiEndpoint string, authProvider AuthProvider, username string, token *StoredToken) error {
+	if token.ExpiresAt.IsZero() || token.NearExpiry() {
+		return nil
+	}
+	ttl := time.Until(token.ExpiresAt) - tokenRenewBefore
+	if err := secrets.NewCache(s.store, ttl).Set(tokenKey(apiEndpoint, authProvider, username), token); err != nil {
+		return fmt.Errorf("storing rancher token: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the stored token for the user
+func (s *TokenStore) Delete(apiEndpoint string, authProvider AuthProvider, username string) error {
+	if err := secrets.NewCache(s.store, DefaultTokenTTL).Delete(tokenKey(apiEndpoint, authProvider, username)); err != nil {
+		return fmt.Errorf("deleting stored rancher token: %w", err)
+	}
+
+	return nil
+}
+
+func tokenKey(apiEndpoint string, authProvider AuthProvider, username string) string {
+	return fmt.Sprintf("rancher/tokens/%s/%s/%s", strings.TrimSuffix(apiEndpoint, "/"), authProvider, strings.ToLower(username))
+}
+
+// TokenName returns the name of the Rancher token resource from a token, which is
+// in the format name:secret
+func TokenName(token string) string {
+	parts := strings.SplitN(token, ":", 2)
+
+	return parts[0]
+}
+
+// IsTokenValid returns true if the Rancher API accepts the token
+func IsTokenValid(httpClient khttp.Client, resolver EndpointsResolver, token string) (bool, error) {
+	headers := defaults.Headers(defaults.WithJSON(), defaults.WithBearerAuth(token))
+	resp, err := httpClient.Get(resolver.Token(TokenName(token)), headers)
+	if err != nil {
+		return false, fmt.Errorf("getting rancher token: %w", err)
+	}
+
+	switch resp.ResponseCode() {
+	case http.StatusOK:
+		return true, nil
+	case http.StatusUnauthorized, http.StatusForbidden, http.StatusNotFound:
+		return false, nil
+	default:
+		return false, fmt.Errorf("getting rancher token, status code %d: %w", resp.ResponseCode(), ErrUnexpectedResponse)
+	}
+}
+
+// RevokeToken deletes the named token using the bearer token. A token that
+// doesn't exist is treated as already revoked.
+func RevokeToken(httpClient khttp.Client, resolver EndpointsResolver, bearerToken, name string) error {
+	resp, err := httpClient.Do(&khttp.ClientRequest{
+		URL:     resolver.Token(name),
+		Method:  http.MethodDelete,
+		Headers: defaults.Headers(defaults.WithJSON(), defaults.WithBearerAuth(bearerToken)),
+	})
+	if err != nil {
+		return fmt.Errorf("revoking rancher token %s: %w", name, err)
+	}
+
+	switch resp.ResponseCode() {
+	case http.StatusOK, http.StatusNoContent, http.StatusNotFound:
+		return nil
+	default:
+		return fmt.Errorf("revoking rancher token %s, status code %d: %w", name, resp.ResponseCode(), ErrUnexpectedResponse)
+	}
+}
@@ -0,0 +1,163 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/config"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+
+	kconnectv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+)
+
+const defaultAWSSecretsManagerPrefix = "kconnect/"
+
+// SecretsManagerAPI is the part of the Secrets Manager API that's used to keep the secrets
+type SecretsManagerAPI interface {
+	GetSecretValue(ctx context.Context, params *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error)
+	PutSecretValue(ctx context.Context, params *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error)
+	CreateSecret(ctx context.Context, params *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error)
+	DeleteSecret(ctx context.Context, params *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error)
+}
+
+// NewAWSSecretsManagerStore creates a store that keeps the secrets in AWS Secrets Manager.
+// The credentials are resolved in the same way as the AWS CLI.
+func NewAWSSecretsManagerStore(cfg *kconnectv1alpha.AWSSecretsManagerSecretStore) (*AWSSecretsManagerStore, error) {
+	if cfg == nil {
+		cfg = &kconnectv1alpha.AWSSecretsManagerSecretStore{}
+	}
+
+	options := []func(*config.LoadOptions) error{}
+	if cfg.Region != "" {
+		options = append(options, config.WithRegion(cfg.Region))
+	}
+	if cfg.Profile != "" {
+		options = append(options, config.WithSharedConfigProfile(cfg.Profile))
+	}
+	awsCfg, err := config.LoadDefaultConfig(context.Background(), options...)
+	if err != nil {
+		return nil, fmt.Errorf("loading aws config: %w", err)
+	}
+
+	return NewAWSSecretsManagerStoreWithClient(secretsmanager.NewFromConfig(awsCfg), cfg), nil
+}
+
+// NewAWSSecretsManagerStoreWithClient creates a store that uses the supplied Secrets Manager client
+func NewAWSSecretsManagerStoreWithClient(client SecretsManagerAPI, cfg *kconnectv1alpha.AWSSecretsManagerSecretStore) *AWSSecretsManagerStore {
+	if cfg == nil {
+		cfg = &kconnectv1alpha.AWSSecretsManagerSecretStore{}
+	}
+
+	return &AWSSecretsManagerStore{
+		client:   client,
+		prefix:   firstNonEmpty(cfg.Prefix, defaultAWSSecretsManagerPrefix),
+		kmsKeyID: cfg.KMSKeyID,
+	}
+}
+
+// AWSSecretsManagerStore is a secret store that uses AWS Secrets Manager
+type AWSSecretsManagerStore struct {
+	client   SecretsManagerAPI
+	prefix   string
+	kmsKeyID string
+}
+
+func (s *AWSSecretsManagerStore) Type() string {
+	return TypeAWSSecretsManager
+}
+
+func (s *AWSSecretsManagerStore) Get(key string) (string, bool, error) {
+	if key == "" {
+		return "", false, ErrKeyRequired
+	}
+
+	output, err := s.client.GetSecretValue(context.Background(), &secretsmanager.GetSecretValueInput{
+		SecretId: aws.String(s.name(key)),
+	})
+	if isSecretNotFound(err) {
+		return "", false, nil
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("getting secret from aws secrets manager: %w", err)
+	}
+
+	return aws.ToString(output.SecretString), true, nil
+}
+
+// Set will update the value of the secret, the secret is created if it doesn't exist
+func (s *AWSSecretsManagerStore) Set(key, value string) error {
+	if key == "" {
+		return ErrKeyRequired
+	}
+
+	_, err := s.client.PutSecretValue(context.Background(), &secretsmanager.PutSecretValueInput{
+		SecretId:     aws.String(s.name(key)),
+		SecretString: aws.String(value),
+	})
+	if err == nil {
+		return nil
+	}
+	if !isSecretNotFound(err) {
+		return fmt.Errorf("putting secret in aws secrets manager: %w", err)
+	}
+
+	input := &secretsmanager.CreateSecretInput{
+		Name:         aws.String(s.name(key)),
+		Description:  aws.String("Created by kconnect"),
+		SecretString: aws.String(value),
+	}
+	if s.kmsKeyID != "" {
+		input.KmsKeyId = aws.String(s.kmsKeyID)
+	}
+	if _, err := s.client.CreateSecret(context.Background(), input); err != nil {
+		return fmt.Errorf("creating secret in aws secrets manager: %w", err)
+	}
+
+	return nil
+}
+
+// Delete removes the secret without a recovery window so that the key can be set again
+func (s *AWSSecretsManagerStore) Delete(key string) error {
+	if key == "" {
+		return ErrKeyRequired
+	}
+
+	_, err := s.client.DeleteSecret(context.Background(), &secretsmanager.DeleteSecretInput{
+		SecretId:                   aws.String(s.name(key)),
+		ForceDeleteWithoutRecovery: aws.Bool(true),
+	})
+	if err != nil && !isSecretNotFound(err) {
+		return fmt.Errorf("deleting secret from aws secrets manager: %w", err)
+	}
+
+	return nil
+}
+
+func (s *AWSSecretsManagerStore) name(key string) string {
+	return s.prefix + secretName(key)
+}
+
+func isSecretNotFound(err error) bool {
+	var notFound *types.ResourceNotFoundException
+
+	return errors.As(err, &notFound)
+}
@@ -0,0 +1,106 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/fidelity/kconnect/pkg/cache"
+)
+
+// NewCache creates a cache for sensitive values, e.g. credentials, that keeps them in the
+// secret store until they expire. The file store keeps them in the kconnect cache
+// directory, the same as the other cached values. A ttl of zero or less disables the
+// cache.
+func NewCache(store Store, ttl time.Duration) cache.Cache {
+	if fileStore, ok := store.(*FileStore); ok {
+		return cache.New(fileStore.cacheDirectory, ttl)
+	}
+
+	return &secretCache{
+		store: store,
+		ttl:   ttl,
+		now:   time.Now,
+	}
+}
+
+type cacheEntry struct {
+	Expires time.Time       `json:"expires"`
+	Value   json.RawMessage `json:"value"`
+}
+
+type secretCache struct {
+	store Store
+	ttl   time.Duration
+	now   func() time.Time
+}
+
+func (c *secretCache) Get(key string, out interface{}) (bool, error) {
+	if c.ttl <= 0 {
+		return false, nil
+	}
+
+	data, found, err := c.store.Get(key)
+	if err != nil || !found {
+		return false, err
+	}
+
+	cached := &cacheEntry{}
+	if err := json.Unmarshal([]byte(data), cached); err != nil {
+		// A corrupt value is treated as a miss and is replaced on the next set
+		return false, nil //nolint: nilerr
+	}
+	if !c.now().Before(cached.Expires) {
+		return false, nil
+	}
+
+	if err := json.Unmarshal(cached.Value, out); err != nil {
+		return false, fmt.Errorf("unmarshalling cached value: %w", err)
+	}
+
+	return true, nil
+}
+
+func (c *secretCache) Set(key string, value interface{}) error {
+	if c.ttl <= 0 {
+		return nil
+	}
+
+	valueData, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshalling value: %w", err)
+	}
+	data, err := json.Marshal(&cacheEntry{
+		Expires: c.now().Add(c.ttl),
+		Value:   valueData,
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling cache entry: %w", err)
+	}
+
+	if err := c.store.Set(key, string(data)); err != nil {
+		return fmt.Errorf("storing cached value in %s secret store: %w", c.store.Type(), err)
+	}
+
+	return nil
+}
+
+func (c *secretCache) Delete(key string) error {
+	return c.store.Delete(key)
+}
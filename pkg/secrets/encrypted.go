@@ -0,0 +1,165 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"crypto/aes"
+	"crypto/cipher"
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"time"
+
+	"github.com/fidelity/kconnect/pkg/cache"
+	"github.com/fidelity/kconnect/pkg/defaults"
+)
+
+const (
+	// encryptionKeyName is the key of the secret that has the key used to encrypt cached values
+	encryptionKeyName = "kconnect/cache-encryption-key"
+	encryptionKeySize = 32
+)
+
+var ErrInvalidCiphertext = errors.New("encrypted value is too short")
+
+// NewEncryptedCache creates a cache that encrypts the values with AES-GCM before they're
+// kept in the cache directory, for values that are too sensitive to be kept there as they
+// are, e.g. SAML assertions. Only the encryption key, which is generated the first time,
+// is kept in the secret store. A ttl of zero or less disables the cache.
+func NewEncryptedCache(store Store, ttl time.Duration) cache.Cache {
+	return &encryptedCache{
+		store: store,
+		cache: cache.New(defaults.CacheDirectory(), ttl),
+	}
+}
+
+type encryptedCache struct {
+	store Store
+	cache cache.Cache
+}
+
+func (c *encryptedCache) Get(key string, out interface{}) (bool, error) {
+	var encrypted string
+	found, err := c.cache.Get(key, &encrypted)
+	if err != nil || !found {
+		return false, err
+	}
+
+	aead, err := c.aead(false)
+	if err != nil || aead == nil {
+		return false, err
+	}
+	data, err := decrypt(aead, encrypted)
+	if err != nil {
+		// A value encrypted with another key is treated as a miss and is replaced on the next set
+		return false, nil //nolint: nilerr
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return false, fmt.Errorf("unmarshalling cached value: %w", err)
+	}
+
+	return true, nil
+}
+
+func (c *encryptedCache) Set(key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return fmt.Errorf("marshalling value: %w", err)
+	}
+	aead, err := c.aead(true)
+	if err != nil {
+		return err
+	}
+	encrypted, err := encrypt(aead, data)
+	if err != nil {
+		return err
+	}
+
+	return c.cache.Set(key, encrypted)
+}
+
+func (c *encryptedCache) Delete(key string) error {
+	return c.cache.Delete(key)
+}
+
+// aead returns the cipher using the encryption key from the secret store. The key is
+// generated if there isn't one and create is true, otherwise nil is returned.
+func (c *encryptedCache) aead(create bool) (cipher.AEAD, error) {
+	encodedKey, found, err := c.store.Get(encryptionKeyName)
+	if err != nil {
+		return nil, fmt.Errorf("getting encryption key from %s secret store: %w", c.store.Type(), err)
+	}
+
+	var key []byte
+	if found {
+		key, err = base64.StdEncoding.DecodeString(encodedKey)
+	}
+	if !found || err != nil || len(key) != encryptionKeySize {
+		if !create {
+			return nil, nil
+		}
+		key = make([]byte, encryptionKeySize)
+		if _, err := io.ReadFull(rand.Reader, key); err != nil {
+			return nil, fmt.Errorf("generating encryption key: %w", err)
+		}
+		if err := c.store.Set(encryptionKeyName, base64.StdEncoding.EncodeToString(key)); err != nil {
+			return nil, fmt.Errorf("storing encryption key in %s secret store: %w", c.store.Type(), err)
+		}
+	}
+
+	block, err := aes.NewCipher(key)
+	if err != nil {
+		return nil, fmt.Errorf("creating cipher: %w", err)
+	}
+	aead, err := cipher.NewGCM(block)
+	if err != nil {
+		return nil, fmt.Errorf("creating gcm cipher: %w", err)
+	}
+
+	return aead, nil
+}
+
+// encrypt seals the data with a random nonce, the nonce is prepended to the ciphertext
+func encrypt(aead cipher.AEAD, data []byte) (string, error) {
+	nonce := make([]byte, aead.NonceSize())
+	if _, err := io.ReadFull(rand.Reader, nonce); err != nil {
+		return "", fmt.Errorf("generating nonce: %w", err)
+	}
+
+	return base64.StdEncoding.EncodeToString(aead.Seal(nonce, nonce, data, nil)), nil
+}
+
+func decrypt(aead cipher.AEAD, encrypted string) ([]byte, error) {
+	data, err := base64.StdEncoding.DecodeString(encrypted)
+	if err != nil {
+		return nil, fmt.Errorf("decoding encrypted value: %w", err)
+	}
+	if len(data) < aead.NonceSize() {
+		return nil, ErrInvalidCiphertext
+	}
+	nonce, ciphertext := data[:aead.NonceSize()], data[aead.NonceSize():]
+
+	plaintext, err := aead.Open(nil, nonce, ciphertext, nil)
+	if err != nil {
+		return nil, fmt.Errorf("decrypting value: %w", err)
+	}
+
+	return plaintext, nil
+}
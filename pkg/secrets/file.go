@@ -0,0 +1,101 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/fidelity/kconnect/pkg/defaults"
+)
+
+const secretsFolderName = "secrets"
+
+// NewFileStore creates a store that keeps each secret in a file in the kconnect app
+// directory that only the user can read. The values cached by kconnect, such as the
+// tokens it creates, stay in the cache directory.
+func NewFileStore() *FileStore {
+	return &FileStore{
+		directory:      filepath.Join(defaults.AppDirectory(), secretsFolderName),
+		cacheDirectory: defaults.CacheDirectory(),
+	}
+}
+
+// FileStore is a secret store that uses files
+type FileStore struct {
+	directory      string
+	cacheDirectory string
+}
+
+func (s *FileStore) Type() string {
+	return TypeFile
+}
+
+func (s *FileStore) Get(key string) (string, bool, error) {
+	path, err := s.path(key)
+	if err != nil {
+		return "", false, err
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("reading secret file %s: %w", path, err)
+	}
+
+	return string(data), true, nil
+}
+
+func (s *FileStore) Set(key, value string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(s.directory, 0700); err != nil {
+		return fmt.Errorf("creating secrets directory %s: %w", s.directory, err)
+	}
+	if err := os.WriteFile(path, []byte(value), 0600); err != nil {
+		return fmt.Errorf("writing secret file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (s *FileStore) Delete(key string) error {
+	path, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.Remove(path); err != nil && !os.IsNotExist(err) {
+		return fmt.Errorf("removing secret file %s: %w", path, err)
+	}
+
+	return nil
+}
+
+func (s *FileStore) path(key string) (string, error) {
+	if key == "" {
+		return "", ErrKeyRequired
+	}
+
+	return filepath.Join(s.directory, secretName(key)), nil
+}
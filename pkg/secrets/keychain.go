@@ -0,0 +1,177 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"runtime"
+	"strings"
+
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
+
+const (
+	keychainService = "kconnect"
+
+	// securityNotFoundCode is the exit code of the macOS security command when there's no item
+	securityNotFoundCode = 44
+)
+
+var (
+	ErrKeychainUnsupported = kerrors.WithCode(kerrors.CodePrereqMissing, errors.New("the keychain secret store isn't supported on this OS"))
+	ErrSecretTooLarge      = errors.New("secret is too large for the keychain")
+)
+
+// runCommand runs the command with the input and returns its output and exit code
+var runCommand = func(input string, name string, args ...string) (string, int, error) {
+	cmd := exec.Command(name, args...)
+	cmd.Stdin = strings.NewReader(input)
+	stdout := &bytes.Buffer{}
+	stderr := &bytes.Buffer{}
+	cmd.Stdout = stdout
+	cmd.Stderr = stderr
+
+	err := cmd.Run()
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) {
+		return stdout.String(), exitErr.ExitCode(), fmt.Errorf("running %s: %s: %w", name, strings.TrimSpace(stderr.String()), err)
+	}
+	if err != nil {
+		return "", -1, fmt.Errorf("running %s: %w", name, err)
+	}
+
+	return stdout.String(), 0, nil
+}
+
+// NewKeychainStore creates a store that keeps the secrets in the keychain of the OS. The
+// macOS keychain is used with the security command, the Secret Service (e.g. GNOME
+// Keyring or KWallet) on Linux is used with the secret-tool command from libsecret and
+// the Windows Credential Manager is used with the wincred API.
+func NewKeychainStore() *KeychainStore {
+	return &KeychainStore{
+		goos: runtime.GOOS,
+	}
+}
+
+// KeychainStore is a secret store that uses the keychain of the OS
+type KeychainStore struct {
+	goos string
+}
+
+func (s *KeychainStore) Type() string {
+	return TypeKeychain
+}
+
+func (s *KeychainStore) Get(key string) (string, bool, error) {
+	if key == "" {
+		return "", false, ErrKeyRequired
+	}
+
+	var output string
+	var code int
+	var err error
+	switch s.goos {
+	case "darwin":
+		output, code, err = runCommand("", "security", "find-generic-password", "-s", keychainService, "-a", key, "-w")
+		if code == securityNotFoundCode {
+			return "", false, nil
+		}
+		output = strings.TrimSuffix(output, "\n")
+	case "linux":
+		// secret-tool exits with 1 and no output when there's no secret
+		output, code, err = runCommand("", "secret-tool", "lookup", "service", keychainService, "key", key)
+		if code == 1 && output == "" {
+			return "", false, nil
+		}
+	case "windows":
+		return wincredGet(wincredTarget(key))
+	default:
+		return "", false, ErrKeychainUnsupported
+	}
+	if err != nil {
+		return "", false, fmt.Errorf("getting secret from keychain: %w", err)
+	}
+
+	return output, true, nil
+}
+
+func (s *KeychainStore) Set(key, value string) error {
+	if key == "" {
+		return ErrKeyRequired
+	}
+
+	var err error
+	switch s.goos {
+	case "darwin":
+		// The command is passed to security interactively so the value isn't in the arguments
+		command := fmt.Sprintf("add-generic-password -U -s %s -a %s -w %s\n", securityQuote(keychainService), securityQuote(key), securityQuote(value))
+		_, _, err = runCommand(command, "security", "-i")
+	case "linux":
+		_, _, err = runCommand(value, "secret-tool", "store", "--label", fmt.Sprintf("%s %s", keychainService, key), "service", keychainService, "key", key)
+	case "windows":
+		err = wincredSet(wincredTarget(key), value)
+	default:
+		return ErrKeychainUnsupported
+	}
+	if err != nil {
+		return fmt.Errorf("storing secret in keychain: %w", err)
+	}
+
+	return nil
+}
+
+func (s *KeychainStore) Delete(key string) error {
+	if key == "" {
+		return ErrKeyRequired
+	}
+
+	var code int
+	var err error
+	switch s.goos {
+	case "darwin":
+		_, code, err = runCommand("", "security", "delete-generic-password", "-s", keychainService, "-a", key)
+		if code == securityNotFoundCode {
+			return nil
+		}
+	case "linux":
+		_, _, err = runCommand("", "secret-tool", "clear", "service", keychainService, "key", key)
+	case "windows":
+		err = wincredDelete(wincredTarget(key))
+	default:
+		return ErrKeychainUnsupported
+	}
+	if err != nil {
+		return fmt.Errorf("deleting secret from keychain: %w", err)
+	}
+
+	return nil
+}
+
+// wincredTarget is the name of the credential of the key in the Windows Credential Manager
+func wincredTarget(key string) string {
+	return keychainService + ":" + key
+}
+
+// securityQuote quotes a value for the interactive mode of the security command
+func securityQuote(value string) string {
+	replacer := strings.NewReplacer(`\`, `\\`, `"`, `\"`)
+
+	return `"` + replacer.Replace(value) + `"`
+}
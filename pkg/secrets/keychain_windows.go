@@ -0,0 +1,129 @@
+//go:build windows
+// +build windows
+
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+	// credMaxBlobSize is the largest secret the Credential Manager keeps
+	credMaxBlobSize = 5 * 512
+)
+
+var (
+	advapi32        = windows.NewLazySystemDLL("advapi32.dll")
+	procCredReadW   = advapi32.NewProc("CredReadW")
+	procCredWriteW  = advapi32.NewProc("CredWriteW")
+	procCredDeleteW = advapi32.NewProc("CredDeleteW")
+	procCredFree    = advapi32.NewProc("CredFree")
+)
+
+// credential is the CREDENTIALW of wincred.h
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        windows.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+func wincredGet(target string) (string, bool, error) {
+	name, err := windows.UTF16PtrFromString(target)
+	if err != nil {
+		return "", false, fmt.Errorf("encoding credential name: %w", err)
+	}
+
+	var cred *credential
+	ret, _, err := procCredReadW.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred)))
+	if ret == 0 {
+		if errors.Is(err, windows.ERROR_NOT_FOUND) {
+			return "", false, nil
+		}
+		return "", false, fmt.Errorf("reading credential %s: %w", target, err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred))) //nolint: errcheck
+
+	if cred.CredentialBlobSize == 0 {
+		return "", true, nil
+	}
+
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), true, nil
+}
+
+func wincredSet(target, value string) error {
+	if len(value) > credMaxBlobSize {
+		return fmt.Errorf("credential %s is %d bytes: %w", target, len(value), ErrSecretTooLarge)
+	}
+	name, err := windows.UTF16PtrFromString(target)
+	if err != nil {
+		return fmt.Errorf("encoding credential name: %w", err)
+	}
+	user, err := windows.UTF16PtrFromString(keychainService)
+	if err != nil {
+		return fmt.Errorf("encoding credential user: %w", err)
+	}
+
+	blob := []byte(value)
+	cred := &credential{
+		Type:               credTypeGeneric,
+		TargetName:         name,
+		UserName:           user,
+		Persist:            credPersistLocalMachine,
+		CredentialBlobSize: uint32(len(blob)),
+	}
+	if len(blob) > 0 {
+		cred.CredentialBlob = &blob[0]
+	}
+
+	ret, _, err := procCredWriteW.Call(uintptr(unsafe.Pointer(cred)), 0)
+	if ret == 0 {
+		return fmt.Errorf("writing credential %s: %w", target, err)
+	}
+
+	return nil
+}
+
+func wincredDelete(target string) error {
+	name, err := windows.UTF16PtrFromString(target)
+	if err != nil {
+		return fmt.Errorf("encoding credential name: %w", err)
+	}
+
+	ret, _, err := procCredDeleteW.Call(uintptr(unsafe.Pointer(name)), credTypeGeneric, 0)
+	if ret == 0 && !errors.Is(err, windows.ERROR_NOT_FOUND) {
+		return fmt.Errorf("deleting credential %s: %w", target, err)
+	}
+
+	return nil
+}
@@ -0,0 +1,119 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sync"
+
+	kconnectv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+)
+
+const (
+	// TypeFile keeps the secrets in files that only the user can read, this is the default
+	TypeFile = "file"
+	// TypeKeychain keeps the secrets in the keychain of the OS
+	TypeKeychain = "keychain"
+	// TypeVault keeps the secrets in a HashiCorp Vault KV secrets engine
+	TypeVault = "vault"
+	// TypeAWSSecretsManager keeps the secrets in AWS Secrets Manager
+	TypeAWSSecretsManager = "aws-secrets-manager"
+)
+
+var (
+	ErrUnknownStoreType = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("unknown secret store type"))
+	ErrKeyRequired      = errors.New("secret key is required")
+
+	defaultStore Store
+	defaultLock  sync.Mutex
+)
+
+// Types returns the types of secret store
+func Types() []string {
+	return []string{TypeFile, TypeKeychain, TypeVault, TypeAWSSecretsManager}
+}
+
+// Store is where kconnect persists sensitive values, such as the tokens it creates,
+// the identities saved to resume a use command and the secrets referenced in the
+// configuration. The store is chosen in the app config so that organisations can
+// decide where these values are kept.
+type Store interface {
+	// Type is the type of the store, e.g. vault
+	Type() string
+	// Get returns the value of the secret with the key. It returns false if there
+	// is no secret with the key.
+	Get(key string) (string, bool, error)
+	// Set will store the value of the secret with the key
+	Set(key, value string) error
+	// Delete will remove the secret with the key, if there is one
+	Delete(key string) error
+}
+
+// New creates the secret store configured in the app config. The file store is used
+// if there is no configuration.
+func New(cfg *kconnectv1alpha.SecretStore) (Store, error) {
+	if cfg == nil {
+		return NewFileStore(), nil
+	}
+
+	switch cfg.Type {
+	case "", TypeFile:
+		return NewFileStore(), nil
+	case TypeKeychain:
+		return NewKeychainStore(), nil
+	case TypeVault:
+		return NewVaultStore(cfg.Vault)
+	case TypeAWSSecretsManager:
+		return NewAWSSecretsManagerStore(cfg.AWSSecretsManager)
+	default:
+		return nil, fmt.Errorf("creating secret store %s: %w", cfg.Type, ErrUnknownStoreType)
+	}
+}
+
+// SetDefault sets the store used by Default
+func SetDefault(store Store) {
+	defaultLock.Lock()
+	defer defaultLock.Unlock()
+
+	defaultStore = store
+}
+
+// Default returns the secret store chosen in the app config, or the file store if
+// one hasn't been set
+func Default() Store {
+	defaultLock.Lock()
+	defer defaultLock.Unlock()
+
+	if defaultStore == nil {
+		defaultStore = NewFileStore()
+	}
+
+	return defaultStore
+}
+
+// secretName returns the name a secret is kept under for the key. Keys can contain
+// characters that the backends don't allow, e.g. the URLs of Rancher endpoints, so
+// they're hashed.
+func secretName(key string) string {
+	sum := sha256.Sum256([]byte(key))
+
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,281 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager"
+	"github.com/aws/aws-sdk-go-v2/service/secretsmanager/types"
+	. "github.com/onsi/gomega"
+
+	kconnectv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/defaults"
+	"github.com/fidelity/kconnect/pkg/secrets"
+)
+
+func TestNewUnknownType(t *testing.T) {
+	g := NewWithT(t)
+
+	_, err := secrets.New(&kconnectv1alpha.SecretStore{Type: "lastpass"})
+	g.Expect(errors.Is(err, secrets.ErrUnknownStoreType)).To(BeTrue())
+
+	store, err := secrets.New(nil)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(store.Type()).To(Equal(secrets.TypeFile))
+}
+
+func TestVaultStore(t *testing.T) {
+	g := NewWithT(t)
+
+	server := newFakeVault()
+	defer server.Close()
+	os.Setenv("VAULT_TOKEN", "s.test") //nolint: errcheck
+	defer os.Unsetenv("VAULT_TOKEN")   //nolint: errcheck
+
+	store, err := secrets.New(&kconnectv1alpha.SecretStore{
+		Type:  secrets.TypeVault,
+		Vault: &kconnectv1alpha.VaultSecretStore{Address: server.URL, Namespace: "team-a", Mount: "kv"},
+	})
+	g.Expect(err).NotTo(HaveOccurred())
+
+	_, found, err := store.Get("rancher-password")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+
+	g.Expect(store.Set("rancher-password", "s3cr3t")).To(Succeed())
+	value, found, err := store.Get("rancher-password")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+	g.Expect(value).To(Equal("s3cr3t"))
+
+	g.Expect(store.Delete("rancher-password")).To(Succeed())
+	_, found, err = store.Get("rancher-password")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+
+	for _, path := range server.paths {
+		g.Expect(path).To(MatchRegexp(`^/v1/kv/(data|metadata)/kconnect/[0-9a-f]{64}$`))
+	}
+}
+
+func TestVaultStoreAddressRequired(t *testing.T) {
+	g := NewWithT(t)
+
+	os.Unsetenv("VAULT_ADDR") //nolint: errcheck
+
+	_, err := secrets.NewVaultStore(nil)
+	g.Expect(errors.Is(err, secrets.ErrVaultAddressRequired)).To(BeTrue())
+}
+
+func TestAWSSecretsManagerStore(t *testing.T) {
+	g := NewWithT(t)
+
+	client := &fakeSecretsManager{secrets: map[string]string{}}
+	store := secrets.NewAWSSecretsManagerStoreWithClient(client, &kconnectv1alpha.AWSSecretsManagerSecretStore{KMSKeyID: "alias/kconnect"})
+
+	_, found, err := store.Get("token")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+
+	g.Expect(store.Set("token", "first")).To(Succeed())
+	g.Expect(store.Set("token", "second")).To(Succeed())
+	value, found, err := store.Get("token")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+	g.Expect(value).To(Equal("second"))
+	g.Expect(client.kmsKeyID).To(Equal("alias/kconnect"))
+	for name := range client.secrets {
+		g.Expect(strings.HasPrefix(name, "kconnect/")).To(BeTrue())
+	}
+
+	g.Expect(store.Delete("token")).To(Succeed())
+	g.Expect(store.Delete("token")).To(Succeed())
+	g.Expect(client.secrets).To(BeEmpty())
+}
+
+func TestCacheWithStore(t *testing.T) {
+	g := NewWithT(t)
+
+	client := &fakeSecretsManager{secrets: map[string]string{}}
+	store := secrets.NewAWSSecretsManagerStoreWithClient(client, nil)
+
+	type token struct {
+		Value string `json:"value"`
+	}
+
+	c := secrets.NewCache(store, time.Hour)
+	g.Expect(c.Set("rancher/tokens/bob", &token{Value: "t0k3n"})).To(Succeed())
+
+	actual := &token{}
+	found, err := c.Get("rancher/tokens/bob", actual)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+	g.Expect(actual.Value).To(Equal("t0k3n"))
+
+	disabled := secrets.NewCache(store, 0)
+	found, err = disabled.Get("rancher/tokens/bob", actual)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+
+	g.Expect(c.Delete("rancher/tokens/bob")).To(Succeed())
+	found, err = c.Get("rancher/tokens/bob", actual)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+}
+
+func TestEncryptedCache(t *testing.T) {
+	g := NewWithT(t)
+	t.Setenv("HOME", t.TempDir())
+
+	client := &fakeSecretsManager{secrets: map[string]string{}}
+	store := secrets.NewAWSSecretsManagerStoreWithClient(client, nil)
+
+	c := secrets.NewEncryptedCache(store, time.Hour)
+	var actual string
+	found, err := c.Get("saml/assertion", &actual)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+
+	g.Expect(c.Set("saml/assertion", "PHNhbWxwOlJlc3BvbnNlPg==")).To(Succeed())
+	// Only the key is in the secret store, the encrypted value is in the cache directory
+	g.Expect(client.secrets).To(HaveLen(1))
+	cached, err := os.ReadDir(defaults.CacheDirectory())
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(cached).To(HaveLen(1))
+	data, err := os.ReadFile(filepath.Join(defaults.CacheDirectory(), cached[0].Name()))
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(string(data)).NotTo(ContainSubstring("PHNhbWxwOlJlc3BvbnNlPg=="))
+
+	found, err = c.Get("saml/assertion", &actual)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeTrue())
+	g.Expect(actual).To(Equal("PHNhbWxwOlJlc3BvbnNlPg=="))
+
+	// A value encrypted with a key that has been replaced can't be read
+	g.Expect(store.Delete("kconnect/cache-encryption-key")).To(Succeed())
+	found, err = c.Get("saml/assertion", &actual)
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(found).To(BeFalse())
+}
+
+type fakeVault struct {
+	*httptest.Server
+
+	lock    sync.Mutex
+	secrets map[string]string
+	paths   []string
+}
+
+func newFakeVault() *fakeVault {
+	v := &fakeVault{secrets: map[string]string{}}
+	v.Server = httptest.NewServer(http.HandlerFunc(v.handle))
+
+	return v
+}
+
+func (v *fakeVault) handle(w http.ResponseWriter, r *http.Request) {
+	v.lock.Lock()
+	defer v.lock.Unlock()
+
+	if r.Header.Get("X-Vault-Token") != "s.test" || r.Header.Get("X-Vault-Namespace") != "team-a" {
+		w.WriteHeader(http.StatusForbidden)
+		return
+	}
+	v.paths = append(v.paths, r.URL.Path)
+	name := r.URL.Path[strings.LastIndex(r.URL.Path, "/")+1:]
+
+	switch r.Method {
+	case http.MethodGet:
+		value, ok := v.secrets[name]
+		if !ok {
+			w.WriteHeader(http.StatusNotFound)
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{ //nolint: errcheck
+			"data": map[string]interface{}{"data": map[string]string{"value": value}},
+		})
+	case http.MethodPost:
+		body := struct {
+			Data map[string]string `json:"data"`
+		}{}
+		if err := json.NewDecoder(r.Body).Decode(&body); err != nil {
+			w.WriteHeader(http.StatusBadRequest)
+			return
+		}
+		v.secrets[name] = body.Data["value"]
+		w.WriteHeader(http.StatusOK)
+	case http.MethodDelete:
+		delete(v.secrets, name)
+		w.WriteHeader(http.StatusNoContent)
+	}
+}
+
+type fakeSecretsManager struct {
+	secrets  map[string]string
+	kmsKeyID string
+}
+
+func (f *fakeSecretsManager) GetSecretValue(ctx context.Context, input *secretsmanager.GetSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.GetSecretValueOutput, error) {
+	value, ok := f.secrets[aws.ToString(input.SecretId)]
+	if !ok {
+		return nil, notFound()
+	}
+
+	return &secretsmanager.GetSecretValueOutput{SecretString: aws.String(value)}, nil
+}
+
+func (f *fakeSecretsManager) PutSecretValue(ctx context.Context, input *secretsmanager.PutSecretValueInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.PutSecretValueOutput, error) {
+	if _, ok := f.secrets[aws.ToString(input.SecretId)]; !ok {
+		return nil, notFound()
+	}
+	f.secrets[aws.ToString(input.SecretId)] = aws.ToString(input.SecretString)
+
+	return &secretsmanager.PutSecretValueOutput{}, nil
+}
+
+func (f *fakeSecretsManager) CreateSecret(ctx context.Context, input *secretsmanager.CreateSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.CreateSecretOutput, error) {
+	f.secrets[aws.ToString(input.Name)] = aws.ToString(input.SecretString)
+	f.kmsKeyID = aws.ToString(input.KmsKeyId)
+
+	return &secretsmanager.CreateSecretOutput{}, nil
+}
+
+func (f *fakeSecretsManager) DeleteSecret(ctx context.Context, input *secretsmanager.DeleteSecretInput, optFns ...func(*secretsmanager.Options)) (*secretsmanager.DeleteSecretOutput, error) {
+	if _, ok := f.secrets[aws.ToString(input.SecretId)]; !ok {
+		return nil, notFound()
+	}
+	delete(f.secrets, aws.ToString(input.SecretId))
+
+	return &secretsmanager.DeleteSecretOutput{}, nil
+}
+
+func notFound() error {
+	return fmt.Errorf("operation error Secrets Manager: %w", &types.ResourceNotFoundException{Message: aws.String("secret not found")})
+}
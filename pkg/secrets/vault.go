@@ -0,0 +1,219 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package secrets
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	kconnectv1alpha "github.com/fidelity/kconnect/api/v1alpha1"
+	"github.com/fidelity/kconnect/pkg/defaults"
+	kerrors "github.com/fidelity/kconnect/pkg/errors"
+	khttp "github.com/fidelity/kconnect/pkg/http"
+)
+
+const (
+	vaultAddressEnv   = "VAULT_ADDR"
+	vaultTokenEnv     = "VAULT_TOKEN"
+	vaultNamespaceEnv = "VAULT_NAMESPACE"
+	vaultTokenFile    = ".vault-token"
+
+	vaultTokenHeader     = "X-Vault-Token"
+	vaultNamespaceHeader = "X-Vault-Namespace"
+
+	defaultVaultMount = "secret"
+	defaultVaultPath  = "kconnect"
+
+	// vaultValueField is the field of the KV secret that holds the value
+	vaultValueField = "value"
+	vaultTimeout    = 30 * time.Second
+)
+
+var (
+	ErrVaultAddressRequired = kerrors.WithCode(kerrors.CodeConfigInvalid, errors.New("the vault address is required, set it in the secret store config or VAULT_ADDR"))
+	ErrVaultTokenRequired   = kerrors.WithCode(kerrors.CodeAuthFailed, errors.New("a vault token is required, log in with vault login or set VAULT_TOKEN"))
+	ErrVaultRequestFailed   = errors.New("vault request failed")
+)
+
+type vaultSecret struct {
+	Data struct {
+		Data map[string]string `json:"data"`
+	} `json:"data"`
+}
+
+// NewVaultStore creates a store that keeps the secrets in a HashiCorp Vault KV version 2
+// secrets engine
+func NewVaultStore(cfg *kconnectv1alpha.VaultSecretStore) (*VaultStore, error) {
+	if cfg == nil {
+		cfg = &kconnectv1alpha.VaultSecretStore{}
+	}
+
+	store := &VaultStore{
+		address:    strings.TrimSuffix(firstNonEmpty(cfg.Address, os.Getenv(vaultAddressEnv)), "/"),
+		namespace:  firstNonEmpty(cfg.Namespace, os.Getenv(vaultNamespaceEnv)),
+		mount:      strings.Trim(firstNonEmpty(cfg.Mount, defaultVaultMount), "/"),
+		path:       strings.Trim(firstNonEmpty(cfg.Path, defaultVaultPath), "/"),
+		httpClient: khttp.NewHTTPClient(),
+	}
+	if store.address == "" {
+		return nil, ErrVaultAddressRequired
+	}
+
+	return store, nil
+}
+
+// VaultStore is a secret store that uses HashiCorp Vault
+type VaultStore struct {
+	address    string
+	namespace  string
+	mount      string
+	path       string
+	httpClient khttp.Client
+}
+
+func (s *VaultStore) Type() string {
+	return TypeVault
+}
+
+func (s *VaultStore) Get(key string) (string, bool, error) {
+	resp, err := s.do(http.MethodGet, "data", key, nil)
+	if err != nil {
+		return "", false, err
+	}
+	if resp.ResponseCode() == http.StatusNotFound {
+		return "", false, nil
+	}
+	if resp.ResponseCode() != http.StatusOK {
+		return "", false, fmt.Errorf("getting secret, vault responded with %d: %w", resp.ResponseCode(), ErrVaultRequestFailed)
+	}
+
+	secret := &vaultSecret{}
+	if err := json.Unmarshal([]byte(resp.Body()), secret); err != nil {
+		return "", false, fmt.Errorf("unmarshalling vault secret: %w", err)
+	}
+	value, found := secret.Data.Data[vaultValueField]
+
+	return value, found, nil
+}
+
+func (s *VaultStore) Set(key, value string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"data": map[string]string{vaultValueField: value},
+	})
+	if err != nil {
+		return fmt.Errorf("marshalling vault secret: %w", err)
+	}
+
+	resp, err := s.do(http.MethodPost, "data", key, body)
+	if err != nil {
+		return err
+	}
+	if resp.ResponseCode() != http.StatusOK && resp.ResponseCode() != http.StatusNoContent {
+		return fmt.Errorf("storing secret, vault responded with %d: %w", resp.ResponseCode(), ErrVaultRequestFailed)
+	}
+
+	return nil
+}
+
+// Delete removes all the versions of the secret
+func (s *VaultStore) Delete(key string) error {
+	resp, err := s.do(http.MethodDelete, "metadata", key, nil)
+	if err != nil {
+		return err
+	}
+	if resp.ResponseCode() != http.StatusNoContent && resp.ResponseCode() != http.StatusOK && resp.ResponseCode() != http.StatusNotFound {
+		return fmt.Errorf("deleting secret, vault responded with %d: %w", resp.ResponseCode(), ErrVaultRequestFailed)
+	}
+
+	return nil
+}
+
+func (s *VaultStore) do(method, kind, key string, body []byte) (khttp.ClientResponse, error) {
+	if key == "" {
+		return nil, ErrKeyRequired
+	}
+	token, err := vaultToken()
+	if err != nil {
+		return nil, err
+	}
+
+	headers := defaults.Headers(defaults.WithContentTypeJSON())
+	headers[vaultTokenHeader] = token
+	if s.namespace != "" {
+		headers[vaultNamespaceHeader] = s.namespace
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), vaultTimeout)
+	defer cancel()
+
+	req := &khttp.ClientRequest{
+		URL:     fmt.Sprintf("%s/v1/%s/%s/%s/%s", s.address, s.mount, kind, s.path, secretName(key)),
+		Method:  method,
+		Headers: headers,
+		Context: ctx,
+	}
+	if body != nil {
+		bodyString := string(body)
+		req.Body = &bodyString
+	}
+
+	resp, err := s.httpClient.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("sending vault request: %w", err)
+	}
+
+	return resp, nil
+}
+
+// vaultToken returns the token from VAULT_TOKEN or the file written by vault login
+func vaultToken() (string, error) {
+	if token := os.Getenv(vaultTokenEnv); token != "" {
+		return token, nil
+	}
+
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return "", ErrVaultTokenRequired
+	}
+	data, err := os.ReadFile(filepath.Join(home, vaultTokenFile))
+	if err != nil {
+		return "", ErrVaultTokenRequired
+	}
+	token := strings.TrimSpace(string(data))
+	if token == "" {
+		return "", ErrVaultTokenRequired
+	}
+
+	return token, nil
+}
+
+func firstNonEmpty(values ...string) string {
+	for _, value := range values {
+		if value != "" {
+			return value
+		}
+	}
+
+	return ""
+}
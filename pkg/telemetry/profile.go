@@ -0,0 +1,157 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"sort"
+	"strings"
+	"sync"
+	"text/tabwriter"
+	"time"
+
+	"go.opentelemetry.io/otel/codes"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	"go.opentelemetry.io/otel/trace"
+)
+
+// SpanData is a span that has ended
+type SpanData struct {
+	Name       string
+	TraceID    string
+	SpanID     string
+	ParentID   string
+	Start      time.Time
+	End        time.Time
+	Attributes map[string]string
+	Err        error
+}
+
+// Recorder is an exporter that keeps the spans in memory, e.g. to print a
+// timing summary of the command
+type Recorder struct {
+	mu    sync.Mutex
+	spans []*SpanData
+}
+
+// NewRecorder creates a recorder with no spans
+func NewRecorder() *Recorder {
+	return &Recorder{}
+}
+
+// ExportSpans will keep the spans of the connect flow. The spans of the http requests
+// to the providers are left out so the summary only has the phases of the flow.
+func (r *Recorder) ExportSpans(ctx context.Context, spans []sdktrace.ReadOnlySpan) error {
+	data := make([]*SpanData, 0, len(spans))
+	for _, span := range spans {
+		if span.SpanKind() == trace.SpanKindClient {
+			continue
+		}
+		data = append(data, spanData(span))
+	}
+
+	return r.Export(ctx, data)
+}
+
+// Export will keep the spans
+func (r *Recorder) Export(ctx context.Context, spans []*SpanData) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.spans = append(r.spans, spans...)
+
+	return nil
+}
+
+// Shutdown does nothing, the spans are kept
+func (r *Recorder) Shutdown(ctx context.Context) error {
+	return nil
+}
+
+// Spans returns the recorded spans
+func (r *Recorder) Spans() []*SpanData {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	return append([]*SpanData{}, r.spans...)
+}
+
+func spanData(span sdktrace.ReadOnlySpan) *SpanData {
+	data := &SpanData{
+		Name:       span.Name(),
+		TraceID:    span.SpanContext().TraceID().String(),
+		SpanID:     span.SpanContext().SpanID().String(),
+		Start:      span.StartTime(),
+		End:        span.EndTime(),
+		Attributes: make(map[string]string),
+	}
+	if span.Parent().IsValid() {
+		data.ParentID = span.Parent().SpanID().String()
+	}
+	for _, kv := range span.Attributes() {
+		data.Attributes[string(kv.Key)] = kv.Value.Emit()
+	}
+	if span.Status().Code == codes.Error {
+		data.Err = errors.New(span.Status().Description)
+	}
+
+	return data
+}
+
+// WriteSummary writes the duration of each span in the order they started. The
+// child spans are indented under their parent and the total is written last.
+func WriteSummary(w io.Writer, spans []*SpanData, total time.Duration) error {
+	children := make(map[string][]*SpanData)
+	ids := make(map[string]bool)
+	for _, span := range spans {
+		ids[span.SpanID] = true
+	}
+	roots := []*SpanData{}
+	for _, span := range spans {
+		if span.ParentID == "" || !ids[span.ParentID] {
+			roots = append(roots, span)
+			continue
+		}
+		children[span.ParentID] = append(children[span.ParentID], span)
+	}
+
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(tw, "PHASE\tDURATION")
+
+	var writeSpans func(spans []*SpanData, depth int)
+	writeSpans = func(spans []*SpanData, depth int) {
+		sort.SliceStable(spans, func(i, j int) bool { return spans[i].Start.Before(spans[j].Start) })
+		for _, span := range spans {
+			duration := formatDuration(span.End.Sub(span.Start))
+			if span.Err != nil {
+				duration += " (failed)"
+			}
+			fmt.Fprintf(tw, "%s%s\t%s\n", strings.Repeat("  ", depth), span.Name, duration)
+			writeSpans(children[span.SpanID], depth+1)
+		}
+	}
+	writeSpans(roots, 0)
+	fmt.Fprintf(tw, "total\t%s\n", formatDuration(total))
+
+	return tw.Flush()
+}
+
+func formatDuration(d time.Duration) string {
+	return d.Round(time.Millisecond).String()
+}
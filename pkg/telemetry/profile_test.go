@@ -0,0 +1,52 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry_test
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/telemetry"
+)
+
+func TestWriteSummary(t *testing.T) {
+	g := NewWithT(t)
+
+	start := time.Date(2021, 6, 1, 10, 0, 0, 0, time.UTC)
+	spans := []*telemetry.SpanData{
+		{Name: "kubeconfig.write", SpanID: "3", ParentID: "1", Start: start.Add(2 * time.Second), End: start.Add(2100 * time.Millisecond)},
+		{Name: "identity.authenticate", SpanID: "2", ParentID: "1", Start: start, End: start.Add(1500 * time.Millisecond), Err: errors.New("login failed")},
+		{Name: "kconnect.use", SpanID: "1", Start: start, End: start.Add(2200 * time.Millisecond)},
+	}
+
+	recorder := telemetry.NewRecorder()
+	g.Expect(recorder.Export(context.Background(), spans)).To(Succeed())
+
+	out := &bytes.Buffer{}
+	g.Expect(telemetry.WriteSummary(out, recorder.Spans(), 2500*time.Millisecond)).To(Succeed())
+	g.Expect(out.String()).To(Equal(`PHASE                    DURATION
+kconnect.use             2.2s
+  identity.authenticate  1.5s (failed)
+  kubeconfig.write       100ms
+total                    2.5s
+`))
+}
@@ -0,0 +1,145 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"context"
+	"sync"
+
+	"go.opentelemetry.io/otel"
+	"go.opentelemetry.io/otel/attribute"
+	"go.opentelemetry.io/otel/codes"
+	"go.opentelemetry.io/otel/propagation"
+	"go.opentelemetry.io/otel/sdk/resource"
+	sdktrace "go.opentelemetry.io/otel/sdk/trace"
+	semconv "go.opentelemetry.io/otel/semconv/v1.17.0"
+	"go.opentelemetry.io/otel/trace"
+	"go.uber.org/zap"
+
+	"github.com/fidelity/kconnect/internal/version"
+)
+
+const ServiceName = "kconnect"
+
+var (
+	providerMu sync.Mutex
+	provider   *sdktrace.TracerProvider
+)
+
+// Exporter sends the ended spans to a tracing backend
+type Exporter = sdktrace.SpanExporter
+
+// Enable will record spans and send them to the exporter when Flush is called. Each
+// exporter that's enabled is sent the spans, e.g. the OTLP exporter and the profile.
+// The trace context is propagated to the providers by the http transports.
+func Enable(exporter Exporter) {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+	if provider == nil {
+		v := version.Get().Version
+		provider = sdktrace.NewTracerProvider(
+			sdktrace.WithResource(resource.NewWithAttributes(
+				semconv.SchemaURL,
+				semconv.ServiceNameKey.String(ServiceName),
+				semconv.ServiceVersionKey.String(v),
+			)),
+		)
+		otel.SetTracerProvider(provider)
+		otel.SetTextMapPropagator(propagation.TraceContext{})
+		otel.SetErrorHandler(otel.ErrorHandlerFunc(func(err error) {
+			zap.S().Debugw("tracing error", "error", err.Error())
+		}))
+	}
+
+	provider.RegisterSpanProcessor(sdktrace.NewBatchSpanProcessor(exporter))
+}
+
+// Enabled returns true if spans are being recorded
+func Enabled() bool {
+	return currentProvider() != nil
+}
+
+// Flush will export the spans that have ended
+func Flush(ctx context.Context) error {
+	p := currentProvider()
+	if p == nil {
+		return nil
+	}
+
+	return p.ForceFlush(ctx)
+}
+
+func currentProvider() *sdktrace.TracerProvider {
+	providerMu.Lock()
+	defer providerMu.Unlock()
+
+	return provider
+}
+
+// Span is a timed operation of a connect flow. A nil span is valid and does
+// nothing, this is what Start returns when tracing isn't enabled.
+type Span struct {
+	span trace.Span
+}
+
+// Start will start a span that is a child of the span in the context, if there is one.
+// The attributes are key value pairs.
+func Start(ctx context.Context, name string, attributes ...string) (context.Context, *Span) {
+	p := currentProvider()
+	if p == nil {
+		return ctx, nil
+	}
+
+	tracer := p.Tracer(ServiceName, trace.WithInstrumentationVersion(version.Get().Version))
+	ctx, span := tracer.Start(ctx, name, trace.WithAttributes(keyValues(attributes)...))
+
+	return ctx, &Span{span: span}
+}
+
+// SetAttributes will add the key value pairs to the span
+func (s *Span) SetAttributes(attributes ...string) {
+	if s == nil {
+		return
+	}
+	s.span.SetAttributes(keyValues(attributes)...)
+}
+
+// RecordError will mark the span as failed with the error
+func (s *Span) RecordError(err error) {
+	if s == nil || err == nil {
+		return
+	}
+	s.span.RecordError(err)
+	s.span.SetStatus(codes.Error, err.Error())
+}
+
+// Finish will end the span so that it's exported on the next flush
+func (s *Span) Finish() {
+	if s == nil {
+		return
+	}
+	s.span.End()
+}
+
+func keyValues(attributes []string) []attribute.KeyValue {
+	kvs := make([]attribute.KeyValue, 0, len(attributes)/2)
+	for i := 0; i+1 < len(attributes); i += 2 {
+		kvs = append(kvs, attribute.String(attributes[i], attributes[i+1]))
+	}
+
+	return kvs
+}
@@ -0,0 +1,96 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	. "github.com/onsi/gomega"
+	coltracepb "go.opentelemetry.io/proto/otlp/collector/trace/v1"
+	tracepb "go.opentelemetry.io/proto/otlp/trace/v1"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/fidelity/kconnect/pkg/telemetry"
+)
+
+func TestExportSpans(t *testing.T) {
+	g := NewWithT(t)
+
+	received := &coltracepb.ExportTraceServiceRequest{}
+	var authHeader, path string
+	collector := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path = r.URL.Path
+		authHeader = r.Header.Get("Authorization")
+		body, _ := ioutil.ReadAll(r.Body)
+		proto.Unmarshal(body, received) //nolint: errcheck
+		w.Header().Set("Content-Type", "application/x-protobuf")
+		w.WriteHeader(http.StatusOK)
+	}))
+	defer collector.Close()
+
+	g.Expect(telemetry.Enabled()).To(BeFalse())
+	_, noop := telemetry.Start(context.Background(), "not-recorded")
+	g.Expect(noop).To(BeNil())
+	noop.SetAttributes("key", "value")
+	noop.Finish()
+
+	exporter, err := telemetry.NewOTLPExporter(context.Background(), collector.URL, map[string]string{"Authorization": "Bearer abc"})
+	g.Expect(err).NotTo(HaveOccurred())
+	telemetry.Enable(exporter)
+	ctx, root := telemetry.Start(context.Background(), "kconnect.use", "discovery-provider", "eks")
+	_, child := telemetry.Start(ctx, "identity.authenticate")
+	child.RecordError(errors.New("login failed"))
+	child.Finish()
+	root.Finish()
+
+	g.Expect(telemetry.Flush(context.Background())).To(Succeed())
+	g.Expect(path).To(Equal("/v1/traces"))
+	g.Expect(authHeader).To(Equal("Bearer abc"))
+
+	spans := received.ResourceSpans[0].ScopeSpans[0].Spans
+	g.Expect(spans).To(HaveLen(2))
+	g.Expect(spans[0].Name).To(Equal("identity.authenticate"))
+	g.Expect(spans[0].TraceId).To(Equal(spans[1].TraceId))
+	g.Expect(spans[0].ParentSpanId).To(Equal(spans[1].SpanId))
+	g.Expect(spans[0].Status.Code).To(Equal(tracepb.Status_STATUS_CODE_ERROR))
+	g.Expect(spans[0].Status.Message).To(Equal("login failed"))
+	g.Expect(spans[1].ParentSpanId).To(BeEmpty())
+	g.Expect(spans[1].Status.GetCode()).To(Equal(tracepb.Status_STATUS_CODE_UNSET))
+	g.Expect(spans[1].Attributes[0].Key).To(Equal("discovery-provider"))
+	g.Expect(spans[1].Attributes[0].Value.GetStringValue()).To(Equal("eks"))
+
+	// The spans are only exported once
+	received = &coltracepb.ExportTraceServiceRequest{}
+	g.Expect(telemetry.Flush(context.Background())).To(Succeed())
+	g.Expect(received.ResourceSpans).To(BeEmpty())
+}
+
+func TestParseHeaders(t *testing.T) {
+	g := NewWithT(t)
+
+	headers, err := telemetry.ParseHeaders("Authorization=Basic abc==, X-Tenant = team1")
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(headers).To(Equal(map[string]string{"Authorization": "Basic abc==", "X-Tenant": "team1"}))
+
+	_, err = telemetry.ParseHeaders("missing-value")
+	g.Expect(err).To(MatchError(telemetry.ErrInvalidHeader))
+}
@@ -0,0 +1,94 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"runtime"
+	"time"
+
+	"github.com/fidelity/kconnect/internal/version"
+)
+
+const (
+	usageTimeout = 2 * time.Second
+)
+
+var ErrUsageRejected = errors.New("usage metrics rejected by the endpoint")
+
+// UsageEvent is the anonymized usage of a command. It mustn't contain anything that
+// identifies the user, e.g. names, accounts or clusters.
+type UsageEvent struct {
+	Command           string `json:"command"`
+	DiscoveryProvider string `json:"discoveryProvider,omitempty"`
+	IdentityProvider  string `json:"identityProvider,omitempty"`
+	DurationMS        int64  `json:"durationMs"`
+	Success           bool   `json:"success"`
+	ErrorClass        string `json:"errorClass,omitempty"`
+	Version           string `json:"version"`
+	OS                string `json:"os"`
+	Arch              string `json:"arch"`
+}
+
+// NewUsageEvent creates the usage event for a command that ran for the duration. The
+// error class is a coarse category of the error, e.g. network, and empty on success.
+func NewUsageEvent(command string, duration time.Duration, errorClass string) *UsageEvent {
+	return &UsageEvent{
+		Command:    command,
+		DurationMS: duration.Milliseconds(),
+		Success:    errorClass == "",
+		ErrorClass: errorClass,
+		Version:    version.Get().Version,
+		OS:         runtime.GOOS,
+		Arch:       runtime.GOARCH,
+	}
+}
+
+// ReportUsage will post the usage event as JSON to the endpoint. The request is
+// limited to a short time so the command isn't held up by a slow endpoint.
+func ReportUsage(ctx context.Context, endpoint string, event *UsageEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return fmt.Errorf("marshalling usage event: %w", err)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, usageTimeout)
+	defer cancel()
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, endpoint, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("creating usage request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("reporting usage: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return fmt.Errorf("reporting usage %d: %w", resp.StatusCode, ErrUsageRejected)
+	}
+
+	return nil
+}
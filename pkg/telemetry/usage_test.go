@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package telemetry_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	. "github.com/onsi/gomega"
+
+	"github.com/fidelity/kconnect/pkg/telemetry"
+)
+
+func TestReportUsage(t *testing.T) {
+	g := NewWithT(t)
+
+	var received map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		g.Expect(r.Method).To(Equal(http.MethodPost))
+		g.Expect(r.Header.Get("Content-Type")).To(Equal("application/json"))
+		g.Expect(json.NewDecoder(r.Body).Decode(&received)).To(Succeed())
+	}))
+	defer server.Close()
+
+	event := telemetry.NewUsageEvent("use", 1500*time.Millisecond, "network")
+	event.DiscoveryProvider = "eks"
+	event.IdentityProvider = "saml"
+
+	g.Expect(telemetry.ReportUsage(context.Background(), server.URL, event)).To(Succeed())
+	g.Expect(received).To(HaveKeyWithValue("command", "use"))
+	g.Expect(received).To(HaveKeyWithValue("discoveryProvider", "eks"))
+	g.Expect(received).To(HaveKeyWithValue("identityProvider", "saml"))
+	g.Expect(received).To(HaveKeyWithValue("durationMs", BeNumerically("==", 1500)))
+	g.Expect(received).To(HaveKeyWithValue("success", false))
+	g.Expect(received).To(HaveKeyWithValue("errorClass", "network"))
+}
+
+func TestReportUsageRejected(t *testing.T) {
+	g := NewWithT(t)
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	err := telemetry.ReportUsage(context.Background(), server.URL, telemetry.NewUsageEvent("ls", time.Second, ""))
+	g.Expect(errors.Is(err, telemetry.ErrUsageRejected)).To(BeTrue())
+}
@@ -0,0 +1,221 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"hash/fnv"
+	"net"
+	"os"
+	"os/exec"
+	"strconv"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+)
+
+const (
+	// portRangeStart and portRangeSize are the range of the local ports that are derived
+	// from the jump host, so that reconnecting through the same jump host reuses the tunnel
+	portRangeStart = 20000
+	portRangeSize  = 10000
+
+	listenTimeout  = 10 * time.Second
+	listenInterval = 200 * time.Millisecond
+)
+
+var (
+	ErrNoJumpHost        = errors.New("no jump host supplied")
+	ErrInvalidJumpHost   = errors.New("invalid jump host, expected [user@]host[:port]")
+	ErrSSHNotFound       = errors.New("ssh not found in the path, it is required to open a tunnel")
+	ErrTunnelNotReady    = errors.New("tunnel didn't start listening")
+	ErrInvalidTunnelPort = errors.New("invalid tunnel port")
+)
+
+// runSSH runs the ssh command, it's a variable so that tests don't need ssh
+var runSSH = func(ctx context.Context, path string, args []string) error {
+	cmd := exec.CommandContext(ctx, path, args...) //nolint: gosec
+	cmd.Stdin = os.Stdin
+	cmd.Stdout = os.Stderr
+	cmd.Stderr = os.Stderr
+
+	return cmd.Run()
+}
+
+// Config is the configuration of a SSH tunnel to a jump host
+type Config struct {
+	// JumpHost is the host to tunnel through in the form [user@]host[:port]
+	JumpHost string
+	// IdentityFile is the private key to authenticate to the jump host with, the
+	// ssh config and agent are used if it's empty
+	IdentityFile string
+	// LocalPort is the port the tunnel listens on, it's derived from the jump host if 0
+	LocalPort int
+	// Interactive is true if ssh can ask for a passphrase or to accept a host key
+	Interactive bool
+}
+
+// Tunnel is a SSH tunnel that forwards connections through the jump host
+type Tunnel struct {
+	// LocalPort is the port on the loopback interface of the SOCKS proxy of the tunnel
+	LocalPort int
+	// Reused is true if the tunnel was already open
+	Reused bool
+}
+
+// ProxyURL returns the url of the SOCKS proxy of the tunnel, this can be used
+// as the proxy-url of a cluster in a kubeconfig
+func (t *Tunnel) ProxyURL() string {
+	return fmt.Sprintf("socks5://%s", localAddress(t.LocalPort))
+}
+
+// Open will open a SSH tunnel to the jump host with a dynamic port forward. The ssh
+// process runs in the background so the tunnel stays open after kconnect exits. If
+// the tunnel is already open it's reused.
+func Open(ctx context.Context, cfg *Config) (*Tunnel, error) {
+	if cfg.JumpHost == "" {
+		return nil, ErrNoJumpHost
+	}
+	user, host, sshPort, err := parseJumpHost(cfg.JumpHost)
+	if err != nil {
+		return nil, err
+	}
+
+	localPort := cfg.LocalPort
+	if localPort == 0 {
+		localPort = DerivePort(cfg.JumpHost)
+	}
+	if localPort < 0 || localPort > 65535 {
+		return nil, fmt.Errorf("port %d: %w", localPort, ErrInvalidTunnelPort)
+	}
+
+	if isListening(localPort) {
+		zap.S().Debugw("using existing ssh tunnel", "jump-host", cfg.JumpHost, "port", localPort)
+		return &Tunnel{LocalPort: localPort, Reused: true}, nil
+	}
+
+	path, err := exec.LookPath("ssh")
+	if err != nil {
+		return nil, ErrSSHNotFound
+	}
+
+	zap.S().Infow("opening ssh tunnel", "jump-host", cfg.JumpHost, "port", localPort)
+	args := sshArgs(cfg, user, host, sshPort, localPort)
+	if err := runSSH(ctx, path, args); err != nil {
+		return nil, fmt.Errorf("running ssh to %s: %w", cfg.JumpHost, err)
+	}
+
+	if err := waitForListening(ctx, localPort); err != nil {
+		return nil, err
+	}
+
+	return &Tunnel{LocalPort: localPort}, nil
+}
+
+// DerivePort returns the local port for a tunnel through the jump host
+func DerivePort(jumpHost string) int {
+	h := fnv.New32a()
+	h.Write([]byte(jumpHost)) //nolint: errcheck
+
+	return portRangeStart + int(h.Sum32()%portRangeSize)
+}
+
+func sshArgs(cfg *Config, user, host string, sshPort, localPort int) []string {
+	args := []string{
+		"-f", "-N",
+		"-D", localAddress(localPort),
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=30",
+	}
+	if !cfg.Interactive {
+		args = append(args, "-o", "BatchMode=yes")
+	}
+	if cfg.IdentityFile != "" {
+		args = append(args, "-i", cfg.IdentityFile)
+	}
+	if sshPort != 0 {
+		args = append(args, "-p", strconv.Itoa(sshPort))
+	}
+	if user != "" {
+		args = append(args, "-l", user)
+	}
+
+	return append(args, host)
+}
+
+// parseJumpHost splits a jump host in the form [user@]host[:port]
+func parseJumpHost(jumpHost string) (string, string, int, error) {
+	user := ""
+	hostPort := jumpHost
+	if i := strings.LastIndex(jumpHost, "@"); i >= 0 {
+		user = jumpHost[:i]
+		hostPort = jumpHost[i+1:]
+	}
+
+	host, portStr, err := net.SplitHostPort(hostPort)
+	if err != nil {
+		// No port, the ssh config or the default port is used
+		host = strings.Trim(hostPort, "[]")
+		portStr = ""
+	}
+	if host == "" || strings.HasPrefix(host, "-") || strings.ContainsAny(host, " /") {
+		return "", "", 0, fmt.Errorf("jump host %s: %w", jumpHost, ErrInvalidJumpHost)
+	}
+
+	port := 0
+	if portStr != "" {
+		port, err = strconv.Atoi(portStr)
+		if err != nil || port < 1 || port > 65535 {
+			return "", "", 0, fmt.Errorf("jump host %s: %w", jumpHost, ErrInvalidJumpHost)
+		}
+	}
+
+	return user, host, port, nil
+}
+
+func localAddress(port int) string {
+	return net.JoinHostPort("127.0.0.1", strconv.Itoa(port))
+}
+
+func isListening(port int) bool {
+	conn, err := net.DialTimeout("tcp", localAddress(port), listenInterval)
+	if err != nil {
+		return false
+	}
+	conn.Close()
+
+	return true
+}
+
+func waitForListening(ctx context.Context, port int) error {
+	ctx, cancel := context.WithTimeout(ctx, listenTimeout)
+	defer cancel()
+
+	for {
+		if isListening(port) {
+			return nil
+		}
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("waiting for port %d: %w", port, ErrTunnelNotReady)
+		case <-time.After(listenInterval):
+		}
+	}
+}
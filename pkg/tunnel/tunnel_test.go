@@ -0,0 +1,129 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package tunnel
+
+import (
+	"context"
+	"net"
+	"testing"
+
+	. "github.com/onsi/gomega"
+)
+
+func TestParseJumpHost(t *testing.T) {
+	testCases := []struct {
+		name         string
+		jumpHost     string
+		expectedUser string
+		expectedHost string
+		expectedPort int
+		expectError  bool
+	}{
+		{
+			name:         "host only",
+			jumpHost:     "bastion.example.com",
+			expectedHost: "bastion.example.com",
+		},
+		{
+			name:         "user host and port",
+			jumpHost:     "ec2-user@bastion.example.com:2222",
+			expectedUser: "ec2-user",
+			expectedHost: "bastion.example.com",
+			expectedPort: 2222,
+		},
+		{
+			name:         "ipv6 with port",
+			jumpHost:     "admin@[fd00::1]:22",
+			expectedUser: "admin",
+			expectedHost: "fd00::1",
+			expectedPort: 22,
+		},
+		{
+			name:        "option injection",
+			jumpHost:    "-oProxyCommand=evil",
+			expectError: true,
+		},
+		{
+			name:        "invalid port",
+			jumpHost:    "bastion:99999",
+			expectError: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			g := NewWithT(t)
+
+			user, host, port, err := parseJumpHost(tc.jumpHost) //nolint:scopelint
+			if tc.expectError {                                 //nolint:scopelint
+				g.Expect(err).To(MatchError(ErrInvalidJumpHost))
+				return
+			}
+			g.Expect(err).NotTo(HaveOccurred())
+			g.Expect(user).To(Equal(tc.expectedUser)) //nolint:scopelint
+			g.Expect(host).To(Equal(tc.expectedHost)) //nolint:scopelint
+			g.Expect(port).To(Equal(tc.expectedPort)) //nolint:scopelint
+		})
+	}
+}
+
+func TestSSHArgs(t *testing.T) {
+	g := NewWithT(t)
+
+	cfg := &Config{JumpHost: "ec2-user@bastion:2222", IdentityFile: "/keys/bastion"}
+	args := sshArgs(cfg, "ec2-user", "bastion", 2222, 21080)
+	g.Expect(args).To(Equal([]string{
+		"-f", "-N",
+		"-D", "127.0.0.1:21080",
+		"-o", "ExitOnForwardFailure=yes",
+		"-o", "ServerAliveInterval=30",
+		"-o", "BatchMode=yes",
+		"-i", "/keys/bastion",
+		"-p", "2222",
+		"-l", "ec2-user",
+		"bastion",
+	}))
+}
+
+func TestDerivePort(t *testing.T) {
+	g := NewWithT(t)
+
+	port := DerivePort("bastion.example.com")
+	g.Expect(port).To(Equal(DerivePort("bastion.example.com")))
+	g.Expect(port).To(BeNumerically(">=", portRangeStart))
+	g.Expect(port).To(BeNumerically("<", portRangeStart+portRangeSize))
+}
+
+func TestOpenReusesTunnel(t *testing.T) {
+	g := NewWithT(t)
+
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	g.Expect(err).NotTo(HaveOccurred())
+	defer listener.Close()
+	port := listener.Addr().(*net.TCPAddr).Port
+
+	defer func(run func(context.Context, string, []string) error) { runSSH = run }(runSSH)
+	runSSH = func(ctx context.Context, path string, args []string) error {
+		t.Fatal("ssh shouldn't be run for an open tunnel")
+		return nil
+	}
+
+	tun, err := Open(context.Background(), &Config{JumpHost: "bastion", LocalPort: port})
+	g.Expect(err).NotTo(HaveOccurred())
+	g.Expect(tun.Reused).To(BeTrue())
+	g.Expect(tun.ProxyURL()).To(Equal("socks5://" + listener.Addr().String()))
+}
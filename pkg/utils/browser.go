@@ -0,0 +1,56 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"fmt"
+	"os/exec"
+	"runtime"
+)
+
+// OpenBrowser opens the URL in the default browser of the user. In WSL the URL is opened
+// in the Windows browser so that the user's existing sessions, e.g. with their SSO
+// provider, are used.
+func OpenBrowser(url string) error {
+	var cmd *exec.Cmd
+	switch {
+	case IsWSL():
+		cmd = wslBrowserCommand(url)
+	case runtime.GOOS == "darwin":
+		cmd = exec.Command("open", url)
+	case runtime.GOOS == "windows":
+		cmd = exec.Command("rundll32", "url.dll,FileProtocolHandler", url)
+	default:
+		cmd = exec.Command("xdg-open", url)
+	}
+
+	if err := cmd.Start(); err != nil {
+		return fmt.Errorf("opening browser: %w", err)
+	}
+
+	return nil
+}
+
+// wslBrowserCommand returns the command to open the URL in the Windows browser from WSL.
+// wslview is used if it's installed as it handles the quoting of the URL.
+func wslBrowserCommand(url string) *exec.Cmd {
+	if _, err := exec.LookPath("wslview"); err == nil {
+		return exec.Command("wslview", url)
+	}
+
+	return exec.Command("rundll32.exe", "url.dll,FileProtocolHandler", url)
+}
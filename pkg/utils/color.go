@@ -0,0 +1,33 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+var colorsDisabled bool
+
+// DisableColors stops the messages to the user being colored, e.g. when the output is
+// a CI job log
+func DisableColors() {
+	colorsDisabled = true
+}
+
+// Warning returns the message colored yellow for the terminal, unless colors are disabled
+func Warning(message string) string {
+	if colorsDisabled {
+		return message
+	}
+	return "\033[33m" + message + "\033[0m"
+}
@@ -23,10 +23,9 @@ import (
 
 func FormatCommand(cmd *cobra.Command) {
 
-	rootCmdName := "kconnect"
+	rootCmdName := CommandName()
 	// If running as a krew plugin, need to change usage output
-	if isKrewPlugin() {
-		rootCmdName = "kubectl connect"
+	if IsKubectlPlugin() {
 		// Only change this for root command
 		if cmd.Use == "kconnect" {
 			cmd.Use = "connect"
@@ -39,16 +38,26 @@ func FormatCommand(cmd *cobra.Command) {
 }
 
 func FormatUse(use string) string {
-	if isKrewPlugin() {
+	if IsKubectlPlugin() {
 		return "kubectl " + use
 	}
 	return use
 }
 
-func isKrewPlugin() bool {
+// IsKubectlPlugin returns true if kconnect is being run by kubectl as a plugin, i.e. the
+// executable is named kubectl-connect as it is when installed using krew
+func IsKubectlPlugin() bool {
 	return strings.HasPrefix(filepath.Base(os.Args[0]), "kubectl-")
 }
 
+// CommandName returns the command that runs kconnect, e.g. to tell the user what to run next
+func CommandName() string {
+	if IsKubectlPlugin() {
+		return "kubectl connect"
+	}
+	return "kconnect"
+}
+
 func formatMessage(message, rootCmdName string) string {
 	return strings.NewReplacer("{{.CommandPath}}", rootCmdName).Replace(message)
 }
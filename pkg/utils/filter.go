@@ -29,3 +29,19 @@ func SurveyFilter(filter string, value string, index int) bool {
 	}
 	return true
 }
+
+// FuzzyMatch returns true if all the characters of the pattern appear in the value in the
+// same order, ignoring case. For example "prd" matches "Production".
+func FuzzyMatch(pattern string, value string) bool {
+	remaining := []rune(strings.ToLower(pattern))
+	for _, r := range strings.ToLower(value) {
+		if len(remaining) == 0 {
+			break
+		}
+		if r == remaining[0] {
+			remaining = remaining[1:]
+		}
+	}
+
+	return len(remaining) == 0
+}
@@ -53,23 +53,3 @@ func CheckKubectlPrereq() error {
 	}
 	return nil
 }
-
-func CheckAWSIAMAuthPrereq() error {
-
-	cmd := exec.Command("aws-iam-authenticator")
-	_, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("error finding aws-iam-authenticator: %w", err)
-	}
-	return nil
-}
-
-func CheckKubeloginPrereq() error {
-
-	cmd := exec.Command("kubelogin")
-	_, err := cmd.Output()
-	if err != nil {
-		return fmt.Errorf("error finding kubelogin: %w", err)
-	}
-	return nil
-}
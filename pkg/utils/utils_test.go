@@ -1,6 +1,10 @@
 package utils
 
-import "testing"
+import (
+	"os"
+	"runtime"
+	"testing"
+)
 
 func Test_Filter(t *testing.T) {
 	testCases := []struct {
@@ -69,3 +73,100 @@ func Test_Filter(t *testing.T) {
 	}
 
 }
+
+func Test_FuzzyMatch(t *testing.T) {
+	testCases := []struct {
+		name         string
+		inputValue   string
+		inputPattern string
+		expect       bool
+	}{
+		{
+			name:         "Exact match",
+			inputValue:   "Production",
+			inputPattern: "Production",
+			expect:       true,
+		},
+		{
+			name:         "Case insensitive match",
+			inputValue:   "Production",
+			inputPattern: "production",
+			expect:       true,
+		},
+		{
+			name:         "Characters in order match",
+			inputValue:   "Production",
+			inputPattern: "prd",
+			expect:       true,
+		},
+		{
+			name:         "Characters out of order mismatch",
+			inputValue:   "Production",
+			inputPattern: "dpr",
+			expect:       false,
+		},
+		{
+			name:         "Longer pattern mismatch",
+			inputValue:   "Prod",
+			inputPattern: "Production",
+			expect:       false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			actual := FuzzyMatch(tc.inputPattern, tc.inputValue)
+			if actual != tc.expect {
+				t.Fatalf("expected %t but got %t", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func Test_CommandName(t *testing.T) {
+	testCases := []struct {
+		name   string
+		arg0   string
+		expect string
+	}{
+		{
+			name:   "Executable",
+			arg0:   "/usr/local/bin/kconnect",
+			expect: "kconnect",
+		},
+		{
+			name:   "Kubectl plugin",
+			arg0:   "/home/user/.krew/bin/kubectl-connect",
+			expect: "kubectl connect",
+		},
+		{
+			name:   "Kubectl plugin on windows",
+			arg0:   "kubectl-connect.exe",
+			expect: "kubectl connect",
+		},
+	}
+
+	args := os.Args
+	defer func() { os.Args = args }()
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			os.Args = []string{tc.arg0}
+			actual := CommandName()
+			if actual != tc.expect {
+				t.Fatalf("expected %s but got %s", tc.expect, actual)
+			}
+		})
+	}
+}
+
+func Test_IsWSL(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("WSL is only detected on linux")
+	}
+
+	t.Setenv("WSL_DISTRO_NAME", "Ubuntu")
+	if !IsWSL() {
+		t.Fatal("expected WSL to be detected from WSL_DISTRO_NAME")
+	}
+}
@@ -0,0 +1,67 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"runtime"
+	"strings"
+)
+
+const wslOSReleaseFile = "/proc/sys/kernel/osrelease"
+
+var ErrNoWindowsUserProfile = errors.New("windows user profile not found")
+
+// IsWSL returns true if kconnect is running in the Windows Subsystem for Linux
+func IsWSL() bool {
+	if runtime.GOOS != "linux" {
+		return false
+	}
+	if os.Getenv("WSL_DISTRO_NAME") != "" {
+		return true
+	}
+
+	release, err := os.ReadFile(wslOSReleaseFile)
+	if err != nil {
+		return false
+	}
+
+	return strings.Contains(strings.ToLower(string(release)), "microsoft")
+}
+
+// WindowsUserProfile returns the Windows user profile directory, i.e. %USERPROFILE%, as
+// a path that can be used in WSL, e.g. /mnt/c/Users/bob
+func WindowsUserProfile() (string, error) {
+	out, err := exec.Command("cmd.exe", "/c", "echo %USERPROFILE%").Output()
+	if err != nil {
+		return "", fmt.Errorf("getting windows user profile: %w", err)
+	}
+	profile := strings.TrimSpace(string(out))
+	if profile == "" || profile == "%USERPROFILE%" {
+		return "", ErrNoWindowsUserProfile
+	}
+
+	out, err = exec.Command("wslpath", "-u", profile).Output()
+	if err != nil {
+		return "", fmt.Errorf("converting windows path %s: %w", profile, err)
+	}
+
+	return strings.TrimSpace(string(out)), nil
+}
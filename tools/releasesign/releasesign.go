@@ -0,0 +1,60 @@
+/*
+Copyright 2021 The kconnect Authors.
+
+Licensed under the Apache License, Version 2.0 (the "License");
+you may not use this file except in compliance with the License.
+You may obtain a copy of the License at
+
+    http://www.apache.org/licenses/LICENSE-2.0
+
+Unless required by applicable law or agreed to in writing, software
+distributed under the License is distributed on an "AS IS" BASIS,
+WITHOUT WARRANTIES OR CONDITIONS OF ANY KIND, either express or implied.
+See the License for the specific language governing permissions and
+limitations under the License.
+*/
+
+// releasesign signs a release artifact with the ed25519 release signing key. The
+// base64 encoded private key is read from the KCONNECT_SIGNING_KEY environment
+// variable and the base64 encoded signature is written to the signature file.
+package main
+
+import (
+	"crypto/ed25519"
+	"encoding/base64"
+	"fmt"
+	"io/ioutil"
+	"log"
+	"os"
+)
+
+const signingKeyEnv = "KCONNECT_SIGNING_KEY"
+
+func main() {
+	if len(os.Args) != 3 {
+		log.Fatal("usage: releasesign <artifact> <signature>")
+	}
+
+	key, err := base64.StdEncoding.DecodeString(os.Getenv(signingKeyEnv))
+	if err != nil {
+		log.Fatalf("decoding %s: %s", signingKeyEnv, err)
+	}
+	switch len(key) {
+	case ed25519.SeedSize:
+		key = ed25519.NewKeyFromSeed(key)
+	case ed25519.PrivateKeySize:
+	default:
+		log.Fatalf("%s is not an ed25519 private key", signingKeyEnv)
+	}
+
+	data, err := ioutil.ReadFile(os.Args[1])
+	if err != nil {
+		log.Fatalf("reading artifact: %s", err)
+	}
+	signature := ed25519.Sign(ed25519.PrivateKey(key), data)
+
+	if err := ioutil.WriteFile(os.Args[2], []byte(base64.StdEncoding.EncodeToString(signature)+"\n"), 0644); err != nil { //nolint: gosec
+		log.Fatalf("writing signature: %s", err)
+	}
+	fmt.Printf("Signed %s\n", os.Args[1])
+}